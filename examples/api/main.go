@@ -43,7 +43,7 @@ func UpdatePet() {}
 //
 // !POST /pet -> addPet "Add a new pet to the store" #pet
 // !secure petstore_auth api_key
-// !body Pet "Create a new pet in the store" required
+// !body PetCreate "Create a new pet in the store" required
 // !ok 200 Pet "Successful operation"
 // !error 405 ApiResponse "Invalid input"
 func AddPet() {}
@@ -218,8 +218,9 @@ func DeleteUser() {}
 
 // Pet represents a pet in the store.
 // !model "A pet for sale in the pet store"
+// !view PetCreate omit=id,status "A pet to add to the store"
 type Pet struct {
-	// !field id:int64 "Unique identifier for the pet" example=10
+	// !field id:int64 "Unique identifier for the pet" readonly example=10
 	ID int64 `json:"id,omitempty"`
 
 	// !field name:string "Name of the pet" required example="doggie"
@@ -298,7 +299,7 @@ type User struct {
 	// !field email:string "Email address" example="john@email.com"
 	Email string `json:"email,omitempty"`
 
-	// !field password:string "Password" example="12345"
+	// !field password:string "Password" writeonly example="12345"
 	Password string `json:"password,omitempty"`
 
 	// !field phone:string "Phone number" example="12345"
@@ -352,16 +353,7 @@ type Customer struct {
 
 // InventoryResponse represents inventory counts by status.
 // !model "Inventory counts by pet status"
-type InventoryResponse struct {
-	// !field available:integer "Number of available pets" example=50
-	Available int `json:"available,omitempty"`
-
-	// !field pending:integer "Number of pending pets" example=10
-	Pending int `json:"pending,omitempty"`
-
-	// !field sold:integer "Number of sold pets" example=100
-	Sold int `json:"sold,omitempty"`
-}
+type InventoryResponse map[string]int32
 
 // LoginResponse represents a successful login response.
 // !model "Login response with session token"
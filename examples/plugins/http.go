@@ -10,56 +10,23 @@ import (
 
 func main() {
 	// Create your OpenAPI spec
-	spec := &openapi.Document{
-		OpenAPI: "3.0.3",
-		Info: openapi.Info{
-			Title:       "Example API",
-			Version:     "1.0.0",
-			Description: "A sample API to demonstrate the HTTP middleware plugin",
-		},
-		Servers: []openapi.Server{
-			{URL: "http://localhost:8080", Description: "Local server"},
-		},
-		Paths: map[string]*openapi.PathItem{
-			"/users": {
-				Get: &openapi.Operation{
-					Summary:     "List all users",
-					OperationID: "listUsers",
-					Tags:        []string{"users"},
-					Parameters: []*openapi.Parameter{
-						{
-							Name:     "limit",
-							In:       openapi.ParameterInQuery,
-							Required: false,
-							Schema:   openapi.IntegerSchema(),
-						},
-					},
-					Responses: map[string]*openapi.Response{
-						"200": {Description: "List of users"},
-					},
-				},
-			},
-			"/users/{id}": {
-				Get: &openapi.Operation{
-					Summary:     "Get user by ID",
-					OperationID: "getUser",
-					Tags:        []string{"users"},
-					Parameters: []*openapi.Parameter{
-						{
-							Name:     "id",
-							In:       openapi.ParameterInPath,
-							Required: true,
-							Schema:   openapi.IntegerSchema(),
-						},
-					},
-					Responses: map[string]*openapi.Response{
-						"200": {Description: "User found"},
-						"404": {Description: "User not found"},
-					},
-				},
-			},
-		},
-	}
+	spec := openapi.NewBuilder().
+		Title("Example API").
+		Version("1.0.0").
+		Description("A sample API to demonstrate the HTTP middleware plugin").
+		Server("http://localhost:8080", "Local server").
+		AddOperation("GET", "/users", openapi.NewOperation("listUsers").
+			Summary("List all users").
+			Tags("users").
+			QueryParam("limit", openapi.IntegerSchema(), "").
+			Response("200", "List of users")).
+		AddOperation("GET", "/users/{id}", openapi.NewOperation("getUser").
+			Summary("Get user by ID").
+			Tags("users").
+			PathParam("id", openapi.IntegerSchema(), "").
+			Response("200", "User found").
+			Response("404", "User not found")).
+		MustBuild()
 
 	// Create HTTP middleware plugin using fluent builder
 	handler := yahttp.WithSpec(spec).
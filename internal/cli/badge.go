@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/audit"
+	"github.com/fathurrohman26/yaswag/pkg/badge"
+	"github.com/fathurrohman26/yaswag/pkg/validator"
+)
+
+func (c *CLI) runBadge(args []string) error {
+	fs := flag.NewFlagSet("badge", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path or - for stdin")
+	metric := fs.String("metric", "valid", "Metric to report: valid, audit, or coverage (default: valid)")
+	format := fs.String("format", "svg", "Output format: svg or json (default: svg)")
+	label := fs.String("label", "", "Badge label (default depends on metric)")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	showHelp := fs.Bool("help", false, "Show help for badge command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.BadgeHelp())
+		return nil
+	}
+
+	result, err := readFromStdinOrFile(*input, true)
+	if err != nil {
+		return err
+	}
+
+	b, err := metricBadge(*metric, result.data)
+	if err != nil {
+		return err
+	}
+	if *label != "" {
+		b.Label = *label
+	}
+
+	var data []byte
+	switch strings.ToLower(*format) {
+	case "svg":
+		data, err = badge.SVG(b)
+	case "json":
+		data, err = badge.Endpoint(b)
+	default:
+		return fmt.Errorf("unsupported badge format: %s (supported: svg, json)", *format)
+	}
+	if err != nil {
+		return err
+	}
+	return c.writeOutput(*outputPath, data, "Badge")
+}
+
+// metricBadge evaluates the spec for the given metric and returns its badge.
+func metricBadge(metric string, data []byte) (badge.Badge, error) {
+	switch strings.ToLower(metric) {
+	case "valid":
+		result, err := validator.New().Validate(data)
+		if err != nil {
+			return badge.Badge{}, err
+		}
+		return badge.Validity(result.Valid), nil
+	case "audit", "coverage":
+		result, err := audit.New().AuditData(data)
+		if err != nil {
+			return badge.Badge{}, err
+		}
+		if strings.EqualFold(metric, "coverage") {
+			return badge.Percent("security coverage", result.CoveragePercent()), nil
+		}
+		return badge.Score("audit", result.Score()), nil
+	default:
+		return badge.Badge{}, fmt.Errorf("unsupported badge metric: %s (supported: valid, audit, coverage)", metric)
+	}
+}
+
+func (c *CLI) BadgeHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Render a status badge for an OpenAPI specification.\n\n")
+	help.WriteString("Writes an SVG image or shields.io endpoint JSON that CI can publish as an\n")
+	help.WriteString("artifact and READMEs can embed.\n\n")
+	help.WriteString("Metrics:\n")
+	help.WriteString("  valid       Whether the spec passes validation\n")
+	help.WriteString("  audit       Security audit score from 0 to 100 (errors -20, warnings -5, info -1)\n")
+	help.WriteString("  coverage    Percentage of endpoints with a security requirement\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag badge [options]\n")
+	help.WriteString("  <command> | yaswag badge [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>    Input file path or - for stdin\n")
+	help.WriteString("  --metric <name>   Metric to report: valid, audit, or coverage (default: valid)\n")
+	help.WriteString("  --format <type>   Output format: svg or json (default: svg)\n")
+	help.WriteString("  --label <text>    Badge label (default depends on metric)\n")
+	help.WriteString("  --output <path>   Output file path (empty for stdout)\n")
+	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag badge --input ./swagger.yaml --output ./badges/openapi.svg\n")
+	help.WriteString("  yaswag badge --input ./swagger.yaml --metric audit --format json --output ./badges/audit.json\n")
+	help.WriteString("  yaswag generate --source ./api | yaswag badge --metric coverage > coverage.svg\n")
+	return help.String()
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/uiassets"
+)
+
+// brandingFlags defines the documentation branding flags on fs. The returned
+// function reports the branding they set after parsing, or nil if none is set.
+func brandingFlags(fs *flag.FlagSet) func() *uiassets.Branding {
+	var b uiassets.Branding
+	fs.StringVar(&b.LogoURL, "logo", "", "Logo URL shown in the navigation bar")
+	fs.StringVar(&b.FaviconURL, "favicon", "", "Favicon URL")
+	fs.StringVar(&b.PrimaryColor, "primary-color", "", "Primary CSS color, e.g. #32329f")
+	fs.StringVar(&b.FooterText, "footer", "", "Footer text")
+	fs.StringVar(&b.Language, "lang", "", "Page language, e.g. id (default: en)")
+	fs.Func("nav-link", "Navigation bar link as label=url (repeatable)", func(s string) error {
+		link, err := uiassets.ParseNavLink(s)
+		if err != nil {
+			return err
+		}
+		b.NavLinks = append(b.NavLinks, link)
+		return nil
+	})
+	return func() *uiassets.Branding {
+		if b.LogoURL == "" && b.FaviconURL == "" && b.PrimaryColor == "" && b.FooterText == "" &&
+			b.Language == "" && len(b.NavLinks) == 0 {
+			return nil
+		}
+		return &b
+	}
+}
+
+// brandingHelp returns the help lines of the branding flags.
+func brandingHelp() string {
+	help := strings.Builder{}
+	help.WriteString("  --logo <url>            Logo URL shown in the navigation bar\n")
+	help.WriteString("  --favicon <url>         Favicon URL\n")
+	help.WriteString("  --primary-color <css>   Primary color, e.g. #32329f\n")
+	help.WriteString("  --footer <text>         Footer text\n")
+	help.WriteString("  --nav-link <label=url>  Navigation bar link (repeatable)\n")
+	help.WriteString("  --lang <code>           Page language, e.g. id (default: en)\n")
+	return help.String()
+}
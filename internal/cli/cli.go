@@ -13,6 +13,7 @@ import (
 	"github.com/fathurrohman26/yaswag/pkg/openapi"
 	"github.com/fathurrohman26/yaswag/pkg/output"
 	"github.com/fathurrohman26/yaswag/pkg/swaggerui"
+	"github.com/fathurrohman26/yaswag/pkg/uiassets"
 	"github.com/fathurrohman26/yaswag/pkg/validator"
 )
 
@@ -60,13 +61,30 @@ func (c *CLI) Run() error {
 
 	// Command dispatcher
 	commands := map[string]func([]string) error{
-		"generate": c.runGenerate,
-		"validate": c.runValidate,
-		"format":   c.runFormat,
-		"serve":    c.runServe,
-		"editor":   c.runEditor,
-		"mcp":      c.runMCP,
-		"audit":    c.runAudit,
+		"init":         c.runInit,
+		"import":       c.runImport,
+		"migrate-swag": c.runMigrateSwag,
+		"generate":     c.runGenerate,
+		"validate":     c.runValidate,
+		"format":       c.runFormat,
+		"serve":        c.runServe,
+		"editor":       c.runEditor,
+		"mcp":          c.runMCP,
+		"audit":        c.runAudit,
+		"convert":      c.runConvert,
+		"filter":       c.runFilter,
+		"prune":        c.runPrune,
+		"inline":       c.runInline,
+		"overlay":      c.runOverlay,
+		"lint":         c.runLint,
+		"docs":         c.runDocs,
+		"badge":        c.runBadge,
+		"owners":       c.runOwners,
+		"deprecations": c.runDeprecations,
+		"stats":        c.runStats,
+		"testmap":      c.runTestMap,
+		"semver":       c.runSemver,
+		"verify-impl":  c.runVerifyImpl,
 	}
 
 	if handler, ok := commands[cmd]; ok {
@@ -89,12 +107,47 @@ func (c *CLI) handleBuiltinCommand(cmd string) bool {
 	return false
 }
 
+// generateSubcommands are the generate commands producing other artifacts
+// than the OpenAPI spec.
+var generateSubcommands = map[string]func(c *CLI, args []string) error{
+	"proto":    (*CLI).runGenerateProto,
+	"types":    (*CLI).runGenerateTypes,
+	"asyncapi": (*CLI).runGenerateAsyncAPI,
+}
+
 func (c *CLI) runGenerate(args []string) error {
+	if len(args) > 0 && generateSubcommands[args[0]] != nil {
+		return generateSubcommands[args[0]](c, args[1:])
+	}
+	return c.runGenerateSpec(args)
+}
+
+// runGenerateSpec generates the OpenAPI spec from the annotations.
+func (c *CLI) runGenerateSpec(args []string) error {
 	fs := flag.NewFlagSet("generate", flag.ExitOnError)
 	source := fs.String("source", ".", "Source directory to scan for annotations")
 	format := fs.String("format", "yaml", "Output format (json or yaml)")
 	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
 	pretty := fs.Int("pretty", 2, "Indentation spaces for pretty printing")
+	discover := fs.Bool("discover", false, "Generate one spec per API root (directory with an !api annotation)")
+	layout := fs.String("layout", defaultDiscoverLayout, "Spec path layout in the output directory with --discover")
+	strict := fs.Bool("strict", false, "Fail on annotation lines that cannot be parsed instead of warning")
+	excludeTags := fs.String("exclude-tags", "", "Comma-separated build tags of internal-only files to leave out")
+	includeInternal := fs.Bool("include-internal", false, "Keep internal operations and models, marked x-internal")
+	splitByTag := fs.Bool("split-by-tag", false, "Write the operations of each tag to their own file next to --output")
+	target := fs.String("openapi", "", "OpenAPI version to convert the generated spec to (3.0 or 3.1)")
+	env := fs.String("env", "", "Environment whose servers from the config file replace the !server annotations")
+	var servers serverFlag
+	fs.Var(&servers, "server", "Server URL, optionally followed by a description, replacing the !server annotations (repeatable)")
+	serversFile := fs.String("servers-file", "", "YAML or JSON file of servers replacing the !server annotations")
+	appendServers := fs.Bool("append-servers", false, "Append the --env, --servers-file, and --server servers to the annotated ones")
+	stamp := fs.Bool("stamp", false, "Stamp the git commit and build date into info as x-build")
+	var build buildStamp
+	fs.StringVar(&build.version, "stamp-version", "", "Version to stamp, replacing info.version (implies --stamp)")
+	fs.StringVar(&build.commit, "stamp-commit", "", "Commit to stamp instead of the git HEAD (implies --stamp)")
+	fs.StringVar(&build.date, "stamp-date", "", "Build date to stamp instead of the current time (implies --stamp)")
+	noCache := fs.Bool("no-cache", false, "Parse every file instead of skipping unchanged files without annotations")
+	locale := fs.String("locale", "", "Comma-separated locales of the localized annotations to generate a spec for, one spec each")
 	showHelp := fs.Bool("help", false, "Show help for generate command")
 
 	if err := fs.Parse(args); err != nil {
@@ -106,24 +159,117 @@ func (c *CLI) runGenerate(args []string) error {
 		return nil
 	}
 
-	openAPIDoc, err := c.parseAndGenerate(*source)
+	config, err := loadProjectConfig()
 	if err != nil {
 		return err
 	}
+	if err := applyConfig(fs, config, config.generateFlags()); err != nil {
+		return err
+	}
+
+	generate := generateOptions{
+		strict:        *strict,
+		target:        *target,
+		appendServers: *appendServers,
+		parser:        parser.Options{ExcludeTags: splitList(*excludeTags), IncludeInternal: *includeInternal},
+	}
+	if generate.servers, err = injectedServers(config, *env, *serversFile, servers); err != nil {
+		return err
+	}
+	if generate.stamp, err = build.requested(*stamp, *source); err != nil {
+		return err
+	}
+
+	if cache := c.parseCache(*source, *noCache); cache != nil {
+		generate.parser.Cache = cache
+		defer saveParseCache(cache)
+	}
+
+	out := specOutput{outputPath: *outputPath, format: *format, pretty: *pretty, splitByTag: *splitByTag}
+	if *discover {
+		return c.discoverSpecs(*source, splitList(*locale), *layout, generate, out)
+	}
+	return c.generateSpecs(*source, splitList(*locale), generate, out)
+}
 
-	data, err := c.formatOutput(openAPIDoc, *format, *pretty)
+// specOutput is where a generated spec is written.
+type specOutput struct {
+	outputPath string
+	format     string
+	pretty     int
+	splitByTag bool
+}
+
+// generateSpecs generates the spec of source, or one for each of several
+// locales, and writes it to out.
+func (c *CLI) generateSpecs(source string, locales []string, generate generateOptions, out specOutput) error {
+	if len(locales) > 1 {
+		return c.generateLocales(source, locales, generate, out)
+	}
+	if len(locales) == 1 {
+		generate.parser.Locale = locales[0]
+	}
+	doc, err := c.parseAndGenerate(source, generate)
 	if err != nil {
 		return err
 	}
+	return c.writeSpec(doc, out, "OpenAPI specification")
+}
 
-	return c.writeOutput(*outputPath, data, "OpenAPI specification")
+// discoverSpecs generates one spec per API root under source, with --discover.
+func (c *CLI) discoverSpecs(source string, locales []string, layout string, generate generateOptions, out specOutput) error {
+	if out.splitByTag {
+		return fmt.Errorf("--split-by-tag cannot be used with --discover")
+	}
+	if len(locales) > 1 {
+		return fmt.Errorf("--discover generates one locale at a time")
+	}
+	if len(locales) == 1 {
+		generate.parser.Locale = locales[0]
+	}
+	return c.generateServices(source, discoverOptions{
+		outputDir: out.outputPath,
+		layout:    layout,
+		format:    out.format,
+		pretty:    out.pretty,
+		generate:  generate,
+	})
+}
+
+// writeSpec writes doc to out, split into a file per tag if requested.
+func (c *CLI) writeSpec(doc *openapi.Document, out specOutput, what string) error {
+	if out.splitByTag {
+		return c.writeSplitByTag(doc, out.outputPath, out.format, out.pretty)
+	}
+	data, err := c.formatOutput(doc, out.format, out.pretty)
+	if err != nil {
+		return err
+	}
+	return c.writeOutput(out.outputPath, data, what)
 }
 
-func (c *CLI) parseAndGenerate(source string) (*openapi.Document, error) {
-	p := parser.New()
-	if err := p.ParseDir(source); err != nil {
+// generateOptions configures how a spec is generated from annotations.
+type generateOptions struct {
+	exclude       []string         // directory trees to skip
+	strict        bool             // fail on annotation lines that cannot be parsed
+	target        string           // OpenAPI version to convert to, if set
+	servers       []openapi.Server // servers replacing the annotated ones, if set
+	appendServers bool             // append servers to the annotated ones instead
+	stamp         *buildStamp      // build metadata to stamp into info, if set
+	diagnosed     bool             // the diagnostics were reported by an earlier run
+	parser        parser.Options
+}
+
+func (c *CLI) parseAndGenerate(source string, opts generateOptions) (*openapi.Document, error) {
+	p := parser.NewWithOptions(opts.parser)
+	if err := p.ParseDirExcept(source, opts.exclude); err != nil {
 		return nil, fmt.Errorf("failed to parse source: %w", err)
 	}
+	if !opts.diagnosed {
+		if err := reportDiagnostics(p.Diagnostics(), opts.strict); err != nil {
+			return nil, err
+		}
+	}
 
 	spec := p.GetSpec()
 	if spec.Info == nil || spec.Info.Title == "" {
@@ -134,9 +280,60 @@ func (c *CLI) parseAndGenerate(source string) (*openapi.Document, error) {
 	if doc == nil {
 		return nil, fmt.Errorf("failed to generate OpenAPI document")
 	}
+	if len(opts.servers) > 0 {
+		doc.Servers = mergeServers(doc.Servers, opts.servers, opts.appendServers)
+	}
+	if opts.stamp != nil {
+		opts.stamp.apply(&doc.Info)
+	}
+	if opts.target != "" {
+		return convertDocument(doc, opts.target)
+	}
 	return doc, nil
 }
 
+// parseCache opens the parse cache of the source directory in the user's
+// cache directory, or returns nil if it is disabled or cannot be opened,
+// warning about the latter.
+func (c *CLI) parseCache(source string, disabled bool) *parser.Cache {
+	if disabled {
+		return nil
+	}
+	path, err := parser.DefaultCachePath(source)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: parse cache disabled: %v\n", err)
+		return nil
+	}
+	return parser.OpenCache(path)
+}
+
+// saveParseCache saves the parse cache, warning if it cannot: the next run
+// then parses every file again.
+func saveParseCache(cache *parser.Cache) {
+	if err := cache.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save parse cache: %v\n", err)
+	}
+}
+
+// reportDiagnostics prints the annotation lines that could not be parsed as
+// warnings, or fails with them in strict mode.
+func reportDiagnostics(diagnostics []parser.Diagnostic, strict bool) error {
+	if len(diagnostics) == 0 {
+		return nil
+	}
+	if !strict {
+		for _, d := range diagnostics {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", d)
+		}
+		return nil
+	}
+	lines := make([]string, len(diagnostics))
+	for i, d := range diagnostics {
+		lines[i] = "  " + d.String()
+	}
+	return fmt.Errorf("%d annotation(s) could not be parsed:\n%s", len(diagnostics), strings.Join(lines, "\n"))
+}
+
 func (c *CLI) formatOutput(doc *openapi.Document, format string, pretty int) ([]byte, error) {
 	outputFormat, err := output.ParseFormat(format)
 	if err != nil {
@@ -326,6 +523,14 @@ func (c *CLI) runServe(args []string) error {
 	fs := flag.NewFlagSet("serve", flag.ExitOnError)
 	input := fs.String("input", "", "Input file path, URL, or - for stdin")
 	port := fs.Int("port", 8080, "Port to serve on")
+	ui := fs.String("ui", "swagger", "Documentation UI: swagger, redoc, scalar, or rapidoc")
+	offline := fs.Bool("offline", false, "Serve the documentation UI from embedded assets instead of a CDN")
+	branding := brandingFlags(fs)
+	var specs namedSpecs
+	fs.Var(&specs, "spec", "Named spec as name=path, selectable in Swagger UI (repeatable)")
+	source := fs.String("source", "", "Source directory to generate the spec from, instead of --input")
+	watch := fs.Bool("watch", false, "Reload the spec in the browser when the --input file or --source changes")
+	listen := listenFlags(fs)
 	showHelp := fs.Bool("help", false, "Show help for serve command")
 
 	if err := fs.Parse(args); err != nil {
@@ -336,12 +541,93 @@ func (c *CLI) runServe(args []string) error {
 		fmt.Println(c.ServeHelp())
 		return nil
 	}
+	if err := checkServeSources(*input, *source, specs, *watch); err != nil {
+		return err
+	}
+
+	docsUI, err := uiassets.ParseUI(*ui)
+	if err != nil {
+		return err
+	}
 
 	server := swaggerui.NewServer(*port)
-	if err := c.setServerSpec(server, *input, true); err != nil {
+	if err := listen(server); err != nil {
+		return err
+	}
+	server.SetDocsUI(docsUI)
+	server.SetOfflineAssets(*offline)
+	if b := branding(); b != nil {
+		if err := b.Validate(); err != nil {
+			return err
+		}
+		server.SetBranding(b)
+	}
+	if err := c.loadServeSpecs(server, *input, *source, specs); err != nil {
 		return err
 	}
-	return server.Serve()
+
+	ctx, stop := signalContext()
+	defer stop()
+	if *watch {
+		c.watchSpec(ctx, server, *source, *input)
+	}
+	return server.ServeContext(ctx)
+}
+
+// namedSpecs collects repeated --spec values, each a name=path pair.
+type namedSpecs [][2]string
+
+func (s *namedSpecs) String() string {
+	names := make([]string, len(*s))
+	for i, spec := range *s {
+		names[i] = spec[0]
+	}
+	return strings.Join(names, ",")
+}
+
+func (s *namedSpecs) Set(value string) error {
+	name, path, ok := strings.Cut(value, "=")
+	if !ok || name == "" || path == "" {
+		return fmt.Errorf("invalid spec %q: use name=path", value)
+	}
+	*s = append(*s, [2]string{name, path})
+	return nil
+}
+
+// checkServeSources reports the serve flags that cannot be combined: only
+// one of --input, --spec, and --source sets the spec, and --watch needs a
+// file or source directory to watch.
+func checkServeSources(input, source string, specs namedSpecs, watch bool) error {
+	sources := 0
+	for _, set := range []bool{input != "", len(specs) > 0, source != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return fmt.Errorf("only one of --input, --spec, and --source can be used")
+	}
+	if watch && source == "" && (input == "" || input == "-" || isURL(input)) {
+		return fmt.Errorf("--watch needs an --input file or --source")
+	}
+	return nil
+}
+
+// loadServeSpecs sets the specs of server: the named specs, or the spec
+// generated from source, or else the spec read from input.
+func (c *CLI) loadServeSpecs(server *swaggerui.Server, input, source string, specs namedSpecs) error {
+	for _, spec := range specs {
+		if err := server.AddSpecFromFile(spec[0], spec[1]); err != nil {
+			return err
+		}
+	}
+	switch {
+	case source != "":
+		return c.regenerateSpec(server, source)
+	case len(specs) == 0:
+		return c.setServerSpec(server, input, true)
+	}
+	return nil
 }
 
 type specSetter interface {
@@ -377,6 +663,7 @@ func (c *CLI) runEditor(args []string) error {
 	fs := flag.NewFlagSet("editor", flag.ExitOnError)
 	input := fs.String("input", "", "Input file path, URL, or - for stdin (optional)")
 	port := fs.Int("port", 8080, "Port to serve on")
+	listen := listenFlags(fs)
 	showHelp := fs.Bool("help", false, "Show help for editor command")
 
 	if err := fs.Parse(args); err != nil {
@@ -389,17 +676,21 @@ func (c *CLI) runEditor(args []string) error {
 	}
 
 	server := swaggerui.NewEditorServer(*port)
+	if err := listen(server); err != nil {
+		return err
+	}
 	// Editor doesn't require input - can launch in create mode
 	if err := c.setServerSpec(server, *input, false); err != nil {
 		return err
 	}
-	return server.Serve()
+	return serveUntilSignal(server)
 }
 
 func (c *CLI) runMCP(args []string) error {
 	fs := flag.NewFlagSet("mcp", flag.ExitOnError)
 	showHelp := fs.Bool("help", false, "Show help for mcp command")
 	skipValidation := fs.Bool("skip-validation", false, "Skip spec validation before starting")
+	seed := fs.Uint64("seed", 0, "Seed of the generate_example data, for the same examples on every run (0 for a random seed)")
 
 	if err := fs.Parse(args); err != nil {
 		return err
@@ -430,6 +721,9 @@ func (c *CLI) runMCP(args []string) error {
 	}
 
 	server := mcp.NewServer(specPaths)
+	if *seed != 0 {
+		server.ExampleSeed = *seed
+	}
 	return server.Run()
 }
 
@@ -456,7 +750,12 @@ func (c *CLI) printMCPValidationResult(result *validator.ValidationResult) {
 func (c *CLI) runAudit(args []string) error {
 	fs := flag.NewFlagSet("audit", flag.ExitOnError)
 	input := fs.String("input", "", "Input file path, URL, or - for stdin")
-	format := fs.String("format", "text", "Output format: text or json (default: text)")
+	format := fs.String("format", "text", "Output format: text, json, sarif, or pr-comment (default: text)")
+	failOn := fs.String("fail-on", "error", "Minimum severity that fails the audit: error, warning, info, or none")
+	maxFindings := fs.Int("max-findings", 10, "Findings listed in pr-comment output")
+	artifactURL := fs.String("artifact-url", "", "Link to the full report in pr-comment output")
+	severities := fs.String("severity", "", "Comma-separated rule severity overrides, e.g. SERVER_HTTP=info")
+	ignore := fs.String("ignore", "", "Comma-separated rule IDs to skip")
 	showHelp := fs.Bool("help", false, "Show help for audit command")
 
 	if err := fs.Parse(args); err != nil {
@@ -468,13 +767,75 @@ func (c *CLI) runAudit(args []string) error {
 		return nil
 	}
 
-	auditor := audit.New()
+	config, err := loadProjectConfig()
+	if err != nil {
+		return err
+	}
+	if err := applyConfig(fs, config, config.auditFlags()); err != nil {
+		return err
+	}
+
+	threshold, err := parseFailOn(*failOn)
+	if err != nil {
+		return err
+	}
+
+	auditSeverities, err := parseAuditSeverities(*severities)
+	if err != nil {
+		return err
+	}
+	auditor := audit.NewWithConfig(&audit.Config{Severities: auditSeverities, IgnoreRules: splitList(*ignore)})
 	result, err := c.auditInput(auditor, *input)
 	if err != nil {
 		return err
 	}
 
-	return c.outputAuditResult(result, *format)
+	prOpts := audit.PRCommentOptions{MaxFindings: *maxFindings, ArtifactURL: *artifactURL}
+	if err := c.outputAuditResult(result, *format, prOpts); err != nil {
+		return err
+	}
+
+	// Exit with non-zero if there are findings at or above the threshold
+	if auditFails(result, threshold) {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// parseAuditSeverities parses the comma-separated RULE=level entries of the
+// --severity flag.
+func parseAuditSeverities(value string) (map[string]audit.Severity, error) {
+	severities := make(map[string]audit.Severity)
+	for _, entry := range splitList(value) {
+		ruleID, level, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --severity entry %q: expected RULE=level", entry)
+		}
+		severity, err := audit.ParseSeverity(level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --severity entry %q: %w", entry, err)
+		}
+		severities[strings.TrimSpace(ruleID)] = severity
+	}
+	return severities, nil
+}
+
+// auditFails reports whether the result has findings at or above the
+// threshold, if there is one.
+func auditFails(result *audit.AuditResult, threshold audit.Severity) bool {
+	return threshold != "" && result.HasFindingsAtOrAbove(threshold)
+}
+
+// parseFailOn parses the --fail-on flag; "none" disables failing and returns an empty severity.
+func parseFailOn(value string) (audit.Severity, error) {
+	if strings.EqualFold(value, "none") {
+		return "", nil
+	}
+	severity, err := audit.ParseSeverity(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid --fail-on value: %w", err)
+	}
+	return severity, nil
 }
 
 func (c *CLI) auditInput(auditor *audit.Auditor, input string) (*audit.AuditResult, error) {
@@ -493,7 +854,7 @@ func (c *CLI) auditInput(auditor *audit.Auditor, input string) (*audit.AuditResu
 	return auditor.AuditFile(input)
 }
 
-func (c *CLI) outputAuditResult(result *audit.AuditResult, format string) error {
+func (c *CLI) outputAuditResult(result *audit.AuditResult, format string, prOpts audit.PRCommentOptions) error {
 	switch strings.ToLower(format) {
 	case "json":
 		data, err := audit.FormatJSON(result)
@@ -501,17 +862,83 @@ func (c *CLI) outputAuditResult(result *audit.AuditResult, format string) error
 			return fmt.Errorf("failed to format JSON: %w", err)
 		}
 		fmt.Println(string(data))
+	case "sarif":
+		data, err := audit.FormatSARIF(result)
+		if err != nil {
+			return fmt.Errorf("failed to format SARIF: %w", err)
+		}
+		fmt.Println(string(data))
+	case "pr-comment":
+		fmt.Print(audit.FormatPRComment(result, prOpts))
 	default:
 		fmt.Print(audit.FormatText(result))
 	}
+	return nil
+}
+
+func (c *CLI) runConvert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path or - for stdin")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	target := fs.String("target", "3.1", "Target OpenAPI version (3.0 or 3.1)")
+	format := fs.String("format", "", "Output format (json or yaml, auto-detected from extension if not specified)")
+	pretty := fs.Int("pretty", 2, "Indentation spaces for pretty printing")
+	showHelp := fs.Bool("help", false, "Show help for convert command")
 
-	// Exit with non-zero if there are error-level findings
-	for _, f := range result.Findings {
-		if f.Severity == audit.SeverityError {
-			os.Exit(1)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.ConvertHelp())
+		return nil
+	}
+
+	result, err := readFromStdinOrFile(*input, true)
+	if err != nil {
+		return err
+	}
+
+	doc, err := parseDocument(result.data)
+	if err != nil {
+		return err
+	}
+
+	converted, err := convertDocument(doc, *target)
+	if err != nil {
+		return err
+	}
+
+	outputFormat := c.determineOutputFormat(*format, *outputPath, *input, result.fromStdin)
+	data, err := c.formatOutput(converted, string(outputFormat), *pretty)
+	if err != nil {
+		return err
+	}
+
+	return c.writeOutput(*outputPath, data, "Converted specification")
+}
+
+// parseDocument parses OpenAPI specification bytes (JSON or YAML) into a Document.
+func parseDocument(data []byte) (*openapi.Document, error) {
+	var doc openapi.Document
+	if err := yamlUnmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+	return &doc, nil
+}
+
+func convertDocument(doc *openapi.Document, target string) (*openapi.Document, error) {
+	switch strings.TrimPrefix(target, "v") {
+	case "3.1", openapi.Version31:
+		return openapi.ConvertTo31(doc)
+	case "3.0", openapi.Version30:
+		if len(doc.Webhooks) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: %d webhook(s) dropped (not supported in OpenAPI 3.0)\n", len(doc.Webhooks))
 		}
+		return openapi.ConvertTo30(doc)
+	default:
+		return nil, fmt.Errorf("unsupported target version: %s (supported: 3.0, 3.1)", target)
 	}
-	return nil
 }
 
 func (c *CLI) Version() string {
@@ -525,15 +952,32 @@ func (c *CLI) Help() string {
 	help.WriteString("Usage:\n")
 	help.WriteString("  yaswag [command] [options]\n\n")
 	help.WriteString("Commands:\n")
-	help.WriteString("  generate    Generate OpenAPI specification from Go annotations\n")
-	help.WriteString("  validate    Validate an existing OpenAPI specification\n")
-	help.WriteString("  format      Format an OpenAPI specification file\n")
-	help.WriteString("  serve       Serve OpenAPI specification with Swagger UI\n")
-	help.WriteString("  editor      Launch Swagger Editor for creating/editing specifications\n")
-	help.WriteString("  mcp         Start MCP server for AI assistant integration\n")
-	help.WriteString("  audit       Perform security audit on OpenAPI specification\n")
-	help.WriteString("  version     Show version information\n")
-	help.WriteString("  help        Show this help message\n\n")
+	help.WriteString("  init          Scaffold an annotated project with a config and Makefile target\n")
+	help.WriteString("  import        Generate annotated Go stubs from an existing specification\n")
+	help.WriteString("  migrate-swag  Rewrite swaggo/swag comments as YaSwag annotations\n")
+	help.WriteString("  generate      Generate OpenAPI specification from Go annotations\n")
+	help.WriteString("  validate      Validate an existing OpenAPI specification\n")
+	help.WriteString("  format        Format an OpenAPI specification file\n")
+	help.WriteString("  serve         Serve OpenAPI specification with Swagger UI\n")
+	help.WriteString("  editor        Launch Swagger Editor for creating/editing specifications\n")
+	help.WriteString("  mcp           Start MCP server for AI assistant integration\n")
+	help.WriteString("  audit         Perform security audit on OpenAPI specification\n")
+	help.WriteString("  convert       Convert an OpenAPI specification between 3.0 and 3.1\n")
+	help.WriteString("  filter        Carve a subset out of a specification by tag, path, or operationId\n")
+	help.WriteString("  prune         Remove the components no operation uses\n")
+	help.WriteString("  inline        Replace the $refs to component schemas with the schemas\n")
+	help.WriteString("  overlay       Apply OpenAPI Overlays or JSON Merge Patches to a specification\n")
+	help.WriteString("  lint          Lint OpenAPI specification against API style rules\n")
+	help.WriteString("  docs          Generate static HTML or Markdown documentation\n")
+	help.WriteString("  badge         Render a status badge (validity, audit score, coverage)\n")
+	help.WriteString("  owners        Report which team owns each operation\n")
+	help.WriteString("  testmap       Map operations to their handlers and tests\n")
+	help.WriteString("  deprecations  Report deprecated schema properties still in use\n")
+	help.WriteString("  stats         Count paths, operations, schemas, and described items of a spec\n")
+	help.WriteString("  verify-impl   Compare the specification with a running server\n")
+	help.WriteString("  semver        Recommend a version bump from the changes to a specification\n")
+	help.WriteString("  version       Show version information\n")
+	help.WriteString("  help          Show this help message\n\n")
 	help.WriteString("Use 'yaswag [command] --help' for more information about a command.\n")
 	return help.String()
 }
@@ -542,16 +986,70 @@ func (c *CLI) GenerateHelp() string {
 	help := strings.Builder{}
 	help.WriteString("Generate OpenAPI specification from Go annotations.\n\n")
 	help.WriteString("Usage:\n")
-	help.WriteString("  yaswag generate [options]\n\n")
+	help.WriteString("  yaswag generate [options]\n")
+	help.WriteString("  yaswag generate <subcommand> [options]\n\n")
+	help.WriteString("Subcommands:\n")
+	help.WriteString("  proto             Export the models and operations of a spec as a .proto file\n")
+	help.WriteString("  types             Export them as type definitions, e.g. TypeScript (--lang ts)\n")
+	help.WriteString("  asyncapi          Export the webhooks of a spec as an AsyncAPI 3.0 document\n\n")
 	help.WriteString("Options:\n")
 	help.WriteString("  --source <path>   Source directory to scan for annotations (default: .)\n")
 	help.WriteString("  --format <type>   Output format: json or yaml (default: yaml)\n")
 	help.WriteString("  --output <path>   Output file path (empty for stdout)\n")
 	help.WriteString("  --pretty <n>      Indentation spaces (default: 2)\n")
+	help.WriteString("  --discover        Generate one spec per API root, i.e. each directory with a\n")
+	help.WriteString("                    file declaring !api; --output is then a directory\n")
+	help.WriteString("                    (default: specs) that also gets an index of the services\n")
+	help.WriteString("  --layout <path>   Spec path with --discover, using {service}, {dir}, and {ext}\n")
+	help.WriteString("                    (default: {service}.{ext})\n")
+	help.WriteString("  --strict          Fail on annotation lines that cannot be parsed, which are\n")
+	help.WriteString("                    otherwise reported as warnings\n")
+	help.WriteString("  --exclude-tags <tags>\n")
+	help.WriteString("                    Comma-separated build tags of internal-only files, left out\n")
+	help.WriteString("                    like operations and models annotated with !ignore\n")
+	help.WriteString("  --include-internal\n")
+	help.WriteString("                    Keep internal operations and models, marked x-internal: true\n")
+	help.WriteString("  --openapi <ver>   Convert the generated spec to OpenAPI 3.0 or 3.1\n")
+	help.WriteString("  --env <name>      Replace the !server annotations with the servers of this\n")
+	help.WriteString("                    environment in .yaswag.yaml\n")
+	help.WriteString("  --server <url>    Replace the !server annotations with this server, optionally\n")
+	help.WriteString("                    followed by a description (repeatable)\n")
+	help.WriteString("  --servers-file <path>\n")
+	help.WriteString("                    Replace the !server annotations with the servers listed in a\n")
+	help.WriteString("                    YAML or JSON file, as a list or under a servers key\n")
+	help.WriteString("  --append-servers  Append the --env, --servers-file, and --server servers to the\n")
+	help.WriteString("                    annotated ones instead, skipping URLs already listed\n")
+	help.WriteString("  --stamp           Stamp the short git commit of --source and the build date\n")
+	help.WriteString("                    into info as x-build; SOURCE_DATE_EPOCH sets the date\n")
+	help.WriteString("  --stamp-version <ver>\n")
+	help.WriteString("                    Version to stamp, also replacing info.version\n")
+	help.WriteString("  --stamp-commit <sha>, --stamp-date <date>\n")
+	help.WriteString("                    Commit and date to stamp instead of the git HEAD and the\n")
+	help.WriteString("                    current time; each --stamp-* option implies --stamp\n")
+	help.WriteString("  --split-by-tag    Write each path to <output>_<tag>.<ext> for the first tag of\n")
+	help.WriteString("                    its first operation, with the components it uses; --output\n")
+	help.WriteString("                    then gets a root spec referencing them\n")
+	help.WriteString("  --no-cache        Parse every file; by default the files without annotations\n")
+	help.WriteString("                    that are unchanged since the last run, by content hash, are\n")
+	help.WriteString("                    skipped until an annotation uses their types\n")
+	help.WriteString("  --locale <list>   Generate for these locales, comma-separated, using the\n")
+	help.WriteString("                    localized annotations such as !info.fr and desc.fr=\"...\";\n")
+	help.WriteString("                    with several, each spec goes to <output>.<locale>.<ext>\n")
 	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Options not given default to the generate section of .yaswag.yaml in the\n")
+	help.WriteString("working directory, or of the file YASWAG_CONFIG names.\n\n")
 	help.WriteString("Examples:\n")
 	help.WriteString("  yaswag generate --source ./api --format yaml --output ./swagger.yaml\n")
 	help.WriteString("  yaswag generate --source . --format json\n")
+	help.WriteString("  yaswag generate --source ./services --discover --output ./specs\n")
+	help.WriteString("  yaswag generate --source . --discover --layout {dir}/openapi.{ext}\n")
+	help.WriteString("  yaswag generate --source ./api --strict\n")
+	help.WriteString("  yaswag generate --source . --exclude-tags internal --output ./public.yaml\n")
+	help.WriteString("  yaswag generate --source . --split-by-tag --output ./api/openapi.yaml\n")
+	help.WriteString("  yaswag generate --locale en,fr,es --output ./docs/openapi.yaml\n")
+	help.WriteString("  yaswag generate --env production --openapi 3.1\n")
+	help.WriteString("  yaswag generate --stamp-version \"$(git describe --tags)\" --output ./openapi.yaml\n")
+	help.WriteString("  yaswag generate --server \"https://api.example.com Production\" --append-servers\n")
 	return help.String()
 }
 
@@ -599,12 +1097,28 @@ func (c *CLI) ServeHelp() string {
 	help.WriteString("  yaswag serve [options]\n")
 	help.WriteString("  <command> | yaswag serve\n\n")
 	help.WriteString("Options:\n")
-	help.WriteString("  --input <path>    Input file path, URL, or - for stdin\n")
-	help.WriteString("  --port <n>        Port to serve on (default: 8080)\n")
-	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("  --input <path>          Input file path, URL, or - for stdin\n")
+	help.WriteString("  --port <n>              Port to serve on (default: 8080)\n")
+	help.WriteString("  --ui <name>             Documentation UI: swagger, redoc, scalar, or rapidoc (default: swagger)\n")
+	help.WriteString("  --offline               Serve the UI from embedded assets (no CDN access needed)\n")
+	help.WriteString("  --spec <name=path>      Named spec served at /openapi/<name>.json and selectable in\n")
+	help.WriteString("                          Swagger UI, instead of --input (repeatable)\n")
+	help.WriteString("  --source <dir>          Generate the spec from the annotations in dir, instead of --input\n")
+	help.WriteString("  --watch                 Reload the spec in the browser when the --input file changes,\n")
+	help.WriteString("                          or regenerate it when the Go files of --source change\n")
+	help.WriteString(listenHelp())
+	help.WriteString(brandingHelp())
+	help.WriteString("  --help                  Show this help message\n\n")
 	help.WriteString("Examples:\n")
 	help.WriteString("  yaswag serve --input ./swagger.yaml\n")
 	help.WriteString("  yaswag serve --input ./swagger.yaml --port 9090\n")
+	help.WriteString("  yaswag serve --input ./swagger.yaml --host 127.0.0.1 --tls-cert ./cert.pem --tls-key ./key.pem\n")
+	help.WriteString("  yaswag serve --input ./swagger.yaml --ui scalar\n")
+	help.WriteString("  yaswag serve --input ./swagger.yaml --offline\n")
+	help.WriteString("  yaswag serve --input ./swagger.yaml --logo /static/logo.svg --primary-color '#0b5fff' --nav-link Status=https://status.example.com\n")
+	help.WriteString("  yaswag serve --spec v1=./v1.yaml --spec v2=./v2.yaml\n")
+	help.WriteString("  yaswag serve --input ./swagger.yaml --watch\n")
+	help.WriteString("  yaswag serve --source ./api --watch\n")
 	help.WriteString("  yaswag serve --input https://example.com/api/swagger.yaml\n")
 	help.WriteString("  yaswag generate --source ./api | yaswag serve\n")
 	help.WriteString("  yaswag generate --source ./api | yaswag serve --port 9090\n")
@@ -621,10 +1135,14 @@ func (c *CLI) EditorHelp() string {
 	help.WriteString("Options:\n")
 	help.WriteString("  --input <path>    Input file path, URL, or - for stdin (optional)\n")
 	help.WriteString("  --port <n>        Port to serve on (default: 8080)\n")
+	help.WriteString("  --host <addr>     Interface to bind, e.g. 127.0.0.1 (default: all interfaces)\n")
+	help.WriteString("  --tls-cert <file> TLS certificate file, to serve HTTPS with --tls-key\n")
+	help.WriteString("  --tls-key <file>  TLS key file, to serve HTTPS with --tls-cert\n")
 	help.WriteString("  --help            Show this help message\n\n")
 	help.WriteString("Examples:\n")
 	help.WriteString("  yaswag editor\n")
 	help.WriteString("  yaswag editor --port 9090\n")
+	help.WriteString("  yaswag editor --host 127.0.0.1 --tls-cert ./cert.pem --tls-key ./key.pem\n")
 	help.WriteString("  yaswag editor --input ./swagger.yaml\n")
 	help.WriteString("  yaswag editor --input https://petstore3.swagger.io/api/v3/openapi.json\n")
 	help.WriteString("  yaswag generate --source ./api | yaswag editor\n")
@@ -641,6 +1159,8 @@ func (c *CLI) MCPHelp() string {
 	help.WriteString("  yaswag mcp [options] <spec-file> [spec-file...]\n\n")
 	help.WriteString("Options:\n")
 	help.WriteString("  --skip-validation Skip spec validation before starting the server\n")
+	help.WriteString("  --seed <n>        Seed the generate_example data, so the examples are the\n")
+	help.WriteString("                    same on every run (default: random)\n")
 	help.WriteString("  --help            Show this help message\n\n")
 	help.WriteString("Note: MCP mode requires spec files as arguments. Stdin piping is not\n")
 	help.WriteString("supported because stdin is reserved for JSON-RPC communication.\n\n")
@@ -681,26 +1201,63 @@ func (c *CLI) AuditHelp() string {
 	help.WriteString("  - API keys in query parameters\n")
 	help.WriteString("  - OAuth URLs not using HTTPS\n")
 	help.WriteString("  - Deprecated endpoints without security\n")
-	help.WriteString("  - OAuth scopes referenced but not defined\n\n")
+	help.WriteString("  - OAuth scopes referenced but not defined\n")
+	help.WriteString("  - Public endpoints without documented 429 responses\n")
+	help.WriteString("  - 401 responses without a WWW-Authenticate header\n")
+	help.WriteString("  - Server URLs not using HTTPS\n")
+	help.WriteString("  - Missing global security while operations are unprotected\n\n")
 	help.WriteString("Usage:\n")
 	help.WriteString("  yaswag audit [options]\n")
 	help.WriteString("  <command> | yaswag audit\n\n")
 	help.WriteString("Options:\n")
-	help.WriteString("  --input <path>    Input file path, URL, or - for stdin\n")
-	help.WriteString("  --format <type>   Output format: text or json (default: text)\n")
-	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("  --input <path>        Input file path, URL, or - for stdin\n")
+	help.WriteString("  --format <type>       Output format: text, json, sarif, or pr-comment (default: text)\n")
+	help.WriteString("  --fail-on <sev>       Fail on findings at or above severity: error, warning,\n")
+	help.WriteString("                        info, or none (default: error)\n")
+	help.WriteString("  --max-findings <n>    Findings listed in pr-comment output (default: 10)\n")
+	help.WriteString("  --artifact-url <url>  Link to the full report in pr-comment output\n")
+	help.WriteString("  --severity <list>     Rule severity overrides: RULE=error|warning|info\n")
+	help.WriteString("  --ignore <list>       Rule IDs to skip\n")
+	help.WriteString("  --help                Show this help message\n\n")
+	help.WriteString("Options not given default to the audit section of .yaswag.yaml, if any.\n\n")
 	help.WriteString("Exit Codes:\n")
-	help.WriteString("  0    No ERROR-level issues found\n")
-	help.WriteString("  1    ERROR-level issues found\n\n")
+	help.WriteString("  0    No issues found at or above the --fail-on severity\n")
+	help.WriteString("  1    Issues found at or above the --fail-on severity\n\n")
 	help.WriteString("Examples:\n")
 	help.WriteString("  yaswag audit --input ./swagger.yaml\n")
 	help.WriteString("  yaswag audit --input ./swagger.yaml --format json\n")
+	help.WriteString("  yaswag audit --input ./swagger.yaml --format sarif > audit.sarif\n")
+	help.WriteString("  yaswag audit --input ./swagger.yaml --fail-on warning\n")
+	help.WriteString("  yaswag audit --input ./swagger.yaml --format pr-comment --max-findings 5\n")
 	help.WriteString("  yaswag audit --input https://petstore3.swagger.io/api/v3/openapi.json\n")
 	help.WriteString("  yaswag generate --source ./api | yaswag audit\n")
 	help.WriteString("  cat swagger.yaml | yaswag audit\n")
 	return help.String()
 }
 
+func (c *CLI) ConvertHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Convert an OpenAPI specification between versions 3.0 and 3.1.\n\n")
+	help.WriteString("Translates nullable and type arrays, exclusiveMinimum/exclusiveMaximum,\n")
+	help.WriteString("example and examples, and drops constructs the target cannot express\n")
+	help.WriteString("(such as webhooks when converting to 3.0).\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag convert [options]\n")
+	help.WriteString("  <command> | yaswag convert [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>    Input file path or - for stdin\n")
+	help.WriteString("  --output <path>   Output file path (empty for stdout)\n")
+	help.WriteString("  --target <ver>    Target OpenAPI version: 3.0 or 3.1 (default: 3.1)\n")
+	help.WriteString("  --format <type>   Output format: json or yaml (auto-detected if not specified)\n")
+	help.WriteString("  --pretty <n>      Indentation spaces (default: 2)\n")
+	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag convert --input ./swagger.yaml --target 3.1 --output ./openapi-3.1.yaml\n")
+	help.WriteString("  yaswag convert --input ./openapi.json --target 3.0\n")
+	help.WriteString("  yaswag generate --source ./api | yaswag convert --target 3.1\n")
+	return help.String()
+}
+
 // formatSpec formats an OpenAPI spec to the specified format with indentation.
 func formatSpec(data []byte, format output.Format, indent int) ([]byte, error) {
 	// Use libopenapi to parse and render
@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/codegen"
+	"github.com/fathurrohman26/yaswag/pkg/output"
+)
+
+func (c *CLI) runGenerateProto(args []string) error {
+	fs := flag.NewFlagSet("generate proto", flag.ExitOnError)
+	input := fs.String("input", "", "Input specification file or - for stdin")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	pkg := fs.String("package", "", "Protobuf package (default: title and major version, e.g. pet_store.v1)")
+	goPackage := fs.String("go-package", "", "Value of the go_package file option")
+	service := fs.String("service", "", "Service name (default: title followed by Service)")
+	showHelp := fs.Bool("help", false, "Show help for generate proto command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.GenerateProtoHelp())
+		return nil
+	}
+
+	inputData, err := readFromStdinOrFile(*input, true)
+	if err != nil {
+		return err
+	}
+	doc, err := parseDocument(inputData.data)
+	if err != nil {
+		return err
+	}
+
+	data := codegen.Proto(doc, codegen.ProtoOptions{Package: *pkg, GoPackage: *goPackage, Service: *service})
+	return c.writeOutput(*outputPath, data, "Protobuf definitions")
+}
+
+func (c *CLI) GenerateProtoHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Export the models and operations of a specification as a proto3 file.\n\n")
+	help.WriteString("Component schemas become messages and enums:\n")
+	help.WriteString("  - objects become messages, with allOf merged and oneOf/anyOf as a oneof\n")
+	help.WriteString("  - string enums become enums, starting with <NAME>_UNSPECIFIED = 0\n")
+	help.WriteString("  - arrays become repeated fields, additionalProperties maps\n")
+	help.WriteString("  - date-time strings become google.protobuf.Timestamp, byte and binary bytes\n")
+	help.WriteString("  - anything else without a protobuf counterpart becomes google.protobuf.Value\n\n")
+	help.WriteString("Operations become RPCs of one service, named after their operationId, taking\n")
+	help.WriteString("a request message of their parameters and body and returning the schema of\n")
+	help.WriteString("their success response, or google.protobuf.Empty.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag generate proto [options]\n")
+	help.WriteString("  <command> | yaswag generate proto [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>    Input specification file or - for stdin\n")
+	help.WriteString("  --output <path>   Output file path (empty for stdout)\n")
+	help.WriteString("  --package <name>  Protobuf package (default: title and major version,\n")
+	help.WriteString("                    e.g. pet_store.v1)\n")
+	help.WriteString("  --go-package <path>\n")
+	help.WriteString("                    Value of the go_package file option\n")
+	help.WriteString("  --service <name>  Service name (default: title followed by Service)\n")
+	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag generate proto --input ./openapi.yaml --output ./api.proto\n")
+	help.WriteString("  yaswag generate --source . | yaswag generate proto --package petstore.v1 --go-package example.com/petstore/pb\n")
+	return help.String()
+}
+
+func (c *CLI) runGenerateTypes(args []string) error {
+	fs := flag.NewFlagSet("generate types", flag.ExitOnError)
+	input := fs.String("input", "", "Input specification file or - for stdin")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	lang := fs.String("lang", "ts", "Language of the type definitions (ts)")
+	showHelp := fs.Bool("help", false, "Show help for generate types command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.GenerateTypesHelp())
+		return nil
+	}
+
+	switch strings.ToLower(*lang) {
+	case "ts", "typescript":
+	default:
+		return fmt.Errorf("unsupported language: %s (supported: ts)", *lang)
+	}
+
+	inputData, err := readFromStdinOrFile(*input, true)
+	if err != nil {
+		return err
+	}
+	doc, err := parseDocument(inputData.data)
+	if err != nil {
+		return err
+	}
+
+	return c.writeOutput(*outputPath, codegen.TypeScript(doc), "TypeScript definitions")
+}
+
+func (c *CLI) GenerateTypesHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Export the models and operations of a specification as type definitions.\n\n")
+	help.WriteString("With --lang ts, writes a TypeScript .d.ts file:\n")
+	help.WriteString("  - object schemas become interfaces, extending the interfaces they allOf\n")
+	help.WriteString("  - enums become unions of their literal values, oneOf and anyOf unions\n")
+	help.WriteString("  - other schemas become type aliases, with | null when nullable\n")
+	help.WriteString("  - each operation gets a <Name>Request interface of its path, query, header,\n")
+	help.WriteString("    and cookie parameters and body, and a <Name>Response type of its success\n")
+	help.WriteString("    response, where Name is the operationId in pascal case\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag generate types --lang ts [options]\n")
+	help.WriteString("  <command> | yaswag generate types --lang ts [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --lang <lang>     Language of the type definitions: ts (default: ts)\n")
+	help.WriteString("  --input <path>    Input specification file or - for stdin\n")
+	help.WriteString("  --output <path>   Output file path (empty for stdout)\n")
+	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag generate types --lang ts --input ./openapi.yaml --output ./web/src/api.d.ts\n")
+	help.WriteString("  yaswag generate --source . | yaswag generate types --lang ts > api.d.ts\n")
+	return help.String()
+}
+
+func (c *CLI) runGenerateAsyncAPI(args []string) error {
+	fs := flag.NewFlagSet("generate asyncapi", flag.ExitOnError)
+	input := fs.String("input", "", "Input specification file or - for stdin")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	format := fs.String("format", "", "Output format (json or yaml, auto-detected from extension if not specified)")
+	pretty := fs.Int("pretty", 2, "Indentation spaces for pretty printing")
+	showHelp := fs.Bool("help", false, "Show help for generate asyncapi command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.GenerateAsyncAPIHelp())
+		return nil
+	}
+
+	inputData, err := readFromStdinOrFile(*input, true)
+	if err != nil {
+		return err
+	}
+	doc, err := parseDocument(inputData.data)
+	if err != nil {
+		return err
+	}
+
+	asyncDoc, err := codegen.AsyncAPI(doc)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if c.determineOutputFormat(*format, *outputPath, *input, inputData.fromStdin) == output.FormatJSON {
+		if data, err = jsonMarshalIndent(asyncDoc, *pretty); err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+		data = append(data, '\n')
+	} else if data, err = yamlMarshalIndent(asyncDoc, *pretty); err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	return c.writeOutput(*outputPath, data, "AsyncAPI document")
+}
+
+func (c *CLI) GenerateAsyncAPIHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Export the webhooks of a specification as an AsyncAPI 3.0 document.\n\n")
+	help.WriteString("Each webhook becomes a channel named after it, and each of its operations a\n")
+	help.WriteString("send operation named after its operationId, with an HTTP binding of its\n")
+	help.WriteString("method. The message of an operation carries the request body as payload and\n")
+	help.WriteString("the header parameters as headers. The component schemas the webhooks use are\n")
+	help.WriteString("copied, so event consumers can use AsyncAPI tooling on the same models.\n\n")
+	help.WriteString("Webhooks are an OpenAPI 3.1 feature; specifications without any are an error.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag generate asyncapi [options]\n")
+	help.WriteString("  <command> | yaswag generate asyncapi [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>    Input specification file or - for stdin\n")
+	help.WriteString("  --output <path>   Output file path (empty for stdout)\n")
+	help.WriteString("  --format <type>   Output format: json or yaml (default: from --output, or yaml)\n")
+	help.WriteString("  --pretty <n>      Indentation spaces (default: 2)\n")
+	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag generate asyncapi --input ./openapi.yaml --output ./asyncapi.yaml\n")
+	help.WriteString("  yaswag generate asyncapi --input ./openapi.json --format json\n")
+	return help.String()
+}
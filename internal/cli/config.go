@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"gopkg.in/yaml.v3"
+)
+
+// projectConfigFile is the project config file looked up in the working
+// directory; the YASWAG_CONFIG environment variable names another one.
+const projectConfigFile = ".yaswag.yaml"
+
+// projectConfig is the YAML form of the project config file. Its values are
+// defaults for the flags of the same name, which override them.
+type projectConfig struct {
+	Generate struct {
+		Source          string   `yaml:"source"`
+		Output          string   `yaml:"output"`
+		Format          string   `yaml:"format"`
+		Pretty          int      `yaml:"pretty"`
+		OpenAPI         string   `yaml:"openapi"`
+		Env             string   `yaml:"env"`
+		ExcludeTags     []string `yaml:"excludeTags"`
+		IncludeInternal bool     `yaml:"includeInternal"`
+		Strict          bool     `yaml:"strict"`
+		SplitByTag      bool     `yaml:"splitByTag"`
+		ServersFile     string   `yaml:"serversFile"`
+		AppendServers   bool     `yaml:"appendServers"`
+		Stamp           bool     `yaml:"stamp"`
+		NoCache         bool     `yaml:"noCache"`
+		Locales         []string `yaml:"locales"`
+	} `yaml:"generate"`
+
+	Lint struct {
+		Preset      string            `yaml:"preset"`
+		Severities  map[string]string `yaml:"severities"`
+		Ignore      []string          `yaml:"ignore"`
+		IgnorePaths []string          `yaml:"ignorePaths"`
+		FailOn      string            `yaml:"failOn"`
+	} `yaml:"lint"`
+
+	Audit struct {
+		Severities map[string]string `yaml:"severities"`
+		Ignore     []string          `yaml:"ignore"`
+		FailOn     string            `yaml:"failOn"`
+	} `yaml:"audit"`
+
+	// Servers lists the servers of each environment, selected with --env
+	Servers map[string][]openapi.Server `yaml:"servers"`
+
+	path string
+}
+
+// loadProjectConfig reads the project config file, returning an empty config
+// if there is none. YASWAG_CONFIG names the file, which must then exist.
+func loadProjectConfig() (*projectConfig, error) {
+	path, required := os.Getenv("YASWAG_CONFIG"), true
+	if path == "" {
+		path, required = projectConfigFile, false
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return &projectConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	config := &projectConfig{path: path}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// applyConfig sets each flag of fs not given on the command line to its
+// value in the project config. Empty values leave the flag's default.
+func applyConfig(fs *flag.FlagSet, config *projectConfig, values map[string]string) error {
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	for name, value := range values {
+		if value == "" || given[name] {
+			continue
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("config %s: %s: %w", config.path, name, err)
+		}
+	}
+	return nil
+}
+
+// generateFlags returns the generate flag values of the config.
+func (p *projectConfig) generateFlags() map[string]string {
+	g := p.Generate
+	return map[string]string{
+		"source":           g.Source,
+		"output":           g.Output,
+		"format":           g.Format,
+		"pretty":           configInt(g.Pretty),
+		"openapi":          g.OpenAPI,
+		"env":              g.Env,
+		"exclude-tags":     strings.Join(g.ExcludeTags, ","),
+		"include-internal": configBool(g.IncludeInternal),
+		"strict":           configBool(g.Strict),
+		"split-by-tag":     configBool(g.SplitByTag),
+		"servers-file":     g.ServersFile,
+		"append-servers":   configBool(g.AppendServers),
+		"stamp":            configBool(g.Stamp),
+		"no-cache":         configBool(g.NoCache),
+		"locale":           strings.Join(g.Locales, ","),
+	}
+}
+
+// lintFlags returns the lint flag values of the config.
+func (p *projectConfig) lintFlags() map[string]string {
+	l := p.Lint
+	return map[string]string{
+		"preset":       l.Preset,
+		"severity":     severityList(l.Severities),
+		"ignore":       strings.Join(l.Ignore, ","),
+		"ignore-paths": strings.Join(l.IgnorePaths, ","),
+		"fail-on":      l.FailOn,
+	}
+}
+
+// auditFlags returns the audit flag values of the config.
+func (p *projectConfig) auditFlags() map[string]string {
+	a := p.Audit
+	return map[string]string{
+		"severity": severityList(a.Severities),
+		"ignore":   strings.Join(a.Ignore, ","),
+		"fail-on":  a.FailOn,
+	}
+}
+
+// environmentServers returns the servers of env in the config.
+func (p *projectConfig) environmentServers(env string) ([]openapi.Server, error) {
+	servers, ok := p.Servers[env]
+	if !ok {
+		envs := make([]string, 0, len(p.Servers))
+		for name := range p.Servers {
+			envs = append(envs, name)
+		}
+		slices.Sort(envs)
+		if len(envs) == 0 {
+			return nil, fmt.Errorf("unknown environment %q: no servers configured", env)
+		}
+		return nil, fmt.Errorf("unknown environment %q (configured: %s)", env, strings.Join(envs, ", "))
+	}
+	return servers, nil
+}
+
+// severityList formats rule severities as the RULE=level list the
+// --severity flags take.
+func severityList(severities map[string]string) string {
+	entries := make([]string, 0, len(severities))
+	for ruleID, level := range severities {
+		entries = append(entries, ruleID+"="+level)
+	}
+	slices.Sort(entries)
+	return strings.Join(entries, ",")
+}
+
+func configInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func configBool(b bool) string {
+	if !b {
+		return ""
+	}
+	return "true"
+}
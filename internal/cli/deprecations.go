@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+func (c *CLI) runDeprecations(args []string) error {
+	fs := flag.NewFlagSet("deprecations", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path or - for stdin")
+	format := fs.String("format", "text", "Output format: text or json (default: text)")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	failRequired := fs.Bool("fail-required", false, "Exit with status 1 if a deprecated property is still required")
+	showHelp := fs.Bool("help", false, "Show help for deprecations command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.DeprecationsHelp())
+		return nil
+	}
+
+	result, err := readFromStdinOrFile(*input, true)
+	if err != nil {
+		return err
+	}
+
+	doc, err := parseDocument(result.data)
+	if err != nil {
+		return err
+	}
+
+	props := doc.DeprecatedProperties()
+	data, err := marshalDeprecations(props, *format)
+	if err != nil {
+		return err
+	}
+	if err := c.writeOutput(*outputPath, data, "Deprecation report"); err != nil {
+		return err
+	}
+
+	if *failRequired && countRequired(props) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func marshalDeprecations(props []openapi.DeprecatedProperty, format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		data, err := json.MarshalIndent(struct {
+			Properties []openapi.DeprecatedProperty `json:"properties"`
+		}{append([]openapi.DeprecatedProperty{}, props...)}, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to format JSON: %w", err)
+		}
+		return append(data, '\n'), nil
+	case "text":
+		return []byte(formatDeprecations(props)), nil
+	default:
+		return nil, fmt.Errorf("unsupported deprecations format: %s (supported: text, json)", format)
+	}
+}
+
+func countRequired(props []openapi.DeprecatedProperty) int {
+	n := 0
+	for _, p := range props {
+		if p.Required {
+			n++
+		}
+	}
+	return n
+}
+
+func formatDeprecations(props []openapi.DeprecatedProperty) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Deprecated properties: %d (%d still required)\n", len(props), countRequired(props))
+
+	for _, p := range props {
+		sb.WriteString("\n" + p.Schema + "." + p.Property)
+		if p.Required {
+			sb.WriteString(" (required)")
+		}
+		sb.WriteString("\n")
+		if len(p.Operations) == 0 {
+			sb.WriteString("  not used by any operation\n")
+			continue
+		}
+		for _, op := range p.Operations {
+			fmt.Fprintf(&sb, "  %s\n", op)
+		}
+	}
+	return sb.String()
+}
+
+func (c *CLI) DeprecationsHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Report the deprecated properties of an OpenAPI specification's schemas.\n\n")
+	help.WriteString("Lists every component schema property marked deprecated (with the !field\n")
+	help.WriteString("deprecated modifier), whether the schema still requires it, and the operations\n")
+	help.WriteString("that still use the schema, to track properties until they can be removed.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag deprecations [options]\n")
+	help.WriteString("  <command> | yaswag deprecations [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>    Input file path or - for stdin\n")
+	help.WriteString("  --format <type>   Output format: text or json (default: text)\n")
+	help.WriteString("  --output <path>   Output file path (empty for stdout)\n")
+	help.WriteString("  --fail-required   Exit with status 1 if a deprecated property is still required\n")
+	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag deprecations --input ./swagger.yaml\n")
+	help.WriteString("  yaswag deprecations --input ./swagger.yaml --format json --output deprecations.json\n")
+	help.WriteString("  yaswag generate --source ./api | yaswag deprecations --fail-required\n")
+	return help.String()
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/parser"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"github.com/fathurrohman26/yaswag/pkg/output"
+)
+
+const (
+	defaultDiscoverLayout = "{service}.{ext}"
+	defaultDiscoverOutput = "specs"
+)
+
+type discoverOptions struct {
+	outputDir string
+	layout    string
+	format    string
+	pretty    int
+	generate  generateOptions
+}
+
+// serviceIndex is the summary of the specs generated with --discover.
+type serviceIndex struct {
+	Services []serviceEntry `json:"services" yaml:"services"`
+}
+
+type serviceEntry struct {
+	Name       string `json:"name" yaml:"name"`
+	Source     string `json:"source" yaml:"source"`
+	Spec       string `json:"spec" yaml:"spec"`
+	Title      string `json:"title" yaml:"title"`
+	Version    string `json:"version" yaml:"version"`
+	Operations int    `json:"operations" yaml:"operations"`
+}
+
+// generateServices generates a spec for every API root under source into
+// the output directory, then writes an index of the services next to them.
+func (c *CLI) generateServices(source string, opts discoverOptions) error {
+	format, err := output.ParseFormat(opts.format)
+	if err != nil {
+		return err
+	}
+	if opts.outputDir == "" {
+		opts.outputDir = defaultDiscoverOutput
+	}
+
+	roots, err := parser.DiscoverRoots(source)
+	if err != nil {
+		return fmt.Errorf("failed to discover API roots: %w", err)
+	}
+	if len(roots) == 0 {
+		return fmt.Errorf("no API roots (files with an !api annotation) found in %s", source)
+	}
+
+	index := serviceIndex{Services: []serviceEntry{}}
+	for _, root := range roots {
+		entry, err := c.generateService(source, root, roots, format, opts)
+		if err != nil {
+			return err
+		}
+		index.Services = append(index.Services, entry)
+		fmt.Printf("%s: %s written to %s\n", entry.Name, entry.Title, filepath.Join(opts.outputDir, entry.Spec))
+	}
+
+	data, err := marshalIndex(index, format, opts.pretty)
+	if err != nil {
+		return err
+	}
+	return c.writeOutput(filepath.Join(opts.outputDir, "index."+string(format)), data, "Service index")
+}
+
+// generateService generates the spec of the service rooted at root,
+// excluding the services nested below it.
+func (c *CLI) generateService(source, root string, roots []string, format output.Format, opts discoverOptions) (serviceEntry, error) {
+	name := serviceName(source, root)
+	generate := opts.generate
+	generate.exclude = parser.NestedRoots(root, roots)
+	doc, err := c.parseAndGenerate(root, generate)
+	if err != nil {
+		return serviceEntry{}, fmt.Errorf("service %s: %w", name, err)
+	}
+	data, err := c.formatOutput(doc, string(format), opts.pretty)
+	if err != nil {
+		return serviceEntry{}, fmt.Errorf("service %s: %w", name, err)
+	}
+
+	dir, _ := filepath.Rel(source, root)
+	spec := strings.NewReplacer("{service}", name, "{dir}", filepath.ToSlash(dir), "{ext}", string(format)).Replace(opts.layout)
+	path := filepath.Join(opts.outputDir, filepath.FromSlash(spec))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return serviceEntry{}, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return serviceEntry{}, fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	entry := serviceEntry{
+		Name:    name,
+		Source:  filepath.ToSlash(root),
+		Spec:    filepath.ToSlash(filepath.Clean(spec)),
+		Title:   doc.Info.Title,
+		Version: doc.Info.Version,
+	}
+	eachOperation(doc, func(string, string, *openapi.Operation) { entry.Operations++ })
+	return entry, nil
+}
+
+// serviceName names a service after the path of its root relative to source,
+// e.g. "billing-api" for services/billing/api under services.
+func serviceName(source, root string) string {
+	rel, err := filepath.Rel(source, root)
+	if err != nil || rel == "." {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return filepath.Base(root)
+		}
+		return filepath.Base(abs)
+	}
+	return strings.ReplaceAll(filepath.ToSlash(rel), "/", "-")
+}
+
+func marshalIndex(index serviceIndex, format output.Format, pretty int) ([]byte, error) {
+	if format == output.FormatJSON {
+		data, err := json.MarshalIndent(index, "", strings.Repeat(" ", pretty))
+		if err != nil {
+			return nil, fmt.Errorf("failed to format index: %w", err)
+		}
+		return append(data, '\n'), nil
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(pretty)
+	if err := encoder.Encode(index); err != nil {
+		return nil, fmt.Errorf("failed to format index: %w", err)
+	}
+	return buf.Bytes(), nil
+}
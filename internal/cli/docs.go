@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/docs"
+)
+
+func (c *CLI) runDocs(args []string) error {
+	fs := flag.NewFlagSet("docs", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path or - for stdin")
+	outputPath := fs.String("output", "", "Output directory (markdown) or file (html, empty for stdout)")
+	format := fs.String("format", "html", "Output format: html or markdown (default: html)")
+	branding := brandingFlags(fs)
+	showHelp := fs.Bool("help", false, "Show help for docs command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.DocsHelp())
+		return nil
+	}
+
+	result, err := readFromStdinOrFile(*input, true)
+	if err != nil {
+		return err
+	}
+
+	doc, err := parseDocument(result.data)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(*format) {
+	case "markdown", "md":
+		if *outputPath == "" {
+			return fmt.Errorf("--output directory is required for markdown format")
+		}
+		if err := docs.WriteMarkdown(doc, *outputPath); err != nil {
+			return err
+		}
+		fmt.Printf("Markdown documentation written to %s\n", *outputPath)
+		return nil
+	case "html":
+		data, err := docs.HTMLWithBranding(doc, branding())
+		if err != nil {
+			return err
+		}
+		return c.writeOutput(*outputPath, data, "HTML documentation")
+	default:
+		return fmt.Errorf("unsupported docs format: %s (supported: html, markdown)", *format)
+	}
+}
+
+func (c *CLI) DocsHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Generate static documentation from an OpenAPI specification.\n\n")
+	help.WriteString("Renders operations grouped by tag, with parameters, request and response\n")
+	help.WriteString("schemas, and example payloads. The output needs no CDN or JavaScript.\n\n")
+	help.WriteString("Formats:\n")
+	help.WriteString("  html        A single self-contained HTML page\n")
+	help.WriteString("  markdown    README.md index, one file per tag, and schemas.md\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag docs [options]\n")
+	help.WriteString("  <command> | yaswag docs [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>          Input file path or - for stdin\n")
+	help.WriteString("  --format <type>         Output format: html or markdown (default: html)\n")
+	help.WriteString("  --output <path>         Output file for html (empty for stdout), or directory\n")
+	help.WriteString("                          for markdown (required)\n")
+	help.WriteString("  --help                  Show this help message\n\n")
+	help.WriteString("Branding options (html only):\n")
+	help.WriteString(brandingHelp())
+	help.WriteString("\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag docs --input ./swagger.yaml --output ./api.html\n")
+	help.WriteString("  yaswag docs --input ./swagger.yaml --format markdown --output ./docs/api\n")
+	help.WriteString("  yaswag docs --input ./swagger.yaml --footer '© Example Corp' --output ./api.html\n")
+	help.WriteString("  yaswag generate --source ./api | yaswag docs > api.html\n")
+	return help.String()
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// listFlag is a flag that can be repeated or given a comma-separated list.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
+
+func (c *CLI) runFilter(args []string) error {
+	fs := flag.NewFlagSet("filter", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path or - for stdin")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	format := fs.String("format", "", "Output format (json or yaml, auto-detected from extension if not specified)")
+	pretty := fs.Int("pretty", 2, "Indentation spaces for pretty printing")
+	var opts openapi.FilterOptions
+	fs.Var((*listFlag)(&opts.Tags), "tag", "Keep operations with this tag (repeatable)")
+	fs.Var((*listFlag)(&opts.Paths), "path", "Keep operations whose path matches this pattern (repeatable)")
+	fs.Var((*listFlag)(&opts.OperationIDs), "operation", "Keep the operation with this operationId (repeatable)")
+	fs.Var((*listFlag)(&opts.ExcludeTags), "exclude-tag", "Drop operations with this tag (repeatable)")
+	fs.Var((*listFlag)(&opts.ExcludePaths), "exclude-path", "Drop operations whose path matches this pattern (repeatable)")
+	fs.Var((*listFlag)(&opts.ExcludeOperationIDs), "exclude-operation", "Drop the operation with this operationId (repeatable)")
+	showHelp := fs.Bool("help", false, "Show help for filter command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.FilterHelp())
+		return nil
+	}
+
+	result, err := readFromStdinOrFile(*input, true)
+	if err != nil {
+		return err
+	}
+
+	doc, err := parseDocument(result.data)
+	if err != nil {
+		return err
+	}
+
+	filtered, err := openapi.Filter(doc, opts)
+	if err != nil {
+		return fmt.Errorf("failed to filter spec: %w", err)
+	}
+
+	outputFormat := c.determineOutputFormat(*format, *outputPath, *input, result.fromStdin)
+	data, err := c.formatOutput(filtered, string(outputFormat), *pretty)
+	if err != nil {
+		return err
+	}
+
+	return c.writeOutput(*outputPath, data, "Filtered specification")
+}
+
+func (c *CLI) FilterHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Carve a subset out of an OpenAPI specification.\n\n")
+	help.WriteString("Keeps the operations matching every include option given and none of the\n")
+	help.WriteString("exclude options, then drops the path items, tags, components, and security\n")
+	help.WriteString("schemes the remaining operations no longer use. Path patterns match path\n")
+	help.WriteString("templates, with * matching any characters. Each option can be repeated or\n")
+	help.WriteString("given a comma-separated list.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag filter [options]\n")
+	help.WriteString("  <command> | yaswag filter [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>              Input file path or - for stdin\n")
+	help.WriteString("  --output <path>             Output file path (empty for stdout)\n")
+	help.WriteString("  --format <type>             Output format: json or yaml (auto-detected if not specified)\n")
+	help.WriteString("  --pretty <n>                Indentation spaces (default: 2)\n")
+	help.WriteString("  --tag <name>                Keep operations with this tag (\"default\" for untagged ones)\n")
+	help.WriteString("  --path <pattern>            Keep operations whose path matches the pattern\n")
+	help.WriteString("  --operation <id>            Keep the operation with this operationId\n")
+	help.WriteString("  --exclude-tag <name>        Drop operations with this tag\n")
+	help.WriteString("  --exclude-path <pattern>    Drop operations whose path matches the pattern\n")
+	help.WriteString("  --exclude-operation <id>    Drop the operation with this operationId\n")
+	help.WriteString("  --help                      Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag filter --input ./swagger.yaml --tag pet --exclude-path '/internal/*'\n")
+	help.WriteString("  yaswag filter --input ./swagger.yaml --tag pet,store --output ./partner.yaml\n")
+	help.WriteString("  yaswag generate --source ./api | yaswag filter --exclude-tag admin\n")
+	return help.String()
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/codegen"
+)
+
+func (c *CLI) runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	outDir := fs.String("out", "./api", "Directory to write the Go files to")
+	pkg := fs.String("package", "", "Package name of the Go files (default: name of the --out directory)")
+	force := fs.Bool("force", false, "Overwrite existing files")
+	showHelp := fs.Bool("help", false, "Show help for import command")
+
+	// accept options after the spec too, as in yaswag import spec.yaml --out ./api
+	inputs, err := parseInterleaved(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.ImportHelp())
+		return nil
+	}
+	if len(inputs) != 1 {
+		return fmt.Errorf("one specification required: yaswag import <spec> [options]")
+	}
+
+	inputData, err := readFromStdinOrFile(inputs[0], true)
+	if err != nil {
+		return err
+	}
+	doc, err := parseDocument(inputData.data)
+	if err != nil {
+		return err
+	}
+
+	if *pkg == "" {
+		*pkg = packageName(*outDir)
+	}
+	files, notes, err := codegen.Annotations(doc, codegen.AnnotationsOptions{Package: *pkg})
+	if err != nil {
+		return fmt.Errorf("failed to generate Go files: %w", err)
+	}
+
+	if err := writeGoFiles(*outDir, files, *force); err != nil {
+		return err
+	}
+	for _, note := range notes {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", note)
+	}
+
+	fmt.Printf("\nRun 'yaswag generate --source %s' to generate the specification from the annotations.\n", *outDir)
+	return nil
+}
+
+// checkNotExisting fails if any of files already exists in dir.
+func checkNotExisting(dir string, files []codegen.GoFile) error {
+	var existing []string
+	for _, f := range files {
+		if _, err := os.Stat(filepath.Join(dir, f.Name)); err == nil {
+			existing = append(existing, f.Name)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if len(existing) > 0 {
+		return fmt.Errorf("%s already exist(s) in %s (use --force to overwrite)", strings.Join(existing, ", "), dir)
+	}
+	return nil
+}
+
+// writeGoFiles writes files to dir, creating it if needed, and fails if any
+// of them exists unless force.
+func writeGoFiles(dir string, files []codegen.GoFile, force bool) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	if !force {
+		if err := checkNotExisting(dir, files); err != nil {
+			return err
+		}
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f.Name)
+		if err := os.WriteFile(path, f.Content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		fmt.Printf("Created %s\n", path)
+	}
+	return nil
+}
+
+// packageName derives a Go package name from the name of dir, e.g.
+// billingapi for ./billing-api, falling back to api.
+func packageName(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "api"
+	}
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return -1
+	}, filepath.Base(abs))
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		return "api"
+	}
+	return name
+}
+
+func (c *CLI) ImportHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Generate Go files annotated for YaSwag from an existing specification.\n\n")
+	help.WriteString("Eases moving a service documented by a hand-written spec to annotations.\n")
+	help.WriteString("Writes to the --out directory:\n")
+	help.WriteString("  - api.go, with the API-level annotations (!api, !info, !server, !tag,\n")
+	help.WriteString("    !security) in its package comment\n")
+	help.WriteString("  - models.go, with a !model type per component schema and inline object,\n")
+	help.WriteString("    and a !field line for each property with a description, example, or\n")
+	help.WriteString("    format to carry\n")
+	help.WriteString("  - a file per tag, with an annotated handler stub per operation that\n")
+	help.WriteString("    responds 501 Not Implemented\n\n")
+	help.WriteString("What annotations cannot express, such as enums, validation keywords, oneOf,\n")
+	help.WriteString("and webhooks, is left out with a TODO comment and reported as a warning.\n")
+	help.WriteString("Existing files are only overwritten with --force.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag import <spec> [options]\n")
+	help.WriteString("  <command> | yaswag import - [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --out <dir>       Directory to write the Go files to (default: ./api)\n")
+	help.WriteString("  --package <name>  Package name of the Go files (default: name of the\n")
+	help.WriteString("                    --out directory)\n")
+	help.WriteString("  --force           Overwrite existing files\n")
+	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag import openapi.yaml --out ./api\n")
+	help.WriteString("  yaswag import petstore.json --out ./internal/petstore --package petstore\n")
+	return help.String()
+}
@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// scaffoldFile is a file written by init, rendered from a template.
+type scaffoldFile struct {
+	name     string
+	template string
+	appendTo bool // append to an existing file instead of refusing to overwrite it
+}
+
+// scaffoldData is the data the init templates are rendered with.
+type scaffoldData struct {
+	Title  string
+	Output string
+}
+
+var scaffoldFiles = []scaffoldFile{
+	{name: "main.go", template: scaffoldMain},
+	{name: ".yaswag.yaml", template: scaffoldConfig},
+	{name: "Makefile", template: scaffoldMakefile, appendTo: true},
+}
+
+const scaffoldMain = `package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// {{.Title}}
+//
+// !api 3.1.0
+// !info "{{.Title}}" v0.1.0 "An API documented with YaSwag annotations."
+// !server http://localhost:8080 "Local development"
+// !tag items "Manage items"
+func main() {
+	http.HandleFunc("GET /items/{id}", GetItem)
+	log.Fatal(http.ListenAndServe(":8080", nil))
+}
+
+// Item is an example model. Each !field documents one property.
+//
+// !model "An item in the catalog"
+type Item struct {
+	// !field id:int64 "Unique identifier" readonly example=1
+	ID int64 ` + "`json:\"id\"`" + `
+
+	// !field name:string "Name of the item" required example="Widget"
+	Name string ` + "`json:\"name\"`" + `
+}
+
+// !model "An error response"
+type Error struct {
+	// !field message:string "What went wrong" required example="invalid id"
+	Message string ` + "`json:\"message\"`" + `
+}
+
+// GetItem is an example operation.
+//
+// !GET /items/{id} -> getItem "Get an item by ID" #items
+// !path id:int64 "ID of the item" required
+// !ok Item "The item"
+// !error 400 Error "Invalid ID"
+func GetItem(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(Error{Message: "invalid id"})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(Item{ID: id, Name: "Widget"})
+}
+`
+
+const scaffoldConfig = `# YaSwag project configuration; command-line flags override these values.
+generate:
+  source: .
+  output: {{.Output}}
+  # openapi: "3.0"     # convert the generated spec to this OpenAPI version
+  # env: production    # replace the !server annotations with an environment's servers
+
+# servers:
+#   production:
+#     - url: https://api.example.com
+#       description: Production
+
+# lint:
+#   preset: zalando
+#   failOn: warning
+
+# audit:
+#   ignore: [RATE_LIMIT_UNDOCUMENTED]
+`
+
+const scaffoldMakefile = `
+.PHONY: openapi
+openapi: ## Generate and validate the OpenAPI specification
+	yaswag generate
+	yaswag validate --input {{.Output}}
+`
+
+func (c *CLI) runInit(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	dir := fs.String("dir", ".", "Directory to scaffold the project in")
+	title := fs.String("title", "", "API title (default: derived from the directory name)")
+	outputPath := fs.String("output", "openapi.yaml", "Spec path the config generates to")
+	force := fs.Bool("force", false, "Overwrite existing main.go and .yaswag.yaml")
+	showHelp := fs.Bool("help", false, "Show help for init command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.InitHelp())
+		return nil
+	}
+
+	data := scaffoldData{Title: *title, Output: *outputPath}
+	if data.Title == "" {
+		data.Title = defaultTitle(*dir)
+	}
+	data.Title = strings.ReplaceAll(data.Title, `"`, "'")
+
+	if err := os.MkdirAll(*dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", *dir, err)
+	}
+	if !*force {
+		if err := checkScaffoldConflicts(*dir); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range scaffoldFiles {
+		if err := writeScaffoldFile(filepath.Join(*dir, f.name), f, data); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("\nRun 'make openapi' in %s to generate %s.\n", *dir, *outputPath)
+	return nil
+}
+
+// checkScaffoldConflicts returns an error naming the scaffold files that
+// already exist in dir and would be overwritten.
+func checkScaffoldConflicts(dir string) error {
+	var existing []string
+	for _, f := range scaffoldFiles {
+		if f.appendTo {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, f.name)); err == nil {
+			existing = append(existing, f.name)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if len(existing) > 0 {
+		return fmt.Errorf("%s already exist(s) in %s (use --force to overwrite)", strings.Join(existing, ", "), dir)
+	}
+	return nil
+}
+
+// writeScaffoldFile renders f to path. A file f appends to keeps its content
+// and gets the rendered template appended, unless it already has it.
+func writeScaffoldFile(path string, f scaffoldFile, data scaffoldData) error {
+	var buf bytes.Buffer
+	if err := template.Must(template.New(f.name).Parse(f.template)).Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", f.name, err)
+	}
+	content := buf.Bytes()
+
+	if f.appendTo {
+		existing, err := os.ReadFile(path)
+		switch {
+		case err == nil && bytes.Contains(existing, []byte("\nopenapi:")):
+			fmt.Printf("%s already has an openapi target, skipped\n", path)
+			return nil
+		case err == nil:
+			content = append(existing, content...)
+		case errors.Is(err, os.ErrNotExist):
+			content = bytes.TrimLeft(content, "\n")
+		default:
+			return err
+		}
+	}
+
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	fmt.Printf("Created %s\n", path)
+	return nil
+}
+
+// defaultTitle derives an API title from the name of dir, e.g. "Billing API"
+// for ./billing.
+func defaultTitle(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "My API"
+	}
+	name := strings.FieldsFunc(filepath.Base(abs), func(r rune) bool {
+		return r == '-' || r == '_' || r == '.' || r == ' '
+	})
+	if len(name) == 0 || filepath.Base(abs) == string(filepath.Separator) {
+		return "My API"
+	}
+	for i, word := range name {
+		name[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(name, " ") + " API"
+}
+
+func (c *CLI) InitHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Scaffold a project documented with YaSwag annotations.\n\n")
+	help.WriteString("Writes main.go with the API-level annotations, an example model and\n")
+	help.WriteString("operation, a .yaswag.yaml config, and an openapi Makefile target that\n")
+	help.WriteString("generates and validates the spec. The target is appended to an existing\n")
+	help.WriteString("Makefile; main.go and .yaswag.yaml are only overwritten with --force.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag init [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --dir <path>      Directory to scaffold the project in (default: .)\n")
+	help.WriteString("  --title <text>    API title (default: derived from the directory name)\n")
+	help.WriteString("  --output <path>   Spec path to generate to (default: openapi.yaml)\n")
+	help.WriteString("  --force           Overwrite existing main.go and .yaswag.yaml\n")
+	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag init\n")
+	help.WriteString("  yaswag init --dir ./billing --title \"Billing API\"\n")
+	return help.String()
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+func (c *CLI) runInline(args []string) error {
+	fs := flag.NewFlagSet("inline", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path or - for stdin")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	format := fs.String("format", "", "Output format (json or yaml, auto-detected from extension if not specified)")
+	pretty := fs.Int("pretty", 2, "Indentation spaces for pretty printing")
+	keepCircular := fs.Bool("keep-circular-refs", false, "Leave the $refs of recursive schemas in place instead of failing")
+	showHelp := fs.Bool("help", false, "Show help for inline command")
+
+	if err := parseWithSpecArg(fs, args, input); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.InlineHelp())
+		return nil
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("one specification required: yaswag inline <spec> [options]")
+	}
+
+	result, err := readFromStdinOrFile(*input, true)
+	if err != nil {
+		return err
+	}
+	doc, err := parseDocument(result.data)
+	if err != nil {
+		return err
+	}
+
+	inlined, err := openapi.Inline(doc, openapi.InlineOptions{KeepCircularRefs: *keepCircular})
+	if err != nil {
+		return fmt.Errorf("failed to inline spec: %w (use --keep-circular-refs to leave recursive $refs in place)", err)
+	}
+
+	outputFormat := c.determineOutputFormat(*format, *outputPath, *input, result.fromStdin)
+	data, err := c.formatOutput(inlined, string(outputFormat), *pretty)
+	if err != nil {
+		return err
+	}
+	return c.writeOutput(*outputPath, data, "Inlined specification")
+}
+
+func (c *CLI) InlineHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Inline the component schemas of an OpenAPI specification.\n\n")
+	help.WriteString("Replaces the $refs to component schemas in paths, webhooks, and the other\n")
+	help.WriteString("components with copies of the schemas, for tools that do not follow $refs.\n")
+	help.WriteString("A recursive schema, such as a category with child categories, is reported\n")
+	help.WriteString("with its chain of $refs; --keep-circular-refs leaves the $ref that closes the\n")
+	help.WriteString("cycle in place instead. The component schemas are kept, pipe the result\n")
+	help.WriteString("through yaswag prune to drop those no longer used.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag inline <spec> [options]\n")
+	help.WriteString("  <command> | yaswag inline [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>          Input file path or - for stdin, instead of <spec>\n")
+	help.WriteString("  --output <path>         Output file path (empty for stdout)\n")
+	help.WriteString("  --format <type>         Output format: json or yaml (auto-detected if not specified)\n")
+	help.WriteString("  --pretty <n>            Indentation spaces (default: 2)\n")
+	help.WriteString("  --keep-circular-refs    Leave the $refs of recursive schemas in place\n")
+	help.WriteString("  --help                  Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag inline ./swagger.yaml --output ./inlined.yaml\n")
+	help.WriteString("  yaswag inline ./swagger.yaml --keep-circular-refs | yaswag prune\n")
+	help.WriteString("  yaswag generate --source ./api | yaswag inline --keep-circular-refs\n")
+	return help.String()
+}
@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/lint"
+)
+
+func (c *CLI) runLint(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path, URL, or - for stdin")
+	format := fs.String("format", "text", "Output format: text, json, or pr-comment (default: text)")
+	failOn := fs.String("fail-on", "error", "Minimum severity that fails the lint: error, warning, info, or none")
+	configPath := fs.String("config", "", "Lint config file extending a preset with rule settings")
+	preset := fs.String("preset", "", "Rule preset: "+strings.Join(lint.PresetNames(), ", "))
+	severities := fs.String("severity", "", "Comma-separated rule severity overrides, e.g. PATH_CASING=error,DESCRIPTION_MISSING=off")
+	ignore := fs.String("ignore", "", "Comma-separated rule IDs to skip")
+	ignorePaths := fs.String("ignore-paths", "", "Comma-separated API path patterns to skip, e.g. /internal/*")
+	maxFindings := fs.Int("max-findings", 10, "Findings listed in pr-comment output")
+	artifactURL := fs.String("artifact-url", "", "Link to the full report in pr-comment output")
+	showHelp := fs.Bool("help", false, "Show help for lint command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.LintHelp())
+		return nil
+	}
+
+	projectConfig, err := loadProjectConfig()
+	if err != nil {
+		return err
+	}
+	if err := applyConfig(fs, projectConfig, projectConfig.lintFlags()); err != nil {
+		return err
+	}
+
+	threshold, err := parseLintFailOn(*failOn)
+	if err != nil {
+		return err
+	}
+
+	config, err := buildLintConfig(lintConfigFlags{
+		configPath:  *configPath,
+		preset:      *preset,
+		severities:  *severities,
+		ignore:      *ignore,
+		ignorePaths: *ignorePaths,
+	})
+	if err != nil {
+		return err
+	}
+
+	result, err := c.lintInput(lint.New(config), *input)
+	if err != nil {
+		return err
+	}
+
+	prOpts := lint.PRCommentOptions{MaxFindings: *maxFindings, ArtifactURL: *artifactURL}
+	if err := outputLintResult(result, *format, prOpts); err != nil {
+		return err
+	}
+
+	// Exit with non-zero if there are findings at or above the threshold
+	if lintFails(result, threshold) {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// lintFails reports whether the result has findings at or above the
+// threshold, if there is one.
+func lintFails(result *lint.Result, threshold lint.Severity) bool {
+	return threshold != "" && result.HasFindingsAtOrAbove(threshold)
+}
+
+func outputLintResult(result *lint.Result, format string, prOpts lint.PRCommentOptions) error {
+	switch strings.ToLower(format) {
+	case "json":
+		data, err := lint.FormatJSON(result)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "pr-comment":
+		fmt.Print(lint.FormatPRComment(result, prOpts))
+	default:
+		fmt.Print(lint.FormatText(result))
+	}
+	return nil
+}
+
+func (c *CLI) lintInput(linter *lint.Linter, input string) (*lint.Result, error) {
+	if isURL(input) {
+		return linter.LintURL(input)
+	}
+
+	stdinRes, err := readFromStdinOrFile(input, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if stdinRes.fromStdin {
+		return linter.LintData(stdinRes.data)
+	}
+	return linter.LintFile(input)
+}
+
+// parseLintFailOn parses the --fail-on flag; "none" disables failing and returns an empty severity.
+func parseLintFailOn(value string) (lint.Severity, error) {
+	if strings.EqualFold(value, "none") {
+		return "", nil
+	}
+	severity, err := lint.ParseSeverity(value)
+	if err != nil || severity == lint.SeverityOff {
+		return "", fmt.Errorf("invalid --fail-on value %q: must be error, warning, info, or none", value)
+	}
+	return severity, nil
+}
+
+// lintConfigFlags holds the CLI flags that configure the lint rules.
+type lintConfigFlags struct {
+	configPath  string
+	preset      string
+	severities  string
+	ignore      string
+	ignorePaths string
+}
+
+// buildLintConfig builds a lint config from the config file, if any, and the
+// comma-separated CLI flag values, which override the file's preset and
+// severities and add to its ignore lists.
+func buildLintConfig(flags lintConfigFlags) (*lint.Config, error) {
+	config := &lint.Config{Severities: make(map[string]lint.Severity)}
+	if flags.configPath != "" {
+		loaded, err := lint.LoadConfig(flags.configPath)
+		if err != nil {
+			return nil, err
+		}
+		config = loaded
+	}
+	if flags.preset != "" {
+		if _, err := lint.LookupPreset(flags.preset); err != nil {
+			return nil, fmt.Errorf("invalid --preset: %w", err)
+		}
+		config.Preset = flags.preset
+	}
+	config.IgnoreRules = append(config.IgnoreRules, splitList(flags.ignore)...)
+	config.IgnorePaths = append(config.IgnorePaths, splitList(flags.ignorePaths)...)
+
+	for _, entry := range splitList(flags.severities) {
+		ruleID, level, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --severity entry %q: expected RULE=level", entry)
+		}
+		severity, err := lint.ParseSeverity(level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --severity entry %q: %w", entry, err)
+		}
+		config.Severities[strings.TrimSpace(ruleID)] = severity
+	}
+	return config, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (c *CLI) LintHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Lint an OpenAPI specification against API style rules.\n\n")
+	help.WriteString("Checks design conventions (use 'yaswag audit' for security issues):\n")
+	help.WriteString("  - OPERATION_ID_NAMING    operationId missing or not camelCase (WARNING)\n")
+	help.WriteString("  - DESCRIPTION_MISSING    operations and schemas without descriptions (INFO)\n")
+	help.WriteString("  - UNUSED_COMPONENT       components never referenced (WARNING)\n")
+	help.WriteString("  - DUPLICATE_TAG          tags declared or listed more than once (WARNING)\n")
+	help.WriteString("  - MISSING_4XX_RESPONSE   operations without 4xx or default responses (WARNING)\n")
+	help.WriteString("  - PATH_CASING            path segments not lowercase kebab-case (WARNING)\n")
+	help.WriteString("  - OWNER_MISSING          operations without an owning team (OFF)\n\n")
+	help.WriteString("Presets (--preset) set every rule's severity after an API style guide:\n")
+	for _, preset := range lint.Presets() {
+		fmt.Fprintf(&help, "  - %-12s %s\n", preset.Name, preset.Description)
+	}
+	help.WriteString("\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag lint [options]\n")
+	help.WriteString("  <command> | yaswag lint\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>         Input file path, URL, or - for stdin\n")
+	help.WriteString("  --format <type>        Output format: text, json, or pr-comment (default: text)\n")
+	help.WriteString("  --fail-on <sev>        Fail on findings at or above severity: error, warning,\n")
+	help.WriteString("                         info, or none (default: error)\n")
+	help.WriteString("  --config <path>        Lint config file (YAML) with preset, severities, ignore,\n")
+	help.WriteString("                         and ignorePaths; flags override it\n")
+	help.WriteString("  --preset <name>        Rule preset; --severity overrides its severities\n")
+	help.WriteString("  --severity <list>      Rule severity overrides: RULE=error|warning|info|off\n")
+	help.WriteString("  --ignore <list>        Rule IDs to skip\n")
+	help.WriteString("  --ignore-paths <list>  API path patterns to skip (a trailing /* matches nested paths)\n")
+	help.WriteString("  --max-findings <n>     Findings listed in pr-comment output (default: 10)\n")
+	help.WriteString("  --artifact-url <url>   Link to the full report in pr-comment output\n")
+	help.WriteString("  --help                 Show this help message\n\n")
+	help.WriteString("Options not given default to the lint section of .yaswag.yaml, if any.\n\n")
+	help.WriteString("Exit Codes:\n")
+	help.WriteString("  0    No issues found at or above the --fail-on severity\n")
+	help.WriteString("  1    Issues found at or above the --fail-on severity\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag lint --input ./swagger.yaml\n")
+	help.WriteString("  yaswag lint --input ./swagger.yaml --format json --fail-on warning\n")
+	help.WriteString("  yaswag lint --input ./swagger.yaml --severity PATH_CASING=error,DESCRIPTION_MISSING=off\n")
+	help.WriteString("  yaswag lint --input ./swagger.yaml --preset zalando --severity OWNER_MISSING=error\n")
+	help.WriteString("  yaswag lint --input ./swagger.yaml --config .yaswag-lint.yaml\n")
+	help.WriteString("  yaswag lint --input ./swagger.yaml --ignore UNUSED_COMPONENT --ignore-paths '/internal/*'\n")
+	help.WriteString("  yaswag lint --input ./swagger.yaml --format pr-comment --artifact-url \"$REPORT_URL\"\n")
+	help.WriteString("  yaswag generate --source ./api | yaswag lint\n")
+	return help.String()
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// listener is the listening configuration of the swaggerui servers.
+type listener interface {
+	SetHost(host string)
+	SetTLS(certFile, keyFile string)
+	ServeContext(ctx context.Context) error
+}
+
+// listenFlags registers the host and TLS flags on fs and returns a function
+// that applies them to a server.
+func listenFlags(fs *flag.FlagSet) func(listener) error {
+	host := fs.String("host", "", "Interface to bind, e.g. 127.0.0.1 (default: all interfaces)")
+	certFile := fs.String("tls-cert", "", "TLS certificate file, to serve HTTPS with --tls-key")
+	keyFile := fs.String("tls-key", "", "TLS key file, to serve HTTPS with --tls-cert")
+	return func(l listener) error {
+		if (*certFile == "") != (*keyFile == "") {
+			return fmt.Errorf("--tls-cert and --tls-key must be used together")
+		}
+		l.SetHost(*host)
+		l.SetTLS(*certFile, *keyFile)
+		return nil
+	}
+}
+
+// listenHelp returns the help lines of the listen flags.
+func listenHelp() string {
+	help := strings.Builder{}
+	help.WriteString("  --host <addr>           Interface to bind, e.g. 127.0.0.1 (default: all interfaces)\n")
+	help.WriteString("  --tls-cert <file>       TLS certificate file, to serve HTTPS with --tls-key\n")
+	help.WriteString("  --tls-key <file>        TLS key file, to serve HTTPS with --tls-cert\n")
+	return help.String()
+}
+
+// serveUntilSignal serves until an interrupt or termination signal, then
+// shuts the server down gracefully.
+func serveUntilSignal(l listener) error {
+	ctx, stop := signalContext()
+	defer stop()
+	return l.ServeContext(ctx)
+}
+
+// signalContext returns a context that is done on an interrupt or
+// termination signal.
+func signalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
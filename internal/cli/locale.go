@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// generateLocales generates a spec for each locale from the annotations
+// localized for it, written next to out.outputPath with the locale inserted
+// before the extension (openapi.fr.yaml for openapi.yaml).
+func (c *CLI) generateLocales(source string, locales []string, opts generateOptions, out specOutput) error {
+	if out.outputPath == "" {
+		return fmt.Errorf("--locale with several locales requires --output")
+	}
+	for i, locale := range locales {
+		opts.parser.Locale = locale
+		opts.diagnosed = i > 0
+		doc, err := c.parseAndGenerate(source, opts)
+		if err != nil {
+			return fmt.Errorf("locale %s: %w", locale, err)
+		}
+
+		localeOut := out
+		localeOut.outputPath = localePath(out.outputPath, locale)
+		if err := c.writeSpec(doc, localeOut, fmt.Sprintf("OpenAPI specification (%s)", locale)); err != nil {
+			return fmt.Errorf("locale %s: %w", locale, err)
+		}
+	}
+	return nil
+}
+
+// localePath returns outputPath with the locale inserted before its
+// extension.
+func localePath(outputPath, locale string) string {
+	ext := filepath.Ext(outputPath)
+	return strings.TrimSuffix(outputPath, ext) + "." + unsafeFileChars.ReplaceAllString(locale, "-") + ext
+}
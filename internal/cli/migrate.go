@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/parser"
+)
+
+func (c *CLI) runMigrateSwag(args []string) error {
+	fs := flag.NewFlagSet("migrate-swag", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "Report what would be converted without rewriting files")
+	showHelp := fs.Bool("help", false, "Show help for migrate-swag command")
+
+	patterns, err := parseInterleaved(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.MigrateSwagHelp())
+		return nil
+	}
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	files, err := parser.SourceFiles(patterns)
+	if err != nil {
+		return err
+	}
+	migrations, err := parser.MigrateSwag(files)
+	if err != nil {
+		return err
+	}
+
+	converted, changed, err := applyMigrations(migrations, *dryRun)
+	if err != nil {
+		return err
+	}
+	if changed == 0 {
+		fmt.Println("No swag annotations found")
+	} else {
+		fmt.Printf("\n%d annotations in %d files\n", converted, changed)
+	}
+	printMigrationIssues(migrations)
+	if changed > 0 && !*dryRun {
+		fmt.Println("\nRemove the generated swag docs package and its import, then run 'yaswag generate' to generate the specification from the annotations.")
+	}
+	return nil
+}
+
+// parseInterleaved parses args with fs, accepting options after the
+// positional arguments too, as in yaswag migrate-swag ./... --dry-run, and
+// returns the positional arguments.
+func parseInterleaved(fs *flag.FlagSet, args []string) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	var positional []string
+	for fs.NArg() > 0 {
+		positional = append(positional, fs.Arg(0))
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return nil, err
+		}
+	}
+	return positional, nil
+}
+
+// applyMigrations writes the changed files of migrations, unless dryRun,
+// and returns the number of annotations converted and files changed.
+func applyMigrations(migrations []*parser.SwagMigration, dryRun bool) (converted, changed int, err error) {
+	verb := "Migrated"
+	if dryRun {
+		verb = "Would migrate"
+	}
+	for _, mig := range migrations {
+		if !mig.Changed {
+			continue
+		}
+		if !dryRun {
+			if err := writeMigration(mig); err != nil {
+				return 0, 0, err
+			}
+		}
+		fmt.Printf("%s %s (%d annotations)\n", verb, mig.Path, mig.Converted)
+		converted += mig.Converted
+		changed++
+	}
+	return converted, changed, nil
+}
+
+// writeMigration rewrites the file of mig, keeping its permissions.
+func writeMigration(mig *parser.SwagMigration) error {
+	info, err := os.Stat(mig.Path)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(mig.Path, mig.Content, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", mig.Path, err)
+	}
+	return nil
+}
+
+// printMigrationIssues lists what the migrations could not convert.
+func printMigrationIssues(migrations []*parser.SwagMigration) {
+	var issues []string
+	for _, mig := range migrations {
+		for _, issue := range mig.Issues {
+			issues = append(issues, fmt.Sprintf("%s:%d: %s: %s", mig.Path, issue.Line, issue.Text, issue.Reason))
+		}
+	}
+	if len(issues) == 0 {
+		return
+	}
+	fmt.Printf("\nNot converted (%d):\n", len(issues))
+	for _, issue := range issues {
+		fmt.Printf("  %s\n", issue)
+	}
+}
+
+func (c *CLI) MigrateSwagHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Rewrite swaggo/swag comment annotations as YaSwag annotations.\n\n")
+	help.WriteString("Eases switching from swag. In the Go files of the packages:\n")
+	help.WriteString("  - the general API info (@title, @version, @host, @BasePath,\n")
+	help.WriteString("    @securityDefinitions, ...) becomes !api, !info, !server, and !security\n")
+	help.WriteString("  - the annotations of each handler (@Summary, @Tags, @Param, @Success,\n")
+	help.WriteString("    @Failure, @Security, @Router, ...) become its route, !query, !body,\n")
+	help.WriteString("    !ok, !error, and !secure annotations\n")
+	help.WriteString("  - the structs the handlers use get !model, and the example, format, and\n")
+	help.WriteString("    binding:\"required\" struct tags of their fields become !field lines\n\n")
+	help.WriteString("Other comments are left as they are. What YaSwag cannot express, such as\n")
+	help.WriteString("enums, validation attributes, and operation descriptions, is listed as not\n")
+	help.WriteString("converted with its file and line. Test files and vendor directories are\n")
+	help.WriteString("skipped. Once migrated, remove the docs package swag generated.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag migrate-swag [packages] [options]\n\n")
+	help.WriteString("Packages are directories, dir/... for a directory and its subdirectories,\n")
+	help.WriteString("or files (default: ./...).\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --dry-run  Report what would be converted without rewriting files\n")
+	help.WriteString("  --help     Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag migrate-swag ./...\n")
+	help.WriteString("  yaswag migrate-swag ./cmd/api ./internal/handler/... --dry-run\n")
+	return help.String()
+}
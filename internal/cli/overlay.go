@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/overlay"
+)
+
+func (c *CLI) runOverlay(args []string) error {
+	fs := flag.NewFlagSet("overlay", flag.ExitOnError)
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	format := fs.String("format", "", "Output format (json or yaml, auto-detected from extension if not specified)")
+	pretty := fs.Int("pretty", 2, "Indentation spaces for pretty printing")
+	strict := fs.Bool("strict", false, "Fail if an overlay action matches nothing")
+	showHelp := fs.Bool("help", false, "Show help for overlay command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.OverlayHelp())
+		return nil
+	}
+
+	paths := fs.Args()
+	if len(paths) < 2 {
+		return fmt.Errorf("specification and overlay required: yaswag overlay <spec> <overlay> [overlay...]")
+	}
+
+	result, err := readFromStdinOrFile(paths[0], true)
+	if err != nil {
+		return err
+	}
+
+	spec := result.data
+	for _, path := range paths[1:] {
+		spec, err = applyOverlayFile(spec, path, *strict)
+		if err != nil {
+			return err
+		}
+	}
+
+	doc, err := parseDocument(spec)
+	if err != nil {
+		return err
+	}
+
+	outputFormat := c.determineOutputFormat(*format, *outputPath, paths[0], result.fromStdin)
+	data, err := c.formatOutput(doc, string(outputFormat), *pretty)
+	if err != nil {
+		return err
+	}
+
+	return c.writeOutput(*outputPath, data, "Patched specification")
+}
+
+// applyOverlayFile applies the overlay or merge patch at path to spec,
+// warning about, or with strict failing on, actions that match nothing.
+func applyOverlayFile(spec []byte, path string, strict bool) ([]byte, error) {
+	patch, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overlay file: %w", err)
+	}
+	result, err := overlay.Apply(spec, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply %s: %w", path, err)
+	}
+	if len(result.Unmatched) > 0 && strict {
+		return nil, fmt.Errorf("%s: targets matched nothing: %s", path, strings.Join(result.Unmatched, ", "))
+	}
+	for _, target := range result.Unmatched {
+		fmt.Fprintf(os.Stderr, "Warning: %s: target %s matched nothing\n", path, target)
+	}
+	return result.Spec, nil
+}
+
+func (c *CLI) OverlayHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Customize an OpenAPI specification without editing the annotations.\n\n")
+	help.WriteString("Applies each overlay in turn. An overlay is an OpenAPI Overlay 1.0 document,\n")
+	help.WriteString("whose actions update or remove the nodes their JSONPath targets select, or\n")
+	help.WriteString("else a JSON Merge Patch (RFC 7396), where null removes a field. Both may be\n")
+	help.WriteString("JSON or YAML. Use - as the spec to read it from stdin.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag overlay [options] <spec> <overlay> [overlay...]\n")
+	help.WriteString("  <command> | yaswag overlay [options] - <overlay> [overlay...]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --output <path>   Output file path (empty for stdout)\n")
+	help.WriteString("  --format <type>   Output format: json or yaml (auto-detected if not specified)\n")
+	help.WriteString("  --pretty <n>      Indentation spaces (default: 2)\n")
+	help.WriteString("  --strict          Fail if an overlay action matches nothing\n")
+	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Example overlay:\n")
+	help.WriteString("  overlay: 1.0.0\n")
+	help.WriteString("  info:\n")
+	help.WriteString("    title: Public servers\n")
+	help.WriteString("    version: 1.0.0\n")
+	help.WriteString("  actions:\n")
+	help.WriteString("    - target: $.servers\n")
+	help.WriteString("      update:\n")
+	help.WriteString("        - url: https://api.example.com\n")
+	help.WriteString("    - target: $.paths['/pets'].get\n")
+	help.WriteString("      update:\n")
+	help.WriteString("        description: Lists the pets in the store.\n")
+	help.WriteString("        x-rate-limit: 100\n")
+	help.WriteString("    - target: $.paths[*][*][?@.tags[0] == 'internal']\n")
+	help.WriteString("      remove: true\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag overlay --output ./public.yaml ./swagger.yaml ./public.overlay.yaml\n")
+	help.WriteString("  yaswag generate --source ./api | yaswag overlay - ./servers.yaml ./descriptions.yaml\n")
+	return help.String()
+}
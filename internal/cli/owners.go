@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// ownersReport groups the operations of a spec by the team that owns them.
+type ownersReport struct {
+	Owners  []ownerGroup     `json:"owners"`
+	Unowned []ownedOperation `json:"unowned"`
+}
+
+type ownerGroup struct {
+	openapi.Owner
+	Operations []ownedOperation `json:"operations"`
+}
+
+type ownedOperation struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	OperationID string `json:"operationId,omitempty"`
+}
+
+func (o ownedOperation) String() string {
+	s := o.Method + " " + o.Path
+	if o.OperationID != "" {
+		s += " (" + o.OperationID + ")"
+	}
+	return s
+}
+
+func (c *CLI) runOwners(args []string) error {
+	fs := flag.NewFlagSet("owners", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path or - for stdin")
+	format := fs.String("format", "text", "Output format: text or json (default: text)")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	showHelp := fs.Bool("help", false, "Show help for owners command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.OwnersHelp())
+		return nil
+	}
+
+	result, err := readFromStdinOrFile(*input, true)
+	if err != nil {
+		return err
+	}
+
+	doc, err := parseDocument(result.data)
+	if err != nil {
+		return err
+	}
+
+	report := buildOwnersReport(doc)
+	var data []byte
+	switch strings.ToLower(*format) {
+	case "json":
+		data, err = json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		data = append(data, '\n')
+	case "text":
+		data = []byte(formatOwnersReport(report))
+	default:
+		return fmt.Errorf("unsupported owners format: %s (supported: text, json)", *format)
+	}
+	return c.writeOutput(*outputPath, data, "Owners report")
+}
+
+// buildOwnersReport assigns every operation to its owner, resolved from the
+// operation's x-owner or the x-owner of its tags. Teams are sorted by name.
+func buildOwnersReport(doc *openapi.Document) *ownersReport {
+	report := &ownersReport{Owners: []ownerGroup{}, Unowned: []ownedOperation{}}
+	groups := make(map[string]int)
+
+	eachOperation(doc, func(method, path string, op *openapi.Operation) {
+		entry := ownedOperation{Method: method, Path: path, OperationID: op.OperationID}
+		report.add(groups, doc.OwnerOf(op), entry)
+	})
+
+	slices.SortFunc(report.Owners, func(a, b ownerGroup) int {
+		return strings.Compare(a.Team, b.Team)
+	})
+	return report
+}
+
+// add files op under owner, creating its group on first use; groups maps teams to their index.
+func (r *ownersReport) add(groups map[string]int, owner *openapi.Owner, op ownedOperation) {
+	if owner == nil {
+		r.Unowned = append(r.Unowned, op)
+		return
+	}
+	i, ok := groups[owner.Team]
+	if !ok {
+		i = len(r.Owners)
+		groups[owner.Team] = i
+		r.Owners = append(r.Owners, ownerGroup{Owner: *owner})
+	}
+	r.Owners[i].Operations = append(r.Owners[i].Operations, op)
+}
+
+// eachOperation calls fn for every operation of doc, sorted by path.
+func eachOperation(doc *openapi.Document, fn func(method, path string, op *openapi.Operation)) {
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		item := doc.Paths[path]
+		if item == nil {
+			continue
+		}
+		for _, entry := range []struct {
+			method string
+			op     *openapi.Operation
+		}{
+			{"GET", item.Get}, {"PUT", item.Put}, {"POST", item.Post}, {"DELETE", item.Delete},
+			{"OPTIONS", item.Options}, {"HEAD", item.Head}, {"PATCH", item.Patch}, {"TRACE", item.Trace},
+		} {
+			if entry.op != nil {
+				fn(entry.method, path, entry.op)
+			}
+		}
+	}
+}
+
+func formatOwnersReport(report *ownersReport) string {
+	var sb strings.Builder
+	owned := 0
+	for _, group := range report.Owners {
+		owned += len(group.Operations)
+	}
+	fmt.Fprintf(&sb, "Owned operations: %d/%d (%d teams)\n", owned, owned+len(report.Unowned), len(report.Owners))
+
+	for _, group := range report.Owners {
+		sb.WriteString("\n" + group.Team)
+		var contacts []string
+		if group.Slack != "" {
+			contacts = append(contacts, "slack: "+group.Slack)
+		}
+		if group.Email != "" {
+			contacts = append(contacts, "email: "+group.Email)
+		}
+		if len(contacts) > 0 {
+			sb.WriteString(" (" + strings.Join(contacts, ", ") + ")")
+		}
+		sb.WriteString("\n")
+		for _, op := range group.Operations {
+			fmt.Fprintf(&sb, "  %s\n", op)
+		}
+	}
+
+	if len(report.Unowned) > 0 {
+		sb.WriteString("\nUnowned:\n")
+		for _, op := range report.Unowned {
+			fmt.Fprintf(&sb, "  %s\n", op)
+		}
+	}
+	return sb.String()
+}
+
+func (c *CLI) OwnersHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Report which team owns each operation of an OpenAPI specification.\n\n")
+	help.WriteString("Owners come from the x-owner extension of an operation or, failing that,\n")
+	help.WriteString("of the first of its tags that declares one (set with the !owner annotation).\n")
+	help.WriteString("To fail CI on unowned operations, run: yaswag lint --severity OWNER_MISSING=error\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag owners [options]\n")
+	help.WriteString("  <command> | yaswag owners [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>    Input file path or - for stdin\n")
+	help.WriteString("  --format <type>   Output format: text or json (default: text)\n")
+	help.WriteString("  --output <path>   Output file path (empty for stdout)\n")
+	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag owners --input ./swagger.yaml\n")
+	help.WriteString("  yaswag owners --input ./swagger.yaml --format json --output owners.json\n")
+	help.WriteString("  yaswag generate --source ./api | yaswag owners\n")
+	return help.String()
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+func (c *CLI) runPrune(args []string) error {
+	fs := flag.NewFlagSet("prune", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path or - for stdin")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	format := fs.String("format", "", "Output format (json or yaml, auto-detected from extension if not specified)")
+	pretty := fs.Int("pretty", 2, "Indentation spaces for pretty printing")
+	dryRun := fs.Bool("dry-run", false, "List the unused components without writing the pruned specification")
+	showHelp := fs.Bool("help", false, "Show help for prune command")
+
+	if err := parseWithSpecArg(fs, args, input); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.PruneHelp())
+		return nil
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("one specification required: yaswag prune <spec> [options]")
+	}
+
+	result, err := readFromStdinOrFile(*input, true)
+	if err != nil {
+		return err
+	}
+	doc, err := parseDocument(result.data)
+	if err != nil {
+		return err
+	}
+
+	pruned, removed, err := openapi.Prune(doc)
+	if err != nil {
+		return fmt.Errorf("failed to prune spec: %w", err)
+	}
+	if *dryRun {
+		printRemoved(os.Stdout, removed, "Unused")
+		return nil
+	}
+
+	outputFormat := c.determineOutputFormat(*format, *outputPath, *input, result.fromStdin)
+	data, err := c.formatOutput(pruned, string(outputFormat), *pretty)
+	if err != nil {
+		return err
+	}
+	// the pruned spec may go to stdout, so report on stderr
+	printRemoved(os.Stderr, removed, "Removed")
+	return c.writeOutput(*outputPath, data, "Pruned specification")
+}
+
+// printRemoved lists the $refs of the unused components.
+func printRemoved(w io.Writer, refs []string, verb string) {
+	fmt.Fprintf(w, "%s components: %d\n", verb, len(refs))
+	for _, ref := range refs {
+		fmt.Fprintf(w, "  %s\n", ref)
+	}
+}
+
+func (c *CLI) PruneHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Remove the unused components of an OpenAPI specification.\n\n")
+	help.WriteString("Drops the schemas, responses, parameters, and other components that no path,\n")
+	help.WriteString("webhook, or security requirement uses, directly or through other components,\n")
+	help.WriteString("as generated specs accumulate dead schemas while the code evolves. The\n")
+	help.WriteString("removed components are listed on stderr. The lint rule UNUSED_COMPONENT\n")
+	help.WriteString("reports them without changing the spec.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag prune <spec> [options]\n")
+	help.WriteString("  <command> | yaswag prune [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>    Input file path or - for stdin, instead of <spec>\n")
+	help.WriteString("  --output <path>   Output file path (empty for stdout)\n")
+	help.WriteString("  --format <type>   Output format: json or yaml (auto-detected if not specified)\n")
+	help.WriteString("  --pretty <n>      Indentation spaces (default: 2)\n")
+	help.WriteString("  --dry-run         List the unused components on stdout instead\n")
+	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag prune ./swagger.yaml --dry-run\n")
+	help.WriteString("  yaswag prune ./swagger.yaml --output ./swagger.yaml\n")
+	help.WriteString("  yaswag generate --source ./api | yaswag prune --output ./openapi.yaml\n")
+	return help.String()
+}
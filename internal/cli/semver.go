@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/diff"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+func (c *CLI) runSemver(args []string) error {
+	fs := flag.NewFlagSet("semver", flag.ExitOnError)
+	basePath := fs.String("base", "", "Previous specification file")
+	input := fs.String("input", "", "New specification file or - for stdin")
+	format := fs.String("format", "text", "Output format: text, json, or pr-comment (default: text)")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	maxFindings := fs.Int("max-findings", 10, "Changes listed in pr-comment output")
+	artifactURL := fs.String("artifact-url", "", "Link to the full report in pr-comment output")
+	check := fs.Bool("check", false, "Exit with status 1 if info.version does not change by the recommended bump")
+	mode := fs.String("mode", "", "Exit with status 1 if a change breaks this compatibility: backward, forward, or full")
+	showHelp := fs.Bool("help", false, "Show help for semver command")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.SemverHelp())
+		return nil
+	}
+
+	var compat diff.Mode
+	if *mode != "" {
+		if compat, err = diff.ParseMode(*mode); err != nil {
+			return err
+		}
+	}
+
+	base, head, err := readSemverDocuments(*basePath, *input)
+	if err != nil {
+		return err
+	}
+	result := diff.Compare(base, head)
+
+	prOpts := diff.PRCommentOptions{MaxFindings: *maxFindings, ArtifactURL: *artifactURL}
+	data, err := formatSemverResult(result, *format, prOpts)
+	if err != nil {
+		return err
+	}
+	if err := c.writeOutput(*outputPath, data, "Semantic versioning report"); err != nil {
+		return err
+	}
+
+	enforceSemver(result, *check, compat)
+	return nil
+}
+
+// enforceSemver exits with status 1 if, with check, info.version does not
+// change by the recommended bump, or if a change breaks compat.
+func enforceSemver(result *diff.Result, check bool, compat diff.Mode) {
+	if check {
+		if err := diff.CheckVersion(result); err != nil {
+			fmt.Fprintf(os.Stderr, "Version check failed: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if compat != "" {
+		if violations := result.Violations(compat); len(violations) > 0 {
+			fmt.Fprintf(os.Stderr, "Compatibility check failed: %d changes are not %s compatible\n", len(violations), compat)
+			os.Exit(1)
+		}
+	}
+}
+
+// readSemverDocuments reads the previous specification from basePath and
+// the new one from input or stdin.
+func readSemverDocuments(basePath, input string) (*openapi.Document, *openapi.Document, error) {
+	if basePath == "" {
+		return nil, nil, fmt.Errorf("--base is required")
+	}
+	baseData, err := os.ReadFile(basePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read base file: %w", err)
+	}
+	base, err := parseDocument(baseData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("base: %w", err)
+	}
+
+	headInput, err := readFromStdinOrFile(input, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	head, err := parseDocument(headInput.data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return base, head, nil
+}
+
+func formatSemverResult(result *diff.Result, format string, prOpts diff.PRCommentOptions) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		data, err := diff.FormatJSON(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format JSON: %w", err)
+		}
+		return append(data, '\n'), nil
+	case "text":
+		return []byte(diff.FormatText(result)), nil
+	case "pr-comment":
+		return []byte(diff.FormatPRComment(result, prOpts)), nil
+	}
+	return nil, fmt.Errorf("unsupported format: %s (supported: text, json, pr-comment)", format)
+}
+
+func (c *CLI) SemverHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Recommend a version bump from the changes between two specifications.\n\n")
+	help.WriteString("Compares the operations of the new specification with the previous one and\n")
+	help.WriteString("classifies each change:\n")
+	help.WriteString("  - BREAKING  removed operations, parameters, properties, media types, or\n")
+	help.WriteString("              success responses; new required inputs; changed types, formats,\n")
+	help.WriteString("              or operationIds; enums and bounds (minimum, maxLength, ...)\n")
+	help.WriteString("              narrowed in requests or widened in responses (major bump)\n")
+	help.WriteString("  - ADDITIVE  new operations, optional inputs, properties, responses, and\n")
+	help.WriteString("              media types; deprecations (minor bump)\n")
+	help.WriteString("  - PATCH     descriptions and anything else that changed (patch bump)\n\n")
+	help.WriteString("Each change also names the compatibility it breaks, as schema registries\n")
+	help.WriteString("define it: backward when existing clients may fail against the new version,\n")
+	help.WriteString("forward when new clients may fail against the previous one, such as new\n")
+	help.WriteString("operations or enum values widened in requests. A full policy needs both.\n\n")
+	help.WriteString("With --check, info.version of the new specification must be at least the\n")
+	help.WriteString("recommended bump from the previous one. Before 1.0.0, a minor bump covers\n")
+	help.WriteString("breaking changes and a patch bump additive ones.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag semver --base <path> [options]\n")
+	help.WriteString("  <command> | yaswag semver --base <path> [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --base <path>         Previous specification file (required)\n")
+	help.WriteString("  --input <path>        New specification file or - for stdin\n")
+	help.WriteString("  --format <type>       Output format: text, json, or pr-comment (default: text)\n")
+	help.WriteString("  --output <path>       Output file path (empty for stdout)\n")
+	help.WriteString("  --max-findings <n>    Changes listed in pr-comment output (default: 10)\n")
+	help.WriteString("  --artifact-url <url>  Link to the full report in pr-comment output\n")
+	help.WriteString("  --check               Fail unless info.version changes by the recommended bump\n")
+	help.WriteString("  --mode <mode>         Fail if a change breaks backward, forward, or full compatibility\n")
+	help.WriteString("  --help                Show this help message\n\n")
+	help.WriteString("Exit Codes:\n")
+	help.WriteString("  0    Report written, and with --check the version bump is sufficient\n")
+	help.WriteString("  1    With --check, the version bump is smaller than the changes need, or\n")
+	help.WriteString("       with --mode, a change breaks the compatibility policy\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag semver --base ./released.yaml --input ./openapi.yaml\n")
+	help.WriteString("  git show main:openapi.yaml > base.yaml && yaswag semver --base base.yaml --input openapi.yaml --check\n")
+	help.WriteString("  yaswag generate --source . | yaswag semver --base ./released.yaml --format json\n")
+	help.WriteString("  yaswag semver --base ./released.yaml --input ./openapi.yaml --mode full\n")
+	help.WriteString("  yaswag semver --base ./released.yaml --input ./openapi.yaml --format pr-comment --artifact-url \"$REPORT_URL\"\n")
+	return help.String()
+}
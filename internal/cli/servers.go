@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"gopkg.in/yaml.v3"
+)
+
+// serverFlag collects repeated --server values, each a URL optionally
+// followed by a description.
+type serverFlag []openapi.Server
+
+func (s *serverFlag) String() string {
+	urls := make([]string, len(*s))
+	for i, server := range *s {
+		urls[i] = server.URL
+	}
+	return strings.Join(urls, ",")
+}
+
+func (s *serverFlag) Set(value string) error {
+	url, description, _ := strings.Cut(strings.TrimSpace(value), " ")
+	if url == "" {
+		return fmt.Errorf("server URL is empty")
+	}
+	*s = append(*s, openapi.Server{URL: url, Description: strings.Trim(strings.TrimSpace(description), `"`)})
+	return nil
+}
+
+// loadServersFile reads servers from a YAML or JSON file holding either a
+// list of server objects or an object with a servers list, such as a spec.
+func loadServersFile(path string) ([]openapi.Server, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read servers file: %w", err)
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("failed to parse servers file %s: %w", path, err)
+	}
+	var servers []openapi.Server
+	if len(node.Content) > 0 && node.Content[0].Kind == yaml.MappingNode {
+		var file struct {
+			Servers []openapi.Server `yaml:"servers"`
+		}
+		err = node.Decode(&file)
+		servers = file.Servers
+	} else {
+		err = node.Decode(&servers)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse servers file %s: %w", path, err)
+	}
+
+	for i, server := range servers {
+		if server.URL == "" {
+			return nil, fmt.Errorf("servers file %s: server %d has no url", path, i+1)
+		}
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("servers file %s lists no servers", path)
+	}
+	return servers, nil
+}
+
+// injectedServers returns the servers replacing or appended to the annotated
+// ones: those of the environment env in the config, of the servers file, and
+// of the --server flags, in that order.
+func injectedServers(config *projectConfig, env, serversFile string, flagged serverFlag) ([]openapi.Server, error) {
+	var servers []openapi.Server
+	if env != "" {
+		envServers, err := config.environmentServers(env)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, envServers...)
+	}
+	if serversFile != "" {
+		fileServers, err := loadServersFile(serversFile)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, fileServers...)
+	}
+	return append(servers, flagged...), nil
+}
+
+// mergeServers returns the servers of a generated spec: injected in place of
+// the annotated servers, or after them when appending, skipping the URLs
+// already listed.
+func mergeServers(annotated, injected []openapi.Server, appendTo bool) []openapi.Server {
+	var merged []openapi.Server
+	if appendTo {
+		merged = append(merged, annotated...)
+	}
+	for _, server := range injected {
+		listed := false
+		for _, s := range merged {
+			listed = listed || s.URL == server.URL
+		}
+		if !listed {
+			merged = append(merged, server)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// unsafeFileChars are the characters of a tag replaced in file names.
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// writeSplitByTag writes doc as one file per tag, named after outputPath
+// with the tag appended (petstore_pet.yaml for petstore.yaml), and a root
+// document at outputPath whose paths reference the path items in them.
+// Each path item goes to the file of its primary tag, and each tag file is
+// a complete document with the components its operations use.
+func (c *CLI) writeSplitByTag(doc *openapi.Document, outputPath, format string, pretty int) error {
+	if outputPath == "" {
+		return fmt.Errorf("--split-by-tag requires --output")
+	}
+	dir := filepath.Dir(outputPath)
+	ext := filepath.Ext(outputPath)
+	base := strings.TrimSuffix(filepath.Base(outputPath), ext)
+
+	var tags []string
+	pathsByTag := make(map[string][]string)
+	for _, path := range pathOrder(doc) {
+		tag := primaryTag(doc.Paths[path])
+		if _, ok := pathsByTag[tag]; !ok {
+			tags = append(tags, tag)
+		}
+		pathsByTag[tag] = append(pathsByTag[tag], path)
+	}
+
+	root := *doc
+	root.Paths = make(openapi.Paths, len(doc.Paths))
+	files := tagFileNames(tags)
+	for _, tag := range tags {
+		part, err := openapi.Filter(doc, openapi.FilterOptions{Paths: pathsByTag[tag]})
+		if err != nil {
+			return fmt.Errorf("tag %s: %w", tag, err)
+		}
+		part.Webhooks = nil
+		data, err := c.formatOutput(part, format, pretty)
+		if err != nil {
+			return fmt.Errorf("tag %s: %w", tag, err)
+		}
+		file := base + "_" + files[tag] + ext
+		if err := c.writeOutput(filepath.Join(dir, file), data, fmt.Sprintf("Operations tagged %s", tag)); err != nil {
+			return err
+		}
+		for _, path := range pathsByTag[tag] {
+			root.Paths[path] = &openapi.PathItem{Ref: file + "#/paths/" + escapePointer(path)}
+		}
+	}
+
+	data, err := c.formatOutput(&root, format, pretty)
+	if err != nil {
+		return err
+	}
+	return c.writeOutput(outputPath, data, "Root OpenAPI specification")
+}
+
+// tagFileNames returns the file name part of each tag, with the characters
+// unsafe in file names replaced. Tags that would share a name, such as
+// "pet store" and "pet/store", or differ only in case, which some file
+// systems ignore, get a numeric suffix in order: pet-store and pet-store-2.
+func tagFileNames(tags []string) map[string]string {
+	names := make(map[string]string, len(tags))
+	taken := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		sanitized := unsafeFileChars.ReplaceAllString(tag, "-")
+		name := sanitized
+		for i := 2; taken[strings.ToLower(name)]; i++ {
+			name = fmt.Sprintf("%s-%d", sanitized, i)
+		}
+		taken[strings.ToLower(name)] = true
+		names[tag] = name
+	}
+	return names
+}
+
+// pathOrder returns the paths of doc in declaration order, followed by any
+// paths missing from doc.PathOrder in sorted order.
+func pathOrder(doc *openapi.Document) []string {
+	seen := make(map[string]bool, len(doc.Paths))
+	var paths []string
+	for _, path := range doc.PathOrder {
+		if doc.Paths[path] != nil && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	var rest []string
+	for path := range doc.Paths {
+		if !seen[path] {
+			rest = append(rest, path)
+		}
+	}
+	slices.Sort(rest)
+	return append(paths, rest...)
+}
+
+// primaryTag returns the first tag of the first operation of item, or
+// openapi.DefaultTag if that operation is untagged.
+func primaryTag(item *openapi.PathItem) string {
+	for _, op := range []*openapi.Operation{
+		item.Get, item.Put, item.Post, item.Delete,
+		item.Options, item.Head, item.Patch, item.Trace,
+	} {
+		if op == nil {
+			continue
+		}
+		if len(op.Tags) > 0 {
+			return op.Tags[0]
+		}
+		break
+	}
+	return openapi.DefaultTag
+}
+
+// escapePointer escapes a path as a JSON pointer token, e.g. ~1pets~1{id}.
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// buildStamp is the build metadata stamped into the info of a generated
+// spec, like the version, commit, and date ldflags of the yaswag binary.
+type buildStamp struct {
+	version string
+	commit  string
+	date    string
+}
+
+// resolve fills in the commit and date not given: the short HEAD commit of
+// the git repository containing dir, and the current time, or the time in
+// SOURCE_DATE_EPOCH for reproducible builds.
+func (s buildStamp) resolve(dir string) (buildStamp, error) {
+	if s.commit == "" {
+		out, err := exec.Command("git", "-C", dir, "rev-parse", "--short", "HEAD").Output()
+		if err != nil {
+			return s, fmt.Errorf("failed to read git commit of %s (use --stamp-commit): %w", dir, err)
+		}
+		s.commit = strings.TrimSpace(string(out))
+	}
+	if s.date == "" {
+		now := time.Now()
+		if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+			seconds, err := strconv.ParseInt(epoch, 10, 64)
+			if err != nil {
+				return s, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %w", epoch, err)
+			}
+			now = time.Unix(seconds, 0)
+		}
+		s.date = now.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	return s, nil
+}
+
+// requested returns the stamp resolved for dir if stamping is requested,
+// with --stamp or a flag setting part of the stamp, or else nil.
+func (s buildStamp) requested(stamp bool, dir string) (*buildStamp, error) {
+	if !stamp && s == (buildStamp{}) {
+		return nil, nil
+	}
+	resolved, err := s.resolve(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &resolved, nil
+}
+
+// apply sets the x-build extension of info to the stamp, and its version
+// to the stamped version if there is one.
+func (s buildStamp) apply(info *openapi.Info) {
+	build := map[string]string{"commit": s.commit, "date": s.date}
+	if s.version != "" {
+		info.Version = s.version
+		build["version"] = s.version
+	}
+	if info.Extensions == nil {
+		info.Extensions = make(openapi.Extensions)
+	}
+	info.Extensions["x-build"] = build
+}
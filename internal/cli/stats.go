@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+func (c *CLI) runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path or - for stdin")
+	format := fs.String("format", "text", "Output format: text or json (default: text)")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	showHelp := fs.Bool("help", false, "Show help for stats command")
+
+	if err := parseWithSpecArg(fs, args, input); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.StatsHelp())
+		return nil
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("one specification required: yaswag stats <spec> [options]")
+	}
+
+	result, err := readFromStdinOrFile(*input, true)
+	if err != nil {
+		return err
+	}
+	doc, err := parseDocument(result.data)
+	if err != nil {
+		return err
+	}
+
+	data, err := marshalStats(doc.Stats(), *format)
+	if err != nil {
+		return err
+	}
+	return c.writeOutput(*outputPath, data, "Spec statistics")
+}
+
+// parseWithSpecArg parses the flags of a command that accepts the spec as an
+// argument too, as in yaswag stats spec.yaml --format json, setting input to
+// it. Arguments left after the flags remain in fs.
+func parseWithSpecArg(fs *flag.FlagSet, args []string, input *string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return nil
+	}
+	*input = fs.Arg(0)
+	return fs.Parse(fs.Args()[1:])
+}
+
+func marshalStats(stats openapi.Stats, format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		data, err := json.MarshalIndent(struct {
+			openapi.Stats
+			DescriptionCoverage int `json:"descriptionCoverage"`
+		}{stats, stats.Descriptions.Average()}, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to format JSON: %w", err)
+		}
+		return append(data, '\n'), nil
+	case "text":
+		return []byte(formatStats(stats)), nil
+	default:
+		return nil, fmt.Errorf("unsupported stats format: %s (supported: text, json)", format)
+	}
+}
+
+// statsMethods are the HTTP methods in the order the text report lists them.
+var statsMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "TRACE"}
+
+func formatStats(stats openapi.Stats) string {
+	var sb strings.Builder
+	var methods []string
+	for _, method := range statsMethods {
+		if n := stats.Methods[method]; n > 0 {
+			methods = append(methods, fmt.Sprintf("%s %d", method, n))
+		}
+	}
+	fmt.Fprintf(&sb, "Paths:       %d\n", stats.Paths)
+	fmt.Fprintf(&sb, "Operations:  %d", stats.Operations)
+	if len(methods) > 0 {
+		fmt.Fprintf(&sb, " (%s)", strings.Join(methods, ", "))
+	}
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "Webhooks:    %d\n", stats.Webhooks)
+	fmt.Fprintf(&sb, "Schemas:     %d\n", stats.Schemas)
+	fmt.Fprintf(&sb, "Parameters:  %d\n", stats.Parameters)
+
+	if len(stats.Responses) > 0 {
+		sb.WriteString("\nResponse codes (operations):\n")
+		for _, status := range slices.Sorted(maps.Keys(stats.Responses)) {
+			fmt.Fprintf(&sb, "  %-8s %d\n", status, stats.Responses[status])
+		}
+	}
+
+	if len(stats.Tags) > 0 || stats.Untagged > 0 {
+		sb.WriteString("\nTags (operations):\n")
+		formatTagStats(&sb, stats)
+	}
+
+	d := stats.Descriptions
+	fmt.Fprintf(&sb, "\nDescription coverage: %d%% on average\n", d.Average())
+	for _, kind := range []struct {
+		name     string
+		coverage openapi.Coverage
+	}{
+		{"operations", d.Operations}, {"parameters", d.Parameters},
+		{"schemas", d.Schemas}, {"properties", d.Properties},
+	} {
+		c := kind.coverage
+		fmt.Fprintf(&sb, "  %-11s %d/%d (%d%%)\n", kind.name, c.Described, c.Total, c.Percent())
+	}
+
+	fmt.Fprintf(&sb, "\nOrphaned components: %d\n", len(stats.Orphaned))
+	for _, ref := range stats.Orphaned {
+		fmt.Fprintf(&sb, "  %s\n", ref)
+	}
+	return sb.String()
+}
+
+// formatTagStats writes the operations of each tag, most first, and of none.
+func formatTagStats(sb *strings.Builder, stats openapi.Stats) {
+	tags := slices.SortedFunc(maps.Keys(stats.Tags), func(a, b string) int {
+		return cmp.Or(cmp.Compare(stats.Tags[b], stats.Tags[a]), cmp.Compare(a, b))
+	})
+	width := len("(untagged)")
+	for _, tag := range tags {
+		width = max(width, len(tag))
+	}
+	for _, tag := range tags {
+		fmt.Fprintf(sb, "  %-*s %d\n", width, tag, stats.Tags[tag])
+	}
+	if stats.Untagged > 0 {
+		fmt.Fprintf(sb, "  %-*s %d\n", width, "(untagged)", stats.Untagged)
+	}
+}
+
+func (c *CLI) StatsHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Report statistics of an OpenAPI specification.\n\n")
+	help.WriteString("Counts the paths, operations per method, webhooks, schemas, and parameters,\n")
+	help.WriteString("the operations per response code and per tag, how many operations,\n")
+	help.WriteString("parameters, schemas, and properties are described, and the components no\n")
+	help.WriteString("operation uses, to track the growth of an API over time.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag stats <spec> [options]\n")
+	help.WriteString("  <command> | yaswag stats [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>    Input file path or - for stdin, instead of <spec>\n")
+	help.WriteString("  --format <type>   Output format: text or json (default: text)\n")
+	help.WriteString("  --output <path>   Output file path (empty for stdout)\n")
+	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag stats ./swagger.yaml\n")
+	help.WriteString("  yaswag stats ./swagger.yaml --format json --output stats/$(date +%F).json\n")
+	help.WriteString("  yaswag generate --source ./api | yaswag stats\n")
+	return help.String()
+}
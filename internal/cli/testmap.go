@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/parser"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// testMap relates every operation to its handler and the tests that refer to it.
+type testMap struct {
+	Total      int              `json:"total"`
+	Tested     int              `json:"tested"`
+	Operations []testMapEntry   `json:"operations"`
+	Untested   []ownedOperation `json:"untested"`
+}
+
+type testMapEntry struct {
+	ownedOperation
+	Handler string                 `json:"handler"`
+	File    string                 `json:"file"`
+	Line    int                    `json:"line"`
+	Owner   string                 `json:"owner,omitempty"`
+	Tests   []parser.TestReference `json:"tests"`
+}
+
+func (c *CLI) runTestMap(args []string) error {
+	fs := flag.NewFlagSet("testmap", flag.ExitOnError)
+	source := fs.String("source", ".", "Source directory to parse")
+	tests := fs.String("tests", "", "Directory to scan for _test.go files (default: --source)")
+	format := fs.String("format", "text", "Output format: text or json (default: text)")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	failUntested := fs.Bool("fail-untested", false, "Exit with status 1 if an operation's handler has no test references")
+	showHelp := fs.Bool("help", false, "Show help for testmap command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.TestMapHelp())
+		return nil
+	}
+
+	if *tests == "" {
+		*tests = *source
+	}
+	report, err := buildTestMap(*source, *tests)
+	if err != nil {
+		return err
+	}
+
+	data, err := marshalTestMap(report, *format)
+	if err != nil {
+		return err
+	}
+	if err := c.writeOutput(*outputPath, data, "Test map"); err != nil {
+		return err
+	}
+
+	if *failUntested && len(report.Untested) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// buildTestMap parses the annotated handlers under source and looks up the
+// tests under tests that refer to them. Operations are sorted by path.
+func buildTestMap(source, tests string) (*testMap, error) {
+	p := parser.New()
+	if err := p.ParseDir(source); err != nil {
+		return nil, fmt.Errorf("failed to parse source: %w", err)
+	}
+	spec := p.GetSpec()
+	if len(spec.Operations) == 0 {
+		return nil, fmt.Errorf("no annotated operations found in %s", source)
+	}
+
+	handlers := make([]string, 0, len(spec.Operations))
+	byRoute := make(map[string]parser.OperationData, len(spec.Operations))
+	for _, op := range spec.Operations {
+		handlers = append(handlers, op.Handler)
+		byRoute[op.Method+" "+op.Path] = op
+	}
+	refs, err := parser.FindTestReferences(tests, handlers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan tests: %w", err)
+	}
+
+	report := &testMap{Operations: []testMapEntry{}, Untested: []ownedOperation{}}
+	doc := p.Generate()
+	eachOperation(doc, func(method, path string, op *openapi.Operation) {
+		data := byRoute[method+" "+path]
+		entry := testMapEntry{
+			ownedOperation: ownedOperation{Method: method, Path: path, OperationID: op.OperationID},
+			Handler:        data.Handler,
+			File:           relativeTo(source, data.Source.Filename),
+			Line:           data.Source.Line,
+			Tests:          slices.Clone(refs[data.Handler]),
+		}
+		for i := range entry.Tests {
+			entry.Tests[i].File = relativeTo(source, entry.Tests[i].File)
+		}
+		if owner := doc.OwnerOf(op); owner != nil {
+			entry.Owner = owner.Team
+		}
+		report.add(entry)
+	})
+	return report, nil
+}
+
+func (r *testMap) add(entry testMapEntry) {
+	r.Total++
+	if len(entry.Tests) > 0 {
+		r.Tested++
+	} else {
+		entry.Tests = []parser.TestReference{}
+		r.Untested = append(r.Untested, entry.ownedOperation)
+	}
+	r.Operations = append(r.Operations, entry)
+}
+
+// relativeTo returns path relative to dir when it lies below it.
+func relativeTo(dir, path string) string {
+	if rel, err := filepath.Rel(dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
+
+func marshalTestMap(report *testMap, format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to format JSON: %w", err)
+		}
+		return append(data, '\n'), nil
+	case "text":
+		return []byte(formatTestMap(report)), nil
+	default:
+		return nil, fmt.Errorf("unsupported testmap format: %s (supported: text, json)", format)
+	}
+}
+
+func formatTestMap(report *testMap) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Tested operations: %d/%d\n", report.Tested, report.Total)
+
+	var untested, tested []testMapEntry
+	for _, e := range report.Operations {
+		if len(e.Tests) == 0 {
+			untested = append(untested, e)
+		} else {
+			tested = append(tested, e)
+		}
+	}
+	writeTestMapSection(&sb, "Untested", untested)
+	writeTestMapSection(&sb, "Tested", tested)
+	return sb.String()
+}
+
+func writeTestMapSection(sb *strings.Builder, title string, entries []testMapEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	sb.WriteString("\n" + title + ":\n")
+	for _, e := range entries {
+		fmt.Fprintf(sb, "  %s -> %s (%s:%d)", e.ownedOperation, e.Handler, e.File, e.Line)
+		if e.Owner != "" {
+			sb.WriteString(" [" + e.Owner + "]")
+		}
+		sb.WriteString("\n")
+		for _, t := range e.Tests {
+			fmt.Fprintf(sb, "    %s (%s:%d)\n", t.Func, t.File, t.Line)
+		}
+	}
+}
+
+func (c *CLI) TestMapHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Map each operation to its Go handler and the tests that refer to it.\n\n")
+	help.WriteString("Handlers are the functions carrying the operation annotations. A test refers\n")
+	help.WriteString("to a handler when a function in a _test.go file uses the handler's name, as in\n")
+	help.WriteString("CreatePet(w, r) or h.CreatePet, or is named after it, as in TestCreatePet or\n")
+	help.WriteString("TestPetHandler_CreatePet. Operations whose handlers no test refers to are\n")
+	help.WriteString("listed as untested, with their owning team.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag testmap [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --source <dir>     Source directory to parse (default: .)\n")
+	help.WriteString("  --tests <dir>      Directory to scan for _test.go files (default: --source)\n")
+	help.WriteString("  --format <type>    Output format: text or json (default: text)\n")
+	help.WriteString("  --output <path>    Output file path (empty for stdout)\n")
+	help.WriteString("  --fail-untested    Exit with status 1 if any operation is untested\n")
+	help.WriteString("  --help             Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag testmap --source ./api\n")
+	help.WriteString("  yaswag testmap --source ./api --tests ./test --format json --output testmap.json\n")
+	help.WriteString("  yaswag testmap --source ./api --fail-untested\n")
+	return help.String()
+}
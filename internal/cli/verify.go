@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fathurrohman26/yaswag/pkg/verify"
+)
+
+func (c *CLI) runVerifyImpl(args []string) error {
+	fs := flag.NewFlagSet("verify-impl", flag.ExitOnError)
+	specPath := fs.String("spec", "", "Spec file path or - for stdin")
+	baseURL := fs.String("base-url", "", "Base URL of the running server")
+	fixturesPath := fs.String("fixtures", "", "JSON or YAML file with additional requests to send")
+	format := fs.String("format", "text", "Output format: text or json (default: text)")
+	timeout := fs.Duration("timeout", 10*time.Second, "Timeout per request")
+	headers := http.Header{}
+	fs.Func("header", "Header added to every request, e.g. 'Authorization: Bearer token' (repeatable)", func(value string) error {
+		return addHeader(headers, value)
+	})
+	showHelp := fs.Bool("help", false, "Show help for verify-impl command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.VerifyImplHelp())
+		return nil
+	}
+
+	if !isURL(*baseURL) {
+		return fmt.Errorf("--base-url must be an http or https URL")
+	}
+
+	result, err := readFromStdinOrFile(*specPath, true)
+	if err != nil {
+		return err
+	}
+	doc, err := parseDocument(result.data)
+	if err != nil {
+		return err
+	}
+
+	cfg := verify.Config{
+		BaseURL: *baseURL,
+		Client:  &http.Client{Timeout: *timeout},
+		Headers: headers,
+	}
+	if cfg.Fixtures, err = loadFixtures(*fixturesPath); err != nil {
+		return err
+	}
+
+	report, err := verify.New(cfg).Verify(context.Background(), doc)
+	if err != nil {
+		return err
+	}
+
+	if err := outputVerifyResult(report, *format); err != nil {
+		return err
+	}
+	if report.HasDrift() {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// addHeader parses a "Name: value" header and adds it to headers.
+func addHeader(headers http.Header, value string) error {
+	name, val, ok := strings.Cut(value, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("invalid header %q: expected 'Name: value'", value)
+	}
+	headers.Add(strings.TrimSpace(name), strings.TrimSpace(val))
+	return nil
+}
+
+func loadFixtures(path string) ([]verify.Fixture, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+	return verify.LoadFixtures(data)
+}
+
+func outputVerifyResult(report *verify.Result, format string) error {
+	switch strings.ToLower(format) {
+	case "json":
+		data, err := verify.FormatJSON(report)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	default:
+		fmt.Print(verify.FormatText(report))
+	}
+	return nil
+}
+
+func (c *CLI) VerifyImplHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Compare an OpenAPI specification with a running server.\n\n")
+	help.WriteString("Probes every path with OPTIONS and, where a GET is documented, a GET built\n")
+	help.WriteString("from parameter examples, then sends any fixtures. Reports:\n")
+	help.WriteString("  MISSING          documented operations the server does not serve\n")
+	help.WriteString("  UNDOCUMENTED     methods or endpoints the server serves but the spec omits\n")
+	help.WriteString("  STATUS_MISMATCH  responses with status codes the spec does not document\n")
+	help.WriteString("  SKIPPED          paths without parameter examples or fixtures\n\n")
+	help.WriteString("Only OPTIONS and GET requests are generated; other methods are exercised\n")
+	help.WriteString("through fixtures only.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag verify-impl --spec <path> --base-url <url> [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --spec <path>       Spec file path or - for stdin\n")
+	help.WriteString("  --base-url <url>    Base URL of the running server\n")
+	help.WriteString("  --fixtures <path>   JSON or YAML list of requests: method, path, headers,\n")
+	help.WriteString("                      body (sent as JSON), and expected status\n")
+	help.WriteString("  --header <header>   Header added to every request (repeatable)\n")
+	help.WriteString("  --format <type>     Output format: text or json (default: text)\n")
+	help.WriteString("  --timeout <dur>     Timeout per request (default: 10s)\n")
+	help.WriteString("  --help              Show this help message\n\n")
+	help.WriteString("Exit Codes:\n")
+	help.WriteString("  0    No drift found (skipped paths do not count)\n")
+	help.WriteString("  1    Drift found\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag verify-impl --spec ./swagger.yaml --base-url http://localhost:8080\n")
+	help.WriteString("  yaswag verify-impl --spec ./swagger.yaml --base-url http://localhost:8080 \\\n")
+	help.WriteString("    --header 'Authorization: Bearer token' --fixtures ./fixtures.yaml --format json\n")
+	return help.String()
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fathurrohman26/yaswag/pkg/swaggerui"
+)
+
+// watchInterval is how often serve --watch checks for changes.
+const watchInterval = 500 * time.Millisecond
+
+// watchFiles calls reload whenever a file of root, a file or a directory
+// tree, changes, until ctx is done. With match, only the files of a tree
+// that match count. Polling the modification times and sizes works alike on
+// every platform and editor, including the ones that save by renaming a new
+// file.
+func watchFiles(ctx context.Context, root string, match func(path string) bool, reload func()) {
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	last, _ := filesStamp(root, match)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		stamp, err := filesStamp(root, match)
+		if err != nil {
+			// e.g. between an editor removing and recreating the file
+			continue
+		}
+		if stamp != last {
+			last = stamp
+			reload()
+		}
+	}
+}
+
+// filesStamp returns a hash of the names, modification times, and sizes of
+// the files of root.
+func filesStamp(root string, match func(path string) bool) (uint64, error) {
+	h := fnv.New64a()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if path != root && match != nil && !match(path) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(h, "%s %d %d\n", path, info.ModTime().UnixNano(), info.Size())
+		return nil
+	})
+	return h.Sum64(), err
+}
+
+// isGoFile reports whether path is a Go source file, the files serve --watch
+// regenerates the spec from.
+func isGoFile(path string) bool {
+	return strings.HasSuffix(path, ".go")
+}
+
+// watchSpec enables live reload on server and regenerates its spec from
+// source, or reloads it from the input file, whenever they change, until
+// ctx is done.
+func (c *CLI) watchSpec(ctx context.Context, server *swaggerui.Server, source, input string) {
+	server.SetLiveReload(true)
+	if source != "" {
+		go watchFiles(ctx, source, isGoFile, func() {
+			if err := c.regenerateSpec(server, source); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to regenerate spec: %v\n", err)
+				return
+			}
+			fmt.Printf("Regenerated the spec from %s\n", source)
+		})
+	} else {
+		go watchFiles(ctx, input, nil, reloadSpecFile(server, input))
+	}
+	fmt.Println("Watching for changes")
+}
+
+// reloadSpecFile returns a reload function that sets the spec of server from
+// the file at path.
+func reloadSpecFile(server specSetter, path string) func() {
+	return func() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to reload spec: %v\n", err)
+			return
+		}
+		server.SetSpecFromData(data)
+		fmt.Printf("Reloaded %s\n", path)
+	}
+}
+
+// regenerateSpec generates the spec of server from the annotations of source.
+func (c *CLI) regenerateSpec(server specSetter, source string) error {
+	doc, err := c.parseAndGenerate(source, generateOptions{})
+	if err != nil {
+		return err
+	}
+	data, err := c.formatOutput(doc, "json", 2)
+	if err != nil {
+		return err
+	}
+	server.SetSpecFromData(data)
+	return nil
+}
@@ -3,7 +3,11 @@
 package parser
 
 import (
-	"regexp"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -23,6 +27,7 @@ const (
 	AnnotationContact      AnnotationType = "contact"      // !contact "Name" <email> (url)
 	AnnotationLicense      AnnotationType = "license"      // !license MIT https://...
 	AnnotationServer       AnnotationType = "server"       // !server https://... "Description"
+	AnnotationServerVar    AnnotationType = "server-var"   // !server-var region default=us-east enum=us-east,eu-west "Region"
 	AnnotationTag          AnnotationType = "tag"          // !tag users "Description"
 	AnnotationTOS          AnnotationType = "tos"          // !tos https://example.com/tos
 	AnnotationSecurity     AnnotationType = "security"     // !security apiKey:header:api_key "API Key Auth"
@@ -30,19 +35,52 @@ const (
 	AnnotationExternalDocs AnnotationType = "externalDocs" // !externalDocs https://... "Description"
 	AnnotationLink         AnnotationType = "link"         // !link "Label" https://...
 
+	// Specification extension of the API, an operation, a model, or a field,
+	// depending on the comment it is written in
+	AnnotationExtension AnnotationType = "x" // !x x-internal true or !x go-type time.Duration
+
+	// Markdown description of the API, an operation, a model, or a field: the
+	// comment lines after it up to the next annotation
+	AnnotationDescription AnnotationType = "description" // !description, then Markdown lines
+
+	// Shared component annotations, declared once and referenced from operations
+	AnnotationSharedParam    AnnotationType = "shared-param"    // !shared-param PageSize query limit:integer "Page size" default=20
+	AnnotationSharedBody     AnnotationType = "shared-body"     // !shared-body NewPet Pet "Pet to add" required
+	AnnotationSharedResponse AnnotationType = "shared-response" // !shared-response NotFound Error "Resource not found"
+
 	// Operation annotations
 	AnnotationRoute  AnnotationType = "route"  // !GET /path -> operationId "summary" #tag1 #tag2
 	AnnotationQuery  AnnotationType = "query"  // !query name:type "description" default=value required
 	AnnotationPath   AnnotationType = "path"   // !path id:integer "description" required
 	AnnotationHeader AnnotationType = "header" // !header X-Token:string "description"
 	AnnotationBody   AnnotationType = "body"   // !body SchemaRef "description" required
+	AnnotationForm   AnnotationType = "form"   // !form avatar:binary "Profile picture" required type=image/png
 	AnnotationOK     AnnotationType = "ok"     // !ok SchemaRef "description" or !ok 201 SchemaRef "description"
 	AnnotationError  AnnotationType = "error"  // !error 404 SchemaRef "description"
 	AnnotationSecure AnnotationType = "secure" // !secure api_key oauth2
+	AnnotationOpLink AnnotationType = "oplink" // !oplink 201 getOrder orderId=$response.body#/id "description"
+	AnnotationOwner  AnnotationType = "owner"  // !owner team-payments slack=#payments, or with tag=payments for tags
+
+	AnnotationOpServer       AnnotationType = "op-server"       // !op-server https://uploads.example.com "Upload server"
+	AnnotationOpExternalDocs AnnotationType = "op-externalDocs" // !op-externalDocs https://... "Description"
+
+	// References to shared components
+	AnnotationParamRef    AnnotationType = "param-ref"    // !param-ref PageSize PageToken
+	AnnotationBodyRef     AnnotationType = "body-ref"     // !body-ref NewPet
+	AnnotationResponseRef AnnotationType = "response-ref" // !response-ref 404 NotFound
+
+	// Example annotation, applied to the preceding !body, !ok, or !error
+	AnnotationExample AnnotationType = "example" // !example created {"id":1} or !example file=./pet.json
 
 	// Schema annotations
-	AnnotationModel AnnotationType = "model" // !model "Description"
-	AnnotationField AnnotationType = "field" // !field name:type "description" required example=value
+	AnnotationModel   AnnotationType = "model"   // !model "Description"
+	AnnotationExtends AnnotationType = "extends" // !extends Base, composing the model with allOf
+	AnnotationView    AnnotationType = "view"    // !view PetCreate omit=id,status "Pet to create"
+	AnnotationField   AnnotationType = "field"   // !field name:type "description" required deprecated example=value
+
+	// Internal-only operation or model, left out of the spec unless internal
+	// items are included
+	AnnotationIgnore AnnotationType = "ignore" // !ignore "Reason"
 )
 
 // Annotation represents a parsed YaSwag annotation.
@@ -51,305 +89,969 @@ type Annotation struct {
 	RawLine string
 	Args    map[string]string
 	Tags    []string
+
+	// Pos is the position of the annotation in its source file, when parsed with ParseComment
+	Pos token.Position
+
+	// Locale is the locale of a localized annotation, such as fr for
+	// !info.fr, or empty
+	Locale string
+}
+
+// Diagnostic reports an annotation line that could not be parsed, at the
+// argument that is wrong.
+type Diagnostic struct {
+	Pos     token.Position
+	Message string
 }
 
-// AnnotationParser parses YaSwag's eccentric annotation syntax.
-type AnnotationParser struct {
-	// Patterns for different annotation types
-	apiPattern          *regexp.Regexp
-	infoPattern         *regexp.Regexp
-	contactPattern      *regexp.Regexp
-	licensePattern      *regexp.Regexp
-	serverPattern       *regexp.Regexp
-	tagPattern          *regexp.Regexp
-	tosPattern          *regexp.Regexp
-	securityPattern     *regexp.Regexp
-	scopePattern        *regexp.Regexp
-	externalDocsPattern *regexp.Regexp
-	linkPattern         *regexp.Regexp
-	routePattern        *regexp.Regexp
-	paramPattern        *regexp.Regexp
-	bodyPattern         *regexp.Regexp
-	responsePattern     *regexp.Regexp
-	securePattern       *regexp.Regexp
-	modelPattern        *regexp.Regexp
-	fieldPattern        *regexp.Regexp
+// String formats the diagnostic as file:line:column: message, e.g.
+// pet.go:42:7: !ok missing schema name.
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", d.Pos.Filename, d.Pos.Line, d.Pos.Column, d.Message)
 }
 
+// AnnotationParser parses YaSwag's eccentric annotation syntax. It reads
+// each line in a single pass: the keyword after the ! selects the
+// annotation, whose arguments are then read as words, "quoted strings"
+// with \" and \\ escapes, and key=value modifiers.
+type AnnotationParser struct{}
+
 // NewAnnotationParser creates a new annotation parser for YaSwag's eccentric syntax.
 func NewAnnotationParser() *AnnotationParser {
-	return &AnnotationParser{
-		// !api 3.0.3 or !api v3.0.3
-		apiPattern: regexp.MustCompile(`^!api\s+v?([\d.]+)`),
+	return &AnnotationParser{}
+}
 
-		// !info "Title" v1.0.0 "Description"
-		infoPattern: regexp.MustCompile(`^!info\s+"([^"]+)"\s+v?([\d.]+)(?:\s+"([^"]*)")?`),
+// Parse extracts all YaSwag annotations from comment text.
+func (p *AnnotationParser) Parse(text string) []Annotation {
+	var annotations []Annotation
 
-		// !contact "Name" <email> or !contact "Name" <email> (url) or !contact "" <email>
-		contactPattern: regexp.MustCompile(`^!contact\s+"([^"]*)"(?:\s+<([^>]+)>)?(?:\s+\(([^)]+)\))?`),
+	for _, line := range joinAnnotationLines(strings.Split(text, "\n")) {
+		if a, _ := line.parse(); a != nil {
+			annotations = append(annotations, *a)
+		}
+	}
 
-		// !license Name URL
-		licensePattern: regexp.MustCompile(`^!license\s+(\S+)(?:\s+(\S+))?`),
+	return annotations
+}
 
-		// !server URL "Description"
-		serverPattern: regexp.MustCompile(`^!server\s+(\S+)(?:\s+"([^"]*)")?`),
+// ParseComment extracts the annotations of a comment group with their
+// positions in fset, and diagnoses the lines starting with ! that are not
+// valid annotations at the argument that is wrong.
+func (p *AnnotationParser) ParseComment(fset *token.FileSet, cg *ast.CommentGroup) ([]Annotation, []Diagnostic) {
+	var annotations []Annotation
+	var diagnostics []Diagnostic
+
+	// the lines of the comment group, with the position of each line's
+	// first byte
+	var lines []string
+	var starts []token.Position
+	for _, c := range cg.List {
+		start := fset.Position(c.Slash)
+		for i, raw := range commentLines(c.Text) {
+			pos := start
+			pos.Line += i
+			pos.Column = 1
+			if i == 0 {
+				pos.Column = start.Column + 2 // after the comment marker
+			}
+			lines = append(lines, raw)
+			starts = append(starts, pos)
+		}
+	}
 
-		// !tag name "description"
-		tagPattern: regexp.MustCompile(`^!tag\s+(\S+)(?:\s+"([^"]*)")?`),
+	for _, line := range joinAnnotationLines(lines) {
+		a, err := line.parse()
+		if a != nil {
+			a.Pos = line.position(starts, 0)
+			annotations = append(annotations, *a)
+		} else if err != nil {
+			diagnostics = append(diagnostics, Diagnostic{Pos: line.position(starts, err.pos), Message: err.message})
+		}
+	}
 
-		// !tos URL
-		tosPattern: regexp.MustCompile(`^!tos\s+(\S+)`),
+	return annotations, diagnostics
+}
 
-		// !security name:type:location "description"
-		// Examples:
-		//   !security api_key:apiKey:header "API Key authentication"
-		//   !security petstore_auth:oauth2:implicit "OAuth2 authentication" https://petstore3.swagger.io/oauth/authorize
-		securityPattern: regexp.MustCompile(`^!security\s+(\w+):(apiKey|oauth2|http|openIdConnect):?(\w*)(?:\s+"([^"]*)")?(?:\s+(\S+))?`),
+// commentLines returns the lines of a // or /* */ comment without its markers.
+func commentLines(text string) []string {
+	if line, ok := strings.CutPrefix(text, "//"); ok {
+		return []string{line}
+	}
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+	return strings.Split(text, "\n")
+}
 
-		// !scope security_name scope_name "description"
-		// Example: !scope petstore_auth write:pets "modify pets in your account"
-		scopePattern: regexp.MustCompile(`^!scope\s+(\w+)\s+([\w:]+)(?:\s+"([^"]*)")?`),
+// annotationLine is an annotation as parsed: its first comment line joined
+// with its continuation lines.
+type annotationLine struct {
+	text   string
+	pieces []linePiece
+	block  string // the Markdown lines of a !description
+	last   int    // index of the last comment line of the annotation
+}
 
-		// !externalDocs URL "Description"
-		// Example: !externalDocs https://swagger.io "Find out more about Swagger"
-		externalDocsPattern: regexp.MustCompile(`^!externalDocs\s+(\S+)(?:\s+"([^"]*)")?`),
+// linePiece is the part of an annotation line written on one comment line.
+type linePiece struct {
+	offset int // in the annotation line
+	line   int // index of the comment line
+	column int // byte offset in the comment line
+}
 
-		// !link "Label" URL
-		// Example: !link "The Pet Store repository" https://github.com/swagger-api/swagger-petstore
-		linkPattern: regexp.MustCompile(`^!link\s+"([^"]+)"\s+(\S+)`),
+// parse parses the annotation line, adding the block of a !description to
+// the text on its line.
+func (l annotationLine) parse() (*Annotation, *syntaxError) {
+	a, err := parseLine(l.text)
+	if a != nil && a.Type == AnnotationDescription {
+		a.Args["text"] = strings.Trim(a.Args["text"]+"\n"+l.block, "\n")
+	}
+	return a, err
+}
 
-		// !GET /path -> operationId "summary" #tag1 #tag2
-		// !POST /path -> operationId "summary" #tag
-		routePattern: regexp.MustCompile(`^!(GET|POST|PUT|DELETE|PATCH|OPTIONS|HEAD)\s+(\S+)\s+->\s+(\S+)(?:\s+"([^"]*)")?`),
+// position returns the position of the byte at offset in the annotation
+// line, given the position of the first byte of each comment line.
+func (l annotationLine) position(starts []token.Position, offset int) token.Position {
+	piece := l.pieces[0]
+	for _, p := range l.pieces[1:] {
+		if p.offset <= offset {
+			piece = p
+		}
+	}
+	pos := starts[piece.line]
+	pos.Column += piece.column + offset - piece.offset
+	return pos
+}
 
-		// !query name:type "description" default=value required
-		// !path id:integer "description" required
-		// !header X-Token:string "description"
-		paramPattern: regexp.MustCompile(`^!(query|path|header|cookie)\s+([\w-]+):(\w+)\??\s*(?:"([^"]*)")?`),
+// joinAnnotationLines returns the annotations of the lines of a comment,
+// each joined with its continuation lines by a space. A line continues the
+// annotation above it if the annotation ends in \, or if it is indented
+// further than the annotation's ! and does not start with !. The lines
+// after a !description, up to the next annotation, are its block instead.
+func joinAnnotationLines(lines []string) []annotationLine {
+	var annotations []annotationLine
+	for i := 0; i < len(lines); i++ {
+		text := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(text, "!") {
+			continue
+		}
+		indent := indentation(lines[i])
+		line := annotationLine{text: text, pieces: []linePiece{{line: i, column: strings.Index(lines[i], "!")}}}
 
-		// !body SchemaRef "description" required
-		bodyPattern: regexp.MustCompile(`^!body\s+(\S+)(?:\s+"([^"]*)")?`),
+		if keyword, _, _ := lexLine(text); isDescription(keyword) {
+			line.block, i = descriptionBlock(lines, i, lines[i][:indent])
+			line.last = i
+			annotations = append(annotations, line)
+			continue
+		}
+		for ; i+1 < len(lines); i++ {
+			next := strings.TrimSpace(lines[i+1])
+			if next == "" {
+				break
+			}
+			if continued, ok := strings.CutSuffix(line.text, `\`); ok {
+				line.text = strings.TrimRight(continued, " \t")
+			} else if indentation(lines[i+1]) <= indent || strings.HasPrefix(next, "!") {
+				break
+			}
+			line.text += " "
+			line.pieces = append(line.pieces, linePiece{
+				offset: len(line.text),
+				line:   i + 1,
+				column: strings.Index(lines[i+1], next),
+			})
+			line.text += next
+		}
+		line.text = strings.TrimRight(strings.TrimSuffix(line.text, `\`), " \t")
+		line.last = i
+		annotations = append(annotations, line)
+	}
+	return annotations
+}
+
+// descriptionBlock returns the block of the !description at lines[i]: the
+// lines after it up to the next annotation, without the indentation of the
+// !description, and the index of its last line.
+func descriptionBlock(lines []string, i int, indent string) (string, int) {
+	var block []string
+	for ; i+1 < len(lines); i++ {
+		if _, _, ok := lexLine(strings.TrimSpace(lines[i+1])); ok {
+			break
+		}
+		block = append(block, strings.TrimRight(strings.TrimPrefix(lines[i+1], indent), " \t\r"))
+	}
+	return strings.Join(block, "\n"), i
+}
 
-		// !ok SchemaRef "description" or !ok 201 SchemaRef "description"
-		// !error 404 SchemaRef "description"
-		responsePattern: regexp.MustCompile(`^!(ok|error)\s+(?:(\d+)\s+)?(\S+)(?:\s+"([^"]*)")?`),
+// indentation returns the length of the leading spaces and tabs of line.
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
 
-		// !secure securityName1 securityName2
-		securePattern: regexp.MustCompile(`^!secure\s+(.+)`),
+// annotationSyntax is how the arguments of an annotation are parsed, and its
+// usage for diagnostics.
+type annotationSyntax struct {
+	usage string
+	parse func(l *argLexer) (*Annotation, *syntaxError)
+}
+
+// rawKeywords are the annotations whose arguments are split into words
+// only, as they end with a value taken as written, quotes included.
+var rawKeywords = map[string]bool{"x": true, "example": true, "description": true}
+
+const routeUsage = `!METHOD /path -> operationId "summary" #tag`
+
+// annotationSyntaxes are the syntaxes of the annotations, by keyword.
+var annotationSyntaxes = map[string]annotationSyntax{
+	"api":             {`!api 3.0.3`, parseAPI},
+	"info":            {`!info "Title" v1.0.0 "Description"`, parseInfo},
+	"contact":         {`!contact "Name" <email> (url)`, parseContact},
+	"license":         {`!license Name URL`, parseLicense},
+	"server":          {`!server URL "Description"`, urlParser(AnnotationServer, "server URL")},
+	"server-var":      {`!server-var name default=value enum=a,b "Description"`, parseServerVar},
+	"tag":             {`!tag name "Description"`, parseTag},
+	"tos":             {`!tos URL`, parseTOS},
+	"security":        {`!security name:type:location "Description"`, parseSecurity},
+	"scope":           {`!scope security_name scope_name "Description"`, parseScope},
+	"externalDocs":    {`!externalDocs URL "Description"`, urlParser(AnnotationExternalDocs, "URL")},
+	"link":            {`!link "Label" URL`, parseLink},
+	"x":               {`!x x-key value`, parseExtension},
+	"shared-param":    {`!shared-param Name in name:type "Description"`, parseShared},
+	"shared-body":     {`!shared-body Name Schema "Description" required`, parseShared},
+	"shared-response": {`!shared-response Name Schema "Description"`, parseShared},
+	"param-ref":       {`!param-ref Name...`, parseParamRef},
+	"body-ref":        {`!body-ref Name`, parseBodyRef},
+	"response-ref":    {`!response-ref status Name`, parseResponseRef},
+	"op-server":       {`!op-server URL "Description"`, urlParser(AnnotationOpServer, "server URL")},
+	"op-externalDocs": {`!op-externalDocs URL "Description"`, urlParser(AnnotationOpExternalDocs, "URL")},
+	"ignore":          {`!ignore "Reason"`, parseIgnore},
+	"GET":             {routeUsage, parseRoute},
+	"POST":            {routeUsage, parseRoute},
+	"PUT":             {routeUsage, parseRoute},
+	"DELETE":          {routeUsage, parseRoute},
+	"PATCH":           {routeUsage, parseRoute},
+	"OPTIONS":         {routeUsage, parseRoute},
+	"HEAD":            {routeUsage, parseRoute},
+	"query":           {`!query name:type "Description" required default=value`, parseParam},
+	"path":            {`!path name:type "Description"`, parseParam},
+	"header":          {`!header name:type "Description" required`, parseParam},
+	"cookie":          {`!cookie name:type "Description" required`, parseParam},
+	"body":            {`!body Schema "Description" required type=media/type`, parseBody},
+	"form":            {`!form name:type "Description" required type=media/type`, parseForm},
+	"ok":              {`!ok [status] Schema "Description"`, parseResponse},
+	"error":           {`!error status Schema "Description"`, parseResponse},
+	"secure":          {`!secure name...`, parseSecure},
+	"oplink":          {`!oplink status operationId param=expression "Description"`, parseOpLink},
+	"owner":           {`!owner team key=value...`, parseOwner},
+	"example":         {`!example [name] value or !example [name] file=path`, parseExample},
+	"description":     {`!description, then Markdown lines`, parseDescription},
+	"model":           {`!model "Description" additionalProperties=Schema`, parseModel},
+	"field":           {`!field name:type "Description" required deprecated readonly writeonly example=value format=format`, parseField},
+	"extends":         {`!extends Schema...`, parseExtends},
+	"view":            {`!view Name pick=a,b omit=c required=d optional=e "Description"`, parseView},
+}
 
-		// !model "Description"
-		modelPattern: regexp.MustCompile(`^!model(?:\s+"([^"]*)")?`),
+// parseLine parses an annotation line. It returns neither an annotation nor
+// an error if the line is not an annotation, such as a comment starting
+// with "!=".
+func parseLine(line string) (*Annotation, *syntaxError) {
+	keyword, start, ok := lexLine(line)
+	if !ok {
+		return nil, nil
+	}
+	keyword, locale := splitLocale(keyword)
+	syntax, ok := annotationSyntaxes[keyword]
+	if !ok {
+		return nil, &syntaxError{message: fmt.Sprintf("unknown annotation !%s", keyword)}
+	}
+	if locale != "" && !localizedKeywords[keyword] {
+		return nil, &syntaxError{pos: len(keyword) + 1, message: fmt.Sprintf("!%s cannot be localized: only !info and !description take a locale, translate the arguments of others with modifiers such as desc.%s=", keyword, locale)}
+	}
 
-		// !field name:type "description" required example=value
-		fieldPattern: regexp.MustCompile(`^!field\s+(\w+):(\w+)\??\s*(?:"([^"]*)")?`),
+	a, err := parseArgs(line, keyword, start, syntax.parse)
+	if err == nil {
+		a.RawLine = line
+		a.Locale = locale
+		return a, nil
 	}
+	if err.missing {
+		err.message = fmt.Sprintf("!%s %s (usage: %s)", keyword, err.message, syntax.usage)
+	} else {
+		err.message = fmt.Sprintf("malformed !%s: %s (usage: %s)", keyword, err.message, syntax.usage)
+	}
+	return nil, err
 }
 
-// Parse extracts all YaSwag annotations from comment text.
-func (p *AnnotationParser) Parse(text string) []Annotation {
-	var annotations []Annotation
+// parseArgs lexes the arguments of an annotation line, starting at offset
+// start, and parses them with parse, applying their translations.
+func parseArgs(line, keyword string, start int, parse func(l *argLexer) (*Annotation, *syntaxError)) (*Annotation, *syntaxError) {
+	if rawKeywords[keyword] {
+		return parse(&argLexer{line: line, keyword: keyword, tokens: lexWords(line, start)})
+	}
+	tokens, err := lexArgs(line, start)
+	if err != nil {
+		return nil, err
+	}
+	tokens, translations := splitTranslations(tokens)
+	a, err := parse(&argLexer{line: line, keyword: keyword, tokens: tokens})
+	if err != nil {
+		return nil, err
+	}
+	return a, translate(a, translations)
+}
 
-	lines := strings.Split(text, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if !strings.HasPrefix(line, "!") {
-			continue
+// diagnose explains why the annotation line failed to parse, or returns ""
+// if it parses or is not an annotation.
+func diagnose(line string) string {
+	if _, err := parseLine(line); err != nil {
+		return err.message
+	}
+	return ""
+}
+
+// version reads an OpenAPI or API version such as 3.0.3 or v1.0.0, without
+// its v.
+func (l *argLexer) version(what string) (string, *syntaxError) {
+	tok, err := l.word(what)
+	if err != nil {
+		return "", err
+	}
+	version := strings.TrimPrefix(tok.text, "v")
+	end := 0
+	for end < len(version) && (isDigit(version[end]) || version[end] == '.') {
+		end++
+	}
+	if end == 0 {
+		return "", l.errorf(tok, "expected %s such as 1.0.0, found %s", what, tok.text)
+	}
+	return version[:end], nil
+}
+
+// nameAndType reads a name:type argument, where the name is made of the
+// bytes \w matches and those in nameBytes, and the type, which ends in ? if
+// it is nullable, of those \w matches and those in typeBytes.
+func (l *argLexer) nameAndType(what, nameBytes, typeBytes string) (name, typ string, nullable bool, err *syntaxError) {
+	tok, err := l.word(what)
+	if err != nil {
+		return "", "", false, err
+	}
+	name, typ, ok := strings.Cut(tok.text, ":")
+	typ, nullable = strings.CutSuffix(typ, "?")
+	if !ok || !isWord(name, nameBytes) || !isWord(typ, typeBytes) {
+		return "", "", false, l.errorf(tok, "expected name:type, found %s", tok.text)
+	}
+	return name, typ, nullable, nil
+}
+
+// componentName reads the name of a component, such as a shared parameter.
+func (l *argLexer) componentName(what string) (string, *syntaxError) {
+	tok, err := l.word(what)
+	if err != nil {
+		return "", err
+	}
+	if !isWord(tok.text, ".-") {
+		return "", l.errorf(tok, "invalid %s %s", what, tok.text)
+	}
+	return tok.text, nil
+}
+
+func parseAPI(l *argLexer) (*Annotation, *syntaxError) {
+	version, err := l.version("OpenAPI version")
+	if err != nil {
+		return nil, err
+	}
+	return &Annotation{Type: AnnotationAPI, Args: map[string]string{"version": version}}, nil
+}
+
+func parseInfo(l *argLexer) (*Annotation, *syntaxError) {
+	tok := l.peek()
+	title, err := l.str("title")
+	if err != nil {
+		return nil, err
+	}
+	if title == "" {
+		return nil, l.errorf(tok, "empty title")
+	}
+	version, err := l.version("version")
+	if err != nil {
+		return nil, err
+	}
+	args := map[string]string{"title": title, "version": version, "description": l.optionalString()}
+	return &Annotation{Type: AnnotationInfo, Args: args}, nil
+}
+
+// parseContact parses a !contact, whose email and URL are written as
+// <email> and (url) after its name.
+func parseContact(l *argLexer) (*Annotation, *syntaxError) {
+	name, err := l.str("name")
+	if err != nil {
+		return nil, err
+	}
+	args := map[string]string{"name": name, "email": "", "url": ""}
+	rest := l.raw()
+	if inner, after, ok := strings.Cut(strings.TrimPrefix(rest, "<"), ">"); ok && strings.HasPrefix(rest, "<") && inner != "" {
+		args["email"], rest = inner, strings.TrimLeft(after, " \t")
+	}
+	if inner, _, ok := strings.Cut(strings.TrimPrefix(rest, "("), ")"); ok && strings.HasPrefix(rest, "(") && inner != "" {
+		args["url"] = inner
+	}
+	return &Annotation{Type: AnnotationContact, Args: args}, nil
+}
+
+func parseLicense(l *argLexer) (*Annotation, *syntaxError) {
+	name, err := l.word("license name")
+	if err != nil {
+		return nil, err
+	}
+	args := map[string]string{"name": name.text, "url": ""}
+	if url, ok := l.optionalWord(nil); ok {
+		args["url"] = url.text
+	}
+	return &Annotation{Type: AnnotationLicense, Args: args}, nil
+}
+
+// urlParser returns the parser of an annotation of the given type whose
+// arguments are a URL and a description, such as !server.
+func urlParser(aType AnnotationType, what string) func(*argLexer) (*Annotation, *syntaxError) {
+	return func(l *argLexer) (*Annotation, *syntaxError) {
+		url, err := l.word(what)
+		if err != nil {
+			return nil, err
 		}
+		args := map[string]string{"url": url.text, "description": l.optionalString()}
+		return &Annotation{Type: aType, Args: args}, nil
+	}
+}
 
-		if a := p.parseLine(line); a != nil {
-			annotations = append(annotations, *a)
+func parseServerVar(l *argLexer) (*Annotation, *syntaxError) {
+	name, err := l.word("variable name")
+	if err != nil {
+		return nil, err
+	}
+	if !isWord(name.text, "") {
+		return nil, l.errorf(name, "invalid variable name %s", name.text)
+	}
+	m := l.modifiers()
+	args := map[string]string{"name": name.text, "description": m.description}
+	for _, key := range []string{"default", "enum"} {
+		if value, ok := m.value(key); ok {
+			args[key] = value
 		}
 	}
+	return &Annotation{Type: AnnotationServerVar, Args: args}, nil
+}
 
-	return annotations
+func parseTag(l *argLexer) (*Annotation, *syntaxError) {
+	name, err := l.word("tag name")
+	if err != nil {
+		return nil, err
+	}
+	args := map[string]string{"name": name.text, "description": l.optionalString()}
+	return &Annotation{Type: AnnotationTag, Args: args}, nil
+}
+
+func parseTOS(l *argLexer) (*Annotation, *syntaxError) {
+	url, err := l.word("terms of service URL")
+	if err != nil {
+		return nil, err
+	}
+	return &Annotation{Type: AnnotationTOS, Args: map[string]string{"url": url.text}}, nil
+}
+
+// securitySchemeTypes are the types of security schemes !security declares.
+var securitySchemeTypes = []string{"apiKey", "oauth2", "http", "openIdConnect", "mutualTLS"}
+
+// parseSecurity parses a !security name:type:location, followed by its
+// description and, for OAuth2, its authorization URL.
+func parseSecurity(l *argLexer) (*Annotation, *syntaxError) {
+	tok, err := l.word("security scheme")
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(tok.text, ":", 3)
+	if len(parts) < 2 || !isWord(parts[0], "") {
+		return nil, l.errorf(tok, "expected security scheme as name:type:location, found %s", tok.text)
+	}
+	if !slices.Contains(securitySchemeTypes, parts[1]) {
+		return nil, l.errorf(tok, "unknown security scheme type %q, want one of %s", parts[1], strings.Join(securitySchemeTypes, ", "))
+	}
+	args := map[string]string{"name": parts[0], "type": parts[1], "location": "", "url": ""}
+	if len(parts) == 3 {
+		if parts[2] != "" && !isWord(parts[2], "") {
+			return nil, l.errorf(tok, "invalid security scheme location %q", parts[2])
+		}
+		args["location"] = parts[2]
+	}
+	args["description"] = l.optionalString()
+	if url, ok := l.optionalWord(nil); ok {
+		args["url"] = url.text
+	}
+	return &Annotation{Type: AnnotationSecurity, Args: args}, nil
 }
 
-func (p *AnnotationParser) parseLine(line string) *Annotation {
-	if a := p.parseSimplePatterns(line); a != nil {
-		return a
+func parseScope(l *argLexer) (*Annotation, *syntaxError) {
+	security, err := l.word("security scheme name")
+	if err != nil {
+		return nil, err
 	}
-	if a := p.parseRoutePattern(line); a != nil {
-		return a
+	if !isWord(security.text, "") {
+		return nil, l.errorf(security, "invalid security scheme name %s", security.text)
 	}
-	if a := p.parseParamPattern(line); a != nil {
-		return a
+	name, err := l.word("scope name")
+	if err != nil {
+		return nil, err
 	}
-	if a := p.parseBodyPattern(line); a != nil {
-		return a
+	if !isWord(name.text, ":") {
+		return nil, l.errorf(name, "invalid scope name %s", name.text)
 	}
-	if a := p.parseResponsePattern(line); a != nil {
-		return a
+	args := map[string]string{"security": security.text, "name": name.text, "description": l.optionalString()}
+	return &Annotation{Type: AnnotationScope, Args: args}, nil
+}
+
+func parseLink(l *argLexer) (*Annotation, *syntaxError) {
+	tok := l.peek()
+	label, err := l.str("label")
+	if err != nil {
+		return nil, err
 	}
-	if a := p.parseSecurePattern(line); a != nil {
-		return a
+	if label == "" {
+		return nil, l.errorf(tok, "empty label")
 	}
-	if a := p.parseModelPattern(line); a != nil {
-		return a
+	url, err := l.word("URL")
+	if err != nil {
+		return nil, err
 	}
-	return p.parseFieldPattern(line)
+	return &Annotation{Type: AnnotationLink, Args: map[string]string{"label": label, "url": url.text}}, nil
 }
 
-func (p *AnnotationParser) parseSimplePatterns(line string) *Annotation {
-	// Simple patterns that just extract matched groups
-	matchers := []struct {
-		pattern *regexp.Regexp
-		aType   AnnotationType
-		keys    []string
-	}{
-		{p.apiPattern, AnnotationAPI, []string{"version"}},
-		{p.infoPattern, AnnotationInfo, []string{"title", "version", "description"}},
-		{p.contactPattern, AnnotationContact, []string{"name", "email", "url"}},
-		{p.licensePattern, AnnotationLicense, []string{"name", "url"}},
-		{p.serverPattern, AnnotationServer, []string{"url", "description"}},
-		{p.tagPattern, AnnotationTag, []string{"name", "description"}},
-		{p.tosPattern, AnnotationTOS, []string{"url"}},
-		{p.securityPattern, AnnotationSecurity, []string{"name", "type", "location", "description", "url"}},
-		{p.scopePattern, AnnotationScope, []string{"security", "name", "description"}},
-		{p.externalDocsPattern, AnnotationExternalDocs, []string{"url", "description"}},
-		{p.linkPattern, AnnotationLink, []string{"label", "url"}},
+// parseExtension parses a !x key value, where the x- prefix of key is
+// optional and value, JSON or a plain string, is the rest of the line as
+// written.
+func parseExtension(l *argLexer) (*Annotation, *syntaxError) {
+	key, err := l.word("extension key")
+	if err != nil {
+		return nil, err
+	}
+	if !isWord(key.text, ".-") {
+		return nil, l.errorf(key, "invalid extension key %s", key.text)
 	}
+	args := map[string]string{"key": key.text, "value": strings.TrimSpace(l.raw())}
+	return &Annotation{Type: AnnotationExtension, Args: args}, nil
+}
 
-	for _, m := range matchers {
-		if match := m.pattern.FindStringSubmatch(line); match != nil {
-			args := make(map[string]string)
-			for i, key := range m.keys {
-				if i+1 < len(match) {
-					args[key] = match[i+1]
-				}
-			}
-			return &Annotation{Type: m.aType, RawLine: line, Args: args}
+// parseShared parses a shared component: the rest of a !shared-param line
+// has the syntax of a parameter after its !query/!path/!header/!cookie,
+// that of !shared-body the syntax of !body, and that of !shared-response
+// the syntax of !ok.
+func parseShared(l *argLexer) (*Annotation, *syntaxError) {
+	component, err := l.componentName("component name")
+	if err != nil {
+		return nil, err
+	}
+	var a *Annotation
+	switch l.keyword {
+	case "shared-param":
+		var in *argToken
+		if in, err = l.word("parameter location"); err != nil {
+			return nil, err
+		}
+		if _, ok := paramTypes[in.text]; !ok {
+			return nil, l.errorf(in, "expected parameter location query, path, header, or cookie, found %s", in.text)
+		}
+		a, err = parseParamIn(l, in.text)
+	case "shared-body":
+		a, err = parseBody(l)
+	default:
+		a, err = parseResponseOf(l, AnnotationOK)
+	}
+	if err != nil {
+		return nil, err
+	}
+	a.Type = AnnotationType(l.keyword)
+	a.Args["component"] = component
+	return a, nil
+}
+
+// parseNames reads the rest of the arguments as names, described by what,
+// joined with sep. There must be at least one.
+func parseNames(l *argLexer, what, sep string) (string, []string, *syntaxError) {
+	var names []string
+	for {
+		tok, ok := l.optionalWord(nil)
+		if !ok {
+			break
 		}
+		names = append(names, tok.text)
+	}
+	if len(names) == 0 {
+		_, err := l.word(what)
+		return "", nil, err
 	}
-	return nil
+	return strings.Join(names, sep), names, nil
 }
 
-func (p *AnnotationParser) parseRoutePattern(line string) *Annotation {
-	match := p.routePattern.FindStringSubmatch(line)
-	if match == nil {
-		return nil
+func parseParamRef(l *argLexer) (*Annotation, *syntaxError) {
+	var names []string
+	for {
+		name, ok := l.optionalWord(nil)
+		if !ok {
+			break
+		}
+		if !isWord(name.text, ".-") {
+			return nil, l.errorf(name, "invalid parameter name %s", name.text)
+		}
+		names = append(names, name.text)
 	}
-	return &Annotation{
-		Type:    AnnotationRoute,
-		RawLine: line,
-		Args: map[string]string{
-			"method":      strings.ToUpper(match[1]),
-			"path":        match[2],
-			"operationId": match[3],
-			"summary":     match[4],
-		},
-		Tags: extractTags(line),
+	if len(names) == 0 {
+		_, err := l.word("parameter name")
+		return nil, err
 	}
+	return &Annotation{Type: AnnotationParamRef, Args: map[string]string{"names": strings.Join(names, " ")}}, nil
 }
 
-func (p *AnnotationParser) parseParamPattern(line string) *Annotation {
-	match := p.paramPattern.FindStringSubmatch(line)
-	if match == nil {
-		return nil
+func parseBodyRef(l *argLexer) (*Annotation, *syntaxError) {
+	component, err := l.componentName("request body name")
+	if err != nil {
+		return nil, err
+	}
+	return &Annotation{Type: AnnotationBodyRef, Args: map[string]string{"component": component}}, nil
+}
+
+func parseResponseRef(l *argLexer) (*Annotation, *syntaxError) {
+	status, err := l.word("status")
+	if err != nil {
+		return nil, err
+	}
+	if !isResponseStatus(status.text) {
+		return nil, l.errorf(status, "expected status such as 404, 4XX, or default, found %s", status.text)
+	}
+	component, err := l.componentName("response name")
+	if err != nil {
+		return nil, err
+	}
+	return &Annotation{Type: AnnotationResponseRef, Args: map[string]string{"status": status.text, "component": component}}, nil
+}
+
+// isResponseStatus reports whether s is a status code, a range such as 4XX,
+// or default.
+func isResponseStatus(s string) bool {
+	if s == "default" {
+		return true
+	}
+	if len(s) != 3 {
+		return false
+	}
+	if s[1:] == "XX" {
+		return '1' <= s[0] && s[0] <= '5'
+	}
+	return isDigit(s[0]) && isDigit(s[1]) && isDigit(s[2])
+}
+
+func parseIgnore(l *argLexer) (*Annotation, *syntaxError) {
+	return &Annotation{Type: AnnotationIgnore, Args: map[string]string{"reason": l.optionalString()}}, nil
+}
+
+func parseExtends(l *argLexer) (*Annotation, *syntaxError) {
+	names, _, err := parseNames(l, "schema name", " ")
+	if err != nil {
+		return nil, err
+	}
+	return &Annotation{Type: AnnotationExtends, Args: map[string]string{"names": names}}, nil
+}
+
+// parseRoute parses an operation's !METHOD /path -> operationId "summary",
+// followed by its #tags.
+func parseRoute(l *argLexer) (*Annotation, *syntaxError) {
+	path, err := l.word("path")
+	if err != nil {
+		return nil, err
+	}
+	arrow, err := l.word(`"->"`)
+	if err != nil {
+		return nil, err
+	}
+	if arrow.text != "->" {
+		return nil, l.errorf(arrow, `expected "->" before the operationId, found %s`, arrow.text)
+	}
+	operationID, err := l.word("operationId")
+	if err != nil {
+		return nil, err
 	}
 	args := map[string]string{
-		"in":          match[1],
-		"name":        match[2],
-		"type":        match[3],
-		"description": match[4],
+		"method":      l.keyword,
+		"path":        path.text,
+		"operationId": operationID.text,
+		"summary":     l.optionalString(),
+	}
+	var tags []string
+	for _, flag := range l.modifiers().flags {
+		if name, ok := strings.CutPrefix(flag, "#"); ok {
+			end := 0
+			for end < len(name) && isWordByte(name[end]) {
+				end++
+			}
+			if end > 0 {
+				tags = append(tags, name[:end])
+			}
+		}
+	}
+	return &Annotation{Type: AnnotationRoute, Args: args, Tags: tags}, nil
+}
+
+// paramTypes are the annotation types of parameters, by location.
+var paramTypes = map[string]AnnotationType{
+	"query":  AnnotationQuery,
+	"path":   AnnotationPath,
+	"header": AnnotationHeader,
+	"cookie": AnnotationQuery,
+}
+
+func parseParam(l *argLexer) (*Annotation, *syntaxError) {
+	return parseParamIn(l, l.keyword)
+}
+
+// parseParamIn parses a parameter in the given location: its name:type,
+// description, and the required and default= modifiers.
+func parseParamIn(l *argLexer, in string) (*Annotation, *syntaxError) {
+	name, typ, _, err := l.nameAndType("parameter name and type", "-", "")
+	if err != nil {
+		return nil, err
 	}
-	if strings.Contains(line, " required") {
+	m := l.modifiers()
+	args := map[string]string{"in": in, "name": name, "type": typ, "description": m.description}
+	if m.flag("required") {
 		args["required"] = argTrue
 	}
-	if defMatch := regexp.MustCompile(`default=(\S+)`).FindStringSubmatch(line); defMatch != nil {
-		args["default"] = strings.Trim(defMatch[1], `"'`)
+	if value, ok := m.value("default"); ok {
+		args["default"] = strings.Trim(value, `"'`)
 	}
+	return &Annotation{Type: paramTypes[in], Args: args}, nil
+}
 
-	aType := AnnotationQuery
-	switch match[1] {
-	case "path":
-		aType = AnnotationPath
-	case "header":
-		aType = AnnotationHeader
+func parseBody(l *argLexer) (*Annotation, *syntaxError) {
+	schema, err := l.word("schema name")
+	if err != nil {
+		return nil, err
+	}
+	m := l.modifiers()
+	args := map[string]string{"schema": schema.text, "description": m.description}
+	if m.flag("required") {
+		args["required"] = argTrue
+	}
+	if types := m.list("type"); len(types) > 0 {
+		args["contentTypes"] = strings.Join(types, ",")
 	}
-	return &Annotation{Type: aType, RawLine: line, Args: args}
+	return &Annotation{Type: AnnotationBody, Args: args}, nil
 }
 
-func (p *AnnotationParser) parseBodyPattern(line string) *Annotation {
-	match := p.bodyPattern.FindStringSubmatch(line)
-	if match == nil {
-		return nil
+// parseForm parses a !form field; its type= modifiers are the content
+// types accepted for the field's part of a multipart body.
+func parseForm(l *argLexer) (*Annotation, *syntaxError) {
+	name, typ, _, err := l.nameAndType("field name and type", ".[]-", "[]")
+	if err != nil {
+		return nil, err
 	}
-	args := map[string]string{"schema": match[1], "description": match[2]}
-	if strings.Contains(line, " required") {
+	if element, ok := strings.CutPrefix(typ, "[]"); ok && !isWord(element, "") || !ok && strings.ContainsAny(typ, "[]") {
+		return nil, l.errorf(&l.tokens[l.next-1], "invalid form field type %s", typ)
+	}
+	m := l.modifiers()
+	args := map[string]string{"name": name, "type": typ, "description": m.description}
+	if m.flag("required") {
 		args["required"] = argTrue
 	}
-	return &Annotation{Type: AnnotationBody, RawLine: line, Args: args}
+	if types := m.list("type"); len(types) > 0 {
+		args["contentTypes"] = strings.Join(types, ",")
+	}
+	return &Annotation{Type: AnnotationForm, Args: args}, nil
 }
 
-func (p *AnnotationParser) parseResponsePattern(line string) *Annotation {
-	match := p.responsePattern.FindStringSubmatch(line)
-	if match == nil {
-		return nil
+func parseResponse(l *argLexer) (*Annotation, *syntaxError) {
+	if l.keyword == "error" {
+		return parseResponseOf(l, AnnotationError)
 	}
-	statusCode, schema := match[2], match[3]
-	if match[1] == "ok" && statusCode == "" {
-		statusCode = "200"
+	return parseResponseOf(l, AnnotationOK)
+}
+
+// parseResponseOf parses a response: its status, 200 for !ok and 500 for
+// !error if it is left out, schema, description, and type= modifiers. A
+// response whose description directly follows its status has no schema, as
+// in !error 404 "Not found".
+func parseResponseOf(l *argLexer, aType AnnotationType) (*Annotation, *syntaxError) {
+	status := "200"
+	if aType == AnnotationError {
+		status = "500"
 	}
-	if match[1] == "error" && statusCode == "" {
-		statusCode = "500"
+	// a status is followed by the schema or description, so a lone number is
+	// a schema
+	if l.next+1 < len(l.tokens) {
+		if tok, ok := l.optionalWord(func(tok *argToken) bool { return isDigits(tok.text) }); ok {
+			status = tok.text
+		}
 	}
-	aType := AnnotationOK
-	if match[1] == "error" {
-		aType = AnnotationError
+	schema := ""
+	if tok := l.peek(); tok == nil || tok.kind == tokenWord {
+		tok, err := l.word("schema name")
+		if err != nil {
+			return nil, err
+		}
+		schema = tok.text
 	}
-	return &Annotation{
-		Type:    aType,
-		RawLine: line,
-		Args:    map[string]string{"status": statusCode, "schema": schema, "description": match[4]},
+	m := l.modifiers()
+	args := map[string]string{"status": status, "schema": schema, "description": m.description}
+	if types := m.list("type"); len(types) > 0 {
+		args["contentTypes"] = strings.Join(types, ",")
 	}
+	return &Annotation{Type: aType, Args: args}, nil
 }
 
-func (p *AnnotationParser) parseSecurePattern(line string) *Annotation {
-	match := p.securePattern.FindStringSubmatch(line)
-	if match == nil {
-		return nil
+func isDigits(s string) bool {
+	if s == "" {
+		return false
 	}
-	names := strings.Fields(match[1])
-	return &Annotation{
-		Type:    AnnotationSecure,
-		RawLine: line,
-		Args:    map[string]string{"names": strings.Join(names, ",")},
-		Tags:    names,
+	for i := range len(s) {
+		if !isDigit(s[i]) {
+			return false
+		}
 	}
+	return true
 }
 
-func (p *AnnotationParser) parseModelPattern(line string) *Annotation {
-	match := p.modelPattern.FindStringSubmatch(line)
-	if match == nil {
-		return nil
+func parseSecure(l *argLexer) (*Annotation, *syntaxError) {
+	names, list, err := parseNames(l, "security scheme name", ",")
+	if err != nil {
+		return nil, err
 	}
-	return &Annotation{Type: AnnotationModel, RawLine: line, Args: map[string]string{"description": match[1]}}
+	return &Annotation{Type: AnnotationSecure, Args: map[string]string{"names": names}, Tags: list}, nil
 }
 
-func (p *AnnotationParser) parseFieldPattern(line string) *Annotation {
-	match := p.fieldPattern.FindStringSubmatch(line)
-	if match == nil {
-		return nil
+// parseOpLink parses a !oplink status operationId param=expression...
+// "description".
+func parseOpLink(l *argLexer) (*Annotation, *syntaxError) {
+	status, err := l.word("status")
+	if err != nil {
+		return nil, err
 	}
-	args := map[string]string{"name": match[1], "type": match[2], "description": match[3]}
-	if strings.Contains(line, " required") {
-		args["required"] = argTrue
+	if status.text != "default" && (len(status.text) != 3 || !isDigit(status.text[0]) ||
+		!(isDigits(status.text[1:]) || status.text[1:] == "XX")) {
+		return nil, l.errorf(status, "expected status such as 201, 2XX, or default, found %s", status.text)
 	}
-	if exMatch := regexp.MustCompile(`example=("[^"]*"|\S+)`).FindStringSubmatch(line); exMatch != nil {
-		args["example"] = strings.Trim(exMatch[1], `"'`)
+	operationID, err := l.word("operationId")
+	if err != nil {
+		return nil, err
 	}
-	return &Annotation{Type: AnnotationField, RawLine: line, Args: args}
+	var parameters []string
+	for {
+		param, ok := l.optionalWord(func(tok *argToken) bool { return tok.key != "" })
+		if !ok {
+			break
+		}
+		parameters = append(parameters, param.key+"="+param.value)
+	}
+	args := map[string]string{
+		"status":      status.text,
+		"operationId": operationID.text,
+		"parameters":  strings.Join(parameters, " "),
+		"description": l.optionalString(),
+	}
+	return &Annotation{Type: AnnotationOpLink, Args: args}, nil
 }
 
-// extractTags extracts hashtag-style tags from a line (e.g., #users #admin)
-func extractTags(line string) []string {
-	var tags []string
-	tagPattern := regexp.MustCompile(`#(\w+)`)
-	matches := tagPattern.FindAllStringSubmatch(line, -1)
-	for _, match := range matches {
-		tags = append(tags, match[1])
+func parseOwner(l *argLexer) (*Annotation, *syntaxError) {
+	team, err := l.word("team")
+	if err != nil {
+		return nil, err
+	}
+	if team.key != "" || strings.HasPrefix(team.text, "=") {
+		return nil, l.errorf(team, "expected team before %s", team.text)
+	}
+	m := l.modifiers()
+	args := map[string]string{"team": team.text}
+	for _, key := range []string{"slack", "email", "tag"} {
+		if value, ok := m.value(key); ok {
+			args[key] = value
+		}
+	}
+	return &Annotation{Type: AnnotationOwner, Args: args}, nil
+}
+
+// parseExample parses a !example [name] value, where value is the rest of
+// the line as written, or a !example [name] file=path.
+func parseExample(l *argLexer) (*Annotation, *syntaxError) {
+	rest := strings.TrimSpace(l.raw())
+	if rest == "" {
+		_, err := l.word("example value")
+		return nil, err
+	}
+	args := map[string]string{"name": ""}
+
+	// The name is optional: a value that starts like JSON or a file reference has none.
+	if first, value, ok := strings.Cut(rest, " "); ok && !strings.HasPrefix(first, "file=") && !strings.ContainsAny(first[:1], `{["`) {
+		args["name"] = first
+		rest = strings.TrimSpace(value)
+	}
+	if file, ok := strings.CutPrefix(rest, "file="); ok {
+		args["file"] = strings.Trim(file, `"'`)
+	} else {
+		args["value"] = rest
+	}
+	return &Annotation{Type: AnnotationExample, Args: args}, nil
+}
+
+// parseDescription parses a !description, whose Markdown text is the rest
+// of its line and the lines of its block, joined by joinAnnotationLines.
+func parseDescription(l *argLexer) (*Annotation, *syntaxError) {
+	return &Annotation{Type: AnnotationDescription, Args: map[string]string{"text": strings.TrimSpace(l.raw())}}, nil
+}
+
+func parseModel(l *argLexer) (*Annotation, *syntaxError) {
+	m := l.modifiers()
+	args := map[string]string{"description": m.description}
+	if value, ok := m.value("additionalProperties"); ok {
+		args["additionalProperties"] = value
 	}
-	return tags
+	return &Annotation{Type: AnnotationModel, Args: args}, nil
+}
+
+func parseView(l *argLexer) (*Annotation, *syntaxError) {
+	name, err := l.componentName("schema name")
+	if err != nil {
+		return nil, err
+	}
+	m := l.modifiers()
+	args := map[string]string{"name": name, "description": m.description}
+	for _, key := range []string{"pick", "omit", "required", "optional"} {
+		if value, ok := m.value(key); ok {
+			args[key] = value
+		}
+	}
+	return &Annotation{Type: AnnotationView, Args: args}, nil
+}
+
+// parseField parses a !field name:type, where name is the JSON name, which
+// may contain hyphens, and type may be a map, as in
+// !field counts:map[string]integer, and is followed by ? if the field is
+// nullable, as in !field category:Category?
+func parseField(l *argLexer) (*Annotation, *syntaxError) {
+	name, typ, nullable, err := l.nameAndType("field name and type", "-", ".[]")
+	if err != nil {
+		return nil, err
+	}
+	m := l.modifiers()
+	args := map[string]string{"name": name, "type": typ, "description": m.description}
+	if nullable {
+		args["nullable"] = argTrue
+	}
+	for _, flag := range []string{"required", "deprecated", "readonly", "writeonly"} {
+		if m.flag(flag) {
+			args[flag] = argTrue
+		}
+	}
+	if value, ok := m.value("example"); ok {
+		args["example"] = strings.Trim(value, `"'`)
+	}
+	if value, ok := m.value("format"); ok {
+		args["format"] = value
+	}
+	return &Annotation{Type: AnnotationField, Args: args}, nil
 }
 
 // Helper functions for parsed data
@@ -410,7 +1112,7 @@ func GetLicense(a Annotation) ParsedLicense {
 	}
 }
 
-// ParsedServer holds parsed !server data.
+// ParsedServer holds parsed !server and !op-server data.
 type ParsedServer struct {
 	URL         string
 	Description string
@@ -424,6 +1126,31 @@ func GetServer(a Annotation) ParsedServer {
 	}
 }
 
+// ParsedServerVar holds parsed !server-var data.
+type ParsedServerVar struct {
+	Name        string
+	Default     string
+	Enum        []string
+	Description string
+}
+
+// GetServerVar extracts a server variable from annotation. A variable
+// without a default defaults to its first enum value.
+func GetServerVar(a Annotation) ParsedServerVar {
+	v := ParsedServerVar{
+		Name:        a.Args["name"],
+		Default:     a.Args["default"],
+		Description: a.Args["description"],
+	}
+	if enum := a.Args["enum"]; enum != "" {
+		v.Enum = strings.Split(enum, ",")
+	}
+	if v.Default == "" && len(v.Enum) > 0 {
+		v.Default = v.Enum[0]
+	}
+	return v
+}
+
 // ParsedTag holds parsed !tag data.
 type ParsedTag struct {
 	Name        string
@@ -480,48 +1207,235 @@ func GetParam(a Annotation) ParsedParam {
 	}
 }
 
+// ParsedSharedParam holds a parsed !shared-param, a parameter declared under components.
+type ParsedSharedParam struct {
+	Component string
+	ParsedParam
+}
+
+// GetSharedParam extracts a shared parameter from annotation.
+func GetSharedParam(a Annotation) ParsedSharedParam {
+	return ParsedSharedParam{Component: a.Args["component"], ParsedParam: GetParam(a)}
+}
+
+// ParsedSharedBody holds a parsed !shared-body, a request body declared under components.
+type ParsedSharedBody struct {
+	Component string
+	ParsedBody
+}
+
+// GetSharedBody extracts a shared request body from annotation.
+func GetSharedBody(a Annotation) ParsedSharedBody {
+	return ParsedSharedBody{Component: a.Args["component"], ParsedBody: GetBody(a)}
+}
+
+// ParsedSharedResponse holds a parsed !shared-response, a response declared under components.
+type ParsedSharedResponse struct {
+	Component string
+	ParsedResponse
+}
+
+// GetSharedResponse extracts a shared response from annotation.
+func GetSharedResponse(a Annotation) ParsedSharedResponse {
+	return ParsedSharedResponse{Component: a.Args["component"], ParsedResponse: GetResponse(a)}
+}
+
+// ParsedRef holds a parsed !param-ref, !body-ref, or !response-ref.
+type ParsedRef struct {
+	Components []string // one for !body-ref and !response-ref
+	Status     string   // !response-ref only
+}
+
+// GetRef extracts the referenced components from annotation.
+func GetRef(a Annotation) ParsedRef {
+	if a.Type == AnnotationParamRef {
+		return ParsedRef{Components: strings.Fields(a.Args["names"])}
+	}
+	return ParsedRef{Components: []string{a.Args["component"]}, Status: a.Args["status"]}
+}
+
 // ParsedBody holds parsed !body data.
 type ParsedBody struct {
-	Schema      string
-	Description string
-	Required    bool
+	Schema       string
+	Description  string
+	Required     bool
+	ContentTypes []string // from type= modifiers; empty means application/json
 }
 
 // GetBody extracts body from annotation.
 func GetBody(a Annotation) ParsedBody {
 	return ParsedBody{
-		Schema:      a.Args["schema"],
-		Description: a.Args["description"],
-		Required:    a.Args["required"] == argTrue,
+		Schema:       a.Args["schema"],
+		Description:  a.Args["description"],
+		Required:     a.Args["required"] == argTrue,
+		ContentTypes: splitContentTypes(a.Args["contentTypes"]),
+	}
+}
+
+// ParsedFormField holds parsed !form data.
+type ParsedFormField struct {
+	Name         string
+	Type         string
+	Description  string
+	Required     bool
+	ContentTypes []string // from type= modifiers; the encoding of the field's part
+}
+
+// GetFormField extracts a form field from annotation.
+func GetFormField(a Annotation) ParsedFormField {
+	return ParsedFormField{
+		Name:         a.Args["name"],
+		Type:         a.Args["type"],
+		Description:  a.Args["description"],
+		Required:     a.Args["required"] == argTrue,
+		ContentTypes: splitContentTypes(a.Args["contentTypes"]),
 	}
 }
 
 // ParsedResponse holds parsed response (!ok, !error) data.
 type ParsedResponse struct {
-	Status      string
-	Schema      string
-	Description string
-	IsError     bool
+	Status       string
+	Schema       string
+	Description  string
+	IsError      bool
+	ContentTypes []string // from type= modifiers; empty means application/json
 }
 
 // GetResponse extracts response from annotation.
 func GetResponse(a Annotation) ParsedResponse {
 	return ParsedResponse{
+		Status:       a.Args["status"],
+		Schema:       a.Args["schema"],
+		Description:  a.Args["description"],
+		IsError:      a.Type == AnnotationError,
+		ContentTypes: splitContentTypes(a.Args["contentTypes"]),
+	}
+}
+
+func splitContentTypes(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// ParsedOpLink holds parsed !oplink data (a Link object on a response).
+type ParsedOpLink struct {
+	Status      string
+	OperationID string
+	Parameters  map[string]string // parameter name -> value or runtime expression
+	Description string
+}
+
+// GetOpLink extracts a response link from annotation.
+func GetOpLink(a Annotation) ParsedOpLink {
+	link := ParsedOpLink{
 		Status:      a.Args["status"],
-		Schema:      a.Args["schema"],
+		OperationID: a.Args["operationId"],
 		Description: a.Args["description"],
-		IsError:     a.Type == AnnotationError,
+	}
+	for _, pair := range strings.Fields(a.Args["parameters"]) {
+		name, value, _ := strings.Cut(pair, "=")
+		if link.Parameters == nil {
+			link.Parameters = make(map[string]string)
+		}
+		link.Parameters[name] = value
+	}
+	return link
+}
+
+// ParsedOwner holds parsed !owner data.
+type ParsedOwner struct {
+	Team  string
+	Slack string
+	Email string
+	Tags  []string // tags the owner applies to; empty for the enclosing operation
+}
+
+// GetOwner extracts an owner from annotation.
+func GetOwner(a Annotation) ParsedOwner {
+	owner := ParsedOwner{
+		Team:  a.Args["team"],
+		Slack: a.Args["slack"],
+		Email: a.Args["email"],
+	}
+	for _, tag := range strings.Split(a.Args["tag"], ",") {
+		if tag = strings.TrimPrefix(tag, "#"); tag != "" {
+			owner.Tags = append(owner.Tags, tag)
+		}
+	}
+	return owner
+}
+
+// ParsedExample holds parsed !example data.
+type ParsedExample struct {
+	Name  string
+	Value string // inline value, JSON or plain text
+	File  string // path of a JSON, YAML, or text file, relative to the source file
+}
+
+// GetExample extracts an example from annotation.
+func GetExample(a Annotation) ParsedExample {
+	return ParsedExample{
+		Name:  a.Args["name"],
+		Value: a.Args["value"],
+		File:  a.Args["file"],
 	}
 }
 
 // ParsedModel holds parsed !model data.
 type ParsedModel struct {
 	Description string
+
+	// AdditionalProperties is the schema of the values of properties not
+	// declared by the model, such as integer or Order
+	AdditionalProperties string
 }
 
 // GetModel extracts model from annotation.
 func GetModel(a Annotation) ParsedModel {
 	return ParsedModel{
+		Description:          a.Args["description"],
+		AdditionalProperties: a.Args["additionalProperties"],
+	}
+}
+
+// GetDescription extracts the Markdown text of a !description annotation.
+func GetDescription(a Annotation) string {
+	return a.Args["text"]
+}
+
+// GetExtends extracts the schemas of an !extends annotation.
+func GetExtends(a Annotation) []string {
+	return strings.Fields(a.Args["names"])
+}
+
+// ParsedView holds parsed !view data: a variant of a model with only the
+// Pick properties if any, without the Omit properties, and with the
+// Required properties required and the Optional ones not.
+type ParsedView struct {
+	Name        string
+	Pick        []string
+	Omit        []string
+	Required    []string
+	Optional    []string
+	Description string
+}
+
+// GetView extracts a model variant from annotation.
+func GetView(a Annotation) ParsedView {
+	list := func(key string) []string {
+		if a.Args[key] == "" {
+			return nil
+		}
+		return strings.Split(a.Args[key], ",")
+	}
+	return ParsedView{
+		Name:        a.Args["name"],
+		Pick:        list("pick"),
+		Omit:        list("omit"),
+		Required:    list("required"),
+		Optional:    list("optional"),
 		Description: a.Args["description"],
 	}
 }
@@ -532,7 +1446,12 @@ type ParsedField struct {
 	Type        string
 	Description string
 	Required    bool
+	Nullable    bool
+	Deprecated  bool
+	ReadOnly    bool // sent in responses only, such as a generated ID
+	WriteOnly   bool // sent in requests only, such as a password
 	Example     string
+	Format      string // overrides the inferred format, as in format=email
 }
 
 // GetField extracts field from annotation.
@@ -542,7 +1461,12 @@ func GetField(a Annotation) ParsedField {
 		Type:        a.Args["type"],
 		Description: a.Args["description"],
 		Required:    a.Args["required"] == argTrue,
+		Nullable:    a.Args["nullable"] == argTrue,
+		Deprecated:  a.Args["deprecated"] == argTrue,
+		ReadOnly:    a.Args["readonly"] == argTrue,
+		WriteOnly:   a.Args["writeonly"] == argTrue,
 		Example:     a.Args["example"],
+		Format:      a.Args["format"],
 	}
 }
 
@@ -561,7 +1485,7 @@ func GetTOS(a Annotation) ParsedTOS {
 // ParsedSecurity holds parsed !security data.
 type ParsedSecurity struct {
 	Name        string
-	Type        string // apiKey, oauth2, http, openIdConnect
+	Type        string // apiKey, oauth2, http, openIdConnect, mutualTLS
 	Location    string // header, query, cookie (for apiKey); implicit, password, clientCredentials, authorizationCode (for oauth2)
 	Description string
 	URL         string // Authorization URL for OAuth2
@@ -606,7 +1530,7 @@ func GetScope(a Annotation) ParsedScope {
 	}
 }
 
-// ParsedExternalDocs holds parsed !externalDocs data.
+// ParsedExternalDocs holds parsed !externalDocs and !op-externalDocs data.
 type ParsedExternalDocs struct {
 	URL         string
 	Description string
@@ -634,6 +1558,30 @@ func GetLink(a Annotation) ParsedLink {
 	}
 }
 
+// ParsedExtension holds parsed !x data.
+type ParsedExtension struct {
+	Key   string
+	Value any
+}
+
+// GetExtension extracts a specification extension from annotation. The key
+// gets an x- prefix if it lacks one. The value is decoded as JSON when it is
+// valid JSON, kept as a string otherwise, and is true when omitted.
+func GetExtension(a Annotation) ParsedExtension {
+	ext := ParsedExtension{Key: a.Args["key"], Value: true}
+	if !strings.HasPrefix(ext.Key, "x-") {
+		ext.Key = "x-" + ext.Key
+	}
+	if raw := strings.TrimSpace(a.Args["value"]); raw != "" {
+		var value any
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			value = raw
+		}
+		ext.Value = value
+	}
+	return ext
+}
+
 // parseValue attempts to parse a string value into its appropriate type.
 func parseValue(s string) any {
 	s = strings.Trim(s, `"'`)
@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"fmt"
+	goparser "go/parser"
+	"go/token"
 	"reflect"
 	"testing"
 )
@@ -76,6 +79,13 @@ func TestAnnotationParser_Parse(t *testing.T) {
 				{Type: AnnotationTag, RawLine: `!tag users "Operations about users"`, Args: map[string]string{"name": "users", "description": "Operations about users"}},
 			},
 		},
+		{
+			name:  "parse mutualTLS security annotation",
+			input: `!security client_cert:mutualTLS "Client certificate"`,
+			expected: []Annotation{
+				{Type: AnnotationSecurity, RawLine: `!security client_cert:mutualTLS "Client certificate"`, Args: map[string]string{"name": "client_cert", "type": "mutualTLS", "location": "", "description": "Client certificate", "url": ""}},
+			},
+		},
 		{
 			name:  "parse GET route annotation",
 			input: `!GET /users -> getUsers "Retrieve users" #users #admin`,
@@ -146,6 +156,130 @@ func TestAnnotationParser_Parse(t *testing.T) {
 				{Type: AnnotationError, RawLine: `!error ErrorResponse "Server error"`, Args: map[string]string{"status": "500", "schema": "ErrorResponse", "description": "Server error"}},
 			},
 		},
+		{
+			name:  "parse body annotation with content type",
+			input: `!body Pet "Pet to add" required type=application/xml`,
+			expected: []Annotation{
+				{Type: AnnotationBody, RawLine: `!body Pet "Pet to add" required type=application/xml`, Args: map[string]string{"schema": "Pet", "description": "Pet to add", "required": "true", "contentTypes": "application/xml"}},
+			},
+		},
+		{
+			name:  "parse ok response annotation with multiple content types",
+			input: `!ok Report "Monthly report" type=application/json,text/csv type=text/csv type=application/pdf`,
+			expected: []Annotation{
+				{Type: AnnotationOK, RawLine: `!ok Report "Monthly report" type=application/json,text/csv type=text/csv type=application/pdf`, Args: map[string]string{"status": "200", "schema": "Report", "description": "Monthly report", "contentTypes": "application/json,text/csv,application/pdf"}},
+			},
+		},
+		{
+			name:  "parse oplink annotation",
+			input: `!oplink 201 getOrder orderId=$response.body#/id "Fetch the created order"`,
+			expected: []Annotation{
+				{Type: AnnotationOpLink, RawLine: `!oplink 201 getOrder orderId=$response.body#/id "Fetch the created order"`, Args: map[string]string{"status": "201", "operationId": "getOrder", "parameters": "orderId=$response.body#/id", "description": "Fetch the created order"}},
+			},
+		},
+		{
+			name:  "parse named example annotation",
+			input: `!example created {"id": 1, "name": "doggie"}`,
+			expected: []Annotation{
+				{Type: AnnotationExample, RawLine: `!example created {"id": 1, "name": "doggie"}`, Args: map[string]string{"name": "created", "value": `{"id": 1, "name": "doggie"}`}},
+			},
+		},
+		{
+			name:  "parse example file annotation",
+			input: `!example file=./examples/pet.json`,
+			expected: []Annotation{
+				{Type: AnnotationExample, RawLine: `!example file=./examples/pet.json`, Args: map[string]string{"name": "", "file": "./examples/pet.json"}},
+			},
+		},
+		{
+			name:  "parse form annotation",
+			input: `!form avatar:binary "Profile picture" required type=image/png,image/jpeg`,
+			expected: []Annotation{
+				{Type: AnnotationForm, RawLine: `!form avatar:binary "Profile picture" required type=image/png,image/jpeg`, Args: map[string]string{"name": "avatar", "type": "binary", "description": "Profile picture", "required": "true", "contentTypes": "image/png,image/jpeg"}},
+			},
+		},
+		{
+			name:  "parse form array annotation",
+			input: `!form attachments:[]binary`,
+			expected: []Annotation{
+				{Type: AnnotationForm, RawLine: `!form attachments:[]binary`, Args: map[string]string{"name": "attachments", "type": "[]binary", "description": ""}},
+			},
+		},
+		{
+			name:  "parse shared param annotation",
+			input: `!shared-param PageSize query limit:integer "Page size" default=20`,
+			expected: []Annotation{
+				{Type: AnnotationSharedParam, RawLine: `!shared-param PageSize query limit:integer "Page size" default=20`, Args: map[string]string{"component": "PageSize", "in": "query", "name": "limit", "type": "integer", "description": "Page size", "default": "20"}},
+			},
+		},
+		{
+			name:  "parse shared body annotation",
+			input: `!shared-body NewPet Pet "Pet to add" required`,
+			expected: []Annotation{
+				{Type: AnnotationSharedBody, RawLine: `!shared-body NewPet Pet "Pet to add" required`, Args: map[string]string{"component": "NewPet", "schema": "Pet", "description": "Pet to add", "required": "true"}},
+			},
+		},
+		{
+			name:  "parse shared response annotation",
+			input: `!shared-response NotFound Error "Resource not found"`,
+			expected: []Annotation{
+				{Type: AnnotationSharedResponse, RawLine: `!shared-response NotFound Error "Resource not found"`, Args: map[string]string{"component": "NotFound", "status": "200", "schema": "Error", "description": "Resource not found"}},
+			},
+		},
+		{
+			name:  "parse param ref annotation",
+			input: `!param-ref PageSize PageToken`,
+			expected: []Annotation{
+				{Type: AnnotationParamRef, RawLine: `!param-ref PageSize PageToken`, Args: map[string]string{"names": "PageSize PageToken"}},
+			},
+		},
+		{
+			name:  "parse response ref annotation",
+			input: `!response-ref 404 NotFound`,
+			expected: []Annotation{
+				{Type: AnnotationResponseRef, RawLine: `!response-ref 404 NotFound`, Args: map[string]string{"status": "404", "component": "NotFound"}},
+			},
+		},
+		{
+			name:  "parse owner annotation",
+			input: `!owner team-payments slack=#payments tag=payments,refunds`,
+			expected: []Annotation{
+				{Type: AnnotationOwner, RawLine: `!owner team-payments slack=#payments tag=payments,refunds`, Args: map[string]string{"team": "team-payments", "slack": "#payments", "tag": "payments,refunds"}},
+			},
+		},
+		{
+			name:  "parse server variable annotation",
+			input: `!server-var region default=us-east enum=us-east,eu-west "Deployment region"`,
+			expected: []Annotation{
+				{Type: AnnotationServerVar, RawLine: `!server-var region default=us-east enum=us-east,eu-west "Deployment region"`, Args: map[string]string{"name": "region", "default": "us-east", "enum": "us-east,eu-west", "description": "Deployment region"}},
+			},
+		},
+		{
+			name:  "parse operation server and external docs annotations",
+			input: "!op-server https://uploads.example.com \"Upload server\"\n!op-externalDocs https://docs.example.com/uploads",
+			expected: []Annotation{
+				{Type: AnnotationOpServer, RawLine: `!op-server https://uploads.example.com "Upload server"`, Args: map[string]string{"url": "https://uploads.example.com", "description": "Upload server"}},
+				{Type: AnnotationOpExternalDocs, RawLine: `!op-externalDocs https://docs.example.com/uploads`, Args: map[string]string{"url": "https://docs.example.com/uploads", "description": ""}},
+			},
+		},
+		{
+			name:  "parse extension annotations",
+			input: "!x x-internal true\n!x go-type time.Duration\n!x x-deprecated\n!x x-note \"unbalanced",
+			expected: []Annotation{
+				{Type: AnnotationExtension, RawLine: `!x x-internal true`, Args: map[string]string{"key": "x-internal", "value": "true"}},
+				{Type: AnnotationExtension, RawLine: `!x go-type time.Duration`, Args: map[string]string{"key": "go-type", "value": "time.Duration"}},
+				{Type: AnnotationExtension, RawLine: `!x x-deprecated`, Args: map[string]string{"key": "x-deprecated", "value": ""}},
+				{Type: AnnotationExtension, RawLine: `!x x-note "unbalanced`, Args: map[string]string{"key": "x-note", "value": `"unbalanced`}},
+			},
+		},
+		{
+			name:  "parse description block",
+			input: "!description Sells pets\n\n  of every kind.\n![logo](logo.png)\n\n!tag pets",
+			expected: []Annotation{
+				{Type: AnnotationDescription, RawLine: `!description Sells pets`, Args: map[string]string{"text": "Sells pets\n\n  of every kind.\n![logo](logo.png)"}},
+				{Type: AnnotationTag, RawLine: `!tag pets`, Args: map[string]string{"name": "pets", "description": ""}},
+			},
+		},
 		{
 			name:  "parse model annotation",
 			input: `!model "A user entity"`,
@@ -160,6 +294,41 @@ func TestAnnotationParser_Parse(t *testing.T) {
 				{Type: AnnotationModel, RawLine: `!model`, Args: map[string]string{"description": ""}},
 			},
 		},
+		{
+			name:  "parse model annotation with additional properties",
+			input: `!model "Stock by warehouse" additionalProperties=integer`,
+			expected: []Annotation{
+				{Type: AnnotationModel, RawLine: `!model "Stock by warehouse" additionalProperties=integer`, Args: map[string]string{"description": "Stock by warehouse", "additionalProperties": "integer"}},
+			},
+		},
+		{
+			name:  "parse view annotation",
+			input: `!view PetCreate omit=id,status required=tag "Pet to create"`,
+			expected: []Annotation{
+				{Type: AnnotationView, RawLine: `!view PetCreate omit=id,status required=tag "Pet to create"`, Args: map[string]string{"name": "PetCreate", "omit": "id,status", "required": "tag", "description": "Pet to create"}},
+			},
+		},
+		{
+			name:  "parse read-only field annotation",
+			input: `!field id:int64 "Pet ID" readonly`,
+			expected: []Annotation{
+				{Type: AnnotationField, RawLine: `!field id:int64 "Pet ID" readonly`, Args: map[string]string{"name": "id", "type": "int64", "description": "Pet ID", "readonly": "true"}},
+			},
+		},
+		{
+			name:  "parse nullable field annotation",
+			input: `!field category:Category? "Pet category"`,
+			expected: []Annotation{
+				{Type: AnnotationField, RawLine: `!field category:Category? "Pet category"`, Args: map[string]string{"name": "category", "type": "Category", "description": "Pet category", "nullable": "true"}},
+			},
+		},
+		{
+			name:  "parse field annotation with map type",
+			input: `!field counts:map[string]integer "Counts by status"`,
+			expected: []Annotation{
+				{Type: AnnotationField, RawLine: `!field counts:map[string]integer "Counts by status"`, Args: map[string]string{"name": "counts", "type": "map[string]integer", "description": "Counts by status"}},
+			},
+		},
 		{
 			name:  "parse field annotation",
 			input: `!field id:integer "User ID" required example=123`,
@@ -174,6 +343,20 @@ func TestAnnotationParser_Parse(t *testing.T) {
 				{Type: AnnotationField, RawLine: `!field name:string "User name" example="John Doe"`, Args: map[string]string{"name": "name", "type": "string", "description": "User name", "example": "John Doe"}},
 			},
 		},
+		{
+			name:  "parse field annotation with hyphenated name",
+			input: `!field rate-limit:integer "Calls per hour" example=5000`,
+			expected: []Annotation{
+				{Type: AnnotationField, RawLine: `!field rate-limit:integer "Calls per hour" example=5000`, Args: map[string]string{"name": "rate-limit", "type": "integer", "description": "Calls per hour", "example": "5000"}},
+			},
+		},
+		{
+			name:  "parse deprecated field annotation",
+			input: `!field nickname:string "Superseded by name, deprecated" deprecated`,
+			expected: []Annotation{
+				{Type: AnnotationField, RawLine: `!field nickname:string "Superseded by name, deprecated" deprecated`, Args: map[string]string{"name": "nickname", "type": "string", "description": "Superseded by name, deprecated", "deprecated": "true"}},
+			},
+		},
 		{
 			name: "parse multiple annotations",
 			input: `!GET /users -> getUsers "Get users" #users
@@ -185,6 +368,56 @@ func TestAnnotationParser_Parse(t *testing.T) {
 				{Type: AnnotationOK, RawLine: `!ok User[] "Success"`, Args: map[string]string{"status": "200", "schema": "User[]", "description": "Success"}},
 			},
 		},
+		{
+			name:  "parse escaped quotes",
+			input: `!GET /search -> search "Search for \"exact phrases\" with C:\\ paths" #search`,
+			expected: []Annotation{
+				{Type: AnnotationRoute, RawLine: `!GET /search -> search "Search for \"exact phrases\" with C:\\ paths" #search`, Args: map[string]string{"method": "GET", "path": "/search", "operationId": "search", "summary": `Search for "exact phrases" with C:\ paths`}, Tags: []string{"search"}},
+			},
+		},
+		{
+			name:  "parse modifiers only outside strings",
+			input: `!GET /issues -> listIssues "Issues tagged #bug, required reading" #issues`,
+			expected: []Annotation{
+				{Type: AnnotationRoute, RawLine: `!GET /issues -> listIssues "Issues tagged #bug, required reading" #issues`, Args: map[string]string{"method": "GET", "path": "/issues", "operationId": "listIssues", "summary": "Issues tagged #bug, required reading"}, Tags: []string{"issues"}},
+			},
+		},
+		{
+			name:  "parse response without schema",
+			input: `!error 404 "Not found"`,
+			expected: []Annotation{
+				{Type: AnnotationError, RawLine: `!error 404 "Not found"`, Args: map[string]string{"status": "404", "schema": "", "description": "Not found"}},
+			},
+		},
+		{
+			name:  "parse continued annotation",
+			input: "!info \"Pet Store\" v1.0.0 \\\n  \"A long description \\\nthat spans lines\"",
+			expected: []Annotation{
+				{Type: AnnotationInfo, RawLine: `!info "Pet Store" v1.0.0 "A long description that spans lines"`, Args: map[string]string{"title": "Pet Store", "version": "1.0.0", "description": "A long description that spans lines"}},
+			},
+		},
+		{
+			name:  "parse indented continuation lines",
+			input: "!GET /pets -> listPets \"List pets\"\n    #pets\n    #store\n!ok Pet[] \"Pets\"",
+			expected: []Annotation{
+				{Type: AnnotationRoute, RawLine: `!GET /pets -> listPets "List pets" #pets #store`, Args: map[string]string{"method": "GET", "path": "/pets", "operationId": "listPets", "summary": "List pets"}, Tags: []string{"pets", "store"}},
+				{Type: AnnotationOK, RawLine: `!ok Pet[] "Pets"`, Args: map[string]string{"status": "200", "schema": "Pet[]", "description": "Pets"}},
+			},
+		},
+		{
+			name:  "parse localized annotation",
+			input: `!info.pt-BR "Loja de Animais" v1.0.0`,
+			expected: []Annotation{
+				{Type: AnnotationInfo, RawLine: `!info.pt-BR "Loja de Animais" v1.0.0`, Args: map[string]string{"title": "Loja de Animais", "version": "1.0.0", "description": ""}, Locale: "pt-BR"},
+			},
+		},
+		{
+			name:  "parse translated arguments",
+			input: `!query limit:integer "Page size" desc.fr="Taille de page" default=20`,
+			expected: []Annotation{
+				{Type: AnnotationQuery, RawLine: `!query limit:integer "Page size" desc.fr="Taille de page" default=20`, Args: map[string]string{"in": "query", "name": "limit", "type": "integer", "description": "Page size", "description.fr": "Taille de page", "default": "20"}},
+			},
+		},
 		{
 			name:     "no annotations",
 			input:    "This is just a comment without annotations",
@@ -361,6 +594,18 @@ func TestGetResponseError(t *testing.T) {
 	}
 }
 
+func TestGetOpLink(t *testing.T) {
+	a := Annotation{Type: AnnotationOpLink, Args: map[string]string{"status": "200", "operationId": "listItems", "parameters": "ownerId=$request.path.id limit=10"}}
+	link := GetOpLink(a)
+	if link.Status != "200" || link.OperationID != "listItems" {
+		t.Errorf("GetOpLink() = %+v, want status 200 and operationId listItems", link)
+	}
+	want := map[string]string{"ownerId": "$request.path.id", "limit": "10"}
+	if !reflect.DeepEqual(link.Parameters, want) {
+		t.Errorf("Parameters = %v, want %v", link.Parameters, want)
+	}
+}
+
 func TestGetModel(t *testing.T) {
 	a := Annotation{Type: AnnotationModel, Args: map[string]string{"description": "A user entity"}}
 	model := GetModel(a)
@@ -412,3 +657,111 @@ func TestParseValue(t *testing.T) {
 		})
 	}
 }
+
+func TestAnnotationParser_ParseComment(t *testing.T) {
+	src := `package api
+
+// GetPet returns a pet.
+//
+// !GET /pets/{id} -> getPet
+// !ok
+/*
+	!error 404 Error "Not found"
+	!sever https://example.com
+*/
+func GetPet() {}
+`
+	fset := token.NewFileSet()
+	f, err := goparser.ParseFile(fset, "pet.go", src, goparser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var annotations []Annotation
+	var diagnostics []string
+	for _, cg := range f.Comments {
+		a, d := NewAnnotationParser().ParseComment(fset, cg)
+		annotations = append(annotations, a...)
+		for _, diagnostic := range d {
+			diagnostics = append(diagnostics, diagnostic.String())
+		}
+	}
+
+	var positions []string
+	for _, a := range annotations {
+		positions = append(positions, fmt.Sprintf("%s %d:%d", a.Type, a.Pos.Line, a.Pos.Column))
+	}
+	if want := []string{"route 5:4", "error 8:2"}; !reflect.DeepEqual(positions, want) {
+		t.Errorf("annotation positions = %q, want %q", positions, want)
+	}
+	want := []string{
+		`pet.go:6:7: !ok missing schema name (usage: !ok [status] Schema "Description")`,
+		"pet.go:9:2: unknown annotation !sever",
+	}
+	if !reflect.DeepEqual(diagnostics, want) {
+		t.Errorf("diagnostics = %q, want %q", diagnostics, want)
+	}
+}
+
+func TestAnnotationParser_ParseCommentContinuation(t *testing.T) {
+	src := `package api
+
+// !GET /pets -> listPets \
+// "List pets" #pets
+// !query limit \
+//   "Page size"
+func ListPets() {}
+`
+	fset := token.NewFileSet()
+	f, err := goparser.ParseFile(fset, "pet.go", src, goparser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	annotations, diagnostics := NewAnnotationParser().ParseComment(fset, f.Comments[0])
+	if len(annotations) != 1 || annotations[0].Args["summary"] != "List pets" || annotations[0].Pos.Line != 3 || annotations[0].Pos.Column != 4 {
+		t.Errorf("annotations = %+v, want the route at 3:4", annotations)
+	}
+	var got []string
+	for _, d := range diagnostics {
+		got = append(got, d.String())
+	}
+	want := []string{`pet.go:5:11: malformed !query: expected name:type, found limit (usage: !query name:type "Description" required default=value)`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diagnostics = %q, want %q", got, want)
+	}
+}
+
+func TestJoinAnnotationLines(t *testing.T) {
+	// positions on a continuation line are relative to it
+	lines := joinAnnotationLines([]string{" !ok \\", "   Pet"})
+	if len(lines) != 1 || lines[0].text != "!ok Pet" {
+		t.Fatalf("joinAnnotationLines() = %+v", lines)
+	}
+	if pos := lines[0].position([]token.Position{{Line: 1, Column: 3}, {Line: 2, Column: 1}}, len("!ok P")); pos.Line != 2 || pos.Column != 5 {
+		t.Errorf("position = %d:%d, want 2:5", pos.Line, pos.Column)
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"!= nil means failure", ""},
+		{"!important", "unknown annotation !important"},
+		{"!GET", `!GET missing path (usage: !METHOD /path -> operationId "summary" #tag)`},
+		{"!query limit", `malformed !query: expected name:type, found limit (usage: !query name:type "Description" required default=value)`},
+		{"!GET /pets => listPets", `malformed !GET: expected "->" before the operationId, found => (usage: !METHOD /path -> operationId "summary" #tag)`},
+		{`!tag pets "Pet operations`, `malformed !tag: unterminated string (usage: !tag name "Description")`},
+		{"!security key:apikey:header", `malformed !security: unknown security scheme type "apikey", want one of apiKey, oauth2, http, openIdConnect, mutualTLS (usage: !security name:type:location "Description")`},
+		{`!ok Pet "The pet"`, ""},
+		{`!GET.fr /pets -> listPets`, "!GET cannot be localized: only !info and !description take a locale, translate the arguments of others with modifiers such as desc.fr="},
+		{`!ok Pet summary.fr="Animal"`, `malformed !ok: no summary to translate with summary.fr (usage: !ok [status] Schema "Description")`},
+	}
+	for _, tt := range tests {
+		if got := diagnose(tt.line); got != tt.want {
+			t.Errorf("diagnose(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
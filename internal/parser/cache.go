@@ -0,0 +1,237 @@
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"go/ast"
+	"go/build/constraint"
+	"go/token"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// cacheVersion is bumped whenever what the cache records changes, including
+// how annotations parse, since it records parsed comments, so that caches
+// written by other versions are ignored.
+const cacheVersion = 3
+
+// settleTime is how long after its last modification a file's mtime is
+// trusted: a file written twice within it may keep the same mtime.
+const settleTime = time.Second
+
+// Cache records what the Go files of earlier runs contain, by their mtime
+// and size, and by the SHA-256 of their content when those changed, so that
+// ParseDir skips reading and parsing unchanged files without annotations.
+// The types such files declare are parsed only when an annotated file uses
+// them. Annotated files are always parsed, as their models and operations
+// depend on types declared in other files, but the annotations of their
+// unchanged comments are reused rather than parsed again.
+type Cache struct {
+	path  string
+	files map[string]cacheEntry // by absolute path
+	used  map[string]bool       // files stored since opening
+}
+
+// cacheEntry is what a Go file contains.
+type cacheEntry struct {
+	Hash        string                `json:"hash"`
+	ModTime     int64                 `json:"mtime,omitempty"` // in Unix nanoseconds, zero if not yet settled
+	Size        int64                 `json:"size"`
+	Annotated   bool                  `json:"annotated,omitempty"`   // a comment contains !
+	Constrained bool                  `json:"constrained,omitempty"` // it has a //go:build constraint
+	Types       []string              `json:"types,omitempty"`       // names of the types it declares
+	Comments    map[int]cachedComment `json:"comments,omitempty"`    // by the offset of the comment group
+}
+
+// cachedComment is what ParseComment returned for a comment group.
+type cachedComment struct {
+	Annotations []Annotation `json:"annotations,omitempty"`
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+}
+
+type cacheFile struct {
+	Version int                   `json:"version"`
+	Files   map[string]cacheEntry `json:"files"`
+}
+
+// DefaultCachePath returns the path of the cache of the source directory
+// dir in the user's cache directory.
+func DefaultCachePath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(base, "yaswag", hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// OpenCache opens the cache at path. A missing, unreadable, or outdated
+// cache opens empty, as if every file had changed.
+func OpenCache(path string) *Cache {
+	c := &Cache{path: path, files: make(map[string]cacheEntry), used: make(map[string]bool)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	var file cacheFile
+	if json.Unmarshal(data, &file) == nil && file.Version == cacheVersion && file.Files != nil {
+		c.files = file.Files
+	}
+	return c
+}
+
+// Save writes the cache back to its path, leaving out the files ParseDir has
+// not found since it was opened, such as deleted files.
+func (c *Cache) Save() error {
+	file := cacheFile{Version: cacheVersion, Files: make(map[string]cacheEntry, len(c.used))}
+	for path := range c.used {
+		file.Files[path] = c.files[path]
+	}
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+
+	// write a temporary file first, so that concurrent runs never read a
+	// partially written cache
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// lookup returns the entry of the file at path if its content, with the
+// given hash, is unchanged. It only reads the cache.
+func (c *Cache) lookup(path, hash string) (cacheEntry, bool) {
+	entry, ok := c.files[cacheKey(path)]
+	return entry, ok && entry.Hash == hash
+}
+
+// lookupStat returns the entry of the file at path if its settled mtime and
+// its size are unchanged, without reading it. It only reads the cache.
+func (c *Cache) lookupStat(path string, info os.FileInfo) (cacheEntry, bool) {
+	entry, ok := c.files[cacheKey(path)]
+	return entry, ok && entry.ModTime != 0 && entry.ModTime == info.ModTime().UnixNano() && entry.Size == info.Size()
+}
+
+// withStat returns entry with the mtime and size of info, leaving the mtime
+// out until it has settled.
+func withStat(entry cacheEntry, info os.FileInfo) cacheEntry {
+	entry.ModTime, entry.Size = 0, info.Size()
+	if time.Since(info.ModTime()) > settleTime {
+		entry.ModTime = info.ModTime().UnixNano()
+	}
+	return entry
+}
+
+// comment returns a copy of what ParseComment returned for the comment
+// group at offset in the file at path, if the file was stored unchanged
+// since, with the positions in path.
+func (c *Cache) comment(path string, offset int) (cachedComment, bool) {
+	key := cacheKey(path)
+	if !c.used[key] {
+		return cachedComment{}, false
+	}
+	cached, ok := c.files[key].Comments[offset]
+	if !ok {
+		return cachedComment{}, false
+	}
+	return cached.clone(path), true
+}
+
+// storeComment records what ParseComment returned for the comment group at
+// offset in the file at path, a file stored since opening.
+func (c *Cache) storeComment(path string, offset int, comment cachedComment) {
+	key := cacheKey(path)
+	entry, ok := c.files[key]
+	if !ok || !c.used[key] {
+		return
+	}
+	if entry.Comments == nil {
+		entry.Comments = make(map[int]cachedComment)
+	}
+	// a copy, as localize changes the arguments of the annotations it returns
+	entry.Comments[offset] = comment.clone(path)
+	c.files[key] = entry
+}
+
+// clone returns a copy of c that shares nothing with it, with the positions
+// in path.
+func (c cachedComment) clone(path string) cachedComment {
+	clone := cachedComment{
+		Annotations: make([]Annotation, len(c.Annotations)),
+		Diagnostics: slices.Clone(c.Diagnostics),
+	}
+	for i, a := range c.Annotations {
+		a.Args, a.Tags = maps.Clone(a.Args), slices.Clone(a.Tags)
+		a.Pos.Filename = path
+		clone.Annotations[i] = a
+	}
+	for i := range clone.Diagnostics {
+		clone.Diagnostics[i].Pos.Filename = path
+	}
+	return clone
+}
+
+// store records what the file at path contains, keeping it when saved.
+func (c *Cache) store(path string, entry cacheEntry) {
+	key := cacheKey(path)
+	c.used[key] = true
+	c.files[key] = entry
+}
+
+func cacheKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+func contentHash(src []byte) string {
+	sum := sha256.Sum256(src)
+	return hex.EncodeToString(sum[:])
+}
+
+func cacheEntryOf(f *ast.File, hash string) cacheEntry {
+	entry := cacheEntry{Hash: hash}
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			if strings.Contains(c.Text, "!") {
+				entry.Annotated = true
+			}
+			if cg.Pos() < f.Package && constraint.IsGoBuild(c.Text) {
+				entry.Constrained = true
+			}
+		}
+	}
+	for _, decl := range f.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
+			for _, spec := range genDecl.Specs {
+				entry.Types = append(entry.Types, spec.(*ast.TypeSpec).Name.Name)
+			}
+		}
+	}
+	return entry
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DiscoverRoots returns the API roots under dir: the directories containing a
+// Go file with an !api annotation. Each root is the source directory of one
+// service; roots nested in another root are returned separately. The roots
+// are sorted and include dir itself if it is annotated.
+func DiscoverRoots(dir string) ([]string, error) {
+	root := filepath.Clean(dir)
+	annotations := NewAnnotationParser()
+	fset := token.NewFileSet()
+
+	var roots []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != root && skipDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isSourceFile(path) {
+			return nil
+		}
+		if slices.Contains(roots, filepath.Dir(path)) {
+			return nil
+		}
+		found, err := declaresAPI(fset, annotations, path)
+		if err != nil {
+			return err
+		}
+		if found {
+			roots = append(roots, filepath.Dir(path))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(roots)
+	return roots, nil
+}
+
+// declaresAPI reports whether a comment of the Go file at path has an !api annotation.
+func declaresAPI(fset *token.FileSet, annotations *AnnotationParser, path string) (bool, error) {
+	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, cg := range f.Comments {
+		for _, a := range annotations.Parse(cg.Text()) {
+			if a.Type == AnnotationAPI {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// NestedRoots returns the roots in roots that lie below root, which a parse
+// of root must skip so that each service only sees its own sources.
+func NestedRoots(root string, roots []string) []string {
+	var nested []string
+	for _, other := range roots {
+		rel, err := filepath.Rel(root, other)
+		if err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			nested = append(nested, other)
+		}
+	}
+	return nested
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fuzzSeedAnnotations are annotation comments covering each annotation kind.
+var fuzzSeedAnnotations = []string{
+	"!api 3.0.3\n!info \"Pet Store\" v1.0.0 \"A sample API\"\n!contact \"API Team\" <api@example.com> (https://example.com)\n!license MIT https://opensource.org/licenses/MIT\n!tos https://example.com/tos",
+	"!server https://{region}.example.com/v1 \"Main\"\n!server-var region default=us-east enum=us-east,eu-west \"Region\"",
+	"!security apiKey:header:api_key \"API Key Auth\"\n!scope petstore_auth write:pets \"modify pets\"\n!owner team-pets slack=#pets tag=pets",
+	"!tag pets \"Pet operations\"\n!x x-logo {\"url\": \"/logo.png\"}\n!externalDocs https://example.com \"Docs\"\n!link \"Status\" https://status.example.com",
+	"!GET /pets/{id} -> getPet \"Get a pet\" #pets #store\n!path id:integer \"Pet ID\" required\n!query limit:integer \"Page size\" default=20\n!header X-Token:string \"Token\"\n!ok Pet \"The pet\"\n!error 404 Error \"Not found\"\n!secure api_key",
+	"!POST /pets -> addPet\n!body Pet \"The pet\" required\n!form avatar:binary \"Photo\" required type=image/png\n!ok 201 Pet \"Created\"\n!oplink 201 getPet id=$response.body#/id \"The pet\"\n!op-server https://uploads.example.com \"Uploads\"",
+	"!model \"A pet\"\n!field name:string \"The name\" required example=rex\n!example created {\"name\": \"rex\"}",
+	"!shared-param PageSize query limit:integer \"Page size\" default=20\n!shared-body NewPet Pet \"Pet to add\" required\n!shared-response NotFound Error \"Not found\"\n!param-ref PageSize\n!body-ref NewPet\n!response-ref 404 NotFound",
+}
+
+// fuzzSource returns a Go file with text as the doc comment of a function and
+// of a struct, so that annotations reach both the operation and the schema
+// handlers.
+func fuzzSource(text string) string {
+	comment := "// " + strings.ReplaceAll(text, "\n", "\n// ") + "\n"
+	return "package api\n\n" + comment + "func Handler() {}\n\n" + comment +
+		"type Model struct {\n" + comment + "\tName string `json:\"name\"`\n\tTags []string `json:\"tags\"`\n}\n"
+}
+
+// FuzzAnnotations checks that parsing and generating a spec from arbitrary
+// annotation comments never panics and always yields a spec that marshals.
+func FuzzAnnotations(f *testing.F) {
+	for _, seed := range fuzzSeedAnnotations {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, text string) {
+		if strings.Contains(text, "*/") {
+			t.Skip()
+		}
+		p := New()
+		if err := p.parseSource("fuzz.go", fuzzSource(text)); err != nil {
+			return
+		}
+		if _, err := json.Marshal(p.Generate()); err != nil {
+			t.Errorf("generated spec does not marshal: %v", err)
+		}
+	})
+}
+
+// FuzzParseSource checks that parsing arbitrary Go source never panics.
+func FuzzParseSource(f *testing.F) {
+	files, _ := filepath.Glob(filepath.Join("..", "..", "examples", "*", "*.go"))
+	for _, file := range files {
+		if data, err := os.ReadFile(file); err == nil {
+			f.Add(string(data))
+		}
+	}
+	for _, seed := range fuzzSeedAnnotations {
+		f.Add(fuzzSource(seed))
+	}
+	f.Fuzz(func(t *testing.T, src string) {
+		p := New()
+		if err := p.parseSource("fuzz.go", src); err != nil {
+			return
+		}
+		if _, err := json.Marshal(p.Generate()); err != nil {
+			t.Errorf("generated spec does not marshal: %v", err)
+		}
+	})
+}
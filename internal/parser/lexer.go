@@ -0,0 +1,336 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// tokenKind is the kind of a token of an annotation's arguments.
+type tokenKind int
+
+const (
+	tokenWord   tokenKind = iota // a run of non-space characters, such as id:integer or type=text/csv
+	tokenString                  // a "quoted string", with \" and \\ escapes
+)
+
+// argToken is a word or quoted string of an annotation's arguments.
+type argToken struct {
+	kind tokenKind
+	text string // the word as written, or the string unescaped
+	pos  int    // offset in the annotation line
+
+	// key and value of a key=value word, whose value may be a quoted
+	// string, as in example="John Doe"
+	key, value string
+}
+
+// syntaxError is why an annotation line could not be parsed, at an offset
+// in the line.
+type syntaxError struct {
+	pos     int
+	message string
+	missing bool // an argument is missing, rather than malformed
+}
+
+// lexLine splits an annotation line into its keyword, which follows the !
+// and may end in a locale as in !info.fr, and the offset of the arguments
+// after it. ok is false if the line is not an annotation, such as a comment
+// starting with "!=".
+func lexLine(line string) (keyword string, args int, ok bool) {
+	if len(line) < 2 || line[0] != '!' || !isLetter(line[1]) {
+		return "", 0, false
+	}
+	end := lexName(line, 1)
+	if end+1 < len(line) && line[end] == '.' && isLetter(line[end+1]) {
+		end = lexName(line, end+1)
+	}
+	return line[1:end], end, true
+}
+
+// lexName returns the offset after the name, a letter followed by word
+// bytes and dashes, starting at line[start].
+func lexName(line string, start int) int {
+	end := start + 1
+	for end < len(line) && (isWordByte(line[end]) || line[end] == '-') {
+		end++
+	}
+	return end
+}
+
+// splitLocale splits a keyword such as info.fr into the annotation's
+// keyword and its locale, if any.
+func splitLocale(keyword string) (string, string) {
+	keyword, locale, _ := strings.Cut(keyword, ".")
+	return keyword, locale
+}
+
+// lexArgs splits the arguments of an annotation line, starting at offset
+// start, into words and quoted strings.
+func lexArgs(line string, start int) ([]argToken, *syntaxError) {
+	var tokens []argToken
+	pos := start
+	for {
+		for pos < len(line) && isSpace(line[pos]) {
+			pos++
+		}
+		if pos == len(line) {
+			return tokens, nil
+		}
+
+		if line[pos] == '"' {
+			text, end, err := lexString(line, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, argToken{kind: tokenString, text: text, pos: pos})
+			pos = end
+			continue
+		}
+
+		tok, err := lexWord(line, pos)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		pos = tok.pos + len(tok.text)
+	}
+}
+
+// lexWord lexes the word at offset pos, splitting key=value words, whose
+// value may be a quoted string, into their key and value.
+func lexWord(line string, pos int) (argToken, *syntaxError) {
+	tok := argToken{kind: tokenWord, pos: pos}
+	end := pos
+	for end < len(line) && !isSpace(line[end]) {
+		end++
+	}
+	if eq := strings.IndexByte(line[pos:end], '='); eq > 0 {
+		tok.key, tok.value = line[pos:pos+eq], line[pos+eq+1:end]
+		if value := pos + eq + 1; value < len(line) && line[value] == '"' {
+			text, valueEnd, err := lexString(line, value)
+			if err != nil {
+				return argToken{}, err
+			}
+			tok.value, end = text, valueEnd
+		}
+	}
+	tok.text = line[pos:end]
+	return tok, nil
+}
+
+// lexWords splits the arguments of an annotation line, starting at offset
+// start, into words only, keeping quotes as written.
+func lexWords(line string, start int) []argToken {
+	var tokens []argToken
+	pos := start
+	for {
+		for pos < len(line) && isSpace(line[pos]) {
+			pos++
+		}
+		if pos == len(line) {
+			return tokens
+		}
+		end := pos
+		for end < len(line) && !isSpace(line[end]) {
+			end++
+		}
+		tokens = append(tokens, argToken{kind: tokenWord, text: line[pos:end], pos: pos})
+		pos = end
+	}
+}
+
+// lexString reads the quoted string starting at line[start], returning it
+// unescaped and the offset after its closing quote.
+func lexString(line string, start int) (string, int, *syntaxError) {
+	var b strings.Builder
+	for i := start + 1; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '"':
+			return b.String(), i + 1, nil
+		case c == '\\' && i+1 < len(line) && (line[i+1] == '"' || line[i+1] == '\\'):
+			i++
+			b.WriteByte(line[i])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, &syntaxError{pos: start, message: "unterminated string"}
+}
+
+// annotationEscaper escapes text for a quoted string, undoing lexString.
+var annotationEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+func isLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isWordByte(c byte) bool {
+	return isLetter(c) || isDigit(c) || c == '_'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\v' || c == '\f'
+}
+
+// isWord reports whether s is made of the bytes \w matches and those in
+// extra, and is not empty.
+func isWord(s, extra string) bool {
+	if s == "" {
+		return false
+	}
+	for i := range len(s) {
+		if !isWordByte(s[i]) && !strings.ContainsRune(extra, rune(s[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// argLexer reads the arguments of an annotation: positional words and
+// strings first, then modifiers, which are key=value words, bare flags such
+// as required, and a quoted description, in any order.
+type argLexer struct {
+	line    string
+	keyword string
+	tokens  []argToken
+	next    int // index in tokens of the next positional argument
+}
+
+// raw returns the arguments from the next positional one on, as written.
+func (l *argLexer) raw() string {
+	if l.next < len(l.tokens) {
+		return l.line[l.tokens[l.next].pos:]
+	}
+	return ""
+}
+
+// errorf returns a syntax error at tok.
+func (l *argLexer) errorf(tok *argToken, format string, args ...any) *syntaxError {
+	return &syntaxError{pos: tok.pos, message: fmt.Sprintf(format, args...)}
+}
+
+// missing returns the syntax error of a missing argument, described by what,
+// at the end of the line.
+func (l *argLexer) missing(what string) *syntaxError {
+	return &syntaxError{pos: len(l.line), message: "missing " + what, missing: true}
+}
+
+// peek returns the next positional argument, or nil if there is none.
+func (l *argLexer) peek() *argToken {
+	if l.next == len(l.tokens) {
+		return nil
+	}
+	return &l.tokens[l.next]
+}
+
+// word reads the next positional argument, a word described by what, as in
+// "schema name", for errors.
+func (l *argLexer) word(what string) (*argToken, *syntaxError) {
+	if l.next == len(l.tokens) {
+		return nil, l.missing(what)
+	}
+	tok := &l.tokens[l.next]
+	if tok.kind != tokenWord {
+		return nil, l.errorf(tok, "expected %s, found %q", what, tok.text)
+	}
+	l.next++
+	return tok, nil
+}
+
+// optionalWord reads the next positional argument if it is a word for which
+// accept, if not nil, returns true.
+func (l *argLexer) optionalWord(accept func(*argToken) bool) (*argToken, bool) {
+	if l.next == len(l.tokens) {
+		return nil, false
+	}
+	tok := &l.tokens[l.next]
+	if tok.kind != tokenWord || accept != nil && !accept(tok) {
+		return nil, false
+	}
+	l.next++
+	return tok, true
+}
+
+// str reads the next positional argument, a quoted string described by
+// what.
+func (l *argLexer) str(what string) (string, *syntaxError) {
+	if l.next == len(l.tokens) {
+		return "", l.missing(what)
+	}
+	tok := &l.tokens[l.next]
+	if tok.kind != tokenString {
+		return "", l.errorf(tok, "expected %s as a quoted string, found %s", what, tok.text)
+	}
+	l.next++
+	return tok.text, nil
+}
+
+// optionalString reads the next positional argument if it is a quoted
+// string.
+func (l *argLexer) optionalString() string {
+	if l.next < len(l.tokens) && l.tokens[l.next].kind == tokenString {
+		l.next++
+		return l.tokens[l.next-1].text
+	}
+	return ""
+}
+
+// modifiers are the arguments after the positional ones.
+type modifiers struct {
+	description string              // the first quoted string
+	flags       []string            // bare words, such as required
+	values      map[string][]string // key=value words, by key, in order
+}
+
+func (m modifiers) flag(name string) bool {
+	return slices.Contains(m.flags, name)
+}
+
+// value returns the last value of the key=value modifiers with the given
+// key, and whether there is one.
+func (m modifiers) value(key string) (string, bool) {
+	values := m.values[key]
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[len(values)-1], true
+}
+
+// list returns the values of the key=value modifiers with the given key,
+// each a comma-separated list, without duplicates and in order.
+func (m modifiers) list(key string) []string {
+	var list []string
+	for _, value := range m.values[key] {
+		for _, item := range strings.Split(value, ",") {
+			if item != "" && !slices.Contains(list, item) {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+// modifiers reads the remaining arguments as modifiers.
+func (l *argLexer) modifiers() modifiers {
+	m := modifiers{values: make(map[string][]string)}
+	descriptionSeen := false
+	for _, tok := range l.tokens[l.next:] {
+		switch {
+		case tok.kind == tokenString:
+			if !descriptionSeen {
+				m.description, descriptionSeen = tok.text, true
+			}
+		case tok.key != "":
+			m.values[tok.key] = append(m.values[tok.key], tok.value)
+		default:
+			m.flags = append(m.flags, tok.text)
+		}
+	}
+	l.next = len(l.tokens)
+	return m
+}
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// localizedKeywords are the annotations that take a locale, as in !info.fr,
+// to replace the annotation without one when generating for the locale.
+var localizedKeywords = map[string]bool{"info": true, "description": true}
+
+// translatedArgs are the arguments that modifiers such as desc.fr="..."
+// translate, by the modifier's key.
+var translatedArgs = map[string]string{"desc": "description", "summary": "summary", "title": "title"}
+
+// isDescription reports whether keyword is description, with or without a
+// locale.
+func isDescription(keyword string) bool {
+	keyword, _ = splitLocale(keyword)
+	return keyword == "description"
+}
+
+// splitTranslations separates the modifiers translating an argument, such
+// as desc.fr="...", from the other arguments.
+func splitTranslations(tokens []argToken) (args, translations []argToken) {
+	for _, tok := range tokens {
+		if key, locale := splitLocale(tok.key); locale != "" && translatedArgs[key] != "" {
+			translations = append(translations, tok)
+		} else {
+			args = append(args, tok)
+		}
+	}
+	return args, translations
+}
+
+// translate adds the translations of the arguments of a to its Args, under
+// the argument's name and the locale, as description.fr.
+func translate(a *Annotation, translations []argToken) *syntaxError {
+	for _, tok := range translations {
+		key, locale := splitLocale(tok.key)
+		arg := translatedArgs[key]
+		if _, ok := a.Args[arg]; !ok {
+			return &syntaxError{pos: tok.pos, message: fmt.Sprintf("no %s to translate with %s", arg, tok.key)}
+		}
+		a.Args[arg+"."+locale] = tok.value
+	}
+	return nil
+}
+
+// localize returns the annotations of a comment for locale, or for no
+// locale if it is empty. Annotations of other locales are left out, those
+// of locale replace the annotations without a locale of the same type, and
+// the translated arguments of locale replace the originals.
+func localize(annotations []Annotation, locale string) []Annotation {
+	replaced := make(map[AnnotationType]bool)
+	for _, a := range annotations {
+		if a.Locale != "" && a.Locale == locale {
+			replaced[a.Type] = true
+		}
+	}
+
+	var localized []Annotation
+	for _, a := range annotations {
+		if a.Locale != locale && (a.Locale != "" || replaced[a.Type]) {
+			continue
+		}
+		if locale != "" {
+			applyTranslations(a.Args, locale)
+		}
+		localized = append(localized, a)
+	}
+	return localized
+}
+
+// applyTranslations replaces the arguments of args that have a translation
+// for locale, such as desc.fr for desc, with it.
+func applyTranslations(args map[string]string, locale string) {
+	for arg := range args {
+		if value, ok := args[arg+"."+locale]; ok && !strings.Contains(arg, ".") {
+			args[arg] = value
+		}
+	}
+}
@@ -1,17 +1,24 @@
 package parser
 
 import (
+	"encoding/json"
 	"fmt"
 	"go/ast"
+	"go/build/constraint"
 	"go/parser"
 	"go/token"
+	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"gopkg.in/yaml.v3"
 )
 
 // Parser extracts OpenAPI documentation from Go source code using YaSwag's eccentric annotations.
@@ -24,6 +31,83 @@ type Parser struct {
 
 	// Global schemas (from !model annotations)
 	globalSchemas map[string]*SchemaData
+
+	// Directory of the file being parsed, for resolving !example file= paths
+	fileDir string
+
+	// First error loading an !example file in the file being parsed
+	exampleErr error
+
+	// Index in spec.Servers of the !server that !server-var annotations in
+	// the comment being parsed apply to, or -1
+	serverTarget int
+
+	// Whether the comment being parsed declares !api or !info, so that its
+	// !x annotations extend the document
+	apiComment bool
+
+	// Annotation lines that could not be parsed, in source order
+	diagnostics []Diagnostic
+
+	opts Options
+
+	// Whether the file being parsed is internal because of its build constraint
+	internalFile bool
+
+	// Types declared in the parsed files, by name, for resolving aliases,
+	// named non-struct types, and instances of generic types
+	types map[string]*typeDecl
+
+	// Type arguments of the generic type being instantiated, by parameter
+	typeArgs map[string]ast.Expr
+
+	// Named types and instances being resolved, to stop at recursive types
+	resolving map[string]bool
+
+	// Number of generic instances synthesized, bounded by maxInstances
+	instances int
+
+	// Files without annotations that ParseDir skipped, by the types they
+	// declare, to parse when the types are used
+	deferred map[string]string
+}
+
+// typeDecl is a type declaration and the doc comment of its declaration.
+type typeDecl struct {
+	spec *ast.TypeSpec
+	doc  *ast.CommentGroup
+}
+
+// model reports whether the type is annotated with !model.
+func (d *typeDecl) model() bool {
+	return d.doc != nil && strings.Contains(d.doc.Text(), "!model")
+}
+
+// Limits on resolving generic instances, so that types such as
+// Node[T] { Next *Node[Node[T]] } cannot expand forever.
+const (
+	maxInstanceDepth = 16
+	maxInstances     = 256
+)
+
+// Options configures which operations and models a Parser keeps.
+type Options struct {
+	// ExcludeTags are build tags of internal-only files: a file whose
+	// //go:build constraint cannot be satisfied without one of them is
+	// internal, like operations and models annotated with !ignore.
+	ExcludeTags []string
+
+	// IncludeInternal keeps internal operations and models, marked with
+	// x-internal: true, instead of leaving them out.
+	IncludeInternal bool
+
+	// Cache, if set, lets ParseDir skip the unchanged files without
+	// annotations, and records the files it parses.
+	Cache *Cache
+
+	// Locale selects the localized annotations, such as !info.fr and
+	// desc.fr="...", that replace the others; without it they are ignored.
+	Locale string
 }
 
 // SpecData holds all parsed data for an OpenAPI specification.
@@ -32,11 +116,16 @@ type SpecData struct {
 	Info         *openapi.Info
 	Servers      []openapi.Server
 	Tags         []openapi.Tag
+	TagOwners    map[string]*openapi.Owner // from !owner ... tag=name
 	Operations   []OperationData
 	Schemas      map[string]*SchemaData
 	Securities   map[string]*openapi.SecurityScheme
+	Parameters   map[string]*openapi.Parameter   // from !shared-param
+	Bodies       map[string]*openapi.RequestBody // from !shared-body
+	Responses    map[string]*openapi.Response    // from !shared-response
 	ExternalDocs *openapi.ExternalDocumentation
-	Links        []LinkData // Additional links for description
+	Links        []LinkData         // Additional links for description
+	Extensions   openapi.Extensions // from !x next to !api or !info
 }
 
 // LinkData holds a link label and URL.
@@ -47,17 +136,30 @@ type LinkData struct {
 
 // OperationData holds parsed operation data.
 type OperationData struct {
-	Method      string
-	Path        string
-	OperationID string
-	Summary     string
-	Description string
-	Tags        []string
-	Deprecated  bool
-	Parameters  []*openapi.Parameter
-	RequestBody *openapi.RequestBody
-	Responses   openapi.Responses
-	Security    []openapi.SecurityRequirement
+	Method        string
+	Path          string
+	OperationID   string
+	Summary       string
+	Description   string
+	Tags          []string
+	Deprecated    bool
+	Parameters    []*openapi.Parameter
+	RequestBody   *openapi.RequestBody
+	Responses     openapi.Responses
+	ResponseOrder []string // statuses of Responses in declaration order
+	Security      []openapi.SecurityRequirement
+	Owner         *openapi.Owner
+	Servers       []openapi.Server
+	ExternalDocs  *openapi.ExternalDocumentation
+	Extensions    openapi.Extensions
+
+	// Handler is the annotated function, as Name or Type.Name for methods
+	Handler string
+	// Source is the position of the handler's declaration
+	Source token.Position
+
+	// exampleTarget is "body" or the status of the response that !example annotations apply to
+	exampleTarget string
 }
 
 // SchemaData holds parsed schema data with examples.
@@ -70,7 +172,13 @@ type SchemaData struct {
 
 // New creates a new Parser instance.
 func New() *Parser {
+	return NewWithOptions(Options{})
+}
+
+// NewWithOptions creates a new Parser instance configured by opts.
+func NewWithOptions(opts Options) *Parser {
 	return &Parser{
+		opts:             opts,
 		fset:             token.NewFileSet(),
 		annotationParser: NewAnnotationParser(),
 		spec: &SpecData{
@@ -78,43 +186,255 @@ func New() *Parser {
 			Info:       &openapi.Info{},
 			Schemas:    make(map[string]*SchemaData),
 			Securities: make(map[string]*openapi.SecurityScheme),
+			TagOwners:  make(map[string]*openapi.Owner),
+			Parameters: make(map[string]*openapi.Parameter),
+			Bodies:     make(map[string]*openapi.RequestBody),
+			Responses:  make(map[string]*openapi.Response),
 		},
 		globalSchemas: make(map[string]*SchemaData),
+		types:         make(map[string]*typeDecl),
+		resolving:     make(map[string]bool),
+		deferred:      make(map[string]string),
 	}
 }
 
 // ParseDir parses all Go files in the given directory recursively.
 func (p *Parser) ParseDir(dir string) error {
+	return p.ParseDirExcept(dir, nil)
+}
+
+// ParseDirExcept parses all Go files in dir recursively, skipping the
+// directory trees in exclude, such as the roots of other services.
+func (p *Parser) ParseDirExcept(dir string, exclude []string) error {
 	// Clean the path to normalize it
 	root := filepath.Clean(dir)
 
-	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+	// The files are found first, then parsed concurrently. Types are declared
+	// from every file, in the order found, before annotations are parsed, so
+	// that annotations can use types declared in files parsed after them.
+	paths, err := sourceFiles(root, exclude)
+	if err != nil {
+		return err
+	}
+
+	var files []*ast.File
+	for _, scanned := range p.scanFiles(paths) {
+		if scanned.err != nil {
+			return scanned.err
+		}
+		if p.opts.Cache != nil {
+			p.opts.Cache.store(scanned.path, scanned.entry)
+		}
+		if scanned.file == nil {
+			for _, name := range scanned.entry.Types {
+				p.deferred[name] = scanned.path
+			}
+			continue
+		}
+		p.declareTypes(scanned.file)
+		files = append(files, scanned.file)
+	}
+	for _, f := range files {
+		if err := p.parseAnnotations(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sourceFiles returns the Go source files in the tree of root, skipping the
+// directory trees in exclude.
+func sourceFiles(root string, exclude []string) ([]string, error) {
+	var paths []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 		if info.IsDir() {
-			name := info.Name()
 			// Don't skip the root directory itself
-			if path != root {
-				// Skip vendor, hidden directories, and testdata
-				if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") {
-					return filepath.SkipDir
-				}
+			if path != root && (skipDir(info.Name()) || slices.Contains(exclude, filepath.Clean(path))) {
+				return filepath.SkipDir
 			}
 			return nil
 		}
-		// Skip non-Go files and test files
-		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
-			return nil
+		if isSourceFile(path) {
+			paths = append(paths, path)
 		}
-		return p.parseFile(path)
+		return nil
 	})
+	return paths, err
+}
+
+// skipDir reports whether a directory is never scanned: vendor, testdata, and hidden directories.
+func skipDir(name string) bool {
+	return name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".")
+}
+
+// isSourceFile reports whether path is a Go file that is not a test file.
+func isSourceFile(path string) bool {
+	return strings.HasSuffix(path, ".go") && !strings.HasSuffix(path, "_test.go")
+}
+
+// parseSource parses the Go source src of the file at path, or reads the
+// file if src is nil, as parser.ParseFile does.
+func (p *Parser) parseSource(path string, src any) error {
+	f, err := p.parseGo(path, src)
+	if err != nil {
+		return err
+	}
+	p.declareTypes(f)
+	return p.parseAnnotations(f)
+}
+
+// scannedFile is a Go file found by ParseDir, and what the cache records it
+// contains. It is not parsed if the cache records that it is unchanged and
+// has no annotations; it is then deferred until the types it declares are
+// used.
+type scannedFile struct {
+	path  string
+	file  *ast.File
+	entry cacheEntry
+	err   error
 }
 
-func (p *Parser) parseFile(path string) error {
-	f, err := parser.ParseFile(p.fset, path, nil, parser.ParseComments)
+// scanFiles parses the Go files at paths concurrently, up to GOMAXPROCS at a
+// time, returning them in the order of paths.
+func (p *Parser) scanFiles(paths []string) []scannedFile {
+	scanned := make([]scannedFile, len(paths))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), len(paths)) {
+		wg.Go(func() {
+			for i := range next {
+				scanned[i] = p.scanFile(paths[i])
+			}
+		})
+	}
+	for i := range paths {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return scanned
+}
+
+// scanFile parses the Go file at path, unless the cache records that it is
+// unchanged and has no annotations. It only reads the cache, so that files
+// can be scanned concurrently.
+func (p *Parser) scanFile(path string) scannedFile {
+	scanned := scannedFile{path: path}
+	if p.opts.Cache == nil {
+		scanned.file, scanned.err = p.parseGo(path, nil)
+		return scanned
+	}
+	info, err := os.Stat(path)
 	if err != nil {
-		return fmt.Errorf("failed to parse %s: %w", path, err)
+		scanned.err = err
+		return scanned
+	}
+	if entry, ok := p.opts.Cache.lookupStat(path, info); ok && skippable(entry) {
+		scanned.entry = entry
+		return scanned
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		scanned.err = err
+		return scanned
+	}
+	hash := contentHash(src)
+	entry, unchanged := p.opts.Cache.lookup(path, hash)
+	if unchanged && skippable(entry) {
+		scanned.entry = withStat(entry, info)
+		return scanned
+	}
+	scanned.file, scanned.err = p.parseGo(path, src)
+	if scanned.err == nil && !unchanged {
+		entry = cacheEntryOf(scanned.file, hash)
+	}
+	scanned.entry = withStat(entry, info)
+	return scanned
+}
+
+// skippable reports whether a file the cache records as unchanged need not
+// be parsed: it has neither annotations nor a build constraint.
+func skippable(entry cacheEntry) bool {
+	return !entry.Annotated && !entry.Constrained
+}
+
+// lookupType returns the declaration of the named type, parsing the file
+// declaring it if ParseDir deferred it.
+func (p *Parser) lookupType(name string) (*typeDecl, bool) {
+	if path, ok := p.deferred[name]; ok {
+		p.parseDeferred(path)
+	}
+	decl, ok := p.types[name]
+	return decl, ok
+}
+
+// parseDeferred declares the types of the deferred file at path that no file
+// parsed after it declares. A file that no longer parses declares none, as
+// it changed since it was found unchanged.
+func (p *Parser) parseDeferred(path string) {
+	pending := make(map[string]bool)
+	for name, file := range p.deferred {
+		if file == path {
+			pending[name] = true
+			delete(p.deferred, name)
+		}
+	}
+	f, err := p.parseGo(path, nil)
+	if err != nil {
+		return
+	}
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			if typeSpec := spec.(*ast.TypeSpec); pending[typeSpec.Name.Name] {
+				p.types[typeSpec.Name.Name] = &typeDecl{spec: typeSpec, doc: genDecl.Doc}
+			}
+		}
+	}
+}
+
+func (p *Parser) parseGo(path string, src any) (*ast.File, error) {
+	f, err := parser.ParseFile(p.fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// declareTypes records the types declared in f, unless f is left out as internal.
+func (p *Parser) declareTypes(f *ast.File) {
+	if p.excludedByBuildTags(f) && !p.opts.IncludeInternal {
+		return
+	}
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+				p.types[typeSpec.Name.Name] = &typeDecl{spec: typeSpec, doc: genDecl.Doc}
+				// the type is declared by this file, not one deferred before it
+				delete(p.deferred, typeSpec.Name.Name)
+			}
+		}
+	}
+}
+
+// parseAnnotations parses the annotations of f, declared with declareTypes.
+func (p *Parser) parseAnnotations(f *ast.File) error {
+	path := p.fset.File(f.Pos()).Name()
+	p.fileDir = filepath.Dir(path)
+	p.exampleErr = nil
+	p.internalFile = p.excludedByBuildTags(f)
+	if p.internalFile && !p.opts.IncludeInternal {
+		return nil
 	}
 
 	// Parse all comment groups for API-level annotations
@@ -136,16 +456,39 @@ func (p *Parser) parseFile(path string) error {
 		}
 	}
 
+	if p.exampleErr != nil {
+		return fmt.Errorf("failed to load example in %s: %w", path, p.exampleErr)
+	}
 	return nil
 }
 
+// parseComment parses the annotations of cg, localized for the locale of
+// the options. The annotations of a comment in an unchanged file come from
+// the cache, if any.
+func (p *Parser) parseComment(cg *ast.CommentGroup) ([]Annotation, []Diagnostic) {
+	if p.opts.Cache == nil {
+		annotations, diagnostics := p.annotationParser.ParseComment(p.fset, cg)
+		return localize(annotations, p.opts.Locale), diagnostics
+	}
+	pos := p.fset.Position(cg.Pos())
+	comment, ok := p.opts.Cache.comment(pos.Filename, pos.Offset)
+	if !ok {
+		comment.Annotations, comment.Diagnostics = p.annotationParser.ParseComment(p.fset, cg)
+		p.opts.Cache.storeComment(pos.Filename, pos.Offset, comment)
+	}
+	return localize(comment.Annotations, p.opts.Locale), comment.Diagnostics
+}
+
 func (p *Parser) parseCommentGroup(cg *ast.CommentGroup) {
 	if cg == nil {
 		return
 	}
-	text := cg.Text()
-
-	annotations := p.annotationParser.Parse(text)
+	p.serverTarget = -1
+	annotations, diagnostics := p.parseComment(cg)
+	p.diagnostics = append(p.diagnostics, diagnostics...)
+	p.apiComment = slices.ContainsFunc(annotations, func(a Annotation) bool {
+		return a.Type == AnnotationAPI || a.Type == AnnotationInfo
+	})
 	for _, a := range annotations {
 		p.handleAnnotation(a)
 	}
@@ -153,23 +496,54 @@ func (p *Parser) parseCommentGroup(cg *ast.CommentGroup) {
 
 func (p *Parser) handleAnnotation(a Annotation) {
 	handlers := map[AnnotationType]func(Annotation){
-		AnnotationAPI:          func(a Annotation) { p.spec.Version = GetAPI(a).Version },
-		AnnotationInfo:         p.handleInfo,
-		AnnotationContact:      p.handleContact,
-		AnnotationLicense:      p.handleLicense,
-		AnnotationServer:       p.handleServer,
-		AnnotationTag:          p.handleTag,
-		AnnotationTOS:          func(a Annotation) { p.spec.Info.TermsOfService = GetTOS(a).URL },
-		AnnotationSecurity:     p.handleSecurity,
-		AnnotationScope:        p.handleScope,
-		AnnotationExternalDocs: p.handleExternalDocs,
-		AnnotationLink:         p.handleLink,
+		AnnotationAPI:            func(a Annotation) { p.spec.Version = GetAPI(a).Version },
+		AnnotationInfo:           p.handleInfo,
+		AnnotationContact:        p.handleContact,
+		AnnotationLicense:        p.handleLicense,
+		AnnotationServer:         p.handleServer,
+		AnnotationServerVar:      p.handleServerVar,
+		AnnotationTag:            p.handleTag,
+		AnnotationTOS:            func(a Annotation) { p.spec.Info.TermsOfService = GetTOS(a).URL },
+		AnnotationSecurity:       p.handleSecurity,
+		AnnotationScope:          p.handleScope,
+		AnnotationExternalDocs:   p.handleExternalDocs,
+		AnnotationLink:           p.handleLink,
+		AnnotationExtension:      p.handleExtension,
+		AnnotationDescription:    p.handleDescription,
+		AnnotationOwner:          p.handleTagOwner,
+		AnnotationSharedParam:    p.handleSharedParam,
+		AnnotationSharedBody:     p.handleSharedBody,
+		AnnotationSharedResponse: p.handleSharedResponse,
 	}
 	if handler, ok := handlers[a.Type]; ok {
 		handler(a)
 	}
 }
 
+func (p *Parser) handleSharedParam(a Annotation) {
+	shared := GetSharedParam(a)
+	p.spec.Parameters[shared.Component] = p.buildParameter(shared.ParsedParam)
+}
+
+func (p *Parser) handleSharedBody(a Annotation) {
+	shared := GetSharedBody(a)
+	p.spec.Bodies[shared.Component] = p.buildRequestBody(shared.ParsedBody)
+}
+
+func (p *Parser) handleSharedResponse(a Annotation) {
+	shared := GetSharedResponse(a)
+	p.spec.Responses[shared.Component] = p.buildResponse(shared.ParsedResponse)
+}
+
+// handleTagOwner records the owner of the tags named by an !owner annotation with tag=.
+// Owners without tags belong to the enclosing operation and are applied there.
+func (p *Parser) handleTagOwner(a Annotation) {
+	owner := GetOwner(a)
+	for _, tag := range owner.Tags {
+		p.spec.TagOwners[tag] = &openapi.Owner{Team: owner.Team, Slack: owner.Slack, Email: owner.Email}
+	}
+}
+
 func (p *Parser) handleInfo(a Annotation) {
 	info := GetInfo(a)
 	p.spec.Info.Title = info.Title
@@ -200,6 +574,60 @@ func (p *Parser) handleServer(a Annotation) {
 		URL:         server.URL,
 		Description: server.Description,
 	})
+	p.serverTarget = len(p.spec.Servers) - 1
+}
+
+// handleServerVar adds a variable to the preceding !server of the same comment.
+func (p *Parser) handleServerVar(a Annotation) {
+	if p.serverTarget >= 0 {
+		addServerVariable(&p.spec.Servers[p.serverTarget], GetServerVar(a))
+	}
+}
+
+// handleExtension adds an extension to the document when written next to !api
+// or !info; elsewhere, !x extends the operation, model, or field it documents.
+func (p *Parser) handleExtension(a Annotation) {
+	if p.apiComment {
+		addExtension(&p.spec.Extensions, GetExtension(a))
+	}
+}
+
+// handleDescription sets the description of the API when written next to
+// !api or !info; elsewhere, !description describes the operation, model, or
+// field it documents.
+func (p *Parser) handleDescription(a Annotation) {
+	if p.apiComment {
+		p.spec.Info.Description = GetDescription(a)
+	}
+}
+
+func addExtension(extensions *openapi.Extensions, ext ParsedExtension) {
+	if *extensions == nil {
+		*extensions = make(openapi.Extensions)
+	}
+	(*extensions)[ext.Key] = ext.Value
+}
+
+// extensionsOf returns the extensions declared by the !x annotations, or nil.
+func extensionsOf(annotations []Annotation) openapi.Extensions {
+	var extensions openapi.Extensions
+	for _, a := range annotations {
+		if a.Type == AnnotationExtension {
+			addExtension(&extensions, GetExtension(a))
+		}
+	}
+	return extensions
+}
+
+func addServerVariable(server *openapi.Server, v ParsedServerVar) {
+	if server.Variables == nil {
+		server.Variables = make(map[string]openapi.ServerVariable)
+	}
+	server.Variables[v.Name] = openapi.ServerVariable{
+		Enum:        v.Enum,
+		Default:     v.Default,
+		Description: v.Description,
+	}
 }
 
 func (p *Parser) handleTag(a Annotation) {
@@ -225,6 +653,8 @@ func (p *Parser) handleSecurity(a Annotation) {
 	case "openIdConnect":
 		scheme.Type = "openIdConnect"
 		scheme.OpenIDConnectURL = sec.URL
+	case "mutualTLS":
+		scheme.Type = "mutualTLS"
 	}
 	p.spec.Securities[sec.Name] = scheme
 }
@@ -298,22 +728,132 @@ func (p *Parser) parseFuncDecl(fn *ast.FuncDecl) {
 		return
 	}
 
-	text := fn.Doc.Text()
-	if !strings.Contains(text, "!") {
+	if !strings.Contains(fn.Doc.Text(), "!") {
 		return
 	}
 
-	annotations := p.annotationParser.Parse(text)
+	// Diagnostics were reported with the file's other comments by parseCommentGroup.
+	annotations, _ := p.parseComment(fn.Doc)
 	if len(annotations) == 0 {
 		return
 	}
 
+	internal := p.isInternal(annotations)
+	if internal && !p.opts.IncludeInternal {
+		return
+	}
+
 	op := p.parseOperationAnnotations(annotations)
 	if op != nil {
+		op.Handler = handlerName(fn)
+		op.Source = p.fset.Position(fn.Pos())
+		if internal {
+			markInternal(&op.Extensions)
+		}
 		p.spec.Operations = append(p.spec.Operations, *op)
 	}
 }
 
+// isInternal reports whether the annotations document an internal-only
+// operation or model, one annotated with !ignore or in an internal file.
+func (p *Parser) isInternal(annotations []Annotation) bool {
+	return p.internalFile || slices.ContainsFunc(annotations, func(a Annotation) bool {
+		return a.Type == AnnotationIgnore
+	})
+}
+
+// markInternal marks an internal operation or model kept by IncludeInternal.
+func markInternal(extensions *openapi.Extensions) {
+	addExtension(extensions, ParsedExtension{Key: "x-internal", Value: true})
+}
+
+// excludedByBuildTags reports whether the //go:build constraint of f cannot
+// be satisfied without one of the excluded build tags, whatever the values
+// of its other tags.
+func (p *Parser) excludedByBuildTags(f *ast.File) bool {
+	if len(p.opts.ExcludeTags) == 0 {
+		return false
+	}
+	for _, cg := range f.Comments {
+		if cg.Pos() >= f.Package {
+			break
+		}
+		for _, c := range cg.List {
+			if !constraint.IsGoBuild(c.Text) {
+				continue
+			}
+			expr, err := constraint.Parse(c.Text)
+			if err != nil {
+				return false
+			}
+			return !satisfiableWithout(expr, p.opts.ExcludeTags)
+		}
+	}
+	return false
+}
+
+// satisfiableWithout reports whether expr holds for some values of its tags
+// with the excluded tags false. Constraints with more tags than are worth
+// enumerating are assumed satisfiable.
+func satisfiableWithout(expr constraint.Expr, excluded []string) bool {
+	var free []string
+	collectTags(expr, func(tag string) {
+		if !slices.Contains(excluded, tag) && !slices.Contains(free, tag) {
+			free = append(free, tag)
+		}
+	})
+	if len(free) > 16 {
+		return true
+	}
+	for values := 0; values < 1<<len(free); values++ {
+		ok := expr.Eval(func(tag string) bool {
+			i := slices.Index(free, tag)
+			return i >= 0 && values&(1<<i) != 0
+		})
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// collectTags calls fn with each tag in expr.
+func collectTags(expr constraint.Expr, fn func(tag string)) {
+	switch e := expr.(type) {
+	case *constraint.TagExpr:
+		fn(e.Tag)
+	case *constraint.NotExpr:
+		collectTags(e.X, fn)
+	case *constraint.AndExpr:
+		collectTags(e.X, fn)
+		collectTags(e.Y, fn)
+	case *constraint.OrExpr:
+		collectTags(e.X, fn)
+		collectTags(e.Y, fn)
+	}
+}
+
+// handlerName names a function declaration, qualifying methods with their receiver type.
+func handlerName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+	if ident, ok := typ.(*ast.Ident); ok {
+		return ident.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
 func (p *Parser) parseOperationAnnotations(annotations []Annotation) *OperationData {
 	op := &OperationData{Responses: make(openapi.Responses)}
 
@@ -328,17 +868,74 @@ func (p *Parser) parseOperationAnnotations(annotations []Annotation) *OperationD
 }
 
 func (p *Parser) applyOperationAnnotation(op *OperationData, a Annotation) {
-	switch a.Type {
-	case AnnotationRoute:
-		p.applyRouteAnnotation(op, a)
-	case AnnotationQuery, AnnotationPath, AnnotationHeader:
-		p.applyParamAnnotation(op, a)
-	case AnnotationBody:
-		p.applyBodyAnnotation(op, a)
-	case AnnotationOK, AnnotationError:
-		p.applyResponseAnnotation(op, a)
-	case AnnotationSecure:
-		p.applySecureAnnotation(op, a)
+	handlers := map[AnnotationType]func(*OperationData, Annotation){
+		AnnotationRoute:  p.applyRouteAnnotation,
+		AnnotationQuery:  p.applyParamAnnotation,
+		AnnotationPath:   p.applyParamAnnotation,
+		AnnotationHeader: p.applyParamAnnotation,
+		AnnotationBody: func(op *OperationData, a Annotation) {
+			p.applyBodyAnnotation(op, a)
+			op.exampleTarget = "body"
+		},
+		AnnotationForm: func(op *OperationData, a Annotation) {
+			p.applyFormAnnotation(op, a)
+			op.exampleTarget = "body"
+		},
+		AnnotationOK:      p.applyResponseAndTarget,
+		AnnotationError:   p.applyResponseAndTarget,
+		AnnotationExample: p.applyExampleAnnotation,
+		AnnotationSecure:  p.applySecureAnnotation,
+		AnnotationOpLink:  p.applyOpLinkAnnotation,
+		AnnotationOwner:   p.applyOwnerAnnotation,
+		AnnotationOpServer: func(op *OperationData, a Annotation) {
+			server := GetServer(a)
+			op.Servers = append(op.Servers, openapi.Server{URL: server.URL, Description: server.Description})
+		},
+		AnnotationServerVar: func(op *OperationData, a Annotation) {
+			if len(op.Servers) > 0 {
+				addServerVariable(&op.Servers[len(op.Servers)-1], GetServerVar(a))
+			}
+		},
+		AnnotationExtension: func(op *OperationData, a Annotation) {
+			addExtension(&op.Extensions, GetExtension(a))
+		},
+		AnnotationDescription: func(op *OperationData, a Annotation) {
+			op.Description = GetDescription(a)
+		},
+		AnnotationOpExternalDocs: func(op *OperationData, a Annotation) {
+			extDocs := GetExternalDocs(a)
+			op.ExternalDocs = &openapi.ExternalDocumentation{URL: extDocs.URL, Description: extDocs.Description}
+		},
+		AnnotationParamRef: func(op *OperationData, a Annotation) {
+			for _, name := range GetRef(a).Components {
+				op.Parameters = append(op.Parameters, openapi.RefToParameter(name))
+			}
+		},
+		AnnotationBodyRef: func(op *OperationData, a Annotation) {
+			op.RequestBody = openapi.RefToRequestBody(GetRef(a).Components[0])
+			op.exampleTarget = "" // examples belong to the shared body
+		},
+		AnnotationResponseRef: func(op *OperationData, a Annotation) {
+			ref := GetRef(a)
+			op.setResponse(ref.Status, openapi.RefToResponse(ref.Components[0]))
+			op.exampleTarget = ""
+		},
+	}
+	if handler, ok := handlers[a.Type]; ok {
+		handler(op, a)
+	}
+}
+
+// applyResponseAndTarget applies a response and directs the following !example annotations to it.
+func (p *Parser) applyResponseAndTarget(op *OperationData, a Annotation) {
+	p.applyResponseAnnotation(op, a)
+	op.exampleTarget = GetResponse(a).Status
+}
+
+// applyOwnerAnnotation sets the owner of the operation; owners with tag= are handled by handleTagOwner.
+func (p *Parser) applyOwnerAnnotation(op *OperationData, a Annotation) {
+	if owner := GetOwner(a); len(owner.Tags) == 0 {
+		op.Owner = &openapi.Owner{Team: owner.Team, Slack: owner.Slack, Email: owner.Email}
 	}
 }
 
@@ -352,37 +949,262 @@ func (p *Parser) applyRouteAnnotation(op *OperationData, a Annotation) {
 }
 
 func (p *Parser) applyParamAnnotation(op *OperationData, a Annotation) {
-	param := GetParam(a)
-	op.Parameters = append(op.Parameters, &openapi.Parameter{
+	op.Parameters = append(op.Parameters, p.buildParameter(GetParam(a)))
+}
+
+func (p *Parser) buildParameter(param ParsedParam) *openapi.Parameter {
+	return &openapi.Parameter{
 		Name:        param.Name,
 		In:          openapi.ParameterLocation(param.In),
 		Description: param.Description,
 		Required:    param.Required || param.In == "path",
 		Schema:      p.typeToSchema(param.Type),
 		Example:     parseDefaultValue(param.Default),
-	})
+	}
 }
 
 func (p *Parser) applyBodyAnnotation(op *OperationData, a Annotation) {
-	body := GetBody(a)
-	op.RequestBody = &openapi.RequestBody{
+	op.RequestBody = p.buildRequestBody(GetBody(a))
+}
+
+func (p *Parser) buildRequestBody(body ParsedBody) *openapi.RequestBody {
+	return &openapi.RequestBody{
 		Description: body.Description,
 		Required:    body.Required,
-		Content: map[string]openapi.MediaType{
-			"application/json": {Schema: p.parseSchemaRef(body.Schema)},
-		},
+		Content:     p.mediaTypes(body.ContentTypes, p.parseSchemaRef(body.Schema)),
+	}
+}
+
+// Media types of form request bodies.
+const (
+	mediaTypeFormURLEncoded = "application/x-www-form-urlencoded"
+	mediaTypeMultipart      = "multipart/form-data"
+)
+
+// applyFormAnnotation adds a field to the form of the request body. The form
+// is sent as multipart/form-data once a field is a file (binary) or declares
+// the content types of its part, and as application/x-www-form-urlencoded
+// otherwise. Other content declared with !body is kept.
+func (p *Parser) applyFormAnnotation(op *OperationData, a Annotation) {
+	field := GetFormField(a)
+	if op.RequestBody == nil {
+		op.RequestBody = &openapi.RequestBody{}
+	}
+	if op.RequestBody.Content == nil {
+		op.RequestBody.Content = make(map[string]openapi.MediaType)
+	}
+
+	current, mt := formMediaType(op.RequestBody.Content)
+	if mt.Schema == nil {
+		mt.Schema = &openapi.Schema{Type: openapi.NewSchemaType(openapi.TypeObject), Properties: make(map[string]*openapi.Schema)}
+	}
+	schema := p.parseSchemaRef(field.Type)
+	schema.Description = field.Description
+	if _, ok := mt.Schema.Properties[field.Name]; !ok {
+		mt.Schema.PropertyOrder = append(mt.Schema.PropertyOrder, field.Name)
+	}
+	mt.Schema.Properties[field.Name] = schema
+	if field.Required {
+		mt.Schema.Required = append(mt.Schema.Required, field.Name)
+		op.RequestBody.Required = true
+	}
+	if len(field.ContentTypes) > 0 {
+		if mt.Encoding == nil {
+			mt.Encoding = make(map[string]openapi.Encoding)
+		}
+		mt.Encoding[field.Name] = openapi.Encoding{ContentType: strings.Join(field.ContentTypes, ", ")}
+	}
+
+	contentType := mediaTypeFormURLEncoded
+	if len(mt.Encoding) > 0 || hasFileProperty(mt.Schema) {
+		contentType = mediaTypeMultipart
+	}
+	delete(op.RequestBody.Content, current)
+	op.RequestBody.Content[contentType] = mt
+}
+
+// formMediaType returns the form media type of content and its key, if any.
+func formMediaType(content map[string]openapi.MediaType) (string, openapi.MediaType) {
+	for _, contentType := range []string{mediaTypeMultipart, mediaTypeFormURLEncoded} {
+		if mt, ok := content[contentType]; ok {
+			return contentType, mt
+		}
+	}
+	return "", openapi.MediaType{}
+}
+
+// hasFileProperty reports whether any property of schema is binary or an array of binaries.
+func hasFileProperty(schema *openapi.Schema) bool {
+	for _, prop := range schema.Properties {
+		if prop.Format == "binary" || prop.Items != nil && prop.Items.Format == "binary" {
+			return true
+		}
 	}
+	return false
 }
 
 func (p *Parser) applyResponseAnnotation(op *OperationData, a Annotation) {
 	resp := GetResponse(a)
+	response := p.buildResponse(resp)
+	if existing := op.Responses[resp.Status]; existing != nil {
+		response.Links = existing.Links
+	}
+	op.setResponse(resp.Status, response)
+}
+
+// setResponse sets the response for status, recording the order in which statuses are declared.
+func (op *OperationData) setResponse(status string, response *openapi.Response) {
+	if _, ok := op.Responses[status]; !ok {
+		op.ResponseOrder = append(op.ResponseOrder, status)
+	}
+	op.Responses[status] = response
+}
+
+func (p *Parser) buildResponse(resp ParsedResponse) *openapi.Response {
 	response := &openapi.Response{Description: resp.Description}
-	if resp.Schema != "" && resp.Schema != "-" && resp.Schema != "nil" && resp.Schema != "none" {
-		response.Content = map[string]openapi.MediaType{
-			"application/json": {Schema: p.parseSchemaRef(resp.Schema)},
+	switch {
+	case resp.Schema != "" && resp.Schema != "-" && resp.Schema != "nil" && resp.Schema != "none":
+		response.Content = p.mediaTypes(resp.ContentTypes, p.parseSchemaRef(resp.Schema))
+	case len(resp.ContentTypes) > 0:
+		// a schemaless response with explicit types, e.g. a file download
+		response.Content = p.mediaTypes(resp.ContentTypes, nil)
+	}
+	return response
+}
+
+// mediaTypes returns content with schema under each of contentTypes,
+// defaulting to application/json.
+func (p *Parser) mediaTypes(contentTypes []string, schema *openapi.Schema) map[string]openapi.MediaType {
+	if len(contentTypes) == 0 {
+		contentTypes = []string{"application/json"}
+	}
+	content := make(map[string]openapi.MediaType, len(contentTypes))
+	for _, contentType := range contentTypes {
+		content[contentType] = openapi.MediaType{Schema: schema}
+	}
+	return content
+}
+
+// applyOpLinkAnnotation adds a link to the response with the annotation's
+// status, creating the response if it has not been declared (yet).
+func (p *Parser) applyOpLinkAnnotation(op *OperationData, a Annotation) {
+	link := GetOpLink(a)
+	response := op.Responses[link.Status]
+	if response == nil {
+		code, _ := strconv.Atoi(link.Status)
+		response = &openapi.Response{Description: http.StatusText(code)}
+		op.setResponse(link.Status, response)
+	}
+	if response.Links == nil {
+		response.Links = make(map[string]*openapi.Link)
+	}
+
+	target := &openapi.Link{OperationID: link.OperationID, Description: link.Description}
+	for name, value := range link.Parameters {
+		if target.Parameters == nil {
+			target.Parameters = make(map[string]any)
 		}
+		target.Parameters[name] = linkParameterValue(value)
+	}
+	response.Links[link.OperationID] = target
+}
+
+// linkParameterValue keeps runtime expressions ($request..., $response...) as
+// strings and converts constant values to their natural type.
+func linkParameterValue(value string) any {
+	if strings.HasPrefix(value, "$") {
+		return value
 	}
-	op.Responses[resp.Status] = response
+	return parseValue(value)
+}
+
+// applyExampleAnnotation adds a named example to the media types of the
+// request body or response declared last. Examples before any !body, !ok,
+// or !error line are ignored.
+func (p *Parser) applyExampleAnnotation(op *OperationData, a Annotation) {
+	content := p.exampleContent(op)
+	if content == nil {
+		return
+	}
+	example := GetExample(a)
+	value, err := p.exampleValue(example)
+	if err != nil {
+		if p.exampleErr == nil {
+			p.exampleErr = err
+		}
+		return
+	}
+
+	name := example.Name
+	if name == "" && example.File != "" {
+		name = strings.TrimSuffix(filepath.Base(example.File), filepath.Ext(example.File))
+	}
+	if name == "" {
+		name = "default"
+	}
+	for contentType, mt := range content {
+		if mt.Examples == nil {
+			mt.Examples = make(map[string]*openapi.Example)
+		}
+		mt.Examples[name] = &openapi.Example{Value: value}
+		content[contentType] = mt
+	}
+}
+
+// exampleContent returns the content of the example target, adding a JSON
+// media type to responses declared without a schema.
+func (p *Parser) exampleContent(op *OperationData) map[string]openapi.MediaType {
+	if op.exampleTarget == "body" {
+		return op.RequestBody.Content
+	}
+	response := op.Responses[op.exampleTarget]
+	if response == nil {
+		return nil
+	}
+	if response.Content == nil {
+		response.Content = map[string]openapi.MediaType{"application/json": {}}
+	}
+	return response.Content
+}
+
+// exampleValue decodes an inline or file example: JSON and YAML are parsed,
+// anything else is kept as a string.
+func (p *Parser) exampleValue(example ParsedExample) (any, error) {
+	if example.File == "" {
+		var value any
+		if err := json.Unmarshal([]byte(example.Value), &value); err != nil {
+			return example.Value, nil
+		}
+		return value, nil
+	}
+
+	path := example.File
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(p.fileDir, path)
+	}
+	// Devices and pipes such as /dev/zero would never finish reading
+	if info, err := os.Stat(path); err != nil {
+		return nil, err
+	} else if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s: not a regular file", example.File)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &value)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &value)
+	default:
+		value = string(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", example.File, err)
+	}
+	return value, nil
 }
 
 func (p *Parser) applySecureAnnotation(op *OperationData, a Annotation) {
@@ -404,36 +1226,174 @@ func (p *Parser) parseTypeDecl(decl *ast.GenDecl) {
 			docText = decl.Doc.Text()
 		}
 
-		// Only process types with !model annotation
-		if !strings.Contains(docText, "!model") {
+		// Only process types with !model annotation. Generic types become
+		// a model per instance, named for its type arguments where used.
+		if !strings.Contains(docText, "!model") || typeSpec.TypeParams != nil {
 			continue
 		}
 
-		structType, ok := typeSpec.Type.(*ast.StructType)
-		if !ok {
-			continue
+		p.declareModel(typeSpec, decl.Doc)
+	}
+}
+
+// declareModel stores the schema of the model typeSpec, annotated by doc,
+// and of its views, unless it is internal and internal models are left out.
+func (p *Parser) declareModel(typeSpec *ast.TypeSpec, doc *ast.CommentGroup) {
+	annotations, _ := p.parseComment(doc)
+	internal := p.isInternal(annotations)
+	if internal && !p.opts.IncludeInternal {
+		return
+	}
+	for _, a := range annotations {
+		if a.Type == AnnotationModel {
+			schemaData := p.buildModel(typeSpec.Name.Name, typeSpec, GetModel(a), annotations)
+			if internal {
+				markInternal(&schemaData.Schema.Extensions)
+			}
+
+			// Store schema globally by type name
+			p.globalSchemas[typeSpec.Name.Name] = schemaData
+		}
+	}
+	for _, a := range annotations {
+		if model, ok := p.globalSchemas[typeSpec.Name.Name]; ok && a.Type == AnnotationView {
+			view := GetView(a)
+			p.globalSchemas[view.Name] = deriveView(model, view)
+			p.checkView(typeSpec.Name.Name, model, view, a.Pos)
 		}
+	}
+}
 
-		annotations := p.annotationParser.Parse(docText)
-		for _, a := range annotations {
-			if a.Type == AnnotationModel {
-				model := GetModel(a)
-				schemaData := &SchemaData{
-					Name:        typeSpec.Name.Name,
-					Description: model.Description,
-					Schema:      p.structToSchema(structType, docText),
-					Examples:    make(map[string]any),
-				}
-				schemaData.Schema.Description = model.Description
+// checkView reports the properties a view of the model name names that the
+// model does not declare itself, such as those of the models it is composed
+// with, which the view cannot pick or omit.
+func (p *Parser) checkView(name string, model *SchemaData, view ParsedView, pos token.Position) {
+	own := model.Schema.Properties
+	if allOf := model.Schema.AllOf; len(allOf) > 0 {
+		own = allOf[len(allOf)-1].Properties
+		if allOf[len(allOf)-1].Ref != "" {
+			own = nil
+		}
+	}
+	seen := make(map[string]bool)
+	for _, names := range [][]string{view.Pick, view.Omit, view.Required, view.Optional} {
+		for _, prop := range names {
+			if _, ok := own[prop]; ok || seen[prop] {
+				continue
+			}
+			seen[prop] = true
+			p.diagnostics = append(p.diagnostics, Diagnostic{
+				Pos:     pos,
+				Message: fmt.Sprintf("!view %s: %s declares no property %s; views only apply to the properties a model declares itself", view.Name, name, prop),
+			})
+		}
+	}
+}
 
-				// Parse field annotations from struct fields
-				p.parseStructFieldAnnotations(structType, schemaData)
+// deriveView derives a variant of a model declared with !view. The view of
+// a model composed with allOf applies to the properties it declares itself.
+func deriveView(model *SchemaData, view ParsedView) *SchemaData {
+	var schema *openapi.Schema
+	if allOf := model.Schema.AllOf; len(allOf) > 0 {
+		derived := *model.Schema
+		derived.AllOf = slices.Clone(allOf)
+		if own := allOf[len(allOf)-1]; own.Ref == "" {
+			derived.AllOf[len(allOf)-1] = viewOf(own, view)
+		}
+		schema = &derived
+	} else {
+		schema = viewOf(model.Schema, view)
+	}
+	if view.Description != "" {
+		schema.Description = view.Description
+	}
+	return &SchemaData{
+		Name:        view.Name,
+		Description: schema.Description,
+		Schema:      schema,
+		Examples:    make(map[string]any),
+	}
+}
 
-				// Store schema globally by struct type name
-				p.globalSchemas[typeSpec.Name.Name] = schemaData
+// viewOf returns a copy of the object schema with the properties and
+// required properties of view.
+func viewOf(schema *openapi.Schema, view ParsedView) *openapi.Schema {
+	out := *schema
+	out.Properties = make(map[string]*openapi.Schema)
+	out.PropertyOrder = nil
+	for _, name := range schema.PropertyOrder {
+		if view.keeps(name) {
+			out.Properties[name] = schema.Properties[name]
+			out.PropertyOrder = append(out.PropertyOrder, name)
+		}
+	}
+	out.Required = viewRequired(schema.Required, out.Properties, view)
+	return &out
+}
+
+// keeps reports whether the view keeps the property name of its model.
+func (v ParsedView) keeps(name string) bool {
+	return (len(v.Pick) == 0 || slices.Contains(v.Pick, name)) && !slices.Contains(v.Omit, name)
+}
+
+// viewRequired returns the required properties of a view with properties:
+// those of the model it keeps and does not make optional, then those it
+// makes required.
+func viewRequired(required []string, properties map[string]*openapi.Schema, view ParsedView) []string {
+	var out []string
+	for _, name := range required {
+		if view.keeps(name) && !slices.Contains(view.Optional, name) {
+			out = append(out, name)
+		}
+	}
+	for _, name := range view.Required {
+		if _, ok := properties[name]; ok && !slices.Contains(out, name) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// buildModel builds the schema of the model name declared by typeSpec: an
+// object for a struct, or the schema of the underlying type otherwise.
+func (p *Parser) buildModel(name string, typeSpec *ast.TypeSpec, model ParsedModel, annotations []Annotation) *SchemaData {
+	description := model.Description
+	for _, a := range annotations {
+		if a.Type == AnnotationDescription {
+			description = GetDescription(a)
+		}
+	}
+	schemaData := &SchemaData{
+		Name:        name,
+		Description: description,
+		Examples:    make(map[string]any),
+	}
+	var bases []*openapi.Schema
+	structType, isStruct := typeSpec.Type.(*ast.StructType)
+	if isStruct {
+		schemaData.Schema, bases = p.structToSchema(structType, "")
+
+		// Parse field annotations from struct fields
+		p.parseStructFieldAnnotations(structType, schemaData)
+	} else {
+		schemaData.Schema = p.astTypeToSchema(typeSpec.Type)
+	}
+	if model.AdditionalProperties != "" {
+		schemaData.Schema.AdditionalProperties = p.parseSchemaRef(model.AdditionalProperties)
+	}
+	for _, a := range annotations {
+		if a.Type == AnnotationExtends {
+			for _, name := range GetExtends(a) {
+				bases = append(bases, p.parseSchemaRef(name))
 			}
 		}
 	}
+	if len(bases) > 0 {
+		schemaData.Schema = composeAllOf(bases, schemaData.Schema)
+	}
+	schemaData.Schema.Description = description
+	schemaData.Schema.Extensions = extensionsOf(annotations)
+	return schemaData
 }
 
 func (p *Parser) parseStructFieldAnnotations(structType *ast.StructType, schemaData *SchemaData) {
@@ -464,10 +1424,19 @@ func (p *Parser) applyFieldAnnotations(field *ast.Field, jsonName string, schema
 	if field.Doc == nil {
 		return
 	}
-	annotations := p.annotationParser.Parse(field.Doc.Text())
+	annotations, _ := p.parseComment(field.Doc)
 	for _, a := range annotations {
-		if a.Type == AnnotationField {
+		switch a.Type {
+		case AnnotationField:
 			p.applyFieldInfo(jsonName, GetField(a), schemaData)
+		case AnnotationExtension:
+			if propSchema, ok := schemaData.Schema.Properties[jsonName]; ok {
+				addExtension(&propSchema.Extensions, GetExtension(a))
+			}
+		case AnnotationDescription:
+			if propSchema, ok := schemaData.Schema.Properties[jsonName]; ok {
+				propSchema.Description = GetDescription(a)
+			}
 		}
 	}
 }
@@ -477,6 +1446,13 @@ func (p *Parser) applyFieldInfo(jsonName string, fieldInfo ParsedField, schemaDa
 	if !ok {
 		return
 	}
+	// Map types replace the inferred schema, as in !field counts:map[string]integer
+	if strings.HasPrefix(fieldInfo.Type, "map[") {
+		mapSchema := p.parseSchemaRef(fieldInfo.Type)
+		mapSchema.Description = propSchema.Description
+		propSchema = mapSchema
+		schemaData.Schema.Properties[jsonName] = propSchema
+	}
 	// Only override description if annotation provides one
 	if fieldInfo.Description != "" {
 		propSchema.Description = fieldInfo.Description
@@ -484,32 +1460,50 @@ func (p *Parser) applyFieldInfo(jsonName string, fieldInfo ParsedField, schemaDa
 	if fieldInfo.Example != "" {
 		propSchema.Example = parseValue(fieldInfo.Example)
 	}
+	applyFieldFlags(propSchema, fieldInfo)
+	if fieldInfo.Format != "" {
+		propSchema.Format = fieldInfo.Format
+	}
 	if fieldInfo.Required && !slices.Contains(schemaData.Schema.Required, jsonName) {
 		schemaData.Schema.Required = append(schemaData.Schema.Required, jsonName)
 	}
 }
 
-func (p *Parser) structToSchema(structType *ast.StructType, docText string) *openapi.Schema {
+// applyFieldFlags sets the deprecated, nullable, readOnly, and writeOnly
+// flags a !field annotation declares on the property schema.
+func applyFieldFlags(propSchema *openapi.Schema, fieldInfo ParsedField) {
+	propSchema.Deprecated = propSchema.Deprecated || fieldInfo.Deprecated
+	propSchema.Nullable = propSchema.Nullable || fieldInfo.Nullable
+	propSchema.ReadOnly = propSchema.ReadOnly || fieldInfo.ReadOnly
+	propSchema.WriteOnly = propSchema.WriteOnly || fieldInfo.WriteOnly
+}
+
+// structToSchema builds the object schema of a struct. Embedded models are
+// returned as the bases to compose it with, while the fields of other
+// embedded structs are promoted into the object, as encoding/json does.
+func (p *Parser) structToSchema(structType *ast.StructType, docText string) (*openapi.Schema, []*openapi.Schema) {
 	schema := &openapi.Schema{
 		Type:       openapi.NewSchemaType(openapi.TypeObject),
 		Properties: make(map[string]*openapi.Schema),
 	}
+	var embedded []ast.Expr
 
 	for _, field := range structType.Fields.List {
-		if len(field.Names) == 0 {
-			continue
-		}
-		fieldName := field.Names[0].Name
 		jsonName := getJSONTagName(field)
 		if jsonName == "-" {
 			continue
 		}
+		if len(field.Names) == 0 && jsonName == "" {
+			embedded = append(embedded, field.Type)
+			continue
+		}
 		if jsonName == "" {
-			jsonName = fieldName
+			jsonName = field.Names[0].Name
 		}
 
 		fieldSchema := p.fieldToSchema(field)
 		schema.Properties[jsonName] = fieldSchema
+		schema.PropertyOrder = append(schema.PropertyOrder, jsonName)
 
 		// Add to required if not omitempty
 		if !strings.Contains(getJSONTag(field), "omitempty") {
@@ -517,7 +1511,69 @@ func (p *Parser) structToSchema(structType *ast.StructType, docText string) *ope
 		}
 	}
 
-	return schema
+	// Promoted fields come after, as fields of the struct itself take precedence
+	var bases []*openapi.Schema
+	for _, expr := range embedded {
+		bases = append(bases, p.embed(expr, schema)...)
+	}
+	return schema, bases
+}
+
+// embed adds the struct embedded as expr to the object schema: it returns
+// a reference to an embedded model, or promotes the fields of any other
+// struct declared in the parsed files into schema, keeping the fields
+// schema already has.
+func (p *Parser) embed(expr ast.Expr, schema *openapi.Schema) []*openapi.Schema {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		if structType := p.promotable(ident.Name); structType != nil {
+			p.resolving[ident.Name] = true
+			defer delete(p.resolving, ident.Name)
+			saved := p.typeArgs
+			p.typeArgs = nil
+			defer func() { p.typeArgs = saved }()
+
+			promoted, bases := p.structToSchema(structType, "")
+			for _, name := range promoted.PropertyOrder {
+				if _, exists := schema.Properties[name]; exists {
+					continue
+				}
+				schema.Properties[name] = promoted.Properties[name]
+				schema.PropertyOrder = append(schema.PropertyOrder, name)
+				if slices.Contains(promoted.Required, name) {
+					schema.Required = append(schema.Required, name)
+				}
+			}
+			return bases
+		}
+	}
+	if base := p.astTypeToSchema(expr); base.Ref != "" {
+		return []*openapi.Schema{base}
+	}
+	return nil
+}
+
+// promotable returns the struct type declared as name if its fields are
+// promoted where it is embedded: it is not a model or a generic type.
+func (p *Parser) promotable(name string) *ast.StructType {
+	decl, ok := p.lookupType(name)
+	if !ok || decl.model() || decl.spec.TypeParams != nil || p.resolving[name] {
+		return nil
+	}
+	structType, _ := decl.spec.Type.(*ast.StructType)
+	return structType
+}
+
+// composeAllOf composes the schema of a model with the models it extends.
+// The schema is left out if it is an object without properties.
+func composeAllOf(bases []*openapi.Schema, schema *openapi.Schema) *openapi.Schema {
+	allOf := slices.Clone(bases)
+	if len(schema.Properties) > 0 || !slices.Equal(schema.Type, openapi.NewSchemaType(openapi.TypeObject)) {
+		allOf = append(allOf, schema)
+	}
+	return &openapi.Schema{AllOf: allOf}
 }
 
 func (p *Parser) fieldToSchema(field *ast.Field) *openapi.Schema {
@@ -559,6 +1615,10 @@ func (p *Parser) astTypeToSchema(expr ast.Expr) *openapi.Schema {
 		return p.mapTypeToSchema(t)
 	case *ast.SelectorExpr:
 		return p.selectorExprToSchema(t)
+	case *ast.IndexExpr:
+		return p.instanceToSchema(t.X, []ast.Expr{t.Index})
+	case *ast.IndexListExpr:
+		return p.instanceToSchema(t.X, t.Indices)
 	default:
 		return &openapi.Schema{}
 	}
@@ -570,16 +1630,27 @@ func (p *Parser) starExprToSchema(t *ast.StarExpr) *openapi.Schema {
 		schema.Nullable = true
 		return schema
 	}
+	if _, ok := t.X.(*ast.SelectorExpr); ok || isInstance(t.X) {
+		schema := p.astTypeToSchema(t.X)
+		schema.Nullable = true
+		return schema
+	}
 	return &openapi.Schema{}
 }
 
 func (p *Parser) arrayTypeToSchema(t *ast.ArrayType) *openapi.Schema {
+	// encoding/json encodes byte slices as base64 strings
+	if ident, ok := t.Elt.(*ast.Ident); ok && t.Len == nil && (ident.Name == "byte" || ident.Name == "uint8") {
+		return &openapi.Schema{Type: openapi.NewSchemaType(openapi.TypeString), Format: "byte"}
+	}
 	schema := &openapi.Schema{Type: openapi.NewSchemaType(openapi.TypeArray)}
-	schema.Items = p.extractArrayItemSchema(t.Elt)
+	schema.Items = p.elementSchema(t.Elt)
 	return schema
 }
 
-func (p *Parser) extractArrayItemSchema(elt ast.Expr) *openapi.Schema {
+// elementSchema resolves the schema of the elements of an array or the
+// values of a map, or returns nil if the type is not supported.
+func (p *Parser) elementSchema(elt ast.Expr) *openapi.Schema {
 	if ident, ok := elt.(*ast.Ident); ok {
 		return p.typeToSchema(ident.Name)
 	}
@@ -587,15 +1658,17 @@ func (p *Parser) extractArrayItemSchema(elt ast.Expr) *openapi.Schema {
 		if ident, ok := star.X.(*ast.Ident); ok {
 			return p.typeToSchema(ident.Name)
 		}
+		elt = star.X
+	}
+	if _, ok := elt.(*ast.SelectorExpr); ok || isInstance(elt) {
+		return p.astTypeToSchema(elt)
 	}
 	return nil
 }
 
 func (p *Parser) mapTypeToSchema(t *ast.MapType) *openapi.Schema {
 	schema := &openapi.Schema{Type: openapi.NewSchemaType(openapi.TypeObject)}
-	if valIdent, ok := t.Value.(*ast.Ident); ok {
-		schema.AdditionalProperties = p.typeToSchema(valIdent.Name)
-	}
+	schema.AdditionalProperties = p.elementSchema(t.Value)
 	return schema
 }
 
@@ -604,15 +1677,23 @@ func (p *Parser) selectorExprToSchema(t *ast.SelectorExpr) *openapi.Schema {
 	if !ok {
 		return &openapi.Schema{}
 	}
-	if x.Name == "time" && t.Sel.Name == "Time" {
-		return &openapi.Schema{Type: openapi.NewSchemaType(openapi.TypeString), Format: "date-time"}
-	}
-	if x.Name == "uuid" && t.Sel.Name == "UUID" {
-		return &openapi.Schema{Type: openapi.NewSchemaType(openapi.TypeString), Format: "uuid"}
+	if info, ok := wellKnownTypes[x.Name+"."+t.Sel.Name]; ok {
+		return &openapi.Schema{Type: openapi.NewSchemaType(info.schemaType), Format: info.format}
 	}
 	return &openapi.Schema{}
 }
 
+// wellKnownTypes maps types of other packages, by package and type name,
+// to the schemas of their JSON encodings.
+var wellKnownTypes = map[string]schemaTypeInfo{
+	"time.Time":     {openapi.TypeString, "date-time"},
+	"time.Duration": {openapi.TypeInteger, "int64"},
+	"uuid.UUID":     {openapi.TypeString, "uuid"},
+	"net.IP":        {openapi.TypeString, "ipv4"},
+	"netip.Addr":    {openapi.TypeString, "ipv4"},
+	"url.URL":       {openapi.TypeString, "uri"},
+}
+
 // schemaTypeInfo holds OpenAPI schema type and format.
 type schemaTypeInfo struct {
 	schemaType string
@@ -641,6 +1722,7 @@ var typeSchemaMapping = map[string]schemaTypeInfo{
 	"bool":        {openapi.TypeBoolean, ""},
 	"boolean":     {openapi.TypeBoolean, ""},
 	"byte":        {openapi.TypeString, "byte"},
+	"binary":      {openapi.TypeString, "binary"},
 	"any":         {openapi.TypeObject, ""},
 	"interface{}": {openapi.TypeObject, ""},
 	"object":      {openapi.TypeObject, ""},
@@ -648,29 +1730,221 @@ var typeSchemaMapping = map[string]schemaTypeInfo{
 }
 
 func (p *Parser) typeToSchema(typeName string) *openapi.Schema {
+	if arg, ok := p.typeArgs[typeName]; ok {
+		return p.schemaInScope(nil, arg)
+	}
 	if info, ok := typeSchemaMapping[typeName]; ok {
 		return &openapi.Schema{Type: openapi.NewSchemaType(info.schemaType), Format: info.format}
 	}
+	if decl, ok := p.lookupType(typeName); ok && p.inlined(decl) && !p.resolving[typeName] {
+		// Aliases and named non-struct types that are not models, such as
+		// type Status string, are replaced by their underlying type
+		p.resolving[typeName] = true
+		defer delete(p.resolving, typeName)
+		return p.schemaInScope(nil, decl.spec.Type)
+	}
 	return openapi.RefTo(typeName)
 }
 
+// inlined reports whether references to the declared type are replaced by
+// its underlying type: it is an alias or a non-struct type, and not a model.
+func (p *Parser) inlined(decl *typeDecl) bool {
+	if decl.spec.TypeParams != nil || decl.model() {
+		return false
+	}
+	_, isStruct := decl.spec.Type.(*ast.StructType)
+	return decl.spec.Assign.IsValid() || !isStruct
+}
+
+// schemaInScope converts expr to a schema with the type parameters of a
+// generic type bound to args, or with none bound if args is nil.
+func (p *Parser) schemaInScope(args map[string]ast.Expr, expr ast.Expr) *openapi.Schema {
+	saved := p.typeArgs
+	p.typeArgs = args
+	defer func() { p.typeArgs = saved }()
+	return p.astTypeToSchema(expr)
+}
+
+// isInstance reports whether expr instantiates a generic type, as in Page[User].
+func isInstance(expr ast.Expr) bool {
+	switch expr.(type) {
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		return true
+	}
+	return false
+}
+
+// instanceToSchema resolves an instance of the generic type base. An
+// instance of a generic struct becomes a model named for its type
+// arguments, such as PageOfUser for Page[User], and is referenced; an
+// instance of any other generic type is replaced by its underlying type.
+func (p *Parser) instanceToSchema(base ast.Expr, args []ast.Expr) *openapi.Schema {
+	ident, ok := base.(*ast.Ident)
+	if !ok {
+		return &openapi.Schema{}
+	}
+	args = slices.Clone(args)
+	for i, arg := range args {
+		args[i] = p.substitute(arg)
+	}
+	name := instanceName(ident.Name, args)
+
+	decl, ok := p.lookupType(ident.Name)
+	if !ok || decl.spec.TypeParams == nil {
+		return openapi.RefTo(name)
+	}
+	params := typeParamNames(decl.spec)
+	if len(params) != len(args) {
+		return &openapi.Schema{}
+	}
+	if p.skipInstance(name) {
+		return openapi.RefTo(name)
+	}
+	bindings := make(map[string]ast.Expr, len(params))
+	for i, param := range params {
+		bindings[param] = args[i]
+	}
+
+	p.resolving[name] = true
+	defer delete(p.resolving, name)
+	saved := p.typeArgs
+	p.typeArgs = bindings
+	defer func() { p.typeArgs = saved }()
+
+	if _, isStruct := decl.spec.Type.(*ast.StructType); !isStruct {
+		return p.astTypeToSchema(decl.spec.Type)
+	}
+	p.instances++
+	p.globalSchemas[name] = p.buildInstance(name, decl)
+	return openapi.RefTo(name)
+}
+
+// skipInstance reports whether the instance name is only referenced: if it
+// is already built or being built, or the limits of instances are reached.
+func (p *Parser) skipInstance(name string) bool {
+	_, exists := p.globalSchemas[name]
+	return exists || p.resolving[name] || len(p.resolving) >= maxInstanceDepth || p.instances >= maxInstances
+}
+
+// typeParamNames returns the names of the type parameters of spec.
+func typeParamNames(spec *ast.TypeSpec) []string {
+	var params []string
+	for _, field := range spec.TypeParams.List {
+		for _, param := range field.Names {
+			params = append(params, param.Name)
+		}
+	}
+	return params
+}
+
+// buildInstance builds the model name of an instance of the generic struct
+// decl, with the annotations of decl, once its type arguments are bound.
+func (p *Parser) buildInstance(name string, decl *typeDecl) *SchemaData {
+	var model ParsedModel
+	var annotations []Annotation
+	if decl.doc != nil {
+		annotations, _ = p.parseComment(decl.doc)
+	}
+	for _, a := range annotations {
+		if a.Type == AnnotationModel {
+			model = GetModel(a)
+		}
+	}
+	return p.buildModel(name, decl.spec, model, annotations)
+}
+
+// substitute replaces the type parameters in expr with their arguments, so
+// that a type argument such as []T means the same outside the generic type.
+func (p *Parser) substitute(expr ast.Expr) ast.Expr {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if arg, ok := p.typeArgs[t.Name]; ok {
+			return arg
+		}
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: p.substitute(t.X)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: t.Len, Elt: p.substitute(t.Elt)}
+	case *ast.MapType:
+		return &ast.MapType{Key: t.Key, Value: p.substitute(t.Value)}
+	case *ast.IndexExpr:
+		return &ast.IndexExpr{X: t.X, Index: p.substitute(t.Index)}
+	case *ast.IndexListExpr:
+		indices := make([]ast.Expr, len(t.Indices))
+		for i, index := range t.Indices {
+			indices[i] = p.substitute(index)
+		}
+		return &ast.IndexListExpr{X: t.X, Indices: indices}
+	}
+	return expr
+}
+
+// instanceName names the model of an instance of a generic type, such as
+// PageOfUser for Page[User] and PairOfStringAndUserList for Pair[string, []User].
+func instanceName(base string, args []ast.Expr) string {
+	names := make([]string, len(args))
+	for i, arg := range args {
+		names[i] = typeArgName(arg)
+	}
+	return base + "Of" + strings.Join(names, "And")
+}
+
+// typeArgName names a type argument in the name of an instance.
+func typeArgName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if _, builtin := typeSchemaMapping[t.Name]; builtin {
+			return strings.ToUpper(t.Name[:1]) + t.Name[1:]
+		}
+		return t.Name
+	case *ast.StarExpr:
+		return typeArgName(t.X)
+	case *ast.ArrayType:
+		return typeArgName(t.Elt) + "List"
+	case *ast.MapType:
+		return typeArgName(t.Value) + "Map"
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.IndexExpr:
+		return instanceName(typeArgName(t.X), []ast.Expr{t.Index})
+	case *ast.IndexListExpr:
+		return instanceName(typeArgName(t.X), t.Indices)
+	}
+	return "Any"
+}
+
+// parseSchemaRef resolves the schema of a body or response: a component
+// reference, a built-in type such as string or binary, or an array of either.
 func (p *Parser) parseSchemaRef(ref string) *openapi.Schema {
+	// Instances of generic types, such as Page[User] or []Page[User]
+	if strings.Contains(strings.TrimPrefix(ref, "[]"), "[") {
+		if expr, err := parser.ParseExpr(ref); err == nil {
+			return p.astTypeToSchema(expr)
+		}
+	}
 	// Check if it's an array type like []User or User[]
 	if strings.HasPrefix(ref, "[]") {
 		itemType := strings.TrimPrefix(ref, "[]")
 		return &openapi.Schema{
 			Type:  openapi.NewSchemaType(openapi.TypeArray),
-			Items: openapi.RefTo(itemType),
+			Items: p.typeToSchema(itemType),
 		}
 	}
 	if strings.HasSuffix(ref, "[]") {
 		itemType := strings.TrimSuffix(ref, "[]")
 		return &openapi.Schema{
 			Type:  openapi.NewSchemaType(openapi.TypeArray),
-			Items: openapi.RefTo(itemType),
+			Items: p.typeToSchema(itemType),
 		}
 	}
-	return openapi.RefTo(ref)
+	return p.typeToSchema(ref)
+}
+
+// Diagnostics returns the annotation lines that could not be parsed, such as
+// an !ok without a schema, in source order, and those that could not be
+// applied, such as a !view of a property its model does not declare.
+func (p *Parser) Diagnostics() []Diagnostic {
+	return p.diagnostics
 }
 
 // GetSpec returns the parsed specification with global schemas merged.
@@ -700,16 +1974,49 @@ func (p *Parser) generateDocument(spec *SpecData) *openapi.Document {
 		OpenAPI:      spec.Version,
 		Info:         p.buildInfo(spec),
 		Servers:      spec.Servers,
-		Tags:         spec.Tags,
+		Tags:         tagsWithOwners(spec.Tags, spec.TagOwners),
 		Paths:        make(openapi.Paths),
 		ExternalDocs: spec.ExternalDocs,
+		Extensions:   spec.Extensions,
 	}
 
 	p.addPaths(doc, spec.Operations)
 	p.addComponents(doc, spec)
+	if strings.HasPrefix(doc.OpenAPI, "3.1") {
+		// Schemas are built with the 3.0 nullable keyword, which 3.1 replaces with null types
+		openapi.NullableToTypeNull(doc)
+	} else {
+		// 3.0 ignores nullable beside a $ref, so nullable references are wrapped in allOf
+		openapi.NullableRefToAllOf(doc)
+	}
 	return doc
 }
 
+// tagsWithOwners sets the x-owner of declared tags, declaring owned tags that
+// have no !tag annotation after them in name order.
+func tagsWithOwners(tags []openapi.Tag, owners map[string]*openapi.Owner) []openapi.Tag {
+	if len(owners) == 0 {
+		return tags
+	}
+	tags = slices.Clone(tags)
+	for i := range tags {
+		if owner, ok := owners[tags[i].Name]; ok {
+			tags[i].Owner = owner
+		}
+	}
+	names := make([]string, 0, len(owners))
+	for name := range owners {
+		if !slices.ContainsFunc(tags, func(t openapi.Tag) bool { return t.Name == name }) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		tags = append(tags, openapi.Tag{Name: name, Owner: owners[name]})
+	}
+	return tags
+}
+
 func (p *Parser) buildInfo(spec *SpecData) openapi.Info {
 	info := *spec.Info
 	if len(spec.Links) > 0 {
@@ -727,6 +2034,7 @@ func (p *Parser) addPaths(doc *openapi.Document, operations []OperationData) {
 		if pathItem == nil {
 			pathItem = &openapi.PathItem{}
 			doc.Paths[op.Path] = pathItem
+			doc.PathOrder = append(doc.PathOrder, op.Path)
 		}
 		setPathOperation(pathItem, op)
 	}
@@ -734,15 +2042,20 @@ func (p *Parser) addPaths(doc *openapi.Document, operations []OperationData) {
 
 func setPathOperation(pathItem *openapi.PathItem, op OperationData) {
 	operation := &openapi.Operation{
-		OperationID: op.OperationID,
-		Summary:     op.Summary,
-		Description: op.Description,
-		Tags:        op.Tags,
-		Deprecated:  op.Deprecated,
-		Parameters:  op.Parameters,
-		RequestBody: op.RequestBody,
-		Responses:   op.Responses,
-		Security:    op.Security,
+		OperationID:   op.OperationID,
+		Summary:       op.Summary,
+		Description:   op.Description,
+		Tags:          op.Tags,
+		Deprecated:    op.Deprecated,
+		Parameters:    op.Parameters,
+		RequestBody:   op.RequestBody,
+		Responses:     op.Responses,
+		ResponseOrder: op.ResponseOrder,
+		Security:      op.Security,
+		Owner:         op.Owner,
+		Servers:       op.Servers,
+		ExternalDocs:  op.ExternalDocs,
+		Extensions:    op.Extensions,
 	}
 
 	switch op.Method {
@@ -766,20 +2079,27 @@ func setPathOperation(pathItem *openapi.PathItem, op OperationData) {
 }
 
 func (p *Parser) addComponents(doc *openapi.Document, spec *SpecData) {
-	hasSchemas := len(spec.Schemas) > 0 || len(p.globalSchemas) > 0
-	hasSecurities := len(spec.Securities) > 0
-
-	if !hasSchemas && !hasSecurities {
+	if len(spec.Schemas)+len(p.globalSchemas)+len(spec.Securities)+len(spec.Parameters)+len(spec.Bodies)+len(spec.Responses) == 0 {
 		return
 	}
 
-	doc.Components = &openapi.Components{}
-	if hasSchemas {
+	doc.Components = &openapi.Components{
+		SecuritySchemes: nonEmpty(spec.Securities),
+		Parameters:      nonEmpty(spec.Parameters),
+		RequestBodies:   nonEmpty(spec.Bodies),
+		Responses:       nonEmpty(spec.Responses),
+	}
+	if len(spec.Schemas) > 0 || len(p.globalSchemas) > 0 {
 		doc.Components.Schemas = p.buildSchemas(spec)
 	}
-	if hasSecurities {
-		doc.Components.SecuritySchemes = spec.Securities
+}
+
+// nonEmpty returns m, or nil if it is empty, so that empty components are omitted.
+func nonEmpty[V any](m map[string]V) map[string]V {
+	if len(m) == 0 {
+		return nil
 	}
+	return m
 }
 
 func (p *Parser) buildSchemas(spec *SpecData) map[string]*openapi.Schema {
@@ -831,11 +2151,16 @@ func getJSONTag(field *ast.Field) string {
 
 func cleanDescription(desc string) string {
 	lines := strings.Split(desc, "\n")
+	annotated := make(map[int]bool)
+	for _, a := range joinAnnotationLines(lines) {
+		for i := a.pieces[0].line; i <= a.last; i++ {
+			annotated[i] = true
+		}
+	}
 	var cleanLines []string
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		// Skip annotation lines
-		if strings.HasPrefix(trimmed, "!") {
+	for i, line := range lines {
+		// Skip annotation lines, their continuation lines, and description blocks
+		if annotated[i] {
 			continue
 		}
 		cleanLines = append(cleanLines, line)
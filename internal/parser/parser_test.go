@@ -1,10 +1,16 @@
 package parser
 
 import (
+	"fmt"
+	"go/token"
+	"maps"
 	"os"
 	"path/filepath"
+	"reflect"
 	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/fathurrohman26/yaswag/pkg/openapi"
 )
@@ -29,6 +35,9 @@ func (h *testHelper) cleanup() {
 
 func (h *testHelper) writeFile(name, content string) {
 	path := filepath.Join(h.tmpDir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		h.t.Fatal(err)
+	}
 	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		h.t.Fatal(err)
 	}
@@ -423,251 +432,1594 @@ func verifyArrayResponse(t *testing.T, doc *openapi.Document, path, schemaName s
 	}
 }
 
-func TestParser_HTTPMethods(t *testing.T) {
+func TestParser_OpLinks(t *testing.T) {
 	h := newTestHelper(t)
 	defer h.cleanup()
 
-	h.writeFile("api.go", httpMethodsTestContent)
+	h.writeFile("api.go", `package main
 
-	p := h.parse()
-	doc := p.Generate()
-	if doc == nil {
-		t.Fatal("Expected document to be generated")
+// !POST /orders -> createOrder "Create order"
+// !oplink 201 getOrder orderId=$response.body#/id "Fetch the created order"
+// !ok 201 Order "Created"
+// !oplink 202 listOrders limit=10
+func CreateOrder() {}
+`)
+
+	op := h.parse().Generate().Paths["/orders"].Post
+	created := op.Responses["201"]
+	if created.Description != "Created" || created.Links["getOrder"] == nil {
+		t.Fatalf("201 response = %+v, want description Created with a getOrder link", created)
+	}
+	link := created.Links["getOrder"]
+	if link.OperationID != "getOrder" || link.Parameters["orderId"] != "$response.body#/id" || link.Description != "Fetch the created order" {
+		t.Errorf("getOrder link = %+v", link)
 	}
 
-	t.Run("resource_path", func(t *testing.T) {
-		verifyResourcePath(t, doc)
-	})
-	t.Run("resource_id_path", func(t *testing.T) {
-		verifyResourceIDPath(t, doc)
-	})
+	accepted := op.Responses["202"]
+	if accepted == nil || accepted.Description != "Accepted" || accepted.Links["listOrders"].Parameters["limit"] != int64(10) {
+		t.Errorf("202 response = %+v, want Accepted with listOrders limit=10", accepted)
+	}
 }
 
-const httpMethodsTestContent = `package main
-
-// !api 3.0.3
-// !info "Test API" v1.0.0 "Test"
-func main() {}
+func TestParser_Diagnostics(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
 
-// !GET /resource -> getResource "Get"
-// !ok - "Success"
-func Get() {}
+	h.writeFile("api.go", `package main
 
-// !POST /resource -> createResource "Post"
-// !ok - "Success"
-func Post() {}
+// !api 3.0.3
+// !info "Pets" v1.0.0
 
-// !PUT /resource/{id} -> updateResource "Put"
-// !ok - "Success"
-func Put() {}
+// !model "A pet"
+type Pet struct {
+	// !field name
+	Name string `+"`json:\"name\"`"+`
+}
 
-// !DELETE /resource/{id} -> deleteResource "Delete"
-// !ok - "Success"
-func Delete() {}
+// !GET /pets -> listPets
+// !ok
+func ListPets() {}
+`)
 
-// !PATCH /resource/{id} -> patchResource "Patch"
-// !ok - "Success"
-func Patch() {}
+	p := h.parse()
+	var got []string
+	for _, d := range p.Diagnostics() {
+		got = append(got, fmt.Sprintf("%s:%d:%d %s", filepath.Base(d.Pos.Filename), d.Pos.Line, d.Pos.Column, d.Message))
+	}
+	want := []string{
+		`api.go:8:12 malformed !field: expected name:type, found name (usage: !field name:type "Description" required deprecated readonly writeonly example=value format=format)`,
+		`api.go:13:7 !ok missing schema name (usage: !ok [status] Schema "Description")`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diagnostics() = %q, want %q", got, want)
+	}
+	if p.Generate().Paths["/pets"].Get == nil {
+		t.Error("operation with a malformed annotation was dropped")
+	}
+}
 
-// !OPTIONS /resource -> optionsResource "Options"
-// !ok - "Success"
-func Options() {}
+func TestParser_ContinuationLines(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
 
-// !HEAD /resource -> headResource "Head"
-// !ok - "Success"
-func Head() {}
-`
+	h.writeFile("api.go", `package main
 
-func verifyResourcePath(t *testing.T, doc *openapi.Document) {
-	resourcePath := doc.Paths["/resource"]
-	if resourcePath == nil {
-		t.Fatal("Expected /resource path")
-	}
-	assertNotNil(t, "GET on /resource", resourcePath.Get)
-	assertNotNil(t, "POST on /resource", resourcePath.Post)
-	assertNotNil(t, "OPTIONS on /resource", resourcePath.Options)
-	assertNotNil(t, "HEAD on /resource", resourcePath.Head)
+// !api 3.0.3
+// !info "Pets" v1.0.0
+//   "Sells pets
+//   of every kind"
+
+// !model "A pet"
+type Pet struct {
+	// !field tag:string \
+	//   "A tag" example=dog
+	Tag string `+"`json:\"tag\"`"+`
+	// Name of the pet.
+	// !x x-order \
+	//   1
+	Name string `+"`json:\"name\"`"+`
 }
 
-func verifyResourceIDPath(t *testing.T, doc *openapi.Document) {
-	resourceIdPath := doc.Paths["/resource/{id}"]
-	if resourceIdPath == nil {
-		t.Fatal("Expected /resource/{id} path")
+// ListPets lists pets.
+//
+// !GET /pets -> listPets "List pets" \
+// #pets #store
+// !ok []Pet "Pets"
+func ListPets() {}
+`)
+
+	doc := h.parse().Generate()
+	if doc.Info.Description != "Sells pets of every kind" {
+		t.Errorf("info description = %q", doc.Info.Description)
+	}
+	pet := doc.Components.Schemas["Pet"]
+	if tag := pet.Properties["tag"]; tag.Description != "A tag" || tag.Example != "dog" {
+		t.Errorf("tag = %+v", tag)
+	}
+	if name := pet.Properties["name"]; name.Description != "Name of the pet." {
+		t.Errorf("name description = %q", name.Description)
+	}
+	if op := doc.Paths["/pets"].Get; op == nil || !reflect.DeepEqual(op.Tags, []string{"pets", "store"}) {
+		t.Errorf("listPets = %+v", op)
 	}
-	assertNotNil(t, "PUT on /resource/{id}", resourceIdPath.Put)
-	assertNotNil(t, "DELETE on /resource/{id}", resourceIdPath.Delete)
-	assertNotNil(t, "PATCH on /resource/{id}", resourceIdPath.Patch)
 }
 
-// TestParser_ParseDirFromCurrentDirectory tests parsing from the current directory using "."
-// This reproduces the bug reported in issue #1
-// currentDirTestSetup contains test fixture data
-type currentDirTestSetup struct {
-	t      *testing.T
-	tmpDir string
-	origWd string
-}
+func TestParser_DescriptionBlocks(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
 
-// setupCurrentDirTest creates the test fixture
-func setupCurrentDirTest(t *testing.T) *currentDirTestSetup {
-	tmpDir, err := os.MkdirTemp("", "yaswag-test")
-	if err != nil {
-		t.Fatal(err)
-	}
+	h.writeFile("api.go", `package main
 
-	origWd, err := os.Getwd()
-	if err != nil {
-		_ = os.RemoveAll(tmpDir)
-		t.Fatal(err)
-	}
+// !api 3.0.3
+// !info "Pets" v1.0.0 "Overwritten"
+// !description
+// # Pet Store
+//
+// Sells pets.
 
-	return &currentDirTestSetup{t: t, tmpDir: tmpDir, origWd: origWd}
+// Pet is a pet for sale.
+//
+// !model "A pet"
+// !description A pet for sale, with its
+// ![photo](https://example.com/pet.png)
+type Pet struct {
+	// !description
+	// The name, one of:
+	//   - Rex
+	//   - Tom
+	Name string `+"`json:\"name\"`"+`
 }
 
-// cleanup restores the working directory and removes temp files
-func (s *currentDirTestSetup) cleanup() {
-	_ = os.Chdir(s.origWd)
-	_ = os.RemoveAll(s.tmpDir)
-}
+// ListPets lists pets.
+//
+// !GET /pets -> listPets "List pets"
+// !description
+// Lists the pets in stock, such as:
+//
+//	curl https://api.example.com/pets
+//
+// **Cached** for a minute.
+// !ok []Pet "Pets"
+func ListPets() {}
+`)
 
-// createSubdirWithFile creates a subdirectory and writes a file to it
-func (s *currentDirTestSetup) createSubdirWithFile(subdir, filename, content string) {
-	dir := filepath.Join(s.tmpDir, subdir)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		s.t.Fatal(err)
+	p := h.parse()
+	if len(p.Diagnostics()) != 0 {
+		t.Errorf("Diagnostics() = %v", p.Diagnostics())
 	}
-	if err := os.WriteFile(filepath.Join(dir, filename), []byte(content), 0644); err != nil {
-		s.t.Fatal(err)
+	doc := p.Generate()
+	if want := "# Pet Store\n\nSells pets."; doc.Info.Description != want {
+		t.Errorf("info description = %q, want %q", doc.Info.Description, want)
 	}
-}
-
-// changeToTmpDir changes to the temp directory
-func (s *currentDirTestSetup) changeToTmpDir() {
-	if err := os.Chdir(s.tmpDir); err != nil {
-		s.t.Fatal(err)
+	pet := doc.Components.Schemas["Pet"]
+	if want := "A pet for sale, with its\n![photo](https://example.com/pet.png)"; pet.Description != want {
+		t.Errorf("Pet description = %q, want %q", pet.Description, want)
+	}
+	if want := "The name, one of:\n  - Rex\n  - Tom"; pet.Properties["name"].Description != want {
+		t.Errorf("Pet.name description = %q, want %q", pet.Properties["name"].Description, want)
+	}
+	op := doc.Paths["/pets"].Get
+	if want := "Lists the pets in stock, such as:\n\n\tcurl https://api.example.com/pets\n\n**Cached** for a minute."; op.Description != want {
+		t.Errorf("listPets description = %q, want %q", op.Description, want)
+	}
+	if op.Responses["200"] == nil {
+		t.Error("the annotation after a description block was dropped")
 	}
 }
 
-func TestParser_ParseDirFromCurrentDirectory(t *testing.T) {
-	setup := setupCurrentDirTest(t)
-	defer setup.cleanup()
+func TestParser_Locales(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
 
-	setup.createSubdirWithFile("docs", "doc.go", currentDirDocContent)
-	setup.createSubdirWithFile("handlers", "handlers.go", currentDirHandlersContent)
-	setup.changeToTmpDir()
+	h.writeFile("api.go", `package main
 
-	p := New()
-	if err := p.ParseDir("."); err != nil {
-		t.Fatalf("ParseDir(\".\") error = %v", err)
+// !api 3.0.3
+// !info "Pet Store" v1.0.0 "Sells pets."
+// !info.fr "Animalerie" v1.0.0 "Vend des animaux."
+// !tag pets "Manage pets" desc.fr="Gérer les animaux"
+
+// Pet is a pet for sale.
+//
+// !model "A pet" desc.es="Una mascota"
+type Pet struct {
+	// !field name:string "Name of the pet" desc.fr="Nom de l'animal" example="Rex"
+	Name string `+"`json:\"name\"`"+`
+}
+
+// ListPets lists pets.
+//
+// !description
+// Lists the pets in stock.
+// !description.fr
+// Liste les animaux en stock.
+// !GET /pets -> listPets "List pets" summary.fr="Lister les animaux"
+// !query limit:integer "Page size" desc.fr="Taille de page"
+// !ok []Pet "Pets" desc.fr="Animaux"
+func ListPets() {}
+`)
+
+	parse := func(locale string) *openapi.Document {
+		p := NewWithOptions(Options{Locale: locale})
+		if err := p.ParseDir(h.tmpDir); err != nil {
+			t.Fatalf("ParseDir() error = %v", err)
+		}
+		if len(p.Diagnostics()) != 0 {
+			t.Errorf("Diagnostics() = %v", p.Diagnostics())
+		}
+		return p.Generate()
 	}
 
-	spec := p.GetSpec()
-	verifyCurrentDirSpec(t, spec)
+	for _, tt := range []struct {
+		locale string
+		want   []string
+	}{
+		{"", []string{"Pet Store", "Sells pets.", "Manage pets", "A pet", "Name of the pet", "List pets", "Lists the pets in stock.", "Page size", "Pets"}},
+		{"fr", []string{"Animalerie", "Vend des animaux.", "Gérer les animaux", "A pet", "Nom de l'animal", "Lister les animaux", "Liste les animaux en stock.", "Taille de page", "Animaux"}},
+		{"es", []string{"Pet Store", "Sells pets.", "Manage pets", "Una mascota", "Name of the pet", "List pets", "Lists the pets in stock.", "Page size", "Pets"}},
+	} {
+		doc := parse(tt.locale)
+		op := doc.Paths["/pets"].Get
+		pet := doc.Components.Schemas["Pet"]
+		got := []string{
+			doc.Info.Title, doc.Info.Description, doc.Tags[0].Description,
+			pet.Description, pet.Properties["name"].Description,
+			op.Summary, op.Description, op.Parameters[0].Description, op.Responses["200"].Description,
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("locale %q: texts = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
 }
 
-const currentDirDocContent = `package docs
+func TestParser_Internal(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", `package main
 
-// API Documentation
-//
 // !api 3.0.3
-// !info "Test API" v1.0.0 "A test API for issue #1"
-// !server https://api.example.com "Production"
-`
+// !info "Pets" v1.0.0
 
-const currentDirHandlersContent = `package handlers
+// !GET /pets -> listPets
+// !ok "Pets"
+func ListPets() {}
 
-// GetUsers retrieves all users
-//
-// !GET /users -> getUsers "Get all users" #users
-// !ok 200 - "Success"
-func GetUsers() {}
-`
+// !DELETE /pets -> purgePets
+// !ignore "ops only"
+// !ok 204 "Purged"
+func PurgePets() {}
 
-// verifyCurrentDirSpec validates the parsed spec from current directory
-func verifyCurrentDirSpec(t *testing.T, spec *SpecData) {
-	t.Helper()
-	if spec.Info == nil || spec.Info.Title == "" {
-		t.Fatal("Expected to find YaSwag annotations when parsing from current directory")
+// !model "Audit record"
+// !ignore
+type Audit struct {
+	ID string `+"`json:\"id\"`"+`
+}
+`)
+	h.writeFile("admin.go", `//go:build internal && (linux || darwin)
+
+package main
+
+// !POST /admin/reindex -> reindex
+// !ok 202 "Started"
+func Reindex() {}
+`)
+	h.writeFile("other.go", `//go:build !windows
+
+package main
+
+// !GET /owners -> listOwners
+// !ok "Owners"
+func ListOwners() {}
+`)
+
+	tests := []struct {
+		name     string
+		opts     Options
+		paths    []string
+		internal []string
+		audit    bool
+	}{
+		{name: "defaults", opts: Options{}, paths: []string{"/owners", "/pets", "/admin/reindex"}},
+		{name: "excluded", opts: Options{ExcludeTags: []string{"internal"}}, paths: []string{"/owners", "/pets"}},
+		{
+			name:     "included",
+			opts:     Options{ExcludeTags: []string{"internal"}, IncludeInternal: true},
+			paths:    []string{"/owners", "/pets", "/admin/reindex"},
+			internal: []string{"DELETE /pets", "POST /admin/reindex"},
+			audit:    true,
+		},
 	}
-	assertEqual(t, "Info.Title", spec.Info.Title, "Test API")
-	assertEqual(t, "Info.Version", spec.Info.Version, "1.0.0")
-	assertLen(t, "Operations", len(spec.Operations), 1)
-	if len(spec.Operations) > 0 {
-		assertEqual(t, "Operation.Path", spec.Operations[0].Path, "/users")
-		assertEqual(t, "Operation.Method", spec.Operations[0].Method, "GET")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewWithOptions(tt.opts)
+			if err := p.ParseDir(h.tmpDir); err != nil {
+				t.Fatalf("ParseDir() error = %v", err)
+			}
+			doc := p.Generate()
+
+			paths, internal := internalOperations(doc)
+			want := slices.Sorted(slices.Values(tt.paths))
+			if !reflect.DeepEqual(paths, want) {
+				t.Errorf("paths = %v, want %v", paths, want)
+			}
+			if !reflect.DeepEqual(internal, tt.internal) {
+				t.Errorf("internal operations = %v, want %v", internal, tt.internal)
+			}
+			var audit *openapi.Schema
+			if doc.Components != nil {
+				audit = doc.Components.Schemas["Audit"]
+			}
+			if ok := audit != nil; ok != tt.audit {
+				t.Fatalf("Audit schema present = %v, want %v", ok, tt.audit)
+			}
+			if audit != nil && audit.Extensions["x-internal"] != true {
+				t.Error("Audit schema is not marked x-internal")
+			}
+		})
 	}
 }
 
-// TestParser_SchemaInference tests automatic schema inference from Go types and json tags
-func TestParser_SchemaInference(t *testing.T) {
+// internalOperations returns the sorted paths of doc and its operations
+// marked x-internal, such as "DELETE /pets".
+func internalOperations(doc *openapi.Document) ([]string, []string) {
+	var paths, internal []string
+	for path, item := range doc.Paths {
+		paths = append(paths, path)
+		for method, op := range map[string]*openapi.Operation{"GET": item.Get, "POST": item.Post, "DELETE": item.Delete} {
+			if op != nil && op.Extensions["x-internal"] == true {
+				internal = append(internal, method+" "+path)
+			}
+		}
+	}
+	slices.Sort(paths)
+	slices.Sort(internal)
+	return paths, internal
+}
+
+func TestParser_GenericsAndAliases(t *testing.T) {
 	h := newTestHelper(t)
 	defer h.cleanup()
 
-	h.writeFile("models.go", schemaInferenceTestContent)
+	h.writeFile("handlers.go", `package main
 
-	p := h.parse()
-	schemas := p.GetGlobalSchemas()
+// !api 3.0.3
+// !info "Users" v1.0.0
+
+// !GET /users -> listUsers
+// !ok Page[User] "Users"
+func ListUsers() {}
+
+// !GET /pairs -> listPairs
+// !ok []Pair[string,[]User] "Pairs"
+func ListPairs() {}
+`)
+	h.writeFile("types.go", `package main
+
+// !model "A page of results"
+type Page[T any] struct {
+	Items []T `+"`json:\"items\"`"+`
+	Next  *T  `+"`json:\"next,omitempty\"`"+`
+}
 
-	// Test Product schema (no !field annotations, relies on inference)
-	productSchema := schemas["Product"]
-	if productSchema == nil {
-		t.Fatal("Expected Product schema")
-	}
+type Pair[K comparable, V any] struct {
+	Key   K `+"`json:\"key\"`"+`
+	Value V `+"`json:\"value\"`"+`
+}
 
-	props := productSchema.Schema.Properties
-	assertLen(t, "Product properties", len(props), 3) // Internal excluded (json:"-")
+type UserID = int64
 
-	// Verify ID field (required, no omitempty)
-	if props["id"] == nil {
-		t.Fatal("Expected id property")
+type Status string
+
+type Tags []string
+
+type Labels map[string]Status
+
+// !model "A user"
+type User struct {
+	ID      UserID     `+"`json:\"id\"`"+`
+	Status  Status     `+"`json:\"status\"`"+`
+	Tags    Tags       `+"`json:\"tags\"`"+`
+	Labels  Labels     `+"`json:\"labels\"`"+`
+	Friends *Page[User] `+"`json:\"friends\"`"+`
+}
+`)
+
+	doc := h.parse().Generate()
+	schemas := doc.Components.Schemas
+
+	page, pair := schemas["PageOfUser"], schemas["PairOfStringAndUserList"]
+	if page == nil || pair == nil {
+		t.Fatalf("instances missing from %v", slices.Sorted(maps.Keys(schemas)))
 	}
-	if !slices.Contains(productSchema.Schema.Required, "id") {
-		t.Error("Expected id to be required (no omitempty)")
+	refs := []struct {
+		name   string
+		schema *openapi.Schema
+		want   string
+	}{
+		{"listUsers schema", doc.Paths["/users"].Get.Responses["200"].Content["application/json"].Schema, "PageOfUser"},
+		{"listPairs items", doc.Paths["/pairs"].Get.Responses["200"].Content["application/json"].Schema.Items, "PairOfStringAndUserList"},
+		{"PageOfUser.items items", page.Properties["items"].Items, "User"},
+		{"PageOfUser.next", page.Properties["next"], "User"},
+		{"PairOfStringAndUserList.value items", pair.Properties["value"].Items, "User"},
+	}
+	for _, r := range refs {
+		if !refersTo(r.schema, r.want) {
+			t.Errorf("%s = %+v, want a $ref to %s", r.name, r.schema, r.want)
+		}
+	}
+	if page.Description != "A page of results" || !page.Properties["next"].Nullable {
+		t.Errorf("PageOfUser = %+v, want its description and a nullable next", page)
+	}
+	if got := typeOf(pair.Properties["key"]); got != "string" {
+		t.Errorf("PairOfStringAndUserList.key type = %q, want string", got)
 	}
+	if _, ok := schemas["Page"]; ok {
+		t.Error("generic type Page has a schema of its own")
+	}
+	checkNamedTypes(t, schemas)
+}
 
-	// Verify name field (optional, has omitempty)
-	if props["name"] == nil {
-		t.Fatal("Expected name property")
+// checkNamedTypes checks that the User model of TestParser_GenericsAndAliases
+// has the schemas of the underlying types of its aliases and named types.
+func checkNamedTypes(t *testing.T, schemas map[string]*openapi.Schema) {
+	t.Helper()
+	user := schemas["User"]
+	for name, want := range map[string]string{"id": "integer", "status": "string", "tags": "array", "labels": "object"} {
+		if got := typeOf(user.Properties[name]); got != want {
+			t.Errorf("User.%s type = %q, want %q", name, got, want)
+		}
 	}
-	if slices.Contains(productSchema.Schema.Required, "name") {
-		t.Error("Expected name to be optional (has omitempty)")
+	if got := user.Properties["labels"].AdditionalProperties; typeOf(got) != "string" {
+		t.Errorf("User.labels values = %+v, want strings", got)
+	}
+	if got := user.Properties["friends"]; !refersTo(got, "PageOfUser") {
+		t.Errorf("User.friends = %+v, want PageOfUser", got)
+	}
+	for _, name := range []string{"UserID", "Status", "Tags", "Labels"} {
+		if _, ok := schemas[name]; ok {
+			t.Errorf("%s has a schema of its own", name)
+		}
 	}
+}
 
-	// Verify description from inline comment
-	if props["name"].Description == "" {
-		t.Error("Expected name to have description from inline comment")
+// diagnosticMessages returns the messages of the diagnostics of p.
+func diagnosticMessages(p *Parser) []string {
+	var messages []string
+	for _, d := range p.Diagnostics() {
+		messages = append(messages, d.Message)
 	}
+	return messages
+}
 
-	// Verify internal field is excluded (json:"-")
-	if props["internal"] != nil {
-		t.Error("Expected internal field to be excluded (json:\"-\")")
+// refersTo reports whether schema is a $ref to the component schema name,
+// or a nullable one, which OpenAPI 3.0 wraps in allOf.
+func refersTo(schema *openapi.Schema, name string) bool {
+	if schema != nil && schema.Nullable && len(schema.AllOf) == 1 {
+		schema = schema.AllOf[0]
 	}
+	return schema != nil && schema.Ref == "#/components/schemas/"+name
+}
 
-	// Verify price field type inference
-	if props["price"] == nil {
-		t.Fatal("Expected price property")
+// extends reports whether schema is composed with allOf of parts schemas,
+// the first a $ref to the component schema base.
+func extends(schema *openapi.Schema, base string, parts int) bool {
+	return schema != nil && len(schema.AllOf) == parts && refersTo(schema.AllOf[0], base)
+}
+
+// typeOf returns the types of schema joined with commas, or "" if it is nil.
+func typeOf(schema *openapi.Schema) string {
+	if schema == nil {
+		return ""
 	}
+	return strings.Join(schema.Type, ",")
 }
 
-// TestParser_SchemaInferenceMixed tests mixed annotations and inference
-func TestParser_SchemaInferenceMixed(t *testing.T) {
+func TestParser_RecursiveGenerics(t *testing.T) {
 	h := newTestHelper(t)
 	defer h.cleanup()
 
-	h.writeFile("models.go", schemaMixedTestContent)
+	h.writeFile("types.go", `package main
 
-	p := h.parse()
-	schemas := p.GetGlobalSchemas()
+type Node[T any] struct {
+	Value T          `+"`json:\"value\"`"+`
+	Next  *Node[T]       `+"`json:\"next\"`"+`
+	Deep  *Node[Node[T]] `+"`json:\"deep\"`"+`
+}
 
-	orderSchema := schemas["Order"]
-	if orderSchema == nil {
-		t.Fatal("Expected Order schema")
-	}
+type Tree []Tree
 
-	props := orderSchema.Schema.Properties
+// !model "A root"
+type Root struct {
+	Node Node[string] `+"`json:\"node\"`"+`
+	Tree Tree         `+"`json:\"tree\"`"+`
+}
+`)
 
-	// ID has !field annotation - should use annotation description
-	if props["id"] == nil {
+	doc := h.parse().Generate()
+	node := doc.Components.Schemas["NodeOfString"]
+	if node == nil || !refersTo(node.Properties["next"], "NodeOfString") {
+		t.Fatalf("NodeOfString = %+v", node)
+	}
+	if len(doc.Components.Schemas) > maxInstanceDepth+2 {
+		t.Errorf("%d schemas, want at most %d", len(doc.Components.Schemas), maxInstanceDepth+2)
+	}
+}
+
+func TestParser_Composition(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("types.go", `package main
+
+// !model "A resource"
+type Resource struct {
+	ID string `+"`json:\"id\"`"+`
+}
+
+type Timestamps struct {
+	CreatedAt string `+"`json:\"createdAt\"`"+`
+	Name      string `+"`json:\"name\"`"+`
+}
+
+// !model "A pet"
+type Pet struct {
+	*Resource
+	Timestamps
+	Name  string   `+"`json:\"name\"`"+`
+	Owner Resource `+"`json:\"owner\"`"+`
+}
+
+// !model "A dog"
+// !extends Pet
+type Dog struct {
+	// !field volume:integer "Bark volume"
+	Volume int `+"`json:\"volume,omitempty\"`"+`
+}
+
+// !model "A pet with a collar"
+type Collared struct {
+	Pet
+}
+`)
+
+	schemas := h.parse().Generate().Components.Schemas
+
+	pet := schemas["Pet"]
+	if !extends(pet, "Resource", 2) {
+		t.Fatalf("Pet allOf = %+v", pet.AllOf)
+	}
+	if pet.Description != "A pet" {
+		t.Errorf("Pet description = %q", pet.Description)
+	}
+	own := pet.AllOf[1]
+	if got := strings.Join(own.PropertyOrder, ","); got != "name,owner,createdAt" {
+		t.Errorf("Pet properties = %s, want name,owner,createdAt", got)
+	}
+	if got := strings.Join(own.Required, ","); got != "name,owner,createdAt" {
+		t.Errorf("Pet required = %s", got)
+	}
+
+	dog := schemas["Dog"]
+	if !extends(dog, "Pet", 2) {
+		t.Fatalf("Dog allOf = %+v", dog.AllOf)
+	}
+	if got := dog.AllOf[1].Properties["volume"].Description; got != "Bark volume" {
+		t.Errorf("Dog volume description = %q", got)
+	}
+
+	collared := schemas["Collared"]
+	if !extends(collared, "Pet", 1) {
+		t.Errorf("Collared allOf = %+v", collared.AllOf)
+	}
+}
+
+func TestParser_Maps(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("types.go", `package main
+
+// !model "An order"
+type Order struct {
+	ID int64 `+"`json:\"id\"`"+`
+}
+
+// !model "Orders by ID" additionalProperties=Order
+type Orders struct{}
+
+// !model "Stock"
+type Stock struct {
+	// !field counts:map[string]integer "Counts by status"
+	Counts any `+"`json:\"counts\"`"+`
+	Orders map[string]*Order `+"`json:\"orders\"`"+`
+}
+
+// !model "Inventory by status"
+type Inventory map[string]int32
+
+// !GET /stock -> getStock
+// !ok map[string]Order "Orders by ID"
+func GetStock() {}
+`)
+
+	doc := h.parse().Generate()
+	schemas := doc.Components.Schemas
+
+	if got := schemas["Orders"].AdditionalProperties; !refersTo(got, "Order") {
+		t.Errorf("Orders additionalProperties = %+v, want Order", got)
+	}
+	counts := schemas["Stock"].Properties["counts"]
+	if counts.Description != "Counts by status" || typeOf(counts.AdditionalProperties) != "integer" {
+		t.Errorf("Stock.counts = %+v", counts)
+	}
+	if got := schemas["Stock"].Properties["orders"].AdditionalProperties; !refersTo(got, "Order") {
+		t.Errorf("Stock.orders additionalProperties = %+v, want Order", got)
+	}
+	if got := schemas["Inventory"].AdditionalProperties; got == nil || got.Format != "int32" {
+		t.Errorf("Inventory additionalProperties = %+v, want int32", got)
+	}
+	body := doc.Paths["/stock"].Get.Responses["200"].Content["application/json"].Schema
+	if !refersTo(body.AdditionalProperties, "Order") {
+		t.Errorf("getStock schema = %+v, want a map of Order", body)
+	}
+}
+
+func TestParser_NullableFields(t *testing.T) {
+	source := func(version string) string {
+		return `package main
+
+// !api ` + version + `
+// !info "Pets" v1.0.0
+
+// !model "A category"
+type Category struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+// !model "A pet"
+type Pet struct {
+	// !field category:Category? "Pet category"
+	Category Category ` + "`json:\"category\"`" + `
+	// !field tag:string? "Pet tag"
+	Tag string ` + "`json:\"tag\"`" + `
+	Nickname *string ` + "`json:\"nickname\"`" + `
+}
+`
+	}
+
+	t.Run("3.0", func(t *testing.T) {
+		h := newTestHelper(t)
+		defer h.cleanup()
+		h.writeFile("api.go", source("3.0.3"))
+
+		pet := h.parse().Generate().Components.Schemas["Pet"]
+		for _, name := range []string{"tag", "nickname"} {
+			if !pet.Properties[name].Nullable {
+				t.Errorf("%s is not nullable", name)
+			}
+		}
+		want := &openapi.Schema{AllOf: []*openapi.Schema{openapi.RefTo("Category")}, Nullable: true, Description: "Pet category"}
+		if category := pet.Properties["category"]; !reflect.DeepEqual(category, want) {
+			t.Errorf("category = %+v, want allOf Category beside nullable", category)
+		}
+	})
+
+	t.Run("3.1", func(t *testing.T) {
+		h := newTestHelper(t)
+		defer h.cleanup()
+		h.writeFile("api.go", source("3.1.0"))
+
+		pet := h.parse().Generate().Components.Schemas["Pet"]
+		null := &openapi.Schema{Type: openapi.NewSchemaType(openapi.TypeNull)}
+		want := &openapi.Schema{AnyOf: []*openapi.Schema{openapi.RefTo("Category"), null}, Description: "Pet category"}
+		if category := pet.Properties["category"]; !reflect.DeepEqual(category, want) {
+			t.Errorf("category = %+v, want anyOf Category and null", category)
+		}
+		for _, name := range []string{"tag", "nickname"} {
+			prop := pet.Properties[name]
+			if prop.Nullable || !slices.Equal(prop.Type, openapi.SchemaType{openapi.TypeString, openapi.TypeNull}) {
+				t.Errorf("%s = %+v, want type [string, null]", name, prop)
+			}
+		}
+	})
+}
+
+func TestParser_WellKnownTypes(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("types.go", `package main
+
+import (
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// !model "An event"
+type Event struct {
+	ID        uuid.UUID     `+"`json:\"id\"`"+`
+	At        time.Time     `+"`json:\"at\"`"+`
+	EndsAt    *time.Time    `+"`json:\"endsAt\"`"+`
+	Times     []time.Time   `+"`json:\"times\"`"+`
+	Timeout   time.Duration `+"`json:\"timeout\"`"+`
+	Source    net.IP        `+"`json:\"source\"`"+`
+	Link      url.URL       `+"`json:\"link\"`"+`
+	Payload   []byte        `+"`json:\"payload\"`"+`
+	// !field contact:string "Contact address" format=email
+	Contact string `+"`json:\"contact\"`"+`
+}
+`)
+
+	event := h.parse().Generate().Components.Schemas["Event"]
+	tests := []struct {
+		property, typ, format string
+	}{
+		{"id", openapi.TypeString, "uuid"},
+		{"at", openapi.TypeString, "date-time"},
+		{"endsAt", openapi.TypeString, "date-time"},
+		{"timeout", openapi.TypeInteger, "int64"},
+		{"source", openapi.TypeString, "ipv4"},
+		{"link", openapi.TypeString, "uri"},
+		{"payload", openapi.TypeString, "byte"},
+		{"contact", openapi.TypeString, "email"},
+	}
+	for _, tt := range tests {
+		prop := event.Properties[tt.property]
+		if strings.Join(prop.Type, ",") != tt.typ || prop.Format != tt.format {
+			t.Errorf("%s = %v/%s, want %s/%s", tt.property, prop.Type, prop.Format, tt.typ, tt.format)
+		}
+	}
+	if !event.Properties["endsAt"].Nullable {
+		t.Error("endsAt is not nullable")
+	}
+	if items := event.Properties["times"].Items; items == nil || items.Format != "date-time" {
+		t.Errorf("times items = %+v, want date-time strings", items)
+	}
+}
+
+func TestParser_ContentTypes(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", `package main
+
+// !POST /pets/{id}/photo -> uploadPhoto "Upload a photo"
+// !body binary "Photo bytes" required type=application/octet-stream
+// !ok Pet "Updated pet" type=application/json,application/xml
+// !error 415 - "Unsupported media type"
+func UploadPhoto() {}
+`)
+
+	upload := h.parse().Generate().Paths["/pets/{id}/photo"].Post
+	body := upload.RequestBody.Content
+	if len(body) != 1 || body["application/octet-stream"].Schema == nil || body["application/octet-stream"].Schema.Format != "binary" {
+		t.Errorf("request body content = %+v, want application/octet-stream with a binary string", body)
+	}
+	ok := upload.Responses["200"].Content
+	if len(ok) != 2 || ok["application/json"].Schema.Ref != "#/components/schemas/Pet" || ok["application/xml"].Schema.Ref != "#/components/schemas/Pet" {
+		t.Errorf("200 content = %+v, want Pet as JSON and XML", ok)
+	}
+	if upload.Responses["415"].Content != nil {
+		t.Errorf("415 content = %+v, want none", upload.Responses["415"].Content)
+	}
+}
+
+func TestParser_ResponseContentTypes(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", `package main
+
+// !GET /reports -> exportReports "Export reports"
+// !ok []Report "Reports" type=text/csv
+// !ok 202 - "Export started" type=text/plain
+func ExportReports() {}
+`)
+
+	export := h.parse().Generate().Paths["/reports"].Get
+	csv, found := export.Responses["200"].Content["text/csv"]
+	if !found || csv.Schema.Items == nil || csv.Schema.Items.Ref != "#/components/schemas/Report" {
+		t.Errorf("200 content = %+v, want an array of Report as text/csv", export.Responses["200"].Content)
+	}
+	if mt, found := export.Responses["202"].Content["text/plain"]; !found || mt.Schema != nil {
+		t.Errorf("202 content = %+v, want text/plain without a schema", export.Responses["202"].Content)
+	}
+}
+
+func TestParser_MutualTLS(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", `package main
+
+// !api 3.1.0
+// !security client_cert:mutualTLS "Client certificate issued by the partner CA"
+
+// !GET /accounts -> listAccounts "List accounts"
+// !secure client_cert
+// !ok Account "Accounts"
+func ListAccounts() {}
+`)
+
+	doc := h.parse().Generate()
+	scheme := doc.Components.SecuritySchemes["client_cert"]
+	if scheme == nil || scheme.Type != "mutualTLS" || scheme.Description != "Client certificate issued by the partner CA" {
+		t.Fatalf("client_cert scheme = %+v, want mutualTLS", scheme)
+	}
+	if scheme.Name != "" || scheme.In != "" || scheme.Scheme != "" {
+		t.Errorf("client_cert scheme = %+v, want no name, in, or scheme", scheme)
+	}
+	if security := doc.Paths["/accounts"].Get.Security; len(security) != 1 || security[0]["client_cert"] == nil {
+		t.Errorf("security = %v, want client_cert", security)
+	}
+}
+
+func TestParser_FormURLEncoded(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", `package main
+
+// !POST /login -> login "Log in"
+// !form username:string "User name" required
+// !form password:string required
+// !ok Session "Logged in"
+func Login() {}
+`)
+
+	doc := h.parse().Generate()
+	login := doc.Paths["/login"].Post.RequestBody
+	mt, ok := login.Content["application/x-www-form-urlencoded"]
+	if !ok || len(login.Content) != 1 {
+		t.Fatalf("login content = %v, want application/x-www-form-urlencoded only", login.Content)
+	}
+	if !login.Required || !slices.Equal(mt.Schema.Required, []string{"username", "password"}) {
+		t.Errorf("login required = %v, %v, want both fields required", login.Required, mt.Schema.Required)
+	}
+	if mt.Schema.Properties["username"].Description != "User name" {
+		t.Errorf("username = %+v, want description", mt.Schema.Properties["username"])
+	}
+}
+
+func TestParser_FormMultipart(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", `package main
+
+// !PUT /users/{id}/avatar -> uploadAvatar "Upload avatar"
+// !form avatar:binary "Profile picture" required type=image/png,image/jpeg
+// !form caption:string "Caption"
+// !form attachments:[]binary "Extra files"
+// !ok User "Updated"
+func UploadAvatar() {}
+`)
+
+	doc := h.parse().Generate()
+	upload := doc.Paths["/users/{id}/avatar"].Put.RequestBody
+	mt, ok := upload.Content["multipart/form-data"]
+	if !ok || len(upload.Content) != 1 {
+		t.Fatalf("upload content = %v, want multipart/form-data only", upload.Content)
+	}
+	if avatar := mt.Schema.Properties["avatar"]; avatar.Format != "binary" {
+		t.Errorf("avatar = %+v, want binary string", avatar)
+	}
+	if files := mt.Schema.Properties["attachments"]; files.Items == nil || files.Items.Format != "binary" {
+		t.Errorf("attachments = %+v, want array of binary", files)
+	}
+	if enc := mt.Encoding["avatar"]; enc.ContentType != "image/png, image/jpeg" {
+		t.Errorf("avatar encoding = %+v, want image/png, image/jpeg", enc)
+	}
+	if _, ok := mt.Encoding["caption"]; ok {
+		t.Errorf("caption has an encoding, want none")
+	}
+}
+
+func TestParser_SharedComponents(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", `package main
+
+// !api 3.1.0
+// !info "Pets" v1.0.0
+// !shared-param PageSize query limit:integer "Page size" default=20
+// !shared-param PageToken query token:string "Page token"
+// !shared-body NewPet Pet "Pet to add" required
+// !shared-response NotFound Error "Resource not found"
+
+// !GET /pets -> listPets "List pets"
+// !param-ref PageSize PageToken
+// !ok []Pet "Pets"
+func ListPets() {}
+
+// !POST /pets -> createPet "Create pet"
+// !body-ref NewPet
+// !ok 201 Pet "Created"
+// !response-ref 404 NotFound
+func CreatePet() {}
+`)
+
+	doc := h.parse().Generate()
+	checkSharedComponents(t, doc.Components)
+
+	params := doc.Paths["/pets"].Get.Parameters
+	if len(params) != 2 || params[0].Ref != "#/components/parameters/PageSize" || params[1].Ref != "#/components/parameters/PageToken" {
+		t.Errorf("listPets parameters = %+v, want refs to PageSize and PageToken", params)
+	}
+	create := doc.Paths["/pets"].Post
+	if create.RequestBody.Ref != "#/components/requestBodies/NewPet" {
+		t.Errorf("createPet body = %+v, want ref to NewPet", create.RequestBody)
+	}
+	if create.Responses["404"].Ref != "#/components/responses/NotFound" || create.Responses["201"].Ref != "" {
+		t.Errorf("createPet responses = %+v, want 404 ref to NotFound", create.Responses)
+	}
+}
+
+func checkSharedComponents(t *testing.T, components *openapi.Components) {
+	t.Helper()
+	if limit := components.Parameters["PageSize"]; limit == nil || limit.Name != "limit" || limit.In != openapi.ParameterInQuery {
+		t.Errorf("PageSize parameter = %+v, want query limit", limit)
+	}
+	if body := components.RequestBodies["NewPet"]; body == nil || !body.Required || body.Content["application/json"].Schema.Ref != "#/components/schemas/Pet" {
+		t.Errorf("NewPet body = %+v, want required Pet", body)
+	}
+	if resp := components.Responses["NotFound"]; resp == nil || resp.Description != "Resource not found" {
+		t.Errorf("NotFound response = %+v", resp)
+	}
+}
+
+func TestParser_OperationServersAndExternalDocs(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", `package main
+
+// !api 3.0.3
+// !server https://api.example.com "Production"
+// !externalDocs https://docs.example.com "API guide"
+
+// !POST /uploads -> uploadFile "Upload a file" #files
+// !op-server https://uploads.example.com "Upload server"
+// !op-server https://uploads-eu.example.com
+// !op-externalDocs https://docs.example.com/uploads "Upload limits"
+// !ok 201 File "Uploaded"
+func UploadFile() {}
+
+// !GET /files -> listFiles "List files" #files
+// !ok File "Files"
+func ListFiles() {}
+`)
+
+	doc := h.parse().Generate()
+	op := doc.Paths["/uploads"].Post
+	wantServers := []openapi.Server{
+		{URL: "https://uploads.example.com", Description: "Upload server"},
+		{URL: "https://uploads-eu.example.com"},
+	}
+	if !reflect.DeepEqual(op.Servers, wantServers) {
+		t.Errorf("uploadFile servers = %+v, want %+v", op.Servers, wantServers)
+	}
+	if op.ExternalDocs == nil || op.ExternalDocs.URL != "https://docs.example.com/uploads" || op.ExternalDocs.Description != "Upload limits" {
+		t.Errorf("uploadFile externalDocs = %+v", op.ExternalDocs)
+	}
+
+	if list := doc.Paths["/files"].Get; list.Servers != nil || list.ExternalDocs != nil {
+		t.Errorf("listFiles should inherit the document servers and docs, got %+v and %+v", list.Servers, list.ExternalDocs)
+	}
+	if len(doc.Servers) != 1 || doc.ExternalDocs == nil || doc.ExternalDocs.URL != "https://docs.example.com" {
+		t.Errorf("document servers = %+v, externalDocs = %+v", doc.Servers, doc.ExternalDocs)
+	}
+}
+
+func TestParser_ServerVariables(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", `package main
+
+// !api 3.0.3
+// !info "Regional API" v1.0.0
+// !server https://{region}.api.example.com/{basePath} "Regional endpoint"
+// !server-var region default=us-east enum=us-east,eu-west "Deployment region"
+// !server-var basePath default=v1
+// !server https://localhost:8080 "Local"
+
+// !server-var stray default=x
+
+// !POST /uploads -> uploadFile "Upload a file"
+// !op-server https://{bucket}.storage.example.com
+// !server-var bucket enum=uploads,archive
+// !ok 201 File "Uploaded"
+func UploadFile() {}
+`)
+
+	doc := h.parse().Generate()
+	if len(doc.Servers) != 2 {
+		t.Fatalf("servers = %+v, want 2", doc.Servers)
+	}
+	want := map[string]openapi.ServerVariable{
+		"region":   {Enum: []string{"us-east", "eu-west"}, Default: "us-east", Description: "Deployment region"},
+		"basePath": {Default: "v1"},
+	}
+	if !reflect.DeepEqual(doc.Servers[0].Variables, want) {
+		t.Errorf("server variables = %+v, want %+v", doc.Servers[0].Variables, want)
+	}
+	if doc.Servers[1].Variables != nil {
+		t.Errorf("variables of a later server or another comment = %+v, want none", doc.Servers[1].Variables)
+	}
+
+	op := doc.Paths["/uploads"].Post
+	wantOp := map[string]openapi.ServerVariable{"bucket": {Enum: []string{"uploads", "archive"}, Default: "uploads"}}
+	if len(op.Servers) != 1 || !reflect.DeepEqual(op.Servers[0].Variables, wantOp) {
+		t.Errorf("operation servers = %+v, want bucket variable defaulting to its first value", op.Servers)
+	}
+}
+
+func TestParser_Extensions(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", `package main
+
+// !api 3.0.3
+// !info "Pet API" v1.0.0
+// !x x-tagGroups [{"name":"Pets","tags":["pets"]}]
+
+// !GET /pets -> listPets "List pets"
+// !x x-internal
+// !x codegen {"skip":true}
+// !ok []Pet "Pets"
+func ListPets() {}
+
+// !model "A pet"
+// !x x-go-type pets.Pet
+type Pet struct {
+	// !field age:integer "Age in years"
+	// !x x-go-name PetAge
+	Age int `+"`json:\"age\"`"+`
+}
+`)
+
+	doc := h.parse().Generate()
+	wantDoc := openapi.Extensions{"x-tagGroups": []any{map[string]any{"name": "Pets", "tags": []any{"pets"}}}}
+	if !reflect.DeepEqual(doc.Extensions, wantDoc) {
+		t.Errorf("document extensions = %v, want %v", doc.Extensions, wantDoc)
+	}
+	wantOp := openapi.Extensions{"x-internal": true, "x-codegen": map[string]any{"skip": true}}
+	if op := doc.Paths["/pets"].Get; !reflect.DeepEqual(op.Extensions, wantOp) {
+		t.Errorf("operation extensions = %v, want %v", op.Extensions, wantOp)
+	}
+	pet := doc.Components.Schemas["Pet"]
+	if want := (openapi.Extensions{"x-go-type": "pets.Pet"}); !reflect.DeepEqual(pet.Extensions, want) {
+		t.Errorf("model extensions = %v, want %v", pet.Extensions, want)
+	}
+	if want := (openapi.Extensions{"x-go-name": "PetAge"}); !reflect.DeepEqual(pet.Properties["age"].Extensions, want) {
+		t.Errorf("field extensions = %v, want %v", pet.Properties["age"].Extensions, want)
+	}
+}
+
+func TestParser_DeclarationOrder(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", `package main
+
+// !GET /zoo -> listZoo "List zoo"
+// !ok Zebra "Zebra"
+// !error 404 "Not found"
+// !error 400 "Bad request"
+func ListZoo() {}
+
+// !GET /aquarium -> listAquarium "List aquarium"
+// !ok Zebra "Zebra"
+func ListAquarium() {}
+
+// !model "A zebra"
+type Zebra struct {
+	Stripes int    `+"`json:\"stripes\"`"+`
+	Name    string `+"`json:\"name\"`"+`
+	Age     int    `+"`json:\"age\"`"+`
+}
+`)
+
+	doc := h.parse().Generate()
+	if want := []string{"/zoo", "/aquarium"}; !reflect.DeepEqual(doc.PathOrder, want) {
+		t.Errorf("PathOrder = %v, want %v", doc.PathOrder, want)
+	}
+	if want := []string{"200", "404", "400"}; !reflect.DeepEqual(doc.Paths["/zoo"].Get.ResponseOrder, want) {
+		t.Errorf("ResponseOrder = %v, want %v", doc.Paths["/zoo"].Get.ResponseOrder, want)
+	}
+	if want := []string{"stripes", "name", "age"}; !reflect.DeepEqual(doc.Components.Schemas["Zebra"].PropertyOrder, want) {
+		t.Errorf("PropertyOrder = %v, want %v", doc.Components.Schemas["Zebra"].PropertyOrder, want)
+	}
+}
+
+func TestParser_DeprecatedField(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("models.go", `package main
+
+// !model "A pet"
+type Pet struct {
+	// !field name:string "Pet name" required
+	Name string `+"`json:\"name\"`"+`
+	// !field nickname:string "Use name instead" deprecated
+	Nickname string `+"`json:\"nickname,omitempty\"`"+`
+}
+`)
+
+	doc := h.parse().Generate()
+	pet := doc.Components.Schemas["Pet"]
+	if !pet.Properties["nickname"].Deprecated || pet.Properties["name"].Deprecated {
+		t.Errorf("deprecated = name %v, nickname %v; want only nickname", pet.Properties["name"].Deprecated, pet.Properties["nickname"].Deprecated)
+	}
+}
+
+func TestParser_ReadWriteOnlyFields(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("models.go", `package main
+
+// !model "A user"
+type User struct {
+	// !field id:int64 "User ID" readonly
+	ID int64 `+"`json:\"id\"`"+`
+	// !field password:string "Password" writeonly
+	Password string `+"`json:\"password\"`"+`
+	Name string `+"`json:\"name\"`"+`
+}
+`)
+
+	user := h.parse().Generate().Components.Schemas["User"]
+	for name, want := range map[string][2]bool{"id": {true, false}, "password": {false, true}, "name": {false, false}} {
+		prop := user.Properties[name]
+		if got := [2]bool{prop.ReadOnly, prop.WriteOnly}; got != want {
+			t.Errorf("%s readOnly, writeOnly = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestParser_Views(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("models.go", `package main
+
+// !model "A resource"
+type Resource struct {
+	ID int64 `+"`json:\"id\"`"+`
+}
+
+// !model "A pet"
+// !view PetCreate omit=id,status "A pet to create"
+// !view PetUpdate optional=name required=tag
+// !view PetSummary pick=id,name
+type Pet struct {
+	ID     int64  `+"`json:\"id\"`"+`
+	Name   string `+"`json:\"name\"`"+`
+	Status string `+"`json:\"status\"`"+`
+	Tag    string `+"`json:\"tag,omitempty\"`"+`
+}
+
+// !model "A dog"
+// !view DogCreate omit=breed
+// !view DogPatch omit=id,good
+type Dog struct {
+	Resource
+	Breed string `+"`json:\"breed\"`"+`
+	Good  bool   `+"`json:\"good\"`"+`
+}
+`)
+
+	p := h.parse()
+	schemas := p.Generate().Components.Schemas
+	tests := []struct {
+		name, properties, required, description string
+	}{
+		{"Pet", "id,name,status,tag", "id,name,status", "A pet"},
+		{"PetCreate", "name,tag", "name", "A pet to create"},
+		{"PetUpdate", "id,name,status,tag", "id,status,tag", "A pet"},
+		{"PetSummary", "id,name", "id,name", "A pet"},
+	}
+	for _, tt := range tests {
+		schema := schemas[tt.name]
+		if schema == nil {
+			t.Errorf("%s is missing", tt.name)
+			continue
+		}
+		if got := strings.Join(schema.PropertyOrder, ","); got != tt.properties {
+			t.Errorf("%s properties = %s, want %s", tt.name, got, tt.properties)
+		}
+		if got := strings.Join(schema.Required, ","); got != tt.required {
+			t.Errorf("%s required = %s, want %s", tt.name, got, tt.required)
+		}
+		if schema.Description != tt.description {
+			t.Errorf("%s description = %q, want %q", tt.name, schema.Description, tt.description)
+		}
+	}
+
+	dog := schemas["DogCreate"]
+	if !extends(dog, "Resource", 2) {
+		t.Fatalf("DogCreate = %+v, want allOf Resource", dog)
+	}
+	if got := strings.Join(dog.AllOf[1].PropertyOrder, ","); got != "good" {
+		t.Errorf("DogCreate own properties = %s, want good", got)
+	}
+	if got := strings.Join(schemas["Dog"].AllOf[1].PropertyOrder, ","); got != "breed,good" {
+		t.Errorf("Dog own properties = %s, want the model unchanged", got)
+	}
+
+	// id comes from Resource, so DogPatch cannot omit it
+	want := []string{"!view DogPatch: Dog declares no property id; views only apply to the properties a model declares itself"}
+	if got := diagnosticMessages(p); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diagnostics() = %q, want %q", got, want)
+	}
+}
+
+func TestParser_Owners(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", `package main
+
+// !tag payments "Payment processing"
+// !owner team-payments slack=#payments email=payments@example.com tag=payments,refunds
+
+// !POST /payments -> createPayment "Create payment" #payments
+// !ok 201 Payment "Created"
+func CreatePayment() {}
+
+// !POST /payments/{id}/disputes -> openDispute "Open dispute" #payments
+// !owner team-risk slack=#risk
+// !ok 201 Dispute "Opened"
+func OpenDispute() {}
+`)
+
+	doc := h.parse().Generate()
+	want := &openapi.Owner{Team: "team-payments", Slack: "#payments", Email: "payments@example.com"}
+	if len(doc.Tags) != 2 {
+		t.Fatalf("tags = %+v, want payments and refunds", doc.Tags)
+	}
+	for _, tag := range doc.Tags {
+		if !reflect.DeepEqual(tag.Owner, want) {
+			t.Errorf("tag %s owner = %+v, want %+v", tag.Name, tag.Owner, want)
+		}
+	}
+	if doc.Tags[1].Name != "refunds" {
+		t.Errorf("tags[1] = %s, want undeclared refunds tag appended", doc.Tags[1].Name)
+	}
+
+	if op := doc.Paths["/payments"].Post; op.Owner != nil || !reflect.DeepEqual(doc.OwnerOf(op), want) {
+		t.Errorf("createPayment owner = %+v, resolved %+v, want none and %+v", op.Owner, doc.OwnerOf(op), want)
+	}
+	if op := doc.Paths["/payments/{id}/disputes"].Post; op.Owner == nil || op.Owner.Team != "team-risk" || op.Owner.Slack != "#risk" {
+		t.Errorf("openDispute owner = %+v, want team-risk", op.Owner)
+	}
+}
+
+func TestParser_Examples(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("pet.yaml", "id: 2\nname: rex\n")
+	h.writeFile("api.go", `package main
+
+// !POST /pets -> createPet "Create pet"
+// !body Pet "Pet to add" required
+// !example doggie {"name":"doggie"}
+// !ok 201 Pet "Created"
+// !example created {"id":1,"name":"doggie"}
+// !example file=pet.yaml
+// !error 409 - "Conflict"
+// !example conflict "Pet already exists"
+func CreatePet() {}
+`)
+
+	op := h.parse().Generate().Paths["/pets"].Post
+	body := op.RequestBody.Content["application/json"].Examples
+	if body["doggie"] == nil || body["doggie"].Value.(map[string]any)["name"] != "doggie" {
+		t.Errorf("request body examples = %v, want doggie", body)
+	}
+
+	created := op.Responses["201"].Content["application/json"].Examples
+	if created["created"] == nil || created["created"].Value.(map[string]any)["id"] != 1.0 {
+		t.Errorf("created example = %+v, want id 1", created["created"])
+	}
+	if created["pet"] == nil || created["pet"].Value.(map[string]any)["name"] != "rex" {
+		t.Errorf("file example = %+v, want name rex from pet.yaml", created["pet"])
+	}
+
+	conflict := op.Responses["409"].Content["application/json"].Examples
+	if conflict["conflict"] == nil || conflict["conflict"].Value != "Pet already exists" {
+		t.Errorf("409 examples = %v, want a string example", conflict)
+	}
+}
+
+func TestParser_ExampleFileMissing(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", `package main
+
+// !GET /pets -> listPets "List pets"
+// !ok Pet[] "Pets"
+// !example file=missing.json
+func ListPets() {}
+`)
+	if err := New().ParseDir(h.tmpDir); err == nil {
+		t.Error("ParseDir() should fail when an example file is missing")
+	}
+}
+
+func TestParser_ExampleFileNotRegular(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("examples/pet.json", `{}`)
+	h.writeFile("api.go", `package main
+
+// !GET /pets -> listPets "List pets"
+// !ok Pet[] "Pets"
+// !example file=examples
+func ListPets() {}
+`)
+	if err := New().ParseDir(h.tmpDir); err == nil || !strings.Contains(err.Error(), "not a regular file") {
+		t.Errorf("ParseDir() error = %v, want a not a regular file error", err)
+	}
+}
+
+func TestParser_HTTPMethods(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", httpMethodsTestContent)
+
+	p := h.parse()
+	doc := p.Generate()
+	if doc == nil {
+		t.Fatal("Expected document to be generated")
+	}
+
+	t.Run("resource_path", func(t *testing.T) {
+		verifyResourcePath(t, doc)
+	})
+	t.Run("resource_id_path", func(t *testing.T) {
+		verifyResourceIDPath(t, doc)
+	})
+}
+
+const httpMethodsTestContent = `package main
+
+// !api 3.0.3
+// !info "Test API" v1.0.0 "Test"
+func main() {}
+
+// !GET /resource -> getResource "Get"
+// !ok - "Success"
+func Get() {}
+
+// !POST /resource -> createResource "Post"
+// !ok - "Success"
+func Post() {}
+
+// !PUT /resource/{id} -> updateResource "Put"
+// !ok - "Success"
+func Put() {}
+
+// !DELETE /resource/{id} -> deleteResource "Delete"
+// !ok - "Success"
+func Delete() {}
+
+// !PATCH /resource/{id} -> patchResource "Patch"
+// !ok - "Success"
+func Patch() {}
+
+// !OPTIONS /resource -> optionsResource "Options"
+// !ok - "Success"
+func Options() {}
+
+// !HEAD /resource -> headResource "Head"
+// !ok - "Success"
+func Head() {}
+`
+
+func verifyResourcePath(t *testing.T, doc *openapi.Document) {
+	resourcePath := doc.Paths["/resource"]
+	if resourcePath == nil {
+		t.Fatal("Expected /resource path")
+	}
+	assertNotNil(t, "GET on /resource", resourcePath.Get)
+	assertNotNil(t, "POST on /resource", resourcePath.Post)
+	assertNotNil(t, "OPTIONS on /resource", resourcePath.Options)
+	assertNotNil(t, "HEAD on /resource", resourcePath.Head)
+}
+
+func verifyResourceIDPath(t *testing.T, doc *openapi.Document) {
+	resourceIdPath := doc.Paths["/resource/{id}"]
+	if resourceIdPath == nil {
+		t.Fatal("Expected /resource/{id} path")
+	}
+	assertNotNil(t, "PUT on /resource/{id}", resourceIdPath.Put)
+	assertNotNil(t, "DELETE on /resource/{id}", resourceIdPath.Delete)
+	assertNotNil(t, "PATCH on /resource/{id}", resourceIdPath.Patch)
+}
+
+// TestParser_ParseDirFromCurrentDirectory tests parsing from the current directory using "."
+// This reproduces the bug reported in issue #1
+// currentDirTestSetup contains test fixture data
+type currentDirTestSetup struct {
+	t      *testing.T
+	tmpDir string
+	origWd string
+}
+
+// setupCurrentDirTest creates the test fixture
+func setupCurrentDirTest(t *testing.T) *currentDirTestSetup {
+	tmpDir, err := os.MkdirTemp("", "yaswag-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	origWd, err := os.Getwd()
+	if err != nil {
+		_ = os.RemoveAll(tmpDir)
+		t.Fatal(err)
+	}
+
+	return &currentDirTestSetup{t: t, tmpDir: tmpDir, origWd: origWd}
+}
+
+// cleanup restores the working directory and removes temp files
+func (s *currentDirTestSetup) cleanup() {
+	_ = os.Chdir(s.origWd)
+	_ = os.RemoveAll(s.tmpDir)
+}
+
+// createSubdirWithFile creates a subdirectory and writes a file to it
+func (s *currentDirTestSetup) createSubdirWithFile(subdir, filename, content string) {
+	dir := filepath.Join(s.tmpDir, subdir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		s.t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, filename), []byte(content), 0644); err != nil {
+		s.t.Fatal(err)
+	}
+}
+
+// changeToTmpDir changes to the temp directory
+func (s *currentDirTestSetup) changeToTmpDir() {
+	if err := os.Chdir(s.tmpDir); err != nil {
+		s.t.Fatal(err)
+	}
+}
+
+func TestParser_ParseDirFromCurrentDirectory(t *testing.T) {
+	setup := setupCurrentDirTest(t)
+	defer setup.cleanup()
+
+	setup.createSubdirWithFile("docs", "doc.go", currentDirDocContent)
+	setup.createSubdirWithFile("handlers", "handlers.go", currentDirHandlersContent)
+	setup.changeToTmpDir()
+
+	p := New()
+	if err := p.ParseDir("."); err != nil {
+		t.Fatalf("ParseDir(\".\") error = %v", err)
+	}
+
+	spec := p.GetSpec()
+	verifyCurrentDirSpec(t, spec)
+}
+
+const currentDirDocContent = `package docs
+
+// API Documentation
+//
+// !api 3.0.3
+// !info "Test API" v1.0.0 "A test API for issue #1"
+// !server https://api.example.com "Production"
+`
+
+const currentDirHandlersContent = `package handlers
+
+// GetUsers retrieves all users
+//
+// !GET /users -> getUsers "Get all users" #users
+// !ok 200 - "Success"
+func GetUsers() {}
+`
+
+// verifyCurrentDirSpec validates the parsed spec from current directory
+func verifyCurrentDirSpec(t *testing.T, spec *SpecData) {
+	t.Helper()
+	if spec.Info == nil || spec.Info.Title == "" {
+		t.Fatal("Expected to find YaSwag annotations when parsing from current directory")
+	}
+	assertEqual(t, "Info.Title", spec.Info.Title, "Test API")
+	assertEqual(t, "Info.Version", spec.Info.Version, "1.0.0")
+	assertLen(t, "Operations", len(spec.Operations), 1)
+	if len(spec.Operations) > 0 {
+		assertEqual(t, "Operation.Path", spec.Operations[0].Path, "/users")
+		assertEqual(t, "Operation.Method", spec.Operations[0].Method, "GET")
+	}
+}
+
+// TestParser_SchemaInference tests automatic schema inference from Go types and json tags
+func TestParser_SchemaInference(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("models.go", schemaInferenceTestContent)
+
+	p := h.parse()
+	schemas := p.GetGlobalSchemas()
+
+	// Test Product schema (no !field annotations, relies on inference)
+	productSchema := schemas["Product"]
+	if productSchema == nil {
+		t.Fatal("Expected Product schema")
+	}
+
+	props := productSchema.Schema.Properties
+	assertLen(t, "Product properties", len(props), 3) // Internal excluded (json:"-")
+
+	// Verify ID field (required, no omitempty)
+	if props["id"] == nil {
+		t.Fatal("Expected id property")
+	}
+	if !slices.Contains(productSchema.Schema.Required, "id") {
+		t.Error("Expected id to be required (no omitempty)")
+	}
+
+	// Verify name field (optional, has omitempty)
+	if props["name"] == nil {
+		t.Fatal("Expected name property")
+	}
+	if slices.Contains(productSchema.Schema.Required, "name") {
+		t.Error("Expected name to be optional (has omitempty)")
+	}
+
+	// Verify description from inline comment
+	if props["name"].Description == "" {
+		t.Error("Expected name to have description from inline comment")
+	}
+
+	// Verify internal field is excluded (json:"-")
+	if props["internal"] != nil {
+		t.Error("Expected internal field to be excluded (json:\"-\")")
+	}
+
+	// Verify price field type inference
+	if props["price"] == nil {
+		t.Fatal("Expected price property")
+	}
+}
+
+// TestParser_SchemaInferenceMixed tests mixed annotations and inference
+func TestParser_SchemaInferenceMixed(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("models.go", schemaMixedTestContent)
+
+	p := h.parse()
+	schemas := p.GetGlobalSchemas()
+
+	orderSchema := schemas["Order"]
+	if orderSchema == nil {
+		t.Fatal("Expected Order schema")
+	}
+
+	props := orderSchema.Schema.Properties
+
+	// ID has !field annotation - should use annotation description
+	if props["id"] == nil {
 		t.Fatal("Expected id property")
 	}
 	assertEqual(t, "id description", props["id"].Description, "Unique order identifier")
@@ -712,3 +2064,456 @@ type Order struct {
 	Status string ` + "`json:\"status,omitempty\"`" + `
 }
 `
+
+func TestDiscoverRoots(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("billing/main.go", "package main\n\n// !api 3.1.0\n// !info \"Billing\" v1.0.0\n")
+	h.writeFile("billing/handlers/invoices.go", "package handlers\n\n// !GET /invoices -> listInvoices \"List invoices\"\nfunc List() {}\n")
+	h.writeFile("billing/admin/main.go", "package main\n\n// !api 3.1.0\n// !info \"Billing admin\" v1.0.0\n\n// !GET /audit -> listAudit \"List audit log\"\nfunc Audit() {}\n")
+	h.writeFile("users/main.go", "package main\n\n// !api 3.0.3\n// !info \"Users\" v1.0.0\n")
+	h.writeFile("vendor/lib/lib.go", "package lib\n\n// !api 3.0.3\n")
+	h.writeFile("shared/util.go", "package shared\n\n// api helpers, not an !api root\n")
+
+	roots, err := DiscoverRoots(h.tmpDir)
+	if err != nil {
+		t.Fatalf("DiscoverRoots() error = %v", err)
+	}
+	want := []string{
+		filepath.Join(h.tmpDir, "billing"),
+		filepath.Join(h.tmpDir, "billing", "admin"),
+		filepath.Join(h.tmpDir, "users"),
+	}
+	if !slices.Equal(roots, want) {
+		t.Fatalf("DiscoverRoots() = %v, want %v", roots, want)
+	}
+
+	nested := NestedRoots(want[0], roots)
+	if !slices.Equal(nested, want[1:2]) {
+		t.Errorf("NestedRoots() = %v, want %v", nested, want[1:2])
+	}
+
+	p := New()
+	if err := p.ParseDirExcept(want[0], nested); err != nil {
+		t.Fatalf("ParseDirExcept() error = %v", err)
+	}
+	doc := p.Generate()
+	if doc.Info.Title != "Billing" || doc.Paths["/invoices"] == nil || doc.Paths["/audit"] != nil {
+		t.Errorf("billing spec = %q with paths %v, want only the billing operations", doc.Info.Title, doc.Paths)
+	}
+}
+
+func TestParser_Handlers(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("pets.go", petHandlersSource)
+
+	spec := h.parse().GetSpec()
+	var handlers []string
+	for _, op := range spec.Operations {
+		handlers = append(handlers, op.Handler)
+	}
+	if want := []string{"PetHandler.ListPets", "PetHandler.CreatePet", "Health", "DeletePet"}; !slices.Equal(handlers, want) {
+		t.Fatalf("handlers = %v, want %v", handlers, want)
+	}
+	if src := spec.Operations[1].Source; filepath.Base(src.Filename) != "pets.go" || src.Line != 9 {
+		t.Errorf("CreatePet source = %s, want pets.go:9", src)
+	}
+}
+
+const petHandlersSource = `package api
+
+type PetHandler struct{}
+
+// !GET /pets -> listPets "List pets"
+func (h *PetHandler) ListPets() {}
+
+// !POST /pets -> createPet "Create a pet"
+func (h *PetHandler) CreatePet() {}
+
+// !GET /health -> health "Health check"
+func Health() {}
+
+// !DELETE /pets/{id} -> deletePet "Delete a pet"
+func DeletePet() {}
+`
+
+func TestFindTestReferences(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("pets.go", petHandlersSource)
+	h.writeFile("pets_test.go", `package api
+
+import "testing"
+
+func TestPetHandler_CreatePet(t *testing.T) {}
+
+func TestListing(t *testing.T) {
+	h := &PetHandler{}
+	h.ListPets()
+}
+
+func TestHealth_NotReady(t *testing.T) {}
+`)
+
+	handlers := []string{"PetHandler.ListPets", "PetHandler.CreatePet", "Health", "DeletePet"}
+	refs, err := FindTestReferences(h.tmpDir, handlers)
+	if err != nil {
+		t.Fatalf("FindTestReferences() error = %v", err)
+	}
+	for handler, want := range map[string]string{
+		"PetHandler.ListPets":  "TestListing",
+		"PetHandler.CreatePet": "TestPetHandler_CreatePet",
+		"Health":               "TestHealth_NotReady",
+	} {
+		if got := refs[handler]; len(got) != 1 || got[0].Func != want || got[0].Line == 0 {
+			t.Errorf("references to %s = %+v, want %s", handler, got, want)
+		}
+	}
+	if len(refs["DeletePet"]) != 0 {
+		t.Errorf("references to DeletePet = %+v, want none", refs["DeletePet"])
+	}
+}
+
+const swagSource = `// Package main is the pet store.
+//
+//	@title			Pet Store
+//	@version		1.2
+//	@description	Sells pets.
+//	@license.name	Apache 2.0
+//	@host			api.example.com
+//	@BasePath		/v1
+//	@schemes		https
+//
+//	@securityDefinitions.apikey	ApiKeyAuth
+//	@in							header
+//	@name						X-API-Key
+package main
+
+import "example.com/petstore/model"
+
+// Pet is a pet for sale.
+type Pet struct {
+	ID    int64   ` + "`json:\"id\" example:\"1\"`" + `
+	Name  string  ` + "`json:\"name,omitempty\" binding:\"required\" example:\"Rex\"`" + `
+	Owner Owner   ` + "`json:\"owner\"`" + `
+	Kind  string  ` + "`json:\"kind\" enums:\"dog,cat\"`" + `
+}
+
+type Owner struct {
+	Email string ` + "`json:\"email\" format:\"email\"`" + `
+}
+
+// ListPets godoc
+//
+//	@Summary		List pets
+//	@Description	Lists the pets in stock.
+//	@Tags			pets
+//	@Produce		json
+//	@Param			limit	query		int	false	"Page size"	default(20)	maximum(100)
+//	@Success		200		{array}		Pet
+//	@Failure		500		{object}	model.Error	"Server error"
+//	@Router			/pets [get]
+func ListPets() {}
+
+// CreatePet godoc
+//
+//	@Summary	Add a pet
+//	@Tags		pets
+//	@Accept		json
+//	@Param		pet	body		Pet	true	"The pet"
+//	@Success	201	{object}	Pet
+//	@Security	ApiKeyAuth
+//	@Router		/pets [post]
+func CreatePet() {}
+`
+
+// migrateSwag migrates swagSource, with a model in another package, in the
+// directory of h and returns the texts of the issues.
+func migrateSwag(t *testing.T, h *testHelper) []string {
+	t.Helper()
+	h.writeFile("main.go", swagSource)
+	h.writeFile("model/error.go", "package model\n\ntype Error struct {\n\tMessage string `json:\"message\"`\n}\n")
+	h.writeFile("main_test.go", "package main\n")
+
+	files, err := SourceFiles([]string{filepath.Join(h.tmpDir, "...")})
+	if err != nil {
+		t.Fatalf("SourceFiles() error = %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("SourceFiles() = %v, want main.go and model/error.go", files)
+	}
+	migrations, err := MigrateSwag(files)
+	if err != nil {
+		t.Fatalf("MigrateSwag() error = %v", err)
+	}
+	var issues []string
+	for _, mig := range migrations {
+		if !mig.Changed {
+			t.Errorf("%s not changed", mig.Path)
+		}
+		for _, issue := range mig.Issues {
+			issues = append(issues, issue.Text)
+		}
+		h.writeFile(strings.TrimPrefix(mig.Path, h.tmpDir), string(mig.Content))
+	}
+	return issues
+}
+
+func TestMigrateSwag(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	issues := migrateSwag(t, h)
+	wantIssues := []string{
+		`@Param limit query int false "Page size" default(20) maximum(100)`,
+		`enums:"dog,cat"`,
+	}
+	if !slices.Equal(issues, wantIssues) {
+		t.Errorf("issues = %q, want %q", issues, wantIssues)
+	}
+
+	doc := h.parse().Generate()
+	if doc.Info.Title != "Pet Store" || doc.Info.Version != "1.2" || doc.Info.Description != "Sells pets." {
+		t.Errorf("info = %+v", doc.Info)
+	}
+	if len(doc.Servers) != 1 || doc.Servers[0].URL != "https://api.example.com/v1" {
+		t.Errorf("servers = %+v", doc.Servers)
+	}
+	if s := doc.Components.SecuritySchemes["ApiKeyAuth"]; s == nil || s.In != "header" || s.Name != "X-API-Key" {
+		t.Errorf("ApiKeyAuth security scheme = %+v", s)
+	}
+}
+
+func TestMigrateSwag_Operations(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	migrateSwag(t, h)
+	doc := h.parse().Generate()
+	list := doc.Paths["/pets"].Get
+	if list == nil || list.OperationID != "listPets" || list.Summary != "List pets" || !slices.Equal(list.Tags, []string{"pets"}) {
+		t.Fatalf("GET /pets = %+v", list)
+	}
+	if list.Description != "Lists the pets in stock." {
+		t.Errorf("listPets description = %q", list.Description)
+	}
+	if len(list.Parameters) != 1 || list.Parameters[0].Name != "limit" || fmt.Sprint(list.Parameters[0].Example) != "20" {
+		t.Errorf("listPets parameters = %+v", list.Parameters)
+	}
+}
+
+func TestMigrateSwag_Responses(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	migrateSwag(t, h)
+	list := h.parse().Generate().Paths["/pets"].Get
+	if r := list.Responses["200"]; r == nil || r.Content["application/json"].Schema.Items.Ref != "#/components/schemas/Pet" {
+		t.Errorf("listPets 200 response = %+v", r)
+	}
+	if r := list.Responses["500"]; r == nil || r.Description != "Server error" || r.Content["application/json"].Schema.Ref != "#/components/schemas/Error" {
+		t.Errorf("listPets 500 response = %+v", r)
+	}
+}
+
+func TestMigrateSwag_RequestBody(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	migrateSwag(t, h)
+	create := h.parse().Generate().Paths["/pets"].Post
+	if create == nil || create.OperationID != "createPet" || len(create.Security) != 1 || create.RequestBody == nil || !create.RequestBody.Required {
+		t.Fatalf("POST /pets = %+v", create)
+	}
+	if create.Responses["201"] == nil {
+		t.Errorf("createPet responses = %+v", create.Responses)
+	}
+}
+
+func TestMigrateSwag_Models(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	migrateSwag(t, h)
+	doc := h.parse().Generate()
+	pet := doc.Components.Schemas["Pet"]
+	if pet == nil || !slices.Contains(pet.Required, "name") {
+		t.Fatalf("Pet = %+v", pet)
+	}
+	if name := pet.Properties["name"]; name.Example != "Rex" {
+		t.Errorf("Pet.name = %+v", name)
+	}
+	if owner := doc.Components.Schemas["Owner"]; owner == nil || owner.Properties["email"].Format != "email" {
+		t.Errorf("Owner = %+v", owner)
+	}
+	if doc.Components.Schemas["Error"] == nil {
+		t.Error("Error schema missing")
+	}
+}
+
+func TestParser_Cache(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", `package api
+
+// !api 3.0.3
+// !info "Pets" v1.0.0
+
+// !model
+type Pet struct {
+	Name   string `+"`json:\"name\"`"+`
+	Status Status `+"`json:\"status\"`"+`
+}
+
+// !GET /pets -> listPets "List pets"
+// !ok []Pet
+func ListPets() {}
+`)
+	h.writeFile("status.go", "package api\n\ntype Status string\n")
+	h.writeFile("store.go", "package api\n\ntype store struct{}\n\nfunc (s *store) list() {}\n")
+
+	parse := func(cache *Cache) (*Parser, []string) {
+		p := NewWithOptions(Options{Cache: cache})
+		if err := p.ParseDir(h.tmpDir); err != nil {
+			t.Fatalf("ParseDir() error = %v", err)
+		}
+		var parsed []string
+		p.fset.Iterate(func(f *token.File) bool {
+			parsed = append(parsed, filepath.Base(f.Name()))
+			return true
+		})
+		slices.Sort(parsed)
+		return p, parsed
+	}
+
+	path := filepath.Join(h.tmpDir, ".cache", "parse.json")
+	want, _ := parse(nil)
+	cold, parsed := parse(OpenCache(path))
+	if !slices.Equal(parsed, []string{"api.go", "status.go", "store.go"}) {
+		t.Errorf("parsed without a cache = %v, want every file", parsed)
+	}
+	if err := cold.opts.Cache.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// unchanged files without annotations are only parsed when their types are used
+	warm, parsed := parse(OpenCache(path))
+	if !slices.Equal(parsed, []string{"api.go", "status.go"}) {
+		t.Errorf("parsed with a cache = %v, want api.go and status.go", parsed)
+	}
+	if got := warm.Generate(); !reflect.DeepEqual(got, want.Generate()) {
+		t.Errorf("spec with a cache differs: %+v", got.Components.Schemas["Pet"])
+	}
+
+	// changed files are parsed again
+	h.writeFile("store.go", "package api\n\n// !model\ntype Store struct{}\n")
+	changed, parsed := parse(OpenCache(path))
+	if !slices.Equal(parsed, []string{"api.go", "status.go", "store.go"}) {
+		t.Errorf("parsed after a change = %v, want every file", parsed)
+	}
+	if changed.Generate().Components.Schemas["Store"] == nil {
+		t.Error("Store schema of the changed file missing")
+	}
+}
+
+// settle sets the mtime of the files to a fixed time in the past, so that
+// a cache trusts it.
+func (h *testHelper) settle(names ...string) {
+	past := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for _, name := range names {
+		if err := os.Chtimes(filepath.Join(h.tmpDir, name), past, past); err != nil {
+			h.t.Fatal(err)
+		}
+	}
+}
+
+func TestParser_CacheStatAndAnnotations(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", `package api
+
+// !GET /pets -> listPets "List pets"
+func ListPets() {}
+`)
+	h.writeFile("store.go", "package api\n\ntype store struct{}\n")
+	h.settle("api.go", "store.go")
+
+	path := filepath.Join(h.tmpDir, ".cache", "parse.json")
+	parse := func() *Parser {
+		p := NewWithOptions(Options{Cache: OpenCache(path)})
+		if err := p.ParseDir(h.tmpDir); err != nil {
+			t.Fatalf("ParseDir() error = %v", err)
+		}
+		if err := p.opts.Cache.Save(); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		return p
+	}
+	p := parse()
+
+	// the annotations of unchanged files come from the cache
+	key := cacheKey(filepath.Join(h.tmpDir, "api.go"))
+	comments := p.opts.Cache.files[key].Comments
+	if len(comments) != 1 {
+		t.Fatalf("cached comments = %+v, want the annotations of api.go", comments)
+	}
+	for _, comment := range comments {
+		comment.Annotations[0].Args["summary"] = "From the cache"
+	}
+	if err := p.opts.Cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if ops := parse().GetSpec().Operations; len(ops) != 1 || ops[0].Summary != "From the cache" {
+		t.Errorf("operations = %+v, want the cached summary", ops)
+	}
+
+	// files with the same mtime and size are not read again
+	h.writeFile("store.go", "package api\n\ntype Store struct{}\n")
+	h.settle("store.go")
+	if _, ok := parse().deferred["store"]; !ok {
+		t.Error("store.go was read again, although its mtime and size are unchanged")
+	}
+}
+
+func TestParser_ParseDirConcurrentOrder(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", "package api\n\n// !api 3.0.3\n// !info \"Orders\" v1.0.0\n")
+	var want []string
+	for i := range 40 {
+		h.writeFile(fmt.Sprintf("pkg%02d/handler.go", i), fmt.Sprintf(`package pkg%02d
+
+// !model
+type Shared struct {
+	Field%02d string `+"`json:\"field%02d\"`"+`
+}
+
+// !GET /items/%02d -> getItem%02d "Get item"
+// !ok Shared
+func GetItem() {}
+`, i, i, i, i, i))
+		want = append(want, fmt.Sprintf("getItem%02d", i))
+	}
+
+	for range 5 {
+		spec := h.parse().GetSpec()
+		var got []string
+		for _, op := range spec.Operations {
+			got = append(got, op.OperationID)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("operations = %v, want the order of their files", got)
+		}
+		// the type declared by the last file wins, as when parsing one file at a time
+		if shared := spec.Schemas["Shared"]; shared == nil || shared.Schema.Properties["field39"] == nil {
+			t.Fatalf("Shared = %+v, want the model of the last file", shared)
+		}
+	}
+}
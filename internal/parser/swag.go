@@ -0,0 +1,1146 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// SwagMigration is the migration of one Go file from swaggo/swag comment
+// annotations to YaSwag annotations.
+type SwagMigration struct {
+	Path      string
+	Content   []byte       // the file with its annotations rewritten
+	Changed   bool         // whether Content differs from the file
+	Converted int          // swag annotations and struct tags converted
+	Issues    []SwagIssue  // what could not be converted, or only in part
+	lines     []string     // lines of the file
+	edits     map[int]edit // edits by line number
+}
+
+// SwagIssue is a swag annotation or struct tag that could not be converted
+// to YaSwag annotations, or only in part.
+type SwagIssue struct {
+	Line   int
+	Text   string // the annotation or struct tag
+	Reason string
+}
+
+// edit replaces a line of a file, or inserts lines before it.
+type edit struct {
+	replace bool     // replace the line with lines, instead of inserting them
+	lines   []string // comment lines, without the indentation of the line
+}
+
+// SourceFiles returns the Go files, other than tests, matched by patterns
+// as in the go tool: dir/... matches the files under dir, a directory the
+// files in it, and a file itself.
+func SourceFiles(patterns []string) ([]string, error) {
+	var files []string
+	for _, pattern := range patterns {
+		matched, err := patternFiles(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range matched {
+			if !slices.Contains(files, path) {
+				files = append(files, path)
+			}
+		}
+	}
+	return files, nil
+}
+
+// patternFiles returns the Go files, other than tests, matched by pattern.
+func patternFiles(pattern string) ([]string, error) {
+	if dir, ok := strings.CutSuffix(filepath.ToSlash(pattern), "/..."); ok {
+		return treeFiles(filepath.Clean(filepath.FromSlash(dir)))
+	}
+	info, err := os.Stat(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{filepath.Clean(pattern)}, nil
+	}
+	entries, err := os.ReadDir(pattern)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if path := filepath.Join(pattern, entry.Name()); !entry.IsDir() && isSourceFile(path) {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// treeFiles returns the Go files, other than tests, under root.
+func treeFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		switch {
+		case err != nil:
+			return err
+		case info.IsDir() && path != root && skipDir(info.Name()):
+			return filepath.SkipDir
+		case !info.IsDir() && isSourceFile(path):
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// MigrateSwag rewrites the swag annotations of the Go files at paths as
+// YaSwag annotations:
+//
+//   - the general API info (@title, @version, @host, @BasePath,
+//     @securityDefinitions, ...) becomes !api, !info, !server, !security,
+//     and the other API-level annotations
+//   - the annotations of an operation (@Summary, @Tags, @Param, @Success,
+//     @Failure, @Router, @Security, ...) become its route, parameter, body,
+//     and response annotations, with @Accept and @Produce as their media
+//     types
+//   - the struct types the operations use, directly or through their
+//     fields, become models, annotated with !model, and the example,
+//     format, and binding or validate required struct tags of their fields
+//     become !field annotations
+//
+// Model references lose their package qualifier, as in Account for
+// model.Account. What has no YaSwag counterpart, such as enums, validation
+// attributes, and operation descriptions, is reported as an issue; the
+// description of an operation is kept as comment text.
+func MigrateSwag(paths []string) ([]*SwagMigration, error) {
+	fset := token.NewFileSet()
+	m := &swagMigrator{types: make(map[string][]swagType)}
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, path, content, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		mig := &SwagMigration{Path: path, lines: strings.Split(string(content), "\n"), edits: make(map[int]edit)}
+		m.files = append(m.files, swagFile{mig: mig, ast: f})
+		m.declareTypes(fset, mig, f)
+	}
+
+	for _, file := range m.files {
+		m.migrateComments(fset, file)
+	}
+	m.markModels(fset)
+
+	results := make([]*SwagMigration, 0, len(m.files))
+	for _, file := range m.files {
+		file.mig.render()
+		results = append(results, file.mig)
+	}
+	return results, nil
+}
+
+type swagMigrator struct {
+	files []swagFile
+	types map[string][]swagType // struct types by name
+	refs  []swagRef             // model references of the annotations
+}
+
+type swagFile struct {
+	mig *SwagMigration
+	ast *ast.File
+}
+
+// swagType is a struct type declared in a migrated file.
+type swagType struct {
+	mig    *SwagMigration
+	spec   *ast.TypeSpec
+	line   int  // line of the type's declaration, where !model goes
+	model  bool // annotated with !model, or about to be
+	fields []swagField
+}
+
+// swagField is a field of a struct type, with the types its type names.
+type swagField struct {
+	field    *ast.Field
+	line     int
+	embedded bool
+	names    []string
+}
+
+// swagRef is a type name used by an annotation.
+type swagRef struct {
+	name string
+	mig  *SwagMigration
+	line int
+}
+
+func (m *swagMigrator) declareTypes(fset *token.FileSet, mig *SwagMigration, f *ast.File) {
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok || ts.TypeParams != nil {
+				continue
+			}
+			doc, pos := ts.Doc, ts.Pos()
+			if !gen.Lparen.IsValid() {
+				doc, pos = gen.Doc, gen.Pos()
+			}
+			t := swagType{
+				mig:   mig,
+				spec:  ts,
+				line:  fset.Position(pos).Line,
+				model: doc != nil && strings.Contains(doc.Text(), "!model"),
+			}
+			for _, field := range st.Fields.List {
+				t.fields = append(t.fields, swagField{
+					field:    field,
+					line:     fset.Position(field.Pos()).Line,
+					embedded: len(field.Names) == 0,
+					names:    typeNames(field.Type),
+				})
+			}
+			m.types[ts.Name.Name] = append(m.types[ts.Name.Name], t)
+		}
+	}
+}
+
+// typeNames returns the names of the types expr uses, without package
+// qualifiers.
+func typeNames(expr ast.Expr) []string {
+	var names []string
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			names = append(names, n.Sel.Name)
+			return false
+		case *ast.Ident:
+			names = append(names, n.Name)
+		}
+		return true
+	})
+	return names
+}
+
+// swagLine is a swag annotation: a comment line starting with @.
+type swagLine struct {
+	line    int
+	keyword string // lower case, without @
+	text    string // the annotation, as written
+	value   string // what follows the keyword
+}
+
+var swagGeneralKeywords = []string{
+	"title", "version", "host", "basepath", "schemes", "termsofservice",
+	"contact.", "license.", "securitydefinitions.", "tag.", "externaldocs.", "query.collection.format",
+}
+
+// migrateComments converts the swag annotations of each comment group of
+// the file.
+func (m *swagMigrator) migrateComments(fset *token.FileSet, file swagFile) {
+	funcs, types := commentOwners(file.ast)
+	mig := file.mig
+	for _, group := range file.ast.Comments {
+		lines := swagLines(fset, group)
+		if len(lines) == 0 {
+			continue
+		}
+
+		var converted []string
+		switch {
+		case slices.ContainsFunc(lines, func(l swagLine) bool { return l.keyword == "router" }):
+			converted = m.operation(mig, lines, funcs[group])
+		case slices.ContainsFunc(lines, isGeneralLine):
+			converted = m.general(mig, lines)
+		case types[group] != "":
+			converted = m.model(mig, lines, types[group])
+		default:
+			for _, l := range lines {
+				mig.issue(l.line, l.text, "not in the comment of an operation, a model, or the general API info")
+			}
+			continue
+		}
+
+		mig.edits[lines[0].line] = edit{replace: true, lines: converted}
+		for _, l := range lines[1:] {
+			mig.edits[l.line] = edit{replace: true}
+		}
+	}
+}
+
+// commentOwners returns the names of the functions and types of f by their
+// doc comments.
+func commentOwners(f *ast.File) (funcs, types map[*ast.CommentGroup]string) {
+	funcs = make(map[*ast.CommentGroup]string)
+	types = make(map[*ast.CommentGroup]string)
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Doc != nil {
+				funcs[d.Doc] = d.Name.Name
+			}
+		case *ast.GenDecl:
+			typeComments(d, types)
+		}
+	}
+	return funcs, types
+}
+
+// typeComments adds the names of the types of decl to types, by their doc
+// comments, the comment of decl for a type declared on its own.
+func typeComments(decl *ast.GenDecl, types map[*ast.CommentGroup]string) {
+	for _, spec := range decl.Specs {
+		ts, ok := spec.(*ast.TypeSpec)
+		switch {
+		case !ok:
+		case ts.Doc != nil:
+			types[ts.Doc] = ts.Name.Name
+		case decl.Doc != nil && !decl.Lparen.IsValid():
+			types[decl.Doc] = ts.Name.Name
+		}
+	}
+}
+
+// swagLines returns the swag annotations of a comment group.
+func swagLines(fset *token.FileSet, group *ast.CommentGroup) []swagLine {
+	var lines []swagLine
+	for _, c := range group.List {
+		text, ok := strings.CutPrefix(c.Text, "//")
+		text = strings.TrimSpace(text)
+		if !ok || !strings.HasPrefix(text, "@") || len(text) < 2 || !unicode.IsLetter(rune(text[1])) {
+			continue
+		}
+		keyword, value, _ := strings.Cut(text[1:], " ")
+		if k, v, found := strings.Cut(keyword, "\t"); found {
+			keyword, value = k, v+" "+value
+		}
+		lines = append(lines, swagLine{
+			line:    fset.Position(c.Pos()).Line,
+			keyword: strings.ToLower(keyword),
+			text:    text,
+			value:   strings.TrimSpace(value),
+		})
+	}
+	return lines
+}
+
+func isGeneralLine(l swagLine) bool {
+	return slices.ContainsFunc(swagGeneralKeywords, func(k string) bool {
+		return l.keyword == k || strings.HasSuffix(k, ".") && strings.HasPrefix(l.keyword, k)
+	})
+}
+
+func (mig *SwagMigration) issue(line int, text, reason string) {
+	mig.Issues = append(mig.Issues, SwagIssue{Line: line, Text: strings.Join(strings.Fields(text), " "), Reason: reason})
+}
+
+// unconverted reports the annotation l as an issue, no longer counting it as
+// converted.
+func (mig *SwagMigration) unconverted(l swagLine, reason string) {
+	mig.Converted--
+	mig.issue(l.line, l.text, reason)
+}
+
+// swagSecurity is a security definition of the general API info.
+type swagSecurity struct {
+	name, kind, flow    string
+	in, key             string
+	tokenURL, authURL   string
+	description         string
+	scopes, scopeDescrs []string
+}
+
+// swagInfo collects the general API info.
+type swagInfo struct {
+	title, version, description string
+	contact                     [3]string // name, email, url
+	license                     [2]string // name, url
+	tos, host, basePath         string
+	schemes                     []string
+	docs                        [2]string // url, description
+	tags                        [][2]string
+	securities                  []*swagSecurity
+	security                    *swagSecurity // the definition attributes apply to
+	extensions                  []string
+}
+
+// swagInfoKeywords convert the general API info annotations by keyword. A
+// conversion that fails reports an issue and uncounts the annotation.
+var swagInfoKeywords = map[string]func(info *swagInfo, mig *SwagMigration, l swagLine){
+	"title":          func(info *swagInfo, _ *SwagMigration, l swagLine) { info.title = l.value },
+	"version":        func(info *swagInfo, _ *SwagMigration, l swagLine) { info.version = l.value },
+	"termsofservice": func(info *swagInfo, _ *SwagMigration, l swagLine) { info.tos = l.value },
+	"contact.name":   func(info *swagInfo, _ *SwagMigration, l swagLine) { info.contact[0] = l.value },
+	"contact.email":  func(info *swagInfo, _ *SwagMigration, l swagLine) { info.contact[1] = l.value },
+	"contact.url":    func(info *swagInfo, _ *SwagMigration, l swagLine) { info.contact[2] = l.value },
+	"license.name":   func(info *swagInfo, _ *SwagMigration, l swagLine) { info.license[0] = l.value },
+	"license.url":    func(info *swagInfo, _ *SwagMigration, l swagLine) { info.license[1] = l.value },
+	"host":           func(info *swagInfo, _ *SwagMigration, l swagLine) { info.host = l.value },
+	"basepath":       func(info *swagInfo, _ *SwagMigration, l swagLine) { info.basePath = l.value },
+	"schemes":        func(info *swagInfo, _ *SwagMigration, l swagLine) { info.schemes = strings.Fields(l.value) },
+	"externaldocs.url": func(info *swagInfo, _ *SwagMigration, l swagLine) {
+		info.docs[0] = l.value
+	},
+	"externaldocs.description": func(info *swagInfo, _ *SwagMigration, l swagLine) {
+		info.docs[1] = l.value
+	},
+	"tag.name": func(info *swagInfo, _ *SwagMigration, l swagLine) {
+		info.tags = append(info.tags, [2]string{l.value})
+	},
+	"tag.description": func(info *swagInfo, mig *SwagMigration, l swagLine) {
+		if len(info.tags) == 0 {
+			mig.unconverted(l, "no YaSwag counterpart")
+			return
+		}
+		info.tags[len(info.tags)-1][1] = l.value
+	},
+	"description": func(info *swagInfo, _ *SwagMigration, l swagLine) {
+		if info.security != nil {
+			info.security.description = l.value
+			return
+		}
+		info.description = strings.TrimSpace(info.description + " " + l.value)
+	},
+}
+
+// swagSecurityKeywords set the attributes of the security definition they
+// follow, by keyword.
+var swagSecurityKeywords = map[string]func(s *swagSecurity, value string){
+	"in":               func(s *swagSecurity, value string) { s.in = value },
+	"name":             func(s *swagSecurity, value string) { s.key = value },
+	"tokenurl":         func(s *swagSecurity, value string) { s.tokenURL = value },
+	"authorizationurl": func(s *swagSecurity, value string) { s.authURL = value },
+}
+
+// general converts the general API info.
+func (m *swagMigrator) general(mig *SwagMigration, lines []swagLine) []string {
+	info := &swagInfo{}
+	for _, l := range lines {
+		mig.Converted++
+		info.convert(mig, l)
+	}
+	return info.annotations(mig, lines[0].line)
+}
+
+// convert applies the general API info annotation l to info.
+func (info *swagInfo) convert(mig *SwagMigration, l swagLine) {
+	k := l.keyword
+	if set, ok := swagSecurityKeywords[k]; ok && info.security != nil {
+		set(info.security, l.value)
+		return
+	}
+	if convert, ok := swagInfoKeywords[k]; ok {
+		convert(info, mig, l)
+		return
+	}
+	switch {
+	case strings.HasPrefix(k, "securitydefinitions."):
+		info.security = swagSecurityDefinition(strings.TrimPrefix(k, "securitydefinitions."), l.value)
+		if info.security == nil {
+			mig.unconverted(l, "unknown security definition type")
+			return
+		}
+		info.securities = append(info.securities, info.security)
+	case strings.HasPrefix(k, "scope.") && info.security != nil:
+		info.security.scopes = append(info.security.scopes, l.text[len("@scope."):len(l.text)-len(l.value)])
+		info.security.scopeDescrs = append(info.security.scopeDescrs, l.value)
+	case strings.HasPrefix(k, "x-"):
+		info.extensions = append(info.extensions, swagExtension(l))
+	default:
+		mig.unconverted(l, "no YaSwag counterpart")
+	}
+}
+
+// annotations renders the API-level annotations of info; line is the line
+// of its first annotation, where issues are reported.
+func (info *swagInfo) annotations(mig *SwagMigration, line int) []string {
+	out := info.apiLines(mig, line)
+	if info.contact != [3]string{} {
+		out = append(out, info.contactLine())
+	}
+	if info.license[0] != "" {
+		out = append(out, strings.TrimSpace("!license "+strings.ReplaceAll(info.license[0], " ", "-")+" "+info.license[1]))
+	}
+	if info.tos != "" {
+		out = append(out, "!tos "+info.tos)
+	}
+	if info.docs[0] != "" {
+		out = append(out, withQuoted("!externalDocs "+info.docs[0], info.docs[1]))
+	}
+	out = append(out, info.serverLines()...)
+	for _, tag := range info.tags {
+		out = append(out, withQuoted("!tag "+tag[0], tag[1]))
+	}
+	for _, s := range info.securities {
+		out = append(out, s.annotations(mig, line)...)
+	}
+	return append(out, info.extensions...)
+}
+
+// apiLines renders the !api and !info lines, if there is a title or version.
+func (info *swagInfo) apiLines(mig *SwagMigration, line int) []string {
+	if info.title == "" && info.version == "" {
+		return nil
+	}
+	v := "0.0.0"
+	if match := swagVersion.FindStringSubmatch(info.version); match != nil {
+		v = match[1]
+	}
+	if v != strings.TrimPrefix(info.version, "v") {
+		mig.issue(line, "@version "+info.version, "!info takes a numeric version, written as "+v)
+	}
+	title := info.title
+	if title == "" {
+		title = "API"
+	}
+	return []string{"!api 3.0.3", withQuoted("!info "+quote(title)+" v"+v, info.description)}
+}
+
+// contactLine renders the !contact line.
+func (info *swagInfo) contactLine() string {
+	line := "!contact " + quote(info.contact[0])
+	if info.contact[1] != "" {
+		line += " <" + info.contact[1] + ">"
+	}
+	if info.contact[2] != "" {
+		line += " (" + info.contact[2] + ")"
+	}
+	return line
+}
+
+// serverLines renders a !server line for each scheme of the host, or one
+// for the base path alone.
+func (info *swagInfo) serverLines() []string {
+	switch {
+	case info.host == "" && info.basePath == "":
+		return nil
+	case info.host == "":
+		return []string{"!server " + info.basePath}
+	}
+	schemes := info.schemes
+	if len(schemes) == 0 {
+		schemes = []string{"http"}
+	}
+	var out []string
+	for _, scheme := range schemes {
+		out = append(out, "!server "+scheme+"://"+info.host+info.basePath)
+	}
+	return out
+}
+
+// swagExtension converts an @x- annotation into an !x annotation.
+func swagExtension(l swagLine) string {
+	return "!x " + l.text[1:len(l.text)-len(l.value)] + l.value
+}
+
+var swagVersion = regexp.MustCompile(`^v?([\d.]+)`)
+
+// swagSecurityDefinition starts the security definition of the given swag
+// type, or returns nil if it is unknown.
+func swagSecurityDefinition(kind, name string) *swagSecurity {
+	s := &swagSecurity{name: name}
+	switch kind {
+	case "basic":
+		s.kind = "http"
+	case "apikey":
+		s.kind = "apiKey"
+	case "oauth2.application":
+		s.kind, s.flow = "oauth2", "clientCredentials"
+	case "oauth2.implicit":
+		s.kind, s.flow = "oauth2", "implicit"
+	case "oauth2.password":
+		s.kind, s.flow = "oauth2", "password"
+	case "oauth2.accesscode":
+		s.kind, s.flow = "oauth2", "authorizationCode"
+	default:
+		return nil
+	}
+	return s
+}
+
+// annotations renders the !security and !scope lines of s.
+func (s *swagSecurity) annotations(mig *SwagMigration, line int) []string {
+	var out []string
+	switch s.kind {
+	case "http":
+		out = append(out, withQuoted("!security "+s.name+":http:basic", s.description))
+	case "apiKey":
+		out = append(out, strings.TrimSpace(withQuoted("!security "+s.name+":apiKey:"+s.in, s.description)+" "+s.key))
+	case "oauth2":
+		url := s.tokenURL
+		if s.flow == "implicit" || s.flow == "authorizationCode" {
+			url = s.authURL
+		}
+		if s.flow == "authorizationCode" && s.tokenURL != "" && s.tokenURL != s.authURL {
+			mig.issue(line, "@tokenUrl "+s.tokenURL, "the authorizationCode flow of !security takes one URL, used as both the authorization and token URL")
+		}
+		out = append(out, strings.TrimSpace(withQuoted("!security "+s.name+":oauth2:"+s.flow, s.description)+" "+url))
+		for i, scope := range s.scopes {
+			out = append(out, withQuoted("!scope "+s.name+" "+scope, s.scopeDescrs[i]))
+		}
+	}
+	return out
+}
+
+// swagMediaTypes are the media types of the @Accept and @Produce aliases.
+var swagMediaTypes = map[string]string{
+	"json":                  "application/json",
+	"xml":                   "text/xml",
+	"plain":                 "text/plain",
+	"html":                  "text/html",
+	"mpfd":                  "multipart/form-data",
+	"x-www-form-urlencoded": "application/x-www-form-urlencoded",
+	"json-api":              "application/vnd.api+json",
+	"json-stream":           "application/x-json-stream",
+	"octet-stream":          "application/octet-stream",
+	"png":                   "image/png",
+	"jpeg":                  "image/jpeg",
+	"gif":                   "image/gif",
+	"event-stream":          "text/event-stream",
+}
+
+var (
+	swagParamPattern    = regexp.MustCompile(`^(\S+)\s+(\w+)\s+(\S+)\s+(\w+)(?:\s+"([^"]*)")?\s*(.*)$`)
+	swagResponsePattern = regexp.MustCompile(`^([\w,]+)(?:\s+\{(\w+)\}\s+(\S+))?(?:\s+"([^"]*)")?`)
+	swagRouterPattern   = regexp.MustCompile(`^(\S+)\s+\[(\w+)\]`)
+	swagAttribute       = regexp.MustCompile(`(\w+)\(([^)]*)\)`)
+	swagQualifier       = regexp.MustCompile(`\b\w+\.(\w+)`)
+	swagIdentifier      = regexp.MustCompile(`[A-Za-z_]\w*`)
+	swagWord            = regexp.MustCompile(`^\w+$`)
+	swagFormType        = regexp.MustCompile(`^(\[\])?\w+$`)
+	swagFieldType       = regexp.MustCompile(`^[\w.\[\]]+$`)
+	swagStatus          = regexp.MustCompile(`^\d{3}$`)
+)
+
+// swagOperation collects the annotations of an operation.
+type swagOperation struct {
+	method, path, id, summary string
+	tags                      []string
+	accept, produce           []string
+	secure                    []string
+	params, body, responses   []string
+	extensions                []string
+	description               []string
+}
+
+// swagOperationKeywords convert the annotations of an operation by keyword.
+// A conversion that fails reports an issue and uncounts the annotation.
+var swagOperationKeywords = map[string]func(m *swagMigrator, mig *SwagMigration, op *swagOperation, l swagLine){
+	"accept":  func(*swagMigrator, *SwagMigration, *swagOperation, swagLine) {}, // read beforehand
+	"produce": func(*swagMigrator, *SwagMigration, *swagOperation, swagLine) {},
+	"summary": func(_ *swagMigrator, _ *SwagMigration, op *swagOperation, l swagLine) { op.summary = l.value },
+	"id":      func(_ *swagMigrator, _ *SwagMigration, op *swagOperation, l swagLine) { op.id = l.value },
+	"description": func(_ *swagMigrator, _ *SwagMigration, op *swagOperation, l swagLine) {
+		op.description = append(op.description, l.value)
+	},
+	"tags":     func(_ *swagMigrator, mig *SwagMigration, op *swagOperation, l swagLine) { op.addTags(mig, l) },
+	"router":   func(_ *swagMigrator, mig *SwagMigration, op *swagOperation, l swagLine) { op.route(mig, l) },
+	"security": func(_ *swagMigrator, mig *SwagMigration, op *swagOperation, l swagLine) { op.addSecurity(mig, l) },
+	"param":    (*swagMigrator).param,
+	"success":  (*swagMigrator).response,
+	"failure":  (*swagMigrator).response,
+	"response": (*swagMigrator).response,
+}
+
+// operation converts the annotations of an operation, whose handler is fn
+// if the comment documents a function.
+func (m *swagMigrator) operation(mig *SwagMigration, lines []swagLine, fn string) []string {
+	op := &swagOperation{}
+	// media types first, as bodies and responses use them
+	for _, l := range lines {
+		switch l.keyword {
+		case "accept":
+			op.accept = swagMediaTypeList(l.value)
+		case "produce":
+			op.produce = swagMediaTypeList(l.value)
+		}
+	}
+
+	for _, l := range lines {
+		mig.Converted++
+		if convert, ok := swagOperationKeywords[l.keyword]; ok {
+			convert(m, mig, op, l)
+			continue
+		}
+		if strings.HasPrefix(l.keyword, "x-") {
+			op.extensions = append(op.extensions, swagExtension(l))
+			continue
+		}
+		mig.unconverted(l, "no YaSwag counterpart")
+	}
+	if op.method == "" {
+		return op.description
+	}
+	return op.annotations(fn)
+}
+
+// addTags converts a @Tags annotation.
+func (op *swagOperation) addTags(mig *SwagMigration, l swagLine) {
+	for _, tag := range strings.Split(l.value, ",") {
+		tag = strings.TrimSpace(tag)
+		word := strings.Map(wordRune, tag)
+		if word != tag {
+			mig.issue(l.line, l.text, fmt.Sprintf("tag %q written as %s, as tags are words", tag, word))
+		}
+		op.tags = append(op.tags, word)
+	}
+}
+
+// wordRune returns r if it may be part of a word, or else an underscore.
+func wordRune(r rune) rune {
+	if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+		return r
+	}
+	return '_'
+}
+
+// route converts a @Router annotation, the first of them.
+func (op *swagOperation) route(mig *SwagMigration, l swagLine) {
+	match := swagRouterPattern.FindStringSubmatch(l.value)
+	method := ""
+	if match != nil {
+		method = strings.ToUpper(match[2])
+	}
+	switch {
+	case match == nil || !slices.Contains([]string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS", "HEAD"}, method):
+		mig.unconverted(l, "not a route YaSwag annotates")
+	case op.method != "":
+		mig.unconverted(l, "only the first route of a handler is converted")
+	default:
+		op.method, op.path = method, match[1]
+	}
+}
+
+// addSecurity converts a @Security annotation.
+func (op *swagOperation) addSecurity(mig *SwagMigration, l swagLine) {
+	for _, alternative := range strings.Split(l.value, "||") {
+		name, scopes, _ := strings.Cut(strings.TrimSpace(alternative), "[")
+		if strings.Contains(name, "&&") {
+			mig.issue(l.line, l.text, "!secure takes alternatives, not schemes required together")
+			continue
+		}
+		if scopes != "" {
+			mig.issue(l.line, l.text, "!secure takes no scopes")
+		}
+		if name = strings.TrimSpace(name); !slices.Contains(op.secure, name) {
+			op.secure = append(op.secure, name)
+		}
+	}
+}
+
+// annotations renders the annotations of the operation, whose handler is fn
+// if the comment documents a function.
+func (op *swagOperation) annotations(fn string) []string {
+	route := withQuoted(fmt.Sprintf("!%s %s -> %s", op.method, op.path, op.operationID(fn)), op.summary)
+	for _, tag := range op.tags {
+		route += " #" + tag
+	}
+
+	var out []string
+	if len(op.description) > 0 {
+		out = append(out, "!description")
+		out = append(out, op.description...)
+	}
+	out = append(out, route)
+	if len(op.secure) > 0 {
+		out = append(out, "!secure "+strings.Join(op.secure, " "))
+	}
+	out = append(out, op.params...)
+	out = append(out, op.body...)
+	out = append(out, op.responses...)
+	return append(out, op.extensions...)
+}
+
+// operationID returns the @ID of the operation, or else the name of its
+// handler fn, or else one made of its method and path.
+func (op *swagOperation) operationID(fn string) string {
+	switch {
+	case op.id != "":
+		return op.id
+	case fn != "":
+		return strings.ToLower(fn[:1]) + fn[1:]
+	}
+	id := strings.ToLower(op.method)
+	for _, w := range strings.FieldsFunc(op.path, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		id += strings.ToUpper(w[:1]) + w[1:]
+	}
+	return id
+}
+
+func swagMediaTypeList(value string) []string {
+	var mediaTypes []string
+	for _, alias := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		if mediaType, ok := swagMediaTypes[alias]; ok {
+			alias = mediaType
+		}
+		mediaTypes = append(mediaTypes, alias)
+	}
+	return mediaTypes
+}
+
+// param converts a @Param annotation.
+func (m *swagMigrator) param(mig *SwagMigration, op *swagOperation, l swagLine) {
+	match := swagParamPattern.FindStringSubmatch(l.value)
+	if match == nil {
+		mig.unconverted(l, "not a @Param of the form name in type required \"description\"")
+		return
+	}
+	name, in, typ := match[1], match[2], match[3]
+	modifiers := paramModifiers(mig, l, in, match[5], strings.EqualFold(match[4], "true"), match[6])
+
+	switch in {
+	case "body":
+		ref := m.reference(mig, l, typ)
+		op.body = append(op.body, "!body "+ref+modifiers+mediaTypes(op.accept, "application/json"))
+	case "formData":
+		op.params = append(op.params, fmt.Sprintf("!form %s:%s%s", name, m.formType(mig, l, typ), modifiers))
+	case "query", "path", "header", "cookie":
+		op.params = append(op.params, fmt.Sprintf("!%s %s:%s%s", in, name, m.paramType(mig, l, typ), modifiers))
+	default:
+		mig.unconverted(l, fmt.Sprintf("unknown parameter location %q", in))
+	}
+}
+
+// paramModifiers renders the description, required, and default modifiers
+// of a parameter in in, reporting the attributes they cannot express.
+func paramModifiers(mig *SwagMigration, l swagLine, in, description string, required bool, attributes string) string {
+	var modifiers string
+	if description != "" {
+		modifiers = " " + quote(description)
+	}
+	if required {
+		modifiers += " required"
+	}
+	for _, attr := range swagAttribute.FindAllStringSubmatch(attributes, -1) {
+		if strings.EqualFold(attr[1], "default") && in != "body" && !strings.ContainsAny(attr[2], " \t") {
+			modifiers += " default=" + attr[2]
+			continue
+		}
+		mig.issue(l.line, l.text, fmt.Sprintf("%s parameters take no %s attribute", in, attr[1]))
+	}
+	return modifiers
+}
+
+// formType returns the type of a form field, a primitive type or an array
+// of one.
+func (m *swagMigrator) formType(mig *SwagMigration, l swagLine, typ string) string {
+	typ = strings.ReplaceAll(m.reference(mig, l, typ), "file", "binary")
+	if !swagFormType.MatchString(typ) {
+		mig.issue(l.line, l.text, "form fields take a primitive type or an array of one, written as object")
+		return "object"
+	}
+	return typ
+}
+
+// paramType returns the type of a query, path, header, or cookie parameter,
+// a primitive type or model.
+func (m *swagMigrator) paramType(mig *SwagMigration, l swagLine, typ string) string {
+	typ = m.reference(mig, l, typ)
+	if strings.HasPrefix(typ, "[]") {
+		mig.issue(l.line, l.text, "parameters take no array item type, written as array")
+		typ = "array"
+	}
+	if !swagWord.MatchString(typ) {
+		mig.issue(l.line, l.text, "parameters take a primitive type or model, written as string")
+		typ = "string"
+	}
+	return typ
+}
+
+// response converts a @Success, @Failure, or @Response annotation.
+func (m *swagMigrator) response(mig *SwagMigration, op *swagOperation, l swagLine) {
+	match := swagResponsePattern.FindStringSubmatch(l.value)
+	if match == nil {
+		mig.unconverted(l, "not a response of the form status {type} model \"description\"")
+		return
+	}
+	ref := "-"
+	if match[3] != "" {
+		ref = m.reference(mig, l, match[3])
+		if match[2] == "array" {
+			ref = "[]" + ref
+		}
+	}
+	var suffix string
+	if match[4] != "" {
+		suffix = " " + quote(match[4])
+	}
+	if ref != "-" {
+		suffix += mediaTypes(op.produce, "application/json")
+	}
+
+	for _, status := range strings.Split(match[1], ",") {
+		switch {
+		case !swagStatus.MatchString(status):
+			mig.issue(l.line, l.text, fmt.Sprintf("responses take a status code, not %s", status))
+		case status == "200":
+			op.responses = append(op.responses, "!ok "+ref+suffix)
+		case strings.HasPrefix(status, "2"):
+			op.responses = append(op.responses, "!ok "+status+" "+ref+suffix)
+		default:
+			op.responses = append(op.responses, "!error "+status+" "+ref+suffix)
+		}
+	}
+}
+
+// swagPrimitives are the swag data types, and Go types, that are not
+// models.
+var swagPrimitives = []string{
+	"string", "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+	"integer", "number", "float32", "float64", "float", "double", "bool", "boolean", "byte", "binary",
+	"object", "array", "any", "interface", "file", "map", "time", "Time",
+}
+
+// reference returns the YaSwag schema reference of the swag data type typ,
+// recording the models it uses.
+func (m *swagMigrator) reference(mig *SwagMigration, l swagLine, typ string) string {
+	if base, _, ok := strings.Cut(typ, "{"); ok {
+		mig.issue(l.line, l.text, "field overrides such as "+typ[len(base):]+" are not converted; declare a model for the composed type")
+		typ = base
+	}
+	typ = swagQualifier.ReplaceAllString(typ, "$1")
+	switch typ {
+	case "interface{}":
+		return "object"
+	case "Time":
+		return "string"
+	}
+	for _, name := range swagIdentifier.FindAllString(typ, -1) {
+		if !slices.Contains(swagPrimitives, name) {
+			m.refs = append(m.refs, swagRef{name: name, mig: mig, line: l.line})
+		}
+	}
+	return typ
+}
+
+// mediaTypes renders the type= modifier of the media types, or nothing if
+// they are just the default.
+func mediaTypes(mediaTypes []string, defaultType string) string {
+	if len(mediaTypes) == 0 || len(mediaTypes) == 1 && mediaTypes[0] == defaultType {
+		return ""
+	}
+	return " type=" + strings.Join(mediaTypes, ",")
+}
+
+// model converts the annotations of a struct type, such as @Description.
+func (m *swagMigrator) model(mig *SwagMigration, lines []swagLine, name string) []string {
+	var description []string
+	for _, l := range lines {
+		if l.keyword != "description" {
+			mig.issue(l.line, l.text, "no YaSwag counterpart")
+			continue
+		}
+		mig.Converted++
+		description = append(description, l.value)
+	}
+	for i := range m.types[name] {
+		if t := &m.types[name][i]; t.mig == mig {
+			t.model = true
+		}
+	}
+	return []string{withQuoted("!model", strings.Join(description, " "))}
+}
+
+// markModels annotates the struct types the operations use, and the struct
+// types of their fields, with !model, converting the struct tags of their
+// fields.
+func (m *swagMigrator) markModels(fset *token.FileSet) {
+	seen := make(map[string]bool)
+	queue := slices.Clone(m.refs)
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		if seen[ref.name] {
+			continue
+		}
+		seen[ref.name] = true
+
+		t := m.modelType(ref)
+		if t == nil {
+			continue
+		}
+		if !t.model {
+			t.model = true
+			t.mig.Converted++
+			t.mig.edits[t.line] = edit{lines: append(t.mig.edits[t.line].lines, "!model")}
+		}
+		for _, f := range t.fields {
+			if !f.embedded {
+				t.mig.fieldTags(f)
+			}
+		}
+		queue = append(queue, m.fieldRefs(t)...)
+	}
+}
+
+// modelType returns the struct type ref names, reporting an issue if it is
+// not declared in the migrated files or declared more than once.
+func (m *swagMigrator) modelType(ref swagRef) *swagType {
+	decls := m.types[ref.name]
+	switch {
+	case ref.mig == nil:
+		// field types such as time.Time are not models
+	case len(decls) == 0:
+		ref.mig.issue(ref.line, ref.name, "not a struct type declared in the migrated files")
+	case len(decls) > 1:
+		ref.mig.issue(ref.line, ref.name, "declared more than once; models are named after their type, so only one of them is annotated")
+	}
+	if len(decls) == 0 {
+		return nil
+	}
+	return &decls[0]
+}
+
+// fieldRefs returns the types the fields of t name. The fields of embedded
+// structs are promoted, so their types are followed but the structs are not
+// models.
+func (m *swagMigrator) fieldRefs(t *swagType) []swagRef {
+	var refs []swagRef
+	for _, f := range t.fields {
+		if !f.embedded {
+			refs = appendRefs(refs, f.names)
+			continue
+		}
+		for _, name := range f.names {
+			if embedded := m.types[name]; len(embedded) > 0 && !embedded[0].model {
+				for _, ef := range embedded[0].fields {
+					refs = appendRefs(refs, ef.names)
+				}
+			}
+		}
+	}
+	return refs
+}
+
+func appendRefs(refs []swagRef, names []string) []swagRef {
+	for _, name := range names {
+		refs = append(refs, swagRef{name: name})
+	}
+	return refs
+}
+
+// fieldTags converts the swag struct tags of f into a !field annotation.
+func (mig *SwagMigration) fieldTags(f swagField) {
+	if f.field.Tag == nil || f.field.Doc != nil && strings.Contains(f.field.Doc.Text(), "!field") {
+		return
+	}
+	tag := reflect.StructTag(strings.Trim(f.field.Tag.Value, "`"))
+	jsonTag, _ := tag.Lookup("json")
+	jsonName, options, _ := strings.Cut(jsonTag, ",")
+	if jsonName == "-" {
+		return
+	}
+
+	modifiers := fieldModifiers(tag, jsonTag == "" || strings.Contains(options, "omitempty"))
+	mig.unconvertedTags(f.line, tag)
+	if len(modifiers) == 0 {
+		return
+	}
+
+	name := f.field.Names[0].Name
+	if jsonName != "" && swagWord.MatchString(jsonName) {
+		name = jsonName
+	}
+	mig.Converted++
+	mig.edits[f.line] = edit{lines: []string{fmt.Sprintf("!field %s:%s %s", name, fieldType(f.field.Type), strings.Join(modifiers, " "))}}
+}
+
+// fieldType returns the type of a !field annotation for the Go type expr,
+// without pointers and package qualifiers, or object if it has no such form.
+func fieldType(expr ast.Expr) string {
+	typ := swagQualifier.ReplaceAllString(strings.TrimLeft(types.ExprString(expr), "*"), "$1")
+	if !swagFieldType.MatchString(typ) {
+		return "object"
+	}
+	return typ
+}
+
+// unconvertedTags reports the swag struct tags of the field on line that
+// have no !field counterpart.
+func (mig *SwagMigration) unconvertedTags(line int, tag reflect.StructTag) {
+	for _, key := range []string{"enums", "swaggertype", "swaggerignore", "default", "minimum", "maximum", "minLength", "maxLength", "extensions"} {
+		if value, ok := tag.Lookup(key); ok {
+			mig.issue(line, key+`:"`+value+`"`, "no !field counterpart")
+		}
+	}
+}
+
+// fieldModifiers returns the required, example, and format modifiers of the
+// struct tag of a field, required only if the field would otherwise be
+// optional.
+func fieldModifiers(tag reflect.StructTag, optional bool) []string {
+	var modifiers []string
+	if optional && (required(tag.Get("binding")) || required(tag.Get("validate"))) {
+		modifiers = append(modifiers, "required")
+	}
+	if example, ok := tag.Lookup("example"); ok {
+		if example == "" || strings.ContainsAny(example, " \t\"") {
+			example = `"` + annotationEscaper.Replace(example) + `"`
+		}
+		modifiers = append(modifiers, "example="+example)
+	}
+	if format, ok := tag.Lookup("format"); ok && format != "" {
+		modifiers = append(modifiers, "format="+format)
+	}
+	return modifiers
+}
+
+// required reports whether the binding or validate tag value requires the
+// field.
+func required(rules string) bool {
+	return slices.Contains(strings.Split(rules, ","), "required")
+}
+
+// render applies the edits to the lines of the file.
+func (mig *SwagMigration) render() {
+	if len(mig.edits) == 0 {
+		mig.Content = []byte(strings.Join(mig.lines, "\n"))
+		return
+	}
+	var out []string
+	for i, line := range mig.lines {
+		e, ok := mig.edits[i+1]
+		if !ok {
+			out = append(out, line)
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		for _, text := range e.lines {
+			out = append(out, strings.TrimRight(indent+"// "+text, " "))
+		}
+		if !e.replace {
+			out = append(out, line)
+		}
+	}
+	mig.Content = []byte(strings.Join(out, "\n"))
+	mig.Changed = true
+}
+
+// quote renders text as a quoted annotation argument on one line.
+func quote(text string) string {
+	return `"` + annotationEscaper.Replace(strings.Join(strings.Fields(text), " ")) + `"`
+}
+
+// withQuoted appends text quoted to line if not empty.
+func withQuoted(line, text string) string {
+	if text == "" {
+		return line
+	}
+	return strings.TrimSpace(line + " " + quote(text))
+}
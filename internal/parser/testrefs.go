@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TestReference is a test file function that refers to a handler.
+type TestReference struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Func string `json:"func"`
+}
+
+// FindTestReferences scans the _test.go files under dir for functions that
+// refer to the given handlers, named as in OperationData.Handler. A function
+// refers to a handler when it uses the handler's name, as in CreatePet(w, r)
+// or h.CreatePet, or when it is named after it, as in TestCreatePet,
+// TestCreatePet_NotFound, or TestPetHandler_CreatePet. The references are
+// keyed by handler.
+func FindTestReferences(dir string, handlers []string) (map[string][]TestReference, error) {
+	root := filepath.Clean(dir)
+	fset := token.NewFileSet()
+	refs := make(map[string][]TestReference)
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != root && skipDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		return scanTestFile(refs, fset, path, handlers)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+// scanTestFile records the references to handlers made by the functions of the test file at path.
+func scanTestFile(refs map[string][]TestReference, fset *token.FileSet, path string, handlers []string) error {
+	f, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+			addTestReferences(refs, fset, fn, handlers)
+		}
+	}
+	return nil
+}
+
+// addTestReferences records fn as a reference to every handler it refers to.
+func addTestReferences(refs map[string][]TestReference, fset *token.FileSet, fn *ast.FuncDecl, handlers []string) {
+	used := make(map[string]bool)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			used[ident.Name] = true
+		}
+		return true
+	})
+
+	pos := fset.Position(fn.Pos())
+	for _, handler := range handlers {
+		if used[handlerFunc(handler)] || namedAfter(fn.Name.Name, handler) {
+			refs[handler] = append(refs[handler], TestReference{File: pos.Filename, Line: pos.Line, Func: fn.Name.Name})
+		}
+	}
+}
+
+// handlerFunc returns the function or method name of a handler.
+func handlerFunc(handler string) string {
+	if _, method, ok := strings.Cut(handler, "."); ok {
+		return method
+	}
+	return handler
+}
+
+// namedAfter reports whether the test named test is named after handler,
+// following the Test<Func> and Test<Type>_<Method> conventions.
+func namedAfter(test, handler string) bool {
+	name, ok := strings.CutPrefix(test, "Test")
+	if !ok {
+		return false
+	}
+	for _, prefix := range []string{handlerFunc(handler), strings.ReplaceAll(handler, ".", "_")} {
+		if name == prefix || strings.HasPrefix(name, prefix+"_") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+// Package shared holds the helpers the packages of yaswag have in common:
+// JSON pointers and component names of a specification, and walking its
+// operations. The severities and pull request comments of reports are in
+// the public package report.
+package shared
+
+import (
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// JSONPointer builds an RFC 6901 JSON pointer from unescaped reference tokens.
+func JSONPointer(tokens ...string) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		token = strings.ReplaceAll(token, "~", "~0")
+		token = strings.ReplaceAll(token, "/", "~1")
+		sb.WriteString("/" + token)
+	}
+	return sb.String()
+}
+
+// ComponentName returns the name of the component a local $ref points to.
+func ComponentName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// Operation is an operation of a document with its path, path item, and
+// method.
+type Operation struct {
+	Path      string
+	Method    string // upper case, e.g. GET
+	Item      *openapi.PathItem
+	Operation *openapi.Operation
+}
+
+// Operations returns the operations of the path items of doc at paths, in
+// the order of paths and, per path, GET, PUT, POST, DELETE, OPTIONS, HEAD,
+// PATCH, and TRACE.
+func Operations(doc *openapi.Document, paths []string) []Operation {
+	var ops []Operation
+	for _, path := range paths {
+		item := doc.Paths[path]
+		if item == nil {
+			continue
+		}
+		for _, o := range []Operation{
+			{path, "GET", item, item.Get},
+			{path, "PUT", item, item.Put},
+			{path, "POST", item, item.Post},
+			{path, "DELETE", item, item.Delete},
+			{path, "OPTIONS", item, item.Options},
+			{path, "HEAD", item, item.Head},
+			{path, "PATCH", item, item.Patch},
+			{path, "TRACE", item, item.Trace},
+		} {
+			if o.Operation != nil {
+				ops = append(ops, o)
+			}
+		}
+	}
+	return ops
+}
@@ -0,0 +1,33 @@
+package shared
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+func TestJSONPointer(t *testing.T) {
+	if got, want := JSONPointer("paths", "/pets/{id}", "get"), "/paths/~1pets~1{id}/get"; got != want {
+		t.Errorf("JSONPointer() = %q, want %q", got, want)
+	}
+	if got, want := JSONPointer("components", "schemas", "a~b"), "/components/schemas/a~0b"; got != want {
+		t.Errorf("JSONPointer() = %q, want %q", got, want)
+	}
+}
+
+func TestOperations(t *testing.T) {
+	op := &openapi.Operation{}
+	doc := &openapi.Document{Paths: openapi.Paths{
+		"/a": {Post: op, Get: op},
+		"/b": {Delete: op},
+		"/c": nil,
+	}}
+	var got []string
+	for _, o := range Operations(doc, []string{"/b", "/c", "/a", "/missing"}) {
+		got = append(got, o.Method+" "+o.Path)
+	}
+	if want := "DELETE /b, GET /a, POST /a"; strings.Join(got, ", ") != want {
+		t.Errorf("Operations() = %v, want %s", got, want)
+	}
+}
@@ -6,26 +6,38 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"slices"
 
 	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"github.com/fathurrohman26/yaswag/pkg/report"
 	"gopkg.in/yaml.v3"
 )
 
 // Severity levels for audit findings
-type Severity string
+type Severity = report.Severity
 
 const (
-	SeverityError   Severity = "ERROR"
-	SeverityWarning Severity = "WARNING"
-	SeverityInfo    Severity = "INFO"
+	SeverityError   = report.SeverityError
+	SeverityWarning = report.SeverityWarning
+	SeverityInfo    = report.SeverityInfo
 )
 
+// ParseSeverity parses a severity name case-insensitively (error, warning, info).
+func ParseSeverity(s string) (Severity, error) {
+	severity, err := report.ParseSeverity(s)
+	if err != nil || severity == report.SeverityOff {
+		return "", fmt.Errorf("unknown severity %q: must be error, warning, or info", s)
+	}
+	return severity, nil
+}
+
 // Finding represents a single audit finding
 type Finding struct {
 	RuleID         string   `json:"rule_id"`
 	RuleName       string   `json:"rule_name"`
 	Severity       Severity `json:"severity"`
 	Location       string   `json:"location"`
+	Pointer        string   `json:"pointer,omitempty"` // JSON pointer into the spec (RFC 6901)
 	Message        string   `json:"message"`
 	Recommendation string   `json:"recommendation"`
 }
@@ -45,25 +57,45 @@ type SecuritySchemeInfo struct {
 
 // AuditResult contains the complete audit results
 type AuditResult struct {
+	Source               string                        `json:"source,omitempty"`
 	TotalEndpoints       int                           `json:"total_endpoints"`
 	ProtectedEndpoints   int                           `json:"protected_endpoints"`
 	UnprotectedEndpoints int                           `json:"unprotected_endpoints"`
 	Findings             []Finding                     `json:"findings"`
+	SeverityCounts       map[Severity]int              `json:"severity_counts"`
 	EndpointsBySecurity  map[string][]string           `json:"endpoints_by_security"`
 	CoverageByTag        map[string]TagCoverage        `json:"coverage_by_tag"`
 	SecuritySchemes      map[string]SecuritySchemeInfo `json:"security_schemes"`
 }
 
+// Config customizes which audit rules run and how severe their findings are
+type Config struct {
+	// Severities overrides the severity of the findings of a rule ID
+	Severities map[string]Severity
+
+	// IgnoreRules lists rule IDs to skip
+	IgnoreRules []string
+}
+
 // Auditor performs security audits on OpenAPI documents
 type Auditor struct {
-	rules []Rule
+	rules  []Rule
+	config Config
 }
 
 // New creates a new Auditor with default rules
 func New() *Auditor {
-	return &Auditor{
-		rules: DefaultRules(),
+	return NewWithConfig(nil)
+}
+
+// NewWithConfig creates a new Auditor with default rules customized by config.
+// A nil config runs every rule at its default severity.
+func NewWithConfig(config *Config) *Auditor {
+	a := &Auditor{rules: DefaultRules()}
+	if config != nil {
+		a.config = *config
 	}
+	return a
 }
 
 // Audit performs a security audit on an OpenAPI document
@@ -72,6 +104,7 @@ func (a *Auditor) Audit(doc *openapi.Document) *AuditResult {
 		EndpointsBySecurity: make(map[string][]string),
 		CoverageByTag:       make(map[string]TagCoverage),
 		SecuritySchemes:     make(map[string]SecuritySchemeInfo),
+		SeverityCounts:      map[Severity]int{SeverityError: 0, SeverityWarning: 0, SeverityInfo: 0},
 	}
 
 	// Analyze security schemes
@@ -85,20 +118,76 @@ func (a *Auditor) Audit(doc *openapi.Document) *AuditResult {
 
 	// Run all audit rules
 	for _, rule := range a.rules {
+		if slices.Contains(a.config.IgnoreRules, rule.ID()) {
+			continue
+		}
 		findings := rule.Check(doc)
+		if severity, ok := a.config.Severities[rule.ID()]; ok {
+			for i := range findings {
+				findings[i].Severity = severity
+			}
+		}
 		result.Findings = append(result.Findings, findings...)
 	}
+	for _, f := range result.Findings {
+		result.SeverityCounts[f.Severity]++
+	}
 
 	return result
 }
 
+// Count returns the number of findings with the given severity.
+func (r *AuditResult) Count(severity Severity) int {
+	return report.Count(r.Findings, findingSeverity, severity)
+}
+
+// HasFindingsAtOrAbove reports whether any finding is at least as severe as threshold.
+func (r *AuditResult) HasFindingsAtOrAbove(threshold Severity) bool {
+	return report.HasFindingsAtOrAbove(r.Findings, findingSeverity, threshold)
+}
+
+func findingSeverity(f Finding) Severity {
+	return f.Severity
+}
+
+// scorePenalty is the number of points each finding of a severity deducts from Score.
+var scorePenalty = map[Severity]int{
+	SeverityError:   20,
+	SeverityWarning: 5,
+	SeverityInfo:    1,
+}
+
+// Score rates the audit from 0 to 100: each error deducts 20 points, each
+// warning 5, and each info finding 1.
+func (r *AuditResult) Score() int {
+	score := 100
+	for _, f := range r.Findings {
+		score -= scorePenalty[f.Severity]
+	}
+	return max(score, 0)
+}
+
+// CoveragePercent returns the percentage of endpoints with a security requirement.
+// A document without endpoints is fully covered.
+func (r *AuditResult) CoveragePercent() int {
+	if r.TotalEndpoints == 0 {
+		return 100
+	}
+	return r.ProtectedEndpoints * 100 / r.TotalEndpoints
+}
+
 // AuditFile audits an OpenAPI specification file
 func (a *Auditor) AuditFile(path string) (*AuditResult, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
-	return a.AuditData(data)
+	result, err := a.AuditData(data)
+	if err != nil {
+		return nil, err
+	}
+	result.Source = path
+	return result, nil
 }
 
 // AuditData audits OpenAPI specification bytes (JSON or YAML)
@@ -128,7 +217,12 @@ func (a *Auditor) AuditURL(url string) (*AuditResult, error) {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	return a.AuditData(data)
+	result, err := a.AuditData(data)
+	if err != nil {
+		return nil, err
+	}
+	result.Source = url
+	return result, nil
 }
 
 // analyzeSecuritySchemes extracts security scheme information
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/report"
 )
 
 // FormatText formats audit result as human-readable text
@@ -41,7 +43,9 @@ func writeSummary(sb *strings.Builder, result *AuditResult) {
 
 	sb.WriteString(fmt.Sprintf("Total Endpoints: %d\n", result.TotalEndpoints))
 	sb.WriteString(fmt.Sprintf("Protected: %d (%d%%)\n", result.ProtectedEndpoints, protectedPct))
-	sb.WriteString(fmt.Sprintf("Unprotected: %d (%d%%)\n\n", result.UnprotectedEndpoints, 100-protectedPct))
+	sb.WriteString(fmt.Sprintf("Unprotected: %d (%d%%)\n", result.UnprotectedEndpoints, 100-protectedPct))
+	sb.WriteString(fmt.Sprintf("Findings: %d error, %d warning, %d info\n\n",
+		result.Count(SeverityError), result.Count(SeverityWarning), result.Count(SeverityInfo)))
 }
 
 func writeFindings(sb *strings.Builder, result *AuditResult) {
@@ -115,3 +119,30 @@ func writeCoverageByTag(sb *strings.Builder, result *AuditResult) {
 func FormatJSON(result *AuditResult) ([]byte, error) {
 	return json.MarshalIndent(result, "", "  ")
 }
+
+// PRCommentOptions controls the size of a pull request comment.
+type PRCommentOptions = report.PRCommentOptions
+
+// FormatPRComment formats the audit result as Markdown sized for a GitHub or
+// GitLab pull request comment: a one-line summary and a collapsed table of
+// the most severe findings.
+func FormatPRComment(result *AuditResult, opts PRCommentOptions) string {
+	status := "passed"
+	if result.HasFindingsAtOrAbove(SeverityError) {
+		status = "failed"
+	}
+	comment := report.PRComment{
+		Heading: fmt.Sprintf("Security audit %s: %d error, %d warning, %d info",
+			status, result.Count(SeverityError), result.Count(SeverityWarning), result.Count(SeverityInfo)),
+		Summary: fmt.Sprintf("Score: %d/100, protected endpoints: %d/%d (%d%%)",
+			result.Score(), result.ProtectedEndpoints, result.TotalEndpoints, result.CoveragePercent()),
+		Empty:   "No issues found.",
+		Noun:    "findings",
+		Report:  "Full audit report",
+		Columns: []string{"Severity", "Rule", "Location", "Message", "Recommendation"},
+	}
+	for _, f := range report.MostSevereFirst(result.Findings, findingSeverity) {
+		comment.Rows = append(comment.Rows, []string{string(f.Severity), "`" + f.RuleID + "`", "`" + f.Location + "`", f.Message, f.Recommendation})
+	}
+	return comment.Format(opts)
+}
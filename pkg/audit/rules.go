@@ -18,5 +18,14 @@ func DefaultRules() []Rule {
 		&OAuthHTTPSRule{},
 		&DeprecatedSecurityRule{},
 		&ScopeValidationRule{},
+		&RateLimitResponseRule{},
+		&WWWAuthenticateRule{},
+		&InsecureServerRule{},
+		&GlobalSecurityRule{},
+		&MutualTLSServerRule{},
+		&MutualTLSVersionRule{},
+		&URLCredentialsRule{},
+		&JWTRule{},
+		&APIKeyRule{},
 	}
 }
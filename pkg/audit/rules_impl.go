@@ -2,8 +2,10 @@ package audit
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/fathurrohman26/yaswag/internal/shared"
 	"github.com/fathurrohman26/yaswag/pkg/openapi"
 )
 
@@ -31,6 +33,7 @@ func (r *UnprotectedWriteRule) Check(doc *openapi.Document) []Finding {
 					RuleName:       r.Name(),
 					Severity:       r.Severity(),
 					Location:       fmt.Sprintf("%s %s", entry.method, path),
+					Pointer:        operationPointer(path, entry.method),
 					Message:        fmt.Sprintf("%s endpoint has no security requirement", entry.method),
 					Recommendation: "Add authentication/authorization requirement to protect write operations",
 				})
@@ -60,6 +63,7 @@ func (r *APIKeyInQueryRule) Check(doc *openapi.Document) []Finding {
 				RuleName:       r.Name(),
 				Severity:       r.Severity(),
 				Location:       fmt.Sprintf("SecurityScheme '%s'", name),
+				Pointer:        shared.JSONPointer("components", "securitySchemes", name),
 				Message:        fmt.Sprintf("API key '%s' is passed in query parameter", name),
 				Recommendation: "Use header-based API key for better security (prevents logging in URLs)",
 			})
@@ -93,13 +97,14 @@ func (r *OAuthHTTPSRule) Check(doc *openapi.Document) []Finding {
 func (r *OAuthHTTPSRule) checkOAuthFlows(schemeName string, flows *openapi.OAuthFlows) []Finding {
 	var findings []Finding
 
-	checkURL := func(urlType, url string) {
+	checkURL := func(flow, urlType, url string) {
 		if url != "" && strings.HasPrefix(url, "http://") {
 			findings = append(findings, Finding{
 				RuleID:         r.ID(),
 				RuleName:       r.Name(),
 				Severity:       r.Severity(),
 				Location:       fmt.Sprintf("SecurityScheme '%s' %s", schemeName, urlType),
+				Pointer:        shared.JSONPointer("components", "securitySchemes", schemeName, "flows", flow, urlType),
 				Message:        fmt.Sprintf("OAuth %s uses HTTP instead of HTTPS", urlType),
 				Recommendation: "Use HTTPS for all OAuth URLs to protect tokens in transit",
 			})
@@ -107,17 +112,17 @@ func (r *OAuthHTTPSRule) checkOAuthFlows(schemeName string, flows *openapi.OAuth
 	}
 
 	if flows.Implicit != nil {
-		checkURL("authorizationUrl", flows.Implicit.AuthorizationURL)
+		checkURL("implicit", "authorizationUrl", flows.Implicit.AuthorizationURL)
 	}
 	if flows.Password != nil {
-		checkURL("tokenUrl", flows.Password.TokenURL)
+		checkURL("password", "tokenUrl", flows.Password.TokenURL)
 	}
 	if flows.ClientCredentials != nil {
-		checkURL("tokenUrl", flows.ClientCredentials.TokenURL)
+		checkURL("clientCredentials", "tokenUrl", flows.ClientCredentials.TokenURL)
 	}
 	if flows.AuthorizationCode != nil {
-		checkURL("authorizationUrl", flows.AuthorizationCode.AuthorizationURL)
-		checkURL("tokenUrl", flows.AuthorizationCode.TokenURL)
+		checkURL("authorizationCode", "authorizationUrl", flows.AuthorizationCode.AuthorizationURL)
+		checkURL("authorizationCode", "tokenUrl", flows.AuthorizationCode.TokenURL)
 	}
 
 	return findings
@@ -146,6 +151,7 @@ func (r *DeprecatedSecurityRule) Check(doc *openapi.Document) []Finding {
 					RuleName:       r.Name(),
 					Severity:       r.Severity(),
 					Location:       fmt.Sprintf("%s %s", entry.method, path),
+					Pointer:        operationPointer(path, entry.method),
 					Message:        "Deprecated endpoint has no security requirement",
 					Recommendation: "Consider adding security or removing the deprecated endpoint",
 				})
@@ -226,6 +232,7 @@ func (r *ScopeValidationRule) checkOperationScopes(path string, entry operationE
 						RuleName:       r.Name(),
 						Severity:       r.Severity(),
 						Location:       fmt.Sprintf("%s %s", entry.method, path),
+						Pointer:        operationPointer(path, entry.method) + "/security",
 						Message:        fmt.Sprintf("Scope '%s' used but not defined in security scheme '%s'", scope, schemeName),
 						Recommendation: "Define the scope in the security scheme or remove from operation",
 					})
@@ -237,7 +244,290 @@ func (r *ScopeValidationRule) checkOperationScopes(path string, entry operationE
 	return findings
 }
 
+// RateLimitResponseRule checks public endpoints document 429 responses
+type RateLimitResponseRule struct{}
+
+func (r *RateLimitResponseRule) ID() string         { return "RATE_LIMIT_UNDOCUMENTED" }
+func (r *RateLimitResponseRule) Name() string       { return "Public endpoint without 429 response" }
+func (r *RateLimitResponseRule) Severity() Severity { return SeverityInfo }
+
+func (r *RateLimitResponseRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+	hasGlobalSecurity := len(doc.Security) > 0
+
+	for path, pathItem := range doc.Paths {
+		for _, entry := range getOperations(pathItem) {
+			if hasEndpointSecurity(entry.op, hasGlobalSecurity) {
+				continue
+			}
+			if _, ok := entry.op.Responses["429"]; ok {
+				continue
+			}
+			findings = append(findings, Finding{
+				RuleID:         r.ID(),
+				RuleName:       r.Name(),
+				Severity:       r.Severity(),
+				Location:       fmt.Sprintf("%s %s", entry.method, path),
+				Pointer:        operationPointer(path, entry.method) + "/responses",
+				Message:        "Public endpoint does not document a 429 Too Many Requests response",
+				Recommendation: "Rate limit public endpoints and document the 429 response with Retry-After",
+			})
+		}
+	}
+	return findings
+}
+
+// WWWAuthenticateRule checks 401 responses document the WWW-Authenticate header
+type WWWAuthenticateRule struct{}
+
+func (r *WWWAuthenticateRule) ID() string         { return "WWW_AUTHENTICATE_MISSING" }
+func (r *WWWAuthenticateRule) Name() string       { return "401 response without WWW-Authenticate" }
+func (r *WWWAuthenticateRule) Severity() Severity { return SeverityInfo }
+
+func (r *WWWAuthenticateRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+
+	for path, pathItem := range doc.Paths {
+		for _, entry := range getOperations(pathItem) {
+			resp := resolveResponse(doc, entry.op.Responses["401"])
+			if resp == nil || hasHeader(resp.Headers, "WWW-Authenticate") {
+				continue
+			}
+			findings = append(findings, Finding{
+				RuleID:         r.ID(),
+				RuleName:       r.Name(),
+				Severity:       r.Severity(),
+				Location:       fmt.Sprintf("%s %s", entry.method, path),
+				Pointer:        operationPointer(path, entry.method) + "/responses/401",
+				Message:        "401 response does not document the WWW-Authenticate header",
+				Recommendation: "Document the WWW-Authenticate header so clients know which authentication scheme to use",
+			})
+		}
+	}
+	return findings
+}
+
+// InsecureServerRule warns when server URLs use plain HTTP
+type InsecureServerRule struct{}
+
+func (r *InsecureServerRule) ID() string         { return "SERVER_HTTP" }
+func (r *InsecureServerRule) Name() string       { return "Server URL not using HTTPS" }
+func (r *InsecureServerRule) Severity() Severity { return SeverityWarning }
+
+func (r *InsecureServerRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+
+	check := func(servers []openapi.Server, location string, pointer ...string) {
+		for i, server := range servers {
+			if !isInsecureURL(server.URL) {
+				continue
+			}
+			findings = append(findings, Finding{
+				RuleID:         r.ID(),
+				RuleName:       r.Name(),
+				Severity:       r.Severity(),
+				Location:       location,
+				Pointer:        shared.JSONPointer(append(pointer, "servers", strconv.Itoa(i))...),
+				Message:        fmt.Sprintf("Server URL '%s' uses HTTP instead of HTTPS", server.URL),
+				Recommendation: "Serve the API over HTTPS to protect credentials and data in transit",
+			})
+		}
+	}
+
+	check(doc.Servers, "Servers")
+	for path, pathItem := range doc.Paths {
+		check(pathItem.Servers, path, "paths", path)
+		for _, entry := range getOperations(pathItem) {
+			check(entry.op.Servers, fmt.Sprintf("%s %s", entry.method, path),
+				"paths", path, strings.ToLower(entry.method))
+		}
+	}
+	return findings
+}
+
+// isInsecureURL reports whether url uses plain HTTP to a non-loopback host.
+func isInsecureURL(url string) bool {
+	rest, ok := strings.CutPrefix(strings.ToLower(url), "http://")
+	if !ok {
+		return false
+	}
+	host, _, _ := strings.Cut(rest, "/")
+	host = strings.TrimPrefix(host, "[")
+	for _, loopback := range []string{"localhost", "127.0.0.1", "::1"} {
+		if host == loopback || strings.HasPrefix(host, loopback+":") || strings.HasPrefix(host, loopback+"]") {
+			return false
+		}
+	}
+	return true
+}
+
+// GlobalSecurityRule warns when there is no global security and some operations are unprotected
+type GlobalSecurityRule struct{}
+
+func (r *GlobalSecurityRule) ID() string         { return "GLOBAL_SECURITY_MISSING" }
+func (r *GlobalSecurityRule) Name() string       { return "No global security requirement" }
+func (r *GlobalSecurityRule) Severity() Severity { return SeverityWarning }
+
+func (r *GlobalSecurityRule) Check(doc *openapi.Document) []Finding {
+	if len(doc.Security) > 0 {
+		return nil
+	}
+
+	unprotected := 0
+	for _, pathItem := range doc.Paths {
+		for _, entry := range getOperations(pathItem) {
+			if len(entry.op.Security) == 0 {
+				unprotected++
+			}
+		}
+	}
+	if unprotected == 0 {
+		return nil
+	}
+
+	return []Finding{{
+		RuleID:         r.ID(),
+		RuleName:       r.Name(),
+		Severity:       r.Severity(),
+		Location:       "Document",
+		Pointer:        "/security",
+		Message:        fmt.Sprintf("No global security requirement is defined and %d operation(s) are unprotected", unprotected),
+		Recommendation: "Define a global security requirement and explicitly opt public operations out with an empty security list",
+	}}
+}
+
+// resolveResponse follows a response $ref into components.responses.
+func resolveResponse(doc *openapi.Document, resp *openapi.Response) *openapi.Response {
+	if resp == nil || resp.Ref == "" {
+		return resp
+	}
+	name, ok := strings.CutPrefix(resp.Ref, "#/components/responses/")
+	if !ok || doc.Components == nil {
+		return resp
+	}
+	if resolved, ok := doc.Components.Responses[name]; ok && resolved != nil {
+		return resolved
+	}
+	return resp
+}
+
+// hasHeader checks for a header name case-insensitively
+func hasHeader(headers map[string]*openapi.Header, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
 // hasEndpointSecurity checks if an endpoint has security (operation or global)
 func hasEndpointSecurity(op *openapi.Operation, hasGlobalSecurity bool) bool {
 	return len(op.Security) > 0 || hasGlobalSecurity
 }
+
+// operationPointer returns the JSON pointer to an operation in the spec.
+func operationPointer(path, method string) string {
+	return shared.JSONPointer("paths", path, strings.ToLower(method))
+}
+
+// MutualTLSServerRule flags plain HTTP servers for operations that accept a client certificate
+type MutualTLSServerRule struct{}
+
+func (r *MutualTLSServerRule) ID() string         { return "MTLS_HTTP_SERVER" }
+func (r *MutualTLSServerRule) Name() string       { return "Mutual TLS over plain HTTP" }
+func (r *MutualTLSServerRule) Severity() Severity { return SeverityError }
+
+func (r *MutualTLSServerRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+	reported := make(map[string]bool)
+
+	check := func(servers []openapi.Server, location string, pointer ...string) {
+		for i, server := range servers {
+			p := shared.JSONPointer(append(pointer, "servers", strconv.Itoa(i))...)
+			if !strings.HasPrefix(strings.ToLower(server.URL), "http://") || reported[p] {
+				continue
+			}
+			reported[p] = true
+			findings = append(findings, Finding{
+				RuleID:         r.ID(),
+				RuleName:       r.Name(),
+				Severity:       r.Severity(),
+				Location:       location,
+				Pointer:        p,
+				Message:        fmt.Sprintf("Server URL '%s' uses HTTP but operations served by it authenticate with mutualTLS", server.URL),
+				Recommendation: "Serve mutualTLS-protected operations over HTTPS only; client certificates cannot be presented over plain HTTP",
+			})
+		}
+	}
+
+	for path, pathItem := range doc.Paths {
+		for _, entry := range getOperations(pathItem) {
+			if !usesMutualTLS(doc, effectiveSecurity(doc, entry.op)) {
+				continue
+			}
+			switch method := strings.ToLower(entry.method); {
+			case len(entry.op.Servers) > 0:
+				check(entry.op.Servers, fmt.Sprintf("%s %s", entry.method, path), "paths", path, method)
+			case len(pathItem.Servers) > 0:
+				check(pathItem.Servers, path, "paths", path)
+			default:
+				check(doc.Servers, "Servers")
+			}
+		}
+	}
+	return findings
+}
+
+// MutualTLSVersionRule flags mutualTLS schemes in documents older than OpenAPI 3.1
+type MutualTLSVersionRule struct{}
+
+func (r *MutualTLSVersionRule) ID() string         { return "MTLS_UNSUPPORTED_VERSION" }
+func (r *MutualTLSVersionRule) Name() string       { return "Mutual TLS requires OpenAPI 3.1" }
+func (r *MutualTLSVersionRule) Severity() Severity { return SeverityWarning }
+
+func (r *MutualTLSVersionRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+	if doc.Components == nil || !strings.HasPrefix(doc.OpenAPI, "3.0") {
+		return findings
+	}
+
+	for name, scheme := range doc.Components.SecuritySchemes {
+		if scheme == nil || scheme.Type != "mutualTLS" {
+			continue
+		}
+		findings = append(findings, Finding{
+			RuleID:         r.ID(),
+			RuleName:       r.Name(),
+			Severity:       r.Severity(),
+			Location:       fmt.Sprintf("SecurityScheme '%s'", name),
+			Pointer:        shared.JSONPointer("components", "securitySchemes", name),
+			Message:        fmt.Sprintf("Security scheme '%s' uses mutualTLS, which OpenAPI %s does not define", name, doc.OpenAPI),
+			Recommendation: "Declare openapi: 3.1.0, or describe the client certificate requirement in the scheme description",
+		})
+	}
+	return findings
+}
+
+// effectiveSecurity returns the security requirements that apply to op.
+func effectiveSecurity(doc *openapi.Document, op *openapi.Operation) []openapi.SecurityRequirement {
+	if op.Security != nil {
+		return op.Security
+	}
+	return doc.Security
+}
+
+// usesMutualTLS reports whether any alternative in requirements includes a mutualTLS scheme.
+func usesMutualTLS(doc *openapi.Document, requirements []openapi.SecurityRequirement) bool {
+	if doc.Components == nil {
+		return false
+	}
+	for _, requirement := range requirements {
+		for name := range requirement {
+			if scheme := doc.Components.SecuritySchemes[name]; scheme != nil && scheme.Type == "mutualTLS" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package audit
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// SARIF 2.1.0 constants.
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolURI = "https://github.com/fathurrohman26/yaswag"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	Help                 *sarifMessage      `json:"help,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Kind               string `json:"kind"`
+}
+
+// FormatSARIF formats audit result as a SARIF 2.1.0 log for code scanning tools.
+// Findings are located by their JSON pointer into the spec and, when the result
+// has a Source, by the spec file as the artifact location.
+func FormatSARIF(result *AuditResult) ([]byte, error) {
+	rules, ruleIndex := buildSARIFRules(result.Findings)
+
+	results := make([]sarifResult, 0, len(result.Findings))
+	for _, f := range result.Findings {
+		results = append(results, sarifResult{
+			RuleID:    f.RuleID,
+			RuleIndex: ruleIndex[f.RuleID],
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{buildSARIFLocation(result.Source, f)},
+		})
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "yaswag",
+				InformationURI: sarifToolURI,
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+	return json.MarshalIndent(log, "", "  ")
+}
+
+// buildSARIFRules describes all built-in rules plus any other rules that produced findings.
+func buildSARIFRules(findings []Finding) ([]sarifRule, map[string]int) {
+	byID := make(map[string]sarifRule)
+	for _, r := range DefaultRules() {
+		byID[r.ID()] = newSARIFRule(r.ID(), r.Name(), r.Severity())
+	}
+	for _, f := range findings {
+		rule, ok := byID[f.RuleID]
+		if !ok {
+			rule = newSARIFRule(f.RuleID, f.RuleName, f.Severity)
+		}
+		if rule.Help == nil && f.Recommendation != "" {
+			rule.Help = &sarifMessage{Text: f.Recommendation}
+		}
+		byID[f.RuleID] = rule
+	}
+
+	ids := make([]string, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	rules := make([]sarifRule, 0, len(ids))
+	index := make(map[string]int, len(ids))
+	for i, id := range ids {
+		rules = append(rules, byID[id])
+		index[id] = i
+	}
+	return rules, index
+}
+
+func newSARIFRule(id, name string, severity Severity) sarifRule {
+	return sarifRule{
+		ID:                   id,
+		Name:                 name,
+		ShortDescription:     sarifMessage{Text: name},
+		DefaultConfiguration: sarifConfiguration{Level: sarifLevel(severity)},
+	}
+}
+
+func buildSARIFLocation(source string, f Finding) sarifLocation {
+	loc := sarifLocation{
+		LogicalLocations: []sarifLogicalLocation{{
+			Name:               f.Location,
+			FullyQualifiedName: f.Pointer,
+			Kind:               "object",
+		}},
+	}
+	if source != "" {
+		loc.PhysicalLocation = &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: source},
+		}
+	}
+	return loc
+}
+
+// sarifLevel maps audit severities to SARIF result levels.
+func sarifLevel(s Severity) string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,288 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/fathurrohman26/yaswag/internal/shared"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// secretField is a string in the document that may carry a leaked secret.
+type secretField struct {
+	kind   string // "description", "example", or "URL"
+	value  string
+	tokens []string
+}
+
+// location describes where the field lives in terms of the document structure.
+func (f secretField) location() string {
+	switch {
+	case len(f.tokens) >= 3 && f.tokens[0] == "paths" && isHTTPMethod(f.tokens[2]):
+		return fmt.Sprintf("%s %s", strings.ToUpper(f.tokens[2]), f.tokens[1])
+	case len(f.tokens) >= 2 && f.tokens[0] == "paths":
+		return f.tokens[1]
+	case len(f.tokens) >= 3 && f.tokens[0] == "components":
+		return fmt.Sprintf("components.%s.%s", f.tokens[1], f.tokens[2])
+	case len(f.tokens) >= 1:
+		return strings.ToUpper(f.tokens[0][:1]) + f.tokens[0][1:]
+	default:
+		return "Document"
+	}
+}
+
+var (
+	textKeys    = []string{"description", "summary"}
+	exampleKeys = []string{"example", "examples", "default"}
+	urlKeys     = []string{"url", "tokenUrl", "authorizationUrl", "refreshUrl", "openIdConnectUrl", "externalValue"}
+)
+
+// secretFields returns the descriptions, examples, and URLs of doc in document order.
+func secretFields(doc *openapi.Document) []secretField {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil
+	}
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil
+	}
+
+	var fields []secretField
+	var walk func(node any, kind string, tokens []string)
+	walk = func(node any, kind string, tokens []string) {
+		switch v := node.(type) {
+		case string:
+			if kind != "" {
+				fields = append(fields, secretField{kind: kind, value: v, tokens: slices.Clone(tokens)})
+			}
+		case []any:
+			for i, item := range v {
+				walk(item, kind, append(tokens, strconv.Itoa(i)))
+			}
+		case map[string]any:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			slices.Sort(keys)
+			for _, key := range keys {
+				walk(v[key], fieldKind(kind, key), append(tokens, key))
+			}
+		}
+	}
+	walk(tree, "", nil)
+	return fields
+}
+
+// fieldKind classifies the value under key; everything inside an example is example data.
+func fieldKind(parent, key string) string {
+	switch {
+	case parent == "example":
+		return parent
+	case slices.Contains(exampleKeys, key):
+		return "example"
+	case slices.Contains(textKeys, key):
+		return "description"
+	case slices.Contains(urlKeys, key):
+		return "URL"
+	default:
+		return ""
+	}
+}
+
+// secretDetector returns a description of the secret found in value and a redacted excerpt.
+type secretDetector func(value string) (what, excerpt string, found bool)
+
+// checkSecrets reports a finding for every field in which detect finds a secret.
+func checkSecrets(doc *openapi.Document, rule Rule, detect secretDetector, recommendation string) []Finding {
+	var findings []Finding
+	for _, field := range secretFields(doc) {
+		what, excerpt, found := detect(field.value)
+		if !found {
+			continue
+		}
+		findings = append(findings, Finding{
+			RuleID:         rule.ID(),
+			RuleName:       rule.Name(),
+			Severity:       rule.Severity(),
+			Location:       field.location(),
+			Pointer:        shared.JSONPointer(field.tokens...),
+			Message:        fmt.Sprintf("Possible %s in %s: %s", what, field.kind, excerpt),
+			Recommendation: recommendation,
+		})
+	}
+	return findings
+}
+
+// URLCredentialsRule flags URLs that embed a username and password
+type URLCredentialsRule struct{}
+
+func (r *URLCredentialsRule) ID() string         { return "SECRET_URL_CREDENTIALS" }
+func (r *URLCredentialsRule) Name() string       { return "Credentials embedded in URL" }
+func (r *URLCredentialsRule) Severity() Severity { return SeverityError }
+
+func (r *URLCredentialsRule) Check(doc *openapi.Document) []Finding {
+	return checkSecrets(doc, r, detectURLCredentials,
+		"Remove the credentials from the URL and document the security scheme instead; rotate the password if it was real")
+}
+
+var urlCredentialsPattern = regexp.MustCompile(`[A-Za-z][A-Za-z0-9+.-]*://([^\s/:@]+):([^\s/@]+)@([^\s/?#]*)`)
+
+func detectURLCredentials(value string) (string, string, bool) {
+	for _, m := range urlCredentialsPattern.FindAllStringSubmatch(value, -1) {
+		user, password, host := m[1], m[2], m[3]
+		if isPlaceholder(password) {
+			continue
+		}
+		return "basic-auth credentials", fmt.Sprintf("%s:%s@%s", user, redact(password), host), true
+	}
+	return "", "", false
+}
+
+// JWTRule flags JSON Web Tokens pasted into the spec
+type JWTRule struct{}
+
+func (r *JWTRule) ID() string         { return "SECRET_JWT" }
+func (r *JWTRule) Name() string       { return "JSON Web Token in spec" }
+func (r *JWTRule) Severity() Severity { return SeverityError }
+
+func (r *JWTRule) Check(doc *openapi.Document) []Finding {
+	return checkSecrets(doc, r, detectJWT,
+		"Replace the token with a placeholder such as <token>; revoke it if it was issued by a real identity provider")
+}
+
+var jwtPattern = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{16,}`)
+
+func detectJWT(value string) (string, string, bool) {
+	token := jwtPattern.FindString(value)
+	if token == "" {
+		return "", "", false
+	}
+	return "JSON Web Token", redact(token), true
+}
+
+// APIKeyRule flags API keys and other high-entropy secrets in the spec
+type APIKeyRule struct{}
+
+func (r *APIKeyRule) ID() string         { return "SECRET_API_KEY" }
+func (r *APIKeyRule) Name() string       { return "API key or secret in spec" }
+func (r *APIKeyRule) Severity() Severity { return SeverityError }
+
+func (r *APIKeyRule) Check(doc *openapi.Document) []Finding {
+	return checkSecrets(doc, r, detectAPIKey,
+		"Replace the value with an obviously fake placeholder and rotate the key")
+}
+
+// knownKeyPatterns match the formats of widely used providers' credentials.
+var knownKeyPatterns = []struct {
+	what    string
+	pattern *regexp.Regexp
+}{
+	{"AWS access key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"GitHub token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{"Stripe secret key", regexp.MustCompile(`\b[rs]k_live_[A-Za-z0-9]{16,}\b`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+}
+
+var (
+	keyAssignmentPattern = regexp.MustCompile(`(?i)\b(?:api[_-]?key|secret|token|password|passwd)["']?\s*[:=]\s*["']?([A-Za-z0-9_\-+/=.]{12,})`)
+	candidatePattern     = regexp.MustCompile(`[A-Za-z0-9_\-+/=]{32,}`)
+	uuidPattern          = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// Entropy thresholds in bits per character. Random base62 strings of 32+
+// characters score above 4.3 while hex digests never exceed 4.0.
+const (
+	assignedSecretEntropy = 3.0
+	generalSecretEntropy  = 4.3
+)
+
+func detectAPIKey(value string) (string, string, bool) {
+	// tokens are reported by JWTRule
+	value = jwtPattern.ReplaceAllString(value, "")
+
+	for _, known := range knownKeyPatterns {
+		if match := known.pattern.FindString(value); match != "" {
+			return known.what, redact(match), true
+		}
+	}
+
+	for _, m := range keyAssignmentPattern.FindAllStringSubmatch(value, -1) {
+		if !isPlaceholder(m[1]) && shannonEntropy(m[1]) >= assignedSecretEntropy {
+			return "hard-coded secret", redact(m[1]), true
+		}
+	}
+
+	for _, candidate := range candidatePattern.FindAllString(value, -1) {
+		if looksRandom(candidate) {
+			return "high-entropy secret", redact(candidate), true
+		}
+	}
+	return "", "", false
+}
+
+// looksRandom reports whether s is a mix of letters and digits with enough
+// entropy to be a generated secret rather than a word, UUID, or digest.
+func looksRandom(s string) bool {
+	if uuidPattern.MatchString(s) || strings.Contains(s, "/") && !strings.ContainsAny(s, "+=") {
+		return false
+	}
+	upper := strings.ContainsFunc(s, unicode.IsUpper)
+	lower := strings.ContainsFunc(s, unicode.IsLower)
+	digit := strings.ContainsFunc(s, unicode.IsDigit)
+	return upper && lower && digit && shannonEntropy(s) >= generalSecretEntropy
+}
+
+// shannonEntropy returns the entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	for _, c := range s {
+		counts[c]++
+	}
+	n := float64(len([]rune(s)))
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+func isHTTPMethod(s string) bool {
+	return slices.Contains([]string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}, s)
+}
+
+// isPlaceholder reports whether a value is an obvious stand-in rather than a real secret.
+func isPlaceholder(s string) bool {
+	lower := strings.ToLower(s)
+	if strings.ContainsAny(lower, "{}<>*$") {
+		return true
+	}
+	for _, word := range []string{"password", "passwd", "secret", "example", "your", "changeme", "placeholder", "xxxx", "redacted"} {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// redact keeps the first four characters of a secret so it can be located without being disclosed.
+func redact(secret string) string {
+	runes := []rune(secret)
+	if len(runes) <= 8 {
+		return "****"
+	}
+	return fmt.Sprintf("%s**** (%d chars)", string(runes[:4]), len(runes))
+}
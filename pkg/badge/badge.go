@@ -0,0 +1,154 @@
+// Package badge renders status badges for OpenAPI specifications, as SVG
+// images and as shields.io endpoint JSON, so repositories can surface
+// contract health in their READMEs from CI artifacts.
+package badge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// Named colors, matching the shields.io palette.
+const (
+	ColorBrightGreen = "brightgreen"
+	ColorGreen       = "green"
+	ColorYellow      = "yellow"
+	ColorOrange      = "orange"
+	ColorRed         = "red"
+	ColorLightGrey   = "lightgrey"
+	ColorBlue        = "blue"
+)
+
+var colorHex = map[string]string{
+	ColorBrightGreen: "#4c1",
+	ColorGreen:       "#97ca00",
+	ColorYellow:      "#dfb317",
+	ColorOrange:      "#fe7d37",
+	ColorRed:         "#e05d44",
+	ColorLightGrey:   "#9f9f9f",
+	ColorBlue:        "#007ec6",
+}
+
+// Badge is a two-part status badge: a label on the left and a colored message on the right.
+type Badge struct {
+	Label   string
+	Message string
+	Color   string // named color (e.g. "brightgreen") or hex value (e.g. "#4c1")
+}
+
+// Validity returns an "openapi" badge reporting whether the spec is valid.
+func Validity(valid bool) Badge {
+	if valid {
+		return Badge{Label: "openapi", Message: "valid", Color: ColorBrightGreen}
+	}
+	return Badge{Label: "openapi", Message: "invalid", Color: ColorRed}
+}
+
+// Percent returns a badge showing percent (0-100), colored from red to bright green.
+func Percent(label string, percent int) Badge {
+	return Badge{Label: label, Message: fmt.Sprintf("%d%%", percent), Color: PercentColor(percent)}
+}
+
+// Score returns a badge showing a score out of 100, colored like Percent.
+func Score(label string, score int) Badge {
+	return Badge{Label: label, Message: fmt.Sprintf("%d/100", score), Color: PercentColor(score)}
+}
+
+// PercentColor maps a percentage to a color: 90+ bright green, 75+ green,
+// 60+ yellow, 40+ orange, and red below that.
+func PercentColor(percent int) string {
+	switch {
+	case percent >= 90:
+		return ColorBrightGreen
+	case percent >= 75:
+		return ColorGreen
+	case percent >= 60:
+		return ColorYellow
+	case percent >= 40:
+		return ColorOrange
+	default:
+		return ColorRed
+	}
+}
+
+// Endpoint renders the badge as shields.io endpoint JSON
+// (https://shields.io/badges/endpoint-badge).
+func Endpoint(b Badge) ([]byte, error) {
+	return json.MarshalIndent(struct {
+		SchemaVersion int    `json:"schemaVersion"`
+		Label         string `json:"label"`
+		Message       string `json:"message"`
+		Color         string `json:"color"`
+	}{1, b.Label, b.Message, b.Color}, "", "  ")
+}
+
+const svgTemplate = `<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="20" role="img" aria-label="{{.Label}}: {{.Message}}">
+  <title>{{.Label}}: {{.Message}}</title>
+  <linearGradient id="s" x2="0" y2="100%">
+    <stop offset="0" stop-color="#bbb" stop-opacity=".1"/>
+    <stop offset="1" stop-opacity=".1"/>
+  </linearGradient>
+  <clipPath id="r"><rect width="{{.Width}}" height="20" rx="3" fill="#fff"/></clipPath>
+  <g clip-path="url(#r)">
+    <rect width="{{.LabelWidth}}" height="20" fill="#555"/>
+    <rect x="{{.LabelWidth}}" width="{{.MessageWidth}}" height="20" fill="{{.Color}}"/>
+    <rect width="{{.Width}}" height="20" fill="url(#s)"/>
+  </g>
+  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+    <text x="{{.LabelX}}" y="15" fill="#010101" fill-opacity=".3">{{.Label}}</text>
+    <text x="{{.LabelX}}" y="14">{{.Label}}</text>
+    <text x="{{.MessageX}}" y="15" fill="#010101" fill-opacity=".3">{{.Message}}</text>
+    <text x="{{.MessageX}}" y="14">{{.Message}}</text>
+  </g>
+</svg>
+`
+
+var svg = template.Must(template.New("badge").Parse(svgTemplate))
+
+// SVG renders the badge as a flat-style SVG image.
+func SVG(b Badge) ([]byte, error) {
+	labelWidth := textWidth(b.Label) + 10
+	messageWidth := textWidth(b.Message) + 10
+
+	color := b.Color
+	if hex, ok := colorHex[color]; ok {
+		color = hex
+	}
+
+	var buf bytes.Buffer
+	err := svg.Execute(&buf, map[string]any{
+		"Label":        b.Label,
+		"Message":      b.Message,
+		"Color":        color,
+		"Width":        labelWidth + messageWidth,
+		"LabelWidth":   labelWidth,
+		"MessageWidth": messageWidth,
+		"LabelX":       float64(labelWidth) / 2,
+		"MessageX":     float64(labelWidth) + float64(messageWidth)/2,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render badge: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// textWidth approximates the rendered width of text in 11px Verdana.
+func textWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		switch {
+		case strings.ContainsRune("iljtfr.,:;!|' ", r):
+			width += 4
+		case strings.ContainsRune("mwMW%", r):
+			width += 11
+		case r >= 'A' && r <= 'Z':
+			width += 8
+		default:
+			width += 7
+		}
+	}
+	return width
+}
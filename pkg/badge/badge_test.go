@@ -0,0 +1,56 @@
+package badge
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSVG(t *testing.T) {
+	data, err := SVG(Score("audit", 82))
+	if err != nil {
+		t.Fatalf("SVG() error = %v", err)
+	}
+	svg := string(data)
+
+	for _, want := range []string{`aria-label="audit: 82/100"`, `fill="#97ca00"`, ">82/100</text>"} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG() missing %q", want)
+		}
+	}
+
+	data, err = SVG(Badge{Label: "<api>", Message: "ok", Color: "#123456"})
+	if err != nil {
+		t.Fatalf("SVG() error = %v", err)
+	}
+	if strings.Contains(string(data), "<api>") || !strings.Contains(string(data), `fill="#123456"`) {
+		t.Error("SVG() should escape text and keep hex colors")
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	data, err := Endpoint(Validity(false))
+	if err != nil {
+		t.Fatalf("Endpoint() error = %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	want := map[string]any{"schemaVersion": 1.0, "label": "openapi", "message": "invalid", "color": "red"}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %v, want %v", key, got[key], value)
+		}
+	}
+}
+
+func TestPercentColor(t *testing.T) {
+	tests := map[int]string{100: ColorBrightGreen, 75: ColorGreen, 60: ColorYellow, 40: ColorOrange, 39: ColorRed}
+	for percent, want := range tests {
+		if got := PercentColor(percent); got != want {
+			t.Errorf("PercentColor(%d) = %s, want %s", percent, got, want)
+		}
+	}
+}
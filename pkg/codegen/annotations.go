@@ -0,0 +1,1240 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/shared"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// AnnotationsOptions configures Annotations.
+type AnnotationsOptions struct {
+	// Package is the name of the Go package of the files, by default api.
+	Package string
+}
+
+// GoFile is a Go source file written by Annotations.
+type GoFile struct {
+	Name    string
+	Content []byte
+}
+
+// Annotations reverse-generates Go source files annotated for YaSwag from
+// doc, to move a service documented by a hand-written spec to annotations:
+//
+//   - api.go declares the API-level annotations in its package comment
+//   - models.go declares a !model type for each component schema, with a
+//     !field line for each property that has more to say than its Go type
+//   - a file per tag, named after it, declares a handler stub with the
+//     annotations of each operation whose first tag it is
+//
+// Inline object schemas of properties, bodies, and responses become models
+// named after where they are used. What the annotations cannot express,
+// such as enums, validation keywords, oneOf, and webhooks, is left out, with
+// a TODO comment where it applied; the notes returned describe each such
+// loss.
+func Annotations(doc *openapi.Document, opts AnnotationsOptions) ([]GoFile, []string, error) {
+	pkg := opts.Package
+	if pkg == "" {
+		pkg = "api"
+	}
+	g := &annotationGen{doc: doc, taken: map[string]bool{}, typeNames: map[string]string{}, imports: map[string]bool{}}
+	g.declareTypeNames()
+
+	files := []GoFile{{Name: "api.go", Content: g.apiFile(pkg)}}
+	handlers, fileOrder := g.handlers()
+
+	// the component models come first, followed by the inline models of
+	// operations and of the components themselves
+	var models []string
+	for _, name := range schemaNames(doc) {
+		models = append(models, g.model(g.typeNames[name], doc.Components.Schemas[name], "components.schemas."+name))
+	}
+	if models = append(models, g.models...); len(models) > 0 {
+		files = append(files, GoFile{Name: "models.go", Content: goSource(pkg, g.imports, models)})
+	}
+	for _, file := range fileOrder {
+		files = append(files, GoFile{Name: file, Content: goSource(pkg, map[string]bool{"net/http": true}, handlers[file])})
+	}
+
+	for i, f := range files {
+		formatted, err := format.Source(f.Content)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		files[i].Content = formatted
+	}
+	return files, g.notes, nil
+}
+
+// declareTypeNames names the Go types of the component schemas.
+func (g *annotationGen) declareTypeNames() {
+	for _, name := range schemaNames(g.doc) {
+		typeName := uniqueName(goName(name), g.taken)
+		g.typeNames[name] = typeName
+		if typeName != name {
+			g.note("components.schemas.%s: declared as %s, a valid Go identifier", name, typeName)
+		}
+	}
+}
+
+// handlers renders the handler stubs of the operations, by file, and
+// returns the files in the order of their first operation.
+func (g *annotationGen) handlers() (map[string][]string, []string) {
+	handlers := map[string][]string{}
+	var fileOrder []string
+	for _, o := range operations(g.doc) {
+		handler := g.handler(o)
+		if handler == "" {
+			continue
+		}
+		file := handlerFile(o)
+		if _, ok := handlers[file]; !ok {
+			fileOrder = append(fileOrder, file)
+		}
+		handlers[file] = append(handlers[file], handler)
+	}
+	return handlers, fileOrder
+}
+
+// handlerFile returns the file of the handler of o, named after its first
+// tag.
+func handlerFile(o operation) string {
+	if len(o.op.Tags) == 0 {
+		return "handlers.go"
+	}
+	file := snakeCase(o.op.Tags[0]) + ".go"
+	if file == "api.go" || file == "models.go" {
+		file = strings.TrimSuffix(file, ".go") + "_handlers.go"
+	}
+	return file
+}
+
+type annotationGen struct {
+	doc       *openapi.Document
+	taken     map[string]bool   // Go identifiers declared in the package
+	typeNames map[string]string // Go type names of the component schemas
+	models    []string          // rendered model declarations
+	imports   map[string]bool   // imports of models.go
+	notes     []string
+}
+
+func (g *annotationGen) note(format string, args ...any) {
+	g.notes = append(g.notes, fmt.Sprintf(format, args...))
+}
+
+// goSource renders a Go file of package pkg with imports and decls.
+func goSource(pkg string, imports map[string]bool, decls []string) []byte {
+	var sb strings.Builder
+	sb.WriteString("// Generated by yaswag import. The annotations are now the source of the\n")
+	sb.WriteString("// specification: edit them rather than the spec.\n\n")
+	fmt.Fprintf(&sb, "package %s\n\n", pkg)
+	if len(imports) > 0 {
+		sb.WriteString("import (\n")
+		for _, path := range sortedKeys(imports) {
+			fmt.Fprintf(&sb, "\t%q\n", path)
+		}
+		sb.WriteString(")\n\n")
+	}
+	sb.WriteString(strings.Join(decls, "\n"))
+	return []byte(sb.String())
+}
+
+var (
+	versionPattern = regexp.MustCompile(`^v?[\d.]+$`)
+	versionPrefix  = regexp.MustCompile(`^v?[\d.]*`)
+)
+
+// apiFile renders api.go, holding the API-level annotations.
+func (g *annotationGen) apiFile(pkg string) []byte {
+	doc, info := g.doc, g.doc.Info
+	lines := []string{"!api " + doc.OpenAPI, g.infoLine()}
+	if c := info.Contact; c != nil {
+		lines = append(lines, contactLine(c))
+	}
+	if l := info.License; l != nil {
+		name := strings.ReplaceAll(l.Name, " ", "-")
+		if name != l.Name {
+			g.note("info.license.name: %q has spaces, imported as %s", l.Name, name)
+		}
+		lines = append(lines, strings.TrimSpace("!license "+name+" "+l.URL))
+	}
+	if info.TermsOfService != "" {
+		lines = append(lines, "!tos "+info.TermsOfService)
+	}
+	if d := doc.ExternalDocs; d != nil {
+		lines = append(lines, withDescription("!externalDocs "+d.URL, d.Description))
+	}
+	lines = append(lines, g.securityLines()...)
+	lines = append(lines, serverLines(doc.Servers)...)
+	lines = append(lines, g.tagLines()...)
+	lines = append(lines, extensionLines(doc.Extensions)...)
+	if multiline(info.Description) {
+		lines = append(lines, descriptionLines(info.Description)...)
+	}
+
+	if len(doc.Webhooks) > 0 {
+		g.note("webhooks: %d webhook(s) not imported", len(doc.Webhooks))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "// Package %s was imported from %s %s by yaswag import.\n//\n", pkg, strings.TrimSpace(info.Title), info.Version)
+	writeLines(&sb, lines)
+	fmt.Fprintf(&sb, "package %s\n", pkg)
+	return []byte(sb.String())
+}
+
+// infoLine renders the !info line, with the description if it fits on one
+// line.
+func (g *annotationGen) infoLine() string {
+	info := g.doc.Info
+	title := quoted(info.Title)
+	if title == `""` {
+		title = `"API"`
+		g.note("info.title: missing, imported as API")
+	}
+	version := info.Version
+	if !versionPattern.MatchString(version) {
+		version = strings.TrimPrefix(versionPrefix.FindString(version), "v")
+		if version == "" {
+			version = "0.0.0"
+		}
+		g.note("info.version: %q is not numeric, imported as %s", info.Version, version)
+	}
+	line := fmt.Sprintf("!info %s v%s", title, strings.TrimPrefix(version, "v"))
+	if multiline(info.Description) {
+		return line
+	}
+	return withDescription(line, info.Description)
+}
+
+// contactLine renders the !contact line of c.
+func contactLine(c *openapi.Contact) string {
+	line := "!contact " + quoted(c.Name)
+	if c.Email != "" {
+		line += " <" + c.Email + ">"
+	}
+	if c.URL != "" {
+		line += " (" + c.URL + ")"
+	}
+	return line
+}
+
+// serverLines renders the !server and !server-var lines of servers.
+func serverLines(servers []openapi.Server) []string {
+	var lines []string
+	for _, s := range servers {
+		lines = append(lines, withDescription("!server "+s.URL, s.Description))
+		for _, name := range sortedKeys(s.Variables) {
+			v := s.Variables[name]
+			line := "!server-var " + name
+			if v.Default != "" {
+				line += " default=" + v.Default
+			}
+			if len(v.Enum) > 0 {
+				line += " enum=" + strings.Join(v.Enum, ",")
+			}
+			lines = append(lines, withDescription(line, v.Description))
+		}
+	}
+	return lines
+}
+
+// tagLines renders the !tag lines of the tags of the document.
+func (g *annotationGen) tagLines() []string {
+	var lines []string
+	for _, t := range g.doc.Tags {
+		name := g.tagName(t.Name)
+		if name != t.Name {
+			g.note("tag %q: imported as %s, as tags must be words", t.Name, name)
+		}
+		lines = append(lines, withDescription("!tag "+name, t.Description))
+	}
+	return lines
+}
+
+// securityLines renders the !security and !scope lines of the security
+// schemes.
+func (g *annotationGen) securityLines() []string {
+	if g.doc.Components == nil {
+		return nil
+	}
+	var lines []string
+	for _, name := range sortedKeys(g.doc.Components.SecuritySchemes) {
+		s := g.doc.Components.SecuritySchemes[name]
+		if s == nil || s.Ref != "" {
+			continue
+		}
+		id := g.securityName(name)
+		if id != name {
+			g.note("components.securitySchemes.%s: imported as %s, as security scheme names must be words", name, id)
+		}
+		lines = append(lines, g.securitySchemeLines(name, id, s)...)
+	}
+	return lines
+}
+
+// securitySchemeLines renders the !security line of the security scheme
+// name, declared as id, and the !scope lines of its OAuth2 flow.
+func (g *annotationGen) securitySchemeLines(name, id string, s *openapi.SecurityScheme) []string {
+	var line string
+	switch s.Type {
+	case "apiKey":
+		line = withDescription(fmt.Sprintf("!security %s:apiKey:%s", id, s.In), s.Description) + " " + s.Name
+	case "http":
+		line = withDescription(fmt.Sprintf("!security %s:http:%s", id, strings.ToLower(s.Scheme)), s.Description)
+	case "openIdConnect":
+		line = withDescription("!security "+id+":openIdConnect", s.Description) + " " + s.OpenIDConnectURL
+	case "mutualTLS":
+		line = withDescription("!security "+id+":mutualTLS", s.Description)
+	case "oauth2":
+		return g.oauth2Lines(name, id, s)
+	default:
+		g.note("components.securitySchemes.%s: type %q is not supported", name, s.Type)
+		return nil
+	}
+	return []string{strings.TrimSpace(line)}
+}
+
+// oauth2Flow is an OAuth2 flow of a security scheme, with the URL the
+// !security line takes for it.
+type oauth2Flow struct {
+	name string
+	flow *openapi.OAuthFlow
+	url  string
+}
+
+// oauth2Flows returns the flows of s in the order the first of them is
+// imported.
+func oauth2Flows(s *openapi.SecurityScheme) []oauth2Flow {
+	var flows []oauth2Flow
+	for _, f := range []struct {
+		name     string
+		flow     *openapi.OAuthFlow
+		tokenURL bool
+	}{
+		{"implicit", s.Flows.Implicit, false},
+		{"authorizationCode", s.Flows.AuthorizationCode, false},
+		{"password", s.Flows.Password, true},
+		{"clientCredentials", s.Flows.ClientCredentials, true},
+	} {
+		switch {
+		case f.flow == nil:
+		case f.tokenURL:
+			flows = append(flows, oauth2Flow{f.name, f.flow, f.flow.TokenURL})
+		default:
+			flows = append(flows, oauth2Flow{f.name, f.flow, f.flow.AuthorizationURL})
+		}
+	}
+	return flows
+}
+
+// oauth2Lines renders the !security line of the first flow of the OAuth2
+// security scheme name, declared as id, and the !scope lines of the flow.
+func (g *annotationGen) oauth2Lines(name, id string, s *openapi.SecurityScheme) []string {
+	if s.Flows == nil {
+		return nil
+	}
+	flows := oauth2Flows(s)
+	if len(flows) == 0 {
+		return nil
+	}
+	for _, f := range flows[1:] {
+		g.note("components.securitySchemes.%s: only the first OAuth2 flow is imported, %s is not", name, f.name)
+	}
+	f := flows[0]
+	lines := []string{strings.TrimSpace(withDescription(fmt.Sprintf("!security %s:oauth2:%s", id, f.name), s.Description) + " " + f.url)}
+	for _, scope := range sortedKeys(f.flow.Scopes) {
+		if !scopePattern.MatchString(scope) {
+			g.note("components.securitySchemes.%s: scope %q is not a valid !scope name", name, scope)
+			continue
+		}
+		lines = append(lines, withDescription("!scope "+id+" "+scope, f.flow.Scopes[scope]))
+	}
+	return lines
+}
+
+var (
+	wordPattern  = regexp.MustCompile(`^\w+$`)
+	scopePattern = regexp.MustCompile(`^[\w:]+$`)
+)
+
+// securityName returns the name of a security scheme in !security and
+// !secure lines, which must be a word.
+func (g *annotationGen) securityName(name string) string {
+	if wordPattern.MatchString(name) {
+		return name
+	}
+	return snakeCase(name)
+}
+
+// tagName returns the name of a tag in !tag lines and #tags, which must be
+// a word.
+func (g *annotationGen) tagName(name string) string {
+	if wordPattern.MatchString(name) {
+		return name
+	}
+	return snakeCase(name)
+}
+
+var annotatedMethods = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS", "HEAD"}
+
+// handler renders the handler stub of o, or "" if its method cannot be
+// annotated.
+func (g *annotationGen) handler(o operation) string {
+	where := o.method + " " + o.path
+	if !slices.Contains(annotatedMethods, o.method) {
+		g.note("%s: %s operations cannot be annotated", where, o.method)
+		return ""
+	}
+	name := uniqueName(goName(o.name()), g.taken)
+
+	lines := []string{g.routeLine(o, name)}
+	var todos []string
+	add := func(more, moreTodos []string) {
+		lines = append(lines, more...)
+		todos = append(todos, moreTodos...)
+	}
+	add(g.secureLine(o))
+	add(g.paramLines(o, name))
+	add(g.bodyLines(o, name))
+	add(g.responseLines(o, name))
+	if d := o.op.ExternalDocs; d != nil {
+		lines = append(lines, withDescription("!op-externalDocs "+d.URL, d.Description))
+	}
+	lines = append(lines, extensionLines(o.op.Extensions)...)
+	if o.op.Deprecated {
+		todos = append(todos, "deprecated")
+	}
+	if len(o.op.Callbacks) > 0 {
+		todos = append(todos, "callbacks")
+	}
+	for _, todo := range todos {
+		g.note("%s: %s not imported", where, todo)
+	}
+
+	// the description block runs until the next annotation, so it goes
+	// first
+	var sb strings.Builder
+	writeLines(&sb, descriptionLines(o.op.Description))
+	writeLines(&sb, lines)
+	if len(todos) > 0 {
+		sb.WriteString("//\n// TODO: not imported: " + strings.Join(todos, "; ") + "\n")
+	}
+	fmt.Fprintf(&sb, "func %s(w http.ResponseWriter, r *http.Request) {\n", name)
+	sb.WriteString("\thttp.Error(w, \"not implemented\", http.StatusNotImplemented)\n}\n")
+	return sb.String()
+}
+
+// routeLine renders the route line of o, whose handler is named handler.
+func (g *annotationGen) routeLine(o operation, handler string) string {
+	opID := o.op.OperationID
+	if opID == "" {
+		opID = strings.ToLower(handler[:1]) + handler[1:]
+	}
+	route := fmt.Sprintf("!%s %s -> %s", o.method, o.path, opID)
+	if o.op.Summary != "" {
+		route += " " + quoted(o.op.Summary)
+	}
+	for _, tag := range o.op.Tags {
+		route += " #" + g.tagName(tag)
+	}
+	return route
+}
+
+// secureLine renders the !secure line of o. Operations without security
+// requirements of their own get those of the document, as there is no
+// document-wide annotation.
+func (g *annotationGen) secureLine(o operation) ([]string, []string) {
+	security := o.op.Security
+	if security == nil {
+		security = g.doc.Security
+	}
+	if security == nil {
+		return nil, nil
+	}
+	var schemes []string
+	for _, req := range security {
+		for _, scheme := range sortedKeys(req) {
+			if scheme = g.securityName(scheme); !slices.Contains(schemes, scheme) {
+				schemes = append(schemes, scheme)
+			}
+		}
+	}
+	if len(schemes) == 0 {
+		return nil, []string{"security: explicitly public (security: [])"}
+	}
+	return []string{"!secure " + strings.Join(schemes, " ")}, nil
+}
+
+// paramLines renders the parameter lines of o.
+func (g *annotationGen) paramLines(o operation, handler string) ([]string, []string) {
+	var lines, todos []string
+	for _, p := range o.params {
+		line, todo := g.paramLine(p, handler)
+		if line != "" {
+			lines = append(lines, line)
+		}
+		todos = append(todos, todo...)
+	}
+	return lines, todos
+}
+
+// responseLines renders the !ok and !error lines of o, in the order of its
+// responses.
+func (g *annotationGen) responseLines(o operation, handler string) ([]string, []string) {
+	responses := slices.Clone(o.op.ResponseOrder)
+	for _, status := range sortedKeys(o.op.Responses) {
+		if !slices.Contains(responses, status) {
+			responses = append(responses, status)
+		}
+	}
+	var lines, todos []string
+	for _, status := range responses {
+		line, todo := g.responseLine(status, o.op.Responses[status], handler)
+		if line != "" {
+			lines = append(lines, line)
+		}
+		todos = append(todos, todo...)
+	}
+	return lines, todos
+}
+
+var paramNamePattern = regexp.MustCompile(`^[\w-]+$`)
+
+// paramLine renders the !query, !path, !header, or !cookie line of p.
+func (g *annotationGen) paramLine(p *openapi.Parameter, handler string) (string, []string) {
+	what := fmt.Sprintf("%s parameter %s", p.In, p.Name)
+	if !paramNamePattern.MatchString(p.Name) {
+		return "", []string{what + " (name is not a word)"}
+	}
+	schema := parameterSchema(p)
+	typ := g.schemaRef(schema, handler+goName(p.Name))
+	var todos []string
+	if !wordPattern.MatchString(typ) {
+		todos = append(todos, fmt.Sprintf("%s type %s (imported as %s)", what, typ, simpleType(typ)))
+		typ = simpleType(typ)
+	}
+	line := fmt.Sprintf("!%s %s:%s", p.In, p.Name, typ)
+	if p.Description != "" {
+		line += " " + quoted(p.Description)
+	}
+	if p.Required {
+		line += " required"
+	}
+	if schema != nil && schema.Default != nil {
+		if value := compactValue(schema.Default); !strings.ContainsAny(value, " \t") {
+			line += " default=" + strings.Trim(value, `"`)
+			copied := *schema
+			copied.Default = nil
+			schema = &copied
+		}
+	}
+	if lost := lostKeywords(schema); len(lost) > 0 {
+		todos = append(todos, what+" "+strings.Join(lost, ", "))
+	}
+	return line, todos
+}
+
+// simpleType returns the word type an annotation schema reference is
+// approximated by where only words are allowed.
+func simpleType(ref string) string {
+	switch {
+	case strings.HasPrefix(ref, "[]"):
+		return "array"
+	case strings.HasPrefix(ref, "map["):
+		return "object"
+	}
+	return ref
+}
+
+var formMediaTypes = []string{"application/x-www-form-urlencoded", "multipart/form-data"}
+
+// bodyLines renders the !body or !form lines of the request body of o.
+func (g *annotationGen) bodyLines(o operation, handler string) ([]string, []string) {
+	body := g.requestBody(o.op.RequestBody)
+	if body == nil {
+		return nil, nil
+	}
+
+	content := maps.Clone(body.Content)
+	lines, todos := g.formLines(content, handler)
+	for i, group := range g.groupContent(content, handler+"Request") {
+		if i > 0 {
+			todos = append(todos, "request body "+strings.Join(group.mediaTypes, ", ")+" (a different schema)")
+			continue
+		}
+		line := withDescription("!body "+group.ref, body.Description)
+		if body.Required {
+			line += " required"
+		}
+		lines = append(lines, line+contentTypes(group.mediaTypes))
+	}
+	return lines, todos
+}
+
+// requestBody follows the $refs of body to a component request body.
+func (g *annotationGen) requestBody(body *openapi.RequestBody) *openapi.RequestBody {
+	for i := 0; body != nil && body.Ref != "" && i < maxRefDepth; i++ {
+		if g.doc.Components == nil {
+			return nil
+		}
+		body = g.doc.Components.RequestBodies[shared.ComponentName(body.Ref)]
+	}
+	return body
+}
+
+// formLines renders the !form lines of the first form media type of content
+// whose schema has properties, removing the form media types from content.
+func (g *annotationGen) formLines(content map[string]openapi.MediaType, handler string) ([]string, []string) {
+	var lines, todos []string
+	for _, mediaType := range formMediaTypes {
+		media, ok := content[mediaType]
+		s := resolve(g.doc, media.Schema)
+		if !ok || s == nil || len(s.Properties) == 0 {
+			continue
+		}
+		delete(content, mediaType)
+		if len(lines) > 0 {
+			todos = append(todos, "request body "+mediaType+" (one form per operation)")
+			continue
+		}
+		files := false
+		for _, name := range propertyNames(s) {
+			line, file, todo := g.formLine(name, s, media.Encoding[name], handler)
+			lines = append(lines, line)
+			todos = append(todos, todo...)
+			files = files || file
+		}
+		if mediaType == "multipart/form-data" && !files {
+			todos = append(todos, "request body multipart/form-data (without a file field, the form is sent as application/x-www-form-urlencoded)")
+		}
+	}
+	return lines, todos
+}
+
+// formLine renders the !form line of the property name of the form schema
+// s, and reports whether it is a file.
+func (g *annotationGen) formLine(name string, s *openapi.Schema, encoding openapi.Encoding, handler string) (string, bool, []string) {
+	var todos []string
+	typ := g.schemaRef(s.Properties[name], handler+goName(name))
+	if !wordPattern.MatchString(strings.TrimPrefix(typ, "[]")) {
+		todos = append(todos, fmt.Sprintf("form field %s type %s", name, typ))
+		typ = simpleType(typ)
+	}
+	line := withDescription(fmt.Sprintf("!form %s:%s", name, typ), description(s.Properties[name]))
+	if slices.Contains(s.Required, name) {
+		line += " required"
+	}
+	file := strings.HasSuffix(typ, "binary")
+	if encoding.ContentType != "" {
+		line += " type=" + strings.ReplaceAll(encoding.ContentType, " ", "")
+		file = true
+	}
+	return line, file, todos
+}
+
+var statusPattern = regexp.MustCompile(`^\d{3}$`)
+
+// responseLine renders the !ok or !error line of the response for status.
+func (g *annotationGen) responseLine(status string, r *openapi.Response, handler string) (string, []string) {
+	r = g.response(r)
+	if r == nil {
+		return "", nil
+	}
+	if !statusPattern.MatchString(status) {
+		return "", []string{"response " + status}
+	}
+
+	ref, mediaTypes := "-", []string(nil)
+	var todos []string
+	groups := g.groupContent(r.Content, handler+"Response"+strings.TrimPrefix(status, "200"))
+	if len(groups) > 0 {
+		ref, mediaTypes = groups[0].ref, groups[0].mediaTypes
+		for _, group := range groups[1:] {
+			todos = append(todos, fmt.Sprintf("response %s %s (a different schema)", status, strings.Join(group.mediaTypes, ", ")))
+		}
+	}
+	if len(r.Headers) > 0 {
+		todos = append(todos, "response "+status+" headers")
+	}
+	line := withDescription(responseKeyword(status)+" "+ref, r.Description)
+	return line + contentTypes(mediaTypes), todos
+}
+
+// response follows the $refs of r to a component response.
+func (g *annotationGen) response(r *openapi.Response) *openapi.Response {
+	for i := 0; r != nil && r.Ref != "" && i < maxRefDepth; i++ {
+		if g.doc.Components == nil {
+			return nil
+		}
+		r = g.doc.Components.Responses[shared.ComponentName(r.Ref)]
+	}
+	return r
+}
+
+// responseKeyword returns the annotation of a response for status: !ok for
+// 200, !ok with the status for other successes, or else !error.
+func responseKeyword(status string) string {
+	switch {
+	case status == "200":
+		return "!ok"
+	case strings.HasPrefix(status, "2"):
+		return "!ok " + status
+	}
+	return "!error " + status
+}
+
+// contentGroup is the media types of a content map sharing a schema.
+type contentGroup struct {
+	ref        string
+	mediaTypes []string
+}
+
+// groupContent groups the media types of content by the annotation schema
+// reference of their schema, JSON first.
+func (g *annotationGen) groupContent(content map[string]openapi.MediaType, hint string) []contentGroup {
+	mediaTypes := sortedKeys(content)
+	slices.SortStableFunc(mediaTypes, func(a, b string) int {
+		return boolRank(b == "application/json") - boolRank(a == "application/json")
+	})
+	var groups []contentGroup
+	for _, mediaType := range mediaTypes {
+		schema := content[mediaType].Schema
+		ref := "-"
+		if schema != nil {
+			ref = g.schemaRef(schema, hint)
+		}
+		if i := slices.IndexFunc(groups, func(c contentGroup) bool { return c.ref == ref }); i >= 0 {
+			groups[i].mediaTypes = append(groups[i].mediaTypes, mediaType)
+			continue
+		}
+		groups = append(groups, contentGroup{ref: ref, mediaTypes: []string{mediaType}})
+	}
+	return groups
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// contentTypes renders the type= modifier of the media types, or nothing
+// for the default application/json.
+func contentTypes(mediaTypes []string) string {
+	if len(mediaTypes) == 0 || (len(mediaTypes) == 1 && mediaTypes[0] == "application/json") {
+		return ""
+	}
+	return " type=" + strings.Join(mediaTypes, ",")
+}
+
+// refTypes are the types annotations such as !body and !ok refer to
+// primitive schemas by.
+var refTypes = map[typeFormat]string{
+	{openapi.TypeString, ""}:       "string",
+	{openapi.TypeString, "byte"}:   "byte",
+	{openapi.TypeString, "binary"}: "binary",
+	{openapi.TypeInteger, ""}:      "integer",
+	{openapi.TypeInteger, "int64"}: "int64",
+	{openapi.TypeNumber, ""}:       "number",
+	{openapi.TypeNumber, "float"}:  "float",
+	{openapi.TypeBoolean, ""}:      "boolean",
+	{openapi.TypeObject, ""}:       "object",
+}
+
+// schemaRef returns how annotations such as !body and !ok refer to s: a
+// model name, a primitive type, or []T and map[string]T of those. Inline
+// objects become models named hint.
+func (g *annotationGen) schemaRef(s *openapi.Schema, hint string) string {
+	if s == nil {
+		return "object"
+	}
+	if typ, ok := g.namedType(s, hint, "object", g.schemaRef); ok {
+		return typ
+	}
+	t := types(s)
+	switch {
+	case len(t) != 1:
+		return "object"
+	case t[0] == openapi.TypeArray:
+		return "[]" + g.schemaRef(s.Items, hint+"Item")
+	case t[0] == openapi.TypeObject && s.AdditionalProperties != nil:
+		return "map[string]" + g.schemaRef(s.AdditionalProperties, hint+"Value")
+	}
+	if typ, ok := lookupType(refTypes, t[0], s.Format); ok {
+		return typ
+	}
+	return "object"
+}
+
+// namedType returns the type of s if it is a named one: the model of the
+// component schema s refers to, or else fallback; the type typeOf returns
+// for the single part of an allOf; or a model declared for an inline
+// object, named hint.
+func (g *annotationGen) namedType(s *openapi.Schema, hint, fallback string, typeOf func(*openapi.Schema, string) string) (string, bool) {
+	switch {
+	case s.Ref != "":
+		if name, ok := g.typeNames[shared.ComponentName(s.Ref)]; ok {
+			return name, true
+		}
+		return fallback, true
+	case len(s.AllOf) == 1 && len(s.Properties) == 0:
+		return typeOf(s.AllOf[0], hint), true
+	case g.isStruct(s):
+		return g.inlineModel(s, hint), true
+	}
+	return "", false
+}
+
+// isStruct reports whether s is an object with properties or an allOf,
+// declared as a struct.
+func (g *annotationGen) isStruct(s *openapi.Schema) bool {
+	if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		return false
+	}
+	return len(s.Properties) > 0 || len(s.AllOf) > 0
+}
+
+// inlineModel declares a model for the inline schema s, named hint.
+func (g *annotationGen) inlineModel(s *openapi.Schema, hint string) string {
+	name := uniqueName(goName(hint), g.taken)
+	g.models = append(g.models, g.model(name, s, name))
+	return name
+}
+
+// goTypes are the Go types of fields holding primitive schemas.
+var goTypes = map[typeFormat]string{
+	{openapi.TypeString, ""}:          "string",
+	{openapi.TypeString, "date-time"}: "time.Time",
+	{openapi.TypeString, "byte"}:      "[]byte",
+	{openapi.TypeInteger, ""}:         "int",
+	{openapi.TypeInteger, "int32"}:    "int32",
+	{openapi.TypeInteger, "int64"}:    "int64",
+	{openapi.TypeNumber, ""}:          "float64",
+	{openapi.TypeNumber, "float"}:     "float32",
+	{openapi.TypeBoolean, ""}:         "bool",
+	{openapi.TypeObject, ""}:          "map[string]any",
+}
+
+// goType returns the Go type of a field holding s. Inline objects become
+// models named hint.
+func (g *annotationGen) goType(s *openapi.Schema, hint string) string {
+	if s == nil {
+		return "any"
+	}
+	if typ, ok := g.namedType(s, hint, "any", g.goType); ok {
+		return typ
+	}
+	t := types(s)
+	switch {
+	case len(t) != 1:
+		return "any"
+	case t[0] == openapi.TypeArray:
+		return "[]" + g.goType(s.Items, hint+"Item")
+	case t[0] == openapi.TypeObject && s.AdditionalProperties != nil:
+		return "map[string]" + g.goType(s.AdditionalProperties, hint+"Value")
+	}
+	typ, ok := lookupType(goTypes, t[0], s.Format)
+	if !ok {
+		return "any"
+	}
+	if typ == "time.Time" {
+		g.imports["time"] = true
+	}
+	return typ
+}
+
+// inferredFormats are the formats the generator infers from Go types.
+var inferredFormats = map[string]string{
+	"int": "int32", "int32": "int32", "int64": "int64",
+	"float32": "float", "float64": "double",
+	"time.Time": "date-time", "[]byte": "byte",
+}
+
+// model renders the declaration of the model name for s; where locates s
+// in notes.
+func (g *annotationGen) model(name string, s *openapi.Schema, where string) string {
+	var sb strings.Builder
+	line := "!model"
+	if multiline(s.Description) {
+		writeLines(&sb, descriptionLines(s.Description))
+	} else {
+		line = withDescription(line, s.Description)
+	}
+	if !g.isStruct(s) {
+		g.writeModelHeader(&sb, line, s, lostKeywords(s), where)
+		fmt.Fprintf(&sb, "type %s %s\n", name, g.aliasedType(name, s))
+		return sb.String()
+	}
+
+	embedded, own := g.flattenAllOf(s, where)
+	if s.AdditionalProperties != nil {
+		line += " additionalProperties=" + g.schemaRef(s.AdditionalProperties, name+"Value")
+	}
+	lost := lostKeywords(&openapi.Schema{Discriminator: s.Discriminator, MinProperties: s.MinProperties, MaxProperties: s.MaxProperties})
+	g.writeModelHeader(&sb, line, s, lost, where)
+	fmt.Fprintf(&sb, "type %s struct {\n", name)
+	for _, e := range embedded {
+		fmt.Fprintf(&sb, "\t%s\n", e)
+	}
+	fields := map[string]bool{}
+	for i, prop := range propertyNames(own) {
+		if i > 0 || len(embedded) > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(g.field(prop, own.Properties[prop], slices.Contains(own.Required, prop), name, where, fields))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// writeModelHeader writes the !model line, the !x lines of s, and a TODO
+// listing the lost keywords, which are noted.
+func (g *annotationGen) writeModelHeader(sb *strings.Builder, line string, s *openapi.Schema, lost []string, where string) {
+	for _, l := range lost {
+		g.note("%s: %s not imported", where, l)
+	}
+	sb.WriteString("// " + line + "\n")
+	for _, ext := range extensionLines(s.Extensions) {
+		sb.WriteString("// " + ext + "\n")
+	}
+	writeTodo(sb, lost, "")
+}
+
+// aliasedType returns the Go type of the model name for s, which is not a
+// struct.
+func (g *annotationGen) aliasedType(name string, s *openapi.Schema) string {
+	if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		return "any"
+	}
+	if typ := g.goType(s, name+"Value"); typ != name {
+		return typ
+	}
+	return "any"
+}
+
+// flattenAllOf returns the types of the models the allOf of s refers to,
+// embedded in its struct, and the properties of s along with those of its
+// inline allOf parts.
+func (g *annotationGen) flattenAllOf(s *openapi.Schema, where string) ([]string, *openapi.Schema) {
+	var embedded []string
+	own := &openapi.Schema{Properties: map[string]*openapi.Schema{}, Required: slices.Clone(s.Required)}
+	for _, part := range s.AllOf {
+		switch {
+		case part.Ref != "":
+			embedded = append(embedded, g.goType(part, ""))
+		case len(part.Properties) > 0 && len(part.AllOf) == 0:
+			addProperties(own, part)
+		default:
+			g.note("%s: allOf part without properties not imported", where)
+		}
+	}
+	addProperties(own, &openapi.Schema{Properties: s.Properties, PropertyOrder: s.PropertyOrder})
+	return embedded, own
+}
+
+// addProperties adds the properties and required properties of part to own.
+func addProperties(own, part *openapi.Schema) {
+	for _, prop := range propertyNames(part) {
+		if _, ok := own.Properties[prop]; !ok {
+			own.PropertyOrder = append(own.PropertyOrder, prop)
+		}
+		own.Properties[prop] = part.Properties[prop]
+	}
+	own.Required = append(own.Required, part.Required...)
+}
+
+var fieldTypePattern = regexp.MustCompile(`^[\w.\[\]]+$`)
+
+// field renders the struct field of the property prop of the model owner.
+func (g *annotationGen) field(prop string, s *openapi.Schema, required bool, owner, where string, taken map[string]bool) string {
+	fieldName := uniqueName(goName(prop), taken)
+	typ := g.goType(s, owner+fieldName)
+	if s != nil && nullable(s) && !strings.HasPrefix(typ, "[]") && !strings.HasPrefix(typ, "map[") && typ != "any" {
+		typ = "*" + typ
+	}
+	tag := prop
+	if !required {
+		tag += ",omitempty"
+	}
+
+	var sb strings.Builder
+	if line := fieldLine(prop, fieldName, strings.TrimPrefix(typ, "*"), s); line != "" {
+		sb.WriteString("\t// " + line + "\n")
+	}
+	for _, ext := range extensionLines(extensionsOf(s)) {
+		sb.WriteString("\t// " + ext + "\n")
+	}
+	lost := lostKeywords(s)
+	for _, l := range lost {
+		g.note("%s.%s: %s not imported", where, prop, l)
+	}
+	writeTodo(&sb, lost, "\t")
+	fmt.Fprintf(&sb, "\t%s %s `json:%q`\n", fieldName, typ, tag)
+	return sb.String()
+}
+
+// fieldLine renders the !field line of the property prop, declared as the
+// field fieldName of type typ, or "" if s has nothing to add to its type.
+func fieldLine(prop, fieldName, typ string, s *openapi.Schema) string {
+	desc, modifiers := description(s), fieldModifiers(s, typ)
+	if desc == "" && len(modifiers) == 0 {
+		return ""
+	}
+	name := prop
+	if !wordPattern.MatchString(name) {
+		name = fieldName
+	}
+	if !fieldTypePattern.MatchString(typ) {
+		typ = "object"
+	}
+	line := withDescription(fmt.Sprintf("!field %s:%s", name, typ), desc)
+	if len(modifiers) > 0 {
+		line += " " + strings.Join(modifiers, " ")
+	}
+	return line
+}
+
+// fieldModifiers returns the modifiers of the !field line of a field of
+// type typ holding s.
+func fieldModifiers(s *openapi.Schema, typ string) []string {
+	if s == nil {
+		return nil
+	}
+	var modifiers []string
+	for _, flag := range []struct {
+		set  bool
+		name string
+	}{{s.Deprecated, "deprecated"}, {s.ReadOnly, "readonly"}, {s.WriteOnly, "writeonly"}} {
+		if flag.set {
+			modifiers = append(modifiers, flag.name)
+		}
+	}
+	if example := schemaExample(s); example != nil {
+		if value := exampleValue(example); value != "" {
+			modifiers = append(modifiers, "example="+value)
+		}
+	}
+	if s.Format != "" && s.Format != inferredFormats[typ] && s.Ref == "" {
+		modifiers = append(modifiers, "format="+s.Format)
+	}
+	return modifiers
+}
+
+// schemaExample returns the example of s, or else its first examples entry.
+func schemaExample(s *openapi.Schema) any {
+	if s.Example == nil && len(s.Examples) > 0 {
+		return s.Examples[0]
+	}
+	return s.Example
+}
+
+func extensionsOf(s *openapi.Schema) openapi.Extensions {
+	if s == nil {
+		return nil
+	}
+	return s.Extensions
+}
+
+func writeTodo(sb *strings.Builder, lost []string, indent string) {
+	if len(lost) > 0 {
+		sb.WriteString(indent + "// TODO: not imported: " + strings.Join(lost, "; ") + "\n")
+	}
+}
+
+// lostKeywords describes the keywords of s that annotations cannot express.
+func lostKeywords(s *openapi.Schema) []string {
+	if s == nil || s.Ref != "" {
+		return nil
+	}
+	var lost []string
+	for _, keyword := range []struct {
+		keyword string
+		value   any
+		set     bool
+	}{
+		{"enum", s.Enum, len(s.Enum) > 0},
+		{"default", s.Default, s.Default != nil},
+		{"pattern", s.Pattern, s.Pattern != ""},
+		{"minimum", ptrValue(s.Minimum), s.Minimum != nil},
+		{"maximum", ptrValue(s.Maximum), s.Maximum != nil},
+		{"exclusiveMinimum", s.ExclusiveMinimum, s.ExclusiveMinimum != nil},
+		{"exclusiveMaximum", s.ExclusiveMaximum, s.ExclusiveMaximum != nil},
+		{"multipleOf", ptrValue(s.MultipleOf), s.MultipleOf != nil},
+		{"minLength", ptrValue(s.MinLength), s.MinLength != nil},
+		{"maxLength", ptrValue(s.MaxLength), s.MaxLength != nil},
+		{"minItems", ptrValue(s.MinItems), s.MinItems != nil},
+		{"maxItems", ptrValue(s.MaxItems), s.MaxItems != nil},
+		{"minProperties", ptrValue(s.MinProperties), s.MinProperties != nil},
+		{"maxProperties", ptrValue(s.MaxProperties), s.MaxProperties != nil},
+	} {
+		if keyword.set {
+			lost = append(lost, keyword.keyword+" "+compactValue(keyword.value))
+		}
+	}
+	return append(lost, lostStructure(s)...)
+}
+
+// lostStructure describes the keywords of s constraining its structure that
+// annotations cannot express, such as oneOf.
+func lostStructure(s *openapi.Schema) []string {
+	var lost []string
+	if s.UniqueItems {
+		lost = append(lost, "uniqueItems")
+	}
+	for _, c := range []struct {
+		keyword string
+		n       int
+	}{{"oneOf", len(s.OneOf)}, {"anyOf", len(s.AnyOf)}} {
+		if c.n > 0 {
+			lost = append(lost, fmt.Sprintf("%s of %d schemas", c.keyword, c.n))
+		}
+	}
+	if s.Not != nil {
+		lost = append(lost, "not")
+	}
+	if s.Discriminator != nil {
+		lost = append(lost, "discriminator "+s.Discriminator.PropertyName)
+	}
+	return lost
+}
+
+func ptrValue[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+// compactValue renders v as compact JSON.
+func compactValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// exampleValue renders v for an example= modifier, which takes a word or a
+// quoted string without quotes, or "" if it cannot.
+func exampleValue(v any) string {
+	if s, ok := v.(string); ok {
+		switch {
+		case strings.Contains(s, "\n"):
+			return ""
+		case strings.ContainsAny(s, " \t\"") || s == "":
+			return `"` + annotationEscaper.Replace(s) + `"`
+		}
+		return s
+	}
+	value := compactValue(v)
+	if strings.ContainsAny(value, " \t") {
+		return ""
+	}
+	return value
+}
+
+// extensionLines renders the !x lines of extensions.
+func extensionLines(extensions openapi.Extensions) []string {
+	var lines []string
+	for _, key := range sortedKeys(extensions) {
+		if extensions[key] == true {
+			lines = append(lines, "!x "+key)
+			continue
+		}
+		value := compactValue(extensions[key])
+		if s, ok := extensions[key].(string); ok && !strings.ContainsAny(s, "\n") {
+			value = s
+		}
+		lines = append(lines, "!x "+key+" "+value)
+	}
+	return lines
+}
+
+// annotationEscaper escapes the double quotes and backslashes of a quoted
+// annotation argument.
+var annotationEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// quoted renders text as a quoted annotation argument on one line.
+func quoted(text string) string {
+	return `"` + annotationEscaper.Replace(strings.Join(strings.Fields(text), " ")) + `"`
+}
+
+// withDescription appends the quoted description to line if not empty.
+func withDescription(line, description string) string {
+	if description == "" {
+		return line
+	}
+	return line + " " + quoted(description)
+}
+
+// multiline reports whether the description text spans several lines,
+// which quoted would join.
+func multiline(text string) bool {
+	return strings.Contains(strings.TrimSpace(text), "\n")
+}
+
+// descriptionLines renders text as a !description block, keeping its
+// Markdown line breaks, or nil if it is empty.
+func descriptionLines(text string) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	return append([]string{"!description"}, strings.Split(text, "\n")...)
+}
+
+// writeLines writes each line as a comment, without trailing spaces.
+func writeLines(sb *strings.Builder, lines []string) {
+	for _, line := range lines {
+		sb.WriteString(strings.TrimRight("// "+line, " \t") + "\n")
+	}
+}
+
+// goInitialisms are the words spelled in capitals in Go identifiers.
+var goInitialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "TLS": true, "UI": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+var exportedIdentifier = regexp.MustCompile(`^[A-Z][A-Za-z0-9_]*$`)
+
+// goName returns name as an exported Go identifier: unchanged if it is
+// one, and in pascal case with Go's initialisms otherwise, e.g. PetID for
+// "petId".
+func goName(name string) string {
+	if exportedIdentifier.MatchString(name) {
+		return name
+	}
+	var sb strings.Builder
+	for _, w := range words(name) {
+		if upper := strings.ToUpper(w); goInitialisms[upper] {
+			sb.WriteString(upper)
+			continue
+		}
+		runes := []rune(w)
+		sb.WriteString(strings.ToUpper(string(runes[0])) + string(runes[1:]))
+	}
+	if sb.Len() == 0 || !exportedIdentifier.MatchString(sb.String()) {
+		return pascalCase(name)
+	}
+	return sb.String()
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
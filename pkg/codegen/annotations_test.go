@@ -0,0 +1,251 @@
+package codegen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/internal/parser"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+const importSpec = `openapi: 3.0.3
+info:
+  title: Pet Store
+  version: 1.2.0
+  description: Sells pets.
+  license: {name: MIT, url: https://opensource.org/licenses/MIT}
+servers:
+  - url: https://api.example.com
+    description: Production
+tags:
+  - name: pets
+    description: Manage pets
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets, "sold" ones last
+      description: |
+        Lists the pets in stock.
+
+        - sold pets come last
+      tags: [pets]
+      parameters:
+        - name: limit
+          in: query
+          description: Page size
+          schema: {type: integer, default: 20}
+      responses:
+        "200":
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: '#/components/schemas/Pet'}
+    post:
+      operationId: addPet
+      tags: [pets]
+      security: [{api_key: []}]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+        "400":
+          description: Invalid pet
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  message: {type: string}
+  /pets/{petId}:
+    delete:
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema: {type: integer, format: int64}
+      responses:
+        "204":
+          description: Deleted
+components:
+  securitySchemes:
+    api_key: {type: apiKey, in: header, name: X-API-Key}
+  schemas:
+    Pet:
+      type: object
+      description: |
+        A pet for sale.
+
+        Pets are *sold* once.
+      required: [id, name]
+      properties:
+        id: {type: integer, format: int64, readOnly: true}
+        name: {type: string, description: Name of the pet, example: Rex}
+        email: {type: string, format: email}
+        status: {type: string, enum: [available, sold]}
+        born: {type: string, format: date-time, nullable: true}
+        owner:
+          type: object
+          properties:
+            user_id: {type: string}
+    Dog:
+      allOf:
+        - $ref: '#/components/schemas/Pet'
+        - type: object
+          properties:
+            barks: {type: boolean}
+    Stock:
+      type: object
+      additionalProperties: {type: integer}
+`
+
+// roundTrip imports importSpec and parses the generated files back into a
+// specification.
+func roundTrip(t *testing.T) *openapi.Document {
+	t.Helper()
+	doc := parse(t, importSpec)
+	files, notes, err := Annotations(doc, AnnotationsOptions{Package: "petstore"})
+	if err != nil {
+		t.Fatalf("Annotations() error = %v", err)
+	}
+
+	var names []string
+	dir := t.TempDir()
+	for _, f := range files {
+		names = append(names, f.Name)
+		if err := os.WriteFile(filepath.Join(dir, f.Name), f.Content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{"api.go", "models.go", "pets.go", "handlers.go"}; !slices.Equal(names, want) {
+		t.Errorf("files = %v, want %v", names, want)
+	}
+	if want := []string{`components.schemas.Pet.status: enum ["available","sold"] not imported`}; !slices.Equal(notes, want) {
+		t.Errorf("notes = %q, want %q", notes, want)
+	}
+
+	p := parser.New()
+	if err := p.ParseDir(dir); err != nil {
+		t.Fatalf("ParseDir() error = %v", err)
+	}
+	return p.Generate()
+}
+
+// TestAnnotations_RoundTrip parses the generated files back into a
+// specification and compares it with the imported one.
+func TestAnnotations_RoundTrip(t *testing.T) {
+	got := roundTrip(t)
+	if got.Info.Title != "Pet Store" || got.Info.Version != "1.2.0" || got.Info.Description != "Sells pets." {
+		t.Errorf("info = %+v", got.Info)
+	}
+	if len(got.Servers) != 1 || got.Servers[0].URL != "https://api.example.com" {
+		t.Errorf("servers = %+v", got.Servers)
+	}
+	if s := got.Components.SecuritySchemes["api_key"]; s == nil || s.In != "header" || s.Name != "X-API-Key" {
+		t.Errorf("api_key security scheme = %+v", s)
+	}
+}
+
+func TestAnnotations_RoundTripOperations(t *testing.T) {
+	got := roundTrip(t)
+	for path, ops := range map[string][]string{"/pets": {"listPets", "addPet"}, "/pets/{petId}": {"deletePetsPetID"}} {
+		item := got.Paths[path]
+		if item == nil {
+			t.Errorf("path %s missing", path)
+			continue
+		}
+		var ids []string
+		for _, op := range []*openapi.Operation{item.Get, item.Post, item.Delete} {
+			if op != nil {
+				ids = append(ids, op.OperationID)
+			}
+		}
+		if !slices.Equal(ids, ops) {
+			t.Errorf("%s operations = %v, want %v", path, ids, ops)
+		}
+	}
+	if summary := got.Paths["/pets"].Get.Summary; summary != `List pets, "sold" ones last` {
+		t.Errorf("listPets summary = %q", summary)
+	}
+	if desc := got.Paths["/pets"].Get.Description; desc != "Lists the pets in stock.\n\n- sold pets come last" {
+		t.Errorf("listPets description = %q", desc)
+	}
+}
+
+func TestAnnotations_RoundTripRequests(t *testing.T) {
+	got := roundTrip(t)
+	addPet := got.Paths["/pets"].Post
+	if limit := got.Paths["/pets"].Get.Parameters[0]; limit.Name != "limit" || fmt.Sprint(limit.Example) != "20" {
+		t.Errorf("listPets limit parameter = %+v", limit)
+	}
+	if len(addPet.Security) != 1 || addPet.RequestBody == nil || !addPet.RequestBody.Required {
+		t.Errorf("addPet = %+v", addPet)
+	}
+	if r := addPet.Responses["400"]; r == nil || r.Content["application/json"].Schema.Ref != "#/components/schemas/AddPetResponse400" {
+		t.Errorf("addPet 400 response = %+v", r)
+	}
+}
+
+func TestAnnotations_RoundTripModels(t *testing.T) {
+	got := roundTrip(t)
+	pet := got.Components.Schemas["Pet"]
+	if pet == nil {
+		t.Fatal("Pet schema missing")
+	}
+	if pet.Description != "A pet for sale.\n\nPets are *sold* once." {
+		t.Errorf("Pet description = %q", pet.Description)
+	}
+	if !slices.Equal(pet.Required, []string{"id", "name"}) {
+		t.Errorf("Pet required = %v", pet.Required)
+	}
+	if dog := got.Components.Schemas["Dog"]; dog == nil || len(dog.AllOf) != 2 || dog.AllOf[0].Ref != "#/components/schemas/Pet" {
+		t.Errorf("Dog = %+v", dog)
+	}
+	if stock := got.Components.Schemas["Stock"]; stock == nil || stock.AdditionalProperties == nil {
+		t.Errorf("Stock = %+v", stock)
+	}
+}
+
+func TestAnnotations_RoundTripFields(t *testing.T) {
+	pet := roundTrip(t).Components.Schemas["Pet"]
+	for name, ok := range map[string]func(s *openapi.Schema) bool{
+		"id":    func(s *openapi.Schema) bool { return s.ReadOnly && s.Format == "int64" },
+		"name":  func(s *openapi.Schema) bool { return s.Description == "Name of the pet" && s.Example == "Rex" },
+		"email": func(s *openapi.Schema) bool { return s.Format == "email" },
+		"born":  func(s *openapi.Schema) bool { return s.Format == "date-time" && s.Nullable },
+		"owner": func(s *openapi.Schema) bool { return s.Ref == "#/components/schemas/PetOwner" },
+	} {
+		if prop := pet.Properties[name]; prop == nil || !ok(prop) {
+			t.Errorf("Pet.%s = %+v", name, prop)
+		}
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"Pet":         "Pet",
+		"APIResponse": "APIResponse",
+		"petId":       "PetID",
+		"user_id":     "UserID",
+		"pet-owner":   "PetOwner",
+		"html_url":    "HTMLURL",
+		"2fa":         "T2fa",
+	}
+	for in, want := range tests {
+		if got := goName(in); got != want {
+			t.Errorf("goName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package codegen
+
+import (
+	"errors"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// AsyncAPIVersion is the AsyncAPI version AsyncAPI exports.
+const AsyncAPIVersion = "3.0.0"
+
+// AsyncAPIDocument is an AsyncAPI document describing the events an API
+// sends.
+// https://www.asyncapi.com/docs/reference/specification/v3.0.0
+type AsyncAPIDocument struct {
+	AsyncAPI           string                        `json:"asyncapi" yaml:"asyncapi"`
+	Info               AsyncAPIInfo                  `json:"info" yaml:"info"`
+	DefaultContentType string                        `json:"defaultContentType,omitempty" yaml:"defaultContentType,omitempty"`
+	Channels           map[string]*AsyncAPIChannel   `json:"channels" yaml:"channels"`
+	Operations         map[string]*AsyncAPIOperation `json:"operations" yaml:"operations"`
+	Components         *AsyncAPIComponents           `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+// AsyncAPIInfo provides metadata about the API.
+type AsyncAPIInfo struct {
+	Title          string           `json:"title" yaml:"title"`
+	Version        string           `json:"version" yaml:"version"`
+	Description    string           `json:"description,omitempty" yaml:"description,omitempty"`
+	TermsOfService string           `json:"termsOfService,omitempty" yaml:"termsOfService,omitempty"`
+	Contact        *openapi.Contact `json:"contact,omitempty" yaml:"contact,omitempty"`
+	License        *AsyncAPILicense `json:"license,omitempty" yaml:"license,omitempty"`
+}
+
+// AsyncAPILicense is the license information of the API.
+type AsyncAPILicense struct {
+	Name string `json:"name" yaml:"name"`
+	URL  string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+// AsyncAPIChannel is a channel messages are sent through, one per webhook.
+type AsyncAPIChannel struct {
+	Summary     string                        `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string                        `json:"description,omitempty" yaml:"description,omitempty"`
+	Messages    map[string]*AsyncAPIReference `json:"messages" yaml:"messages"`
+}
+
+// AsyncAPIOperation is an action the API performs on a channel.
+type AsyncAPIOperation struct {
+	Action      string                     `json:"action" yaml:"action"`
+	Channel     AsyncAPIReference          `json:"channel" yaml:"channel"`
+	Summary     string                     `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string                     `json:"description,omitempty" yaml:"description,omitempty"`
+	Messages    []AsyncAPIReference        `json:"messages" yaml:"messages"`
+	Bindings    *AsyncAPIOperationBindings `json:"bindings,omitempty" yaml:"bindings,omitempty"`
+}
+
+// AsyncAPIOperationBindings holds the protocol-specific details of an
+// operation.
+type AsyncAPIOperationBindings struct {
+	HTTP *AsyncAPIHTTPBinding `json:"http,omitempty" yaml:"http,omitempty"`
+}
+
+// AsyncAPIHTTPBinding is the HTTP method a webhook is delivered with.
+type AsyncAPIHTTPBinding struct {
+	Method         string `json:"method" yaml:"method"`
+	BindingVersion string `json:"bindingVersion" yaml:"bindingVersion"`
+}
+
+// AsyncAPIMessage is a message sent on a channel: the body of a webhook
+// request, with its header parameters as headers.
+type AsyncAPIMessage struct {
+	Name        string          `json:"name" yaml:"name"`
+	Title       string          `json:"title,omitempty" yaml:"title,omitempty"`
+	Summary     string          `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string          `json:"description,omitempty" yaml:"description,omitempty"`
+	ContentType string          `json:"contentType,omitempty" yaml:"contentType,omitempty"`
+	Headers     *openapi.Schema `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Payload     *openapi.Schema `json:"payload,omitempty" yaml:"payload,omitempty"`
+}
+
+// AsyncAPIComponents holds the messages and the schemas they use.
+type AsyncAPIComponents struct {
+	Schemas  map[string]*openapi.Schema  `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+	Messages map[string]*AsyncAPIMessage `json:"messages,omitempty" yaml:"messages,omitempty"`
+}
+
+// AsyncAPIReference is a $ref to another object of the document.
+type AsyncAPIReference struct {
+	Ref string `json:"$ref" yaml:"$ref"`
+}
+
+// ErrNoWebhooks is returned by AsyncAPI for documents without webhooks.
+var ErrNoWebhooks = errors.New("specification has no webhooks")
+
+// AsyncAPI converts the webhooks of doc into an AsyncAPI 3.0 document for
+// event consumers. Each webhook becomes a channel named after it, and each
+// of its operations a send operation, named after its operationId, whose
+// message carries the request body as payload and the header parameters as
+// headers. Names that are not valid AsyncAPI keys are converted to camel
+// case. The component schemas the webhooks use are kept under the same
+// names, so their $refs resolve unchanged.
+func AsyncAPI(doc *openapi.Document) (*AsyncAPIDocument, error) {
+	if len(doc.Webhooks) == 0 {
+		return nil, ErrNoWebhooks
+	}
+
+	g := &asyncAPIGen{
+		doc: doc,
+		out: &AsyncAPIDocument{
+			AsyncAPI:           AsyncAPIVersion,
+			Info:               asyncAPIInfo(doc.Info),
+			DefaultContentType: "application/json",
+			Channels:           map[string]*AsyncAPIChannel{},
+			Operations:         map[string]*AsyncAPIOperation{},
+			Components:         &AsyncAPIComponents{Messages: map[string]*AsyncAPIMessage{}},
+		},
+		channelIDs: map[string]bool{},
+		ids:        map[string]bool{},
+	}
+	for _, name := range slices.Sorted(maps.Keys(doc.Webhooks)) {
+		if item := doc.Webhooks[name]; item != nil {
+			g.webhook(name, item)
+		}
+	}
+
+	schemas, err := webhookSchemas(doc)
+	if err != nil {
+		return nil, err
+	}
+	g.out.Components.Schemas = schemas
+	return g.out, nil
+}
+
+// asyncAPIInfo returns the AsyncAPI counterpart of info.
+func asyncAPIInfo(info openapi.Info) AsyncAPIInfo {
+	out := AsyncAPIInfo{
+		Title:          info.Title,
+		Version:        info.Version,
+		Description:    info.Description,
+		TermsOfService: info.TermsOfService,
+		Contact:        info.Contact,
+	}
+	if license := info.License; license != nil {
+		out.License = &AsyncAPILicense{Name: license.Name, URL: license.URL}
+	}
+	return out
+}
+
+type asyncAPIGen struct {
+	doc        *openapi.Document
+	out        *AsyncAPIDocument
+	channelIDs map[string]bool // taken channel keys
+	ids        map[string]bool // taken operation and message keys
+}
+
+// webhook adds a channel for the webhook named name, with a send operation
+// and a message per operation of item, unless item has no operations.
+func (g *asyncAPIGen) webhook(name string, item *openapi.PathItem) {
+	webhook := &openapi.Document{Paths: openapi.Paths{name: item}, PathOrder: []string{name}, Components: g.doc.Components}
+	ops := operations(webhook)
+	if len(ops) == 0 {
+		return
+	}
+
+	channelID := uniqueName(asyncAPIKey(name), g.channelIDs)
+	channel := &AsyncAPIChannel{Summary: item.Summary, Description: item.Description, Messages: map[string]*AsyncAPIReference{}}
+	g.out.Channels[channelID] = channel
+	for _, o := range ops {
+		id := o.op.OperationID
+		if id == "" {
+			id = channelID
+			if len(ops) > 1 {
+				id += pascalCase(strings.ToLower(o.method))
+			}
+		}
+		id = uniqueName(asyncAPIKey(id), g.ids)
+
+		g.out.Components.Messages[id] = g.message(id, o)
+		channel.Messages[id] = &AsyncAPIReference{Ref: "#/components/messages/" + id}
+		g.out.Operations[id] = &AsyncAPIOperation{
+			Action:      "send",
+			Channel:     AsyncAPIReference{Ref: "#/channels/" + channelID},
+			Summary:     o.op.Summary,
+			Description: o.op.Description,
+			Messages:    []AsyncAPIReference{{Ref: "#/channels/" + channelID + "/messages/" + id}},
+			Bindings:    &AsyncAPIOperationBindings{HTTP: &AsyncAPIHTTPBinding{Method: o.method, BindingVersion: "0.3.0"}},
+		}
+	}
+}
+
+// message returns the message named id of the operation o, carrying its
+// request body and header parameters.
+func (g *asyncAPIGen) message(id string, o operation) *AsyncAPIMessage {
+	message := &AsyncAPIMessage{Name: id, Title: o.op.Summary, Description: o.op.Description}
+	if body := resolveRequestBody(g.doc, o.op.RequestBody); body != nil && len(body.Content) > 0 {
+		message.ContentType = contentMediaType(body.Content)
+		message.Payload = body.Content[message.ContentType].Schema
+	}
+	message.Headers = headerSchema(o.params)
+	return message
+}
+
+// webhookSchemas returns the component schemas the webhooks of doc use.
+func webhookSchemas(doc *openapi.Document) (map[string]*openapi.Schema, error) {
+	if doc.Components == nil {
+		return nil, nil
+	}
+	webhooks := &openapi.Document{Webhooks: doc.Webhooks, Components: doc.Components}
+	used, err := openapi.Filter(webhooks, openapi.FilterOptions{})
+	if err != nil || used.Components == nil {
+		return nil, err
+	}
+	return used.Components.Schemas, nil
+}
+
+// headerSchema returns an object schema of the header parameters of params,
+// or nil if there are none.
+func headerSchema(params []*openapi.Parameter) *openapi.Schema {
+	var headers *openapi.Schema
+	for _, p := range params {
+		if p.In != openapi.ParameterInHeader {
+			continue
+		}
+		if headers == nil {
+			headers = &openapi.Schema{Type: openapi.NewSchemaType(openapi.TypeObject), Properties: map[string]*openapi.Schema{}}
+		}
+		schema := parameterSchema(p)
+		if schema == nil {
+			schema = &openapi.Schema{Type: openapi.NewSchemaType(openapi.TypeString)}
+		}
+		if p.Description != "" {
+			copied := *schema
+			copied.Description = p.Description
+			schema = &copied
+		}
+		headers.Properties[p.Name] = schema
+		headers.PropertyOrder = append(headers.PropertyOrder, p.Name)
+		if p.Required {
+			headers.Required = append(headers.Required, p.Name)
+		}
+	}
+	return headers
+}
+
+var asyncAPIKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// asyncAPIKey returns name if it is a valid key for channels, operations,
+// and components, and name in camel case otherwise, e.g. orderCreated for
+// "order/created".
+func asyncAPIKey(name string) string {
+	if asyncAPIKeyPattern.MatchString(name) {
+		return name
+	}
+	key := pascalCase(name)
+	return strings.ToLower(key[:1]) + key[1:]
+}
@@ -0,0 +1,127 @@
+package codegen
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestAsyncAPI(t *testing.T) {
+	spec := `openapi: 3.1.0
+info:
+  title: Pet Store
+  version: 1.2.0
+  license: {name: MIT, identifier: MIT}
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: Pets
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Order'}
+webhooks:
+  newPet:
+    post:
+      operationId: petAdded
+      summary: A pet was added
+      parameters:
+        - name: X-Signature
+          in: header
+          required: true
+          description: HMAC of the body
+          schema: {type: string}
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        "200":
+          description: Received
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string}
+        category: {$ref: '#/components/schemas/Category'}
+    Category:
+      type: string
+    Order:
+      type: object
+`
+	want := `asyncapi: 3.0.0
+info:
+  title: Pet Store
+  version: 1.2.0
+  license:
+    name: MIT
+defaultContentType: application/json
+channels:
+  newPet:
+    messages:
+      petAdded:
+        $ref: '#/components/messages/petAdded'
+operations:
+  petAdded:
+    action: send
+    channel:
+      $ref: '#/channels/newPet'
+    summary: A pet was added
+    messages:
+      - $ref: '#/channels/newPet/messages/petAdded'
+    bindings:
+      http:
+        method: POST
+        bindingVersion: 0.3.0
+components:
+  schemas:
+    Category:
+      type: string
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+        category:
+          $ref: '#/components/schemas/Category'
+  messages:
+    petAdded:
+      name: petAdded
+      title: A pet was added
+      contentType: application/json
+      headers:
+        type: object
+        properties:
+          X-Signature:
+            type: string
+            description: HMAC of the body
+        required:
+          - X-Signature
+      payload:
+        $ref: '#/components/schemas/Pet'
+`
+	out, err := AsyncAPI(parse(t, spec))
+	if err != nil {
+		t.Fatalf("AsyncAPI() error = %v", err)
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(out); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("AsyncAPI() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAsyncAPI_NoWebhooks(t *testing.T) {
+	_, err := AsyncAPI(parse(t, "openapi: 3.0.3\ninfo: {title: API, version: 1.0.0}\npaths: {}\n"))
+	if !errors.Is(err, ErrNoWebhooks) {
+		t.Errorf("AsyncAPI() error = %v, want %v", err, ErrNoWebhooks)
+	}
+}
@@ -0,0 +1,325 @@
+// Package codegen exports the models and operations of an OpenAPI document
+// as source files in other schema languages, for teams that maintain another
+// API surface or client next to the REST one, and as Go files annotated for
+// YaSwag, for services moving from a hand-written spec to annotations.
+package codegen
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/fathurrohman26/yaswag/internal/shared"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// maxRefDepth bounds the $ref chains followed when resolving a schema.
+const maxRefDepth = 32
+
+// resolve follows the $refs of s to the component schema they name,
+// returning nil if one is missing.
+func resolve(doc *openapi.Document, s *openapi.Schema) *openapi.Schema {
+	for i := 0; s != nil && s.Ref != "" && i < maxRefDepth; i++ {
+		if doc.Components == nil {
+			return nil
+		}
+		s = doc.Components.Schemas[shared.ComponentName(s.Ref)]
+	}
+	return s
+}
+
+// schemaNames returns the names of the component schemas of doc, sorted.
+func schemaNames(doc *openapi.Document) []string {
+	if doc.Components == nil {
+		return nil
+	}
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// propertyNames returns the property names of s in declaration order,
+// followed by any missing from s.PropertyOrder in sorted order.
+func propertyNames(s *openapi.Schema) []string {
+	names := make([]string, 0, len(s.Properties))
+	for _, name := range s.PropertyOrder {
+		if _, ok := s.Properties[name]; ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	var rest []string
+	for name := range s.Properties {
+		if !slices.Contains(names, name) {
+			rest = append(rest, name)
+		}
+	}
+	slices.Sort(rest)
+	return append(names, rest...)
+}
+
+// types returns the types of s other than null.
+func types(s *openapi.Schema) []string {
+	return slices.DeleteFunc(slices.Clone([]string(s.Type)), func(t string) bool { return t == openapi.TypeNull })
+}
+
+// typeFormat is the type and format of a schema, keying tables of the types
+// generated for primitive schemas; the empty format keys the default of a
+// type.
+type typeFormat struct{ typ, format string }
+
+// lookupType returns the entry of table for the type and format of a
+// schema, or else the default of its type, reporting false if the type has
+// neither.
+func lookupType(table map[typeFormat]string, typ, format string) (string, bool) {
+	if t, ok := table[typeFormat{typ, format}]; ok {
+		return t, true
+	}
+	t, ok := table[typeFormat{typ, ""}]
+	return t, ok
+}
+
+// nullable reports whether s allows null, in either the 3.0 or 3.1 form.
+func nullable(s *openapi.Schema) bool {
+	return s.Nullable || slices.Contains(s.Type, openapi.TypeNull)
+}
+
+// operation is an operation of a document with its method and path.
+type operation struct {
+	method, path string
+	op           *openapi.Operation
+	params       []*openapi.Parameter // path item and operation parameters, resolved
+}
+
+// operations returns the operations of doc in path order, each with the
+// parameters of its path item that it does not override.
+func operations(doc *openapi.Document) []operation {
+	var ops []operation
+	for _, o := range shared.Operations(doc, pathNames(doc)) {
+		ops = append(ops, operation{method: o.Method, path: o.Path, op: o.Operation, params: operationParams(doc, o.Item, o.Operation)})
+	}
+	return ops
+}
+
+// pathNames returns the paths of doc in declaration order, followed by any
+// missing from doc.PathOrder in sorted order.
+func pathNames(doc *openapi.Document) []string {
+	paths := slices.Clone(doc.PathOrder)
+	for path := range doc.Paths {
+		if !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths[len(doc.PathOrder):])
+	return paths
+}
+
+// operationParams returns the resolved parameters of op followed by those
+// of item that op does not override.
+func operationParams(doc *openapi.Document, item *openapi.PathItem, op *openapi.Operation) []*openapi.Parameter {
+	var params []*openapi.Parameter
+	for _, p := range append(slices.Clone(op.Parameters), item.Parameters...) {
+		p = resolveParameter(doc, p)
+		if p != nil && !slices.ContainsFunc(params, func(q *openapi.Parameter) bool { return q.Name == p.Name && q.In == p.In }) {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// name returns the operationId of the operation, or a name made of its
+// method and path, e.g. getPetsPetId for GET /pets/{petId}.
+func (o operation) name() string {
+	if o.op.OperationID != "" {
+		return o.op.OperationID
+	}
+	return strings.ToLower(o.method) + pascalCase(o.path)
+}
+
+// requestBody returns the schema of the JSON request body, if any.
+func (o operation) requestBody(doc *openapi.Document) (*openapi.Schema, bool) {
+	body := resolveRequestBody(doc, o.op.RequestBody)
+	if body == nil {
+		return nil, false
+	}
+	return contentSchema(body.Content), body.Required
+}
+
+// resolveRequestBody follows the $refs of body to the component request
+// body they name, returning nil if one is missing.
+func resolveRequestBody(doc *openapi.Document, body *openapi.RequestBody) *openapi.RequestBody {
+	for i := 0; body != nil && body.Ref != "" && i < maxRefDepth; i++ {
+		if doc.Components == nil {
+			return nil
+		}
+		body = doc.Components.RequestBodies[shared.ComponentName(body.Ref)]
+	}
+	return body
+}
+
+// successResponse returns the schema of the first success response, or of
+// the default response if there is none. It returns nil if the response has
+// no content.
+func (o operation) successResponse(doc *openapi.Document) *openapi.Schema {
+	statuses := make([]string, 0, len(o.op.Responses))
+	for status := range o.op.Responses {
+		statuses = append(statuses, status)
+	}
+	slices.Sort(statuses)
+	if i := slices.IndexFunc(statuses, func(s string) bool { return strings.HasPrefix(s, "2") }); i >= 0 {
+		statuses = statuses[i : i+1]
+	} else if slices.Contains(statuses, "default") {
+		statuses = []string{"default"}
+	} else {
+		return nil
+	}
+
+	r := o.op.Responses[statuses[0]]
+	for i := 0; r != nil && r.Ref != "" && i < maxRefDepth; i++ {
+		if doc.Components == nil {
+			return nil
+		}
+		r = doc.Components.Responses[shared.ComponentName(r.Ref)]
+	}
+	if r == nil {
+		return nil
+	}
+	return contentSchema(r.Content)
+}
+
+// contentSchema returns the schema of the media type of content picked by
+// contentMediaType. It returns nil if content is empty.
+func contentSchema(content map[string]openapi.MediaType) *openapi.Schema {
+	if len(content) == 0 {
+		return nil
+	}
+	return content[contentMediaType(content)].Schema
+}
+
+// contentMediaType returns the JSON media type of content, or else the
+// first +json one, or else the first one.
+func contentMediaType(content map[string]openapi.MediaType) string {
+	if _, ok := content["application/json"]; ok {
+		return "application/json"
+	}
+	mediaTypes := slices.Sorted(maps.Keys(content))
+	for _, mediaType := range mediaTypes {
+		if strings.HasSuffix(mediaType, "+json") {
+			return mediaType
+		}
+	}
+	return mediaTypes[0]
+}
+
+// parameterSchema returns the schema of the parameter, given either directly or as
+// content.
+func parameterSchema(p *openapi.Parameter) *openapi.Schema {
+	if p.Schema != nil {
+		return p.Schema
+	}
+	return contentSchema(p.Content)
+}
+
+// describedParameterSchema returns the schema of the parameter, a string if
+// it has none, carrying the description and deprecation of the parameter.
+func describedParameterSchema(p *openapi.Parameter) *openapi.Schema {
+	schema := parameterSchema(p)
+	if schema == nil {
+		schema = &openapi.Schema{Type: openapi.NewSchemaType(openapi.TypeString)}
+	}
+	if p.Description != "" || p.Deprecated {
+		copied := *schema
+		copied.Description = p.Description
+		copied.Deprecated = p.Deprecated
+		schema = &copied
+	}
+	return schema
+}
+
+// addProperty appends the property name to s.
+func addProperty(s *openapi.Schema, name string, schema *openapi.Schema, required bool) {
+	s.Properties[name] = schema
+	s.PropertyOrder = append(s.PropertyOrder, name)
+	if required {
+		s.Required = append(s.Required, name)
+	}
+}
+
+func resolveParameter(doc *openapi.Document, p *openapi.Parameter) *openapi.Parameter {
+	for i := 0; p != nil && p.Ref != "" && i < maxRefDepth; i++ {
+		if doc.Components == nil {
+			return nil
+		}
+		p = doc.Components.Parameters[shared.ComponentName(p.Ref)]
+	}
+	return p
+}
+
+// words splits an identifier into words at case changes and at any
+// character other than a letter or digit, e.g. "petID" and "pet-id" into
+// "pet" and "ID"/"id".
+func words(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := -1
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+			}
+			start = -1
+		case start < 0:
+			start = i
+		case wordBoundary(runes, i):
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// wordBoundary reports whether a word starts at the letter or digit
+// runes[i] that follows another: an upper case letter after a lower case
+// letter or digit, or the last upper case letter of an acronym followed by
+// a lower case one, as in the R of "HTTPRequest".
+func wordBoundary(runes []rune, i int) bool {
+	if !unicode.IsUpper(runes[i]) {
+		return false
+	}
+	prev := runes[i-1]
+	nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+	return unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower)
+}
+
+// pascalCase joins the words of s capitalized, e.g. PetId for "pet_id".
+// Names starting with a digit are prefixed with T.
+func pascalCase(s string) string {
+	var sb strings.Builder
+	for _, w := range words(s) {
+		runes := []rune(strings.ToLower(w))
+		runes[0] = unicode.ToUpper(runes[0])
+		sb.WriteString(string(runes))
+	}
+	name := sb.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "T" + name
+	}
+	return name
+}
+
+// snakeCase joins the words of s in lower case with underscores, e.g.
+// pet_id for "petId". Names starting with a digit are prefixed with f_.
+func snakeCase(s string) string {
+	name := strings.ToLower(strings.Join(words(s), "_"))
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "f_" + name
+	}
+	return name
+}
@@ -0,0 +1,42 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"gopkg.in/yaml.v3"
+)
+
+func parse(t *testing.T, spec string) *openapi.Document {
+	t.Helper()
+	var doc openapi.Document
+	if err := yaml.Unmarshal([]byte(spec), &doc); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+	return &doc
+}
+
+func TestNames(t *testing.T) {
+	tests := []struct {
+		in, pascal, snake string
+	}{
+		{in: "pet_id", pascal: "PetId", snake: "pet_id"},
+		{in: "petId", pascal: "PetId", snake: "pet_id"},
+		{in: "userID", pascal: "UserId", snake: "user_id"},
+		{in: "HTTPServer", pascal: "HttpServer", snake: "http_server"},
+		{in: "rate-limit", pascal: "RateLimit", snake: "rate_limit"},
+		{in: "/pets/{petId}", pascal: "PetsPetId", snake: "pets_pet_id"},
+		{in: "2fa", pascal: "T2fa", snake: "f_2fa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := pascalCase(tt.in); got != tt.pascal {
+				t.Errorf("pascalCase(%q) = %q, want %q", tt.in, got, tt.pascal)
+			}
+			if got := snakeCase(tt.in); got != tt.snake {
+				t.Errorf("snakeCase(%q) = %q, want %q", tt.in, got, tt.snake)
+			}
+		})
+	}
+}
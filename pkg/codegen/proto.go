@@ -0,0 +1,593 @@
+package codegen
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/fathurrohman26/yaswag/internal/shared"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// ProtoOptions configures Proto.
+type ProtoOptions struct {
+	// Package is the protobuf package, by default the title of the API in
+	// snake case followed by its major version, e.g. pet_store.v1.
+	Package string
+	// GoPackage sets the go_package file option when not empty.
+	GoPackage string
+	// Service names the service holding the operations, by default the
+	// title of the API followed by Service, e.g. PetStoreService.
+	Service string
+}
+
+// Well-known types used where a schema has no protobuf counterpart.
+const (
+	protoValue     = "google.protobuf.Value"
+	protoStruct    = "google.protobuf.Struct"
+	protoEmpty     = "google.protobuf.Empty"
+	protoTimestamp = "google.protobuf.Timestamp"
+)
+
+// protoImports maps the well-known types to the files declaring them.
+var protoImports = map[string]string{
+	protoValue:     "google/protobuf/struct.proto",
+	protoStruct:    "google/protobuf/struct.proto",
+	protoEmpty:     "google/protobuf/empty.proto",
+	protoTimestamp: "google/protobuf/timestamp.proto",
+}
+
+// fieldKind is how a type is used in a field, which decides its label.
+type fieldKind int
+
+const (
+	kindScalar fieldKind = iota
+	kindEnum
+	kindMessage
+	kindRepeated
+	kindMap
+)
+
+// Proto converts the component schemas of doc into proto3 messages and
+// enums, and its operations into the RPCs of one service.
+//
+// Objects become messages with fields numbered in declaration order, allOf
+// is merged into one message, and oneOf and anyOf become a oneof. String
+// enums become enums whose zero value is <NAME>_UNSPECIFIED. Arrays become
+// repeated fields and objects with only additionalProperties become maps.
+// Components that are not objects are wrapped in a message with a single
+// field, and schemas with no protobuf counterpart, such as mixed types, fall
+// back to google.protobuf.Value.
+//
+// Each operation becomes an RPC named after its operationId that takes a
+// request message with its parameters and body, and returns the schema of
+// its first success response, or google.protobuf.Empty if it has none.
+func Proto(doc *openapi.Document, opts ProtoOptions) []byte {
+	g := &protoGen{doc: doc, imports: map[string]bool{}, taken: map[string]bool{}}
+	for _, name := range schemaNames(doc) {
+		g.taken[pascalCase(name)] = true
+	}
+
+	var defs []string
+	for _, name := range schemaNames(doc) {
+		defs = append(defs, g.componentDef(name, doc.Components.Schemas[name]))
+	}
+	service := g.service(opts.Service)
+	defs = append(defs, g.rpcDefs...)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "// Code generated by yaswag from %s %s. DO NOT EDIT.\n\n", doc.Info.Title, doc.Info.Version)
+	sb.WriteString("syntax = \"proto3\";\n\n")
+	pkg := opts.Package
+	if pkg == "" {
+		pkg = protoPackage(doc.Info)
+	}
+	fmt.Fprintf(&sb, "package %s;\n\n", pkg)
+	if len(g.imports) > 0 {
+		imports := make([]string, 0, len(g.imports))
+		for file := range g.imports {
+			imports = append(imports, file)
+		}
+		slices.Sort(imports)
+		for _, file := range imports {
+			fmt.Fprintf(&sb, "import %q;\n", file)
+		}
+		sb.WriteString("\n")
+	}
+	if opts.GoPackage != "" {
+		fmt.Fprintf(&sb, "option go_package = %q;\n\n", opts.GoPackage)
+	}
+	for _, def := range defs {
+		sb.WriteString(def)
+		sb.WriteString("\n")
+	}
+	sb.WriteString(service)
+	return []byte(strings.TrimRight(sb.String(), "\n") + "\n")
+}
+
+// protoPackage derives a package name such as pet_store.v1 from info.
+func protoPackage(info openapi.Info) string {
+	pkg := strings.ToLower(strings.Join(words(info.Title), "_"))
+	if pkg == "" || !unicode.IsLetter([]rune(pkg)[0]) {
+		pkg = strings.TrimSuffix("api_"+pkg, "_")
+	}
+	major := strings.TrimPrefix(info.Version, "v")
+	if i := strings.IndexFunc(major, func(r rune) bool { return !unicode.IsDigit(r) }); i >= 0 {
+		major = major[:i]
+	}
+	if major != "" {
+		pkg += ".v" + major
+	}
+	return pkg
+}
+
+type protoGen struct {
+	doc     *openapi.Document
+	imports map[string]bool // files of the well-known types used
+	taken   map[string]bool // top-level message and enum names
+	rpcDefs []string        // request and response messages of the service
+}
+
+// use records the import of typ if it is a well-known type.
+func (g *protoGen) use(typ string) string {
+	if file, ok := protoImports[typ]; ok {
+		g.imports[file] = true
+	}
+	return typ
+}
+
+// componentDef renders the component schema s named name.
+func (g *protoGen) componentDef(name string, s *openapi.Schema) string {
+	typeName := pascalCase(name)
+	switch {
+	case isStringEnum(s):
+		return comment(s.Description, "") + enumDef(typeName, s.Enum, "")
+	case isMessage(s):
+		return g.messageDef(typeName, s, "")
+	}
+	return g.wrapperDef(typeName, s, "")
+}
+
+// wrapperDef renders a message with a single field holding s, named items
+// for arrays, values for maps, and value otherwise.
+func (g *protoGen) wrapperDef(name string, s *openapi.Schema, indent string) string {
+	var nested []string
+	taken := maps.Clone(g.taken)
+	typ, kind := g.fieldType(s, "value", &nested, taken, indent+"  ")
+	field := "value"
+	switch kind {
+	case kindRepeated:
+		typ, field = "repeated "+typ, "items"
+	case kindMap:
+		field = "values"
+	}
+
+	var sb strings.Builder
+	if s != nil {
+		sb.WriteString(comment(s.Description, indent))
+	}
+	fmt.Fprintf(&sb, "%smessage %s {\n", indent, name)
+	for _, def := range nested {
+		sb.WriteString(def)
+	}
+	fmt.Fprintf(&sb, "%s  %s %s = 1;\n", indent, typ, field)
+	fmt.Fprintf(&sb, "%s}\n", indent)
+	return sb.String()
+}
+
+// protoField is a field of a message being rendered.
+type protoField struct {
+	name, typ   string
+	kind        fieldKind
+	optional    bool
+	deprecated  bool
+	description string
+}
+
+// messageDef renders s as a message with its properties, those of its allOf
+// parts, and a oneof for its oneOf or anyOf variants.
+func (g *protoGen) messageDef(name string, s *openapi.Schema, indent string) string {
+	m := &protoMessage{taken: maps.Clone(g.taken), fieldNames: map[string]bool{}, indent: indent + "  "}
+	fields := g.propertyFields(m, s)
+	variants := g.variantFields(m, s)
+
+	var sb strings.Builder
+	sb.WriteString(comment(s.Description, indent))
+	fmt.Fprintf(&sb, "%smessage %s {\n", indent, name)
+	for _, def := range m.nested {
+		sb.WriteString(def)
+	}
+	number := 0
+	for _, f := range fields {
+		number++
+		sb.WriteString(f.render(number, indent+"  "))
+	}
+	if len(variants) > 0 {
+		fmt.Fprintf(&sb, "%s  oneof %s {\n", indent, uniqueName("value", m.fieldNames))
+		for _, f := range variants {
+			number++
+			sb.WriteString(f.render(number, indent+"    "))
+		}
+		fmt.Fprintf(&sb, "%s  }\n", indent)
+	}
+	fmt.Fprintf(&sb, "%s}\n", indent)
+	return sb.String()
+}
+
+// protoMessage is a message being rendered.
+type protoMessage struct {
+	nested     []string        // enums and messages declared for inline schemas
+	taken      map[string]bool // nested names must not shadow top-level ones
+	fieldNames map[string]bool
+	indent     string // of the fields
+}
+
+// propertyFields returns a field per property of s and its allOf parts.
+func (g *protoGen) propertyFields(m *protoMessage, s *openapi.Schema) []protoField {
+	var fields []protoField
+	props, required := g.collect(s, 0)
+	for _, p := range props {
+		typ, kind := g.fieldType(p.schema, p.name, &m.nested, m.taken, m.indent)
+		fields = append(fields, protoField{
+			name:        uniqueName(snakeCase(p.name), m.fieldNames),
+			typ:         typ,
+			kind:        kind,
+			optional:    !required[p.name],
+			deprecated:  p.schema != nil && p.schema.Deprecated,
+			description: description(p.schema),
+		})
+	}
+	return fields
+}
+
+// variantFields returns a field of the oneof per oneOf or anyOf variant of
+// s, named after the component or type it holds.
+func (g *protoGen) variantFields(m *protoMessage, s *openapi.Schema) []protoField {
+	var variants []protoField
+	for i, v := range slices.Concat(s.OneOf, s.AnyOf) {
+		typ, kind := g.fieldType(v, fmt.Sprintf("option%d", i+1), &m.nested, m.taken, m.indent)
+		if kind == kindRepeated || kind == kindMap {
+			// repeated and map fields are not allowed in a oneof
+			typ, kind = g.use(protoValue), kindMessage
+		}
+		field := strings.ToLower(typ) + "_value"
+		if v != nil && v.Ref != "" {
+			field = snakeCase(shared.ComponentName(v.Ref))
+		} else if kind == kindMessage || kind == kindEnum {
+			field = snakeCase(typ[strings.LastIndex(typ, ".")+1:])
+		}
+		variants = append(variants, protoField{name: uniqueName(field, m.fieldNames), typ: typ, kind: kind, description: description(v)})
+	}
+	return variants
+}
+
+func (f protoField) render(number int, indent string) string {
+	label := ""
+	switch {
+	case f.kind == kindRepeated:
+		label = "repeated "
+	case f.optional && (f.kind == kindScalar || f.kind == kindEnum):
+		label = "optional "
+	}
+	options := ""
+	if f.deprecated {
+		options = " [deprecated = true]"
+	}
+	return fmt.Sprintf("%s%s%s %s = %d%s;\n", comment(f.description, indent), indent, label+f.typ, f.name, number, options)
+}
+
+// property is a property of a message in declaration order.
+type property struct {
+	name   string
+	schema *openapi.Schema
+}
+
+// collect returns the properties of s and of its allOf parts, and the names
+// of the required ones. Properties declared again replace earlier ones in
+// place.
+func (g *protoGen) collect(s *openapi.Schema, depth int) ([]property, map[string]bool) {
+	var props []property
+	required := map[string]bool{}
+	if s == nil || depth > maxRefDepth {
+		return props, required
+	}
+	add := func(ps []property, req map[string]bool) {
+		for _, p := range ps {
+			if i := slices.IndexFunc(props, func(q property) bool { return q.name == p.name }); i >= 0 {
+				props[i] = p
+			} else {
+				props = append(props, p)
+			}
+		}
+		for name := range req {
+			required[name] = true
+		}
+	}
+	for _, part := range s.AllOf {
+		add(g.collect(resolve(g.doc, part), depth+1))
+	}
+	own := make([]property, 0, len(s.Properties))
+	for _, name := range propertyNames(s) {
+		own = append(own, property{name: name, schema: s.Properties[name]})
+	}
+	ownRequired := map[string]bool{}
+	for _, name := range s.Required {
+		ownRequired[name] = true
+	}
+	add(own, ownRequired)
+	return props, required
+}
+
+// protoScalars maps the types and formats of primitive schemas to protobuf
+// types.
+var protoScalars = map[typeFormat]string{
+	{openapi.TypeString, ""}:          "string",
+	{openapi.TypeString, "byte"}:      "bytes",
+	{openapi.TypeString, "binary"}:    "bytes",
+	{openapi.TypeString, "date-time"}: protoTimestamp,
+	{openapi.TypeInteger, ""}:         "int64",
+	{openapi.TypeInteger, "int32"}:    "int32",
+	{openapi.TypeInteger, "uint32"}:   "uint32",
+	{openapi.TypeInteger, "uint64"}:   "uint64",
+	{openapi.TypeNumber, ""}:          "double",
+	{openapi.TypeNumber, "float"}:     "float",
+	{openapi.TypeBoolean, ""}:         "bool",
+}
+
+// fieldType returns the type of a field holding s, rendering nested enums
+// and messages for inline schemas into nested, named after the field.
+func (g *protoGen) fieldType(s *openapi.Schema, name string, nested *[]string, taken map[string]bool, indent string) (string, fieldKind) {
+	switch {
+	case s == nil:
+		return g.use(protoValue), kindMessage
+	case s.Ref != "":
+		return g.refType(s)
+	case isWrappedRef(s):
+		return g.fieldType(s.AllOf[0], name, nested, taken, indent)
+	case isStringEnum(s):
+		typeName := uniqueName(pascalCase(name), taken)
+		*nested = append(*nested, enumDef(typeName, s.Enum, indent))
+		return typeName, kindEnum
+	case isMessage(s):
+		typeName := uniqueName(pascalCase(name), taken)
+		*nested = append(*nested, g.messageDef(typeName, s, indent))
+		return typeName, kindMessage
+	}
+	return g.typedField(s, name, nested, taken, indent)
+}
+
+// typedField returns the type of a field holding s by its type: a repeated
+// field for arrays, a map for objects with additionalProperties, and a
+// scalar or well-known type otherwise.
+func (g *protoGen) typedField(s *openapi.Schema, name string, nested *[]string, taken map[string]bool, indent string) (string, fieldKind) {
+	t := types(s)
+	switch {
+	case len(t) != 1:
+		return g.use(protoValue), kindMessage
+	case t[0] == openapi.TypeArray:
+		return g.elementType(s.Items, name+"Item", nested, taken, indent), kindRepeated
+	case t[0] == openapi.TypeObject && s.AdditionalProperties != nil:
+		return "map<string, " + g.elementType(s.AdditionalProperties, name+"Value", nested, taken, indent) + ">", kindMap
+	case t[0] == openapi.TypeObject:
+		return g.use(protoStruct), kindMessage
+	}
+	return g.scalarType(t[0], s.Format)
+}
+
+// refType returns the type of a field holding the component s refers to.
+func (g *protoGen) refType(s *openapi.Schema) (string, fieldKind) {
+	target := resolve(g.doc, s)
+	switch {
+	case target == nil:
+		return g.use(protoValue), kindMessage
+	case isStringEnum(target):
+		return pascalCase(shared.ComponentName(s.Ref)), kindEnum
+	}
+	return pascalCase(shared.ComponentName(s.Ref)), kindMessage
+}
+
+// elementType returns the type of the items of a repeated field or the
+// values of a map field, which cannot be repeated fields or maps themselves.
+func (g *protoGen) elementType(s *openapi.Schema, name string, nested *[]string, taken map[string]bool, indent string) string {
+	typ, kind := g.fieldType(s, name, nested, taken, indent)
+	if kind == kindRepeated || kind == kindMap {
+		return g.use(protoValue)
+	}
+	return typ
+}
+
+// scalarType returns the type of a field holding a primitive schema, which
+// is a message for the well-known types such as google.protobuf.Timestamp.
+func (g *protoGen) scalarType(typ, format string) (string, fieldKind) {
+	t, ok := lookupType(protoScalars, typ, format)
+	if !ok {
+		return g.use(protoValue), kindMessage
+	}
+	if _, wellKnown := protoImports[t]; wellKnown {
+		return g.use(t), kindMessage
+	}
+	return t, kindScalar
+}
+
+// isWrappedRef reports whether s only wraps a single allOf part, such as a
+// $ref wrapped to add a description or nullable.
+func isWrappedRef(s *openapi.Schema) bool {
+	return len(s.AllOf) == 1 && len(s.Properties) == 0 && len(s.OneOf) == 0 && len(s.AnyOf) == 0
+}
+
+// service renders the service with an RPC per operation, collecting the
+// request and response messages it declares in g.rpcDefs.
+func (g *protoGen) service(name string) string {
+	if name == "" {
+		name = "Api"
+		if g.doc.Info.Title != "" {
+			name = pascalCase(g.doc.Info.Title)
+		}
+		name += "Service"
+	}
+
+	ops := operations(g.doc)
+	if len(ops) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(comment(g.doc.Info.Description, ""))
+	fmt.Fprintf(&sb, "service %s {\n", name)
+	rpcNames := map[string]bool{}
+	for i, o := range ops {
+		rpc := uniqueName(pascalCase(o.name()), rpcNames)
+		request := g.request(rpc, o)
+		response := g.response(rpc, o)
+
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		summary := o.op.Summary
+		if summary == "" {
+			summary = o.op.Description
+		}
+		sb.WriteString(comment(summary, "  "))
+		fmt.Fprintf(&sb, "  // %s %s\n", o.method, o.path)
+		if o.op.Deprecated {
+			fmt.Fprintf(&sb, "  rpc %s(%s) returns (%s) {\n    option deprecated = true;\n  }\n", rpc, request, response)
+		} else {
+			fmt.Fprintf(&sb, "  rpc %s(%s) returns (%s);\n", rpc, request, response)
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// request returns the request message of the operation, declaring one with
+// a field per parameter and one for the body unless it takes neither.
+func (g *protoGen) request(rpc string, o operation) string {
+	body, bodyRequired := o.requestBody(g.doc)
+	if len(o.params) == 0 && body == nil {
+		return g.use(protoEmpty)
+	}
+
+	s := &openapi.Schema{Type: openapi.NewSchemaType(openapi.TypeObject), Properties: map[string]*openapi.Schema{}}
+	for _, p := range o.params {
+		if _, ok := s.Properties[p.Name]; !ok {
+			addProperty(s, p.Name, describedParameterSchema(p), p.Required || p.In == openapi.ParameterInPath)
+		}
+	}
+	if body != nil {
+		field := "body"
+		if body.Ref != "" {
+			field = shared.ComponentName(body.Ref)
+		}
+		if _, ok := s.Properties[field]; ok {
+			field = "body"
+		}
+		addProperty(s, field, body, bodyRequired)
+	}
+
+	name := uniqueName(rpc+"Request", g.taken)
+	g.rpcDefs = append(g.rpcDefs, g.messageDef(name, s, ""))
+	return name
+}
+
+// response returns the message returned by the operation: the component of
+// its success response if that is a message, google.protobuf.Empty if it
+// has no content, and a message declared for it otherwise.
+func (g *protoGen) response(rpc string, o operation) string {
+	s := o.successResponse(g.doc)
+	if s == nil {
+		return g.use(protoEmpty)
+	}
+	if s.Ref != "" {
+		if target := resolve(g.doc, s); target != nil && !isStringEnum(target) {
+			return pascalCase(shared.ComponentName(s.Ref))
+		}
+	}
+
+	name := uniqueName(rpc+"Response", g.taken)
+	if isMessage(s) {
+		g.rpcDefs = append(g.rpcDefs, g.messageDef(name, s, ""))
+	} else {
+		g.rpcDefs = append(g.rpcDefs, g.wrapperDef(name, s, ""))
+	}
+	return name
+}
+
+// enumDef renders an enum of the string values of values. Values are
+// prefixed with the enum name, as their names share the enclosing scope.
+func enumDef(name string, values []any, indent string) string {
+	prefix := strings.ToUpper(strings.Join(words(name), "_"))
+	taken := map[string]bool{prefix + "_UNSPECIFIED": true}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%senum %s {\n", indent, name)
+	fmt.Fprintf(&sb, "%s  %s_UNSPECIFIED = 0;\n", indent, prefix)
+	number := 0
+	for _, v := range values {
+		value, ok := v.(string)
+		if !ok {
+			continue
+		}
+		number++
+		valueName := strings.ToUpper(strings.Join(words(value), "_"))
+		if valueName == "" {
+			valueName = fmt.Sprintf("VALUE_%d", number)
+		}
+		fmt.Fprintf(&sb, "%s  %s = %d;\n", indent, uniqueName(prefix+"_"+valueName, taken), number)
+	}
+	fmt.Fprintf(&sb, "%s}\n", indent)
+	return sb.String()
+}
+
+// isStringEnum reports whether s is an enumeration of strings.
+func isStringEnum(s *openapi.Schema) bool {
+	if len(s.Enum) == 0 {
+		return false
+	}
+	if t := types(s); len(t) > 1 || (len(t) == 1 && t[0] != openapi.TypeString) {
+		return false
+	}
+	return slices.ContainsFunc(s.Enum, func(v any) bool { _, ok := v.(string); return ok })
+}
+
+// isMessage reports whether s declares properties or composes schemas, and
+// so becomes a message of its own.
+func isMessage(s *openapi.Schema) bool {
+	if len(s.Properties) > 0 || len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		return true
+	}
+	return len(s.AllOf) > 1
+}
+
+// description returns the description of s, if any.
+func description(s *openapi.Schema) string {
+	if s == nil {
+		return ""
+	}
+	return s.Description
+}
+
+// comment renders text as line comments at indent.
+func comment(text, indent string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	var sb strings.Builder
+	for line := range strings.SplitSeq(text, "\n") {
+		sb.WriteString(strings.TrimRight(indent+"// "+strings.TrimSpace(line), " "))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// uniqueName returns name, or name with the first free numeric suffix if
+// it is taken, and marks the result as taken.
+func uniqueName(name string, taken map[string]bool) string {
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	taken[unique] = true
+	return unique
+}
@@ -0,0 +1,216 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+)
+
+const petSpec = `openapi: 3.0.3
+info:
+  title: Pet Store
+  version: 1.2.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets
+      parameters:
+        - name: limit
+          in: query
+          schema: {type: integer, format: int32}
+      responses:
+        "200":
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: '#/components/schemas/Pet'}
+    post:
+      operationId: addPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+  /pets/{petId}:
+    delete:
+      deprecated: true
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema: {type: integer}
+      responses:
+        "204":
+          description: Deleted
+components:
+  schemas:
+    Pet:
+      type: object
+      description: A pet for sale.
+      required: [id, name]
+      properties:
+        id: {type: integer, format: int64}
+        name: {type: string}
+        status: {$ref: '#/components/schemas/Status'}
+        tags:
+          type: array
+          items: {type: string}
+        born: {type: string, format: date-time}
+        attributes:
+          type: object
+          additionalProperties: {type: string}
+        owner:
+          type: object
+          properties:
+            userID: {type: string}
+    Status:
+      type: string
+      enum: [available, pending, sold]
+    Dog:
+      allOf:
+        - $ref: '#/components/schemas/Pet'
+        - type: object
+          properties:
+            barks: {type: boolean}
+    Animal:
+      oneOf:
+        - $ref: '#/components/schemas/Dog'
+        - type: string
+    Names:
+      type: array
+      items: {type: string}
+`
+
+func TestProto(t *testing.T) {
+	want := `// Code generated by yaswag from Pet Store 1.2.0. DO NOT EDIT.
+
+syntax = "proto3";
+
+package pet_store.v1;
+
+import "google/protobuf/empty.proto";
+import "google/protobuf/timestamp.proto";
+
+option go_package = "example.com/petstore";
+
+message Animal {
+  oneof value {
+    Dog dog = 1;
+    string string_value = 2;
+  }
+}
+
+message Dog {
+  message Owner {
+    optional string user_id = 1;
+  }
+  int64 id = 1;
+  string name = 2;
+  optional Status status = 3;
+  repeated string tags = 4;
+  google.protobuf.Timestamp born = 5;
+  map<string, string> attributes = 6;
+  Owner owner = 7;
+  optional bool barks = 8;
+}
+
+message Names {
+  repeated string items = 1;
+}
+
+// A pet for sale.
+message Pet {
+  message Owner {
+    optional string user_id = 1;
+  }
+  int64 id = 1;
+  string name = 2;
+  optional Status status = 3;
+  repeated string tags = 4;
+  google.protobuf.Timestamp born = 5;
+  map<string, string> attributes = 6;
+  Owner owner = 7;
+}
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_AVAILABLE = 1;
+  STATUS_PENDING = 2;
+  STATUS_SOLD = 3;
+}
+
+message ListPetsRequest {
+  optional int32 limit = 1;
+}
+
+message ListPetsResponse {
+  repeated Pet items = 1;
+}
+
+message AddPetRequest {
+  Pet pet = 1;
+}
+
+message DeletePetsPetIdRequest {
+  int64 pet_id = 1;
+}
+
+service PetStoreService {
+  // List pets
+  // GET /pets
+  rpc ListPets(ListPetsRequest) returns (ListPetsResponse);
+
+  // POST /pets
+  rpc AddPet(AddPetRequest) returns (Pet);
+
+  // DELETE /pets/{petId}
+  rpc DeletePetsPetId(DeletePetsPetIdRequest) returns (google.protobuf.Empty) {
+    option deprecated = true;
+  }
+}
+`
+	got := string(Proto(parse(t, petSpec), ProtoOptions{GoPackage: "example.com/petstore"}))
+	if got != want {
+		t.Errorf("Proto() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestProto_Fallbacks(t *testing.T) {
+	spec := `openapi: 3.1.0
+info: {title: 2FA API, version: v3.0.0}
+paths:
+  /codes:
+    get:
+      responses:
+        "200":
+          description: Codes
+          content:
+            application/json:
+              schema: {type: object, additionalProperties: {type: array, items: {type: string}}}
+components:
+  schemas:
+    Mixed:
+      type: [string, integer, "null"]
+`
+	got := string(Proto(parse(t, spec), ProtoOptions{Service: "Codes"}))
+	for _, want := range []string{
+		"package api_2_fa_api.v3;",
+		`import "google/protobuf/struct.proto";`,
+		"message Mixed {\n  google.protobuf.Value value = 1;\n}",
+		"message GetCodesResponse {\n  map<string, google.protobuf.Value> values = 1;\n}",
+		"service Codes {",
+		"rpc GetCodes(google.protobuf.Empty) returns (GetCodesResponse);",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Proto() missing %q in\n%s", want, got)
+		}
+	}
+}
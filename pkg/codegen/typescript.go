@@ -0,0 +1,440 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/shared"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// TypeScript converts the component schemas of doc into TypeScript type
+// declarations, and its operations into request and response types, as
+// the contents of a .d.ts file.
+//
+// Objects become interfaces, with allOf of other interfaces as extends, and
+// everything else a type alias: enums become unions of their literal
+// values, oneOf and anyOf unions, arrays T[], and objects with only
+// additionalProperties Record<string, T>. Nullable schemas add | null.
+//
+// Each operation gets a <Name>Request interface grouping its path, query,
+// header, and cookie parameters and its body, unless it takes none, and a
+// <Name>Response type of its success response, or void if it has no
+// content. Name is the operationId in pascal case, or the method and path.
+func TypeScript(doc *openapi.Document) []byte {
+	g := &tsGen{doc: doc, taken: map[string]bool{}}
+	for _, name := range schemaNames(doc) {
+		g.taken[pascalCase(name)] = true
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "// Code generated by yaswag from %s %s. DO NOT EDIT.\n", doc.Info.Title, doc.Info.Version)
+	for _, name := range schemaNames(doc) {
+		sb.WriteString("\n")
+		sb.WriteString(g.componentDecl(name, doc.Components.Schemas[name]))
+	}
+	for _, o := range operations(doc) {
+		sb.WriteString("\n")
+		sb.WriteString(g.operationDecls(o))
+	}
+	return []byte(sb.String())
+}
+
+type tsGen struct {
+	doc   *openapi.Document
+	taken map[string]bool // declared type names
+}
+
+// componentDecl declares the component schema s named name.
+func (g *tsGen) componentDecl(name string, s *openapi.Schema) string {
+	typeName := pascalCase(name)
+	doc := jsDoc(s.Description, s.Deprecated, "")
+	if bases, own, ok := g.interfaceParts(s, 0); ok {
+		extends := ""
+		if len(bases) > 0 {
+			extends = " extends " + strings.Join(bases, ", ")
+		}
+		return fmt.Sprintf("%sexport interface %s%s %s\n", doc, typeName, extends, g.objectType(own, ""))
+	}
+	return fmt.Sprintf("%sexport type %s = %s;\n", doc, typeName, g.tsType(s, ""))
+}
+
+// interfaceParts reports whether s can be declared as an interface: an
+// object with properties, or an allOf of such objects and references to
+// interfaces. It returns the interfaces to extend and the object holding
+// the properties s declares itself.
+func (g *tsGen) interfaceParts(s *openapi.Schema, depth int) ([]string, *openapi.Schema, bool) {
+	if depth > maxRefDepth || !plainObject(s) {
+		return nil, nil, false
+	}
+
+	var bases []string
+	own := &openapi.Schema{Properties: map[string]*openapi.Schema{}}
+	for _, part := range s.AllOf {
+		if part.Ref != "" {
+			base, ok := g.interfaceBase(part, depth)
+			if !ok {
+				return nil, nil, false
+			}
+			bases = append(bases, base)
+			continue
+		}
+		if _, _, ok := g.interfaceParts(part, depth+1); !ok || len(part.AllOf) > 0 {
+			return nil, nil, false
+		}
+		mergeProperties(own, part)
+	}
+	mergeProperties(own, s)
+	if len(bases) == 0 && len(own.Properties) == 0 {
+		return nil, nil, false
+	}
+	return bases, own, true
+}
+
+// plainObject reports whether s is an object, typed or not, that is neither
+// nullable nor an enum, a union, or a map.
+func plainObject(s *openapi.Schema) bool {
+	if nullable(s) || len(s.Enum) > 0 || len(s.OneOf) > 0 || len(s.AnyOf) > 0 || s.AdditionalProperties != nil {
+		return false
+	}
+	t := types(s)
+	return len(t) == 0 || (len(t) == 1 && t[0] == openapi.TypeObject)
+}
+
+// interfaceBase returns the interface the allOf part referring to it
+// extends, reporting false if the component is not an interface.
+func (g *tsGen) interfaceBase(part *openapi.Schema, depth int) (string, bool) {
+	target := resolve(g.doc, part)
+	if target == nil {
+		return "", false
+	}
+	if _, _, ok := g.interfaceParts(target, depth+1); !ok {
+		return "", false
+	}
+	return pascalCase(shared.ComponentName(part.Ref)), true
+}
+
+// mergeProperties adds the properties of part to own, replacing those
+// declared before in place.
+func mergeProperties(own, part *openapi.Schema) {
+	for _, name := range propertyNames(part) {
+		if _, ok := own.Properties[name]; !ok {
+			own.PropertyOrder = append(own.PropertyOrder, name)
+		}
+		own.Properties[name] = part.Properties[name]
+	}
+	own.Required = append(own.Required, part.Required...)
+}
+
+// operationDecls declares the request and response types of o.
+func (g *tsGen) operationDecls(o operation) string {
+	name := pascalCase(o.name())
+	summary := o.op.Summary
+	if summary == "" {
+		summary = o.op.Description
+	}
+	doc := jsDoc(strings.TrimSpace(summary+"\n\n"+o.method+" "+o.path), o.op.Deprecated, "")
+
+	var sb strings.Builder
+	if request := g.requestType(o); len(request.Properties) > 0 {
+		fmt.Fprintf(&sb, "%sexport interface %s %s\n\n", doc, uniqueName(name+"Request", g.taken), g.objectType(request, ""))
+		doc = ""
+	}
+	response := "void"
+	if s := o.successResponse(g.doc); s != nil {
+		response = g.tsType(s, "")
+	}
+	fmt.Fprintf(&sb, "%sexport type %s = %s;\n", doc, uniqueName(name+"Response", g.taken), response)
+	return sb.String()
+}
+
+// requestType returns an object with the parameters of o grouped by
+// location, and its body.
+func (g *tsGen) requestType(o operation) *openapi.Schema {
+	request := &openapi.Schema{Properties: map[string]*openapi.Schema{}}
+	for _, in := range []openapi.ParameterLocation{openapi.ParameterInPath, openapi.ParameterInQuery, openapi.ParameterInHeader, openapi.ParameterInCookie} {
+		if group := parameterGroup(o.params, in); len(group.Properties) > 0 {
+			addProperty(request, string(in), group, len(group.Required) > 0)
+		}
+	}
+	if body, required := o.requestBody(g.doc); body != nil {
+		addProperty(request, "body", body, required)
+	}
+	return request
+}
+
+// parameterGroup returns an object with the parameters in the location in.
+func parameterGroup(params []*openapi.Parameter, in openapi.ParameterLocation) *openapi.Schema {
+	group := &openapi.Schema{Type: openapi.NewSchemaType(openapi.TypeObject), Properties: map[string]*openapi.Schema{}}
+	for _, p := range params {
+		if p.In == in {
+			addProperty(group, p.Name, describedParameterSchema(p), p.Required || in == openapi.ParameterInPath)
+		}
+	}
+	return group
+}
+
+// tsType returns the TypeScript type of s, with inline object types
+// indented from indent.
+func (g *tsGen) tsType(s *openapi.Schema, indent string) string {
+	if s == nil {
+		return "unknown"
+	}
+	typ := g.nonNullType(s, indent)
+	if nullable(s) && typ != "unknown" && !slices.Contains(strings.Split(typ, " | "), "null") {
+		typ = union([]string{typ, "null"})
+	}
+	return typ
+}
+
+// nonNullType returns the type of s, disregarding whether it is nullable.
+func (g *tsGen) nonNullType(s *openapi.Schema, indent string) string {
+	switch {
+	case s.Ref != "" && resolve(g.doc, s) == nil:
+		return "unknown"
+	case s.Ref != "":
+		return pascalCase(shared.ComponentName(s.Ref))
+	case len(s.Enum) > 0:
+		return enumType(s.Enum)
+	}
+
+	parts := g.compositionParts(s, indent)
+	if own := g.ownType(s, indent); own != "" {
+		parts = append(parts, own)
+	}
+	switch len(parts) {
+	case 0:
+		return "unknown"
+	case 1:
+		return parts[0]
+	}
+	return intersection(parts)
+}
+
+// enumType returns the union of the literals of values, or unknown if one
+// cannot be written as a literal.
+func enumType(values []any) string {
+	literals := make([]string, 0, len(values))
+	for _, v := range values {
+		literal, err := json.Marshal(v)
+		if err != nil {
+			return "unknown"
+		}
+		if !slices.Contains(literals, string(literal)) {
+			literals = append(literals, string(literal))
+		}
+	}
+	return union(literals)
+}
+
+// compositionParts returns the intersection of the allOf parts of s and the
+// union of its oneOf and anyOf variants, of those it has.
+func (g *tsGen) compositionParts(s *openapi.Schema, indent string) []string {
+	var parts []string
+	if len(s.AllOf) > 0 {
+		parts = append(parts, intersection(g.tsTypes(s.AllOf, indent)))
+	}
+	if variants := slices.Concat(s.OneOf, s.AnyOf); len(variants) > 0 {
+		parts = append(parts, union(g.tsTypes(variants, indent)))
+	}
+	return parts
+}
+
+// tsTypes returns the types of schemas.
+func (g *tsGen) tsTypes(schemas []*openapi.Schema, indent string) []string {
+	typs := make([]string, 0, len(schemas))
+	for _, s := range schemas {
+		typs = append(typs, g.tsType(s, indent))
+	}
+	return typs
+}
+
+// ownType returns the union of the types s declares, taking schemas with
+// only properties or additionalProperties as objects, or "" if it declares
+// none.
+func (g *tsGen) ownType(s *openapi.Schema, indent string) string {
+	t := types(s)
+	if len(t) == 0 && (len(s.Properties) > 0 || s.AdditionalProperties != nil) {
+		t = []string{openapi.TypeObject}
+	}
+	if len(t) == 0 {
+		return ""
+	}
+	own := make([]string, 0, len(t))
+	for _, typ := range t {
+		own = append(own, g.primitiveType(typ, s, indent))
+	}
+	return union(own)
+}
+
+// tsPrimitives maps the JSON types of scalars to TypeScript types.
+var tsPrimitives = map[string]string{
+	openapi.TypeString:  "string",
+	openapi.TypeInteger: "number",
+	openapi.TypeNumber:  "number",
+	openapi.TypeBoolean: "boolean",
+}
+
+// primitiveType returns the TypeScript type of s as the JSON type typ.
+func (g *tsGen) primitiveType(typ string, s *openapi.Schema, indent string) string {
+	if t, ok := tsPrimitives[typ]; ok {
+		return t
+	}
+	switch typ {
+	case openapi.TypeArray:
+		return g.arrayType(s, indent)
+	case openapi.TypeObject:
+		return g.recordType(s, indent)
+	}
+	return "unknown"
+}
+
+// arrayType returns the type of s as an array of its items.
+func (g *tsGen) arrayType(s *openapi.Schema, indent string) string {
+	if s.Items == nil {
+		return "unknown[]"
+	}
+	item := g.tsType(s.Items, indent)
+	if topLevel(item, "|&") {
+		item = "(" + item + ")"
+	}
+	return item + "[]"
+}
+
+// recordType returns the type of s as an object: an object type literal of
+// its properties, a Record of its additionalProperties, or both.
+func (g *tsGen) recordType(s *openapi.Schema, indent string) string {
+	var values string
+	if s.AdditionalProperties != nil {
+		values = "Record<string, " + g.tsType(s.AdditionalProperties, indent) + ">"
+	}
+	switch {
+	case len(s.Properties) == 0 && values == "":
+		return "Record<string, unknown>"
+	case len(s.Properties) == 0:
+		return values
+	case values == "":
+		return g.objectType(s, indent)
+	}
+	return intersection([]string{g.objectType(s, indent), values})
+}
+
+// objectType renders the properties of s as an object type literal.
+func (g *tsGen) objectType(s *openapi.Schema, indent string) string {
+	names := propertyNames(s)
+	if len(names) == 0 {
+		return "{}"
+	}
+	var sb strings.Builder
+	sb.WriteString("{\n")
+	for _, name := range names {
+		p := s.Properties[name]
+		optional := "?"
+		if slices.Contains(s.Required, name) {
+			optional = ""
+		}
+		if p != nil {
+			sb.WriteString(jsDoc(p.Description, p.Deprecated, indent+"  "))
+		}
+		fmt.Fprintf(&sb, "%s  %s%s: %s;\n", indent, propertyKey(name), optional, g.tsType(p, indent+"  "))
+	}
+	sb.WriteString(indent + "}")
+	return sb.String()
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// propertyKey returns name, quoted unless it is a valid identifier.
+func propertyKey(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	quoted, _ := json.Marshal(name)
+	return string(quoted)
+}
+
+// union joins types with |, dropping duplicates.
+func union(types []string) string {
+	return joinTypes(types, " | ")
+}
+
+// intersection joins types with &, parenthesizing unions.
+func intersection(types []string) string {
+	parenthesized := make([]string, 0, len(types))
+	for _, t := range types {
+		if topLevel(t, "|") {
+			t = "(" + t + ")"
+		}
+		parenthesized = append(parenthesized, t)
+	}
+	return joinTypes(parenthesized, " & ")
+}
+
+// topLevel reports whether t contains any of the operators in ops outside
+// brackets and string literals.
+func topLevel(t, ops string) bool {
+	depth := 0
+	quoted := false
+	for i := 0; i < len(t); i++ {
+		switch c := t[i]; {
+		case quoted:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				quoted = false
+			}
+		case c == '"':
+			quoted = true
+		case strings.IndexByte("{([<", c) >= 0:
+			depth++
+		case strings.IndexByte("})]>", c) >= 0:
+			depth--
+		case depth == 0 && strings.IndexByte(ops, c) >= 0:
+			return true
+		}
+	}
+	return false
+}
+
+func joinTypes(types []string, sep string) string {
+	seen := map[string]bool{}
+	var unique []string
+	for _, t := range types {
+		if !seen[t] {
+			seen[t] = true
+			unique = append(unique, t)
+		}
+	}
+	return strings.Join(unique, sep)
+}
+
+// jsDoc renders text, and a @deprecated tag if deprecated, as a JSDoc
+// comment at indent.
+func jsDoc(text string, deprecated bool, indent string) string {
+	var lines []string
+	if text = strings.TrimSpace(text); text != "" {
+		for line := range strings.SplitSeq(text, "\n") {
+			lines = append(lines, strings.TrimRight(line, " \t"))
+		}
+	}
+	if deprecated {
+		lines = append(lines, "@deprecated")
+	}
+	switch len(lines) {
+	case 0:
+		return ""
+	case 1:
+		return indent + "/** " + strings.ReplaceAll(lines[0], "*/", "*\\/") + " */\n"
+	}
+	var sb strings.Builder
+	sb.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		sb.WriteString(strings.TrimRight(indent+" * "+strings.ReplaceAll(line, "*/", "*\\/"), " "))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(indent + " */\n")
+	return sb.String()
+}
@@ -0,0 +1,120 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTypeScript(t *testing.T) {
+	want := `// Code generated by yaswag from Pet Store 1.2.0. DO NOT EDIT.
+
+export type Animal = Dog | string;
+
+export interface Dog extends Pet {
+  barks?: boolean;
+}
+
+export type Names = string[];
+
+/** A pet for sale. */
+export interface Pet {
+  id: number;
+  name: string;
+  status?: Status;
+  tags?: string[];
+  born?: string;
+  attributes?: Record<string, string>;
+  owner?: {
+    userID?: string;
+  };
+}
+
+export type Status = "available" | "pending" | "sold";
+
+/**
+ * List pets
+ *
+ * GET /pets
+ */
+export interface ListPetsRequest {
+  query?: {
+    limit?: number;
+  };
+}
+
+export type ListPetsResponse = Pet[];
+
+/** POST /pets */
+export interface AddPetRequest {
+  body: Pet;
+}
+
+export type AddPetResponse = Pet;
+
+/**
+ * DELETE /pets/{petId}
+ * @deprecated
+ */
+export interface DeletePetsPetIdRequest {
+  path: {
+    petId: number;
+  };
+}
+
+export type DeletePetsPetIdResponse = void;
+`
+	got := string(TypeScript(parse(t, petSpec)))
+	if got != want {
+		t.Errorf("TypeScript() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTypeScript_Types(t *testing.T) {
+	spec := `openapi: 3.1.0
+info: {title: Types, version: 1.0.0}
+paths:
+  /ping:
+    get:
+      operationId: ping
+      responses:
+        "204":
+          description: Pong
+components:
+  schemas:
+    Mixed:
+      type: [string, integer, "null"]
+    Items:
+      type: array
+      items:
+        oneOf:
+          - {type: string}
+          - {type: number}
+    Headers:
+      type: object
+      properties:
+        content-type: {type: string, deprecated: true}
+      additionalProperties: {type: string}
+    Level:
+      enum: [1, 2, null]
+    Named:
+      allOf:
+        - {$ref: '#/components/schemas/Missing'}
+        - {type: object, properties: {name: {type: string}}}
+`
+	got := string(TypeScript(parse(t, spec)))
+	for _, want := range []string{
+		"export type Mixed = string | number | null;",
+		"export type Items = (string | number)[];",
+		"export type Headers = {\n  /** @deprecated */\n  \"content-type\"?: string;\n} & Record<string, string>;",
+		"export type Level = 1 | 2 | null;",
+		"export type Named = unknown & {\n  name?: string;\n};",
+		"export type PingResponse = void;",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("TypeScript() missing %q in\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "PingRequest") {
+		t.Errorf("TypeScript() declared a request for an operation without inputs:\n%s", got)
+	}
+}
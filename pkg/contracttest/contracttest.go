@@ -0,0 +1,351 @@
+// Package contracttest checks HTTP traffic against an OpenAPI document in Go tests.
+//
+// A Contract wraps the client of an httptest server so that every request the
+// test sends and every response the server returns is validated against the
+// spec. Mismatches fail the test with the operation, the location in the
+// message, and what was expected:
+//
+//	func TestUsers(t *testing.T) {
+//	    srv := httptest.NewServer(newRouter())
+//	    defer srv.Close()
+//
+//	    client := contracttest.New(spec).Client(t, srv)
+//	    resp, err := client.Get(srv.URL + "/users/42")
+//	    ...
+//	}
+//
+//	// contract: GET /users/{id} (getUser): response body /email: expected string, got number
+package contracttest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/internal/shared"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"github.com/fathurrohman26/yaswag/pkg/validator"
+	"github.com/fathurrohman26/yaswag/pkg/yahttp"
+)
+
+// Contract validates requests and responses against an OpenAPI document.
+type Contract struct {
+	spec   *openapi.Document
+	routes []route
+
+	// BasePath is stripped from request paths before they are matched against
+	// the spec, for APIs mounted under a prefix such as "/api/v1"
+	BasePath string
+}
+
+// Mismatch describes one way an exchange deviates from the spec.
+type Mismatch struct {
+	// Operation is "METHOD /path/template (operationId)", or the raw method and path if no operation matched
+	Operation string
+
+	// Location is where the mismatch was found, e.g. "request query", "response body"
+	Location string
+
+	// Pointer is the JSON pointer of the offending value in a body, if any
+	Pointer string
+
+	Message string
+}
+
+func (m Mismatch) String() string {
+	location := m.Location
+	if m.Pointer != "" {
+		location += " " + m.Pointer
+	}
+	return fmt.Sprintf("%s: %s: %s", m.Operation, location, m.Message)
+}
+
+type route struct {
+	path   string
+	regex  *regexp.Regexp
+	params int
+	item   *openapi.PathItem
+}
+
+var (
+	templateParam       = regexp.MustCompile(`\{[^/}]+\}`)
+	quotedTemplateParam = regexp.MustCompile(`\\\{[^/}]+\\\}`) // templateParam after regexp.QuoteMeta
+)
+
+// New creates a contract for spec.
+func New(spec *openapi.Document) *Contract {
+	c := &Contract{spec: spec}
+	for path, item := range spec.Paths {
+		if item == nil {
+			continue
+		}
+		pattern := "^" + quotedTemplateParam.ReplaceAllString(regexp.QuoteMeta(path), "[^/]+") + "$"
+		c.routes = append(c.routes, route{
+			path:   path,
+			regex:  regexp.MustCompile(pattern),
+			params: len(templateParam.FindAllString(path, -1)),
+			item:   item,
+		})
+	}
+	// concrete paths before templated ones, so /users/me matches before /users/{id}
+	slices.SortFunc(c.routes, func(a, b route) int {
+		if a.params != b.params {
+			return a.params - b.params
+		}
+		return strings.Compare(a.path, b.path)
+	})
+	return c
+}
+
+// Client returns a client for srv that validates every exchange and reports
+// mismatches as test errors on t.
+func (c *Contract) Client(t testing.TB, srv *httptest.Server) *http.Client {
+	client := *srv.Client()
+	client.Transport = c.Transport(t, client.Transport)
+	return &client
+}
+
+// Transport wraps next (http.DefaultTransport if nil) so that every exchange
+// is validated and mismatches are reported as test errors on t.
+func (c *Contract) Transport(t testing.TB, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		reqBody, err := drain(&req.Body)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		respBody, err := drain(&resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range c.Check(req, reqBody, resp, respBody) {
+			t.Errorf("contract: %s", m)
+		}
+		return resp, nil
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// drain reads body and replaces it with a reader over the same bytes.
+func drain(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, err
+}
+
+// Check validates a request and the response it received, with their bodies
+// already read, and returns every mismatch found.
+func (c *Contract) Check(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte) []Mismatch {
+	path := strings.TrimPrefix(req.URL.Path, strings.TrimSuffix(c.BasePath, "/"))
+	rt, op := c.match(path, req.Method)
+	if op == nil {
+		name := req.Method + " " + path
+		message := "no operation in the spec matches the request"
+		if rt != nil {
+			message = fmt.Sprintf("method %s is not declared for %s", req.Method, rt.path)
+		}
+		return []Mismatch{{Operation: name, Location: "request", Message: message}}
+	}
+
+	name := req.Method + " " + rt.path
+	if op.OperationID != "" {
+		name += " (" + op.OperationID + ")"
+	}
+	e := &exchange{doc: c.spec, operation: name}
+	e.checkParameters(req, path)
+	e.checkRequestBody(op, req.Header.Get("Content-Type"), reqBody)
+	if resp != nil {
+		e.checkResponse(op, resp, respBody)
+	}
+	return e.mismatches
+}
+
+func (c *Contract) match(path, method string) (*route, *openapi.Operation) {
+	for i := range c.routes {
+		rt := &c.routes[i]
+		if rt.regex.MatchString(path) {
+			return rt, operationFor(rt.item, method)
+		}
+	}
+	return nil, nil
+}
+
+func operationFor(item *openapi.PathItem, method string) *openapi.Operation {
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		return item.Get
+	case http.MethodPut:
+		return item.Put
+	case http.MethodPost:
+		return item.Post
+	case http.MethodDelete:
+		return item.Delete
+	case http.MethodOptions:
+		return item.Options
+	case http.MethodHead:
+		return item.Head
+	case http.MethodPatch:
+		return item.Patch
+	case http.MethodTrace:
+		return item.Trace
+	default:
+		return nil
+	}
+}
+
+// exchange collects the mismatches of one request and response.
+type exchange struct {
+	doc        *openapi.Document
+	operation  string
+	mismatches []Mismatch
+}
+
+func (e *exchange) add(location, pointer, message string) {
+	e.mismatches = append(e.mismatches, Mismatch{Operation: e.operation, Location: location, Pointer: pointer, Message: message})
+}
+
+// checkParameters validates path, query, and header parameters with the
+// request validation of yahttp. The body is left to checkRequestBody, which
+// reports its mismatches with their location.
+func (e *exchange) checkParameters(req *http.Request, path string) {
+	r := req.Clone(req.Context())
+	r.URL.Path = path
+	r.Body = http.NoBody
+	for _, err := range yahttp.ValidateRequest(e.doc, r) {
+		if err.In == "body" {
+			continue
+		}
+		e.add("request "+err.In, "", fmt.Sprintf("parameter %s: %s", err.Field, err.Message))
+	}
+}
+
+func (e *exchange) checkRequestBody(op *openapi.Operation, contentType string, body []byte) {
+	rb := e.requestBody(op.RequestBody)
+	if rb == nil {
+		return
+	}
+	if len(body) == 0 {
+		if rb.Required {
+			e.add("request body", "", "body is required")
+		}
+		return
+	}
+	e.checkContent("request body", rb.Content, contentType, body, validator.DirectionRequest)
+}
+
+func (e *exchange) checkResponse(op *openapi.Operation, resp *http.Response, body []byte) {
+	code, response := e.response(op, resp.StatusCode)
+	if response == nil {
+		e.add("response status", "", fmt.Sprintf("status %d is not documented", resp.StatusCode))
+		return
+	}
+	location := fmt.Sprintf("response %s body", code)
+	if len(response.Content) == 0 {
+		if len(body) > 0 && resp.Request != nil && resp.Request.Method != http.MethodHead {
+			e.add(location, "", "body is not documented")
+		}
+		return
+	}
+	if len(body) == 0 {
+		return
+	}
+	e.checkContent(location, response.Content, resp.Header.Get("Content-Type"), body, validator.DirectionResponse)
+}
+
+// checkContent checks that the content type is declared and that JSON bodies match its schema.
+func (e *exchange) checkContent(location string, content map[string]openapi.MediaType, contentType string, body []byte, dir validator.Direction) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	mt, ok := lookupMediaType(content, mediaType)
+	if !ok {
+		e.add(location, "", fmt.Sprintf("content type %q is not declared (declared: %s)", contentType, strings.Join(sortedKeys(content), ", ")))
+		return
+	}
+	if !isJSON(mediaType) || mt.Schema == nil {
+		return
+	}
+	err := validator.ValidateSchema(e.doc, mt.Schema, body, dir)
+	var schemaErrs validator.SchemaErrors
+	switch {
+	case errors.As(err, &schemaErrs):
+		for _, err := range schemaErrs {
+			e.add(location, err.Pointer, err.Message)
+		}
+	case err != nil:
+		e.add(location, "", err.Error())
+	}
+}
+
+func (e *exchange) requestBody(rb *openapi.RequestBody) *openapi.RequestBody {
+	if rb != nil && rb.Ref != "" && e.doc.Components != nil {
+		return e.doc.Components.RequestBodies[shared.ComponentName(rb.Ref)]
+	}
+	return rb
+}
+
+// response returns the documented response for status, preferring an exact
+// code over a range ("2XX") over "default".
+func (e *exchange) response(op *openapi.Operation, status int) (string, *openapi.Response) {
+	code := strconv.Itoa(status)
+	for _, candidate := range []string{code, code[:1] + "XX", code[:1] + "xx", "default"} {
+		if r, ok := op.Responses[candidate]; ok && r != nil {
+			if r.Ref != "" && e.doc.Components != nil {
+				r = e.doc.Components.Responses[shared.ComponentName(r.Ref)]
+			}
+			return candidate, r
+		}
+	}
+	return "", nil
+}
+
+// lookupMediaType finds the declared media type for mediaType, honoring
+// wildcards such as "application/*" and "*/*".
+func lookupMediaType(content map[string]openapi.MediaType, mediaType string) (openapi.MediaType, bool) {
+	if mt, ok := content[mediaType]; ok {
+		return mt, true
+	}
+	major, _, _ := strings.Cut(mediaType, "/")
+	for _, candidate := range []string{major + "/*", "*/*"} {
+		if mt, ok := content[candidate]; ok {
+			return mt, true
+		}
+	}
+	return openapi.MediaType{}, false
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
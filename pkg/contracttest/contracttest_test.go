@@ -0,0 +1,178 @@
+package contracttest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+func createContractTestSpec() *openapi.Document {
+	minLength := int64(1)
+	return &openapi.Document{
+		OpenAPI: "3.0.3",
+		Info:    openapi.Info{Title: "Users", Version: "1.0.0"},
+		Paths: openapi.Paths{
+			"/users": {
+				Post: &openapi.Operation{
+					OperationID: "createUser",
+					RequestBody: &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{
+						"application/json": {Schema: openapi.RefTo("User")},
+					}},
+					Responses: openapi.Responses{
+						"201": {Description: "Created", Content: map[string]openapi.MediaType{
+							"application/json": {Schema: openapi.RefTo("User")},
+						}},
+						"4XX": {Description: "Client error"},
+					},
+				},
+			},
+			"/users/{id}": {
+				Get: &openapi.Operation{
+					OperationID: "getUser",
+					Parameters: []*openapi.Parameter{
+						{Name: "id", In: openapi.ParameterInPath, Required: true, Schema: openapi.IntegerSchema()},
+					},
+					Responses: openapi.Responses{
+						"200": {Description: "OK", Content: map[string]openapi.MediaType{
+							"application/json": {Schema: openapi.RefTo("User")},
+						}},
+					},
+				},
+			},
+		},
+		Components: &openapi.Components{Schemas: map[string]*openapi.Schema{
+			"User": {
+				Type:     openapi.NewSchemaType(openapi.TypeObject),
+				Required: []string{"id", "name"},
+				Properties: map[string]*openapi.Schema{
+					"id":       {Type: openapi.NewSchemaType(openapi.TypeInteger), ReadOnly: true},
+					"name":     {Type: openapi.NewSchemaType(openapi.TypeString), MinLength: &minLength},
+					"role":     {Type: openapi.NewSchemaType(openapi.TypeString), Enum: []any{"admin", "member"}},
+					"password": {Type: openapi.NewSchemaType(openapi.TypeString), WriteOnly: true},
+					"tags":     openapi.ArraySchema(openapi.StringSchema()),
+				},
+			},
+		}},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	contract := New(createContractTestSpec())
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		reqBody  string
+		status   int
+		respBody string
+		want     []string
+	}{
+		{
+			name: "valid exchange", method: "GET", target: "/users/7",
+			status: 200, respBody: `{"id": 7, "name": "Ada", "tags": ["x"]}`,
+		},
+		{
+			name: "response body mismatches", method: "GET", target: "/users/7",
+			status: 200, respBody: `{"id": "7", "name": "", "role": "owner", "password": "x", "tags": [1]}`,
+			want: []string{
+				"GET /users/{id} (getUser): response 200 body /id: expected integer, got string",
+				"response 200 body /name: string is shorter than minLength 1",
+				`response 200 body /role: value "owner" is not one of ["admin","member"]`,
+				"response 200 body /password: property is writeOnly and must not appear in a response",
+				"response 200 body /tags/0: expected string, got number",
+			},
+		},
+		{
+			name: "invalid path parameter and undocumented status", method: "GET", target: "/users/abc",
+			status: 404,
+			want:   []string{"request path: parameter id:", "response status: status 404 is not documented"},
+		},
+		{
+			name: "request body mismatches", method: "POST", target: "/users", reqBody: `{"id": 1}`,
+			status: 400,
+			want: []string{
+				"request body /: missing required property \"name\"",
+				"request body /id: property is readOnly and must not appear in a request",
+			},
+		},
+		{
+			name: "missing body", method: "POST", target: "/users",
+			status: 201, respBody: `{"id": 1, "name": "Ada"}`,
+			want: []string{"request body: body is required"},
+		},
+		{
+			name: "unknown operation", method: "DELETE", target: "/users/1",
+			status: 204,
+			want:   []string{"DELETE /users/1: request: method DELETE is not declared for /users/{id}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.reqBody != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{"Content-Type": {"application/json"}}, Request: req}
+
+			got := joinMismatches(contract.Check(req, []byte(tt.reqBody), resp, []byte(tt.respBody)))
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Check() missing %q in:\n%s", want, got)
+				}
+			}
+			if len(tt.want) == 0 && got != "" {
+				t.Errorf("Check() = %s, want no mismatches", got)
+			}
+		})
+	}
+}
+
+func joinMismatches(mismatches []Mismatch) string {
+	lines := make([]string, len(mismatches))
+	for i, m := range mismatches {
+		lines[i] = m.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// recorder captures test errors reported by the contract.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(`{"id": 1, "name": "Ada"}`))
+	}))
+	defer srv.Close()
+
+	contract := New(createContractTestSpec())
+	contract.BasePath = "/api"
+	rec := &recorder{TB: t}
+
+	resp, err := contract.Client(rec, srv).Get(srv.URL + "/api/users/1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], `content type "text/plain" is not declared`) {
+		t.Errorf("errors = %v, want one content type mismatch", rec.errors)
+	}
+	buf := make([]byte, 64)
+	if n, _ := resp.Body.Read(buf); !strings.Contains(string(buf[:n]), "Ada") {
+		t.Error("response body should still be readable after validation")
+	}
+}
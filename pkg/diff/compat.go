@@ -0,0 +1,66 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mode is a compatibility policy, as schema registries define them, applied
+// to an API: which side may upgrade first without the other failing.
+type Mode string
+
+const (
+	// ModeBackward holds when the new version serves existing clients: it
+	// accepts the requests they send and sends responses they understand.
+	ModeBackward Mode = "backward"
+	// ModeForward holds when new clients work against the previous version:
+	// it accepts the requests they send and they understand its responses.
+	ModeForward Mode = "forward"
+	// ModeFull holds when both backward and forward compatibility hold.
+	ModeFull Mode = "full"
+)
+
+// ParseMode parses a compatibility mode: backward, forward, or full.
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(strings.ToLower(s)); m {
+	case ModeBackward, ModeForward, ModeFull:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown compatibility mode %q (supported: backward, forward, full)", s)
+}
+
+// Violates reports whether the change violates mode.
+func (c Change) Violates(mode Mode) bool {
+	switch {
+	case c.Breaks == "":
+		return false
+	case mode == ModeFull, c.Breaks == ModeFull:
+		return true
+	}
+	return c.Breaks == mode
+}
+
+// Violations returns the changes that violate mode.
+func (r *Result) Violations(mode Mode) []Change {
+	var violations []Change
+	for _, c := range r.Changes {
+		if c.Violates(mode) {
+			violations = append(violations, c)
+		}
+	}
+	return violations
+}
+
+// effect is the version bump a change needs and the compatibility it breaks.
+type effect struct {
+	level  Level
+	breaks Mode
+}
+
+var (
+	breaking     = effect{LevelBreaking, ModeBackward}
+	breakingBoth = effect{LevelBreaking, ModeFull} // such as a changed type, which neither side can read
+	additive     = effect{LevelAdditive, ""}       // ignored by the side that does not know it
+	needsNew     = effect{LevelAdditive, ModeForward}
+	patch        = effect{LevelPatch, ""}
+)
@@ -0,0 +1,622 @@
+// Package diff compares two versions of an OpenAPI specification and
+// classifies each change by its effect on existing clients: breaking changes
+// need a major version bump, additive changes a minor one, and anything else
+// that changed a patch.
+//
+// Request and response schemas are compared by the direction data flows in:
+// a change that narrows what a schema accepts, such as a new required
+// property, breaks requests, while one that widens it, such as a new enum
+// value, breaks responses, whose clients do not expect the new values.
+//
+// Each change also records the compatibility mode it breaks: backward when
+// existing clients may fail against the new version, forward when new
+// clients may fail against the previous one, such as by calling a new
+// operation, or full when both may.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/shared"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// Level classifies a change by the version bump it needs.
+type Level string
+
+const (
+	LevelBreaking Level = "BREAKING" // existing clients may fail; needs a major bump
+	LevelAdditive Level = "ADDITIVE" // new capabilities; needs a minor bump
+	LevelPatch    Level = "PATCH"    // documentation and other changes; needs a patch bump
+)
+
+// Change is a single difference between two specifications.
+type Change struct {
+	Level    Level  `json:"level"`
+	Location string `json:"location"` // operation, then where in it, e.g. "POST /pets request body /name"
+	Message  string `json:"message"`
+	Breaks   Mode   `json:"breaks,omitempty"` // the compatibility it breaks: backward, forward, or full for both
+}
+
+// Result contains the changes from a base specification to a head one.
+type Result struct {
+	BaseVersion string   `json:"base_version"`
+	HeadVersion string   `json:"head_version"`
+	Changes     []Change `json:"changes"`
+}
+
+// Count returns the number of changes of the given level.
+func (r *Result) Count(level Level) int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Level == level {
+			n++
+		}
+	}
+	return n
+}
+
+// Bump returns the version bump the changes need.
+func (r *Result) Bump() Bump {
+	switch {
+	case r.Count(LevelBreaking) > 0:
+		return BumpMajor
+	case r.Count(LevelAdditive) > 0:
+		return BumpMinor
+	case len(r.Changes) > 0:
+		return BumpPatch
+	default:
+		return BumpNone
+	}
+}
+
+// direction is the way data flows through a schema.
+type direction int
+
+const (
+	request direction = iota
+	response
+)
+
+// maxRefDepth bounds the $ref chains followed when resolving a component.
+const maxRefDepth = 32
+
+// comparer accumulates the changes between two documents.
+type comparer struct {
+	base, head *openapi.Document
+	changes    []Change
+	compared   map[schemaPair]bool // schemas already compared, which bounds recursion
+}
+
+// schemaPair is a base and head schema compared in a direction.
+type schemaPair struct {
+	base, head *openapi.Schema
+	dir        direction
+}
+
+// Compare returns the changes from base to head. Differences outside the
+// operations, such as in unused components or info, count as a single patch
+// change, except for info.version, which is expected to change.
+func Compare(base, head *openapi.Document) *Result {
+	c := &comparer{base: base, head: head, changes: []Change{}, compared: make(map[schemaPair]bool)}
+	c.comparePaths()
+	if len(c.changes) == 0 && !equalIgnoringVersion(base, head) {
+		c.add(patch, "", "specification changed outside its operations")
+	}
+	return &Result{BaseVersion: base.Info.Version, HeadVersion: head.Info.Version, Changes: c.changes}
+}
+
+func (c *comparer) add(e effect, location, format string, args ...any) {
+	c.changes = append(c.changes, Change{Level: e.level, Location: location, Message: fmt.Sprintf(format, args...), Breaks: e.breaks})
+}
+
+// methods lists the operations of a path item with their HTTP methods.
+var methods = []struct {
+	name string
+	op   func(*openapi.PathItem) *openapi.Operation
+}{
+	{"GET", func(p *openapi.PathItem) *openapi.Operation { return p.Get }},
+	{"PUT", func(p *openapi.PathItem) *openapi.Operation { return p.Put }},
+	{"POST", func(p *openapi.PathItem) *openapi.Operation { return p.Post }},
+	{"DELETE", func(p *openapi.PathItem) *openapi.Operation { return p.Delete }},
+	{"OPTIONS", func(p *openapi.PathItem) *openapi.Operation { return p.Options }},
+	{"HEAD", func(p *openapi.PathItem) *openapi.Operation { return p.Head }},
+	{"PATCH", func(p *openapi.PathItem) *openapi.Operation { return p.Patch }},
+	{"TRACE", func(p *openapi.PathItem) *openapi.Operation { return p.Trace }},
+}
+
+func (c *comparer) comparePaths() {
+	for _, path := range unionKeys(c.base.Paths, c.head.Paths) {
+		baseItem, headItem := c.base.Paths[path], c.head.Paths[path]
+		for _, m := range methods {
+			var baseOp, headOp *openapi.Operation
+			if baseItem != nil {
+				baseOp = m.op(baseItem)
+			}
+			if headItem != nil {
+				headOp = m.op(headItem)
+			}
+			location := m.name + " " + path
+			switch {
+			case baseOp == nil && headOp == nil:
+			case headOp == nil:
+				c.add(breaking, location, "operation removed")
+			case baseOp == nil:
+				c.add(needsNew, location, "operation added")
+			default:
+				c.compareOperation(location, withPathParameters(baseItem, baseOp), withPathParameters(headItem, headOp))
+			}
+		}
+	}
+}
+
+// withPathParameters returns the parameters of op together with those of
+// its path item that it does not override.
+func withPathParameters(item *openapi.PathItem, op *openapi.Operation) *openapi.Operation {
+	if len(item.Parameters) == 0 {
+		return op
+	}
+	merged := *op
+	merged.Parameters = slices.Clone(op.Parameters)
+	for _, p := range item.Parameters {
+		if !slices.ContainsFunc(op.Parameters, func(q *openapi.Parameter) bool {
+			return q != nil && p != nil && q.Name == p.Name && q.In == p.In
+		}) {
+			merged.Parameters = append(merged.Parameters, p)
+		}
+	}
+	return &merged
+}
+
+func (c *comparer) compareOperation(location string, base, head *openapi.Operation) {
+	if !base.Deprecated && head.Deprecated {
+		c.add(additive, location, "operation deprecated")
+	}
+	if base.Summary != head.Summary || base.Description != head.Description {
+		c.add(patch, location, "summary or description changed")
+	}
+	if base.OperationID != head.OperationID {
+		// generated clients name their methods after operationIds
+		c.add(breakingBoth, location, "operationId changed from %q to %q", base.OperationID, head.OperationID)
+	}
+	c.compareParameters(location, base.Parameters, head.Parameters)
+	c.compareRequestBody(location, base.RequestBody, head.RequestBody)
+	c.compareResponses(location, base.Responses, head.Responses)
+}
+
+func (c *comparer) compareParameters(location string, base, head []*openapi.Parameter) {
+	baseParams, baseKeys := indexParameters(c.base, base)
+	headParams, headKeys := indexParameters(c.head, head)
+	for _, key := range baseKeys {
+		c.compareParameter(location+" parameter "+key, baseParams[key], headParams[key])
+	}
+	for _, key := range headKeys {
+		if baseParams[key] != nil {
+			continue
+		}
+		if headParams[key].Required {
+			c.add(breaking, location+" parameter "+key, "required parameter added")
+		} else {
+			c.add(additive, location+" parameter "+key, "optional parameter added")
+		}
+	}
+}
+
+// indexParameters resolves params and keys them by location and name, such
+// as "query limit", returning the keys in order.
+func indexParameters(doc *openapi.Document, params []*openapi.Parameter) (map[string]*openapi.Parameter, []string) {
+	byKey := make(map[string]*openapi.Parameter)
+	var keys []string
+	for _, p := range params {
+		if p = resolveParameter(doc, p); p != nil {
+			key := string(p.In) + " " + p.Name
+			byKey[key] = p
+			keys = append(keys, key)
+		}
+	}
+	return byKey, keys
+}
+
+// compareParameter compares the base parameter with the head one, which is
+// nil if it was removed.
+func (c *comparer) compareParameter(location string, base, head *openapi.Parameter) {
+	switch {
+	case head == nil:
+		c.add(breaking, location, "parameter removed")
+		return
+	case !base.Required && head.Required:
+		c.add(breaking, location, "parameter became required")
+	case base.Required && !head.Required:
+		c.add(needsNew, location, "parameter became optional")
+	}
+	c.compareSchema(location, base.Schema, head.Schema, request)
+	if base.Description != head.Description {
+		c.add(patch, location, "description changed")
+	}
+}
+
+func (c *comparer) compareRequestBody(location string, base, head *openapi.RequestBody) {
+	base, head = resolveRequestBody(c.base, base), resolveRequestBody(c.head, head)
+	loc := location + " request body"
+	switch {
+	case base == nil && head == nil:
+		return
+	case head == nil:
+		c.add(breaking, loc, "request body removed")
+		return
+	case base == nil:
+		if head.Required {
+			c.add(breaking, loc, "required request body added")
+		} else {
+			c.add(additive, loc, "optional request body added")
+		}
+		return
+	}
+	if !base.Required && head.Required {
+		c.add(breaking, loc, "request body became required")
+	}
+	if base.Description != head.Description {
+		c.add(patch, loc, "description changed")
+	}
+	c.compareContent(loc, base.Content, head.Content, request)
+}
+
+func (c *comparer) compareResponses(location string, base, head openapi.Responses) {
+	for _, status := range unionKeys(base, head) {
+		loc := location + " response " + status
+		b, h := resolveResponse(c.base, base[status]), resolveResponse(c.head, head[status])
+		switch {
+		case b == nil && h == nil:
+		case h == nil && isSuccess(status):
+			c.add(breaking, loc, "response removed")
+		case h == nil:
+			c.add(patch, loc, "response removed")
+		case b == nil:
+			c.add(additive, loc, "response added")
+		default:
+			if b.Description != h.Description {
+				c.add(patch, loc, "description changed")
+			}
+			c.compareContent(loc, b.Content, h.Content, response)
+		}
+	}
+}
+
+// isSuccess reports whether a response status is one clients rely on
+// receiving: a 2XX status or the default response.
+func isSuccess(status string) bool {
+	return strings.HasPrefix(status, "2") || status == "default"
+}
+
+func (c *comparer) compareContent(location string, base, head map[string]openapi.MediaType, dir direction) {
+	for _, mediaType := range unionKeys(base, head) {
+		loc := location + " " + mediaType
+		b, inBase := base[mediaType]
+		h, inHead := head[mediaType]
+		switch {
+		case !inHead:
+			c.add(breaking, loc, "media type removed")
+		case !inBase && dir == request:
+			c.add(needsNew, loc, "media type added")
+		case !inBase:
+			c.add(additive, loc, "media type added")
+		default:
+			c.compareSchema(loc, b.Schema, h.Schema, dir)
+		}
+	}
+}
+
+// compareSchema compares base and head facet by facet: type, description,
+// enum, bounds, required properties, properties, and subschemas.
+func (c *comparer) compareSchema(location string, base, head *openapi.Schema, dir direction) {
+	base, head = resolveSchema(c.base, base), resolveSchema(c.head, head)
+	if !c.comparable(location, base, head, dir) {
+		return
+	}
+	c.compareType(location, base, head, dir)
+	if base.Description != head.Description {
+		c.add(patch, location, "description changed")
+	}
+	c.compareEnum(location, base.Enum, head.Enum, dir)
+	c.compareBounds(location, base, head, dir)
+	c.compareRequired(location, base, head, dir)
+	c.compareProperties(location, base, head, dir)
+	c.compareSubschemas(location, base, head, dir)
+}
+
+// comparable reports whether base and head are both present and not yet
+// compared in dir. It records a schema added or removed as a change.
+func (c *comparer) comparable(location string, base, head *openapi.Schema, dir direction) bool {
+	switch {
+	case base == nil && head == nil:
+		return false
+	case base == nil:
+		c.add(c.level(dir, false), location, "schema added")
+		return false
+	case head == nil:
+		c.add(c.level(dir, true), location, "schema removed")
+		return false
+	}
+	pair := schemaPair{base, head, dir}
+	if c.compared[pair] {
+		return false
+	}
+	c.compared[pair] = true
+	return true
+}
+
+// compareType compares the type, format, and nullability of base and head.
+func (c *comparer) compareType(location string, base, head *openapi.Schema, dir direction) {
+	if baseType, headType := typeString(base), typeString(head); baseType != headType && baseType != "" {
+		c.add(breakingBoth, location, "type changed from %s to %s", baseType, orAny(headType))
+	}
+	if base.Format != "" && base.Format != head.Format {
+		c.add(breakingBoth, location, "format changed from %s to %s", base.Format, orAny(head.Format))
+	}
+	if !base.Nullable && head.Nullable {
+		c.add(c.level(dir, true), location, "schema became nullable")
+	} else if base.Nullable && !head.Nullable {
+		c.add(c.level(dir, false), location, "schema became non-nullable")
+	}
+}
+
+// compareSubschemas compares the items, additionalProperties, and allOf,
+// oneOf, and anyOf parts of base and head.
+func (c *comparer) compareSubschemas(location string, base, head *openapi.Schema, dir direction) {
+	if base.Items != nil || head.Items != nil {
+		c.compareSchema(location+"/items", base.Items, head.Items, dir)
+	}
+	if base.AdditionalProperties != nil && head.AdditionalProperties != nil {
+		c.compareSchema(location+"/additionalProperties", base.AdditionalProperties, head.AdditionalProperties, dir)
+	}
+	for _, composition := range []struct {
+		keyword    string
+		base, head []*openapi.Schema
+	}{
+		{"allOf", base.AllOf, head.AllOf},
+		{"oneOf", base.OneOf, head.OneOf},
+		{"anyOf", base.AnyOf, head.AnyOf},
+	} {
+		if len(composition.base) != len(composition.head) {
+			c.add(breakingBoth, location, "%s changed from %d to %d schemas", composition.keyword, len(composition.base), len(composition.head))
+			continue
+		}
+		for i := range composition.base {
+			c.compareSchema(fmt.Sprintf("%s/%s/%d", location, composition.keyword, i), composition.base[i], composition.head[i], dir)
+		}
+	}
+}
+
+// level classifies a change that widens what a schema accepts (widened) or
+// narrows it: widening breaks responses, whose clients do not expect the new
+// values, and narrowing breaks requests, whose clients may send the old ones.
+// The other way round, the change breaks forward compatibility instead.
+func (c *comparer) level(dir direction, widened bool) effect {
+	if widened == (dir == response) {
+		return breaking
+	}
+	return needsNew
+}
+
+func (c *comparer) compareEnum(location string, base, head []any, dir direction) {
+	if len(base) == 0 && len(head) == 0 {
+		return
+	}
+	contains := func(values []any, v any) bool {
+		return slices.ContainsFunc(values, func(w any) bool { return reflect.DeepEqual(v, w) })
+	}
+	for _, v := range base {
+		if len(head) > 0 && !contains(head, v) {
+			c.add(c.level(dir, false), location, "enum value %v removed", v)
+		}
+	}
+	for _, v := range head {
+		if len(base) == 0 {
+			c.add(c.level(dir, false), location, "enum restricted to %v", head)
+			break
+		}
+		if !contains(base, v) {
+			c.add(c.level(dir, true), location, "enum value %v added", v)
+		}
+	}
+}
+
+// compareBounds compares the numeric, length, item count, and property
+// count bounds of base and head. Raising a lower bound or lowering an upper
+// one narrows what the schema accepts.
+func (c *comparer) compareBounds(location string, base, head *openapi.Schema, dir direction) {
+	baseMin, baseMinExclusive := base.LowerBound()
+	headMin, headMinExclusive := head.LowerBound()
+	baseMax, baseMaxExclusive := base.UpperBound()
+	headMax, headMaxExclusive := head.UpperBound()
+	for _, b := range []struct {
+		keyword    string
+		base, head limit
+		upper      bool
+	}{
+		{"minimum", limit{baseMin, baseMinExclusive}, limit{headMin, headMinExclusive}, false},
+		{"maximum", limit{baseMax, baseMaxExclusive}, limit{headMax, headMaxExclusive}, true},
+		{"minLength", countLimit(base.MinLength), countLimit(head.MinLength), false},
+		{"maxLength", countLimit(base.MaxLength), countLimit(head.MaxLength), true},
+		{"minItems", countLimit(base.MinItems), countLimit(head.MinItems), false},
+		{"maxItems", countLimit(base.MaxItems), countLimit(head.MaxItems), true},
+		{"minProperties", countLimit(base.MinProperties), countLimit(head.MinProperties), false},
+		{"maxProperties", countLimit(base.MaxProperties), countLimit(head.MaxProperties), true},
+	} {
+		if b.base.String() == b.head.String() {
+			continue
+		}
+		c.add(c.level(dir, b.head.relaxes(b.base, b.upper)), location, "%s changed from %s to %s", b.keyword, b.base, b.head)
+	}
+}
+
+// limit is a bound of a schema, nil if it has none.
+type limit struct {
+	value     *float64
+	exclusive bool
+}
+
+func countLimit(n *int64) limit {
+	if n == nil {
+		return limit{}
+	}
+	v := float64(*n)
+	return limit{value: &v}
+}
+
+// relaxes reports whether l, replacing the bound other, lets through
+// values other does not: it is absent while other is not, lower as a lower
+// bound or higher as an upper one, or the same but inclusive while other is
+// exclusive.
+func (l limit) relaxes(other limit, upper bool) bool {
+	switch {
+	case other.value == nil:
+		return false
+	case l.value == nil:
+		return true
+	case *l.value != *other.value:
+		return (*l.value < *other.value) != upper
+	}
+	return !l.exclusive && other.exclusive
+}
+
+func (l limit) String() string {
+	switch {
+	case l.value == nil:
+		return "none"
+	case l.exclusive:
+		return fmt.Sprintf("%v (exclusive)", *l.value)
+	}
+	return fmt.Sprint(*l.value)
+}
+
+// compareRequired compares whether the properties base and head both
+// declare are required.
+func (c *comparer) compareRequired(location string, base, head *openapi.Schema, dir direction) {
+	for _, name := range unionKeys(base.Properties, head.Properties) {
+		if base.Properties[name] == nil || head.Properties[name] == nil {
+			continue
+		}
+		baseRequired, headRequired := slices.Contains(base.Required, name), slices.Contains(head.Required, name)
+		if !baseRequired && headRequired {
+			c.add(c.level(dir, false), location+"/"+name, "property became required")
+		} else if baseRequired && !headRequired {
+			c.add(c.level(dir, true), location+"/"+name, "property became optional")
+		}
+	}
+}
+
+// compareProperties compares the properties of base and head, and reports
+// those added and removed. Removing a property that was not deprecated in
+// base is flagged, as clients had no warning.
+func (c *comparer) compareProperties(location string, base, head *openapi.Schema, dir direction) {
+	for _, name := range unionKeys(base.Properties, head.Properties) {
+		loc := location + "/" + name
+		b, h := base.Properties[name], head.Properties[name]
+		switch {
+		case h == nil && c.deprecated(b):
+			c.add(breaking, loc, "deprecated property removed")
+		case h == nil:
+			c.add(breaking, loc, "property removed without being deprecated first")
+		case b == nil && slices.Contains(head.Required, name) && dir == request:
+			c.add(breaking, loc, "required property added")
+		case b == nil:
+			c.add(additive, loc, "property added")
+		default:
+			c.compareSchema(loc, b, h, dir)
+		}
+	}
+}
+
+// deprecated reports whether the base schema s, or the component it refers
+// to, is marked deprecated.
+func (c *comparer) deprecated(s *openapi.Schema) bool {
+	if s.Deprecated {
+		return true
+	}
+	resolved := resolveSchema(c.base, s)
+	return resolved != nil && resolved.Deprecated
+}
+
+// typeString returns the types of a schema as a sorted, comma-separated list.
+func typeString(s *openapi.Schema) string {
+	types := slices.Clone([]string(s.Type))
+	slices.Sort(types)
+	return strings.Join(types, ",")
+}
+
+func orAny(s string) string {
+	if s == "" {
+		return "any"
+	}
+	return s
+}
+
+// unionKeys returns the keys of a and b, sorted.
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// equalIgnoringVersion reports whether two documents are the same apart
+// from info.version.
+func equalIgnoringVersion(base, head *openapi.Document) bool {
+	b, h := *base, *head
+	b.Info.Version, h.Info.Version = "", ""
+	bData, bErr := json.Marshal(&b)
+	hData, hErr := json.Marshal(&h)
+	return bErr == nil && hErr == nil && string(bData) == string(hData)
+}
+
+func resolveSchema(doc *openapi.Document, s *openapi.Schema) *openapi.Schema {
+	for i := 0; s != nil && s.Ref != "" && i < maxRefDepth; i++ {
+		if doc.Components == nil {
+			return nil
+		}
+		s = doc.Components.Schemas[shared.ComponentName(s.Ref)]
+	}
+	return s
+}
+
+func resolveParameter(doc *openapi.Document, p *openapi.Parameter) *openapi.Parameter {
+	for i := 0; p != nil && p.Ref != "" && i < maxRefDepth; i++ {
+		if doc.Components == nil {
+			return nil
+		}
+		p = doc.Components.Parameters[shared.ComponentName(p.Ref)]
+	}
+	return p
+}
+
+func resolveRequestBody(doc *openapi.Document, b *openapi.RequestBody) *openapi.RequestBody {
+	for i := 0; b != nil && b.Ref != "" && i < maxRefDepth; i++ {
+		if doc.Components == nil {
+			return nil
+		}
+		b = doc.Components.RequestBodies[shared.ComponentName(b.Ref)]
+	}
+	return b
+}
+
+func resolveResponse(doc *openapi.Document, r *openapi.Response) *openapi.Response {
+	for i := 0; r != nil && r.Ref != "" && i < maxRefDepth; i++ {
+		if doc.Components == nil {
+			return nil
+		}
+		r = doc.Components.Responses[shared.ComponentName(r.Ref)]
+	}
+	return r
+}
@@ -0,0 +1,370 @@
+package diff
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"gopkg.in/yaml.v3"
+)
+
+const baseSpec = `openapi: 3.0.3
+info:
+  title: Pet Store
+  version: 1.2.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      operationId: addPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        "201":
+          description: Created
+  /pets/{id}:
+    delete:
+      operationId: deletePet
+      responses:
+        "204":
+          description: Deleted
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        status:
+          type: string
+          enum: [available, sold]
+        tag:
+          type: string
+`
+
+func parse(t *testing.T, spec string) *openapi.Document {
+	t.Helper()
+	var doc openapi.Document
+	if err := yaml.Unmarshal([]byte(spec), &doc); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+	return &doc
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string // replaced in baseSpec to make the head spec
+		want     []string
+		bump     Bump
+	}{
+		{
+			name: "unchanged",
+			bump: BumpNone,
+		},
+		{
+			name: "operation removed",
+			old:  "    delete:\n      operationId: deletePet\n      responses:\n        \"204\":\n          description: Deleted\n",
+			new:  "    get:\n      operationId: getPet\n      responses:\n        \"200\":\n          description: Pet\n",
+			want: []string{
+				"[ADDITIVE] GET /pets/{id}: operation added",
+				"[BREAKING] DELETE /pets/{id}: operation removed",
+			},
+			bump: BumpMajor,
+		},
+		{
+			name: "required parameter",
+			old:  "        - name: limit\n          in: query\n",
+			new:  "        - name: limit\n          in: query\n          required: true\n",
+			want: []string{"[BREAKING] GET /pets parameter query limit: parameter became required"},
+			bump: BumpMajor,
+		},
+		{
+			name: "optional property added",
+			old:  "        tag:\n          type: string\n",
+			new:  "        tag:\n          type: string\n        age:\n          type: integer\n",
+			want: []string{
+				"[ADDITIVE] GET /pets response 200 application/json/items/age: property added",
+				"[ADDITIVE] POST /pets request body application/json/age: property added",
+			},
+			bump: BumpMinor,
+		},
+		{
+			name: "required property added",
+			old:  "required: [name]",
+			new:  "required: [name, tag]",
+			want: []string{
+				"[ADDITIVE] GET /pets response 200 application/json/items/tag: property became required",
+				"[BREAKING] POST /pets request body application/json/tag: property became required",
+			},
+			bump: BumpMajor,
+		},
+		{
+			name: "property removed",
+			old:  "        tag:\n          type: string\n",
+			new:  "",
+			want: []string{
+				"[BREAKING] GET /pets response 200 application/json/items/tag: property removed without being deprecated first",
+				"[BREAKING] POST /pets request body application/json/tag: property removed without being deprecated first",
+			},
+			bump: BumpMajor,
+		},
+		{
+			name: "enum value added",
+			old:  "enum: [available, sold]",
+			new:  "enum: [available, pending, sold]",
+			want: []string{
+				"[BREAKING] GET /pets response 200 application/json/items/status: enum value pending added",
+				"[ADDITIVE] POST /pets request body application/json/status: enum value pending added",
+			},
+			bump: BumpMajor,
+		},
+		{
+			name: "maximum length added",
+			old:  "        tag:\n          type: string\n",
+			new:  "        tag:\n          type: string\n          maxLength: 64\n",
+			want: []string{
+				"[ADDITIVE] GET /pets response 200 application/json/items/tag: maxLength changed from none to 64",
+				"[BREAKING] POST /pets request body application/json/tag: maxLength changed from none to 64",
+			},
+			bump: BumpMajor,
+		},
+		{
+			name: "exclusive minimum added",
+			old:  "          schema:\n            type: integer\n",
+			new:  "          schema:\n            type: integer\n            exclusiveMinimum: true\n            minimum: 0\n",
+			want: []string{"[BREAKING] GET /pets parameter query limit: minimum changed from none to 0 (exclusive)"},
+			bump: BumpMajor,
+		},
+		{
+			name: "type changed",
+			old:  "        tag:\n          type: string\n",
+			new:  "        tag:\n          type: integer\n",
+			want: []string{
+				"[BREAKING] GET /pets response 200 application/json/items/tag: type changed from string to integer",
+				"[BREAKING] POST /pets request body application/json/tag: type changed from string to integer",
+			},
+			bump: BumpMajor,
+		},
+		{
+			name: "description changed",
+			old:  "          description: Deleted",
+			new:  "          description: Pet deleted",
+			want: []string{"[PATCH] DELETE /pets/{id} response 204: description changed"},
+			bump: BumpPatch,
+		},
+		{
+			name: "outside operations",
+			old:  "  title: Pet Store",
+			new:  "  title: Pet Shop",
+			want: []string{"[PATCH] specification changed outside its operations"},
+			bump: BumpPatch,
+		},
+	}
+
+	base := parse(t, baseSpec)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headSpec := strings.Replace(baseSpec, tt.old, tt.new, 1)
+			if headSpec == baseSpec && tt.old != "" {
+				t.Fatalf("%q not found in the base spec", tt.old)
+			}
+			result := Compare(base, parse(t, headSpec))
+
+			var got []string
+			for _, c := range result.Changes {
+				if c.Location == "" {
+					got = append(got, "["+string(c.Level)+"] "+c.Message)
+				} else {
+					got = append(got, "["+string(c.Level)+"] "+c.Location+": "+c.Message)
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("changes = %q, want %q", got, tt.want)
+			}
+			if b := result.Bump(); b != tt.bump {
+				t.Errorf("Bump() = %s, want %s", b, tt.bump)
+			}
+		})
+	}
+}
+
+func TestCompare_RecursiveSchema(t *testing.T) {
+	spec := `openapi: 3.0.3
+info: {title: Tree, version: 1.0.0}
+paths:
+  /nodes:
+    get:
+      responses:
+        "200":
+          description: Root
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Node'}
+components:
+  schemas:
+    Node:
+      type: object
+      properties:
+        children:
+          type: array
+          items: {$ref: '#/components/schemas/Node'}
+`
+	result := Compare(parse(t, spec), parse(t, strings.Replace(spec, "type: object", "type: object\n      description: A node", 1)))
+	if len(result.Changes) != 1 || result.Changes[0].Level != LevelPatch {
+		t.Errorf("changes = %v, want one patch change", result.Changes)
+	}
+}
+
+func TestCompare_DeprecatedPropertyRemoved(t *testing.T) {
+	tag := "        tag:\n          type: string\n"
+	base := parse(t, strings.Replace(baseSpec, tag, tag+"          deprecated: true\n", 1))
+	result := Compare(base, parse(t, strings.Replace(baseSpec, tag, "", 1)))
+
+	var got []string
+	for _, c := range result.Changes {
+		got = append(got, c.Location+": "+c.Message)
+	}
+	want := []string{
+		"GET /pets response 200 application/json/items/tag: deprecated property removed",
+		"POST /pets request body application/json/tag: deprecated property removed",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("changes = %q, want %q", got, want)
+	}
+}
+
+func TestResult_Violations(t *testing.T) {
+	head := strings.NewReplacer(
+		"enum: [available, sold]", "enum: [available, sold, pending]",
+		"operationId: deletePet", "operationId: removePet",
+		"description: Deleted", "description: Removed",
+	).Replace(baseSpec)
+	result := Compare(parse(t, baseSpec), parse(t, head))
+
+	tests := []struct {
+		mode Mode
+		want []string
+	}{
+		{ModeBackward, []string{
+			"GET /pets response 200 application/json/items/status: enum value pending added",
+			"DELETE /pets/{id}: operationId changed from \"deletePet\" to \"removePet\"",
+		}},
+		{ModeForward, []string{
+			"POST /pets request body application/json/status: enum value pending added",
+			"DELETE /pets/{id}: operationId changed from \"deletePet\" to \"removePet\"",
+		}},
+		{ModeFull, []string{
+			"GET /pets response 200 application/json/items/status: enum value pending added",
+			"POST /pets request body application/json/status: enum value pending added",
+			"DELETE /pets/{id}: operationId changed from \"deletePet\" to \"removePet\"",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			var got []string
+			for _, c := range result.Violations(tt.mode) {
+				got = append(got, c.Location+": "+c.Message)
+			}
+			slices.Sort(got)
+			slices.Sort(tt.want)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Violations(%s) = %q, want %q", tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	if m, err := ParseMode("Forward"); err != nil || m != ModeForward {
+		t.Errorf("ParseMode(Forward) = %q, %v, want forward", m, err)
+	}
+	if _, err := ParseMode("transitive"); err == nil {
+		t.Error("ParseMode(transitive) should fail")
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		base, head string
+		level      Level
+		wantErr    string
+	}{
+		{name: "major for breaking", base: "1.2.0", head: "2.0.0", level: LevelBreaking},
+		{name: "minor for breaking", base: "1.2.0", head: "1.3.0", level: LevelBreaking, wantErr: "is a minor bump, but the changes need a major bump (e.g. 2.0.0)"},
+		{name: "minor for additive", base: "v1.2.3", head: "v1.3.0", level: LevelAdditive},
+		{name: "patch for additive", base: "1.2.3", head: "1.2.4", level: LevelAdditive, wantErr: "need a minor bump (e.g. 1.3.0)"},
+		{name: "unchanged for patch", base: "1.2.3", head: "1.2.3", level: LevelPatch, wantErr: "version is still 1.2.3, but the changes need a patch bump (e.g. 1.2.4)"},
+		{name: "0.x minor for breaking", base: "0.4.1", head: "0.5.0", level: LevelBreaking},
+		{name: "0.x patch for breaking", base: "0.4.1", head: "0.4.2", level: LevelBreaking, wantErr: "need a minor bump (e.g. 0.5.0)"},
+		{name: "lower", base: "1.2.0", head: "1.1.9", level: LevelPatch, wantErr: "version 1.1.9 is lower than 1.2.0"},
+		{name: "not semver", base: "1.2", head: "1.3.0", level: LevelPatch, wantErr: `base version: "1.2" is not a semantic version`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &Result{BaseVersion: tt.base, HeadVersion: tt.head, Changes: []Change{{Level: tt.level}}}
+			err := CheckVersion(result)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("CheckVersion() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CheckVersion() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatPRComment(t *testing.T) {
+	result := &Result{
+		BaseVersion: "1.2.0",
+		HeadVersion: "1.3.0",
+		Changes: []Change{
+			{Level: LevelPatch, Message: "specification changed outside its operations"},
+			{Level: LevelBreaking, Location: "DELETE /pets/{id}", Message: "operation removed"},
+			{Level: LevelAdditive, Location: "GET /pets/{id}", Message: "operation added"},
+		},
+	}
+
+	comment := FormatPRComment(result, PRCommentOptions{MaxFindings: 2, ArtifactURL: "https://ci.example.com/report"})
+
+	for _, want := range []string{
+		"### Semantic versioning: major bump needed, 1 breaking, 1 additive, 1 patch",
+		"Versions: 1.2.0 -> 1.3.0",
+		"<summary>Top 2 of 3 changes</summary>",
+		"| BREAKING | `DELETE /pets/{id}` | operation removed |\n| ADDITIVE | `GET /pets/{id}` | operation added |\n",
+		"[Full semantic versioning report](https://ci.example.com/report)",
+	} {
+		if !strings.Contains(comment, want) {
+			t.Errorf("FormatPRComment() = %q, missing %q", comment, want)
+		}
+	}
+	if strings.Contains(comment, "PATCH") {
+		t.Error("FormatPRComment() should list the breaking and additive changes first")
+	}
+}
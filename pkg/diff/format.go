@@ -0,0 +1,109 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/report"
+)
+
+// FormatText formats the changes and the version bump they need as
+// human-readable text
+func FormatText(result *Result) string {
+	var sb strings.Builder
+
+	sb.WriteString("Semantic Versioning Report\n")
+	sb.WriteString("==========================\n\n")
+
+	sb.WriteString("Summary\n")
+	sb.WriteString("-------\n")
+	sb.WriteString(fmt.Sprintf("Versions: %s -> %s\n", orNone(result.BaseVersion), orNone(result.HeadVersion)))
+	sb.WriteString(fmt.Sprintf("Changes: %d breaking, %d additive, %d patch\n",
+		result.Count(LevelBreaking), result.Count(LevelAdditive), result.Count(LevelPatch)))
+	sb.WriteString(fmt.Sprintf("Recommended bump: %s\n", result.Bump()))
+	sb.WriteString(fmt.Sprintf("Compatibility: %d breaking backward, %d breaking forward\n\n",
+		len(result.Violations(ModeBackward)), len(result.Violations(ModeForward))))
+
+	if len(result.Changes) == 0 {
+		sb.WriteString("Changes\n")
+		sb.WriteString("-------\n")
+		sb.WriteString("No changes found.\n")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("Changes (%d)\n", len(result.Changes)))
+	sb.WriteString("-------------\n\n")
+
+	for _, level := range []Level{LevelBreaking, LevelAdditive, LevelPatch} {
+		for _, c := range result.Changes {
+			if c.Level != level {
+				continue
+			}
+			if c.Location == "" {
+				sb.WriteString(fmt.Sprintf("[%s] %s%s\n", c.Level, c.Message, breaksNote(c.Breaks)))
+			} else {
+				sb.WriteString(fmt.Sprintf("[%s] %s: %s%s\n", c.Level, c.Location, c.Message, breaksNote(c.Breaks)))
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// breaksNote describes the compatibility a change breaks, if any.
+func breaksNote(mode Mode) string {
+	switch mode {
+	case ModeBackward, ModeForward:
+		return fmt.Sprintf(" (not %s compatible)", mode)
+	case ModeFull:
+		return " (neither backward nor forward compatible)"
+	}
+	return ""
+}
+
+// FormatJSON formats the changes and the version bump they need as JSON
+func FormatJSON(result *Result) ([]byte, error) {
+	return json.MarshalIndent(struct {
+		*Result
+		Bump Bump `json:"bump"`
+	}{result, result.Bump()}, "", "  ")
+}
+
+// PRCommentOptions controls the size of a pull request comment.
+type PRCommentOptions = report.PRCommentOptions
+
+// FormatPRComment formats the changes as Markdown sized for a GitHub or
+// GitLab pull request comment: the recommended bump and a collapsed table of
+// the changes, breaking ones first.
+func FormatPRComment(result *Result, opts PRCommentOptions) string {
+	comment := report.PRComment{
+		Heading: fmt.Sprintf("Semantic versioning: %s bump needed, %d breaking, %d additive, %d patch",
+			result.Bump(), result.Count(LevelBreaking), result.Count(LevelAdditive), result.Count(LevelPatch)),
+		Summary: fmt.Sprintf("Versions: %s -> %s", orNone(result.BaseVersion), orNone(result.HeadVersion)),
+		Empty:   "No changes found.",
+		Noun:    "changes",
+		Report:  "Full semantic versioning report",
+		Columns: []string{"Level", "Location", "Message"},
+	}
+	for _, level := range []Level{LevelBreaking, LevelAdditive, LevelPatch} {
+		for _, c := range result.Changes {
+			if c.Level != level {
+				continue
+			}
+			location := c.Location
+			if location != "" {
+				location = "`" + location + "`"
+			}
+			comment.Rows = append(comment.Rows, []string{string(c.Level), location, c.Message})
+		}
+	}
+	return comment.Format(opts)
+}
+
+func orNone(version string) string {
+	if version == "" {
+		return "(none)"
+	}
+	return version
+}
@@ -0,0 +1,127 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Bump is a semantic version increment.
+type Bump string
+
+const (
+	BumpNone  Bump = "none"
+	BumpPatch Bump = "patch"
+	BumpMinor Bump = "minor"
+	BumpMajor Bump = "major"
+)
+
+// bumpRank orders bumps from smallest to largest.
+var bumpRank = map[Bump]int{BumpNone: 0, BumpPatch: 1, BumpMinor: 2, BumpMajor: 3}
+
+// Version is a parsed semantic version.
+type Version struct {
+	Major, Minor, Patch int
+	Prerelease          string
+}
+
+var semverPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// ParseVersion parses a semantic version such as 1.4.0, v2.0.0-rc.1, or
+// 1.0.0+build.5. Build metadata is ignored.
+func ParseVersion(s string) (Version, error) {
+	m := semverPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Version{}, fmt.Errorf("%q is not a semantic version (MAJOR.MINOR.PATCH)", s)
+	}
+	var v Version
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	v.Patch, _ = strconv.Atoi(m[3])
+	v.Prerelease = m[4]
+	return v, nil
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// Next returns the version after v with the given bump applied.
+func (v Version) Next(bump Bump) Version {
+	switch bump {
+	case BumpMajor:
+		return Version{Major: v.Major + 1}
+	case BumpMinor:
+		return Version{Major: v.Major, Minor: v.Minor + 1}
+	case BumpPatch:
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	default:
+		return v
+	}
+}
+
+// bumpBetween returns the bump from base to head, or an error if head is
+// not later than base. Prereleases are ignored.
+func bumpBetween(base, head Version) (Bump, error) {
+	switch {
+	case head.Major != base.Major:
+		if head.Major < base.Major {
+			return "", fmt.Errorf("version %s is lower than %s", head, base)
+		}
+		return BumpMajor, nil
+	case head.Minor != base.Minor:
+		if head.Minor < base.Minor {
+			return "", fmt.Errorf("version %s is lower than %s", head, base)
+		}
+		return BumpMinor, nil
+	case head.Patch != base.Patch:
+		if head.Patch < base.Patch {
+			return "", fmt.Errorf("version %s is lower than %s", head, base)
+		}
+		return BumpPatch, nil
+	default:
+		return BumpNone, nil
+	}
+}
+
+// CheckVersion reports whether the version change from base to head is at
+// least the bump the changes in result need. Before 1.0.0 anything may
+// change, so a minor bump suffices for breaking changes and a patch bump
+// for additive ones, as is customary for 0.x releases.
+func CheckVersion(result *Result) error {
+	base, err := ParseVersion(result.BaseVersion)
+	if err != nil {
+		return fmt.Errorf("base version: %w", err)
+	}
+	head, err := ParseVersion(result.HeadVersion)
+	if err != nil {
+		return fmt.Errorf("head version: %w", err)
+	}
+	actual, err := bumpBetween(base, head)
+	if err != nil {
+		return err
+	}
+
+	required := result.Bump()
+	if base.Major == 0 {
+		switch required {
+		case BumpMajor:
+			required = BumpMinor
+		case BumpMinor:
+			required = BumpPatch
+		}
+	}
+	if actual == BumpNone && required != BumpNone {
+		return fmt.Errorf("version is still %s, but the changes need a %s bump (e.g. %s)", head, required, base.Next(required))
+	}
+	if bumpRank[actual] < bumpRank[required] {
+		return fmt.Errorf("version %s -> %s is a %s bump, but the changes need a %s bump (e.g. %s)",
+			base, head, actual, required, base.Next(required))
+	}
+	return nil
+}
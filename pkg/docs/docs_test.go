@@ -0,0 +1,195 @@
+package docs
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"github.com/fathurrohman26/yaswag/pkg/uiassets"
+)
+
+func createDocsTestDoc() *openapi.Document {
+	return &openapi.Document{
+		OpenAPI: "3.0.3",
+		Info:    openapi.Info{Title: "Pet Store", Version: "1.0.0", Description: "Manage pets."},
+		Servers: []openapi.Server{{URL: "https://api.example.com", Description: "Production"}},
+		Tags:    []openapi.Tag{{Name: "pets", Description: "Pet operations"}},
+		Paths: openapi.Paths{
+			"/pets/{petId}": {
+				Parameters: []*openapi.Parameter{
+					{Name: "petId", In: openapi.ParameterInPath, Required: true, Schema: openapi.IntegerSchema()},
+				},
+				Get: &openapi.Operation{
+					OperationID: "getPet",
+					Summary:     "Get a pet",
+					Tags:        []string{"pets"},
+					Parameters: []*openapi.Parameter{
+						{Name: "fields", In: openapi.ParameterInQuery, Description: "Fields to return | comma separated", Schema: openapi.StringSchema()},
+					},
+					Responses: openapi.Responses{
+						"200": {Description: "OK", Content: map[string]openapi.MediaType{
+							"application/json": {Schema: openapi.RefTo("Pet")},
+						}},
+						"404": {Description: "Not found"},
+					},
+				},
+			},
+			"/orders": {
+				Post: &openapi.Operation{
+					OperationID: "createOrder",
+					Tags:        []string{"store"},
+					Security: []openapi.SecurityRequirement{
+						{"client_cert": {}},
+						{"store_auth": {"write:orders"}},
+					},
+					RequestBody: &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{
+						"application/json": {Example: map[string]any{"petId": 7}},
+					}},
+					Responses: openapi.Responses{"201": {Description: "Created", Links: map[string]*openapi.Link{
+						"getPet": {OperationID: "getPet", Parameters: map[string]any{"petId": "$request.body#/petId"}},
+					}}},
+				},
+			},
+			"/health": {
+				Get: &openapi.Operation{Summary: "<b>Health</b>", Responses: openapi.Responses{"200": {Description: "OK"}}},
+			},
+		},
+		Components: &openapi.Components{
+			SecuritySchemes: map[string]*openapi.SecurityScheme{
+				"client_cert": {Type: "mutualTLS", Description: "Partner certificate"},
+				"store_auth": {Type: "oauth2", Flows: &openapi.OAuthFlows{ClientCredentials: &openapi.OAuthFlow{
+					TokenURL: "https://auth.example.com/token",
+					Scopes:   map[string]string{"write:orders": "place orders"},
+				}}},
+			},
+			Schemas: map[string]*openapi.Schema{
+				"Pet": {
+					Description: "A pet",
+					Type:        openapi.NewSchemaType(openapi.TypeObject),
+					Required:    []string{"name"},
+					Properties: map[string]*openapi.Schema{
+						"name":   openapi.StringSchema(),
+						"parent": openapi.RefTo("Pet"),
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestBuildSite_TagOrder(t *testing.T) {
+	s := buildSite(createDocsTestDoc())
+
+	var names []string
+	for _, tag := range s.Tags {
+		names = append(names, tag.Name)
+	}
+	if want := []string{"pets", "store", "default"}; !slices.Equal(names, want) {
+		t.Errorf("tags = %v, want %v", names, want)
+	}
+
+	getPet := s.Tags[0].Operations[0]
+	if len(getPet.Parameters) != 2 || getPet.Parameters[0].Name != "petId" {
+		t.Errorf("parameters = %+v, want path-level petId first", getPet.Parameters)
+	}
+	if getPet.Responses[0].Type.Schema != "Pet" {
+		t.Errorf("response schema = %q, want Pet", getPet.Responses[0].Type.Schema)
+	}
+}
+
+func TestMarkdownFiles(t *testing.T) {
+	files := MarkdownFiles(createDocsTestDoc())
+
+	for _, name := range []string{"README.md", "pets.md", "store.md", "default.md", "schemas.md"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("missing file %s", name)
+		}
+	}
+
+	wants := map[string][]string{
+		"pets.md": {
+			"## `GET /pets/{petId}`",
+			`Fields to return \| comma separated`,
+			"[Pet](schemas.md#schema-pet)",
+			"```json",
+		},
+		"store.md": {
+			`"petId": 7`,
+			"| getPet | [`getPet`](pets.md#getpet) | petId=$request.body#/petId |",
+			"Security: [`client_cert`](README.md#security-client-cert) or [`store_auth`](README.md#security-store-auth) (write:orders)",
+		},
+		"README.md": {
+			"(pets.md#getpet)",
+			"## Security Schemes",
+			"Type: Mutual TLS",
+			"- Client credentials flow: token URL https://auth.example.com/token; scopes write:orders",
+		},
+	}
+	for name, want := range wants {
+		for _, w := range want {
+			if !strings.Contains(string(files[name]), w) {
+				t.Errorf("%s missing %q", name, w)
+			}
+		}
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "api")
+	if err := WriteMarkdown(createDocsTestDoc(), dir); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "schemas.md")); err != nil {
+		t.Errorf("schemas.md not written: %v", err)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	page, err := HTML(createDocsTestDoc())
+	if err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+	html := string(page)
+
+	for _, want := range []string{
+		"<title>Pet Store 1.0.0</title>",
+		`id="getpet"`,
+		`<a href="#schema-pet">Pet</a>`,
+		`<a href="#getpet"><code>getPet</code></a>`,
+		`id="security-client-cert"`,
+		`<a href="#security-client-cert"><code>client_cert</code></a> or <a href="#security-store-auth"><code>store_auth</code></a> (write:orders)`,
+		"&lt;b&gt;Health&lt;/b&gt;",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML() missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{"<script", "https://unpkg.com", "<b>Health"} {
+		if strings.Contains(html, unwanted) {
+			t.Errorf("HTML() should not contain %q", unwanted)
+		}
+	}
+}
+
+func TestHTMLWithBranding(t *testing.T) {
+	page, err := HTMLWithBranding(createDocsTestDoc(), &uiassets.Branding{
+		FaviconURL: "/favicon.ico",
+		FooterText: "Acme Corp",
+		Language:   "id",
+	})
+	if err != nil {
+		t.Fatalf("HTMLWithBranding() error = %v", err)
+	}
+	for _, want := range []string{`<html lang="id">`, `<link rel="icon" href="/favicon.ico">`, "Acme Corp"} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("HTMLWithBranding() missing %q", want)
+		}
+	}
+
+	if _, err := HTMLWithBranding(createDocsTestDoc(), &uiassets.Branding{PrimaryColor: "red;}"}); err == nil {
+		t.Error("HTMLWithBranding() should reject an invalid primary color")
+	}
+}
@@ -0,0 +1,44 @@
+package docs
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"github.com/fathurrohman26/yaswag/pkg/uiassets"
+)
+
+//go:embed templates/*.html
+var templates embed.FS
+
+var htmlTemplate = template.Must(template.Must(template.New("docs.html").Funcs(template.FuncMap{
+	"lower":        strings.ToLower,
+	"schemaAnchor": schemaAnchor,
+}).ParseFS(templates, "templates/docs.html")).Parse(uiassets.BrandingTemplates))
+
+// HTML renders the document as a single self-contained HTML page with inline
+// styles and no scripts or external assets.
+func HTML(doc *openapi.Document) ([]byte, error) {
+	return HTMLWithBranding(doc, nil)
+}
+
+// HTMLWithBranding renders the document like HTML, with the logo, favicon,
+// primary color, footer text, and navigation links of branding, which may be nil.
+// The logo and favicon are linked, not embedded.
+func HTMLWithBranding(doc *openapi.Document, branding *uiassets.Branding) ([]byte, error) {
+	if err := branding.Validate(); err != nil {
+		return nil, err
+	}
+	page := struct {
+		*site
+		Branding *uiassets.Branding
+	}{buildSite(doc), branding}
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, page); err != nil {
+		return nil, fmt.Errorf("failed to render HTML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,317 @@
+package docs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+const (
+	// MarkdownIndexFile is the name of the Markdown index page.
+	MarkdownIndexFile = "README.md"
+	// MarkdownSchemasFile is the name of the Markdown page listing component schemas.
+	MarkdownSchemasFile = "schemas.md"
+)
+
+// MarkdownFiles renders the document as Markdown pages keyed by file name: an
+// index page, one page per tag, and a page listing component schemas.
+// Operations without tags are listed on the "default" page.
+func MarkdownFiles(doc *openapi.Document) map[string][]byte {
+	s := buildSite(doc)
+	files := map[string][]byte{
+		MarkdownIndexFile: []byte(markdownIndex(s)),
+	}
+	for _, tag := range s.Tags {
+		files[tag.Slug+".md"] = []byte(markdownTag(tag))
+	}
+	if len(s.Schemas) > 0 {
+		files[MarkdownSchemasFile] = []byte(markdownSchemas(s.Schemas))
+	}
+	return files
+}
+
+// WriteMarkdown renders the document with MarkdownFiles and writes the pages to dir,
+// creating it if needed.
+func WriteMarkdown(doc *openapi.Document, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	files := MarkdownFiles(doc)
+	for _, name := range sortedKeys(files) {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, files[name], 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func markdownIndex(s *site) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s\n\n", s.Title)
+	if s.Version != "" {
+		fmt.Fprintf(&sb, "Version: `%s`\n\n", s.Version)
+	}
+	if s.Description != "" {
+		sb.WriteString(s.Description + "\n\n")
+	}
+
+	markdownServers(&sb, s.Servers)
+	markdownSecuritySchemes(&sb, s.Security)
+	markdownContents(&sb, s)
+	return sb.String()
+}
+
+func markdownServers(sb *strings.Builder, servers []openapi.Server) {
+	if len(servers) == 0 {
+		return
+	}
+	sb.WriteString("## Servers\n\n")
+	for _, server := range servers {
+		if server.Description != "" {
+			fmt.Fprintf(sb, "- `%s` - %s\n", server.URL, server.Description)
+		} else {
+			fmt.Fprintf(sb, "- `%s`\n", server.URL)
+		}
+	}
+	sb.WriteString("\n")
+}
+
+func markdownSecuritySchemes(sb *strings.Builder, schemes []securitySection) {
+	if len(schemes) == 0 {
+		return
+	}
+	sb.WriteString("## Security Schemes\n\n")
+	for _, scheme := range schemes {
+		fmt.Fprintf(sb, "<a id=\"%s\"></a>\n\n", scheme.Anchor)
+		fmt.Fprintf(sb, "### `%s`\n\n", scheme.Name)
+		fmt.Fprintf(sb, "Type: %s\n\n", scheme.Type)
+		if scheme.Description != "" {
+			sb.WriteString(scheme.Description + "\n\n")
+		}
+		for _, detail := range scheme.Details {
+			fmt.Fprintf(sb, "- %s\n", detail)
+		}
+		if len(scheme.Details) > 0 {
+			sb.WriteString("\n")
+		}
+	}
+}
+
+// markdownContents lists the operations of each tag and the component schemas, linking to their pages.
+func markdownContents(sb *strings.Builder, s *site) {
+	if len(s.Tags) > 0 {
+		sb.WriteString("## Operations\n\n")
+		for _, tag := range s.Tags {
+			fmt.Fprintf(sb, "### [%s](%s.md)\n\n", tag.Name, tag.Slug)
+			if tag.Description != "" {
+				sb.WriteString(tag.Description + "\n\n")
+			}
+			for _, op := range tag.Operations {
+				fmt.Fprintf(sb, "- [`%s %s`](%s.md#%s)", op.Method, op.Path, tag.Slug, op.Anchor)
+				if op.Summary != "" {
+					sb.WriteString(" - " + op.Summary)
+				}
+				sb.WriteString("\n")
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(s.Schemas) > 0 {
+		fmt.Fprintf(sb, "## [Schemas](%s)\n\n", MarkdownSchemasFile)
+		for _, schema := range s.Schemas {
+			fmt.Fprintf(sb, "- [%s](%s#%s)\n", schema.Name, MarkdownSchemasFile, schema.Anchor)
+		}
+		sb.WriteString("\n")
+	}
+}
+
+func markdownTag(tag tagSection) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s\n\n", tag.Name)
+	if tag.Description != "" {
+		sb.WriteString(tag.Description + "\n\n")
+	}
+	fmt.Fprintf(&sb, "[Back to index](%s)\n\n", MarkdownIndexFile)
+
+	for _, op := range tag.Operations {
+		markdownOperation(&sb, op)
+	}
+	return sb.String()
+}
+
+func markdownOperation(sb *strings.Builder, op operationSection) {
+	markdownOperationHeader(sb, op)
+
+	if len(op.Parameters) > 0 {
+		sb.WriteString("### Parameters\n\n")
+		sb.WriteString("| Name | In | Type | Required | Description |\n")
+		sb.WriteString("|------|----|------|----------|-------------|\n")
+		for _, p := range op.Parameters {
+			fmt.Fprintf(sb, "| `%s` | %s | %s | %s | %s |\n",
+				p.Name, p.In, markdownType(p.Type), yesNo(p.Required), markdownCell(describe(p)))
+		}
+		sb.WriteString("\n")
+	}
+
+	if op.RequestBody != nil {
+		sb.WriteString("### Request Body\n\n")
+		markdownBody(sb, *op.RequestBody)
+	}
+
+	if len(op.Responses) > 0 {
+		sb.WriteString("### Responses\n\n")
+		for _, resp := range op.Responses {
+			fmt.Fprintf(sb, "#### %s\n\n", resp.Code)
+			markdownBody(sb, resp)
+			markdownLinks(sb, resp.Links)
+		}
+	}
+}
+
+// markdownOperationHeader writes the operation's heading, description, operation ID, and security requirements.
+func markdownOperationHeader(sb *strings.Builder, op operationSection) {
+	fmt.Fprintf(sb, "<a id=\"%s\"></a>\n\n", op.Anchor)
+	fmt.Fprintf(sb, "## `%s %s`\n\n", op.Method, op.Path)
+	if op.Deprecated {
+		sb.WriteString("> **Deprecated**\n\n")
+	}
+	if op.Summary != "" {
+		fmt.Fprintf(sb, "**%s**\n\n", op.Summary)
+	}
+	if op.Description != "" {
+		sb.WriteString(op.Description + "\n\n")
+	}
+	if op.OperationID != "" {
+		fmt.Fprintf(sb, "Operation ID: `%s`\n\n", op.OperationID)
+	}
+	if len(op.Security) > 0 {
+		fmt.Fprintf(sb, "Security: %s\n\n", markdownSecurity(op.Security))
+	}
+}
+
+// markdownSecurity renders security requirements as alternatives joined by "or",
+// linking each scheme to its section on the index page.
+func markdownSecurity(rows []securityRow) string {
+	alternatives := make([]string, len(rows))
+	for i, row := range rows {
+		if len(row.Schemes) == 0 {
+			alternatives[i] = "anonymous"
+			continue
+		}
+		schemes := make([]string, len(row.Schemes))
+		for j, ref := range row.Schemes {
+			schemes[j] = fmt.Sprintf("`%s`", ref.Name)
+			if ref.Anchor != "" {
+				schemes[j] = fmt.Sprintf("[%s](%s#%s)", schemes[j], MarkdownIndexFile, ref.Anchor)
+			}
+			if ref.Scopes != "" {
+				schemes[j] += " (" + ref.Scopes + ")"
+			}
+		}
+		alternatives[i] = strings.Join(schemes, " and ")
+	}
+	return strings.Join(alternatives, " or ")
+}
+
+func markdownBody(sb *strings.Builder, body bodySection) {
+	if body.Description != "" {
+		sb.WriteString(body.Description + "\n\n")
+	}
+	if body.Required {
+		sb.WriteString("Required: yes\n\n")
+	}
+	if len(body.ContentTypes) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "Content types: `%s`\n\n", strings.Join(body.ContentTypes, "`, `"))
+	fmt.Fprintf(sb, "Schema: %s\n\n", markdownType(body.Type))
+	if body.Example != "" {
+		fmt.Fprintf(sb, "Example:\n\n```json\n%s\n```\n\n", body.Example)
+	}
+}
+
+func markdownLinks(sb *strings.Builder, links []linkRow) {
+	if len(links) == 0 {
+		return
+	}
+	sb.WriteString("Links:\n\n")
+	sb.WriteString("| Name | Operation | Parameters | Description |\n")
+	sb.WriteString("|------|-----------|------------|-------------|\n")
+	for _, link := range links {
+		operation := fmt.Sprintf("`%s`", link.OperationID)
+		if link.Anchor != "" {
+			operation = fmt.Sprintf("[%s](%s#%s)", operation, link.Page, link.Anchor)
+		}
+		fmt.Fprintf(sb, "| %s | %s | %s | %s |\n",
+			markdownCell(link.Name), operation, markdownCell(link.Parameters), markdownCell(link.Description))
+	}
+	sb.WriteString("\n")
+}
+
+func markdownSchemas(schemas []schemaSection) string {
+	var sb strings.Builder
+
+	sb.WriteString("# Schemas\n\n")
+	fmt.Fprintf(&sb, "[Back to index](%s)\n\n", MarkdownIndexFile)
+
+	for _, schema := range schemas {
+		fmt.Fprintf(&sb, "<a id=\"%s\"></a>\n\n", schema.Anchor)
+		fmt.Fprintf(&sb, "## %s\n\n", schema.Name)
+		if schema.Description != "" {
+			sb.WriteString(schema.Description + "\n\n")
+		}
+		fmt.Fprintf(&sb, "Type: %s\n\n", markdownType(schema.Type))
+
+		if len(schema.Properties) > 0 {
+			sb.WriteString("| Property | Type | Required | Description |\n")
+			sb.WriteString("|----------|------|----------|-------------|\n")
+			for _, p := range schema.Properties {
+				fmt.Fprintf(&sb, "| `%s` | %s | %s | %s |\n",
+					p.Name, markdownType(p.Type), yesNo(p.Required), markdownCell(describe(p)))
+			}
+			sb.WriteString("\n")
+		}
+
+		if schema.Example != "" {
+			fmt.Fprintf(&sb, "Example:\n\n```json\n%s\n```\n\n", schema.Example)
+		}
+	}
+	return sb.String()
+}
+
+// markdownType renders a type label, linking it to the schemas page when it names a component.
+func markdownType(t typeRef) string {
+	label := markdownCell(t.Label)
+	if t.Schema == "" {
+		return label
+	}
+	return fmt.Sprintf("[%s](%s#%s)", label, MarkdownSchemasFile, schemaAnchor(t.Schema))
+}
+
+// markdownCell makes text safe for a single Markdown table cell.
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// describe returns a row's description, prefixed when the field is deprecated.
+func describe(p propertyRow) string {
+	if p.Deprecated {
+		return strings.TrimSpace("**Deprecated.** " + p.Description)
+	}
+	return p.Description
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,461 @@
+// Package docs renders OpenAPI documents into static documentation.
+//
+// Two outputs are supported: Markdown with one file per tag, for docs kept in
+// the repository, and a single self-contained HTML page for offline
+// distribution. Neither output loads anything from a CDN or uses JavaScript.
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/shared"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// defaultTag groups operations that declare no tags.
+const defaultTag = "default"
+
+// site is the renderer-independent view of a document.
+type site struct {
+	Title       string
+	Version     string
+	Description string
+	Servers     []openapi.Server
+	Security    []securitySection
+	Tags        []tagSection
+	Schemas     []schemaSection
+}
+
+type tagSection struct {
+	Name        string
+	Slug        string
+	Description string
+	Operations  []operationSection
+}
+
+type operationSection struct {
+	Method      string
+	Path        string
+	Anchor      string
+	OperationID string
+	Summary     string
+	Description string
+	Deprecated  bool
+	Security    []securityRow
+	Parameters  []propertyRow
+	RequestBody *bodySection
+	Responses   []bodySection
+}
+
+// bodySection describes a request body or a response.
+type bodySection struct {
+	Code         string // response status code; empty for request bodies
+	Description  string
+	Required     bool
+	ContentTypes []string
+	Type         typeRef
+	Example      string
+	Links        []linkRow // response links; empty for request bodies
+}
+
+// linkRow describes a response link to another operation.
+type linkRow struct {
+	Name        string
+	OperationID string
+	Anchor      string // anchor of the target operation; empty if it is not in the document
+	Page        string // Markdown file of the target operation
+	Parameters  string
+	Description string
+}
+
+type schemaSection struct {
+	Name        string
+	Anchor      string
+	Description string
+	Type        typeRef
+	Properties  []propertyRow
+	Example     string
+}
+
+// propertyRow describes a schema property or an operation parameter.
+type propertyRow struct {
+	Name        string
+	In          string // parameter location; empty for properties
+	Type        typeRef
+	Required    bool
+	Deprecated  bool
+	Description string
+}
+
+// typeRef is a human-readable type label with the component schema it refers to, if any.
+type typeRef struct {
+	Label  string
+	Schema string
+}
+
+var slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify lowercases s and joins its words with dashes, for file names and anchors.
+func slugify(s string) string {
+	return strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+// uniqueSlug slugifies name, adding a numeric suffix when the slug is already taken.
+func uniqueSlug(name string, taken map[string]bool) string {
+	base := slugify(name)
+	if base == "" {
+		base = "tag"
+	}
+	slug := base
+	for i := 2; taken[slug]; i++ {
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+	taken[slug] = true
+	return slug
+}
+
+// schemaAnchor returns the anchor of a component schema's section.
+func schemaAnchor(name string) string {
+	return "schema-" + slugify(name)
+}
+
+func buildSite(doc *openapi.Document) *site {
+	s := &site{
+		Title:       doc.Info.Title,
+		Version:     doc.Info.Version,
+		Description: doc.Info.Description,
+		Servers:     doc.Servers,
+		Security:    buildSecuritySchemes(doc),
+	}
+
+	byTag := make(map[string][]operationSection)
+	for _, entry := range shared.Operations(doc, sortedKeys(doc.Paths)) {
+		section := buildOperation(doc, entry)
+		tags := entry.Operation.Tags
+		if len(tags) == 0 {
+			tags = []string{defaultTag}
+		}
+		for _, tag := range tags {
+			byTag[tag] = append(byTag[tag], section)
+		}
+	}
+
+	// Tag slugs name the Markdown files, so keep them clear of the fixed pages and of each other.
+	taken := map[string]bool{"readme": true, "schemas": true}
+	for _, name := range tagOrder(doc, byTag) {
+		s.Tags = append(s.Tags, tagSection{
+			Name:        name,
+			Slug:        uniqueSlug(name, taken),
+			Description: tagDescription(doc, name),
+			Operations:  byTag[name],
+		})
+	}
+
+	resolveLinkPages(s.Tags)
+
+	if doc.Components != nil {
+		for _, name := range sortedKeys(doc.Components.Schemas) {
+			s.Schemas = append(s.Schemas, buildSchema(doc, name, doc.Components.Schemas[name]))
+		}
+	}
+	return s
+}
+
+// tagOrder lists declared tags first, then undeclared tags alphabetically, then the default tag.
+func tagOrder(doc *openapi.Document, byTag map[string][]operationSection) []string {
+	var order []string
+	for _, tag := range doc.Tags {
+		if _, ok := byTag[tag.Name]; ok && !slices.Contains(order, tag.Name) {
+			order = append(order, tag.Name)
+		}
+	}
+	for _, name := range sortedKeys(byTag) {
+		if name != defaultTag && !slices.Contains(order, name) {
+			order = append(order, name)
+		}
+	}
+	if _, ok := byTag[defaultTag]; ok && !slices.Contains(order, defaultTag) {
+		order = append(order, defaultTag)
+	}
+	return order
+}
+
+func tagDescription(doc *openapi.Document, name string) string {
+	for _, tag := range doc.Tags {
+		if tag.Name == name {
+			return tag.Description
+		}
+	}
+	return ""
+}
+
+func buildOperation(doc *openapi.Document, entry shared.Operation) operationSection {
+	op := entry.Operation
+	section := operationSection{
+		Method:      entry.Method,
+		Path:        entry.Path,
+		Anchor:      slugify(entry.Method + " " + entry.Path),
+		OperationID: op.OperationID,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Deprecated:  op.Deprecated,
+		Security:    buildSecurity(doc, op),
+	}
+	if op.OperationID != "" {
+		section.Anchor = slugify(op.OperationID)
+	}
+
+	for _, param := range slices.Concat(entry.Item.Parameters, op.Parameters) {
+		param = resolveParameter(doc, param)
+		if param == nil {
+			continue
+		}
+		section.Parameters = append(section.Parameters, propertyRow{
+			Name:        param.Name,
+			In:          string(param.In),
+			Type:        schemaType(param.Schema),
+			Required:    param.Required,
+			Deprecated:  param.Deprecated,
+			Description: param.Description,
+		})
+	}
+
+	if body := resolveRequestBody(doc, op.RequestBody); body != nil {
+		section.RequestBody = buildBody(doc, "", body.Description, body.Required, body.Content)
+	}
+
+	for _, code := range sortedKeys(op.Responses) {
+		resp := resolveResponse(doc, op.Responses[code])
+		if resp == nil {
+			continue
+		}
+		body := buildBody(doc, code, resp.Description, false, resp.Content)
+		body.Links = buildLinks(doc, resp.Links)
+		section.Responses = append(section.Responses, *body)
+	}
+	return section
+}
+
+func buildLinks(doc *openapi.Document, links map[string]*openapi.Link) []linkRow {
+	var rows []linkRow
+	for _, name := range sortedKeys(links) {
+		link := links[name]
+		if link == nil {
+			continue
+		}
+		row := linkRow{Name: name, OperationID: link.OperationID, Description: link.Description}
+		if row.OperationID == "" {
+			row.OperationID = link.OperationRef
+		}
+		if hasOperation(doc, link.OperationID) {
+			row.Anchor = slugify(link.OperationID)
+		}
+		var params []string
+		for _, param := range sortedKeys(link.Parameters) {
+			params = append(params, fmt.Sprintf("%s=%v", param, link.Parameters[param]))
+		}
+		row.Parameters = strings.Join(params, ", ")
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func hasOperation(doc *openapi.Document, operationID string) bool {
+	if operationID == "" {
+		return false
+	}
+	return slices.ContainsFunc(shared.Operations(doc, sortedKeys(doc.Paths)), func(o shared.Operation) bool {
+		return o.Operation.OperationID == operationID
+	})
+}
+
+// resolveLinkPages points response links at the Markdown file of the first
+// tag listing the target operation.
+func resolveLinkPages(tags []tagSection) {
+	pages := make(map[string]string)
+	for _, tag := range tags {
+		for _, op := range tag.Operations {
+			if _, ok := pages[op.Anchor]; !ok {
+				pages[op.Anchor] = tag.Slug + ".md"
+			}
+		}
+	}
+	for _, tag := range tags {
+		for _, op := range tag.Operations {
+			for _, resp := range op.Responses {
+				for i, link := range resp.Links {
+					if link.Anchor != "" {
+						resp.Links[i].Page = pages[link.Anchor]
+					}
+				}
+			}
+		}
+	}
+}
+
+func buildBody(doc *openapi.Document, code, description string, required bool, content map[string]openapi.MediaType) *bodySection {
+	body := &bodySection{
+		Code:         code,
+		Description:  description,
+		Required:     required,
+		ContentTypes: sortedKeys(content),
+	}
+	if len(body.ContentTypes) > 0 {
+		mt := content[preferredContentType(body.ContentTypes)]
+		body.Type = schemaType(mt.Schema)
+		body.Example = formatExample(doc.MediaTypeExample(mt))
+	}
+	return body
+}
+
+// preferredContentType picks the JSON media type when present, else the first one.
+func preferredContentType(types []string) string {
+	for _, t := range types {
+		if strings.Contains(t, "json") {
+			return t
+		}
+	}
+	return types[0]
+}
+
+func buildSchema(doc *openapi.Document, name string, schema *openapi.Schema) schemaSection {
+	section := schemaSection{
+		Name:   name,
+		Anchor: schemaAnchor(name),
+		Type:   schemaType(schema),
+	}
+	if schema == nil {
+		return section
+	}
+	section.Description = schema.Description
+
+	for _, propName := range sortedKeys(schema.Properties) {
+		prop := schema.Properties[propName]
+		row := propertyRow{
+			Name:     propName,
+			Type:     schemaType(prop),
+			Required: slices.Contains(schema.Required, propName),
+		}
+		if prop != nil {
+			row.Description = prop.Description
+			row.Deprecated = prop.Deprecated
+		}
+		section.Properties = append(section.Properties, row)
+	}
+	section.Example = formatExample(doc.ExampleFor(schema))
+	return section
+}
+
+// schemaType describes a schema as a short type label.
+func schemaType(s *openapi.Schema) typeRef {
+	if s == nil {
+		return typeRef{Label: "any"}
+	}
+	if s.Ref != "" {
+		name := shared.ComponentName(s.Ref)
+		return typeRef{Label: name, Schema: name}
+	}
+
+	t := baseType(s)
+	if s.Format != "" {
+		t.Label += " (" + s.Format + ")"
+	}
+	if len(s.Enum) > 0 {
+		values := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			values[i] = fmt.Sprint(v)
+		}
+		t.Label += ", one of: " + strings.Join(values, ", ")
+	}
+	if s.Nullable {
+		t.Label += ", nullable"
+	}
+	return t
+}
+
+func baseType(s *openapi.Schema) typeRef {
+	switch {
+	case len(s.OneOf) > 0:
+		return compositeType("oneOf", s.OneOf)
+	case len(s.AnyOf) > 0:
+		return compositeType("anyOf", s.AnyOf)
+	case len(s.AllOf) > 0:
+		return compositeType("allOf", s.AllOf)
+	case slices.Contains(s.Type, openapi.TypeArray):
+		item := schemaType(s.Items)
+		return typeRef{Label: "array of " + item.Label, Schema: item.Schema}
+	case len(s.Type) > 0:
+		return typeRef{Label: strings.Join(s.Type, " | ")}
+	default:
+		return typeRef{Label: "any"}
+	}
+}
+
+func compositeType(keyword string, schemas []*openapi.Schema) typeRef {
+	labels := make([]string, len(schemas))
+	t := typeRef{}
+	for i, sub := range schemas {
+		st := schemaType(sub)
+		labels[i] = st.Label
+		if t.Schema == "" {
+			t.Schema = st.Schema
+		}
+	}
+	t.Label = keyword + ": " + strings.Join(labels, " | ")
+	return t
+}
+
+func formatExample(v any) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func resolveParameter(doc *openapi.Document, p *openapi.Parameter) *openapi.Parameter {
+	if p == nil || p.Ref == "" || doc.Components == nil {
+		return p
+	}
+	if resolved := doc.Components.Parameters[shared.ComponentName(p.Ref)]; resolved != nil {
+		return resolved
+	}
+	return p
+}
+
+func resolveRequestBody(doc *openapi.Document, rb *openapi.RequestBody) *openapi.RequestBody {
+	if rb == nil || rb.Ref == "" || doc.Components == nil {
+		return rb
+	}
+	if resolved := doc.Components.RequestBodies[shared.ComponentName(rb.Ref)]; resolved != nil {
+		return resolved
+	}
+	return rb
+}
+
+func resolveResponse(doc *openapi.Document, r *openapi.Response) *openapi.Response {
+	if r == nil || r.Ref == "" || doc.Components == nil {
+		return r
+	}
+	if resolved := doc.Components.Responses[shared.ComponentName(r.Ref)]; resolved != nil {
+		return resolved
+	}
+	return r
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,132 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// securitySection describes a security scheme declared in the components.
+type securitySection struct {
+	Name        string
+	Anchor      string
+	Type        string
+	Description string
+	Details     []string
+}
+
+// securityRow is one alternative of an operation's security requirements:
+// every scheme it lists must be satisfied. A row without schemes allows
+// anonymous access.
+type securityRow struct {
+	Schemes []securityRef
+}
+
+type securityRef struct {
+	Name   string
+	Anchor string // anchor of the scheme's section; empty if it is not declared
+	Scopes string
+}
+
+// securityAnchor returns the anchor of a security scheme's section.
+func securityAnchor(name string) string {
+	return "security-" + slugify(name)
+}
+
+func buildSecuritySchemes(doc *openapi.Document) []securitySection {
+	if doc.Components == nil {
+		return nil
+	}
+	var sections []securitySection
+	for _, name := range sortedKeys(doc.Components.SecuritySchemes) {
+		scheme := doc.Components.SecuritySchemes[name]
+		if scheme == nil {
+			continue
+		}
+		typ, details := describeScheme(scheme)
+		sections = append(sections, securitySection{
+			Name:        name,
+			Anchor:      securityAnchor(name),
+			Type:        typ,
+			Description: scheme.Description,
+			Details:     details,
+		})
+	}
+	return sections
+}
+
+// describeScheme returns a readable label for the scheme type and the details a client needs to authenticate.
+func describeScheme(scheme *openapi.SecurityScheme) (string, []string) {
+	switch scheme.Type {
+	case "apiKey":
+		return "API key", []string{fmt.Sprintf("Sent in the %s parameter %s", scheme.In, scheme.Name)}
+	case "http":
+		var details []string
+		if scheme.BearerFormat != "" {
+			details = append(details, "Bearer format: "+scheme.BearerFormat)
+		}
+		return "HTTP " + scheme.Scheme, details
+	case "oauth2":
+		return "OAuth 2.0", oauthFlowDetails(scheme.Flows)
+	case "openIdConnect":
+		return "OpenID Connect", []string{"Discovery URL: " + scheme.OpenIDConnectURL}
+	case "mutualTLS":
+		return "Mutual TLS", []string{"Clients authenticate with an X.509 certificate presented during the TLS handshake"}
+	default:
+		return scheme.Type, nil
+	}
+}
+
+func oauthFlowDetails(flows *openapi.OAuthFlows) []string {
+	if flows == nil {
+		return nil
+	}
+	var details []string
+	for _, f := range []struct {
+		name string
+		flow *openapi.OAuthFlow
+	}{
+		{"Implicit", flows.Implicit},
+		{"Password", flows.Password},
+		{"Client credentials", flows.ClientCredentials},
+		{"Authorization code", flows.AuthorizationCode},
+	} {
+		if f.flow == nil {
+			continue
+		}
+		var parts []string
+		if f.flow.AuthorizationURL != "" {
+			parts = append(parts, "authorization URL "+f.flow.AuthorizationURL)
+		}
+		if f.flow.TokenURL != "" {
+			parts = append(parts, "token URL "+f.flow.TokenURL)
+		}
+		if len(f.flow.Scopes) > 0 {
+			parts = append(parts, "scopes "+strings.Join(sortedKeys(f.flow.Scopes), ", "))
+		}
+		details = append(details, strings.TrimSuffix(f.name+" flow: "+strings.Join(parts, "; "), ": "))
+	}
+	return details
+}
+
+// buildSecurity lists the security requirements of op, falling back to the document's.
+func buildSecurity(doc *openapi.Document, op *openapi.Operation) []securityRow {
+	requirements := op.Security
+	if requirements == nil {
+		requirements = doc.Security
+	}
+	var rows []securityRow
+	for _, requirement := range requirements {
+		row := securityRow{}
+		for _, name := range sortedKeys(requirement) {
+			ref := securityRef{Name: name, Scopes: strings.Join(requirement[name], ", ")}
+			if doc.Components != nil && doc.Components.SecuritySchemes[name] != nil {
+				ref.Anchor = securityAnchor(name)
+			}
+			row.Schemes = append(row.Schemes, ref)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -0,0 +1,70 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/report"
+)
+
+// FormatText formats lint result as human-readable text
+func FormatText(result *Result) string {
+	var sb strings.Builder
+
+	sb.WriteString("Lint Report\n")
+	sb.WriteString("===========\n\n")
+
+	sb.WriteString("Summary\n")
+	sb.WriteString("-------\n")
+	sb.WriteString(fmt.Sprintf("Findings: %d error, %d warning, %d info\n\n",
+		result.Count(SeverityError), result.Count(SeverityWarning), result.Count(SeverityInfo)))
+
+	if len(result.Findings) == 0 {
+		sb.WriteString("Findings\n")
+		sb.WriteString("--------\n")
+		sb.WriteString("No issues found.\n")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("Findings (%d issues)\n", len(result.Findings)))
+	sb.WriteString("-------------------\n\n")
+
+	for _, f := range result.Findings {
+		sb.WriteString(fmt.Sprintf("[%s] %s - %s\n", f.Severity, f.RuleID, f.RuleName))
+		sb.WriteString(fmt.Sprintf("  Location: %s\n", f.Location))
+		sb.WriteString(fmt.Sprintf("  Message: %s\n\n", f.Message))
+	}
+
+	return sb.String()
+}
+
+// FormatJSON formats lint result as JSON
+func FormatJSON(result *Result) ([]byte, error) {
+	return json.MarshalIndent(result, "", "  ")
+}
+
+// PRCommentOptions controls the size of a pull request comment.
+type PRCommentOptions = report.PRCommentOptions
+
+// FormatPRComment formats the lint result as Markdown sized for a GitHub or
+// GitLab pull request comment: a one-line summary and a collapsed table of
+// the most severe findings.
+func FormatPRComment(result *Result, opts PRCommentOptions) string {
+	status := "passed"
+	if result.HasFindingsAtOrAbove(SeverityError) {
+		status = "failed"
+	}
+	comment := report.PRComment{
+		Heading: fmt.Sprintf("Lint %s: %d error, %d warning, %d info",
+			status, result.Count(SeverityError), result.Count(SeverityWarning), result.Count(SeverityInfo)),
+		Empty:   "No issues found.",
+		Noun:    "findings",
+		Report:  "Full lint report",
+		Columns: []string{"Severity", "Rule", "Location", "Message"},
+	}
+	for _, f := range report.MostSevereFirst(result.Findings, findingSeverity) {
+		comment.Rows = append(comment.Rows, []string{string(f.Severity), "`" + f.RuleID + "`", "`" + f.Location + "`", f.Message})
+	}
+	return comment.Format(opts)
+}
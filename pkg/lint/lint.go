@@ -0,0 +1,206 @@
+// Package lint provides style linting for OpenAPI specifications.
+//
+// Unlike the audit package, which looks for security issues, lint checks
+// API design conventions such as operationId naming, path casing, missing
+// descriptions, and unused components. Rule severities can be overridden and
+// rules or paths ignored through Config, starting from one of the presets
+// that follow a recognized API style guide.
+package lint
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"github.com/fathurrohman26/yaswag/pkg/report"
+	"gopkg.in/yaml.v3"
+)
+
+// Severity levels for lint findings
+type Severity = report.Severity
+
+const (
+	SeverityError   = report.SeverityError
+	SeverityWarning = report.SeverityWarning
+	SeverityInfo    = report.SeverityInfo
+	SeverityOff     = report.SeverityOff // disables a rule
+)
+
+// ParseSeverity parses a severity name case-insensitively (error, warning, info, off).
+func ParseSeverity(s string) (Severity, error) {
+	return report.ParseSeverity(s)
+}
+
+// Finding represents a single lint finding
+type Finding struct {
+	RuleID   string   `json:"rule_id"`
+	RuleName string   `json:"rule_name"`
+	Severity Severity `json:"severity"`
+	Location string   `json:"location"`
+	Pointer  string   `json:"pointer,omitempty"` // JSON pointer into the spec (RFC 6901)
+	Path     string   `json:"path,omitempty"`    // API path the finding belongs to, if any
+	Message  string   `json:"message"`
+}
+
+// Config customizes which rules run and how severe their findings are
+type Config struct {
+	// Preset names a built-in preset (see Presets) whose severities apply to
+	// the rules without an entry in Severities
+	Preset string
+
+	// Severities overrides the default severity per rule ID; SeverityOff disables a rule
+	Severities map[string]Severity
+
+	// IgnoreRules lists rule IDs to skip
+	IgnoreRules []string
+
+	// IgnorePaths lists API path patterns (path.Match syntax) whose findings are dropped;
+	// a trailing "/*" also matches nested paths
+	IgnorePaths []string
+}
+
+// Result contains the complete lint results
+type Result struct {
+	Source         string           `json:"source,omitempty"`
+	Findings       []Finding        `json:"findings"`
+	SeverityCounts map[Severity]int `json:"severity_counts"`
+}
+
+// Count returns the number of findings with the given severity.
+func (r *Result) Count(severity Severity) int {
+	return report.Count(r.Findings, findingSeverity, severity)
+}
+
+// HasFindingsAtOrAbove reports whether any finding is at least as severe as threshold.
+func (r *Result) HasFindingsAtOrAbove(threshold Severity) bool {
+	return report.HasFindingsAtOrAbove(r.Findings, findingSeverity, threshold)
+}
+
+func findingSeverity(f Finding) Severity {
+	return f.Severity
+}
+
+// Linter checks OpenAPI documents against style rules
+type Linter struct {
+	rules  []Rule
+	config Config
+	preset Preset
+}
+
+// New creates a new Linter with default rules and the given config (may be nil).
+// An unknown config preset is ignored; check it with LookupPreset first.
+func New(config *Config) *Linter {
+	l := &Linter{rules: DefaultRules()}
+	if config != nil {
+		l.config = *config
+		l.preset, _ = LookupPreset(config.Preset)
+	}
+	return l
+}
+
+// Lint runs all enabled rules against an OpenAPI document
+func (l *Linter) Lint(doc *openapi.Document) *Result {
+	result := &Result{
+		Findings:       []Finding{},
+		SeverityCounts: map[Severity]int{SeverityError: 0, SeverityWarning: 0, SeverityInfo: 0},
+	}
+
+	for _, rule := range l.rules {
+		severity := l.ruleSeverity(rule)
+		if severity == SeverityOff {
+			continue
+		}
+		for _, f := range rule.Check(doc) {
+			if l.isIgnoredPath(f.Path) {
+				continue
+			}
+			f.Severity = severity
+			result.Findings = append(result.Findings, f)
+			result.SeverityCounts[severity]++
+		}
+	}
+
+	return result
+}
+
+func (l *Linter) ruleSeverity(rule Rule) Severity {
+	if slices.Contains(l.config.IgnoreRules, rule.ID()) {
+		return SeverityOff
+	}
+	if severity, ok := l.config.Severities[rule.ID()]; ok {
+		return severity
+	}
+	if severity, ok := l.preset.Severities[rule.ID()]; ok {
+		return severity
+	}
+	return rule.Severity()
+}
+
+func (l *Linter) isIgnoredPath(apiPath string) bool {
+	if apiPath == "" {
+		return false
+	}
+	for _, pattern := range l.config.IgnorePaths {
+		if matched, _ := path.Match(pattern, apiPath); matched {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasSuffix(prefix, "/") && strings.HasPrefix(apiPath, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// LintFile lints an OpenAPI specification file
+func (l *Linter) LintFile(path string) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	result, err := l.LintData(data)
+	if err != nil {
+		return nil, err
+	}
+	result.Source = path
+	return result, nil
+}
+
+// LintData lints OpenAPI specification bytes (JSON or YAML)
+func (l *Linter) LintData(data []byte) (*Result, error) {
+	var doc openapi.Document
+	// yaml.Unmarshal handles both JSON and YAML formats
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+	return l.Lint(&doc), nil
+}
+
+// LintURL lints an OpenAPI specification from a URL
+func (l *Linter) LintURL(url string) (*Result, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	result, err := l.LintData(data)
+	if err != nil {
+		return nil, err
+	}
+	result.Source = url
+	return result, nil
+}
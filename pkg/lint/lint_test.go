@@ -0,0 +1,299 @@
+package lint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+func createLintTestDoc() *openapi.Document {
+	return &openapi.Document{
+		OpenAPI: "3.0.3",
+		Info:    openapi.Info{Title: "Test", Version: "1.0.0"},
+		Tags:    []openapi.Tag{{Name: "pets"}, {Name: "pets"}},
+		Paths: openapi.Paths{
+			"/pets/{petId}": {
+				Get: &openapi.Operation{
+					OperationID: "getPetById",
+					Summary:     "Get a pet",
+					Tags:        []string{"pets"},
+					Responses: openapi.Responses{
+						"200": {Description: "OK", Content: map[string]openapi.MediaType{
+							"application/json": {Schema: openapi.RefTo("Pet")},
+						}},
+						"404": {Description: "Not found"},
+					},
+				},
+			},
+			"/Store_Orders": {
+				Post: &openapi.Operation{
+					OperationID: "Create_Order",
+					Tags:        []string{"store", "store"},
+					Responses:   openapi.Responses{"201": {Description: "Created"}},
+				},
+			},
+			"/internal/jobs": {
+				Get: &openapi.Operation{Summary: "List jobs", Responses: openapi.Responses{"default": {Description: "Error"}}},
+			},
+		},
+		Components: &openapi.Components{
+			Schemas: map[string]*openapi.Schema{
+				"Pet": {
+					Description: "A pet",
+					Type:        openapi.NewSchemaType(openapi.TypeObject),
+					Properties:  map[string]*openapi.Schema{"category": openapi.RefTo("Category")},
+				},
+				"Category": {Description: "A category", Type: openapi.NewSchemaType(openapi.TypeObject)},
+				"Orphan":   {Type: openapi.NewSchemaType(openapi.TypeObject)},
+			},
+		},
+	}
+}
+
+func findingsByRule(result *Result) map[string][]Finding {
+	byRule := make(map[string][]Finding)
+	for _, f := range result.Findings {
+		byRule[f.RuleID] = append(byRule[f.RuleID], f)
+	}
+	return byRule
+}
+
+func TestLint_DefaultRules(t *testing.T) {
+	byRule := findingsByRule(New(nil).Lint(createLintTestDoc()))
+
+	tests := []struct {
+		ruleID   string
+		count    int
+		location string
+	}{
+		{ruleID: "OPERATION_ID_NAMING", count: 2, location: "POST /Store_Orders"},
+		{ruleID: "DESCRIPTION_MISSING", count: 2, location: "POST /Store_Orders"},
+		{ruleID: "UNUSED_COMPONENT", count: 1, location: "components.schemas.Orphan"},
+		{ruleID: "DUPLICATE_TAG", count: 2, location: "Tag 'pets'"},
+		{ruleID: "MISSING_4XX_RESPONSE", count: 1, location: "POST /Store_Orders"},
+		{ruleID: "PATH_CASING", count: 1, location: "/Store_Orders"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ruleID, func(t *testing.T) {
+			findings := byRule[tt.ruleID]
+			if len(findings) != tt.count {
+				t.Fatalf("%s findings = %d, want %d: %+v", tt.ruleID, len(findings), tt.count, findings)
+			}
+			if findings[0].Location != tt.location {
+				t.Errorf("%s location = %q, want %q", tt.ruleID, findings[0].Location, tt.location)
+			}
+		})
+	}
+}
+
+func TestOwnerRule(t *testing.T) {
+	doc := &openapi.Document{
+		Tags: []openapi.Tag{{Name: "payments", Owner: &openapi.Owner{Team: "team-payments"}}},
+		Paths: map[string]*openapi.PathItem{
+			"/payments": {Get: &openapi.Operation{Tags: []string{"payments"}}},
+			"/refunds":  {Get: &openapi.Operation{Owner: &openapi.Owner{Team: "team-refunds"}}},
+			"/health":   {Get: &openapi.Operation{Tags: []string{"ops"}}},
+		},
+	}
+
+	if findings := findingsByRule(New(nil).Lint(doc))["OWNER_MISSING"]; len(findings) != 0 {
+		t.Error("OWNER_MISSING should be off by default")
+	}
+
+	linter := New(&Config{Severities: map[string]Severity{"OWNER_MISSING": SeverityError}})
+	findings := findingsByRule(linter.Lint(doc))["OWNER_MISSING"]
+	if len(findings) != 1 || findings[0].Location != "GET /health" || findings[0].Severity != SeverityError {
+		t.Errorf("OWNER_MISSING = %+v, want one ERROR for GET /health", findings)
+	}
+}
+
+func TestLint_Config(t *testing.T) {
+	linter := New(&Config{
+		Severities: map[string]Severity{
+			"PATH_CASING":         SeverityError,
+			"DESCRIPTION_MISSING": SeverityOff,
+		},
+		IgnoreRules: []string{"DUPLICATE_TAG"},
+		IgnorePaths: []string{"/internal/*"},
+	})
+	result := linter.Lint(createLintTestDoc())
+	byRule := findingsByRule(result)
+
+	if len(byRule["DESCRIPTION_MISSING"]) != 0 || len(byRule["DUPLICATE_TAG"]) != 0 {
+		t.Error("Disabled and ignored rules should produce no findings")
+	}
+	if f := byRule["PATH_CASING"]; len(f) != 1 || f[0].Severity != SeverityError {
+		t.Errorf("PATH_CASING = %+v, want one ERROR finding", f)
+	}
+	for _, f := range result.Findings {
+		if strings.HasPrefix(f.Path, "/internal/") {
+			t.Errorf("Finding for ignored path: %+v", f)
+		}
+	}
+	if result.Count(SeverityError) != 1 || !result.HasFindingsAtOrAbove(SeverityError) {
+		t.Errorf("error count = %d, want 1", result.Count(SeverityError))
+	}
+}
+
+func TestPresets(t *testing.T) {
+	for _, preset := range Presets() {
+		for _, rule := range DefaultRules() {
+			if _, ok := preset.Severities[rule.ID()]; !ok {
+				t.Errorf("preset %s does not configure %s", preset.Name, rule.ID())
+			}
+		}
+	}
+
+	linter := New(&Config{Preset: "zalando", Severities: map[string]Severity{"DUPLICATE_TAG": SeverityOff}})
+	byRule := findingsByRule(linter.Lint(createLintTestDoc()))
+	if f := byRule["PATH_CASING"]; len(f) != 1 || f[0].Severity != SeverityError {
+		t.Errorf("PATH_CASING = %+v, want one ERROR finding", f)
+	}
+	if len(byRule["DUPLICATE_TAG"]) != 0 {
+		t.Error("Severities should override the preset")
+	}
+
+	if _, err := LookupPreset("Google-AIP"); err != nil {
+		t.Errorf("LookupPreset should ignore case: %v", err)
+	}
+	if _, err := LookupPreset("acme"); err == nil {
+		t.Error("LookupPreset(acme) should return an error")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lint.yaml")
+	data := "preset: minimal\nseverities:\n  PATH_CASING: error\nignore: [DUPLICATE_TAG]\nignorePaths: [/internal/*]\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if config.Preset != "minimal" || config.Severities["PATH_CASING"] != SeverityError ||
+		!reflect.DeepEqual(config.IgnoreRules, []string{"DUPLICATE_TAG"}) || !reflect.DeepEqual(config.IgnorePaths, []string{"/internal/*"}) {
+		t.Errorf("LoadConfig = %+v", config)
+	}
+
+	byRule := findingsByRule(New(config).Lint(createLintTestDoc()))
+	if len(byRule["PATH_CASING"]) != 1 || len(byRule["OPERATION_ID_NAMING"]) != 0 || len(byRule["UNUSED_COMPONENT"]) != 1 {
+		t.Errorf("findings = %v, want the minimal preset extended with PATH_CASING", byRule)
+	}
+}
+
+func TestLoadConfig_UnknownPreset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lint.yaml")
+	if err := os.WriteFile(path, []byte("preset: acme\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("LoadConfig should reject an unknown preset")
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	for input, want := range map[string]Severity{"error": SeverityError, "Warning": SeverityWarning, "info": SeverityInfo, "OFF": SeverityOff} {
+		got, err := ParseSeverity(input)
+		if err != nil || got != want {
+			t.Errorf("ParseSeverity(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("ParseSeverity(fatal) should return an error")
+	}
+	if SeverityOff.AtLeast(SeverityOff) {
+		t.Error("SeverityOff should never meet a threshold")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	result := New(nil).Lint(createLintTestDoc())
+
+	text := FormatText(result)
+	for _, want := range []string{"Lint Report", "[WARNING] PATH_CASING", "Location: /Store_Orders"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("FormatText() missing %q", want)
+		}
+	}
+
+	data, err := FormatJSON(result)
+	if err != nil {
+		t.Fatalf("FormatJSON() error = %v", err)
+	}
+	var decoded Result
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if len(decoded.Findings) != len(result.Findings) {
+		t.Errorf("decoded findings = %d, want %d", len(decoded.Findings), len(result.Findings))
+	}
+
+	empty := FormatText(New(nil).Lint(&openapi.Document{}))
+	if !strings.Contains(empty, "No issues found.") {
+		t.Error("FormatText() should report no issues for an empty document")
+	}
+}
+
+func TestFormatPRComment(t *testing.T) {
+	result := &Result{
+		Findings: []Finding{
+			{RuleID: "DESCRIPTION_MISSING", Severity: SeverityInfo, Location: "GET /a", Message: "no description"},
+			{RuleID: "PATH_CASING", Severity: SeverityError, Location: "/A_b", Message: "use a | b"},
+			{RuleID: "UNUSED_COMPONENT", Severity: SeverityWarning, Location: "#/components/schemas/X", Message: "unused"},
+		},
+		SeverityCounts: map[Severity]int{SeverityError: 1, SeverityWarning: 1, SeverityInfo: 1},
+	}
+
+	comment := FormatPRComment(result, PRCommentOptions{MaxFindings: 2, ArtifactURL: "https://ci.example.com/lint.txt"})
+
+	for _, want := range []string{
+		"### Lint failed: 1 error, 1 warning, 1 info",
+		"<summary>Top 2 of 3 findings</summary>",
+		`use a \| b`,
+		"[Full lint report](https://ci.example.com/lint.txt)",
+	} {
+		if !strings.Contains(comment, want) {
+			t.Errorf("FormatPRComment() missing %q", want)
+		}
+	}
+	if strings.Index(comment, "PATH_CASING") > strings.Index(comment, "UNUSED_COMPONENT") {
+		t.Error("FormatPRComment() should list errors before warnings")
+	}
+	if strings.Contains(comment, "DESCRIPTION_MISSING") {
+		t.Error("FormatPRComment() should stop at MaxFindings")
+	}
+}
+
+func TestLintData_YAML(t *testing.T) {
+	spec := `
+openapi: 3.0.3
+info:
+  title: Test
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      summary: List users
+      responses:
+        "200":
+          description: OK
+        "400":
+          description: Bad request
+`
+	result, err := New(nil).LintData([]byte(spec))
+	if err != nil {
+		t.Fatalf("LintData() error = %v", err)
+	}
+	if len(result.Findings) != 0 {
+		t.Errorf("findings = %+v, want none", result.Findings)
+	}
+}
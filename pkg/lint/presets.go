@@ -0,0 +1,132 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Preset is a named rule configuration following a recognized API style guide
+type Preset struct {
+	Name        string
+	Description string
+
+	// Severities sets the severity of every built-in rule
+	Severities map[string]Severity
+}
+
+// presets are the built-in presets, in the order they are listed
+var presets = []Preset{
+	{
+		Name:        "minimal",
+		Description: "Duplicate tags and unused components only",
+		Severities: map[string]Severity{
+			"OPERATION_ID_NAMING":  SeverityOff,
+			"DESCRIPTION_MISSING":  SeverityOff,
+			"UNUSED_COMPONENT":     SeverityWarning,
+			"DUPLICATE_TAG":        SeverityWarning,
+			"MISSING_4XX_RESPONSE": SeverityOff,
+			"PATH_CASING":          SeverityOff,
+			"OWNER_MISSING":        SeverityOff,
+		},
+	},
+	{
+		Name:        "zalando",
+		Description: "Zalando RESTful API Guidelines: kebab-case paths, 4xx responses, owners",
+		Severities: map[string]Severity{
+			"OPERATION_ID_NAMING":  SeverityInfo,
+			"DESCRIPTION_MISSING":  SeverityWarning,
+			"UNUSED_COMPONENT":     SeverityWarning,
+			"DUPLICATE_TAG":        SeverityError,
+			"MISSING_4XX_RESPONSE": SeverityError,
+			"PATH_CASING":          SeverityError,
+			"OWNER_MISSING":        SeverityWarning,
+		},
+	},
+	{
+		Name:        "google-aip",
+		Description: "Google AIPs: camelCase operationIds and descriptions, any path casing",
+		Severities: map[string]Severity{
+			"OPERATION_ID_NAMING":  SeverityError,
+			"DESCRIPTION_MISSING":  SeverityWarning,
+			"UNUSED_COMPONENT":     SeverityWarning,
+			"DUPLICATE_TAG":        SeverityError,
+			"MISSING_4XX_RESPONSE": SeverityWarning,
+			"PATH_CASING":          SeverityOff,
+			"OWNER_MISSING":        SeverityOff,
+		},
+	},
+}
+
+// Presets returns the built-in presets
+func Presets() []Preset {
+	return presets
+}
+
+// LookupPreset returns the built-in preset with the given name
+func LookupPreset(name string) (Preset, error) {
+	for _, p := range presets {
+		if strings.EqualFold(p.Name, strings.TrimSpace(name)) {
+			return p, nil
+		}
+	}
+	return Preset{}, fmt.Errorf("unknown preset %q: must be one of %s", name, strings.Join(PresetNames(), ", "))
+}
+
+// PresetNames returns the names of the built-in presets
+func PresetNames() []string {
+	names := make([]string, len(presets))
+	for i, p := range presets {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// fileConfig is the YAML form of a lint config file
+type fileConfig struct {
+	Preset      string            `yaml:"preset"`
+	Severities  map[string]string `yaml:"severities"`
+	Ignore      []string          `yaml:"ignore"`
+	IgnorePaths []string          `yaml:"ignorePaths"`
+}
+
+// LoadConfig reads a lint config file, which extends a preset with rule
+// severity overrides and ignored rules and paths:
+//
+//	preset: zalando
+//	severities:
+//	  OWNER_MISSING: error
+//	ignore: [UNUSED_COMPONENT]
+//	ignorePaths: [/internal/*]
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lint config: %w", err)
+	}
+	var file fileConfig
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse lint config %s: %w", path, err)
+	}
+
+	config := &Config{
+		Preset:      file.Preset,
+		Severities:  make(map[string]Severity),
+		IgnoreRules: file.Ignore,
+		IgnorePaths: file.IgnorePaths,
+	}
+	for ruleID, level := range file.Severities {
+		severity, err := ParseSeverity(level)
+		if err != nil {
+			return nil, fmt.Errorf("lint config %s: rule %s: %w", path, ruleID, err)
+		}
+		config.Severities[ruleID] = severity
+	}
+	if file.Preset != "" {
+		if _, err := LookupPreset(file.Preset); err != nil {
+			return nil, fmt.Errorf("lint config %s: %w", path, err)
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,88 @@
+package lint
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+const componentsPrefix = "#/components/"
+
+func componentRef(kind, name string) string {
+	name = strings.ReplaceAll(name, "~", "~0")
+	name = strings.ReplaceAll(name, "/", "~1")
+	return componentsPrefix + kind + "/" + name
+}
+
+// reachableComponents returns the $refs of all components reachable from the
+// document's paths and webhooks, following references between components.
+func reachableComponents(doc *openapi.Document) map[string]bool {
+	components := componentValues(doc.Components)
+
+	root := *doc
+	root.Components = nil
+	var queue []string
+	collectRefs(toJSONValue(root), &queue)
+
+	used := make(map[string]bool)
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		if used[ref] {
+			continue
+		}
+		used[ref] = true
+		collectRefs(components[ref], &queue)
+	}
+	return used
+}
+
+// componentValues maps each component $ref to its generic JSON value.
+func componentValues(c *openapi.Components) map[string]any {
+	values := make(map[string]any)
+	kinds, ok := toJSONValue(c).(map[string]any)
+	if !ok {
+		return values
+	}
+	for kind, entries := range kinds {
+		byName, ok := entries.(map[string]any)
+		if !ok {
+			continue
+		}
+		for name, v := range byName {
+			values[componentRef(kind, name)] = v
+		}
+	}
+	return values
+}
+
+func toJSONValue(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+// collectRefs appends every local component $ref found in v to refs.
+func collectRefs(v any, refs *[]string) {
+	switch val := v.(type) {
+	case map[string]any:
+		for key, child := range val {
+			if ref, ok := child.(string); ok && key == "$ref" && strings.HasPrefix(ref, componentsPrefix) {
+				*refs = append(*refs, ref)
+				continue
+			}
+			collectRefs(child, refs)
+		}
+	case []any:
+		for _, child := range val {
+			collectRefs(child, refs)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package lint
+
+import "github.com/fathurrohman26/yaswag/pkg/openapi"
+
+// Rule interface for extensible style checks
+type Rule interface {
+	ID() string
+	Name() string
+	Severity() Severity
+	Check(doc *openapi.Document) []Finding
+}
+
+// DefaultRules returns all built-in style rules
+func DefaultRules() []Rule {
+	return []Rule{
+		&OperationIDNamingRule{},
+		&DescriptionRule{},
+		&UnusedComponentRule{},
+		&DuplicateTagRule{},
+		&ClientErrorResponseRule{},
+		&PathCasingRule{},
+		&OwnerRule{},
+	}
+}
@@ -0,0 +1,246 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/shared"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+var (
+	camelCasePattern   = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+	kebabCasePattern   = regexp.MustCompile(`^[a-z0-9]+(?:[-.][a-z0-9]+)*$`)
+	pathParamPattern   = regexp.MustCompile(`^\{[^}]+\}$`)
+	clientErrorPattern = regexp.MustCompile(`^4(\d\d|XX)$`)
+)
+
+// OperationIDNamingRule checks every operation has a camelCase operationId
+type OperationIDNamingRule struct{}
+
+func (r *OperationIDNamingRule) ID() string         { return "OPERATION_ID_NAMING" }
+func (r *OperationIDNamingRule) Name() string       { return "operationId naming convention" }
+func (r *OperationIDNamingRule) Severity() Severity { return SeverityWarning }
+
+func (r *OperationIDNamingRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+	for _, entry := range shared.Operations(doc, sortedKeys(doc.Paths)) {
+		id := entry.Operation.OperationID
+		switch {
+		case id == "":
+			findings = append(findings, operationFinding(entry, r, "Operation has no operationId"))
+		case !camelCasePattern.MatchString(id):
+			findings = append(findings, operationFinding(entry, r, fmt.Sprintf("operationId '%s' is not camelCase", id)))
+		}
+	}
+	return findings
+}
+
+// DescriptionRule checks operations and component schemas are described
+type DescriptionRule struct{}
+
+func (r *DescriptionRule) ID() string         { return "DESCRIPTION_MISSING" }
+func (r *DescriptionRule) Name() string       { return "Missing description" }
+func (r *DescriptionRule) Severity() Severity { return SeverityInfo }
+
+func (r *DescriptionRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+	for _, entry := range shared.Operations(doc, sortedKeys(doc.Paths)) {
+		if entry.Operation.Summary == "" && entry.Operation.Description == "" {
+			findings = append(findings, operationFinding(entry, r, "Operation has neither a summary nor a description"))
+		}
+	}
+
+	if doc.Components == nil {
+		return findings
+	}
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		schema := doc.Components.Schemas[name]
+		if schema == nil || schema.Ref != "" || schema.Description != "" || schema.Title != "" {
+			continue
+		}
+		findings = append(findings, Finding{
+			RuleID:   r.ID(),
+			RuleName: r.Name(),
+			Severity: r.Severity(),
+			Location: fmt.Sprintf("Schema '%s'", name),
+			Pointer:  shared.JSONPointer("components", "schemas", name),
+			Message:  fmt.Sprintf("Schema '%s' has no description", name),
+		})
+	}
+	return findings
+}
+
+// UnusedComponentRule checks reusable components are referenced from paths or webhooks
+type UnusedComponentRule struct{}
+
+func (r *UnusedComponentRule) ID() string         { return "UNUSED_COMPONENT" }
+func (r *UnusedComponentRule) Name() string       { return "Unused component" }
+func (r *UnusedComponentRule) Severity() Severity { return SeverityWarning }
+
+func (r *UnusedComponentRule) Check(doc *openapi.Document) []Finding {
+	if doc.Components == nil {
+		return nil
+	}
+
+	used := reachableComponents(doc)
+	var findings []Finding
+	check := func(kind string, names []string) {
+		for _, name := range names {
+			if used[componentRef(kind, name)] {
+				continue
+			}
+			findings = append(findings, Finding{
+				RuleID:   r.ID(),
+				RuleName: r.Name(),
+				Severity: r.Severity(),
+				Location: fmt.Sprintf("components.%s.%s", kind, name),
+				Pointer:  shared.JSONPointer("components", kind, name),
+				Message:  fmt.Sprintf("Component '%s' is never referenced", name),
+			})
+		}
+	}
+
+	c := doc.Components
+	check("schemas", sortedKeys(c.Schemas))
+	check("parameters", sortedKeys(c.Parameters))
+	check("responses", sortedKeys(c.Responses))
+	check("requestBodies", sortedKeys(c.RequestBodies))
+	check("headers", sortedKeys(c.Headers))
+	return findings
+}
+
+// DuplicateTagRule checks tags are declared and used only once
+type DuplicateTagRule struct{}
+
+func (r *DuplicateTagRule) ID() string         { return "DUPLICATE_TAG" }
+func (r *DuplicateTagRule) Name() string       { return "Duplicate tag" }
+func (r *DuplicateTagRule) Severity() Severity { return SeverityWarning }
+
+func (r *DuplicateTagRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+
+	seen := make(map[string]bool)
+	for i, tag := range doc.Tags {
+		if seen[tag.Name] {
+			findings = append(findings, Finding{
+				RuleID:   r.ID(),
+				RuleName: r.Name(),
+				Severity: r.Severity(),
+				Location: fmt.Sprintf("Tag '%s'", tag.Name),
+				Pointer:  shared.JSONPointer("tags", fmt.Sprint(i)),
+				Message:  fmt.Sprintf("Tag '%s' is declared more than once", tag.Name),
+			})
+		}
+		seen[tag.Name] = true
+	}
+
+	for _, entry := range shared.Operations(doc, sortedKeys(doc.Paths)) {
+		opTags := make(map[string]bool)
+		for _, tag := range entry.Operation.Tags {
+			if opTags[tag] {
+				findings = append(findings, operationFinding(entry, r, fmt.Sprintf("Tag '%s' is listed more than once", tag)))
+			}
+			opTags[tag] = true
+		}
+	}
+	return findings
+}
+
+// ClientErrorResponseRule checks operations document at least one 4xx response
+type ClientErrorResponseRule struct{}
+
+func (r *ClientErrorResponseRule) ID() string         { return "MISSING_4XX_RESPONSE" }
+func (r *ClientErrorResponseRule) Name() string       { return "Missing 4xx response" }
+func (r *ClientErrorResponseRule) Severity() Severity { return SeverityWarning }
+
+func (r *ClientErrorResponseRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+	for _, entry := range shared.Operations(doc, sortedKeys(doc.Paths)) {
+		if hasClientErrorResponse(entry.Operation.Responses) {
+			continue
+		}
+		findings = append(findings, operationFinding(entry, r, "Operation documents no 4xx or default response"))
+	}
+	return findings
+}
+
+func hasClientErrorResponse(responses openapi.Responses) bool {
+	for code := range responses {
+		if code == "default" || clientErrorPattern.MatchString(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// PathCasingRule checks static path segments are lowercase kebab-case
+type PathCasingRule struct{}
+
+func (r *PathCasingRule) ID() string         { return "PATH_CASING" }
+func (r *PathCasingRule) Name() string       { return "Path not kebab-case" }
+func (r *PathCasingRule) Severity() Severity { return SeverityWarning }
+
+func (r *PathCasingRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+	for _, p := range sortedKeys(doc.Paths) {
+		for _, segment := range strings.Split(strings.Trim(p, "/"), "/") {
+			if segment == "" || pathParamPattern.MatchString(segment) || kebabCasePattern.MatchString(segment) {
+				continue
+			}
+			findings = append(findings, Finding{
+				RuleID:   r.ID(),
+				RuleName: r.Name(),
+				Severity: r.Severity(),
+				Location: p,
+				Pointer:  shared.JSONPointer("paths", p),
+				Path:     p,
+				Message:  fmt.Sprintf("Path segment '%s' is not lowercase kebab-case", segment),
+			})
+			break
+		}
+	}
+	return findings
+}
+
+// OwnerRule checks every operation has an owner, declared on the operation or one of its tags.
+// It is off by default; enable it with a severity override to enforce ownership.
+type OwnerRule struct{}
+
+func (r *OwnerRule) ID() string         { return "OWNER_MISSING" }
+func (r *OwnerRule) Name() string       { return "Missing owner" }
+func (r *OwnerRule) Severity() Severity { return SeverityOff }
+
+func (r *OwnerRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+	for _, entry := range shared.Operations(doc, sortedKeys(doc.Paths)) {
+		if doc.OwnerOf(entry.Operation) == nil {
+			findings = append(findings, operationFinding(entry, r, "Operation has no x-owner, and none of its tags declares one"))
+		}
+	}
+	return findings
+}
+
+// operationFinding returns a finding of rule about the operation o.
+func operationFinding(o shared.Operation, rule Rule, message string) Finding {
+	return Finding{
+		RuleID:   rule.ID(),
+		RuleName: rule.Name(),
+		Severity: rule.Severity(),
+		Location: fmt.Sprintf("%s %s", o.Method, o.Path),
+		Pointer:  shared.JSONPointer("paths", o.Path, strings.ToLower(o.Method)),
+		Path:     o.Path,
+		Message:  message,
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
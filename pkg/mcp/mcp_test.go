@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -337,27 +338,42 @@ func TestGetEndpointHandler(t *testing.T) {
 	}
 }
 
-func TestGenerateStringExample(t *testing.T) {
-	tests := []struct {
-		format   string
-		expected string
-	}{
-		{"date", "2024-01-15"},
-		{"date-time", "2024-01-15T10:30:00Z"},
-		{"email", "user@example.com"},
-		{"uri", "https://example.com"},
-		{"uuid", "550e8400-e29b-41d4-a716-446655440000"},
-		{"", "string"},
+func TestExtractExampleFromContent(t *testing.T) {
+	doc := &openapi.Document{
+		Components: &openapi.Components{
+			Schemas: map[string]*openapi.Schema{
+				"Category": {
+					Type: openapi.NewSchemaType(openapi.TypeObject),
+					Properties: map[string]*openapi.Schema{
+						"name":     openapi.StringSchema(),
+						"children": openapi.ArraySchema(openapi.RefTo("Category")),
+					},
+				},
+			},
+		},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.format, func(t *testing.T) {
-			schema := &openapi.Schema{Format: tt.format}
-			result := generateStringExample(schema)
-			if result != tt.expected {
-				t.Errorf("generateStringExample with format %s = %s, want %s", tt.format, result, tt.expected)
-			}
-		})
+	gen := openapi.NewExampleGenerator(doc, 7)
+
+	declared := map[string]openapi.MediaType{"application/json": {Example: map[string]any{"name": "Dogs"}}}
+	if example, ok := extractExampleFromContent(declared, gen).(map[string]any); !ok || example["name"] != "Dogs" {
+		t.Errorf("expected the declared example, got %#v", example)
+	}
+
+	// a recursive schema must not recurse forever
+	recursive := map[string]openapi.MediaType{"application/json": {Schema: openapi.RefTo("Category")}}
+	example, ok := extractExampleFromContent(recursive, gen).(map[string]any)
+	if !ok {
+		t.Fatalf("expected an object example, got %#v", example)
+	}
+	if name, _ := example["name"].(string); name == "" || name == "string" {
+		t.Errorf("name = %v, want a generated name", example["name"])
+	}
+
+	// the same seed gives the same example
+	again := extractExampleFromContent(recursive, openapi.NewExampleGenerator(doc, 7))
+	if !reflect.DeepEqual(example, again) {
+		t.Errorf("examples differ for the same seed:\n%#v\n%#v", example, again)
 	}
 }
 
@@ -7,7 +7,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"math/rand/v2"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 
@@ -23,12 +26,17 @@ type Server struct {
 	mcpServer *server.MCPServer
 	specPaths []string
 	specData  []byte // in-memory spec data (for stdin)
+
+	// ExampleSeed seeds the data of generate_example, so the same seed gives
+	// the same examples on every run; it is random unless set
+	ExampleSeed uint64
 }
 
 // NewServer creates a new MCP server for OpenAPI interactions
 func NewServer(specPaths []string) *Server {
 	s := &Server{
-		specPaths: specPaths,
+		specPaths:   specPaths,
+		ExampleSeed: rand.Uint64(),
 	}
 
 	// Create MCP server with tool capabilities
@@ -48,7 +56,8 @@ func NewServer(specPaths []string) *Server {
 // NewServerWithData creates a new MCP server with in-memory spec data
 func NewServerWithData(data []byte) *Server {
 	s := &Server{
-		specData: data,
+		specData:    data,
+		ExampleSeed: rand.Uint64(),
 	}
 
 	s.mcpServer = server.NewMCPServer(
@@ -211,6 +220,9 @@ func (s *Server) registerTools() {
 			mcp.WithString("status_code",
 				mcp.Description("Response status code for response examples (default: 200)"),
 			),
+			mcp.WithNumber("seed",
+				mcp.Description("Seed of the example data, for the same example every time (default: the server's seed)"),
+			),
 		),
 		s.handleGenerateExample,
 	)
@@ -832,6 +844,10 @@ func (s *Server) handleGenerateExample(ctx context.Context, req mcp.CallToolRequ
 	method, _ := req.RequireString("method")
 	exampleType := getString(req, "type", "response")
 	statusCode := getString(req, "status_code", "200")
+	seed := s.ExampleSeed
+	if v, err := req.RequireFloat("seed"); err == nil {
+		seed = uint64(v)
+	}
 
 	doc, err := s.loadSpec()
 	if err != nil {
@@ -848,7 +864,7 @@ func (s *Server) handleGenerateExample(ctx context.Context, req mcp.CallToolRequ
 		return mcp.NewToolResultText(fmt.Sprintf("Method %s not found for path %s", method, path)), nil
 	}
 
-	example, errMsg := generateEndpointExample(op, exampleType, statusCode, doc)
+	example, errMsg := generateEndpointExample(op, exampleType, statusCode, openapi.NewExampleGenerator(doc, seed))
 	if errMsg != "" {
 		return mcp.NewToolResultText(errMsg), nil
 	}
@@ -862,135 +878,41 @@ func (s *Server) handleGenerateExample(ctx context.Context, req mcp.CallToolRequ
 }
 
 // generateEndpointExample generates example for request or response
-func generateEndpointExample(op *openapi.Operation, exampleType, statusCode string, doc *openapi.Document) (any, string) {
+func generateEndpointExample(op *openapi.Operation, exampleType, statusCode string, gen *openapi.ExampleGenerator) (any, string) {
 	if exampleType == "request" {
-		return generateRequestExample(op, doc)
+		return generateRequestExample(op, gen)
 	}
-	return generateResponseExample(op, statusCode, doc)
+	return generateResponseExample(op, statusCode, gen)
 }
 
 // generateRequestExample generates example from request body
-func generateRequestExample(op *openapi.Operation, doc *openapi.Document) (any, string) {
+func generateRequestExample(op *openapi.Operation, gen *openapi.ExampleGenerator) (any, string) {
 	if op.RequestBody == nil {
 		return nil, "No request body defined for this endpoint."
 	}
-	return extractExampleFromContent(op.RequestBody.Content, doc), ""
+	return extractExampleFromContent(op.RequestBody.Content, gen), ""
 }
 
 // generateResponseExample generates example from response
-func generateResponseExample(op *openapi.Operation, statusCode string, doc *openapi.Document) (any, string) {
+func generateResponseExample(op *openapi.Operation, statusCode string, gen *openapi.ExampleGenerator) (any, string) {
 	resp, ok := op.Responses[statusCode]
 	if !ok {
 		return nil, fmt.Sprintf("No response defined for status code %s", statusCode)
 	}
-	return extractExampleFromContent(resp.Content, doc), ""
+	return extractExampleFromContent(resp.Content, gen), ""
 }
 
 // extractExampleFromContent extracts example from content map
-func extractExampleFromContent(content map[string]openapi.MediaType, doc *openapi.Document) any {
-	for _, mediaType := range content {
-		if mediaType.Schema != nil {
-			return generateSchemaExample(mediaType.Schema, doc)
+func extractExampleFromContent(content map[string]openapi.MediaType, gen *openapi.ExampleGenerator) any {
+	// in order, so the example is the same every time
+	for _, contentType := range slices.Sorted(maps.Keys(content)) {
+		if example := gen.MediaTypeExample(content[contentType]); example != nil {
+			return example
 		}
 	}
 	return nil
 }
 
-// generateSchemaExample generates example data from a schema
-func generateSchemaExample(schema *openapi.Schema, doc *openapi.Document) any {
-	if schema == nil {
-		return nil
-	}
-
-	if resolved := resolveSchemaRef(schema, doc); resolved != nil {
-		return generateSchemaExample(resolved, doc)
-	}
-
-	if schema.Example != nil {
-		return schema.Example
-	}
-
-	if len(schema.Type) == 0 {
-		return nil
-	}
-
-	return generateExampleByType(schema.Type[0], schema, doc)
-}
-
-// resolveSchemaRef resolves a $ref to the actual schema, returns nil if not a ref
-func resolveSchemaRef(schema *openapi.Schema, doc *openapi.Document) *openapi.Schema {
-	if schema.Ref == "" {
-		return nil
-	}
-	refName := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
-	if doc.Components != nil && doc.Components.Schemas != nil {
-		if refSchema, ok := doc.Components.Schemas[refName]; ok {
-			return refSchema
-		}
-	}
-	return nil
-}
-
-// generateExampleByType generates an example value based on schema type
-func generateExampleByType(schemaType string, schema *openapi.Schema, doc *openapi.Document) any {
-	switch schemaType {
-	case "string":
-		return generateStringExample(schema)
-	case "integer":
-		return 1
-	case "number":
-		return 1.0
-	case "boolean":
-		return true
-	case "array":
-		return generateArrayExample(schema, doc)
-	case "object":
-		return generateObjectExample(schema, doc)
-	default:
-		return nil
-	}
-}
-
-// generateArrayExample generates an example array
-func generateArrayExample(schema *openapi.Schema, doc *openapi.Document) []any {
-	if schema.Items != nil {
-		return []any{generateSchemaExample(schema.Items, doc)}
-	}
-	return []any{}
-}
-
-// generateObjectExample generates an example object
-func generateObjectExample(schema *openapi.Schema, doc *openapi.Document) map[string]any {
-	obj := make(map[string]any)
-	for propName, propSchema := range schema.Properties {
-		obj[propName] = generateSchemaExample(propSchema, doc)
-	}
-	return obj
-}
-
-// generateStringExample generates example string based on format
-func generateStringExample(schema *openapi.Schema) string {
-	if len(schema.Enum) > 0 {
-		if s, ok := schema.Enum[0].(string); ok {
-			return s
-		}
-	}
-	switch schema.Format {
-	case "date":
-		return "2024-01-15"
-	case "date-time":
-		return "2024-01-15T10:30:00Z"
-	case "email":
-		return "user@example.com"
-	case "uri":
-		return "https://example.com"
-	case "uuid":
-		return "550e8400-e29b-41d4-a716-446655440000"
-	default:
-		return "string"
-	}
-}
-
 // relatedEndpoint represents a related endpoint
 type relatedEndpoint struct {
 	Path    string `json:"path"`
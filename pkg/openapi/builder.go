@@ -0,0 +1,364 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Builder builds a Document with chainable methods, as an alternative to
+// composing struct literals. Mistakes such as a duplicate operationId, an
+// undeclared path parameter, or a reference to a missing component are
+// recorded as they are made and reported together by Build.
+type Builder struct {
+	doc  *Document
+	errs []error
+}
+
+// NewBuilder returns a Builder for an OpenAPI 3.0 document.
+func NewBuilder() *Builder {
+	return &Builder{doc: &Document{OpenAPI: Version30, Paths: Paths{}}}
+}
+
+// OpenAPI sets the OpenAPI version of the document (default: 3.0.3).
+func (b *Builder) OpenAPI(version string) *Builder {
+	b.doc.OpenAPI = version
+	return b
+}
+
+// Title sets the title of the API.
+func (b *Builder) Title(title string) *Builder {
+	b.doc.Info.Title = title
+	return b
+}
+
+// Version sets the version of the API.
+func (b *Builder) Version(version string) *Builder {
+	b.doc.Info.Version = version
+	return b
+}
+
+// Description sets the description of the API.
+func (b *Builder) Description(description string) *Builder {
+	b.doc.Info.Description = description
+	return b
+}
+
+// Server adds a server the API is available at.
+func (b *Builder) Server(url, description string) *Builder {
+	b.doc.Servers = append(b.doc.Servers, Server{URL: url, Description: description})
+	return b
+}
+
+// Tag declares a tag with its description.
+func (b *Builder) Tag(name, description string) *Builder {
+	if slices.ContainsFunc(b.doc.Tags, func(t Tag) bool { return t.Name == name }) {
+		return b.fail("tag %q declared twice", name)
+	}
+	b.doc.Tags = append(b.doc.Tags, Tag{Name: name, Description: description})
+	return b
+}
+
+// Schema adds a component schema, which operations reference with RefTo(name).
+func (b *Builder) Schema(name string, schema *Schema) *Builder {
+	if _, ok := b.components().Schemas[name]; ok {
+		return b.fail("schema %q added twice", name)
+	}
+	keepComponent(&b.doc.Components.Schemas, name, schema)
+	return b
+}
+
+// SecurityScheme adds a component security scheme.
+func (b *Builder) SecurityScheme(name string, scheme *SecurityScheme) *Builder {
+	if _, ok := b.components().SecuritySchemes[name]; ok {
+		return b.fail("security scheme %q added twice", name)
+	}
+	keepComponent(&b.doc.Components.SecuritySchemes, name, scheme)
+	return b
+}
+
+// Security requires one of the security schemes by default for every
+// operation, with the given scopes.
+func (b *Builder) Security(scheme string, scopes ...string) *Builder {
+	b.doc.Security = append(b.doc.Security, SecurityRequirement{scheme: nonNil(scopes)})
+	return b
+}
+
+// AddOperation adds the operation op for method, such as GET, on the path
+// template path, such as /users/{id}.
+func (b *Builder) AddOperation(method, path string, op *OperationBuilder) *Builder {
+	where := strings.ToUpper(method) + " " + path
+	for _, err := range op.check(path) {
+		b.errs = append(b.errs, fmt.Errorf("%s: %w", where, err))
+	}
+	if !strings.HasPrefix(path, "/") {
+		return b.fail("%s: path must start with /", where)
+	}
+	item := b.doc.Paths[path]
+	if item == nil {
+		item = &PathItem{}
+	}
+	slot := item.operation(method)
+	switch {
+	case slot == nil:
+		return b.fail("%s: unknown method %q", where, method)
+	case *slot != nil:
+		return b.fail("%s: operation added twice", where)
+	case op.op.OperationID != "" && b.hasOperationID(op.op.OperationID):
+		return b.fail("%s: operationId %q already used", where, op.op.OperationID)
+	}
+	*slot = op.op
+	if b.doc.Paths[path] == nil {
+		b.doc.Paths[path] = item
+		b.doc.PathOrder = append(b.doc.PathOrder, path)
+	}
+	return b
+}
+
+// Build returns the document, or the mistakes made building it.
+func (b *Builder) Build() (*Document, error) {
+	errs := slices.Clone(b.errs)
+	if b.doc.Info.Title == "" {
+		errs = append(errs, errors.New("title is required"))
+	}
+	if b.doc.Info.Version == "" {
+		errs = append(errs, errors.New("version is required"))
+	}
+	missing, err := b.missingComponents()
+	if err != nil {
+		return nil, err
+	}
+	errs = append(errs, missing...)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return b.doc, nil
+}
+
+// MustBuild is like Build but panics on mistakes, for documents built in
+// package-level variables or program startup.
+func (b *Builder) MustBuild() *Document {
+	doc, err := b.Build()
+	if err != nil {
+		panic("openapi: " + err.Error())
+	}
+	return doc
+}
+
+func (b *Builder) fail(format string, args ...any) *Builder {
+	b.errs = append(b.errs, fmt.Errorf(format, args...))
+	return b
+}
+
+func (b *Builder) components() *Components {
+	if b.doc.Components == nil {
+		b.doc.Components = &Components{}
+	}
+	return b.doc.Components
+}
+
+func (b *Builder) hasOperationID(id string) bool {
+	for _, item := range b.doc.Paths {
+		if slices.ContainsFunc(pathItemOperations(item), func(op *Operation) bool { return op.OperationID == id }) {
+			return true
+		}
+	}
+	return false
+}
+
+// missingComponents reports the components and security schemes the document
+// references but does not define.
+func (b *Builder) missingComponents() ([]error, error) {
+	components := b.components()
+	refs, err := componentRefs(b.doc)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, ref := range refs {
+		kind, name, _ := strings.Cut(strings.TrimPrefix(ref, "#/components/"), "/")
+		if (&Components{}).keep(components, kind, name) == nil {
+			errs = append(errs, fmt.Errorf("%s is not defined", ref))
+		}
+	}
+	for _, name := range b.doc.securitySchemeNames() {
+		if _, ok := components.SecuritySchemes[name]; !ok {
+			errs = append(errs, fmt.Errorf("security scheme %q is not defined", name))
+		}
+	}
+	return errs, nil
+}
+
+// operation returns the field of the path item holding the operation for
+// method, or nil for an unknown method.
+func (p *PathItem) operation(method string) **Operation {
+	slots := map[string]**Operation{
+		"GET": &p.Get, "PUT": &p.Put, "POST": &p.Post, "DELETE": &p.Delete,
+		"OPTIONS": &p.Options, "HEAD": &p.Head, "PATCH": &p.Patch, "TRACE": &p.Trace,
+	}
+	return slots[strings.ToUpper(method)]
+}
+
+// OperationBuilder builds an Operation for Builder.AddOperation.
+type OperationBuilder struct {
+	op   *Operation
+	errs []error
+}
+
+// NewOperation returns an OperationBuilder for the operation with operationId id.
+func NewOperation(id string) *OperationBuilder {
+	return &OperationBuilder{op: &Operation{OperationID: id, Responses: Responses{}}}
+}
+
+// Summary sets the summary of the operation.
+func (o *OperationBuilder) Summary(summary string) *OperationBuilder {
+	o.op.Summary = summary
+	return o
+}
+
+// Description sets the description of the operation.
+func (o *OperationBuilder) Description(description string) *OperationBuilder {
+	o.op.Description = description
+	return o
+}
+
+// Tags adds tags to the operation.
+func (o *OperationBuilder) Tags(tags ...string) *OperationBuilder {
+	o.op.Tags = append(o.op.Tags, tags...)
+	return o
+}
+
+// Deprecated marks the operation as deprecated.
+func (o *OperationBuilder) Deprecated() *OperationBuilder {
+	o.op.Deprecated = true
+	return o
+}
+
+// Parameter adds a parameter. Path parameters are always required.
+func (o *OperationBuilder) Parameter(param *Parameter) *OperationBuilder {
+	if param.Ref == "" && slices.ContainsFunc(o.op.Parameters, func(p *Parameter) bool {
+		return p.Name == param.Name && p.In == param.In
+	}) {
+		return o.fail("%s parameter %q added twice", param.In, param.Name)
+	}
+	if param.In == ParameterInPath {
+		param.Required = true
+	}
+	o.op.Parameters = append(o.op.Parameters, param)
+	return o
+}
+
+// PathParam adds a path parameter, which must appear in the path template.
+func (o *OperationBuilder) PathParam(name string, schema *Schema, description string) *OperationBuilder {
+	return o.Parameter(&Parameter{Name: name, In: ParameterInPath, Schema: schema, Description: description})
+}
+
+// QueryParam adds an optional query parameter.
+func (o *OperationBuilder) QueryParam(name string, schema *Schema, description string) *OperationBuilder {
+	return o.Parameter(&Parameter{Name: name, In: ParameterInQuery, Schema: schema, Description: description})
+}
+
+// HeaderParam adds an optional header parameter.
+func (o *OperationBuilder) HeaderParam(name string, schema *Schema, description string) *OperationBuilder {
+	return o.Parameter(&Parameter{Name: name, In: ParameterInHeader, Schema: schema, Description: description})
+}
+
+// Body sets the required request body, of the given content type.
+func (o *OperationBuilder) Body(contentType string, schema *Schema, description string) *OperationBuilder {
+	if o.op.RequestBody != nil {
+		return o.fail("request body set twice")
+	}
+	o.op.RequestBody = &RequestBody{
+		Description: description,
+		Required:    true,
+		Content:     map[string]MediaType{contentType: {Schema: schema}},
+	}
+	return o
+}
+
+// JSONBody sets the required application/json request body.
+func (o *OperationBuilder) JSONBody(schema *Schema, description string) *OperationBuilder {
+	return o.Body("application/json", schema, description)
+}
+
+// Response adds a response without content for status, such as 204 or default.
+func (o *OperationBuilder) Response(status, description string) *OperationBuilder {
+	return o.addResponse(status, &Response{Description: description})
+}
+
+// JSONResponse adds an application/json response for status.
+func (o *OperationBuilder) JSONResponse(status string, schema *Schema, description string) *OperationBuilder {
+	return o.addResponse(status, &Response{
+		Description: description,
+		Content:     map[string]MediaType{"application/json": {Schema: schema}},
+	})
+}
+
+// Security requires the security scheme for the operation, with the given
+// scopes. Adding several requirements accepts any one of them.
+func (o *OperationBuilder) Security(scheme string, scopes ...string) *OperationBuilder {
+	o.op.Security = append(o.op.Security, SecurityRequirement{scheme: nonNil(scopes)})
+	return o
+}
+
+// statusPattern matches the keys of Responses.
+var statusPattern = regexp.MustCompile(`^(default|[1-5](\d\d|XX))$`)
+
+func (o *OperationBuilder) addResponse(status string, response *Response) *OperationBuilder {
+	if !statusPattern.MatchString(status) {
+		return o.fail("invalid response status %q", status)
+	}
+	if _, ok := o.op.Responses[status]; ok {
+		return o.fail("response %s added twice", status)
+	}
+	o.op.Responses[status] = response
+	o.op.ResponseOrder = append(o.op.ResponseOrder, status)
+	return o
+}
+
+func (o *OperationBuilder) fail(format string, args ...any) *OperationBuilder {
+	o.errs = append(o.errs, fmt.Errorf(format, args...))
+	return o
+}
+
+// check returns the mistakes made building the operation for path.
+func (o *OperationBuilder) check(path string) []error {
+	errs := slices.Clone(o.errs)
+	if len(o.op.Responses) == 0 {
+		errs = append(errs, errors.New("no responses"))
+	}
+	for _, name := range pathTemplateParams(path) {
+		if !slices.ContainsFunc(o.op.Parameters, func(p *Parameter) bool { return p.In == ParameterInPath && p.Name == name }) {
+			errs = append(errs, fmt.Errorf("path parameter %q is not declared", name))
+		}
+	}
+	for _, param := range o.op.Parameters {
+		if param.In == ParameterInPath && !slices.Contains(pathTemplateParams(path), param.Name) {
+			errs = append(errs, fmt.Errorf("path parameter %q is not in the path", param.Name))
+		}
+	}
+	return errs
+}
+
+// pathTemplatePattern matches the parameters of a path template.
+var pathTemplatePattern = regexp.MustCompile(`\{([^}/]+)\}`)
+
+// pathTemplateParams returns the names of the parameters in the path template.
+func pathTemplateParams(path string) []string {
+	var names []string
+	for _, match := range pathTemplatePattern.FindAllStringSubmatch(path, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// nonNil returns s, or an empty slice if s is nil, for the scopes of
+// security requirements, which are written as [] rather than null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
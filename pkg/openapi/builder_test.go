@@ -0,0 +1,95 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	doc, err := NewBuilder().
+		Title("Pet Store").
+		Version("1.0.0").
+		Server("https://api.example.com", "Production").
+		Tag("pets", "Pet operations").
+		Schema("Pet", ObjectSchema()).
+		SecurityScheme("api_key", &SecurityScheme{Type: "apiKey", Name: "X-API-Key", In: "header"}).
+		AddOperation("get", "/pets/{id}", NewOperation("getPet").
+			Tags("pets").
+			PathParam("id", IntegerSchema(), "Pet ID").
+			JSONResponse("200", RefTo("Pet"), "The pet").
+			Response("404", "Not found").
+			Security("api_key")).
+		AddOperation("POST", "/pets", NewOperation("addPet").
+			JSONBody(RefTo("Pet"), "Pet to add").
+			Response("201", "Created")).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if got := strings.Join(doc.PathOrder, ","); got != "/pets/{id},/pets" {
+		t.Errorf("path order = %s", got)
+	}
+	get := doc.Paths["/pets/{id}"].Get
+	if get == nil || get.OperationID != "getPet" {
+		t.Fatalf("GET /pets/{id} = %+v", get)
+	}
+	if !get.Parameters[0].Required {
+		t.Error("path parameter is not required")
+	}
+	if got := strings.Join(get.ResponseOrder, ","); got != "200,404" {
+		t.Errorf("response order = %s", got)
+	}
+	if post := doc.Paths["/pets"].Post; post == nil || !post.RequestBody.Required {
+		t.Errorf("POST /pets = %+v", post)
+	}
+}
+
+func TestBuilder_Mistakes(t *testing.T) {
+	_, err := NewBuilder().
+		Title("API").
+		Tag("pets", "").
+		Tag("pets", "").
+		AddOperation("GET", "/pets/{id}", NewOperation("getPet").
+			QueryParam("limit", IntegerSchema(), "").
+			JSONResponse("200", RefTo("Pet"), "")).
+		AddOperation("GET", "/pets/{id}", NewOperation("getPetAgain").Response("200", "")).
+		AddOperation("GET", "/pets", NewOperation("getPet").Response("200", "")).
+		AddOperation("FETCH", "/things", NewOperation("fetch").Response("200", "")).
+		AddOperation("GET", "owners", NewOperation("listOwners").
+			PathParam("id", IntegerSchema(), "").
+			Response("2000", "")).
+		AddOperation("DELETE", "/pets", NewOperation("").Security("oauth")).
+		Build()
+	if err == nil {
+		t.Fatal("Build() succeeded")
+	}
+
+	for _, want := range []string{
+		"version is required",
+		`tag "pets" declared twice`,
+		`GET /pets/{id}: path parameter "id" is not declared`,
+		"GET /pets/{id}: operation added twice",
+		`GET /pets: operationId "getPet" already used`,
+		`FETCH /things: unknown method "FETCH"`,
+		"GET owners: path must start with /",
+		`GET owners: path parameter "id" is not in the path`,
+		`GET owners: invalid response status "2000"`,
+		"DELETE /pets: no responses",
+		"#/components/schemas/Pet is not defined",
+		`security scheme "oauth" is not defined`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestBuilder_MustBuild(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustBuild() did not panic")
+		}
+	}()
+	NewBuilder().Version("1.0.0").MustBuild()
+}
@@ -0,0 +1,263 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Target OpenAPI versions produced by the converters.
+const (
+	Version30 = "3.0.3"
+	Version31 = "3.1.0"
+)
+
+// ConvertTo31 returns a copy of doc translated to OpenAPI 3.1.
+//
+// The conversion rewrites nullable schemas into type arrays (or anyOf with a
+// null schema for $refs), turns boolean exclusiveMinimum/exclusiveMaximum into
+// numeric bounds, and moves schema example values into examples.
+// The input document is not modified.
+func ConvertTo31(doc *Document) (*Document, error) {
+	out, err := cloneDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	out.OpenAPI = Version31
+	walkDocumentSchemas(out, schemaTo31)
+	return out, nil
+}
+
+// ConvertTo30 returns a copy of doc translated to OpenAPI 3.0.
+//
+// The conversion rewrites type arrays containing "null" into nullable schemas,
+// turns numeric exclusiveMinimum/exclusiveMaximum into minimum/maximum with a
+// boolean modifier, and moves the first schema examples entry into example.
+// Constructs without a 3.0 equivalent (webhooks, info summary, license
+// identifier, components.pathItems, mutualTLS security schemes and the
+// security requirements that reference them) are dropped.
+// The input document is not modified.
+func ConvertTo30(doc *Document) (*Document, error) {
+	out, err := cloneDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	out.OpenAPI = Version30
+	out.Webhooks = nil
+	out.Info.Summary = ""
+	if out.Info.License != nil {
+		out.Info.License.Identifier = ""
+	}
+	if out.Components != nil {
+		out.Components.PathItems = nil
+	}
+	dropMutualTLS(out)
+	walkDocumentSchemas(out, schemaTo30)
+	return out, nil
+}
+
+// dropMutualTLS removes mutualTLS security schemes, which OpenAPI 3.0 does
+// not define, along with every security requirement that lists one.
+func dropMutualTLS(doc *Document) {
+	dropped := deleteMutualTLSSchemes(doc.Components)
+	if len(dropped) == 0 {
+		return
+	}
+
+	usesDropped := func(requirement SecurityRequirement) bool {
+		for name := range requirement {
+			if dropped[name] {
+				return true
+			}
+		}
+		return false
+	}
+	doc.Security = slices.DeleteFunc(doc.Security, usesDropped)
+	for _, item := range doc.Paths {
+		if item == nil {
+			continue
+		}
+		for _, op := range pathItemOperations(item) {
+			op.Security = slices.DeleteFunc(op.Security, usesDropped)
+		}
+	}
+}
+
+// deleteMutualTLSSchemes deletes the mutualTLS security schemes of c and returns their names.
+func deleteMutualTLSSchemes(c *Components) map[string]bool {
+	dropped := make(map[string]bool)
+	if c == nil {
+		return dropped
+	}
+	for name, scheme := range c.SecuritySchemes {
+		if scheme != nil && scheme.Type == "mutualTLS" {
+			dropped[name] = true
+			delete(c.SecuritySchemes, name)
+		}
+	}
+	return dropped
+}
+
+// cloneDocument creates a deep copy of a document.
+func cloneDocument(doc *Document) (*Document, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("document is nil")
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy document: %w", err)
+	}
+	var out Document
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to copy document: %w", err)
+	}
+	return &out, nil
+}
+
+// NullableToTypeNull rewrites the nullable schemas of doc in place into the
+// OpenAPI 3.1 form: type arrays including "null", or anyOf with a null
+// schema for $refs and compositions. ConvertTo31 does this along with its other changes.
+func NullableToTypeNull(doc *Document) {
+	walkDocumentSchemas(doc, nullableTo31)
+}
+
+// NullableRefToAllOf rewrites the nullable $ref schemas of doc in place into
+// the OpenAPI 3.0 form, an allOf of the reference beside nullable, because 3.0
+// ignores the siblings of $ref. ConvertTo30 does this along with its other changes.
+func NullableRefToAllOf(doc *Document) {
+	walkDocumentSchemas(doc, wrapNullableRef)
+}
+
+// wrapNullableRef moves the $ref of a nullable schema into its allOf.
+func wrapNullableRef(s *Schema) {
+	if s.Nullable && s.Ref != "" {
+		s.AllOf = append([]*Schema{{Ref: s.Ref}}, s.AllOf...)
+		s.Ref = ""
+	}
+}
+
+func schemaTo31(s *Schema) {
+	nullableTo31(s)
+	s.ExclusiveMinimum, s.Minimum = exclusiveTo31(s.ExclusiveMinimum, s.Minimum)
+	s.ExclusiveMaximum, s.Maximum = exclusiveTo31(s.ExclusiveMaximum, s.Maximum)
+	if s.Example != nil && len(s.Examples) == 0 {
+		s.Examples = []any{s.Example}
+		s.Example = nil
+	}
+}
+
+// nullableTo31 rewrites a nullable schema into a type array including "null",
+// a oneOf or anyOf with a null schema, or else an anyOf of the $ref or
+// composition of the schema and a null schema. Its other keywords, such as
+// title and example, stay on the rewritten schema.
+func nullableTo31(s *Schema) {
+	if !s.Nullable {
+		return
+	}
+	s.Nullable = false
+	null := &Schema{Type: NewSchemaType(TypeNull)}
+	switch {
+	case s.Ref != "":
+		s.AnyOf = []*Schema{{Ref: s.Ref}, null}
+		s.Ref = ""
+	case alternatives(s) != nil:
+		group := alternatives(s)
+		*group = append(*group, null)
+	case len(s.AllOf) > 0 || len(s.OneOf) > 0 || len(s.AnyOf) > 0:
+		s.AnyOf = []*Schema{takeComposition(s), null}
+	case len(s.Type) > 0 && !slices.Contains(s.Type, TypeNull):
+		s.Type = append(s.Type, TypeNull)
+	}
+}
+
+// alternatives returns the oneOf or anyOf of s if it is the only type or
+// composition keyword of s, so that a null schema can be another alternative.
+func alternatives(s *Schema) *[]*Schema {
+	if len(s.Type) > 0 || len(s.AllOf) > 0 || (len(s.OneOf) > 0) == (len(s.AnyOf) > 0) {
+		return nil
+	}
+	if len(s.OneOf) > 0 {
+		return &s.OneOf
+	}
+	return &s.AnyOf
+}
+
+// takeComposition moves the type and composition keywords of s into the
+// schema they form, a lone allOf subschema, such as the $ref collapseNullAnyOf
+// wraps, as is.
+func takeComposition(s *Schema) *Schema {
+	sub := &Schema{Type: s.Type, AllOf: s.AllOf, OneOf: s.OneOf, AnyOf: s.AnyOf}
+	if len(s.AllOf) == 1 && len(s.Type) == 0 && len(s.OneOf) == 0 && len(s.AnyOf) == 0 {
+		sub = s.AllOf[0]
+	}
+	s.Type, s.AllOf, s.OneOf, s.AnyOf = nil, nil, nil, nil
+	return sub
+}
+
+// exclusiveTo31 converts a boolean exclusive modifier and its bound into a numeric exclusive bound.
+func exclusiveTo31(excl *ExclusiveBound, bound *float64) (*ExclusiveBound, *float64) {
+	if excl == nil || excl.Value != nil {
+		return excl, bound
+	}
+	if !excl.Exclusive || bound == nil {
+		return nil, bound
+	}
+	return ExclusiveValue(*bound), nil
+}
+
+func schemaTo30(s *Schema) {
+	nullableTo30(s)
+	s.ExclusiveMinimum, s.Minimum = exclusiveTo30(s.ExclusiveMinimum, s.Minimum)
+	s.ExclusiveMaximum, s.Maximum = exclusiveTo30(s.ExclusiveMaximum, s.Maximum)
+	if s.Example == nil && len(s.Examples) > 0 {
+		s.Example = s.Examples[0]
+	}
+	s.Examples = nil
+}
+
+func nullableTo30(s *Schema) {
+	// A bare {type: null} is left for the parent's anyOf/oneOf to collapse
+	if len(s.Type) > 1 && slices.Contains(s.Type, TypeNull) {
+		s.Nullable = true
+		s.Type = slices.DeleteFunc(slices.Clone(s.Type), func(t string) bool { return t == TypeNull })
+	}
+	if len(s.Type) > 1 {
+		// 3.0 only allows a single type, so split multiple types into anyOf
+		for _, t := range s.Type {
+			s.AnyOf = append(s.AnyOf, &Schema{Type: NewSchemaType(t)})
+		}
+		s.Type = nil
+	}
+	collapseNullAnyOf(s)
+	wrapNullableRef(s)
+}
+
+// collapseNullAnyOf rewrites anyOf/oneOf [X, {type: null}] into a nullable X.
+func collapseNullAnyOf(s *Schema) {
+	for _, group := range []*[]*Schema{&s.AnyOf, &s.OneOf} {
+		idx := slices.IndexFunc(*group, isNullSchema)
+		if idx < 0 {
+			continue
+		}
+		*group = slices.Delete(*group, idx, idx+1)
+		s.Nullable = true
+		if len(*group) == 1 && (*group)[0].Ref != "" {
+			// $ref siblings are ignored in 3.0, so wrap the reference in allOf
+			s.AllOf = append(s.AllOf, (*group)[0])
+			*group = nil
+		}
+	}
+}
+
+func isNullSchema(s *Schema) bool {
+	return s != nil && len(s.Type) == 1 && s.Type[0] == TypeNull
+}
+
+// exclusiveTo30 converts a numeric exclusive bound into a bound with a boolean exclusive modifier.
+func exclusiveTo30(excl *ExclusiveBound, bound *float64) (*ExclusiveBound, *float64) {
+	if excl == nil || excl.Value == nil {
+		return excl, bound
+	}
+	v := *excl.Value
+	return ExclusiveFlag(true), &v
+}
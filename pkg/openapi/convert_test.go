@@ -0,0 +1,322 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func newConvertTestDoc(version string, schemas map[string]*Schema) *Document {
+	return &Document{
+		OpenAPI:    version,
+		Info:       Info{Title: "Test", Version: "1.0.0"},
+		Components: &Components{Schemas: schemas},
+	}
+}
+
+func TestConvertTo31_Nullable(t *testing.T) {
+	doc := newConvertTestDoc("3.0.3", map[string]*Schema{
+		"Name":  {Type: NewSchemaType(TypeString), Nullable: true},
+		"Owner": {Ref: "#/components/schemas/User", Nullable: true},
+	})
+
+	out, err := ConvertTo31(doc)
+	if err != nil {
+		t.Fatalf("ConvertTo31() error = %v", err)
+	}
+	if out.OpenAPI != Version31 {
+		t.Errorf("OpenAPI = %q, want %q", out.OpenAPI, Version31)
+	}
+
+	name := out.Components.Schemas["Name"]
+	if name.Nullable || len(name.Type) != 2 || name.Type[1] != TypeNull {
+		t.Errorf("Name = %+v, want type [string null] without nullable", name)
+	}
+
+	owner := out.Components.Schemas["Owner"]
+	if owner.Ref != "" || len(owner.AnyOf) != 2 || owner.AnyOf[0].Ref != "#/components/schemas/User" {
+		t.Errorf("Owner = %+v, want anyOf [$ref, null]", owner)
+	}
+
+	if !doc.Components.Schemas["Name"].Nullable {
+		t.Error("ConvertTo31() should not modify the input document")
+	}
+}
+
+func TestNullableToTypeNull(t *testing.T) {
+	doc := newConvertTestDoc("3.1.0", map[string]*Schema{
+		"Pet": {Type: NewSchemaType(TypeObject), Properties: map[string]*Schema{
+			"tag":   {Type: NewSchemaType(TypeString), Nullable: true, Example: "dog"},
+			"owner": {Ref: "#/components/schemas/User", Nullable: true, Description: "Owner"},
+		}},
+	})
+
+	NullableToTypeNull(doc)
+
+	pet := doc.Components.Schemas["Pet"]
+	if tag := pet.Properties["tag"]; tag.Nullable || len(tag.Type) != 2 || tag.Example != "dog" {
+		t.Errorf("tag = %+v, want type [string null] keeping its example", tag)
+	}
+	if owner := pet.Properties["owner"]; len(owner.AnyOf) != 2 || owner.Description != "Owner" {
+		t.Errorf("owner = %+v, want anyOf [$ref, null] keeping its description", owner)
+	}
+}
+
+func TestNullableRefToAllOf(t *testing.T) {
+	doc := newConvertTestDoc("3.0.3", map[string]*Schema{
+		"Pet": {Type: NewSchemaType(TypeObject), Properties: map[string]*Schema{
+			"owner": {Ref: "#/components/schemas/User", Nullable: true, Description: "Owner"},
+			"home":  RefTo("Address"),
+		}},
+	})
+
+	NullableRefToAllOf(doc)
+
+	pet := doc.Components.Schemas["Pet"]
+	want := &Schema{AllOf: []*Schema{RefTo("User")}, Nullable: true, Description: "Owner"}
+	if owner := pet.Properties["owner"]; !reflect.DeepEqual(owner, want) {
+		t.Errorf("owner = %+v, want allOf [$ref] beside nullable", owner)
+	}
+	if home := pet.Properties["home"]; home.Ref == "" || len(home.AllOf) != 0 {
+		t.Errorf("home = %+v, want the reference left as is", home)
+	}
+}
+
+func TestConvertTo31_NullableComposition(t *testing.T) {
+	user := RefTo("User")
+	doc := newConvertTestDoc("3.0.3", map[string]*Schema{
+		"Owner": {AllOf: []*Schema{user}, Nullable: true, Title: "Owner", Deprecated: true},
+		"Pet":   {OneOf: []*Schema{RefTo("Cat"), RefTo("Dog")}, Nullable: true},
+		"Both":  {AllOf: []*Schema{user, RefTo("Named")}, Nullable: true, Example: "x"},
+	})
+
+	out, err := ConvertTo31(doc)
+	if err != nil {
+		t.Fatalf("ConvertTo31() error = %v", err)
+	}
+
+	null := &Schema{Type: NewSchemaType(TypeNull)}
+	want := map[string]*Schema{
+		"Owner": {AnyOf: []*Schema{user, null}, Title: "Owner", Deprecated: true},
+		"Pet":   {OneOf: []*Schema{RefTo("Cat"), RefTo("Dog"), null}},
+		"Both":  {AnyOf: []*Schema{{AllOf: []*Schema{user, RefTo("Named")}}, null}, Examples: []any{"x"}},
+	}
+	for name, want := range want {
+		wantJSON, _ := json.Marshal(want)
+		gotJSON, _ := json.Marshal(out.Components.Schemas[name])
+		if string(gotJSON) != string(wantJSON) {
+			t.Errorf("%s = %s, want %s", name, gotJSON, wantJSON)
+		}
+	}
+}
+
+func TestConvert_NullableRoundTrip(t *testing.T) {
+	doc := newConvertTestDoc(Version31, map[string]*Schema{
+		"Name":  {Type: SchemaType{TypeString, TypeNull}},
+		"Owner": {AnyOf: []*Schema{RefTo("User"), {Type: NewSchemaType(TypeNull)}}, Title: "Owner", Deprecated: true, Extensions: map[string]any{"x-go-type": "User"}},
+		"Pet":   {OneOf: []*Schema{RefTo("Cat"), RefTo("Dog"), {Type: NewSchemaType(TypeNull)}}, Description: "A pet"},
+	})
+
+	down, err := ConvertTo30(doc)
+	if err != nil {
+		t.Fatalf("ConvertTo30() error = %v", err)
+	}
+	up, err := ConvertTo31(down)
+	if err != nil {
+		t.Fatalf("ConvertTo31() error = %v", err)
+	}
+
+	for name, want := range doc.Components.Schemas {
+		wantJSON, _ := json.Marshal(want)
+		gotJSON, _ := json.Marshal(up.Components.Schemas[name])
+		if string(gotJSON) != string(wantJSON) {
+			t.Errorf("%s = %s, want %s", name, gotJSON, wantJSON)
+		}
+	}
+}
+
+func TestConvertTo31_ExclusiveAndExamples(t *testing.T) {
+	minimum := 5.0
+	doc := newConvertTestDoc("3.0.3", map[string]*Schema{
+		"Age": {
+			Type:             NewSchemaType(TypeInteger),
+			Minimum:          &minimum,
+			ExclusiveMinimum: ExclusiveFlag(true),
+			Example:          10,
+		},
+	})
+
+	out, err := ConvertTo31(doc)
+	if err != nil {
+		t.Fatalf("ConvertTo31() error = %v", err)
+	}
+
+	age := out.Components.Schemas["Age"]
+	if age.Minimum != nil {
+		t.Errorf("Minimum = %v, want nil", *age.Minimum)
+	}
+	if age.ExclusiveMinimum == nil || age.ExclusiveMinimum.Value == nil || *age.ExclusiveMinimum.Value != 5 {
+		t.Errorf("ExclusiveMinimum = %+v, want 5", age.ExclusiveMinimum)
+	}
+	if age.Example != nil || len(age.Examples) != 1 {
+		t.Errorf("Example = %v, Examples = %v, want examples [10]", age.Example, age.Examples)
+	}
+}
+
+func convertTo30TestDoc(t *testing.T) *Document {
+	t.Helper()
+	doc := newConvertTestDoc("3.1.0", map[string]*Schema{
+		"Name": {Type: SchemaType{TypeString, TypeNull}},
+		"Owner": {AnyOf: []*Schema{
+			RefTo("User"),
+			{Type: NewSchemaType(TypeNull)},
+		}},
+		"Mixed": {Type: SchemaType{TypeString, TypeInteger}},
+		"Price": {
+			Type:             NewSchemaType(TypeNumber),
+			ExclusiveMaximum: ExclusiveValue(100),
+			Examples:         []any{42.5},
+		},
+	})
+	doc.Webhooks = map[string]*PathItem{"newPet": {}}
+	doc.Info.Summary = "Summary"
+
+	out, err := ConvertTo30(doc)
+	if err != nil {
+		t.Fatalf("ConvertTo30() error = %v", err)
+	}
+	return out
+}
+
+func TestConvertTo30_Document(t *testing.T) {
+	out := convertTo30TestDoc(t)
+	if out.OpenAPI != Version30 {
+		t.Errorf("OpenAPI = %q, want %q", out.OpenAPI, Version30)
+	}
+	if out.Webhooks != nil || out.Info.Summary != "" {
+		t.Error("ConvertTo30() should drop webhooks and info summary")
+	}
+}
+
+func TestConvertTo30_MutualTLS(t *testing.T) {
+	doc := &Document{
+		OpenAPI:  Version31,
+		Security: []SecurityRequirement{{"client_cert": {}}, {"bearer": {}}},
+		Paths: map[string]*PathItem{
+			"/accounts": {Get: &Operation{Security: []SecurityRequirement{{"client_cert": {}, "bearer": {}}}}},
+		},
+		Components: &Components{SecuritySchemes: map[string]*SecurityScheme{
+			"client_cert": {Type: "mutualTLS"},
+			"bearer":      {Type: "http", Scheme: "bearer"},
+		}},
+	}
+
+	out, err := ConvertTo30(doc)
+	if err != nil {
+		t.Fatalf("ConvertTo30() error = %v", err)
+	}
+	if _, ok := out.Components.SecuritySchemes["client_cert"]; ok || len(out.Components.SecuritySchemes) != 1 {
+		t.Errorf("security schemes = %v, want only bearer", out.Components.SecuritySchemes)
+	}
+	if len(out.Security) != 1 || out.Security[0]["bearer"] == nil {
+		t.Errorf("security = %v, want [bearer]", out.Security)
+	}
+	if security := out.Paths["/accounts"].Get.Security; len(security) != 0 {
+		t.Errorf("operation security = %v, want none", security)
+	}
+	if doc.Components.SecuritySchemes["client_cert"] == nil {
+		t.Error("ConvertTo30() modified the input document")
+	}
+}
+
+func TestConvertTo30_Nullable(t *testing.T) {
+	out := convertTo30TestDoc(t)
+
+	name := out.Components.Schemas["Name"]
+	if !name.Nullable || len(name.Type) != 1 || name.Type[0] != TypeString {
+		t.Errorf("Name = %+v, want nullable string", name)
+	}
+
+	owner := out.Components.Schemas["Owner"]
+	if !owner.Nullable || len(owner.AnyOf) != 0 || len(owner.AllOf) != 1 {
+		t.Errorf("Owner = %+v, want nullable allOf [$ref]", owner)
+	}
+
+	mixed := out.Components.Schemas["Mixed"]
+	if len(mixed.Type) != 0 || len(mixed.AnyOf) != 2 {
+		t.Errorf("Mixed = %+v, want anyOf of two types", mixed)
+	}
+}
+
+func TestConvertTo30_ExclusiveAndExamples(t *testing.T) {
+	price := convertTo30TestDoc(t).Components.Schemas["Price"]
+	if price.Maximum == nil || *price.Maximum != 100 || !price.ExclusiveMaximum.Exclusive {
+		t.Errorf("Price = %+v, want maximum 100 with exclusiveMaximum true", price)
+	}
+	if price.Example != 42.5 || price.Examples != nil {
+		t.Errorf("Price example = %v, examples = %v, want example 42.5", price.Example, price.Examples)
+	}
+}
+
+func TestConvert_NilDocument(t *testing.T) {
+	if _, err := ConvertTo31(nil); err == nil {
+		t.Error("ConvertTo31(nil) should return an error")
+	}
+	if _, err := ConvertTo30(nil); err == nil {
+		t.Error("ConvertTo30(nil) should return an error")
+	}
+}
+
+func TestExclusiveBound_Serialization(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "boolean", input: `{"exclusiveMinimum":true}`, want: `{"exclusiveMinimum":true}`},
+		{name: "number", input: `{"exclusiveMinimum":5}`, want: `{"exclusiveMinimum":5}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Schema
+			if err := json.Unmarshal([]byte(tt.input), &s); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			data, err := json.Marshal(s)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("round trip = %s, want %s", data, tt.want)
+			}
+
+			var fromYAML Schema
+			if err := yaml.Unmarshal([]byte(tt.input), &fromYAML); err != nil {
+				t.Fatalf("yaml.Unmarshal() error = %v", err)
+			}
+			if fromYAML.ExclusiveMinimum == nil {
+				t.Error("yaml.Unmarshal() should populate ExclusiveMinimum")
+			}
+		})
+	}
+}
+
+func TestSchemaType_UnmarshalYAML(t *testing.T) {
+	var s Schema
+	if err := yaml.Unmarshal([]byte("type: string"), &s); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+	if len(s.Type) != 1 || s.Type[0] != TypeString {
+		t.Errorf("Type = %v, want [string]", s.Type)
+	}
+
+	if err := yaml.Unmarshal([]byte("type: [string, \"null\"]"), &s); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+	if len(s.Type) != 2 {
+		t.Errorf("Type = %v, want [string null]", s.Type)
+	}
+}
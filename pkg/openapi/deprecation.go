@@ -0,0 +1,130 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// DeprecatedProperty is a deprecated property of a component schema.
+type DeprecatedProperty struct {
+	Schema   string `json:"schema" yaml:"schema"`
+	Property string `json:"property" yaml:"property"`
+
+	// Required reports whether the schema still lists the property as required
+	Required bool `json:"required" yaml:"required"`
+
+	// Operations lists the operations, as "METHOD /path", whose parameters,
+	// request body, or responses use the schema, directly or through other schemas
+	Operations []string `json:"operations" yaml:"operations"`
+}
+
+// DeprecatedProperties returns the deprecated properties of the component
+// schemas, sorted by schema and property name, with the operations that
+// still use them. Properties of inline schemas are not included.
+func (d *Document) DeprecatedProperties() []DeprecatedProperty {
+	if d.Components == nil {
+		return nil
+	}
+	users := d.schemaUsers()
+
+	var props []DeprecatedProperty
+	for _, name := range slices.Sorted(maps.Keys(d.Components.Schemas)) {
+		schema := d.Components.Schemas[name]
+		if schema == nil {
+			continue
+		}
+		for _, prop := range slices.Sorted(maps.Keys(schema.Properties)) {
+			if p := schema.Properties[prop]; p == nil || !p.Deprecated {
+				continue
+			}
+			props = append(props, DeprecatedProperty{
+				Schema:     name,
+				Property:   prop,
+				Required:   slices.Contains(schema.Required, prop),
+				Operations: append([]string{}, users[name]...),
+			})
+		}
+	}
+	return props
+}
+
+// schemaUsers maps each component schema name to the operations that use it, sorted by path.
+func (d *Document) schemaUsers() map[string][]string {
+	users := make(map[string][]string)
+	for _, path := range slices.Sorted(maps.Keys(d.Paths)) {
+		item := d.Paths[path]
+		if item == nil {
+			continue
+		}
+		for _, entry := range []struct {
+			method string
+			op     *Operation
+		}{
+			{"GET", item.Get}, {"PUT", item.Put}, {"POST", item.Post}, {"DELETE", item.Delete},
+			{"OPTIONS", item.Options}, {"HEAD", item.Head}, {"PATCH", item.Patch}, {"TRACE", item.Trace},
+		} {
+			if entry.op == nil {
+				continue
+			}
+			for name := range d.reachableSchemas(entry.op) {
+				users[name] = append(users[name], entry.method+" "+path)
+			}
+		}
+	}
+	return users
+}
+
+// reachableSchemas returns the names of the component schemas op uses,
+// following references between schemas.
+func (d *Document) reachableSchemas(op *Operation) map[string]bool {
+	var queue []string
+	collect := func(s *Schema) {
+		if name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/"); ok {
+			queue = append(queue, name)
+		}
+	}
+	walkOperationSchemas(d.resolvedOperation(op), collect)
+
+	seen := make(map[string]bool)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if d.Components != nil {
+			walkSchema(d.Components.Schemas[name], collect)
+		}
+	}
+	return seen
+}
+
+// resolvedOperation returns a copy of op whose parameter, request body, and
+// response $refs are replaced by the components they reference.
+func (d *Document) resolvedOperation(op *Operation) *Operation {
+	c := d.Components
+	if c == nil {
+		return op
+	}
+	resolved := *op
+	resolved.Parameters = make([]*Parameter, len(op.Parameters))
+	for i, p := range op.Parameters {
+		resolved.Parameters[i] = p
+		if p != nil && p.Ref != "" {
+			resolved.Parameters[i] = c.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+		}
+	}
+	if op.RequestBody != nil && op.RequestBody.Ref != "" {
+		resolved.RequestBody = c.RequestBodies[strings.TrimPrefix(op.RequestBody.Ref, "#/components/requestBodies/")]
+	}
+	resolved.Responses = make(Responses, len(op.Responses))
+	for status, r := range op.Responses {
+		resolved.Responses[status] = r
+		if r != nil && r.Ref != "" {
+			resolved.Responses[status] = c.Responses[strings.TrimPrefix(r.Ref, "#/components/responses/")]
+		}
+	}
+	return &resolved
+}
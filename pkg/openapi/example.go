@@ -0,0 +1,288 @@
+package openapi
+
+import (
+	"maps"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+)
+
+// maxExampleDepth bounds example synthesis so recursive schemas terminate.
+const maxExampleDepth = 5
+
+// maxExampleLength bounds the items of array examples and the characters of
+// string examples, so that a huge minItems or minLength does not exhaust
+// memory.
+const maxExampleLength = 64
+
+// ExampleFor synthesizes an example value for schema. The example, first
+// examples entry, default, or first enum value declared on the schema is
+// used if there is one; otherwise the value follows the type and format of
+// the schema and stays within its bounds, such as minimum and maxLength.
+// A $ref yields nil, as there is no document to resolve it in; see
+// Document.ExampleFor.
+func ExampleFor(schema *Schema) any {
+	return exampleFor(nil, schema, 0)
+}
+
+// ExampleFor synthesizes an example value for schema like the package-level
+// ExampleFor, resolving $refs to the component schemas of d. Recursive
+// schemas are expanded a few levels deep.
+func (d *Document) ExampleFor(schema *Schema) any {
+	return exampleFor(d, schema, 0)
+}
+
+// MediaTypeExample returns the declared example of a media type, falling back
+// to the first named example and then to one synthesized from its schema.
+func (d *Document) MediaTypeExample(mt MediaType) any {
+	if v := declaredMediaTypeExample(d, mt); v != nil {
+		return v
+	}
+	return d.ExampleFor(mt.Schema)
+}
+
+// declaredMediaTypeExample returns the example of mt or the value of its
+// first named example, resolving $refs to the component examples of doc.
+func declaredMediaTypeExample(doc *Document, mt MediaType) any {
+	if mt.Example != nil {
+		return mt.Example
+	}
+	for _, name := range slices.Sorted(maps.Keys(mt.Examples)) {
+		example := mt.Examples[name]
+		if example != nil && example.Ref != "" && doc != nil && doc.Components != nil {
+			example = doc.Components.Examples[strings.TrimPrefix(example.Ref, "#/components/examples/")]
+		}
+		if example != nil && example.Value != nil {
+			return example.Value
+		}
+	}
+	return nil
+}
+
+func exampleFor(doc *Document, schema *Schema, depth int) any {
+	return exampler{doc: doc}.example(schema, "", depth)
+}
+
+// exampler synthesizes the examples of the schemas of doc, with the values of
+// gen if set.
+type exampler struct {
+	doc  *Document
+	gen  *ExampleGenerator
+	rand *rand.Rand
+}
+
+// example returns an example value for schema, the schema of the property
+// field if it is one.
+func (e exampler) example(schema *Schema, field string, depth int) any {
+	if schema == nil || depth > maxExampleDepth {
+		return nil
+	}
+	if schema.Ref != "" {
+		return e.example(e.referenced(schema.Ref), field, depth+1)
+	}
+	if v := declaredExample(schema); v != nil {
+		return v
+	}
+	if v, ok := e.compositionExample(schema, field, depth); ok {
+		return v
+	}
+
+	if e.gen != nil && len(schema.Properties) == 0 && schema.Items == nil {
+		if v := e.gen.provide(e.rand, schema, field); v != nil {
+			return v
+		}
+	}
+	return e.exampleByType(schema, depth)
+}
+
+// referenced returns the component schema ref points to, or nil.
+func (e exampler) referenced(ref string) *Schema {
+	if e.doc == nil || e.doc.Components == nil {
+		return nil
+	}
+	return e.doc.Components.Schemas[strings.TrimPrefix(ref, "#/components/schemas/")]
+}
+
+// compositionExample returns an example for a schema composed with allOf, of
+// all its schemas, or with oneOf or anyOf, of the first, and whether it is one.
+func (e exampler) compositionExample(schema *Schema, field string, depth int) (any, bool) {
+	switch {
+	case len(schema.AllOf) > 0:
+		return e.allOfExample(schema.AllOf, depth), true
+	case len(schema.OneOf) > 0:
+		return e.example(schema.OneOf[0], field, depth+1), true
+	case len(schema.AnyOf) > 0:
+		return e.example(schema.AnyOf[0], field, depth+1), true
+	}
+	return nil, false
+}
+
+// declaredExample returns the example, first examples entry, default, or
+// first enum value declared on schema.
+func declaredExample(schema *Schema) any {
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case len(schema.Examples) > 0:
+		return schema.Examples[0]
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	default:
+		return nil
+	}
+}
+
+func (e exampler) exampleByType(schema *Schema, depth int) any {
+	switch {
+	case slices.Contains(schema.Type, TypeObject) || len(schema.Properties) > 0:
+		obj := make(map[string]any)
+		// in order, so generated values do not depend on map iteration
+		for _, name := range slices.Sorted(maps.Keys(schema.Properties)) {
+			obj[name] = e.example(schema.Properties[name], name, depth+1)
+		}
+		return obj
+	case slices.Contains(schema.Type, TypeArray):
+		return e.arrayExample(schema, depth)
+	case slices.Contains(schema.Type, TypeString):
+		return stringExample(schema)
+	case slices.Contains(schema.Type, TypeInteger):
+		return int64(numberExample(schema, true))
+	case slices.Contains(schema.Type, TypeNumber):
+		return numberExample(schema, false)
+	case slices.Contains(schema.Type, TypeBoolean):
+		return true
+	default:
+		return nil
+	}
+}
+
+// arrayExample returns an array of at least minItems examples of the items of
+// schema, and at most maxExampleLength.
+func (e exampler) arrayExample(schema *Schema, depth int) []any {
+	item := e.example(schema.Items, "", depth+1)
+	if item == nil {
+		return []any{}
+	}
+	items := []any{item}
+	for len(items) < exampleLength(schema.MinItems) {
+		items = append(items, e.example(schema.Items, "", depth+1))
+	}
+	return items
+}
+
+// exampleLength returns the minimum length min, capped at maxExampleLength
+// before converting it to int, so that it cannot overflow.
+func exampleLength(min *int64) int {
+	switch {
+	case min == nil || *min < 0:
+		return 0
+	case *min > maxExampleLength:
+		return maxExampleLength
+	}
+	return int(*min)
+}
+
+// allOfExample merges the object examples of all subschemas.
+func (e exampler) allOfExample(schemas []*Schema, depth int) any {
+	merged := make(map[string]any)
+	for _, sub := range schemas {
+		obj, ok := e.example(sub, "", depth+1).(map[string]any)
+		if !ok {
+			continue
+		}
+		for k, v := range obj {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// formatExamples are example values of the string formats.
+var formatExamples = map[string]string{
+	"date":      "2024-01-15",
+	"date-time": "2024-01-15T10:30:00Z",
+	"time":      "10:30:00Z",
+	"email":     "user@example.com",
+	"uri":       "https://example.com",
+	"url":       "https://example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"uuid":      "550e8400-e29b-41d4-a716-446655440000",
+	"byte":      "c3RyaW5n",
+	"password":  "********",
+}
+
+// stringExample returns an example of the format of schema, or a plain
+// string padded or cut to its length bounds, padded to at most
+// maxExampleLength characters.
+func stringExample(schema *Schema) string {
+	if s, ok := formatExamples[schema.Format]; ok {
+		return s
+	}
+	s := "string"
+	if n := exampleLength(schema.MinLength); len(s) < n {
+		s += strings.Repeat("x", n-len(s))
+	}
+	if schema.MaxLength != nil && *schema.MaxLength >= 0 && int64(len(s)) > *schema.MaxLength {
+		s = s[:*schema.MaxLength]
+	}
+	return s
+}
+
+// numberExample returns 0, or the value closest to it within the bounds of
+// schema, rounded up from a minimum or down from a maximum to a multiple of
+// its multipleOf and, for integers, to a whole number.
+func numberExample(schema *Schema, integer bool) float64 {
+	step := exclusiveStep(schema, integer)
+	v, round := 0.0, math.Ceil
+	lo, loExclusive := schema.LowerBound()
+	hi, hiExclusive := schema.UpperBound()
+	switch {
+	case excludesZero(lo, loExclusive, 1):
+		v = *lo
+		if loExclusive {
+			v = nextAfter(v, step, integer, math.Floor)
+		}
+	case excludesZero(hi, hiExclusive, -1):
+		v, round = *hi, math.Floor
+		if hiExclusive {
+			v = nextAfter(v, -step, integer, math.Ceil)
+		}
+	}
+
+	if m := schema.MultipleOf; m != nil && *m > 0 {
+		v = round(v / *m) * *m
+	}
+	if integer {
+		v = round(v)
+	}
+	return v
+}
+
+// exclusiveStep returns the step past an exclusive bound of schema: its
+// multipleOf, or 1 for integers and schemas without one.
+func exclusiveStep(schema *Schema, integer bool) float64 {
+	if m := schema.MultipleOf; m != nil && *m > 0 && !integer {
+		return *m
+	}
+	return 1
+}
+
+// excludesZero reports whether the bound, a minimum for sign 1 or a maximum
+// for sign -1, leaves out zero.
+func excludesZero(bound *float64, exclusive bool, sign float64) bool {
+	return bound != nil && (*bound*sign > 0 || exclusive && *bound == 0)
+}
+
+// nextAfter returns the first value past the exclusive bound v, the next
+// whole number for integers.
+func nextAfter(v, step float64, integer bool, whole func(float64) float64) float64 {
+	if integer {
+		return whole(v) + step
+	}
+	return v + step
+}
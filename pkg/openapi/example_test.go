@@ -0,0 +1,134 @@
+package openapi
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestExampleFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema *Schema
+		want   any
+	}{
+		{"nil", nil, nil},
+		{"example", &Schema{Type: NewSchemaType(TypeString), Example: "Rex", Default: "Max"}, "Rex"},
+		{"examples", &Schema{Type: NewSchemaType(TypeString), Examples: []any{"Rex", "Max"}}, "Rex"},
+		{"default before enum", &Schema{Type: NewSchemaType(TypeString), Enum: []any{"cat", "dog"}, Default: "dog"}, "dog"},
+		{"enum", &Schema{Type: NewSchemaType(TypeString), Enum: []any{"cat", "dog"}}, "cat"},
+		{"string", StringSchema(), "string"},
+		{"date", &Schema{Type: NewSchemaType(TypeString), Format: "date"}, "2024-01-15"},
+		{"date-time", &Schema{Type: NewSchemaType(TypeString), Format: "date-time"}, "2024-01-15T10:30:00Z"},
+		{"email", &Schema{Type: NewSchemaType(TypeString), Format: "email"}, "user@example.com"},
+		{"uri", &Schema{Type: NewSchemaType(TypeString), Format: "uri"}, "https://example.com"},
+		{"uuid", &Schema{Type: NewSchemaType(TypeString), Format: "uuid"}, "550e8400-e29b-41d4-a716-446655440000"},
+		{"minLength", &Schema{Type: NewSchemaType(TypeString), MinLength: ptr[int64](8)}, "stringxx"},
+		{"maxLength", &Schema{Type: NewSchemaType(TypeString), MaxLength: ptr[int64](3)}, "str"},
+		{"integer", IntegerSchema(), int64(0)},
+		{"minimum", &Schema{Type: NewSchemaType(TypeInteger), Minimum: ptr(1.0)}, int64(1)},
+		{"negative maximum", &Schema{Type: NewSchemaType(TypeInteger), Maximum: ptr(-5.5)}, int64(-6)},
+		{"exclusive minimum 3.0", &Schema{Type: NewSchemaType(TypeInteger), Minimum: ptr(0.0), ExclusiveMinimum: ExclusiveFlag(true)}, int64(1)},
+		{"exclusive minimum 3.1", &Schema{Type: NewSchemaType(TypeInteger), ExclusiveMinimum: ExclusiveValue(0.5)}, int64(1)},
+		{"multipleOf", &Schema{Type: NewSchemaType(TypeInteger), Minimum: ptr(12.0), MultipleOf: ptr(5.0)}, int64(15)},
+		{"number", NumberSchema(), 0.0},
+		{"exclusive number", &Schema{Type: NewSchemaType(TypeNumber), ExclusiveMinimum: ExclusiveValue(0), MultipleOf: ptr(0.25)}, 0.25},
+		{"boolean", BooleanSchema(), true},
+		{"nullable type", &Schema{Type: SchemaType{TypeString, TypeNull}}, "string"},
+		{"array", ArraySchema(IntegerSchema()), []any{int64(0)}},
+		{"minItems", &Schema{Type: NewSchemaType(TypeArray), Items: BooleanSchema(), MinItems: ptr[int64](2)}, []any{true, true}},
+		{"object", &Schema{Type: NewSchemaType(TypeObject), Properties: map[string]*Schema{
+			"name": StringSchema(),
+			"age":  {Type: NewSchemaType(TypeInteger), Minimum: ptr(1.0)},
+		}}, map[string]any{"name": "string", "age": int64(1)}},
+		{"allOf", &Schema{AllOf: []*Schema{
+			{Properties: map[string]*Schema{"id": IntegerSchema()}},
+			{Properties: map[string]*Schema{"name": StringSchema()}},
+		}}, map[string]any{"id": int64(0), "name": "string"}},
+		{"oneOf", &Schema{OneOf: []*Schema{BooleanSchema(), StringSchema()}}, true},
+		{"unresolved ref", RefTo("Pet"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExampleFor(tt.schema); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExampleFor() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExampleFor_HugeLengths(t *testing.T) {
+	for _, min := range []int64{1 << 40, math.MaxInt64} {
+		s, ok := ExampleFor(&Schema{Type: NewSchemaType(TypeString), MinLength: ptr(min)}).(string)
+		if !ok || len(s) != maxExampleLength {
+			t.Errorf("minLength %d: len = %d, want %d", min, len(s), maxExampleLength)
+		}
+		items, ok := ExampleFor(&Schema{Type: NewSchemaType(TypeArray), Items: BooleanSchema(), MinItems: ptr(min)}).([]any)
+		if !ok || len(items) != maxExampleLength {
+			t.Errorf("minItems %d: len = %d, want %d", min, len(items), maxExampleLength)
+		}
+	}
+	if s := ExampleFor(&Schema{Type: NewSchemaType(TypeString), MinLength: ptr[int64](-1)}); s != "string" {
+		t.Errorf("negative minLength = %#v, want %q", s, "string")
+	}
+}
+
+func TestDocument_ExampleFor(t *testing.T) {
+	doc := &Document{Components: &Components{Schemas: map[string]*Schema{
+		"Pet": {
+			Type: NewSchemaType(TypeObject),
+			Properties: map[string]*Schema{
+				"name":   StringSchema(),
+				"parent": RefTo("Pet"),
+			},
+		},
+	}}}
+
+	example, ok := doc.ExampleFor(RefTo("Pet")).(map[string]any)
+	if !ok {
+		t.Fatalf("ExampleFor() = %T, want object", example)
+	}
+	if example["name"] != "string" {
+		t.Errorf("name = %v, want string", example["name"])
+	}
+	// recursive schemas are expanded a few levels deep and then stop
+	levels := 0
+	for parent, ok := example["parent"].(map[string]any); ok; parent, ok = parent["parent"].(map[string]any) {
+		levels++
+	}
+	if levels == 0 || levels > maxExampleDepth {
+		t.Errorf("parent expanded %d levels, want 1 to %d", levels, maxExampleDepth)
+	}
+}
+
+func TestDocument_MediaTypeExample(t *testing.T) {
+	doc := &Document{Components: &Components{
+		Schemas:  map[string]*Schema{"Pet": {Properties: map[string]*Schema{"name": StringSchema()}}},
+		Examples: map[string]*Example{"rex": {Value: map[string]any{"name": "Rex"}}},
+	}}
+
+	tests := []struct {
+		name string
+		mt   MediaType
+		want any
+	}{
+		{"declared", MediaType{Example: "declared", Schema: RefTo("Pet")}, "declared"},
+		{"named", MediaType{Examples: map[string]*Example{
+			"b": {Value: "second"},
+			"a": {Ref: "#/components/examples/rex"},
+		}}, map[string]any{"name": "Rex"}},
+		{"synthesized", MediaType{Schema: RefTo("Pet")}, map[string]any{"name": "string"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := doc.MediaTypeExample(tt.mt); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MediaTypeExample() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
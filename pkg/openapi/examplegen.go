@@ -0,0 +1,196 @@
+package openapi
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ExampleProvider returns a random example value for schema, or nil to leave
+// the value to the plain example of the schema, such as when the type of the
+// schema does not fit.
+type ExampleProvider func(r *rand.Rand, schema *Schema) any
+
+// ExampleGenerator synthesizes realistic example values, such as names and
+// email addresses, for mock responses. Values come from the provider of the
+// format of a schema or, for properties, of the property name; schemas
+// without either get the plain example of ExampleFor. Declared examples,
+// defaults, and enums take precedence as they do for ExampleFor.
+//
+// Values are random but depend only on the seed and the schema, so a
+// generator returns the same example for a schema every time and on every
+// run.
+type ExampleGenerator struct {
+	// Formats maps formats, such as email, to their providers
+	Formats map[string]ExampleProvider
+
+	// Fields maps property names to their providers. Names are matched
+	// lowercase without separators, so firstName and first_name both match
+	// firstname, first as a whole and then by their longest suffix, so
+	// contactEmail matches email.
+	Fields map[string]ExampleProvider
+
+	doc  *Document
+	seed uint64
+}
+
+// NewExampleGenerator creates a generator of examples for the schemas of doc,
+// which resolves their $refs, with the default providers.
+func NewExampleGenerator(doc *Document, seed uint64) *ExampleGenerator {
+	return &ExampleGenerator{
+		Formats: maps.Clone(DefaultFormatProviders),
+		Fields:  maps.Clone(DefaultFieldProviders),
+		doc:     doc,
+		seed:    seed,
+	}
+}
+
+// ExampleFor synthesizes an example value for schema.
+func (g *ExampleGenerator) ExampleFor(schema *Schema) any {
+	e := exampler{doc: g.doc, gen: g, rand: rand.New(rand.NewPCG(g.seed, 0))}
+	return e.example(schema, "", 0)
+}
+
+// MediaTypeExample returns the declared example of a media type, falling back
+// to the first named example and then to one synthesized from its schema.
+func (g *ExampleGenerator) MediaTypeExample(mt MediaType) any {
+	if v := declaredMediaTypeExample(g.doc, mt); v != nil {
+		return v
+	}
+	return g.ExampleFor(mt.Schema)
+}
+
+// provide returns the value of the provider of the format of schema or of
+// field, nil if neither has one.
+func (g *ExampleGenerator) provide(r *rand.Rand, schema *Schema, field string) any {
+	if p := g.Formats[schema.Format]; schema.Format != "" && p != nil {
+		if v := p(r, schema); v != nil {
+			return v
+		}
+	}
+	if p := g.fieldProvider(field); p != nil {
+		return p(r, schema)
+	}
+	return nil
+}
+
+// fieldProvider returns the provider of the property name field.
+func (g *ExampleGenerator) fieldProvider(field string) ExampleProvider {
+	name := strings.ToLower(strings.NewReplacer("_", "", "-", "", ".", "").Replace(field))
+	if name == "" {
+		return nil
+	}
+	if p, ok := g.Fields[name]; ok {
+		return p
+	}
+	var match string
+	for key := range g.Fields {
+		if len(key) > len(match) && strings.HasSuffix(name, key) {
+			match = key
+		}
+	}
+	return g.Fields[match]
+}
+
+// DefaultFormatProviders are the format providers of NewExampleGenerator.
+var DefaultFormatProviders = map[string]ExampleProvider{
+	"email":     stringProvider(fakeEmail),
+	"uuid":      stringProvider(fakeUUID),
+	"date":      stringProvider(func(r *rand.Rand) string { return fakeTime(r).Format(time.DateOnly) }),
+	"date-time": stringProvider(func(r *rand.Rand) string { return fakeTime(r).Format(time.RFC3339) }),
+	"uri":       stringProvider(fakeURL),
+	"url":       stringProvider(fakeURL),
+	"phone":     stringProvider(fakePhone),
+	"hostname": stringProvider(func(r *rand.Rand) string {
+		return strings.TrimPrefix(fakeURL(r), "https://")
+	}),
+	"ipv4": stringProvider(func(r *rand.Rand) string {
+		// TEST-NET-3, reserved for documentation
+		return fmt.Sprintf("203.0.113.%d", 1+r.IntN(254))
+	}),
+}
+
+// DefaultFieldProviders are the field providers of NewExampleGenerator.
+var DefaultFieldProviders = map[string]ExampleProvider{
+	"email":     stringProvider(fakeEmail),
+	"phone":     stringProvider(fakePhone),
+	"mobile":    stringProvider(fakePhone),
+	"firstname": stringProvider(func(r *rand.Rand) string { return pick(r, firstNames) }),
+	"givenname": stringProvider(func(r *rand.Rand) string { return pick(r, firstNames) }),
+	"lastname":  stringProvider(func(r *rand.Rand) string { return pick(r, lastNames) }),
+	"surname":   stringProvider(func(r *rand.Rand) string { return pick(r, lastNames) }),
+	"name":      stringProvider(func(r *rand.Rand) string { return pick(r, firstNames) + " " + pick(r, lastNames) }),
+	"username":  stringProvider(func(r *rand.Rand) string { return strings.ToLower(pick(r, firstNames)) + fmt.Sprint(10+r.IntN(90)) }),
+	"uuid":      stringProvider(fakeUUID),
+	"city":      stringProvider(func(r *rand.Rand) string { return pick(r, cities) }),
+	"country":   stringProvider(func(r *rand.Rand) string { return pick(r, countries) }),
+	"address":   stringProvider(fakeStreet),
+	"street":    stringProvider(fakeStreet),
+	"zipcode":   stringProvider(func(r *rand.Rand) string { return fmt.Sprintf("%05d", r.IntN(100000)) }),
+	"postcode":  stringProvider(func(r *rand.Rand) string { return fmt.Sprintf("%05d", r.IntN(100000)) }),
+	"company":   stringProvider(func(r *rand.Rand) string { return pick(r, companies) }),
+	"url":       stringProvider(fakeURL),
+	"website":   stringProvider(fakeURL),
+	"age": func(r *rand.Rand, schema *Schema) any {
+		if !slices.Contains(schema.Type, TypeInteger) {
+			return nil
+		}
+		return int64(18 + r.IntN(63))
+	},
+}
+
+// stringProvider provides the values of fake for string schemas.
+func stringProvider(fake func(*rand.Rand) string) ExampleProvider {
+	return func(r *rand.Rand, schema *Schema) any {
+		if len(schema.Type) > 0 && !slices.Contains(schema.Type, TypeString) {
+			return nil
+		}
+		return fake(r)
+	}
+}
+
+var (
+	firstNames = []string{"Alice", "Bima", "Carlos", "Dewi", "Emma", "Farid", "Grace", "Hiro", "Ines", "Jonas"}
+	lastNames  = []string{"Anderson", "Brown", "Garcia", "Hartono", "Kim", "Meyer", "Nguyen", "Santoso", "Smith", "Tanaka"}
+	cities     = []string{"Amsterdam", "Bandung", "Berlin", "Jakarta", "Lisbon", "Nairobi", "Osaka", "Toronto"}
+	countries  = []string{"Canada", "Germany", "Indonesia", "Japan", "Kenya", "Netherlands", "Portugal"}
+	streets    = []string{"Main Street", "Oak Avenue", "Station Road", "Jalan Merdeka", "Market Square"}
+	companies  = []string{"Acme Corp", "Globex", "Initech", "Umbrella Foods", "Nusantara Tech"}
+)
+
+func pick(r *rand.Rand, values []string) string {
+	return values[r.IntN(len(values))]
+}
+
+func fakeEmail(r *rand.Rand) string {
+	return strings.ToLower(pick(r, firstNames)+"."+pick(r, lastNames)) + "@example.com"
+}
+
+// fakePhone returns a number of the 555-01xx range reserved for fiction.
+func fakePhone(r *rand.Rand) string {
+	return fmt.Sprintf("+1-555-01%02d", r.IntN(100))
+}
+
+func fakeUUID(r *rand.Rand) string {
+	hi, lo := r.Uint64(), r.Uint64()
+	hi = hi&^0xf000 | 0x4000     // version 4
+	lo = lo&^(0xc<<60) | 0x8<<60 // RFC 4122 variant
+	return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x", hi>>32, hi>>16&0xffff, hi&0xffff, lo>>48, lo&0xffffffffffff)
+}
+
+// fakeTime returns a time in 2024.
+func fakeTime(r *rand.Rand) time.Time {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return start.Add(time.Duration(r.Int64N(366*24*60*60)) * time.Second)
+}
+
+func fakeURL(r *rand.Rand) string {
+	return "https://" + strings.ToLower(strings.ReplaceAll(pick(r, companies), " ", "-")) + ".example.com"
+}
+
+func fakeStreet(r *rand.Rand) string {
+	return fmt.Sprintf("%d %s", 1+r.IntN(200), pick(r, streets))
+}
@@ -0,0 +1,130 @@
+package openapi
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func exampleGenTestDocument() *Document {
+	return &Document{Components: &Components{Schemas: map[string]*Schema{
+		"User": {
+			Type: NewSchemaType(TypeObject),
+			Properties: map[string]*Schema{
+				"id":           {Type: NewSchemaType(TypeString), Format: "uuid"},
+				"firstName":    StringSchema(),
+				"last_name":    StringSchema(),
+				"contactEmail": StringSchema(),
+				"phone":        StringSchema(),
+				"age":          IntegerSchema(),
+				"createdAt":    {Type: NewSchemaType(TypeString), Format: "date-time"},
+				"role":         {Type: NewSchemaType(TypeString), Enum: []any{"admin", "member"}},
+				"nickname":     {Type: NewSchemaType(TypeString), Example: "Ace"},
+				"address":      {Type: NewSchemaType(TypeObject), Properties: map[string]*Schema{"city": StringSchema()}},
+				"score":        NumberSchema(),
+			},
+		},
+	}}}
+}
+
+// generatedUser returns the example the generator with seed 42 synthesizes
+// for the User schema.
+func generatedUser(t *testing.T) map[string]any {
+	t.Helper()
+	example := NewExampleGenerator(exampleGenTestDocument(), 42).ExampleFor(RefTo("User"))
+	user, ok := example.(map[string]any)
+	if !ok {
+		t.Fatalf("ExampleFor() = %T, want object", example)
+	}
+	return user
+}
+
+func TestExampleGenerator(t *testing.T) {
+	user := generatedUser(t)
+
+	patterns := map[string]string{
+		"id":           `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`,
+		"firstName":    `^[A-Z][a-z]+$`,
+		"last_name":    `^[A-Z][a-z]+$`,
+		"contactEmail": `^[a-z]+\.[a-z]+@example\.com$`,
+		"phone":        `^\+1-555-01\d\d$`,
+	}
+	for field, pattern := range patterns {
+		if s, _ := user[field].(string); !regexp.MustCompile(pattern).MatchString(s) {
+			t.Errorf("%s = %#v, want a match of %s", field, user[field], pattern)
+		}
+	}
+}
+
+func TestExampleGenerator_Values(t *testing.T) {
+	user := generatedUser(t)
+	if age, _ := user["age"].(int64); age < 18 || age > 80 {
+		t.Errorf("age = %#v, want 18 to 80", user["age"])
+	}
+	if s, _ := user["createdAt"].(string); !strings.HasPrefix(s, "2024-") {
+		t.Errorf("createdAt = %#v, want a date-time in 2024", user["createdAt"])
+	} else if _, err := time.Parse(time.RFC3339, s); err != nil {
+		t.Errorf("createdAt = %q: %v", s, err)
+	}
+	if user["role"] != "admin" || user["nickname"] != "Ace" {
+		t.Errorf("role, nickname = %v, %v, want the declared enum and example", user["role"], user["nickname"])
+	}
+	if address, ok := user["address"].(map[string]any); !ok || !slices.Contains(cities, address["city"].(string)) {
+		t.Errorf("address = %#v, want an object with a city", user["address"])
+	}
+	if user["score"] != 0.0 {
+		t.Errorf("score = %#v, want the plain example", user["score"])
+	}
+}
+
+func TestExampleGenerator_Seed(t *testing.T) {
+	doc := exampleGenTestDocument()
+	first := NewExampleGenerator(doc, 42).ExampleFor(RefTo("User"))
+	gen := NewExampleGenerator(doc, 42)
+	for range 3 {
+		if got := gen.ExampleFor(RefTo("User")); !reflect.DeepEqual(got, first) {
+			t.Fatalf("examples differ for the same seed:\n%#v\n%#v", got, first)
+		}
+	}
+
+	differs := false
+	for seed := uint64(1); seed < 5 && !differs; seed++ {
+		differs = !reflect.DeepEqual(NewExampleGenerator(doc, seed).ExampleFor(RefTo("User")), first)
+	}
+	if !differs {
+		t.Error("examples are the same for other seeds")
+	}
+}
+
+func TestExampleGenerator_Providers(t *testing.T) {
+	gen := NewExampleGenerator(nil, 1)
+	gen.Fields["sku"] = func(r *rand.Rand, schema *Schema) any { return "SKU-1" }
+	gen.Formats["email"] = func(r *rand.Rand, schema *Schema) any { return "fixed@example.com" }
+	delete(gen.Fields, "name")
+
+	product := &Schema{Properties: map[string]*Schema{
+		"productSku": StringSchema(),
+		"email":      StringSchema(),
+		"support":    {Type: NewSchemaType(TypeString), Format: "email"},
+		"name":       StringSchema(),
+	}}
+	got := gen.ExampleFor(product).(map[string]any)
+	// the email field keeps its field provider, while the format provider of
+	// support is replaced
+	if email, _ := got["email"].(string); email == "fixed@example.com" || !strings.HasSuffix(email, "@example.com") {
+		t.Errorf("email = %#v, want a generated email", got["email"])
+	}
+	delete(got, "email")
+	want := map[string]any{"productSku": "SKU-1", "support": "fixed@example.com", "name": "string"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExampleFor() = %#v, want %#v", got, want)
+	}
+
+	if _, ok := DefaultFieldProviders["sku"]; ok {
+		t.Error("changing the providers of a generator changed the defaults")
+	}
+}
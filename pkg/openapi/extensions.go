@@ -0,0 +1,809 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Extensions holds the specification extensions of an object, the fields
+// whose names start with "x-", such as x-go-type or x-internal. They are
+// marshaled inline, after the object's own fields, in key order.
+// https://spec.openapis.org/oas/v3.1.0#specification-extensions
+type Extensions map[string]any
+
+// IsExtension reports whether key names a specification extension.
+func IsExtension(key string) bool {
+	return strings.HasPrefix(key, "x-")
+}
+
+// keys returns the sorted extension keys of e, skipping the reserved keys,
+// which the object already marshals as typed fields.
+func (e Extensions) keys(reserved []string) []string {
+	var keys []string
+	for _, key := range slices.Sorted(maps.Keys(e)) {
+		if IsExtension(key) && !slices.Contains(reserved, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// set sets the extension key of *e, allocating the map if needed.
+func (e *Extensions) set(key string, value any) {
+	if *e == nil {
+		*e = make(Extensions)
+	}
+	(*e)[key] = value
+}
+
+// marshalJSONWithExtensions marshals v, a JSON object, with ext appended to its fields.
+func marshalJSONWithExtensions(v any, ext Extensions, reserved ...string) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(ext) == 0 {
+		return data, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for _, key := range ext.keys(reserved) {
+		value, err := json.Marshal(ext[key])
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// unmarshalJSONWithExtensions unmarshals the JSON object data into v and its
+// specification extensions into ext.
+func unmarshalJSONWithExtensions(data []byte, v any, ext *Extensions, reserved ...string) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	*ext = nil
+	if !bytes.Contains(data, []byte(`"x-`)) {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for key, raw := range fields {
+		if !IsExtension(key) || slices.Contains(reserved, key) {
+			continue
+		}
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return err
+		}
+		ext.set(key, value)
+	}
+	return nil
+}
+
+// marshalYAMLWithExtensions returns the YAML form of v, a mapping, with ext appended to its fields.
+func marshalYAMLWithExtensions(v any, ext Extensions, reserved ...string) (interface{}, error) {
+	if len(ext) == 0 {
+		return v, nil
+	}
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, err
+	}
+	for _, key := range ext.keys(reserved) {
+		var value yaml.Node
+		if err := value.Encode(ext[key]); err != nil {
+			return nil, err
+		}
+		name := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+		node.Content = append(node.Content, name, &value)
+	}
+	return &node, nil
+}
+
+// unmarshalYAMLWithExtensions decodes the YAML mapping node into v and its
+// specification extensions into ext.
+func unmarshalYAMLWithExtensions(node *yaml.Node, v any, ext *Extensions, reserved ...string) error {
+	if err := node.Decode(v); err != nil {
+		return err
+	}
+	*ext = nil
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if !IsExtension(key) || slices.Contains(reserved, key) {
+			continue
+		}
+		var value any
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return err
+		}
+		ext.set(key, value)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Document) MarshalJSON() ([]byte, error) {
+	type plain Document
+	data, err := marshalJSONWithExtensions(plain(d), d.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	return orderJSONMember(data, "paths", d.PathOrder)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Document) UnmarshalJSON(data []byte) error {
+	type plain Document
+	if err := checkJSONLimits(data); err != nil {
+		return err
+	}
+	if err := unmarshalJSONWithExtensions(data, (*plain)(d), &d.Extensions); err != nil {
+		return err
+	}
+	var err error
+	d.PathOrder, err = jsonMemberKeys(data, "paths")
+	return err
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (d Document) MarshalYAML() (interface{}, error) {
+	type plain Document
+	v, err := marshalYAMLWithExtensions(plain(d), d.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	return orderYAMLMember(v, "paths", d.PathOrder)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *Document) UnmarshalYAML(value *yaml.Node) error {
+	type plain Document
+	if err := checkYAMLLimits(value); err != nil {
+		return err
+	}
+	if err := unmarshalYAMLWithExtensions(value, (*plain)(d), &d.Extensions); err != nil {
+		return err
+	}
+	d.PathOrder = yamlMemberKeys(value, "paths")
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (i Info) MarshalJSON() ([]byte, error) {
+	type plain Info
+	return marshalJSONWithExtensions(plain(i), i.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (i *Info) UnmarshalJSON(data []byte) error {
+	type plain Info
+	return unmarshalJSONWithExtensions(data, (*plain)(i), &i.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (i Info) MarshalYAML() (interface{}, error) {
+	type plain Info
+	return marshalYAMLWithExtensions(plain(i), i.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (i *Info) UnmarshalYAML(value *yaml.Node) error {
+	type plain Info
+	return unmarshalYAMLWithExtensions(value, (*plain)(i), &i.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c Contact) MarshalJSON() ([]byte, error) {
+	type plain Contact
+	return marshalJSONWithExtensions(plain(c), c.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *Contact) UnmarshalJSON(data []byte) error {
+	type plain Contact
+	return unmarshalJSONWithExtensions(data, (*plain)(c), &c.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (c Contact) MarshalYAML() (interface{}, error) {
+	type plain Contact
+	return marshalYAMLWithExtensions(plain(c), c.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (c *Contact) UnmarshalYAML(value *yaml.Node) error {
+	type plain Contact
+	return unmarshalYAMLWithExtensions(value, (*plain)(c), &c.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (l License) MarshalJSON() ([]byte, error) {
+	type plain License
+	return marshalJSONWithExtensions(plain(l), l.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *License) UnmarshalJSON(data []byte) error {
+	type plain License
+	return unmarshalJSONWithExtensions(data, (*plain)(l), &l.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (l License) MarshalYAML() (interface{}, error) {
+	type plain License
+	return marshalYAMLWithExtensions(plain(l), l.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (l *License) UnmarshalYAML(value *yaml.Node) error {
+	type plain License
+	return unmarshalYAMLWithExtensions(value, (*plain)(l), &l.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Server) MarshalJSON() ([]byte, error) {
+	type plain Server
+	return marshalJSONWithExtensions(plain(s), s.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Server) UnmarshalJSON(data []byte) error {
+	type plain Server
+	return unmarshalJSONWithExtensions(data, (*plain)(s), &s.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (s Server) MarshalYAML() (interface{}, error) {
+	type plain Server
+	return marshalYAMLWithExtensions(plain(s), s.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (s *Server) UnmarshalYAML(value *yaml.Node) error {
+	type plain Server
+	return unmarshalYAMLWithExtensions(value, (*plain)(s), &s.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v ServerVariable) MarshalJSON() ([]byte, error) {
+	type plain ServerVariable
+	return marshalJSONWithExtensions(plain(v), v.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *ServerVariable) UnmarshalJSON(data []byte) error {
+	type plain ServerVariable
+	return unmarshalJSONWithExtensions(data, (*plain)(v), &v.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (v ServerVariable) MarshalYAML() (interface{}, error) {
+	type plain ServerVariable
+	return marshalYAMLWithExtensions(plain(v), v.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (v *ServerVariable) UnmarshalYAML(value *yaml.Node) error {
+	type plain ServerVariable
+	return unmarshalYAMLWithExtensions(value, (*plain)(v), &v.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (p PathItem) MarshalJSON() ([]byte, error) {
+	type plain PathItem
+	return marshalJSONWithExtensions(plain(p), p.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *PathItem) UnmarshalJSON(data []byte) error {
+	type plain PathItem
+	return unmarshalJSONWithExtensions(data, (*plain)(p), &p.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (p PathItem) MarshalYAML() (interface{}, error) {
+	type plain PathItem
+	return marshalYAMLWithExtensions(plain(p), p.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (p *PathItem) UnmarshalYAML(value *yaml.Node) error {
+	type plain PathItem
+	return unmarshalYAMLWithExtensions(value, (*plain)(p), &p.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type plain Operation
+	data, err := marshalJSONWithExtensions(plain(o), o.Extensions, "x-owner")
+	if err != nil {
+		return nil, err
+	}
+	return orderJSONMember(data, "responses", o.ResponseOrder)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	type plain Operation
+	if err := unmarshalJSONWithExtensions(data, (*plain)(o), &o.Extensions, "x-owner"); err != nil {
+		return err
+	}
+	var err error
+	o.ResponseOrder, err = jsonMemberKeys(data, "responses")
+	return err
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (o Operation) MarshalYAML() (interface{}, error) {
+	type plain Operation
+	v, err := marshalYAMLWithExtensions(plain(o), o.Extensions, "x-owner")
+	if err != nil {
+		return nil, err
+	}
+	return orderYAMLMember(v, "responses", o.ResponseOrder)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (o *Operation) UnmarshalYAML(value *yaml.Node) error {
+	type plain Operation
+	if err := unmarshalYAMLWithExtensions(value, (*plain)(o), &o.Extensions, "x-owner"); err != nil {
+		return err
+	}
+	o.ResponseOrder = yamlMemberKeys(value, "responses")
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e ExternalDocumentation) MarshalJSON() ([]byte, error) {
+	type plain ExternalDocumentation
+	return marshalJSONWithExtensions(plain(e), e.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *ExternalDocumentation) UnmarshalJSON(data []byte) error {
+	type plain ExternalDocumentation
+	return unmarshalJSONWithExtensions(data, (*plain)(e), &e.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (e ExternalDocumentation) MarshalYAML() (interface{}, error) {
+	type plain ExternalDocumentation
+	return marshalYAMLWithExtensions(plain(e), e.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (e *ExternalDocumentation) UnmarshalYAML(value *yaml.Node) error {
+	type plain ExternalDocumentation
+	return unmarshalYAMLWithExtensions(value, (*plain)(e), &e.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	type plain Parameter
+	return marshalJSONWithExtensions(plain(p), p.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *Parameter) UnmarshalJSON(data []byte) error {
+	type plain Parameter
+	return unmarshalJSONWithExtensions(data, (*plain)(p), &p.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (p Parameter) MarshalYAML() (interface{}, error) {
+	type plain Parameter
+	return marshalYAMLWithExtensions(plain(p), p.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (p *Parameter) UnmarshalYAML(value *yaml.Node) error {
+	type plain Parameter
+	return unmarshalYAMLWithExtensions(value, (*plain)(p), &p.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b RequestBody) MarshalJSON() ([]byte, error) {
+	type plain RequestBody
+	return marshalJSONWithExtensions(plain(b), b.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *RequestBody) UnmarshalJSON(data []byte) error {
+	type plain RequestBody
+	return unmarshalJSONWithExtensions(data, (*plain)(b), &b.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (b RequestBody) MarshalYAML() (interface{}, error) {
+	type plain RequestBody
+	return marshalYAMLWithExtensions(plain(b), b.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (b *RequestBody) UnmarshalYAML(value *yaml.Node) error {
+	type plain RequestBody
+	return unmarshalYAMLWithExtensions(value, (*plain)(b), &b.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m MediaType) MarshalJSON() ([]byte, error) {
+	type plain MediaType
+	return marshalJSONWithExtensions(plain(m), m.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m *MediaType) UnmarshalJSON(data []byte) error {
+	type plain MediaType
+	return unmarshalJSONWithExtensions(data, (*plain)(m), &m.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (m MediaType) MarshalYAML() (interface{}, error) {
+	type plain MediaType
+	return marshalYAMLWithExtensions(plain(m), m.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (m *MediaType) UnmarshalYAML(value *yaml.Node) error {
+	type plain MediaType
+	return unmarshalYAMLWithExtensions(value, (*plain)(m), &m.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e Encoding) MarshalJSON() ([]byte, error) {
+	type plain Encoding
+	return marshalJSONWithExtensions(plain(e), e.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *Encoding) UnmarshalJSON(data []byte) error {
+	type plain Encoding
+	return unmarshalJSONWithExtensions(data, (*plain)(e), &e.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (e Encoding) MarshalYAML() (interface{}, error) {
+	type plain Encoding
+	return marshalYAMLWithExtensions(plain(e), e.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (e *Encoding) UnmarshalYAML(value *yaml.Node) error {
+	type plain Encoding
+	return unmarshalYAMLWithExtensions(value, (*plain)(e), &e.Extensions)
+}
+
+// responseRef is a Response given by reference. The referenced response
+// carries the required description, so it is only written when overridden.
+type responseRef struct {
+	Ref         string `json:"$ref" yaml:"$ref"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r Response) MarshalJSON() ([]byte, error) {
+	if r.Ref != "" {
+		return json.Marshal(responseRef{Ref: r.Ref, Description: r.Description})
+	}
+	type plain Response
+	return marshalJSONWithExtensions(plain(r), r.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *Response) UnmarshalJSON(data []byte) error {
+	type plain Response
+	return unmarshalJSONWithExtensions(data, (*plain)(r), &r.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (r Response) MarshalYAML() (interface{}, error) {
+	if r.Ref != "" {
+		return responseRef{Ref: r.Ref, Description: r.Description}, nil
+	}
+	type plain Response
+	return marshalYAMLWithExtensions(plain(r), r.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (r *Response) UnmarshalYAML(value *yaml.Node) error {
+	type plain Response
+	return unmarshalYAMLWithExtensions(value, (*plain)(r), &r.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (h Header) MarshalJSON() ([]byte, error) {
+	type plain Header
+	return marshalJSONWithExtensions(plain(h), h.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (h *Header) UnmarshalJSON(data []byte) error {
+	type plain Header
+	return unmarshalJSONWithExtensions(data, (*plain)(h), &h.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (h Header) MarshalYAML() (interface{}, error) {
+	type plain Header
+	return marshalYAMLWithExtensions(plain(h), h.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (h *Header) UnmarshalYAML(value *yaml.Node) error {
+	type plain Header
+	return unmarshalYAMLWithExtensions(value, (*plain)(h), &h.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (l Link) MarshalJSON() ([]byte, error) {
+	type plain Link
+	return marshalJSONWithExtensions(plain(l), l.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *Link) UnmarshalJSON(data []byte) error {
+	type plain Link
+	return unmarshalJSONWithExtensions(data, (*plain)(l), &l.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (l Link) MarshalYAML() (interface{}, error) {
+	type plain Link
+	return marshalYAMLWithExtensions(plain(l), l.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (l *Link) UnmarshalYAML(value *yaml.Node) error {
+	type plain Link
+	return unmarshalYAMLWithExtensions(value, (*plain)(l), &l.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e Example) MarshalJSON() ([]byte, error) {
+	type plain Example
+	return marshalJSONWithExtensions(plain(e), e.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *Example) UnmarshalJSON(data []byte) error {
+	type plain Example
+	return unmarshalJSONWithExtensions(data, (*plain)(e), &e.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (e Example) MarshalYAML() (interface{}, error) {
+	type plain Example
+	return marshalYAMLWithExtensions(plain(e), e.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (e *Example) UnmarshalYAML(value *yaml.Node) error {
+	type plain Example
+	return unmarshalYAMLWithExtensions(value, (*plain)(e), &e.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t Tag) MarshalJSON() ([]byte, error) {
+	type plain Tag
+	return marshalJSONWithExtensions(plain(t), t.Extensions, "x-owner")
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	type plain Tag
+	return unmarshalJSONWithExtensions(data, (*plain)(t), &t.Extensions, "x-owner")
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (t Tag) MarshalYAML() (interface{}, error) {
+	type plain Tag
+	return marshalYAMLWithExtensions(plain(t), t.Extensions, "x-owner")
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (t *Tag) UnmarshalYAML(value *yaml.Node) error {
+	type plain Tag
+	return unmarshalYAMLWithExtensions(value, (*plain)(t), &t.Extensions, "x-owner")
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c Components) MarshalJSON() ([]byte, error) {
+	type plain Components
+	return marshalJSONWithExtensions(plain(c), c.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *Components) UnmarshalJSON(data []byte) error {
+	type plain Components
+	return unmarshalJSONWithExtensions(data, (*plain)(c), &c.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (c Components) MarshalYAML() (interface{}, error) {
+	type plain Components
+	return marshalYAMLWithExtensions(plain(c), c.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (c *Components) UnmarshalYAML(value *yaml.Node) error {
+	type plain Components
+	return unmarshalYAMLWithExtensions(value, (*plain)(c), &c.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SecurityScheme) MarshalJSON() ([]byte, error) {
+	type plain SecurityScheme
+	return marshalJSONWithExtensions(plain(s), s.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SecurityScheme) UnmarshalJSON(data []byte) error {
+	type plain SecurityScheme
+	return unmarshalJSONWithExtensions(data, (*plain)(s), &s.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (s SecurityScheme) MarshalYAML() (interface{}, error) {
+	type plain SecurityScheme
+	return marshalYAMLWithExtensions(plain(s), s.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (s *SecurityScheme) UnmarshalYAML(value *yaml.Node) error {
+	type plain SecurityScheme
+	return unmarshalYAMLWithExtensions(value, (*plain)(s), &s.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (f OAuthFlows) MarshalJSON() ([]byte, error) {
+	type plain OAuthFlows
+	return marshalJSONWithExtensions(plain(f), f.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (f *OAuthFlows) UnmarshalJSON(data []byte) error {
+	type plain OAuthFlows
+	return unmarshalJSONWithExtensions(data, (*plain)(f), &f.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (f OAuthFlows) MarshalYAML() (interface{}, error) {
+	type plain OAuthFlows
+	return marshalYAMLWithExtensions(plain(f), f.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (f *OAuthFlows) UnmarshalYAML(value *yaml.Node) error {
+	type plain OAuthFlows
+	return unmarshalYAMLWithExtensions(value, (*plain)(f), &f.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (f OAuthFlow) MarshalJSON() ([]byte, error) {
+	type plain OAuthFlow
+	return marshalJSONWithExtensions(plain(f), f.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (f *OAuthFlow) UnmarshalJSON(data []byte) error {
+	type plain OAuthFlow
+	return unmarshalJSONWithExtensions(data, (*plain)(f), &f.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (f OAuthFlow) MarshalYAML() (interface{}, error) {
+	type plain OAuthFlow
+	return marshalYAMLWithExtensions(plain(f), f.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (f *OAuthFlow) UnmarshalYAML(value *yaml.Node) error {
+	type plain OAuthFlow
+	return unmarshalYAMLWithExtensions(value, (*plain)(f), &f.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+	data, err := marshalJSONWithExtensions(plain(s), s.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	return orderJSONMember(data, "properties", s.PropertyOrder)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type plain Schema
+	if err := unmarshalJSONWithExtensions(data, (*plain)(s), &s.Extensions); err != nil {
+		return err
+	}
+	var err error
+	s.PropertyOrder, err = jsonMemberKeys(data, "properties")
+	return err
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (s Schema) MarshalYAML() (interface{}, error) {
+	type plain Schema
+	v, err := marshalYAMLWithExtensions(plain(s), s.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	return orderYAMLMember(v, "properties", s.PropertyOrder)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (s *Schema) UnmarshalYAML(value *yaml.Node) error {
+	type plain Schema
+	if err := unmarshalYAMLWithExtensions(value, (*plain)(s), &s.Extensions); err != nil {
+		return err
+	}
+	s.PropertyOrder = yamlMemberKeys(value, "properties")
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Discriminator) MarshalJSON() ([]byte, error) {
+	type plain Discriminator
+	return marshalJSONWithExtensions(plain(d), d.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Discriminator) UnmarshalJSON(data []byte) error {
+	type plain Discriminator
+	return unmarshalJSONWithExtensions(data, (*plain)(d), &d.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (d Discriminator) MarshalYAML() (interface{}, error) {
+	type plain Discriminator
+	return marshalYAMLWithExtensions(plain(d), d.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *Discriminator) UnmarshalYAML(value *yaml.Node) error {
+	type plain Discriminator
+	return unmarshalYAMLWithExtensions(value, (*plain)(d), &d.Extensions)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (x XML) MarshalJSON() ([]byte, error) {
+	type plain XML
+	return marshalJSONWithExtensions(plain(x), x.Extensions)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (x *XML) UnmarshalJSON(data []byte) error {
+	type plain XML
+	return unmarshalJSONWithExtensions(data, (*plain)(x), &x.Extensions)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (x XML) MarshalYAML() (interface{}, error) {
+	type plain XML
+	return marshalYAMLWithExtensions(plain(x), x.Extensions)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (x *XML) UnmarshalYAML(value *yaml.Node) error {
+	type plain XML
+	return unmarshalYAMLWithExtensions(value, (*plain)(x), &x.Extensions)
+}
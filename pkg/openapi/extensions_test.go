@@ -0,0 +1,229 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func extensionsDocument() *Document {
+	return &Document{
+		OpenAPI:    "3.0.3",
+		Info:       Info{Title: "Test API", Version: "1.0.0"},
+		Extensions: Extensions{"x-tagGroups": []any{"pets"}},
+		Paths: Paths{
+			"/pets": &PathItem{
+				Get: &Operation{
+					OperationID: "listPets",
+					Owner:       &Owner{Team: "team-pets"},
+					Extensions:  Extensions{"x-internal": true, "x-owner": "ignored"},
+				},
+			},
+		},
+		Components: &Components{
+			Schemas: map[string]*Schema{
+				"Pet": {
+					Type:       NewSchemaType(TypeObject),
+					Extensions: Extensions{"x-go-type": "pets.Pet"},
+					Properties: map[string]*Schema{
+						"age": {Type: NewSchemaType(TypeInteger), Extensions: Extensions{"x-go-name": "PetAge"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestExtensions_JSON(t *testing.T) {
+	doc := extensionsDocument()
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`"x-tagGroups":["pets"]}`,
+		`"operationId":"listPets","x-owner":{"team":"team-pets"},"x-internal":true}`,
+		`"properties":{"age":{"type":"integer","x-go-name":"PetAge"}},"x-go-type":"pets.Pet"}`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("JSON missing %s:\n%s", want, data)
+		}
+	}
+
+	var decoded Document
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	assertExtensions(t, &decoded)
+}
+
+func TestExtensions_YAML(t *testing.T) {
+	doc := extensionsDocument()
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		t.Fatalf("yaml.Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), "x-go-type: pets.Pet") {
+		t.Errorf("YAML missing schema extension:\n%s", data)
+	}
+
+	var decoded Document
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+	assertExtensions(t, &decoded)
+}
+
+func TestResponse_Ref(t *testing.T) {
+	responses := Responses{
+		"404": {Ref: "#/components/responses/NotFound"},
+		"410": {Ref: "#/components/responses/Gone", Description: "Deleted user"},
+		"200": {Description: ""},
+	}
+	data, err := json.Marshal(responses)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"200":{"description":""},"404":{"$ref":"#/components/responses/NotFound"},"410":{"$ref":"#/components/responses/Gone","description":"Deleted user"}}`
+	if string(data) != want {
+		t.Errorf("JSON = %s, want %s", data, want)
+	}
+
+	data, err = yaml.Marshal(responses)
+	if err != nil {
+		t.Fatalf("yaml.Marshal() error = %v", err)
+	}
+	if strings.Count(string(data), "description") != 2 {
+		t.Errorf("YAML should only write the description of references that override it:\n%s", data)
+	}
+}
+
+func assertExtensions(t *testing.T, doc *Document) {
+	t.Helper()
+	if want := (Extensions{"x-tagGroups": []any{"pets"}}); !reflect.DeepEqual(doc.Extensions, want) {
+		t.Errorf("document extensions = %v, want %v", doc.Extensions, want)
+	}
+	op := doc.Paths["/pets"].Get
+	if want := (Extensions{"x-internal": true}); !reflect.DeepEqual(op.Extensions, want) {
+		t.Errorf("operation extensions = %v, want %v", op.Extensions, want)
+	}
+	if op.Owner == nil || op.Owner.Team != "team-pets" {
+		t.Errorf("operation owner = %+v, want team-pets", op.Owner)
+	}
+	pet := doc.Components.Schemas["Pet"]
+	if want := (Extensions{"x-go-type": "pets.Pet"}); !reflect.DeepEqual(pet.Extensions, want) {
+		t.Errorf("schema extensions = %v, want %v", pet.Extensions, want)
+	}
+	if want := (Extensions{"x-go-name": "PetAge"}); !reflect.DeepEqual(pet.Properties["age"].Extensions, want) {
+		t.Errorf("property extensions = %v, want %v", pet.Properties["age"].Extensions, want)
+	}
+}
+
+// extensionsSpec has an extension on every object that can carry one.
+const extensionsSpec = `openapi: 3.1.0
+x-api: root
+info:
+  title: Pets
+  version: 1.0.0
+  x-info: 1
+  contact: {name: Team, x-contact: true}
+  license: {name: MIT, x-license: [a, b]}
+servers:
+  - url: https://{env}.example.com
+    x-server: s
+    variables:
+      env: {default: prod, x-var: v}
+externalDocs: {url: https://docs.example.com, x-docs: d}
+tags:
+  - {name: pets, x-owner: {team: team-pets}, x-tag: t}
+paths:
+  /pets/{id}:
+    x-path: p
+    get:
+      operationId: getPet
+      x-op: {nested: {deep: true}}
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string, x-schema: s}, x-param: p}
+      requestBody:
+        x-body: b
+        content:
+          multipart/form-data:
+            x-media: m
+            encoding:
+              file: {contentType: image/png, x-encoding: e}
+      responses:
+        "200":
+          description: OK
+          x-response: r
+          headers:
+            X-Rate: {schema: {type: integer}, x-header: h}
+          links:
+            self: {operationId: getPet, x-link: l}
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+              examples:
+                dog: {value: {name: dog}, x-example: e}
+components:
+  x-components: c
+  schemas:
+    Pet:
+      type: object
+      x-go-type: pets.Pet
+      discriminator: {propertyName: kind, x-discriminator: d}
+      xml: {name: pet, x-xml: x}
+  securitySchemes:
+    oauth:
+      type: oauth2
+      x-scheme: s
+      flows:
+        x-flows: f
+        implicit: {authorizationUrl: https://auth.example.com, scopes: {read: Read pets}, x-flow: f}
+`
+
+func TestExtensions_RoundTrip(t *testing.T) {
+	var doc Document
+	if err := yaml.Unmarshal([]byte(extensionsSpec), &doc); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+	var raw map[string]any
+	if err := yaml.Unmarshal([]byte(extensionsSpec), &raw); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+
+	got, want := jsonTree(t, &doc), jsonTree(t, raw)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip lost data:\n got  %v\n want %v", got, want)
+	}
+
+	data, err := yaml.Marshal(&doc)
+	if err != nil {
+		t.Fatalf("yaml.Marshal() error = %v", err)
+	}
+	var again Document
+	if err := yaml.Unmarshal(data, &again); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+	if got := jsonTree(t, &again); !reflect.DeepEqual(got, want) {
+		t.Errorf("YAML round trip lost data:\n got  %v\n want %v", got, want)
+	}
+}
+
+// jsonTree returns v marshaled to JSON and decoded into generic values.
+func jsonTree(t *testing.T, v any) any {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	return tree
+}
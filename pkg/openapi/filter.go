@@ -0,0 +1,266 @@
+package openapi
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// FilterOptions selects the operations Filter keeps. An operation is kept if
+// it matches every include option that is set and none of the exclude
+// options. Path patterns match path templates, or webhook names, with *
+// matching any characters, e.g. /internal/* matches /internal/users/{id}.
+type FilterOptions struct {
+	// Tags keeps the operations with one of these tags; DefaultTag selects untagged operations
+	Tags []string
+
+	// Paths keeps the operations whose path matches one of these patterns
+	Paths []string
+
+	// OperationIDs keeps the operations with one of these operationIds
+	OperationIDs []string
+
+	// ExcludeTags drops the operations with one of these tags
+	ExcludeTags []string
+
+	// ExcludePaths drops the operations whose path matches one of these patterns
+	ExcludePaths []string
+
+	// ExcludeOperationIDs drops the operations with one of these operationIds
+	ExcludeOperationIDs []string
+}
+
+// Filter returns a copy of doc with only the operations opts selects, for
+// carving a partner-facing spec out of a full internal one. Path items left
+// without operations are dropped, as are tag declarations, components, and
+// security schemes that the remaining operations no longer use. The copy
+// shares the objects it keeps with doc.
+func Filter(doc *Document, opts FilterOptions) (*Document, error) {
+	filtered := *doc
+	filtered.Paths = filterOperations(doc.Paths, opts.keeps)
+	filtered.Webhooks = filterOperations(doc.Webhooks, opts.keeps)
+	filtered.PathOrder = slices.DeleteFunc(slices.Clone(doc.PathOrder), func(path string) bool {
+		return filtered.Paths[path] == nil
+	})
+
+	used := filtered.usedTags()
+	filtered.Tags = slices.DeleteFunc(slices.Clone(doc.Tags), func(t Tag) bool { return !used[t.Name] })
+
+	if doc.Components != nil {
+		components, err := filtered.usedComponents()
+		if err != nil {
+			return nil, err
+		}
+		filtered.Components = components
+	}
+	return &filtered, nil
+}
+
+// keeps reports whether the operation op at path is selected.
+func (o FilterOptions) keeps(path string, op *Operation) bool {
+	tags := operationTags(op)
+	if len(o.Tags) > 0 && !containsAny(o.Tags, tags) {
+		return false
+	}
+	if len(o.Paths) > 0 && !matchesAny(o.Paths, path) {
+		return false
+	}
+	if len(o.OperationIDs) > 0 && !slices.Contains(o.OperationIDs, op.OperationID) {
+		return false
+	}
+	return !containsAny(o.ExcludeTags, tags) &&
+		!matchesAny(o.ExcludePaths, path) &&
+		!slices.Contains(o.ExcludeOperationIDs, op.OperationID)
+}
+
+// containsAny reports whether list contains one of values.
+func containsAny(list, values []string) bool {
+	return slices.ContainsFunc(values, func(v string) bool { return slices.Contains(list, v) })
+}
+
+// matchesAny reports whether path matches one of patterns.
+func matchesAny(patterns []string, path string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool { return matchPattern(pattern, path) })
+}
+
+// matchPattern reports whether s matches pattern, in which * matches any characters.
+func matchPattern(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
+
+// filterOperations returns copies of the path items with only the operations
+// keep selects, or nil if none has any.
+func filterOperations(items map[string]*PathItem, keep func(path string, op *Operation) bool) map[string]*PathItem {
+	var filtered map[string]*PathItem
+	for path, item := range items {
+		if item == nil {
+			continue
+		}
+		copied := *item
+		kept := false
+		for _, op := range []**Operation{
+			&copied.Get, &copied.Put, &copied.Post, &copied.Delete,
+			&copied.Options, &copied.Head, &copied.Patch, &copied.Trace,
+		} {
+			if *op != nil && !keep(path, *op) {
+				*op = nil
+			}
+			kept = kept || *op != nil
+		}
+		if kept {
+			if filtered == nil {
+				filtered = make(map[string]*PathItem)
+			}
+			filtered[path] = &copied
+		}
+	}
+	return filtered
+}
+
+// usedComponents returns a copy of the document's components with only the
+// ones its paths, webhooks, and security requirements use, directly or
+// through other components.
+func (d *Document) usedComponents() (*Components, error) {
+	src := d.Components
+	used := &Components{Extensions: src.Extensions}
+
+	rest := *d
+	rest.Components = nil
+	queue, err := componentRefs(rest)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		kind, name, _ := strings.Cut(strings.TrimPrefix(ref, "#/components/"), "/")
+		component := used.keep(src, kind, name)
+		if component == nil {
+			continue
+		}
+		refs, err := componentRefs(component)
+		if err != nil {
+			return nil, err
+		}
+		queue = append(queue, refs...)
+	}
+
+	for _, name := range d.securitySchemeNames() {
+		if scheme, ok := src.SecuritySchemes[name]; ok {
+			keepComponent(&used.SecuritySchemes, name, scheme)
+		}
+	}
+	return used, nil
+}
+
+// keep copies the component kind/name from src to c, returning it, or nil if src has no such component.
+func (c *Components) keep(src *Components, kind, name string) any {
+	switch kind {
+	case "schemas":
+		return keepComponent(&c.Schemas, name, src.Schemas[name])
+	case "responses":
+		return keepComponent(&c.Responses, name, src.Responses[name])
+	case "parameters":
+		return keepComponent(&c.Parameters, name, src.Parameters[name])
+	case "examples":
+		return keepComponent(&c.Examples, name, src.Examples[name])
+	case "requestBodies":
+		return keepComponent(&c.RequestBodies, name, src.RequestBodies[name])
+	case "headers":
+		return keepComponent(&c.Headers, name, src.Headers[name])
+	case "links":
+		return keepComponent(&c.Links, name, src.Links[name])
+	case "callbacks":
+		return keepComponent(&c.Callbacks, name, src.Callbacks[name])
+	case "pathItems":
+		return keepComponent(&c.PathItems, name, src.PathItems[name])
+	}
+	return nil
+}
+
+// keepComponent adds component to *m under name, returning it, or nil if component is nil.
+func keepComponent[V any](m *map[string]*V, name string, component *V) any {
+	if component == nil {
+		return nil
+	}
+	if *m == nil {
+		*m = make(map[string]*V)
+	}
+	(*m)[name] = component
+	return component
+}
+
+// componentRefs returns the local component references in v: its $refs and
+// discriminator mappings.
+func componentRefs(v any) ([]string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	var refs []string
+	var walk func(any)
+	walk = func(node any) {
+		switch node := node.(type) {
+		case map[string]any:
+			for _, child := range node {
+				walk(child)
+			}
+		case []any:
+			for _, child := range node {
+				walk(child)
+			}
+		case string:
+			if strings.HasPrefix(node, "#/components/") {
+				refs = append(refs, node)
+			}
+		}
+	}
+	walk(tree)
+	return refs, nil
+}
+
+// securitySchemeNames returns the names of the security schemes the
+// document's security requirements and operations use.
+func (d *Document) securitySchemeNames() []string {
+	var names []string
+	add := func(requirements []SecurityRequirement) {
+		for _, requirement := range requirements {
+			for name := range requirement {
+				names = append(names, name)
+			}
+		}
+	}
+	add(d.Security)
+	for _, items := range []map[string]*PathItem{d.Paths, d.Webhooks} {
+		for _, item := range items {
+			for _, op := range pathItemOperations(item) {
+				add(op.Security)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,103 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func filterTestDocument() *Document {
+	doc := sliceTestDocument()
+	doc.Paths["/internal/pets/{id}"] = &PathItem{
+		Delete: &Operation{
+			OperationID: "purgePet", Tags: []string{"pet"},
+			Parameters: []*Parameter{RefToParameter("PetID")},
+			Security:   []SecurityRequirement{{"admin_key": {}}},
+		},
+	}
+	doc.Paths["/pets"].Get.Security = []SecurityRequirement{{"petstore_auth": {"read:pets"}}}
+	doc.Paths["/pets"].Get.Responses["404"] = RefToResponse("NotFound")
+	doc.Components.Schemas["Error"] = ObjectSchema()
+	doc.Components.Parameters = map[string]*Parameter{"PetID": {Name: "id", In: "path", Schema: StringSchema()}}
+	doc.Components.Responses = map[string]*Response{
+		"NotFound": {Description: "Not found", Content: map[string]MediaType{
+			"application/json": {Schema: RefTo("Error")},
+		}},
+	}
+	doc.Components.SecuritySchemes = map[string]*SecurityScheme{
+		"petstore_auth": {Type: "oauth2"},
+		"admin_key":     {Type: "apiKey", Name: "X-Admin", In: "header"},
+	}
+	return doc
+}
+
+func TestFilter(t *testing.T) {
+	doc := filterTestDocument()
+	filtered, err := Filter(doc, FilterOptions{Tags: []string{"pet"}, ExcludePaths: []string{"/internal/*"}})
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+
+	assertKeys(t, "paths", filtered.Paths, "/pets")
+	if len(filtered.Tags) != 1 || filtered.Tags[0].Name != "pet" {
+		t.Errorf("tags = %+v, want the pet declaration", filtered.Tags)
+	}
+
+	c := filtered.Components
+	assertKeys(t, "schemas", c.Schemas, "Category", "Error", "Pet")
+	assertKeys(t, "responses", c.Responses, "NotFound")
+	assertKeys(t, "parameters", c.Parameters)
+	assertKeys(t, "security schemes", c.SecuritySchemes, "petstore_auth")
+
+	if len(doc.Paths) != 4 || len(doc.Components.Schemas) != 4 || len(doc.Components.SecuritySchemes) != 2 {
+		t.Error("Filter modified the document")
+	}
+}
+
+func TestFilter_OperationIDs(t *testing.T) {
+	filtered, err := Filter(filterTestDocument(), FilterOptions{
+		OperationIDs:        []string{"addPet", "purgePet", "health"},
+		ExcludeOperationIDs: []string{"health"},
+	})
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+
+	if item := filtered.Paths["/pets"]; item == nil || item.Get != nil || item.Post == nil {
+		t.Errorf("/pets = %+v, want POST only", item)
+	}
+	assertKeys(t, "paths", filtered.Paths, "/internal/pets/{id}", "/pets")
+	c := filtered.Components
+	assertKeys(t, "parameters", c.Parameters, "PetID")
+	assertKeys(t, "schemas", c.Schemas)
+	assertKeys(t, "responses", c.Responses)
+	assertKeys(t, "security schemes", c.SecuritySchemes, "admin_key")
+}
+
+// assertKeys fails unless the keys of m are want.
+func assertKeys[V any](t *testing.T, what string, m map[string]V, want ...string) {
+	t.Helper()
+	if got := slices.Sorted(maps.Keys(m)); !slices.Equal(got, want) {
+		t.Errorf("%s = %v, want %v", what, got, want)
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"/pets", "/pets", true},
+		{"/pets", "/pets/{id}", false},
+		{"/internal/*", "/internal/users/{id}", true},
+		{"/internal/*", "/internal", false},
+		{"*/admin", "/v1/admin", true},
+		{"/v*/pets/*", "/v2/pets/{id}", true},
+		{"/v*/pets/*", "/v2/users/{id}", false},
+	}
+	for _, tt := range tests {
+		if got := matchPattern(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchPattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
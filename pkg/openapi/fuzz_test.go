@@ -0,0 +1,66 @@
+package openapi
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// addSpecSeeds adds the example spec and the test documents as seeds.
+func addSpecSeeds(f *testing.F, marshal func(any) ([]byte, error)) {
+	if data, err := os.ReadFile("../../example.json"); err == nil {
+		f.Add(data)
+	}
+	for _, doc := range []*Document{sliceTestDocument(), filterTestDocument(), orderedDocument()} {
+		data, err := marshal(doc)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Add([]byte(`{"openapi":"3.1.0","paths":{"/a":null,"/b":{"get":null}},"components":{"schemas":{"A":{"$ref":"#/components/schemas/A"}}}}`))
+}
+
+// exerciseDocument marshals a loaded document and runs the document
+// operations over it, which must not panic.
+func exerciseDocument(t *testing.T, doc *Document) {
+	if _, err := json.Marshal(doc); err != nil {
+		t.Errorf("json.Marshal() error = %v", err)
+	}
+	if _, err := yaml.Marshal(doc); err != nil {
+		t.Errorf("yaml.Marshal() error = %v", err)
+	}
+	_, _ = ConvertTo31(doc)
+	_, _ = ConvertTo30(doc)
+	_, _ = Filter(doc, FilterOptions{Tags: []string{"pet"}})
+	for _, tag := range doc.OperationTags() {
+		doc.TagSlice(tag)
+	}
+	doc.DeprecatedProperties()
+}
+
+// FuzzLoadJSON checks that loading arbitrary JSON never panics.
+func FuzzLoadJSON(f *testing.F) {
+	addSpecSeeds(f, json.Marshal)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var doc Document
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return
+		}
+		exerciseDocument(t, &doc)
+	})
+}
+
+// FuzzLoadYAML checks that loading arbitrary YAML never panics.
+func FuzzLoadYAML(f *testing.F) {
+	addSpecSeeds(f, yaml.Marshal)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var doc Document
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return
+		}
+		exerciseDocument(t, &doc)
+	})
+}
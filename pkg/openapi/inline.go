@@ -0,0 +1,149 @@
+package openapi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CircularRefError reports a component schema that refers back to itself,
+// directly or through other schemas, and so cannot be inlined.
+type CircularRefError struct {
+	// Chain lists the $refs followed from the first schema of the cycle back
+	// to it, such as #/components/schemas/Category twice for a category
+	// with child categories
+	Chain []string
+}
+
+func (e *CircularRefError) Error() string {
+	return "circular reference: " + strings.Join(e.Chain, " -> ")
+}
+
+// InlineOptions configures Inline.
+type InlineOptions struct {
+	// KeepCircularRefs leaves the $ref that closes a cycle in place instead
+	// of failing with a CircularRefError, so recursive schemas are inlined
+	// down to the point where they refer back to themselves
+	KeepCircularRefs bool
+}
+
+// Inline returns a copy of doc whose paths, webhooks, and components other
+// than schemas use copies of the component schemas in place of $refs to
+// them, for tools that do not follow $refs. The component schemas are kept,
+// as the $refs left in place by KeepCircularRefs point to them; Prune drops
+// those no longer used. The input document is not modified.
+func Inline(doc *Document, opts InlineOptions) (*Document, error) {
+	out, err := cloneDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	if out.Components == nil || len(out.Components.Schemas) == 0 {
+		return out, nil
+	}
+
+	inl := inliner{schemas: out.Components.Schemas, opts: opts}
+	visit := func(s *Schema) {
+		// nested schemas are visited first, so the inlined copies are not
+		// visited again
+		if err != nil || s.Ref == "" {
+			return
+		}
+		var inlined *Schema
+		if inlined, err = inl.inline(s, nil); err == nil {
+			*s = *inlined
+		}
+	}
+	for _, item := range out.Paths {
+		walkPathItemSchemas(item, visit)
+	}
+	for _, item := range out.Webhooks {
+		walkPathItemSchemas(item, visit)
+	}
+	rest := *out.Components
+	rest.Schemas = nil
+	walkComponentSchemas(&rest, visit)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type inliner struct {
+	schemas map[string]*Schema
+	opts    InlineOptions
+}
+
+// inline returns a copy of s with the $refs to component schemas replaced.
+// chain holds the $refs being inlined, to detect cycles.
+func (inl inliner) inline(s *Schema, chain []string) (*Schema, error) {
+	if s == nil {
+		return nil, nil
+	}
+	if s.Ref != "" {
+		return inl.inlineRef(s, chain)
+	}
+
+	out := *s
+	var err error
+	single := func(sub *Schema) *Schema {
+		if err != nil {
+			return nil
+		}
+		var inlined *Schema
+		inlined, err = inl.inline(sub, chain)
+		return inlined
+	}
+	out.Items = single(s.Items)
+	out.AdditionalProperties = single(s.AdditionalProperties)
+	out.Not = single(s.Not)
+	if s.Properties != nil {
+		out.Properties = make(map[string]*Schema, len(s.Properties))
+		for name, prop := range s.Properties {
+			out.Properties[name] = single(prop)
+		}
+	}
+	for _, group := range []*[]*Schema{&out.AllOf, &out.AnyOf, &out.OneOf} {
+		if *group == nil {
+			continue
+		}
+		subs := make([]*Schema, len(*group))
+		for i, sub := range *group {
+			subs[i] = single(sub)
+		}
+		*group = subs
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (inl inliner) inlineRef(s *Schema, chain []string) (*Schema, error) {
+	name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/")
+	if !ok {
+		// only component schemas are inlined
+		ref := *s
+		return &ref, nil
+	}
+	if i := slices.Index(chain, s.Ref); i >= 0 {
+		if inl.opts.KeepCircularRefs {
+			ref := *s
+			return &ref, nil
+		}
+		return nil, &CircularRefError{Chain: append(slices.Clone(chain[i:]), s.Ref)}
+	}
+	target, ok := inl.schemas[name]
+	if !ok || target == nil {
+		return nil, fmt.Errorf("%s is not defined", s.Ref)
+	}
+	out, err := inl.inline(target, append(slices.Clip(chain), s.Ref))
+	if err != nil {
+		return nil, err
+	}
+	// OpenAPI 3.1 allows a description next to a $ref, overriding the one
+	// of the schema
+	if s.Description != "" {
+		out.Description = s.Description
+	}
+	return out, nil
+}
@@ -0,0 +1,114 @@
+package openapi
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestInline(t *testing.T) {
+	doc := sliceTestDocument()
+	doc.Components.Parameters = map[string]*Parameter{
+		"Order": {Name: "order", In: "query", Schema: RefTo("Order")},
+	}
+
+	inlined, err := Inline(doc, InlineOptions{})
+	if err != nil {
+		t.Fatalf("Inline() error = %v", err)
+	}
+
+	pets := inlined.Paths["/pets"].Get.Responses["200"].Content["application/json"].Schema
+	pet := pets.Items
+	if !isInlined(pet, TypeObject) {
+		t.Fatalf("items = %+v, want the inlined Pet schema", pet)
+	}
+	if category := pet.Properties["category"]; !isInlined(category, TypeString) {
+		t.Errorf("category = %+v, want the inlined Category schema", category)
+	}
+	if order := inlined.Paths["/orders"].Get.Responses["200"].Content["application/json"].Schema; !isInlined(order, TypeObject) {
+		t.Errorf("order = %+v, want the inlined Order schema", order)
+	}
+	if param := inlined.Components.Parameters["Order"].Schema; param.Ref != "" {
+		t.Errorf("parameter schema = %+v, want the inlined Order schema", param)
+	}
+
+	// component schemas keep their $refs
+	if got := inlined.Components.Schemas["Pet"].Properties["category"].Ref; got != "#/components/schemas/Category" {
+		t.Errorf("Pet category $ref = %q, want it kept", got)
+	}
+	if doc.Paths["/pets"].Get.Responses["200"].Content["application/json"].Schema.Items.Ref == "" {
+		t.Error("Inline modified the document")
+	}
+}
+
+// isInlined reports whether schema is an inlined schema of type typ rather
+// than a $ref.
+func isInlined(schema *Schema, typ string) bool {
+	return schema.Ref == "" && slices.Contains(schema.Type, typ)
+}
+
+func recursiveTestDocument() *Document {
+	doc := sliceTestDocument()
+	doc.Components.Schemas["Category"] = &Schema{
+		Type: NewSchemaType(TypeObject),
+		Properties: map[string]*Schema{
+			"name":     StringSchema(),
+			"children": ArraySchema(RefTo("Category")),
+		},
+	}
+	return doc
+}
+
+func TestInline_CircularRef(t *testing.T) {
+	_, err := Inline(recursiveTestDocument(), InlineOptions{})
+	var circular *CircularRefError
+	if !errors.As(err, &circular) {
+		t.Fatalf("Inline() error = %v, want a CircularRefError", err)
+	}
+	want := []string{"#/components/schemas/Category", "#/components/schemas/Category"}
+	if !reflect.DeepEqual(circular.Chain, want) {
+		t.Errorf("chain = %v, want %v", circular.Chain, want)
+	}
+	if got := err.Error(); got != "circular reference: #/components/schemas/Category -> #/components/schemas/Category" {
+		t.Errorf("error = %q", got)
+	}
+
+	doc := sliceTestDocument()
+	doc.Components.Schemas["Order"] = &Schema{AllOf: []*Schema{RefTo("Invoice")}}
+	doc.Components.Schemas["Invoice"] = &Schema{Properties: map[string]*Schema{"order": RefTo("Order")}}
+	_, err = Inline(doc, InlineOptions{})
+	if !errors.As(err, &circular) {
+		t.Fatalf("Inline() error = %v, want a CircularRefError", err)
+	}
+	want = []string{"#/components/schemas/Order", "#/components/schemas/Invoice", "#/components/schemas/Order"}
+	if !reflect.DeepEqual(circular.Chain, want) {
+		t.Errorf("chain = %v, want %v", circular.Chain, want)
+	}
+}
+
+func TestInline_KeepCircularRefs(t *testing.T) {
+	inlined, err := Inline(recursiveTestDocument(), InlineOptions{KeepCircularRefs: true})
+	if err != nil {
+		t.Fatalf("Inline() error = %v", err)
+	}
+
+	category := inlined.Paths["/pets"].Get.Responses["200"].Content["application/json"].Schema.Items.Properties["category"]
+	if category.Ref != "" || category.Properties["name"] == nil {
+		t.Fatalf("category = %+v, want the inlined Category schema", category)
+	}
+	if got := category.Properties["children"].Items.Ref; got != "#/components/schemas/Category" {
+		t.Errorf("children items $ref = %q, want it kept", got)
+	}
+	if _, ok := inlined.Components.Schemas["Category"]; !ok {
+		t.Error("the Category schema the kept $ref points to was dropped")
+	}
+}
+
+func TestInline_UndefinedRef(t *testing.T) {
+	doc := sliceTestDocument()
+	delete(doc.Components.Schemas, "Order")
+	if _, err := Inline(doc, InlineOptions{}); err == nil || err.Error() != "#/components/schemas/Order is not defined" {
+		t.Errorf("Inline() error = %v, want the undefined $ref", err)
+	}
+}
@@ -0,0 +1,102 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Limits on loaded documents, so that loading an untrusted spec takes time and
+// memory in proportion to its size.
+const (
+	// MaxNestingDepth is the deepest nesting of objects and arrays a document may have
+	MaxNestingDepth = 512
+
+	// MaxAliasExpansion is the number of nodes YAML aliases may expand a
+	// document to, unless it is less than ten times the nodes in the source
+	MaxAliasExpansion = 1_000_000
+)
+
+// checkJSONLimits reports whether the JSON data nests deeper than
+// MaxNestingDepth. Syntax errors are left to the unmarshaler.
+func checkJSONLimits(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			if depth++; depth > MaxNestingDepth {
+				return fmt.Errorf("spec nests deeper than %d levels", MaxNestingDepth)
+			}
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+}
+
+// checkYAMLLimits reports whether the YAML node nests deeper than
+// MaxNestingDepth, has an alias inside its own anchor, or has aliases that
+// expand it beyond MaxAliasExpansion nodes.
+func checkYAMLLimits(node *yaml.Node) error {
+	m := yamlMeasure{anchored: make(map[*yaml.Node]yamlSize)}
+	size, err := m.measure(node, 1)
+	if err != nil {
+		return err
+	}
+	if size.nodes > MaxAliasExpansion && size.nodes > 10*m.literal {
+		return fmt.Errorf("YAML aliases expand the spec to more than %d nodes", MaxAliasExpansion)
+	}
+	return nil
+}
+
+// yamlSize is the expanded size of a YAML node: its nodes, with aliases
+// counted as the nodes they refer to, and the depth of its nesting.
+type yamlSize struct {
+	nodes, height int
+}
+
+// yamlMeasure measures a YAML node tree in one pass, following each alias to
+// the already measured size of its anchor.
+type yamlMeasure struct {
+	literal  int                     // nodes in the source
+	anchored map[*yaml.Node]yamlSize // sizes of the anchored nodes measured so far
+}
+
+// measure returns the size of the node at nesting depth.
+func (m *yamlMeasure) measure(node *yaml.Node, depth int) (yamlSize, error) {
+	if node.Kind == yaml.AliasNode {
+		size, ok := m.anchored[node.Alias]
+		if !ok {
+			return size, fmt.Errorf("YAML anchor %q contains an alias to itself", node.Value)
+		}
+		if depth+size.height-1 > MaxNestingDepth {
+			return size, fmt.Errorf("spec nests deeper than %d levels", MaxNestingDepth)
+		}
+		return size, nil
+	}
+	if depth > MaxNestingDepth {
+		return yamlSize{}, fmt.Errorf("spec nests deeper than %d levels", MaxNestingDepth)
+	}
+
+	m.literal++
+	size := yamlSize{nodes: 1, height: 1}
+	for _, child := range node.Content {
+		childSize, err := m.measure(child, depth+1)
+		if err != nil {
+			return size, err
+		}
+		size.nodes = min(size.nodes+childSize.nodes, math.MaxInt32)
+		size.height = max(size.height, childSize.height+1)
+	}
+	if node.Anchor != "" {
+		m.anchored[node] = size
+	}
+	return size, nil
+}
@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// laughsYAML returns a spec whose schemas alias the previous one nine times
+// over levels times.
+func laughsYAML(levels int) string {
+	var b strings.Builder
+	b.WriteString("components:\n  schemas:\n    S0: &s0 {type: string}\n")
+	for i := 1; i <= levels; i++ {
+		fmt.Fprintf(&b, "    S%d: &s%d {properties: {", i, i)
+		for j := range 9 {
+			fmt.Fprintf(&b, "p%d: *s%d, ", j, i-1)
+		}
+		b.WriteString("}}\n")
+	}
+	return b.String()
+}
+
+func TestLoadLimits_YAML(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		wantErr string
+	}{
+		"aliases":          {input: laughsYAML(2)},
+		"alias expansion":  {input: laughsYAML(8), wantErr: "expand"},
+		"recursive anchor": {input: "components:\n  schemas:\n    A: &a\n      properties:\n        self: *a\n", wantErr: "contains an alias to itself"},
+		"nesting":          {input: "x-deep: " + strings.Repeat("[", MaxNestingDepth) + strings.Repeat("]", MaxNestingDepth), wantErr: "nests deeper"},
+		"nesting by alias": {input: "x-a: &a " + strings.Repeat("[", MaxNestingDepth-2) + strings.Repeat("]", MaxNestingDepth-2) + "\nx-b: [[*a]]", wantErr: "nests deeper"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var doc Document
+			err := yaml.Unmarshal([]byte(tt.input), &doc)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Unmarshal() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Unmarshal() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadLimits_JSON(t *testing.T) {
+	nested := func(depth int) string {
+		return `{"x-deep": ` + strings.Repeat(`{"a": `, depth-1) + "1" + strings.Repeat("}", depth)
+	}
+	var doc Document
+	if err := json.Unmarshal([]byte(nested(MaxNestingDepth)), &doc); err != nil {
+		t.Errorf("Unmarshal() error = %v", err)
+	}
+	if err := json.Unmarshal([]byte(nested(MaxNestingDepth+1)), &doc); err == nil || !strings.Contains(err.Error(), "nests deeper") {
+		t.Errorf("Unmarshal() error = %v, want a nesting error", err)
+	}
+}
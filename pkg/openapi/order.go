@@ -0,0 +1,179 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Paths, responses, and schema properties are maps, which encoding/json and
+// yaml.v3 marshal in key order. Their parent objects record the declaration
+// order of their keys (PathOrder, ResponseOrder, PropertyOrder) so that
+// output keeps it: decoding a document records the source order, and the
+// parser records the order of the annotations and struct fields. Keys
+// missing from the order follow it in key order.
+
+// objectMember is a member of a JSON object.
+type objectMember struct {
+	key   string
+	value json.RawMessage
+}
+
+// objectMembers returns the members of the JSON object data in source order.
+func objectMembers(data []byte) ([]objectMember, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object, got %v", tok)
+	}
+	var members []objectMember
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		member := objectMember{key: tok.(string)}
+		if err := dec.Decode(&member.value); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// joinMembers returns the JSON object with members.
+func joinMembers(members []objectMember) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, member := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(member.key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(member.value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+// orderRank returns a sort function that puts the keys in order first, in
+// that order, and keeps the relative order of the others.
+func orderRank[T any](order []string, keyOf func(T) string) func(a, b T) int {
+	ranks := make(map[string]int, len(order))
+	for i, key := range order {
+		if _, ok := ranks[key]; !ok {
+			ranks[key] = i
+		}
+	}
+	rank := func(v T) int {
+		if i, ok := ranks[keyOf(v)]; ok {
+			return i
+		}
+		return len(order)
+	}
+	return func(a, b T) int { return rank(a) - rank(b) }
+}
+
+// orderJSONMember reorders the keys of the object member key of the JSON object data.
+func orderJSONMember(data []byte, key string, order []string) ([]byte, error) {
+	if len(order) == 0 {
+		return data, nil
+	}
+	members, err := objectMembers(data)
+	if err != nil {
+		return nil, err
+	}
+	for i, member := range members {
+		if member.key != key || !bytes.HasPrefix(member.value, []byte("{")) {
+			continue
+		}
+		inner, err := objectMembers(member.value)
+		if err != nil {
+			return nil, err
+		}
+		slices.SortStableFunc(inner, orderRank(order, func(m objectMember) string { return m.key }))
+		members[i].value = joinMembers(inner)
+	}
+	return joinMembers(members), nil
+}
+
+// jsonMemberKeys returns the keys of the object member key of the JSON object data in source order.
+func jsonMemberKeys(data []byte, key string) ([]string, error) {
+	if !bytes.Contains(data, []byte(`"`+key+`"`)) {
+		return nil, nil
+	}
+	members, err := objectMembers(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		if member.key != key || !bytes.HasPrefix(member.value, []byte("{")) {
+			continue
+		}
+		inner, err := objectMembers(member.value)
+		if err != nil {
+			return nil, err
+		}
+		keys := make([]string, len(inner))
+		for i, m := range inner {
+			keys[i] = m.key
+		}
+		return keys, nil
+	}
+	return nil, nil
+}
+
+// mappingValue returns the value of key in the YAML mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// orderYAMLMember returns v, the YAML form of a mapping, with the keys of its
+// mapping member key reordered.
+func orderYAMLMember(v any, key string, order []string) (interface{}, error) {
+	if len(order) == 0 {
+		return v, nil
+	}
+	node, ok := v.(*yaml.Node)
+	if !ok {
+		node = &yaml.Node{}
+		if err := node.Encode(v); err != nil {
+			return nil, err
+		}
+	}
+	value := mappingValue(node, key)
+	if value == nil || value.Kind != yaml.MappingNode {
+		return node, nil
+	}
+	pairs := slices.Collect(slices.Chunk(value.Content, 2))
+	slices.SortStableFunc(pairs, orderRank(order, func(pair []*yaml.Node) string { return pair[0].Value }))
+	value.Content = slices.Concat(pairs...)
+	return node, nil
+}
+
+// yamlMemberKeys returns the keys of the mapping member key of the YAML mapping node in source order.
+func yamlMemberKeys(node *yaml.Node, key string) []string {
+	value := mappingValue(node, key)
+	if value == nil || value.Kind != yaml.MappingNode {
+		return nil
+	}
+	keys := make([]string, 0, len(value.Content)/2)
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		keys = append(keys, value.Content[i].Value)
+	}
+	return keys
+}
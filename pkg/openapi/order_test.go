@@ -0,0 +1,124 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func orderedDocument() *Document {
+	return &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: "Zoo", Version: "1.0.0"},
+		Paths: Paths{
+			"/zebras": &PathItem{Get: &Operation{
+				Responses: Responses{
+					"200": {Description: "OK"},
+					"404": {Description: "Not found"},
+					"400": {Description: "Bad request"},
+				},
+				ResponseOrder: []string{"200", "404"},
+			}},
+			"/ants":  &PathItem{},
+			"/birds": &PathItem{},
+		},
+		PathOrder: []string{"/zebras", "/birds"},
+		Components: &Components{Schemas: map[string]*Schema{
+			"Zebra": {
+				Type: NewSchemaType(TypeObject),
+				Properties: map[string]*Schema{
+					"stripes": IntegerSchema(),
+					"name":    StringSchema(),
+					"age":     IntegerSchema(),
+				},
+				PropertyOrder: []string{"stripes", "name", "age"},
+			},
+		}},
+	}
+}
+
+// assertInOrder fails unless each of want appears in s after the previous one.
+func assertInOrder(t *testing.T, s string, want ...string) {
+	t.Helper()
+	rest := s
+	for _, w := range want {
+		i := strings.Index(rest, w)
+		if i < 0 {
+			t.Fatalf("%s missing or out of order in:\n%s", w, s)
+		}
+		rest = rest[i+len(w):]
+	}
+}
+
+func TestOrder_JSON(t *testing.T) {
+	data, err := json.Marshal(orderedDocument())
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	assertInOrder(t, string(data), `"/zebras"`, `"200"`, `"404"`, `"400"`, `"/birds"`, `"/ants"`)
+	assertInOrder(t, string(data), `"stripes"`, `"name"`, `"age"`)
+
+	var decoded Document
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	assertOrder(t, &decoded)
+}
+
+func TestOrder_YAML(t *testing.T) {
+	data, err := yaml.Marshal(orderedDocument())
+	if err != nil {
+		t.Fatalf("yaml.Marshal() error = %v", err)
+	}
+	assertInOrder(t, string(data), "/zebras:", `"200":`, `"404":`, `"400":`, "/birds:", "/ants:")
+	assertInOrder(t, string(data), "stripes:", "name:", "age:")
+
+	var decoded Document
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+	assertOrder(t, &decoded)
+}
+
+func TestOrder_Unordered(t *testing.T) {
+	doc := orderedDocument()
+	doc.PathOrder = nil
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	assertInOrder(t, string(data), `"/ants"`, `"/birds"`, `"/zebras"`)
+}
+
+// assertOrder checks the order decoded from the output of orderedDocument.
+func assertOrder(t *testing.T, doc *Document) {
+	t.Helper()
+	if want := []string{"/zebras", "/birds", "/ants"}; !reflect.DeepEqual(doc.PathOrder, want) {
+		t.Errorf("PathOrder = %v, want %v", doc.PathOrder, want)
+	}
+	if want := []string{"200", "404", "400"}; !reflect.DeepEqual(doc.Paths["/zebras"].Get.ResponseOrder, want) {
+		t.Errorf("ResponseOrder = %v, want %v", doc.Paths["/zebras"].Get.ResponseOrder, want)
+	}
+	if want := []string{"stripes", "name", "age"}; !reflect.DeepEqual(doc.Components.Schemas["Zebra"].PropertyOrder, want) {
+		t.Errorf("PropertyOrder = %v, want %v", doc.Components.Schemas["Zebra"].PropertyOrder, want)
+	}
+}
+
+func BenchmarkOrder_JSON(b *testing.B) {
+	doc := &Document{OpenAPI: "3.0.3", Info: Info{Title: "Large", Version: "1.0.0"}, Paths: Paths{}}
+	for i := range 5000 {
+		path := fmt.Sprintf("/resources%d", i)
+		doc.Paths[path] = &PathItem{}
+		doc.PathOrder = append(doc.PathOrder, path)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := json.Marshal(doc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package openapi
+
+// Owner identifies the team responsible for a tag or an operation. It is
+// emitted as the x-owner specification extension.
+type Owner struct {
+	Team  string `json:"team" yaml:"team"`
+	Slack string `json:"slack,omitempty" yaml:"slack,omitempty"`
+	Email string `json:"email,omitempty" yaml:"email,omitempty"`
+}
+
+// OwnerOf returns the owner of op: its own x-owner, else the x-owner of the
+// first of its tags that declares one, else nil.
+func (d *Document) OwnerOf(op *Operation) *Owner {
+	if op == nil {
+		return nil
+	}
+	if op.Owner != nil {
+		return op.Owner
+	}
+	for _, name := range op.Tags {
+		for _, tag := range d.Tags {
+			if tag.Name == name && tag.Owner != nil {
+				return tag.Owner
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package openapi
+
+import "slices"
+
+// Prune returns a copy of doc without the components that its paths,
+// webhooks, and security requirements do not use, directly or through other
+// components, such as the schemas of removed handlers that generated specs
+// accumulate. It also returns the $refs of the removed components, sorted.
+// The copy shares the objects it keeps with doc.
+func Prune(doc *Document) (*Document, []string, error) {
+	pruned := *doc
+	if doc.Components == nil {
+		return &pruned, []string{}, nil
+	}
+	used, err := doc.usedComponents()
+	if err != nil {
+		return nil, nil, err
+	}
+	pruned.Components = used
+
+	c := doc.Components
+	removed := slices.Concat(
+		unusedComponents("schemas", c.Schemas, used.Schemas),
+		unusedComponents("responses", c.Responses, used.Responses),
+		unusedComponents("parameters", c.Parameters, used.Parameters),
+		unusedComponents("examples", c.Examples, used.Examples),
+		unusedComponents("requestBodies", c.RequestBodies, used.RequestBodies),
+		unusedComponents("headers", c.Headers, used.Headers),
+		unusedComponents("securitySchemes", c.SecuritySchemes, used.SecuritySchemes),
+		unusedComponents("links", c.Links, used.Links),
+		unusedComponents("callbacks", c.Callbacks, used.Callbacks),
+		unusedComponents("pathItems", c.PathItems, used.PathItems),
+	)
+	slices.Sort(removed)
+	return &pruned, append([]string{}, removed...), nil
+}
+
+// unusedComponents returns the $refs of the components of a kind in all but
+// not in used.
+func unusedComponents[V any](kind string, all, used map[string]*V) []string {
+	var refs []string
+	for name := range all {
+		if _, ok := used[name]; !ok {
+			refs = append(refs, "#/components/"+kind+"/"+name)
+		}
+	}
+	return refs
+}
@@ -0,0 +1,44 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrune(t *testing.T) {
+	doc := filterTestDocument()
+	doc.Components.Schemas["Legacy"] = &Schema{Type: NewSchemaType(TypeObject), Properties: map[string]*Schema{"owner": RefTo("LegacyOwner")}}
+	doc.Components.Schemas["LegacyOwner"] = ObjectSchema()
+	doc.Components.Responses["Gone"] = &Response{Description: "Gone"}
+	doc.Components.SecuritySchemes["basic"] = &SecurityScheme{Type: "http", Scheme: "basic"}
+
+	pruned, removed, err := Prune(doc)
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	want := []string{
+		"#/components/responses/Gone",
+		"#/components/schemas/Legacy",
+		"#/components/schemas/LegacyOwner",
+		"#/components/securitySchemes/basic",
+	}
+	if !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %v, want %v", removed, want)
+	}
+
+	c := pruned.Components
+	assertKeys(t, "schemas", c.Schemas, "Category", "Error", "Order", "Pet")
+	assertKeys(t, "responses", c.Responses, "NotFound")
+	assertKeys(t, "parameters", c.Parameters, "PetID")
+	assertKeys(t, "security schemes", c.SecuritySchemes, "admin_key", "petstore_auth")
+	if len(pruned.Paths) != len(doc.Paths) {
+		t.Errorf("paths = %d, want %d", len(pruned.Paths), len(doc.Paths))
+	}
+	if len(doc.Components.Schemas) != 6 || len(doc.Components.Responses) != 2 {
+		t.Error("Prune modified the document")
+	}
+
+	if _, removed, err := Prune(pruned); err != nil || len(removed) != 0 {
+		t.Errorf("Prune() of a pruned document removed %v, error = %v", removed, err)
+	}
+}
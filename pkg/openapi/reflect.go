@@ -0,0 +1,171 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// SchemaOf returns the schema of values of type T; see SchemaFromType.
+func SchemaOf[T any]() *Schema {
+	return SchemaFromType(reflect.TypeFor[T]())
+}
+
+// SchemaFromType returns the schema of values of type t as encoding/json
+// marshals them, for building documents in code rather than from annotations.
+// Struct fields are named by their json tags, and those without omitempty or
+// omitzero are required. Fields of embedded structs are promoted, pointers are
+// nullable, []byte is a base64 string, time.Time is a date-time string, and
+// other types implementing encoding.TextMarshaler are strings. A struct type
+// nested in itself is referenced by its name, as #/components/schemas/Name,
+// so the caller adds that schema to the document's components.
+func SchemaFromType(t reflect.Type) *Schema {
+	b := schemaBuilder{building: make(map[reflect.Type]bool)}
+	return b.schema(t)
+}
+
+// schemaBuilder builds the schemas of Go types.
+type schemaBuilder struct {
+	building map[reflect.Type]bool // struct types being built, to cut off recursion
+}
+
+func (b *schemaBuilder) schema(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Pointer {
+		schema := b.schema(t.Elem())
+		schema.Nullable = schema.Ref == ""
+		return schema
+	}
+	switch {
+	case t == timeType:
+		return &Schema{Type: NewSchemaType(TypeString), Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return StringSchema()
+	}
+	if info, ok := kindSchemas[t.Kind()]; ok {
+		return &Schema{Type: NewSchemaType(info[0]), Format: info[1]}
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return b.arraySchema(t)
+	case reflect.Map:
+		return &Schema{Type: NewSchemaType(TypeObject), AdditionalProperties: b.schema(t.Elem())}
+	case reflect.Struct:
+		return b.structSchema(t)
+	}
+	return &Schema{}
+}
+
+// kindSchemas maps basic kinds to their schema type and format, matching the
+// parser's mapping of Go type names.
+var kindSchemas = map[reflect.Kind][2]string{
+	reflect.String:  {TypeString, ""},
+	reflect.Bool:    {TypeBoolean, ""},
+	reflect.Int:     {TypeInteger, "int32"},
+	reflect.Int8:    {TypeInteger, "int32"},
+	reflect.Int16:   {TypeInteger, "int32"},
+	reflect.Int32:   {TypeInteger, "int32"},
+	reflect.Int64:   {TypeInteger, "int64"},
+	reflect.Uint:    {TypeInteger, "int32"},
+	reflect.Uint8:   {TypeInteger, "int32"},
+	reflect.Uint16:  {TypeInteger, "int32"},
+	reflect.Uint32:  {TypeInteger, "int32"},
+	reflect.Uint64:  {TypeInteger, "int64"},
+	reflect.Float32: {TypeNumber, "float"},
+	reflect.Float64: {TypeNumber, "double"},
+}
+
+func (b *schemaBuilder) arraySchema(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		return &Schema{Type: NewSchemaType(TypeString), Format: "byte"}
+	}
+	schema := ArraySchema(b.schema(t.Elem()))
+	if t.Kind() == reflect.Array {
+		n := int64(t.Len())
+		schema.MinItems, schema.MaxItems = &n, &n
+	}
+	return schema
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) *Schema {
+	if b.building[t] {
+		return RefTo(t.Name())
+	}
+	b.building[t] = true
+	defer delete(b.building, t)
+
+	schema := ObjectSchema()
+	b.addFields(schema, t)
+	return schema
+}
+
+// addFields adds the properties of the fields of struct type t to schema,
+// then those of its embedded structs that are not already there, as
+// encoding/json promotes them.
+func (b *schemaBuilder) addFields(schema *Schema, t reflect.Type) {
+	var embedded []reflect.Type
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && indirect(field.Type).Kind() == reflect.Struct {
+			embedded = append(embedded, indirect(field.Type))
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		b.addProperty(schema, name, field.Type, strings.Split(opts, ","))
+	}
+
+	for _, e := range embedded {
+		if !b.building[e] {
+			b.building[e] = true
+			b.addFields(schema, e)
+			delete(b.building, e)
+		}
+	}
+}
+
+// addProperty adds the property name of type t with the json tag options
+// opts to schema, unless it already has one by that name.
+func (b *schemaBuilder) addProperty(schema *Schema, name string, t reflect.Type, opts []string) {
+	if _, ok := schema.Properties[name]; ok {
+		return
+	}
+	property := b.schema(t)
+	if slices.Contains(opts, "string") && property.Ref == "" {
+		if _, basic := kindSchemas[indirect(t).Kind()]; basic {
+			property.Type, property.Format = NewSchemaType(TypeString), ""
+		}
+	}
+	schema.Properties[name] = property
+	schema.PropertyOrder = append(schema.PropertyOrder, name)
+	if !slices.Contains(opts, "omitempty") && !slices.Contains(opts, "omitzero") {
+		schema.Required = append(schema.Required, name)
+	}
+}
+
+// indirect returns the type t points to, or t if it is not a pointer.
+func indirect(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
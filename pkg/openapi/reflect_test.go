@@ -0,0 +1,80 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+type reflectBase struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type reflectPet struct {
+	reflectBase
+	Name     string            `json:"name"`
+	Tag      *string           `json:"tag,omitempty"`
+	Age      int               `json:"age,string"`
+	Photo    []byte            `json:"photo,omitempty"`
+	Labels   map[string]string `json:"labels,omitzero"`
+	Owner    *reflectOwner     `json:"owner"`
+	IP       net.IP            `json:"ip"`
+	Metadata json.RawMessage   `json:"metadata"`
+	Color    [3]uint8          `json:"color"`
+	Secret   string            `json:"-"`
+	Dash     string            `json:"-,"`
+	NoTag    bool
+	private  string
+}
+
+type reflectOwner struct {
+	Name string          `json:"name"`
+	Pets []*reflectOwner `json:"friends"`
+}
+
+func TestSchemaOf(t *testing.T) {
+	schema := SchemaOf[reflectPet]()
+
+	wantOrder := []string{"name", "tag", "age", "photo", "labels", "owner", "ip", "metadata", "color", "-", "NoTag", "id", "createdAt"}
+	if !slices.Equal(schema.PropertyOrder, wantOrder) {
+		t.Errorf("PropertyOrder = %v, want %v", schema.PropertyOrder, wantOrder)
+	}
+	wantRequired := []string{"name", "age", "owner", "ip", "metadata", "color", "-", "NoTag", "id", "createdAt"}
+	if !slices.Equal(schema.Required, wantRequired) {
+		t.Errorf("Required = %v, want %v", schema.Required, wantRequired)
+	}
+
+	props := schema.Properties
+	tests := map[string]*Schema{
+		"id":        {Type: NewSchemaType(TypeInteger), Format: "int64"},
+		"createdAt": {Type: NewSchemaType(TypeString), Format: "date-time"},
+		"tag":       {Type: NewSchemaType(TypeString), Nullable: true},
+		"age":       {Type: NewSchemaType(TypeString)},
+		"photo":     {Type: NewSchemaType(TypeString), Format: "byte"},
+		"labels":    {Type: NewSchemaType(TypeObject), AdditionalProperties: StringSchema()},
+		"ip":        StringSchema(),
+		"metadata":  {},
+	}
+	for name, want := range tests {
+		if !reflect.DeepEqual(props[name], want) {
+			t.Errorf("%s = %+v, want %+v", name, props[name], want)
+		}
+	}
+	if color := props["color"]; *color.MinItems != 3 || *color.MaxItems != 3 || color.Items.Type[0] != TypeInteger {
+		t.Errorf("color = %+v, want an array of three integers", color)
+	}
+}
+
+func TestSchemaOf_Recursive(t *testing.T) {
+	owner := SchemaOf[*reflectOwner]()
+	if !owner.Nullable || owner.Properties["name"] == nil {
+		t.Fatalf("owner = %+v, want a nullable object", owner)
+	}
+	if friends := owner.Properties["friends"]; friends.Items.Ref != "#/components/schemas/reflectOwner" {
+		t.Errorf("friends items = %+v, want a reference to reflectOwner", friends.Items)
+	}
+}
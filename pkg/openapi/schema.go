@@ -1,6 +1,10 @@
 package openapi
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
 
 // Schema represents a JSON Schema object that describes the structure of data.
 // https://spec.openapis.org/oas/v3.1.0#schema-object
@@ -25,11 +29,11 @@ type Schema struct {
 	Pattern   string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
 
 	// Number validation
-	Minimum          *float64 `json:"minimum,omitempty" yaml:"minimum,omitempty"`
-	Maximum          *float64 `json:"maximum,omitempty" yaml:"maximum,omitempty"`
-	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
-	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
-	MultipleOf       *float64 `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
+	Minimum          *float64        `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum          *float64        `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	ExclusiveMinimum *ExclusiveBound `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *ExclusiveBound `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64        `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
 
 	// Array validation
 	Items       *Schema `json:"items,omitempty" yaml:"items,omitempty"`
@@ -58,6 +62,12 @@ type Schema struct {
 
 	// XML
 	XML *XML `json:"xml,omitempty" yaml:"xml,omitempty"`
+
+	// Specification extensions
+	Extensions Extensions `json:"-" yaml:"-"`
+
+	// PropertyOrder lists the keys of Properties in declaration order, which output keeps.
+	PropertyOrder []string `json:"-" yaml:"-"`
 }
 
 // SchemaType represents the type field which can be a single type or array of types.
@@ -132,21 +142,128 @@ func (s SchemaType) MarshalYAML() (interface{}, error) {
 	return []string(s), nil
 }
 
+// UnmarshalYAML implements yaml.Unmarshaler.
+// Handles both scalar (OpenAPI 3.0) and sequence (OpenAPI 3.1+) formats.
+func (s *SchemaType) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*s = SchemaType{value.Value}
+		return nil
+	}
+
+	var arr []string
+	if err := value.Decode(&arr); err != nil {
+		return err
+	}
+	*s = arr
+	return nil
+}
+
+// ExclusiveBound represents exclusiveMinimum or exclusiveMaximum.
+// OpenAPI 3.0 uses a boolean that makes minimum/maximum exclusive,
+// while OpenAPI 3.1+ uses the exclusive bound itself as a number.
+type ExclusiveBound struct {
+	// Value is the numeric bound (OpenAPI 3.1+)
+	Value *float64
+
+	// Exclusive is the boolean modifier (OpenAPI 3.0), used when Value is nil
+	Exclusive bool
+}
+
+// ExclusiveValue creates an OpenAPI 3.1 style numeric exclusive bound.
+func ExclusiveValue(v float64) *ExclusiveBound {
+	return &ExclusiveBound{Value: &v}
+}
+
+// ExclusiveFlag creates an OpenAPI 3.0 style boolean exclusive bound.
+func ExclusiveFlag(exclusive bool) *ExclusiveBound {
+	return &ExclusiveBound{Exclusive: exclusive}
+}
+
+// LowerBound returns the effective minimum of the schema and whether it is
+// exclusive, for both the OpenAPI 3.0 (boolean) and 3.1 (numeric) forms of
+// exclusiveMinimum. The minimum is nil if the schema has none.
+func (s *Schema) LowerBound() (*float64, bool) {
+	if b := s.ExclusiveMinimum; b != nil && b.Value != nil {
+		return b.Value, true
+	}
+	return s.Minimum, s.Minimum != nil && s.ExclusiveMinimum != nil && s.ExclusiveMinimum.Exclusive
+}
+
+// UpperBound is the exclusiveMaximum counterpart of LowerBound.
+func (s *Schema) UpperBound() (*float64, bool) {
+	if b := s.ExclusiveMaximum; b != nil && b.Value != nil {
+		return b.Value, true
+	}
+	return s.Maximum, s.Maximum != nil && s.ExclusiveMaximum != nil && s.ExclusiveMaximum.Exclusive
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b ExclusiveBound) MarshalJSON() ([]byte, error) {
+	if b.Value != nil {
+		return json.Marshal(*b.Value)
+	}
+	return json.Marshal(b.Exclusive)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Handles both boolean (OpenAPI 3.0) and number (OpenAPI 3.1+) formats.
+func (b *ExclusiveBound) UnmarshalJSON(data []byte) error {
+	var flag bool
+	if err := json.Unmarshal(data, &flag); err == nil {
+		*b = ExclusiveBound{Exclusive: flag}
+		return nil
+	}
+
+	var v float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*b = ExclusiveBound{Value: &v}
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (b ExclusiveBound) MarshalYAML() (interface{}, error) {
+	if b.Value != nil {
+		return *b.Value, nil
+	}
+	return b.Exclusive, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+// Handles both boolean (OpenAPI 3.0) and number (OpenAPI 3.1+) formats.
+func (b *ExclusiveBound) UnmarshalYAML(value *yaml.Node) error {
+	var flag bool
+	if err := value.Decode(&flag); err == nil {
+		*b = ExclusiveBound{Exclusive: flag}
+		return nil
+	}
+
+	var v float64
+	if err := value.Decode(&v); err != nil {
+		return err
+	}
+	*b = ExclusiveBound{Value: &v}
+	return nil
+}
+
 // Discriminator is used when request bodies or response payloads may be one of a number of different schemas.
 // https://spec.openapis.org/oas/v3.1.0#discriminator-object
 type Discriminator struct {
 	PropertyName string            `json:"propertyName" yaml:"propertyName"`
 	Mapping      map[string]string `json:"mapping,omitempty" yaml:"mapping,omitempty"`
+	Extensions   Extensions        `json:"-" yaml:"-"`
 }
 
 // XML provides additional information to describe XML representation of this property.
 // https://spec.openapis.org/oas/v3.1.0#xml-object
 type XML struct {
-	Name      string `json:"name,omitempty" yaml:"name,omitempty"`
-	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
-	Prefix    string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
-	Attribute bool   `json:"attribute,omitempty" yaml:"attribute,omitempty"`
-	Wrapped   bool   `json:"wrapped,omitempty" yaml:"wrapped,omitempty"`
+	Name       string     `json:"name,omitempty" yaml:"name,omitempty"`
+	Namespace  string     `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Prefix     string     `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	Attribute  bool       `json:"attribute,omitempty" yaml:"attribute,omitempty"`
+	Wrapped    bool       `json:"wrapped,omitempty" yaml:"wrapped,omitempty"`
+	Extensions Extensions `json:"-" yaml:"-"`
 }
 
 // RefTo creates a reference schema to a component schema.
@@ -364,3 +364,29 @@ func TestSchema_Discriminator(t *testing.T) {
 		t.Errorf("Discriminator.Mapping length = %d, want 2", len(schema.Discriminator.Mapping))
 	}
 }
+
+func TestSchema_Bounds(t *testing.T) {
+	five, ten := 5.0, 10.0
+	tests := []struct {
+		name          string
+		schema        *Schema
+		want          *float64
+		wantExclusive bool
+	}{
+		{"none", &Schema{}, nil, false},
+		{"inclusive", &Schema{Minimum: &five, Maximum: &five}, &five, false},
+		{"3.0 exclusive", &Schema{Minimum: &five, ExclusiveMinimum: ExclusiveFlag(true), Maximum: &five, ExclusiveMaximum: ExclusiveFlag(true)}, &five, true},
+		{"3.0 flag without bound", &Schema{ExclusiveMinimum: ExclusiveFlag(true), ExclusiveMaximum: ExclusiveFlag(true)}, nil, false},
+		{"3.1 exclusive", &Schema{Minimum: &ten, ExclusiveMinimum: ExclusiveValue(5), Maximum: &ten, ExclusiveMaximum: ExclusiveValue(5)}, &five, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, bound := range map[string]func() (*float64, bool){"LowerBound": tt.schema.LowerBound, "UpperBound": tt.schema.UpperBound} {
+				got, exclusive := bound()
+				if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want || exclusive != tt.wantExclusive {
+					t.Errorf("%s() = %v, %v, want %v, %v", name, got, exclusive, tt.want, tt.wantExclusive)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+)
+
+// DefaultTag groups the operations without tags, as documentation UIs do.
+const DefaultTag = "default"
+
+// OperationTags returns the tags of the document's operations: the declared
+// tags first, in declaration order, then the undeclared ones in name order,
+// then DefaultTag if some operation has no tags. Declared tags without
+// operations are omitted.
+func (d *Document) OperationTags() []string {
+	used := d.usedTags()
+	var tags []string
+	for _, tag := range d.Tags {
+		if used[tag.Name] && !slices.Contains(tags, tag.Name) {
+			tags = append(tags, tag.Name)
+		}
+	}
+	var undeclared []string
+	for tag := range used {
+		if tag != DefaultTag && !slices.Contains(tags, tag) {
+			undeclared = append(undeclared, tag)
+		}
+	}
+	slices.Sort(undeclared)
+	tags = append(tags, undeclared...)
+	if used[DefaultTag] && !slices.Contains(tags, DefaultTag) {
+		tags = append(tags, DefaultTag)
+	}
+	return tags
+}
+
+// usedTags returns the set of tags of the document's operations.
+func (d *Document) usedTags() map[string]bool {
+	used := make(map[string]bool)
+	for _, items := range []map[string]*PathItem{d.Paths, d.Webhooks} {
+		for _, item := range items {
+			if item == nil {
+				continue
+			}
+			for _, op := range pathItemOperations(item) {
+				for _, tag := range operationTags(op) {
+					used[tag] = true
+				}
+			}
+		}
+	}
+	return used
+}
+
+// TagSlice returns a copy of the document with only the operations tagged
+// tag, or the untagged ones for DefaultTag, the declaration of the tag, and
+// the component schemas those operations use. Path items without such
+// operations are dropped. The copy shares the objects it keeps with d.
+func (d *Document) TagSlice(tag string) *Document {
+	tagged := func(_ string, op *Operation) bool { return slices.Contains(operationTags(op), tag) }
+	slice := *d
+	slice.Paths = filterOperations(d.Paths, tagged)
+	slice.Webhooks = filterOperations(d.Webhooks, tagged)
+	slice.Tags = nil
+	for _, t := range d.Tags {
+		if t.Name == tag {
+			slice.Tags = append(slice.Tags, t)
+		}
+	}
+
+	if d.Components != nil {
+		components := *d.Components
+		used := make(map[string]bool)
+		for _, items := range []map[string]*PathItem{slice.Paths, slice.Webhooks} {
+			for _, item := range items {
+				for _, op := range pathItemOperations(item) {
+					maps.Copy(used, d.reachableSchemas(op))
+				}
+			}
+		}
+		components.Schemas = nil
+		for name, schema := range d.Components.Schemas {
+			if used[name] {
+				if components.Schemas == nil {
+					components.Schemas = make(map[string]*Schema)
+				}
+				components.Schemas[name] = schema
+			}
+		}
+		slice.Components = &components
+	}
+	return &slice
+}
+
+// operationTags returns the tags of op, or DefaultTag if it has none.
+func operationTags(op *Operation) []string {
+	if len(op.Tags) == 0 {
+		return []string{DefaultTag}
+	}
+	return op.Tags
+}
@@ -0,0 +1,90 @@
+package openapi
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func sliceTestDocument() *Document {
+	return &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: "Store", Version: "1.0.0"},
+		Tags:    []Tag{{Name: "store"}, {Name: "pet"}, {Name: "unused"}},
+		Paths: Paths{
+			"/pets": &PathItem{
+				Get: &Operation{OperationID: "listPets", Tags: []string{"pet"},
+					Responses: Responses{"200": {Description: "OK", Content: map[string]MediaType{
+						"application/json": {Schema: ArraySchema(RefTo("Pet"))},
+					}}}},
+				Post: &Operation{OperationID: "addPet", Tags: []string{"pet", "admin"}},
+			},
+			"/orders": &PathItem{
+				Get: &Operation{OperationID: "listOrders", Tags: []string{"store"},
+					Responses: Responses{"200": {Description: "OK", Content: map[string]MediaType{
+						"application/json": {Schema: RefTo("Order")},
+					}}}},
+			},
+			"/health": &PathItem{Get: &Operation{OperationID: "health"}},
+		},
+		Components: &Components{
+			Schemas: map[string]*Schema{
+				"Pet":      {Type: NewSchemaType(TypeObject), Properties: map[string]*Schema{"category": RefTo("Category")}},
+				"Category": StringSchema(),
+				"Order":    ObjectSchema(),
+			},
+		},
+	}
+}
+
+func TestDocument_OperationTags(t *testing.T) {
+	got := sliceTestDocument().OperationTags()
+	want := []string{"store", "pet", "admin", DefaultTag}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("OperationTags() = %v, want %v", got, want)
+	}
+}
+
+func TestDocument_TagSlice(t *testing.T) {
+	doc := sliceTestDocument()
+	slice := doc.TagSlice("pet")
+
+	if got := slices.Sorted(maps.Keys(slice.Paths)); !reflect.DeepEqual(got, []string{"/pets"}) {
+		t.Fatalf("paths = %v, want [/pets]", got)
+	}
+	if item := slice.Paths["/pets"]; item.Get == nil || item.Post == nil {
+		t.Errorf("/pets operations = %+v, want GET and POST", item)
+	}
+	if len(slice.Tags) != 1 || slice.Tags[0].Name != "pet" {
+		t.Errorf("tags = %+v, want the pet declaration", slice.Tags)
+	}
+	if got := slices.Sorted(maps.Keys(slice.Components.Schemas)); !reflect.DeepEqual(got, []string{"Category", "Pet"}) {
+		t.Errorf("schemas = %v, want [Category Pet]", got)
+	}
+
+	if len(doc.Paths) != 3 || len(doc.Components.Schemas) != 3 || len(doc.Tags) != 3 {
+		t.Error("TagSlice modified the document")
+	}
+}
+
+func TestDocument_TagSlice_Operations(t *testing.T) {
+	doc := sliceTestDocument()
+
+	admin := doc.TagSlice("admin")
+	if item := admin.Paths["/pets"]; item == nil || item.Get != nil || item.Post == nil {
+		t.Errorf("admin /pets = %+v, want POST only", item)
+	}
+
+	untagged := doc.TagSlice(DefaultTag)
+	if got := slices.Sorted(maps.Keys(untagged.Paths)); !reflect.DeepEqual(got, []string{"/health"}) {
+		t.Errorf("untagged paths = %v, want [/health]", got)
+	}
+	if untagged.Components.Schemas != nil {
+		t.Errorf("untagged schemas = %v, want none", untagged.Components.Schemas)
+	}
+
+	if missing := doc.TagSlice("missing"); missing.Paths != nil {
+		t.Errorf("paths of unknown tag = %v, want none", missing.Paths)
+	}
+}
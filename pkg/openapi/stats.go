@@ -0,0 +1,164 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+)
+
+// Stats summarizes the size of a document and how well it is documented,
+// for tracking the growth of an API over time.
+type Stats struct {
+	Paths      int `json:"paths" yaml:"paths"`
+	Operations int `json:"operations" yaml:"operations"`
+	Webhooks   int `json:"webhooks" yaml:"webhooks"`
+	Schemas    int `json:"schemas" yaml:"schemas"` // component schemas
+
+	// Parameters counts the parameters declared by path items, operations,
+	// and components; references to component parameters are not counted
+	Parameters int `json:"parameters" yaml:"parameters"`
+
+	// Methods counts the operations of each HTTP method, such as GET
+	Methods map[string]int `json:"methods" yaml:"methods"`
+
+	// Responses counts the operations declaring each response status code,
+	// or default
+	Responses map[string]int `json:"responses" yaml:"responses"`
+
+	// Tags counts the operations of each tag; Untagged those without one
+	Tags     map[string]int `json:"tags" yaml:"tags"`
+	Untagged int            `json:"untagged" yaml:"untagged"`
+
+	Descriptions DescriptionStats `json:"descriptions" yaml:"descriptions"`
+
+	// Orphaned lists the $refs of the unused components, which Prune
+	// removes, sorted
+	Orphaned []string `json:"orphaned" yaml:"orphaned"`
+}
+
+// DescriptionStats counts the described operations, parameters, component
+// schemas, and properties of component schemas.
+type DescriptionStats struct {
+	Operations Coverage `json:"operations" yaml:"operations"` // with a summary or description
+	Parameters Coverage `json:"parameters" yaml:"parameters"`
+	Schemas    Coverage `json:"schemas" yaml:"schemas"`
+	Properties Coverage `json:"properties" yaml:"properties"` // a $ref counts as described
+}
+
+// Coverage is how many of a kind of item are described.
+type Coverage struct {
+	Described int `json:"described" yaml:"described"`
+	Total     int `json:"total" yaml:"total"`
+}
+
+// Percent returns the described percentage of the items, 100 if there are
+// none.
+func (c Coverage) Percent() int {
+	if c.Total == 0 {
+		return 100
+	}
+	return c.Described * 100 / c.Total
+}
+
+func (c *Coverage) add(described bool) {
+	c.Total++
+	if described {
+		c.Described++
+	}
+}
+
+// Average returns the average of the percentages of each kind of item,
+// leaving out the kinds the document has none of.
+func (s DescriptionStats) Average() int {
+	sum, kinds := 0, 0
+	for _, c := range []Coverage{s.Operations, s.Parameters, s.Schemas, s.Properties} {
+		if c.Total > 0 {
+			sum += c.Percent()
+			kinds++
+		}
+	}
+	if kinds == 0 {
+		return 100
+	}
+	return sum / kinds
+}
+
+// Stats returns the statistics of the document.
+func (d *Document) Stats() Stats {
+	stats := Stats{
+		Paths:     len(d.Paths),
+		Webhooks:  len(d.Webhooks),
+		Methods:   make(map[string]int),
+		Responses: make(map[string]int),
+		Tags:      make(map[string]int),
+		Orphaned:  []string{},
+	}
+	for _, item := range d.Paths {
+		if item == nil {
+			continue
+		}
+		stats.addParameters(item.Parameters)
+		for _, entry := range []struct {
+			method string
+			op     *Operation
+		}{
+			{"GET", item.Get}, {"PUT", item.Put}, {"POST", item.Post}, {"DELETE", item.Delete},
+			{"OPTIONS", item.Options}, {"HEAD", item.Head}, {"PATCH", item.Patch}, {"TRACE", item.Trace},
+		} {
+			if entry.op != nil {
+				stats.addOperation(entry.method, entry.op)
+			}
+		}
+	}
+	if d.Components != nil {
+		stats.addComponents(d.Components)
+		if _, orphaned, err := Prune(d); err == nil {
+			stats.Orphaned = orphaned
+		}
+	}
+	return stats
+}
+
+// addParameters counts the parameters of params that are not references.
+func (s *Stats) addParameters(params []*Parameter) {
+	for _, p := range params {
+		if p != nil && p.Ref == "" {
+			s.Parameters++
+			s.Descriptions.Parameters.add(p.Description != "")
+		}
+	}
+}
+
+// addOperation counts op, the operation for method, with its responses,
+// tags, and parameters.
+func (s *Stats) addOperation(method string, op *Operation) {
+	s.Operations++
+	s.Methods[method]++
+	for status := range op.Responses {
+		s.Responses[status]++
+	}
+	for _, tag := range op.Tags {
+		s.Tags[tag]++
+	}
+	if len(op.Tags) == 0 {
+		s.Untagged++
+	}
+	s.addParameters(op.Parameters)
+	s.Descriptions.Operations.add(op.Summary != "" || op.Description != "")
+}
+
+// addComponents counts the component schemas and parameters of c.
+func (s *Stats) addComponents(c *Components) {
+	s.Schemas = len(c.Schemas)
+	s.addParameters(slices.Collect(maps.Values(c.Parameters)))
+	for _, schema := range c.Schemas {
+		if schema == nil || schema.Ref != "" {
+			continue
+		}
+		s.Descriptions.Schemas.add(schema.Description != "")
+		for _, prop := range schema.Properties {
+			if prop != nil {
+				s.Descriptions.Properties.add(prop.Description != "" || prop.Ref != "")
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDocument_Stats(t *testing.T) {
+	doc := filterTestDocument()
+	doc.Paths["/pets"].Get.Summary = "List pets"
+	doc.Components.Schemas["Pet"].Description = "A pet"
+	doc.Components.Schemas["Legacy"] = ObjectSchema()
+
+	stats := doc.Stats()
+	checkStatsCounts(t, stats)
+
+	want := DescriptionStats{
+		Operations: Coverage{Described: 1, Total: 5},
+		Parameters: Coverage{Described: 0, Total: 1},
+		Schemas:    Coverage{Described: 1, Total: 5},
+		Properties: Coverage{Described: 1, Total: 1},
+	}
+	if stats.Descriptions != want {
+		t.Errorf("descriptions = %+v, want %+v", stats.Descriptions, want)
+	}
+	if got := stats.Descriptions.Average(); got != 35 {
+		t.Errorf("Average() = %d, want 35", got)
+	}
+
+	if want := []string{"#/components/schemas/Legacy"}; !reflect.DeepEqual(stats.Orphaned, want) {
+		t.Errorf("orphaned = %v, want %v", stats.Orphaned, want)
+	}
+}
+
+// checkStatsCounts checks the counts of the stats of filterTestDocument.
+func checkStatsCounts(t *testing.T, stats Stats) {
+	t.Helper()
+	if stats.Paths != 4 || stats.Operations != 5 || stats.Schemas != 5 || stats.Parameters != 1 || stats.Untagged != 1 {
+		t.Errorf("counts = %+v", stats)
+	}
+	if want := map[string]int{"GET": 3, "POST": 1, "DELETE": 1}; !reflect.DeepEqual(stats.Methods, want) {
+		t.Errorf("methods = %v, want %v", stats.Methods, want)
+	}
+	if want := map[string]int{"200": 2, "404": 1}; !reflect.DeepEqual(stats.Responses, want) {
+		t.Errorf("responses = %v, want %v", stats.Responses, want)
+	}
+	if want := map[string]int{"pet": 3, "admin": 1, "store": 1}; !reflect.DeepEqual(stats.Tags, want) {
+		t.Errorf("tags = %v, want %v", stats.Tags, want)
+	}
+}
+
+func TestDocument_StatsEmpty(t *testing.T) {
+	stats := (&Document{}).Stats()
+	if stats.Operations != 0 || stats.Orphaned == nil || stats.Descriptions.Average() != 100 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
@@ -13,34 +13,41 @@ type Document struct {
 	Security     []SecurityRequirement  `json:"security,omitempty" yaml:"security,omitempty"`
 	Tags         []Tag                  `json:"tags,omitempty" yaml:"tags,omitempty"`
 	ExternalDocs *ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+	Extensions   Extensions             `json:"-" yaml:"-"`
+
+	// PathOrder lists the keys of Paths in declaration order, which output keeps.
+	PathOrder []string `json:"-" yaml:"-"`
 }
 
 // Info provides metadata about the API.
 // https://spec.openapis.org/oas/v3.1.0#info-object
 type Info struct {
-	Title          string   `json:"title" yaml:"title"`
-	Summary        string   `json:"summary,omitempty" yaml:"summary,omitempty"`
-	Description    string   `json:"description,omitempty" yaml:"description,omitempty"`
-	TermsOfService string   `json:"termsOfService,omitempty" yaml:"termsOfService,omitempty"`
-	Contact        *Contact `json:"contact,omitempty" yaml:"contact,omitempty"`
-	License        *License `json:"license,omitempty" yaml:"license,omitempty"`
-	Version        string   `json:"version" yaml:"version"`
+	Title          string     `json:"title" yaml:"title"`
+	Summary        string     `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description    string     `json:"description,omitempty" yaml:"description,omitempty"`
+	TermsOfService string     `json:"termsOfService,omitempty" yaml:"termsOfService,omitempty"`
+	Contact        *Contact   `json:"contact,omitempty" yaml:"contact,omitempty"`
+	License        *License   `json:"license,omitempty" yaml:"license,omitempty"`
+	Version        string     `json:"version" yaml:"version"`
+	Extensions     Extensions `json:"-" yaml:"-"`
 }
 
 // Contact provides contact information for the API.
 // https://spec.openapis.org/oas/v3.1.0#contact-object
 type Contact struct {
-	Name  string `json:"name,omitempty" yaml:"name,omitempty"`
-	URL   string `json:"url,omitempty" yaml:"url,omitempty"`
-	Email string `json:"email,omitempty" yaml:"email,omitempty"`
+	Name       string     `json:"name,omitempty" yaml:"name,omitempty"`
+	URL        string     `json:"url,omitempty" yaml:"url,omitempty"`
+	Email      string     `json:"email,omitempty" yaml:"email,omitempty"`
+	Extensions Extensions `json:"-" yaml:"-"`
 }
 
 // License provides license information for the API.
 // https://spec.openapis.org/oas/v3.1.0#license-object
 type License struct {
-	Name       string `json:"name" yaml:"name"`
-	Identifier string `json:"identifier,omitempty" yaml:"identifier,omitempty"`
-	URL        string `json:"url,omitempty" yaml:"url,omitempty"`
+	Name       string     `json:"name" yaml:"name"`
+	Identifier string     `json:"identifier,omitempty" yaml:"identifier,omitempty"`
+	URL        string     `json:"url,omitempty" yaml:"url,omitempty"`
+	Extensions Extensions `json:"-" yaml:"-"`
 }
 
 // Server represents a server.
@@ -49,14 +56,16 @@ type Server struct {
 	URL         string                    `json:"url" yaml:"url"`
 	Description string                    `json:"description,omitempty" yaml:"description,omitempty"`
 	Variables   map[string]ServerVariable `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Extensions  Extensions                `json:"-" yaml:"-"`
 }
 
 // ServerVariable represents a server variable for URL template substitution.
 // https://spec.openapis.org/oas/v3.1.0#server-variable-object
 type ServerVariable struct {
-	Enum        []string `json:"enum,omitempty" yaml:"enum,omitempty"`
-	Default     string   `json:"default" yaml:"default"`
-	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Enum        []string   `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Default     string     `json:"default" yaml:"default"`
+	Description string     `json:"description,omitempty" yaml:"description,omitempty"`
+	Extensions  Extensions `json:"-" yaml:"-"`
 }
 
 // Paths holds the relative paths to the individual endpoints and their operations.
@@ -79,6 +88,7 @@ type PathItem struct {
 	Trace       *Operation   `json:"trace,omitempty" yaml:"trace,omitempty"`
 	Servers     []Server     `json:"servers,omitempty" yaml:"servers,omitempty"`
 	Parameters  []*Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Extensions  Extensions   `json:"-" yaml:"-"`
 }
 
 // Operation describes a single API operation on a path.
@@ -96,13 +106,19 @@ type Operation struct {
 	Deprecated   bool                   `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
 	Security     []SecurityRequirement  `json:"security,omitempty" yaml:"security,omitempty"`
 	Servers      []Server               `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Owner        *Owner                 `json:"x-owner,omitempty" yaml:"x-owner,omitempty"`
+	Extensions   Extensions             `json:"-" yaml:"-"`
+
+	// ResponseOrder lists the keys of Responses in declaration order, which output keeps.
+	ResponseOrder []string `json:"-" yaml:"-"`
 }
 
 // ExternalDocumentation allows referencing an external resource for extended documentation.
 // https://spec.openapis.org/oas/v3.1.0#external-documentation-object
 type ExternalDocumentation struct {
-	Description string `json:"description,omitempty" yaml:"description,omitempty"`
-	URL         string `json:"url" yaml:"url"`
+	Description string     `json:"description,omitempty" yaml:"description,omitempty"`
+	URL         string     `json:"url" yaml:"url"`
+	Extensions  Extensions `json:"-" yaml:"-"`
 }
 
 // Parameter describes a single operation parameter.
@@ -122,6 +138,7 @@ type Parameter struct {
 	Example         any                  `json:"example,omitempty" yaml:"example,omitempty"`
 	Examples        map[string]*Example  `json:"examples,omitempty" yaml:"examples,omitempty"`
 	Content         map[string]MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+	Extensions      Extensions           `json:"-" yaml:"-"`
 }
 
 // ParameterLocation represents where a parameter is expected.
@@ -141,15 +158,17 @@ type RequestBody struct {
 	Description string               `json:"description,omitempty" yaml:"description,omitempty"`
 	Content     map[string]MediaType `json:"content,omitempty" yaml:"content,omitempty"`
 	Required    bool                 `json:"required,omitempty" yaml:"required,omitempty"`
+	Extensions  Extensions           `json:"-" yaml:"-"`
 }
 
 // MediaType provides schema and examples for the media type identified by its key.
 // https://spec.openapis.org/oas/v3.1.0#media-type-object
 type MediaType struct {
-	Schema   *Schema             `json:"schema,omitempty" yaml:"schema,omitempty"`
-	Example  any                 `json:"example,omitempty" yaml:"example,omitempty"`
-	Examples map[string]*Example `json:"examples,omitempty" yaml:"examples,omitempty"`
-	Encoding map[string]Encoding `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+	Schema     *Schema             `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Example    any                 `json:"example,omitempty" yaml:"example,omitempty"`
+	Examples   map[string]*Example `json:"examples,omitempty" yaml:"examples,omitempty"`
+	Encoding   map[string]Encoding `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+	Extensions Extensions          `json:"-" yaml:"-"`
 }
 
 // Encoding describes a single encoding definition applied to a single schema property.
@@ -160,6 +179,7 @@ type Encoding struct {
 	Style         string             `json:"style,omitempty" yaml:"style,omitempty"`
 	Explode       bool               `json:"explode,omitempty" yaml:"explode,omitempty"`
 	AllowReserved bool               `json:"allowReserved,omitempty" yaml:"allowReserved,omitempty"`
+	Extensions    Extensions         `json:"-" yaml:"-"`
 }
 
 // Responses is a container for the expected responses of an operation.
@@ -174,6 +194,7 @@ type Response struct {
 	Headers     map[string]*Header   `json:"headers,omitempty" yaml:"headers,omitempty"`
 	Content     map[string]MediaType `json:"content,omitempty" yaml:"content,omitempty"`
 	Links       map[string]*Link     `json:"links,omitempty" yaml:"links,omitempty"`
+	Extensions  Extensions           `json:"-" yaml:"-"`
 }
 
 // Header follows the structure of the Parameter Object with some differences.
@@ -191,6 +212,7 @@ type Header struct {
 	Example         any                  `json:"example,omitempty" yaml:"example,omitempty"`
 	Examples        map[string]*Example  `json:"examples,omitempty" yaml:"examples,omitempty"`
 	Content         map[string]MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+	Extensions      Extensions           `json:"-" yaml:"-"`
 }
 
 // Callback is a map of possible out-of-band callbacks related to the parent operation.
@@ -207,16 +229,18 @@ type Link struct {
 	RequestBody  any            `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
 	Description  string         `json:"description,omitempty" yaml:"description,omitempty"`
 	Server       *Server        `json:"server,omitempty" yaml:"server,omitempty"`
+	Extensions   Extensions     `json:"-" yaml:"-"`
 }
 
 // Example describes an example of a media type.
 // https://spec.openapis.org/oas/v3.1.0#example-object
 type Example struct {
-	Ref           string `json:"$ref,omitempty" yaml:"$ref,omitempty"`
-	Summary       string `json:"summary,omitempty" yaml:"summary,omitempty"`
-	Description   string `json:"description,omitempty" yaml:"description,omitempty"`
-	Value         any    `json:"value,omitempty" yaml:"value,omitempty"`
-	ExternalValue string `json:"externalValue,omitempty" yaml:"externalValue,omitempty"`
+	Ref           string     `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Summary       string     `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description   string     `json:"description,omitempty" yaml:"description,omitempty"`
+	Value         any        `json:"value,omitempty" yaml:"value,omitempty"`
+	ExternalValue string     `json:"externalValue,omitempty" yaml:"externalValue,omitempty"`
+	Extensions    Extensions `json:"-" yaml:"-"`
 }
 
 // Tag adds metadata to a single tag that is used by the Operation Object.
@@ -225,6 +249,8 @@ type Tag struct {
 	Name         string                 `json:"name" yaml:"name"`
 	Description  string                 `json:"description,omitempty" yaml:"description,omitempty"`
 	ExternalDocs *ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+	Owner        *Owner                 `json:"x-owner,omitempty" yaml:"x-owner,omitempty"`
+	Extensions   Extensions             `json:"-" yaml:"-"`
 }
 
 // Components holds a set of reusable objects for different aspects of the OAS.
@@ -240,6 +266,7 @@ type Components struct {
 	Links           map[string]*Link           `json:"links,omitempty" yaml:"links,omitempty"`
 	Callbacks       map[string]*Callback       `json:"callbacks,omitempty" yaml:"callbacks,omitempty"`
 	PathItems       map[string]*PathItem       `json:"pathItems,omitempty" yaml:"pathItems,omitempty"`
+	Extensions      Extensions                 `json:"-" yaml:"-"`
 }
 
 // SecurityScheme defines a security scheme that can be used by the operations.
@@ -254,6 +281,7 @@ type SecurityScheme struct {
 	BearerFormat     string      `json:"bearerFormat,omitempty" yaml:"bearerFormat,omitempty"`
 	Flows            *OAuthFlows `json:"flows,omitempty" yaml:"flows,omitempty"`
 	OpenIDConnectURL string      `json:"openIdConnectUrl,omitempty" yaml:"openIdConnectUrl,omitempty"`
+	Extensions       Extensions  `json:"-" yaml:"-"`
 }
 
 // OAuthFlows allows configuration of the supported OAuth Flows.
@@ -263,6 +291,7 @@ type OAuthFlows struct {
 	Password          *OAuthFlow `json:"password,omitempty" yaml:"password,omitempty"`
 	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty" yaml:"clientCredentials,omitempty"`
 	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty" yaml:"authorizationCode,omitempty"`
+	Extensions        Extensions `json:"-" yaml:"-"`
 }
 
 // OAuthFlow provides configuration details for a supported OAuth Flow.
@@ -272,6 +301,7 @@ type OAuthFlow struct {
 	TokenURL         string            `json:"tokenUrl,omitempty" yaml:"tokenUrl,omitempty"`
 	RefreshURL       string            `json:"refreshUrl,omitempty" yaml:"refreshUrl,omitempty"`
 	Scopes           map[string]string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+	Extensions       Extensions        `json:"-" yaml:"-"`
 }
 
 // SecurityRequirement lists the required security schemes to execute this operation.
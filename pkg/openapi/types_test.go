@@ -2,6 +2,7 @@ package openapi
 
 import (
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -487,3 +488,81 @@ func TestMediaType_WithExample(t *testing.T) {
 		t.Errorf("Examples length = %d, want 1", len(decoded.Examples))
 	}
 }
+
+func TestDocument_OwnerOf(t *testing.T) {
+	doc := &Document{
+		Tags: []Tag{
+			{Name: "pets"},
+			{Name: "store", Owner: &Owner{Team: "team-store"}},
+		},
+	}
+	tests := []struct {
+		name string
+		op   *Operation
+		want string
+	}{
+		{"own owner wins", &Operation{Tags: []string{"store"}, Owner: &Owner{Team: "team-orders"}}, "team-orders"},
+		{"first owned tag", &Operation{Tags: []string{"pets", "store"}}, "team-store"},
+		{"unowned", &Operation{Tags: []string{"pets", "unknown"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if owner := doc.OwnerOf(tt.op); owner != nil {
+				got = owner.Team
+			}
+			if got != tt.want {
+				t.Errorf("OwnerOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	data, err := json.Marshal(doc.Tags[1])
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"x-owner":{"team":"team-store"}`) {
+		t.Errorf("tag JSON = %s, want x-owner extension", data)
+	}
+}
+
+func TestDocument_DeprecatedProperties(t *testing.T) {
+	doc := &Document{
+		Paths: Paths{
+			"/pets": {
+				Get:  &Operation{Responses: Responses{"200": {Content: map[string]MediaType{"application/json": {Schema: ArraySchema(RefTo("Pet"))}}}}},
+				Post: &Operation{RequestBody: RefToRequestBody("NewPet")},
+			},
+			"/owners": {Get: &Operation{Responses: Responses{"200": RefToResponse("Owners")}}},
+		},
+		Components: &Components{
+			Schemas: map[string]*Schema{
+				"Pet": {
+					Properties: map[string]*Schema{
+						"name":     StringSchema(),
+						"nickname": {Type: NewSchemaType(TypeString), Deprecated: true},
+						"owner":    RefTo("Owner"),
+					},
+					Required: []string{"name", "nickname"},
+				},
+				"Owner":  {Properties: map[string]*Schema{"phone": {Type: NewSchemaType(TypeString), Deprecated: true}}},
+				"Legacy": {Properties: map[string]*Schema{"code": {Deprecated: true}}},
+			},
+			RequestBodies: map[string]*RequestBody{
+				"NewPet": {Content: map[string]MediaType{"application/json": {Schema: RefTo("Pet")}}},
+			},
+			Responses: map[string]*Response{
+				"Owners": {Content: map[string]MediaType{"application/json": {Schema: ArraySchema(RefTo("Owner"))}}},
+			},
+		},
+	}
+
+	want := []DeprecatedProperty{
+		{Schema: "Legacy", Property: "code", Operations: []string{}},
+		{Schema: "Owner", Property: "phone", Operations: []string{"GET /owners", "GET /pets", "POST /pets"}},
+		{Schema: "Pet", Property: "nickname", Required: true, Operations: []string{"GET /pets", "POST /pets"}},
+	}
+	if got := doc.DeprecatedProperties(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DeprecatedProperties() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,148 @@
+package openapi
+
+// walkDocumentSchemas calls fn for every schema reachable from the document,
+// including components, paths, webhooks, and callbacks.
+// Nested schemas are visited before their parents.
+func walkDocumentSchemas(doc *Document, fn func(*Schema)) {
+	if doc == nil {
+		return
+	}
+	for _, item := range doc.Paths {
+		walkPathItemSchemas(item, fn)
+	}
+	for _, item := range doc.Webhooks {
+		walkPathItemSchemas(item, fn)
+	}
+	walkComponentSchemas(doc.Components, fn)
+}
+
+func walkComponentSchemas(c *Components, fn func(*Schema)) {
+	if c == nil {
+		return
+	}
+	for _, s := range c.Schemas {
+		walkSchema(s, fn)
+	}
+	for _, p := range c.Parameters {
+		walkParameterSchemas(p, fn)
+	}
+	for _, rb := range c.RequestBodies {
+		walkRequestBodySchemas(rb, fn)
+	}
+	for _, r := range c.Responses {
+		walkResponseSchemas(r, fn)
+	}
+	walkHeaderMapSchemas(c.Headers, fn)
+	for _, cb := range c.Callbacks {
+		walkCallbackSchemas(cb, fn)
+	}
+	for _, item := range c.PathItems {
+		walkPathItemSchemas(item, fn)
+	}
+}
+
+func walkPathItemSchemas(item *PathItem, fn func(*Schema)) {
+	if item == nil {
+		return
+	}
+	for _, p := range item.Parameters {
+		walkParameterSchemas(p, fn)
+	}
+	for _, op := range pathItemOperations(item) {
+		walkOperationSchemas(op, fn)
+	}
+}
+
+func walkOperationSchemas(op *Operation, fn func(*Schema)) {
+	for _, p := range op.Parameters {
+		walkParameterSchemas(p, fn)
+	}
+	walkRequestBodySchemas(op.RequestBody, fn)
+	for _, r := range op.Responses {
+		walkResponseSchemas(r, fn)
+	}
+	for _, cb := range op.Callbacks {
+		walkCallbackSchemas(cb, fn)
+	}
+}
+
+func walkCallbackSchemas(cb *Callback, fn func(*Schema)) {
+	if cb == nil {
+		return
+	}
+	for _, item := range *cb {
+		walkPathItemSchemas(item, fn)
+	}
+}
+
+func walkParameterSchemas(p *Parameter, fn func(*Schema)) {
+	if p == nil {
+		return
+	}
+	walkSchema(p.Schema, fn)
+	walkContentSchemas(p.Content, fn)
+}
+
+func walkRequestBodySchemas(rb *RequestBody, fn func(*Schema)) {
+	if rb == nil {
+		return
+	}
+	walkContentSchemas(rb.Content, fn)
+}
+
+func walkResponseSchemas(r *Response, fn func(*Schema)) {
+	if r == nil {
+		return
+	}
+	walkHeaderMapSchemas(r.Headers, fn)
+	walkContentSchemas(r.Content, fn)
+}
+
+func walkHeaderMapSchemas(headers map[string]*Header, fn func(*Schema)) {
+	for _, h := range headers {
+		if h == nil {
+			continue
+		}
+		walkSchema(h.Schema, fn)
+		walkContentSchemas(h.Content, fn)
+	}
+}
+
+func walkContentSchemas(content map[string]MediaType, fn func(*Schema)) {
+	for _, mt := range content {
+		walkSchema(mt.Schema, fn)
+	}
+}
+
+// walkSchema visits the nested schemas of s and then s itself.
+func walkSchema(s *Schema, fn func(*Schema)) {
+	if s == nil {
+		return
+	}
+	walkSchema(s.Items, fn)
+	walkSchema(s.AdditionalProperties, fn)
+	walkSchema(s.Not, fn)
+	for _, prop := range s.Properties {
+		walkSchema(prop, fn)
+	}
+	for _, group := range [][]*Schema{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, sub := range group {
+			walkSchema(sub, fn)
+		}
+	}
+	fn(s)
+}
+
+// pathItemOperations returns all non-nil operations of a path item.
+func pathItemOperations(item *PathItem) []*Operation {
+	var ops []*Operation
+	for _, op := range []*Operation{
+		item.Get, item.Put, item.Post, item.Delete,
+		item.Options, item.Head, item.Patch, item.Trace,
+	} {
+		if op != nil {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
@@ -0,0 +1,144 @@
+// Package overlay customizes generated OpenAPI specifications without
+// touching the annotations they come from, by applying OpenAPI Overlay
+// documents (https://spec.openapis.org/overlay/v1.0.0.html) or JSON Merge
+// Patches (RFC 7396) to them.
+package overlay
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pb33f/jsonpath/pkg/jsonpath"
+	"github.com/pb33f/jsonpath/pkg/jsonpath/config"
+	"github.com/pb33f/jsonpath/pkg/overlay"
+	"go.yaml.in/yaml/v4"
+)
+
+// Result is a patched specification.
+type Result struct {
+	// Spec is the patched specification in YAML
+	Spec []byte
+
+	// Unmatched lists the targets of overlay actions that matched nothing,
+	// usually a sign that the overlay is out of date with the spec
+	Unmatched []string
+}
+
+// Apply applies patch to spec, both JSON or YAML. A patch with an overlay
+// field is an Overlay document, whose actions are applied in order: update
+// merges into every node the target JSONPath selects, and remove deletes
+// them. Any other patch is a JSON Merge Patch, where objects are merged
+// recursively, null deletes a field, and other values replace the original.
+func Apply(spec, patch []byte) (*Result, error) {
+	root, err := decode(spec, "spec")
+	if err != nil {
+		return nil, err
+	}
+	patchRoot, err := decode(patch, "patch")
+	if err != nil {
+		return nil, err
+	}
+
+	var unmatched []string
+	if isOverlay(patchRoot) {
+		unmatched, err = applyOverlay(root, patchRoot)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		root = mergePatch(root, patchRoot)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return nil, fmt.Errorf("failed to encode patched spec: %w", err)
+	}
+	return &Result{Spec: buf.Bytes(), Unmatched: unmatched}, nil
+}
+
+// decode parses a JSON or YAML document into its root node.
+func decode(data []byte, what string) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", what, err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, fmt.Errorf("failed to parse %s: empty document", what)
+	}
+	return doc.Content[0], nil
+}
+
+// isOverlay reports whether the patch is an Overlay document.
+func isOverlay(patch *yaml.Node) bool {
+	return memberIndex(patch, "overlay") >= 0
+}
+
+// applyOverlay applies the Overlay document in patch to root, returning the
+// targets of the actions that matched nothing.
+func applyOverlay(root, patch *yaml.Node) ([]string, error) {
+	var o overlay.Overlay
+	if err := patch.Decode(&o); err != nil {
+		return nil, fmt.Errorf("failed to parse overlay: %w", err)
+	}
+	if err := o.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid overlay: %w", err)
+	}
+
+	var unmatched []string
+	for i, action := range o.Actions {
+		path, err := jsonpath.NewPath(action.Target, config.WithPropertyNameExtension())
+		if err != nil {
+			return nil, fmt.Errorf("overlay action %d: invalid target %q: %w", i, action.Target, err)
+		}
+		if len(path.Query(root)) == 0 {
+			unmatched = append(unmatched, action.Target)
+			continue
+		}
+		single := overlay.Overlay{Actions: []overlay.Action{action}}
+		if err := single.ApplyTo(root); err != nil {
+			return nil, fmt.Errorf("overlay action %d: %w", i, err)
+		}
+	}
+	return unmatched, nil
+}
+
+// mergePatch merges patch into target as RFC 7396 describes, returning the result.
+func mergePatch(target, patch *yaml.Node) *yaml.Node {
+	if patch.Kind != yaml.MappingNode {
+		return patch
+	}
+	if target == nil || target.Kind != yaml.MappingNode {
+		target = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+	for i := 0; i+1 < len(patch.Content); i += 2 {
+		key, value := patch.Content[i], patch.Content[i+1]
+		j := memberIndex(target, key.Value)
+		switch {
+		case value.Tag == "!!null":
+			if j >= 0 {
+				target.Content = append(target.Content[:j], target.Content[j+2:]...)
+			}
+		case j >= 0:
+			target.Content[j+1] = mergePatch(target.Content[j+1], value)
+		default:
+			target.Content = append(target.Content, key, mergePatch(nil, value))
+		}
+	}
+	return target
+}
+
+// memberIndex returns the index of the key node of the member key in the
+// mapping node, or -1 if node is not a mapping or has no such member.
+func memberIndex(node *yaml.Node, key string) int {
+	if node.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
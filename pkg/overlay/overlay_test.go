@@ -0,0 +1,108 @@
+package overlay
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const testSpec = `openapi: 3.0.3
+info:
+  title: Pet Store
+  version: 1.0.0
+  termsOfService: https://example.com/tos
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pet]
+  /internal/stats:
+    get:
+      operationId: stats
+      tags: [internal]
+`
+
+// decodeResult parses the patched spec into a generic tree.
+func decodeResult(t *testing.T, result *Result) map[string]any {
+	t.Helper()
+	var spec map[string]any
+	if err := yaml.Unmarshal(result.Spec, &spec); err != nil {
+		t.Fatalf("patched spec does not parse: %v", err)
+	}
+	return spec
+}
+
+func TestApply_Overlay(t *testing.T) {
+	result, err := Apply([]byte(testSpec), []byte(`overlay: 1.0.0
+info: {title: Public, version: 1.0.0}
+actions:
+  - target: $.info
+    update:
+      description: The public API.
+  - target: $.paths['/pets'].get
+    update:
+      x-rate-limit: 100
+  - target: $.paths[*][?@.tags[0] == 'internal']
+    remove: true
+  - target: $.components.schemas
+    update: {Pet: {type: object}}
+`))
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	spec := decodeResult(t, result)
+	info := spec["info"].(map[string]any)
+	if info["description"] != "The public API." || info["title"] != "Pet Store" {
+		t.Errorf("info = %v, want description merged in", info)
+	}
+	paths := spec["paths"].(map[string]any)
+	if get := paths["/pets"].(map[string]any)["get"].(map[string]any); get["x-rate-limit"] != 100 {
+		t.Errorf("/pets get = %v, want x-rate-limit", get)
+	}
+	if stats := paths["/internal/stats"].(map[string]any); len(stats) != 0 {
+		t.Errorf("/internal/stats = %v, want its operation removed", stats)
+	}
+	if len(result.Unmatched) != 1 || result.Unmatched[0] != "$.components.schemas" {
+		t.Errorf("Unmatched = %v, want the components target", result.Unmatched)
+	}
+}
+
+func TestApply_MergePatch(t *testing.T) {
+	result, err := Apply([]byte(testSpec), []byte(`{"info": {"title": "Public", "termsOfService": null}, "servers": [{"url": "https://api.example.com"}]}`))
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	spec := decodeResult(t, result)
+	info := spec["info"].(map[string]any)
+	if _, ok := info["termsOfService"]; ok || info["title"] != "Public" || info["version"] != "1.0.0" {
+		t.Errorf("info = %v, want title replaced and termsOfService removed", info)
+	}
+	if servers := spec["servers"].([]any); len(servers) != 1 {
+		t.Errorf("servers = %v, want the patch's server", servers)
+	}
+	if !strings.HasPrefix(string(result.Spec), "openapi:") {
+		t.Errorf("patched spec should keep its key order:\n%s", result.Spec)
+	}
+}
+
+func TestApply_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		spec, patch, wantErr string
+	}{
+		"spec":        {spec: "openapi: [", patch: "{}", wantErr: "failed to parse spec"},
+		"empty patch": {spec: testSpec, patch: "", wantErr: "empty document"},
+		"no info":     {spec: testSpec, patch: "overlay: 1.0.0\nactions: []", wantErr: "invalid overlay"},
+		"target":      {spec: testSpec, patch: "overlay: 1.0.0\ninfo: {title: t, version: v}\nactions: [{target: '$[', remove: true}]", wantErr: "invalid target"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Apply([]byte(tt.spec), []byte(tt.patch))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Apply() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
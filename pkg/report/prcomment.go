@@ -0,0 +1,78 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PRCommentOptions controls the size of a pull request comment.
+type PRCommentOptions struct {
+	MaxFindings int    // findings listed in the table, most severe first (default 10)
+	ArtifactURL string // link to the full report, e.g. a CI artifact (optional)
+}
+
+func (o PRCommentOptions) maxFindings() int {
+	if o.MaxFindings > 0 {
+		return o.MaxFindings
+	}
+	return 10
+}
+
+// PRComment is a report rendered as Markdown sized for a GitHub or GitLab
+// pull request comment: a heading, a summary, and a collapsed table of the
+// first rows.
+type PRComment struct {
+	Heading string     // e.g. "Lint failed: 1 error, 0 warning, 0 info"
+	Summary string     // lines following the heading (optional)
+	Empty   string     // shown instead of the table when there are no rows
+	Noun    string     // what the rows are, e.g. "findings"
+	Report  string     // text of the link to the full report, e.g. "Full lint report"
+	Columns []string   // column headings
+	Rows    [][]string // cells, most important rows first
+}
+
+// Format renders the comment, listing at most opts.MaxFindings rows.
+func (c PRComment) Format(opts PRCommentOptions) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "### %s\n\n", c.Heading)
+	if c.Summary != "" {
+		fmt.Fprintf(&sb, "%s\n\n", c.Summary)
+	}
+	if len(c.Rows) == 0 {
+		sb.WriteString(c.Empty + "\n")
+		return sb.String()
+	}
+
+	shown := min(len(c.Rows), opts.maxFindings())
+	sb.WriteString("<details>\n")
+	fmt.Fprintf(&sb, "<summary>Top %d of %d %s</summary>\n\n", shown, len(c.Rows), c.Noun)
+	sb.WriteString(tableRow(c.Columns))
+	separators := make([]string, len(c.Columns))
+	for i, column := range c.Columns {
+		separators[i] = strings.Repeat("-", len(column)+2)
+	}
+	sb.WriteString("|" + strings.Join(separators, "|") + "|\n")
+	for _, row := range c.Rows[:shown] {
+		sb.WriteString(tableRow(row))
+	}
+	sb.WriteString("\n</details>\n")
+
+	if opts.ArtifactURL != "" {
+		fmt.Fprintf(&sb, "\n[%s](%s)\n", c.Report, opts.ArtifactURL)
+	}
+	return sb.String()
+}
+
+func tableRow(cells []string) string {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = Cell(cell)
+	}
+	return "| " + strings.Join(escaped, " | ") + " |\n"
+}
+
+// Cell makes text safe for a single Markdown table cell.
+func Cell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.Join(strings.Fields(text), " ")
+}
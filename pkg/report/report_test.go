@@ -0,0 +1,62 @@
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSeverity(t *testing.T) {
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("ParseSeverity(fatal) error = nil")
+	}
+	if s, err := ParseSeverity(" warning "); err != nil || s != SeverityWarning {
+		t.Errorf("ParseSeverity(warning) = %v, %v", s, err)
+	}
+	if SeverityOff.AtLeast(SeverityOff) || !SeverityError.AtLeast(SeverityWarning) || SeverityInfo.AtLeast(SeverityWarning) {
+		t.Error("AtLeast() orders severities wrongly")
+	}
+}
+
+func TestSeverityHelpers(t *testing.T) {
+	findings := []Severity{SeverityInfo, SeverityError, SeverityWarning, SeverityError}
+	self := func(s Severity) Severity { return s }
+	if n := Count(findings, self, SeverityError); n != 2 {
+		t.Errorf("Count(ERROR) = %d, want 2", n)
+	}
+	if !HasFindingsAtOrAbove(findings, self, SeverityError) || HasFindingsAtOrAbove(findings[:1], self, SeverityWarning) {
+		t.Error("HasFindingsAtOrAbove() is wrong")
+	}
+	if got := MostSevereFirst(findings, self); got[0] != SeverityError || got[2] != SeverityWarning || got[3] != SeverityInfo {
+		t.Errorf("MostSevereFirst() = %v", got)
+	}
+}
+
+func TestPRComment_Format(t *testing.T) {
+	comment := PRComment{
+		Heading: "Lint failed: 2 error",
+		Empty:   "No issues found.",
+		Noun:    "findings",
+		Report:  "Full lint report",
+		Columns: []string{"Severity", "Message"},
+		Rows:    [][]string{{"ERROR", "a | b"}, {"ERROR", "second\n line"}},
+	}
+	got := comment.Format(PRCommentOptions{MaxFindings: 1, ArtifactURL: "https://ci.example.com/1"})
+	for _, want := range []string{
+		"### Lint failed: 2 error\n\n<details>\n",
+		"<summary>Top 1 of 2 findings</summary>",
+		"| Severity | Message |\n|----------|---------|\n| ERROR | a \\| b |\n\n</details>",
+		"[Full lint report](https://ci.example.com/1)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Format() = %q, want it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "second") {
+		t.Errorf("Format() = %q, want one row", got)
+	}
+
+	comment.Rows = nil
+	if got := comment.Format(PRCommentOptions{}); got != "### Lint failed: 2 error\n\nNo issues found.\n" {
+		t.Errorf("Format() without rows = %q", got)
+	}
+}
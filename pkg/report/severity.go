@@ -0,0 +1,72 @@
+// Package report holds what the reports of yaswag have in common: the
+// severities of audit and lint findings, and pull request comments listing
+// findings or changes.
+package report
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Severity is how severe a finding is.
+type Severity string
+
+// Severities of findings; rules whose severity is SeverityOff report nothing.
+const (
+	SeverityError   Severity = "ERROR"
+	SeverityWarning Severity = "WARNING"
+	SeverityInfo    Severity = "INFO"
+	SeverityOff     Severity = "OFF" // disables a rule
+)
+
+// severityRank orders severities from least to most severe.
+var severityRank = map[Severity]int{
+	SeverityOff:     0,
+	SeverityInfo:    1,
+	SeverityWarning: 2,
+	SeverityError:   3,
+}
+
+// ParseSeverity parses a severity name case-insensitively (error, warning,
+// info, off).
+func ParseSeverity(s string) (Severity, error) {
+	severity := Severity(strings.ToUpper(strings.TrimSpace(s)))
+	if _, ok := severityRank[severity]; !ok {
+		return "", fmt.Errorf("unknown severity %q: must be error, warning, info, or off", s)
+	}
+	return severity, nil
+}
+
+// AtLeast reports whether s is as severe as or more severe than threshold.
+// Findings of rules turned off are never severe.
+func (s Severity) AtLeast(threshold Severity) bool {
+	return s != SeverityOff && severityRank[s] >= severityRank[threshold]
+}
+
+// Count returns the number of findings whose severity is s.
+func Count[F any](findings []F, severity func(F) Severity, s Severity) int {
+	n := 0
+	for _, f := range findings {
+		if severity(f) == s {
+			n++
+		}
+	}
+	return n
+}
+
+// HasFindingsAtOrAbove reports whether any finding is at least as severe as
+// threshold.
+func HasFindingsAtOrAbove[F any](findings []F, severity func(F) Severity, threshold Severity) bool {
+	return slices.ContainsFunc(findings, func(f F) bool { return severity(f).AtLeast(threshold) })
+}
+
+// MostSevereFirst returns a copy of findings sorted from most to least
+// severe, keeping equally severe findings in order.
+func MostSevereFirst[F any](findings []F, severity func(F) Severity) []F {
+	sorted := slices.Clone(findings)
+	slices.SortStableFunc(sorted, func(a, b F) int {
+		return severityRank[severity(b)] - severityRank[severity(a)]
+	})
+	return sorted
+}
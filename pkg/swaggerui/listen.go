@@ -0,0 +1,168 @@
+package swaggerui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Timeouts bounds the requests of a server and its shutdown. Zero values mean
+// no limit, as for http.Server.
+type Timeouts struct {
+	// ReadHeader is the time to read the request headers (default: 10s)
+	ReadHeader time.Duration
+
+	// Read is the time to read the whole request (default: 30s)
+	Read time.Duration
+
+	// Write is the time to write the response (default: none, so that the
+	// live reload events stream stays open)
+	Write time.Duration
+
+	// Idle is the time to keep an idle keep-alive connection (default: 2m)
+	Idle time.Duration
+
+	// Shutdown is the time in-flight requests get to finish once the context
+	// of ServeContext is done (default: 5s)
+	Shutdown time.Duration
+}
+
+// DefaultTimeouts returns the timeouts of new servers.
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		ReadHeader: 10 * time.Second,
+		Read:       30 * time.Second,
+		Idle:       2 * time.Minute,
+		Shutdown:   5 * time.Second,
+	}
+}
+
+// listenConfig is how Server and EditorServer listen.
+type listenConfig struct {
+	port     int
+	host     string
+	certFile string
+	keyFile  string
+	timeouts Timeouts
+}
+
+func newListenConfig(port int) listenConfig {
+	return listenConfig{port: port, timeouts: DefaultTimeouts()}
+}
+
+// SetHost binds the server to the interface of host, such as 127.0.0.1 to
+// accept local connections only (default: all interfaces).
+func (l *listenConfig) SetHost(host string) {
+	l.host = host
+}
+
+// SetTLS serves HTTPS with the certificate and key in PEM files.
+func (l *listenConfig) SetTLS(certFile, keyFile string) {
+	l.certFile = certFile
+	l.keyFile = keyFile
+}
+
+// SetTimeouts replaces the timeouts of the server.
+func (l *listenConfig) SetTimeouts(timeouts Timeouts) {
+	l.timeouts = timeouts
+}
+
+// addr returns the address to listen on.
+func (l *listenConfig) addr() string {
+	return net.JoinHostPort(l.host, strconv.Itoa(l.port))
+}
+
+// listenAndServe listens on the configured address and serves handler, the
+// name of which is printed along with its URL, until ctx is done.
+func (l *listenConfig) listenAndServe(ctx context.Context, handler http.Handler, name string) error {
+	if (l.certFile == "") != (l.keyFile == "") {
+		return fmt.Errorf("TLS needs both a certificate and a key file")
+	}
+	ln, err := net.Listen("tcp", l.addr())
+	if err != nil {
+		return err
+	}
+
+	scheme, host := "http", l.host
+	if l.certFile != "" {
+		scheme = "https"
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	fmt.Printf("%s is available at %s://%s\n", name, scheme, net.JoinHostPort(host, strconv.Itoa(port)))
+	fmt.Println("Press Ctrl+C to stop the server")
+
+	return l.serve(ctx, ln, handler)
+}
+
+// serve serves handler on ln until ctx is done, then shuts down gracefully:
+// it stops accepting connections and gives the in-flight requests the
+// shutdown timeout to finish. Long-lived requests, such as the live reload
+// events stream, end as soon as the shutdown starts; the contexts of the
+// others are canceled only once it is over.
+func (l *listenConfig) serve(ctx context.Context, ln net.Listener, handler http.Handler) error {
+	base, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+	streams, endStreams := context.WithCancel(base)
+	base = context.WithValue(base, shutdownKey{}, streams)
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: l.timeouts.ReadHeader,
+		ReadTimeout:       l.timeouts.Read,
+		WriteTimeout:      l.timeouts.Write,
+		IdleTimeout:       l.timeouts.Idle,
+		BaseContext:       func(net.Listener) context.Context { return base },
+	}
+	server.RegisterOnShutdown(endStreams)
+
+	errs := make(chan error, 1)
+	go func() {
+		if l.certFile != "" {
+			errs <- server.ServeTLS(ln, l.certFile, l.keyFile)
+		} else {
+			errs <- server.Serve(ln)
+		}
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx := context.Background()
+	if l.timeouts.Shutdown > 0 {
+		var cancel context.CancelFunc
+		shutdownCtx, cancel = context.WithTimeout(shutdownCtx, l.timeouts.Shutdown)
+		defer cancel()
+	}
+	err := server.Shutdown(shutdownCtx)
+	cancelRequests()
+	if err != nil {
+		return fmt.Errorf("failed to shut down: %w", err)
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// shutdownKey is the context key of the context that serve cancels when the
+// shutdown starts.
+type shutdownKey struct{}
+
+// shuttingDown returns a channel closed when the server of the request with
+// context ctx starts shutting down, so that long-lived streams can end
+// without holding up the shutdown. It is nil outside serve.
+func shuttingDown(ctx context.Context) <-chan struct{} {
+	if streams, ok := ctx.Value(shutdownKey{}).(context.Context); ok {
+		return streams.Done()
+	}
+	return nil
+}
@@ -2,6 +2,8 @@
 package swaggerui
 
 import (
+	"bytes"
+	"context"
 	"embed"
 	"encoding/json"
 	"fmt"
@@ -10,25 +12,55 @@ import (
 	"net/http"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/pkg/uiassets"
 	"github.com/fathurrohman26/yaswag/pkg/validator"
 )
 
 //go:embed templates/*.html
 var templates embed.FS
 
+// pages holds the UI and editor page templates, parsed once, and the branding templates they use.
+var pages = template.Must(template.Must(template.ParseFS(templates, "templates/*.html")).Parse(uiassets.BrandingTemplates))
+
 // Server serves OpenAPI specifications with Swagger UI.
 type Server struct {
 	specData    []byte
 	specURL     string
 	isRemoteURL bool
-	port        int
+	offline     bool
+	ui          uiassets.UI
+	branding    *uiassets.Branding
+	listenConfig
+
+	// spec and specType are specData as served by handleSpec, prepared when it
+	// is set. specMu guards the three, so that a reload swaps them at once.
+	specMu   sync.RWMutex
+	spec     []byte
+	specType string
+
+	// liveReload tells the open pages over /events when the spec changes
+	liveReload bool
+	reloads    reloadBroadcaster
+
+	// specs are the named specs served at /openapi/{name}.json
+	specs []namedSpec
+
+	// page is the UI page, rendered again whenever a setter changes it
+	page    []byte
+	pageErr error
 }
 
 // NewServer creates a new Swagger UI server.
 func NewServer(port int) *Server {
-	return &Server{port: port}
+	s := &Server{listenConfig: newListenConfig(port)}
+	s.renderPage()
+	return s
 }
 
 // SetSpecFromFile loads the OpenAPI specification from a file.
@@ -37,8 +69,7 @@ func (s *Server) SetSpecFromFile(path string) error {
 	if err != nil {
 		return fmt.Errorf("failed to read spec file: %w", err)
 	}
-	s.specData = data
-	s.isRemoteURL = false
+	s.SetSpecFromData(data)
 	return nil
 }
 
@@ -46,36 +77,145 @@ func (s *Server) SetSpecFromFile(path string) error {
 func (s *Server) SetSpecFromURL(url string) {
 	s.specURL = url
 	s.isRemoteURL = true
+	s.renderPage()
 }
 
-// SetSpecFromData sets the OpenAPI specification from raw data.
+// SetSpecFromData sets the OpenAPI specification from raw data. It may be
+// called while serving, such as when the spec file changes; with live reload,
+// the open pages then reload the spec.
 func (s *Server) SetSpecFromData(data []byte) {
-	s.specData = data
-	s.isRemoteURL = false
+	// Patch OpenAPI 3.2.x to 3.1.x for Swagger UI compatibility
+	spec := patchOpenAPI32To31(data)
+	specType := specContentType(spec)
+
+	s.specMu.Lock()
+	s.specData, s.spec, s.specType = data, spec, specType
+	s.specMu.Unlock()
+
+	if s.isRemoteURL {
+		s.isRemoteURL = false
+		s.renderPage()
+	}
+	s.reloads.broadcast()
+}
+
+// namedSpec is a spec added with AddSpec.
+type namedSpec struct {
+	name string
+	data []byte // as added, for validation
+	json []byte // as served, patched for Swagger UI
+}
+
+// specNamePattern matches the spec names usable in URLs.
+var specNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// AddSpec adds a named spec, such as one version of an API, served at
+// /openapi/{name}.json. With named specs, Swagger UI has a selector of the
+// specs in the order they are added, instead of showing the spec set by
+// SetSpecFromData, SetSpecFromFile, or SetSpecFromURL.
+func (s *Server) AddSpec(name string, data []byte) error {
+	if !specNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid spec name %q: use letters, digits, '.', '_', and '-'", name)
+	}
+	if slices.ContainsFunc(s.specs, func(spec namedSpec) bool { return spec.name == name }) {
+		return fmt.Errorf("spec %q is already added", name)
+	}
+	jsonData := data
+	if !json.Valid(data) {
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("failed to parse spec %q: %w", name, err)
+		}
+		var err error
+		if jsonData, err = json.Marshal(doc); err != nil {
+			return fmt.Errorf("failed to convert spec %q to JSON: %w", name, err)
+		}
+	}
+	s.specs = append(s.specs, namedSpec{name: name, data: data, json: patchOpenAPI32To31(jsonData)})
+	s.renderPage()
+	return nil
+}
+
+// AddSpecFromFile adds a named spec loaded from a file; see AddSpec.
+func (s *Server) AddSpecFromFile(name, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read spec file: %w", err)
+	}
+	return s.AddSpec(name, data)
+}
+
+// SetDocsUI selects the documentation renderer served at the root path
+// (default: Swagger UI).
+func (s *Server) SetDocsUI(ui uiassets.UI) {
+	s.ui = ui
+	s.renderPage()
+}
+
+// SetOfflineAssets serves the documentation UI from bundles embedded in the
+// binary instead of a CDN, so it works without outbound network access. The
+// UI falls back to the CDN if the bundles were not embedded at build time.
+func (s *Server) SetOfflineAssets(enabled bool) {
+	s.offline = enabled
+	s.renderPage()
+}
+
+// SetLiveReload has the pages served reload the spec whenever it is set
+// again with SetSpecFromData, told by server-sent events at /events.
+func (s *Server) SetLiveReload(enabled bool) {
+	s.liveReload = enabled
+	s.renderPage()
+}
+
+// SetBranding adds a logo, favicon, primary color, footer text, and
+// navigation links to the documentation page.
+func (s *Server) SetBranding(branding *uiassets.Branding) {
+	s.branding = branding
+	s.renderPage()
 }
 
 // Serve starts the HTTP server and serves the Swagger UI.
 func (s *Server) Serve() error {
+	return s.ServeContext(context.Background())
+}
+
+// ServeContext serves the Swagger UI until ctx is done, then shuts down
+// gracefully within the shutdown timeout.
+func (s *Server) ServeContext(ctx context.Context) error {
+	return s.listenAndServe(ctx, s.handler(), "Swagger UI")
+}
+
+// handler returns the handler of the UI, specs, validation, and assets.
+func (s *Server) handler() http.Handler {
 	mux := http.NewServeMux()
 
+	// Serve the embedded UI assets
+	if s.offline {
+		if !uiassets.Embedded() {
+			fmt.Println("Warning: UI assets are not embedded; loading them from the CDN instead (run 'make ui-assets' and rebuild for offline use)")
+		}
+		mux.Handle("/assets/", http.StripPrefix("/assets", uiassets.Handler()))
+	}
+
 	// Serve the spec
 	mux.HandleFunc("/spec", s.handleSpec)
+	mux.HandleFunc("/openapi/", s.handleNamedSpec)
+	if s.liveReload {
+		mux.HandleFunc("/events", s.handleEvents)
+	}
 
 	// Serve validation endpoint
 	mux.HandleFunc("/validate", s.handleValidate)
 
 	// Serve the Swagger UI HTML
 	mux.HandleFunc("/", s.handleUI)
-
-	addr := fmt.Sprintf(":%d", s.port)
-	fmt.Printf("Swagger UI is available at http://localhost%s\n", addr)
-	fmt.Println("Press Ctrl+C to stop the server")
-
-	return http.ListenAndServe(addr, mux)
+	return mux
 }
 
 func (s *Server) handleSpec(w http.ResponseWriter, r *http.Request) {
-	var specData []byte
+	s.specMu.RLock()
+	specData, contentType := s.spec, s.specType
+	s.specMu.RUnlock()
 
 	if s.isRemoteURL {
 		// Proxy the remote URL
@@ -91,23 +231,109 @@ func (s *Server) handleSpec(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, fmt.Sprintf("Failed to read remote spec: %v", err), http.StatusInternalServerError)
 			return
 		}
-	} else {
-		specData = s.specData
+		// Swagger UI does not yet support OpenAPI 3.2.x rendering
+		specData = patchOpenAPI32To31(specData)
+		contentType = specContentType(specData)
 	}
 
-	// Patch OpenAPI 3.2.x to 3.1.x for Swagger UI compatibility
-	// Swagger UI does not yet support OpenAPI 3.2.x rendering
-	specData = patchOpenAPI32To31(specData)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if s.liveReload {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	_, _ = w.Write(specData)
+}
 
-	// Determine content type
-	contentType := "application/json"
-	if len(specData) > 0 && (specData[0] == '-' || specData[0] == '#' || strings.HasPrefix(string(specData), "openapi:") || strings.HasPrefix(string(specData), "swagger:")) {
-		contentType = "application/yaml"
+// handleEvents streams a reload event to the page whenever the spec changes.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
 	}
+	reload := s.reloads.subscribe()
+	defer s.reloads.unsubscribe(reload)
 
-	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	_, _ = fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-shuttingDown(r.Context()):
+			return
+		case <-reload:
+			_, _ = fmt.Fprint(w, "event: reload\ndata: {}\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// reloadBroadcaster notifies the subscribed pages of spec reloads.
+type reloadBroadcaster struct {
+	mu      sync.Mutex
+	clients map[chan struct{}]struct{}
+}
+
+func (b *reloadBroadcaster) subscribe() chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.clients == nil {
+		b.clients = make(map[chan struct{}]struct{})
+	}
+	// buffered, so that a reload during a slow write is not lost
+	ch := make(chan struct{}, 1)
+	b.clients[ch] = struct{}{}
+	return ch
+}
+
+func (b *reloadBroadcaster) unsubscribe(ch chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.clients, ch)
+}
+
+func (b *reloadBroadcaster) broadcast() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.clients {
+		select {
+		case ch <- struct{}{}:
+		default: // a reload is already pending
+		}
+	}
+}
+
+// handleNamedSpec serves the named spec at /openapi/{name}.json.
+func (s *Server) handleNamedSpec(w http.ResponseWriter, r *http.Request) {
+	spec := s.namedSpec(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/openapi/"), ".json"))
+	if spec == nil || !strings.HasSuffix(r.URL.Path, ".json") {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	_, _ = w.Write(specData)
+	_, _ = w.Write(spec.json)
+}
+
+// namedSpec returns the named spec called name, or nil.
+func (s *Server) namedSpec(name string) *namedSpec {
+	for i := range s.specs {
+		if s.specs[i].name == name {
+			return &s.specs[i]
+		}
+	}
+	return nil
+}
+
+// specContentType tells YAML specs from JSON ones.
+func specContentType(data []byte) string {
+	if len(data) > 0 && (data[0] == '-' || data[0] == '#' || bytes.HasPrefix(data, []byte("openapi:")) || bytes.HasPrefix(data, []byte("swagger:"))) {
+		return "application/yaml"
+	}
+	return "application/json"
 }
 
 // ValidationResponse represents the JSON response for validation endpoint.
@@ -126,8 +352,20 @@ type ValidationItem struct {
 	Column  int    `json:"column,omitempty"`
 }
 
+// handleValidate validates the spec, or the named spec of the spec query
+// parameter.
 func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
-	specData, err := s.getSpecData()
+	var specData []byte
+	var err error
+	if name := r.URL.Query().Get("spec"); name != "" {
+		if spec := s.namedSpec(name); spec != nil {
+			specData = spec.data
+		} else {
+			err = fmt.Errorf("no spec named %q", name)
+		}
+	} else {
+		specData, err = s.getSpecData()
+	}
 	if err != nil {
 		writeValidationError(w, err.Error())
 		return
@@ -148,6 +386,8 @@ func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) getSpecData() ([]byte, error) {
 	if !s.isRemoteURL {
+		s.specMu.RLock()
+		defer s.specMu.RUnlock()
 		return s.specData, nil
 	}
 	resp, err := http.Get(s.specURL)
@@ -238,18 +478,12 @@ type SwaggerValidatorResponse struct {
 
 // callSwaggerValidator calls the Swagger.io validator API.
 func callSwaggerValidator(specData []byte) (*SwaggerValidatorResponse, error) {
-	// Determine content type
-	contentType := "application/json"
-	if len(specData) > 0 && (specData[0] == '-' || specData[0] == '#' || strings.HasPrefix(string(specData), "openapi:") || strings.HasPrefix(string(specData), "swagger:")) {
-		contentType = "application/yaml"
-	}
-
 	// POST to Swagger.io validator
-	req, err := http.NewRequest("POST", "https://validator.swagger.io/validator/debug", strings.NewReader(string(specData)))
+	req, err := http.NewRequest("POST", "https://validator.swagger.io/validator/debug", bytes.NewReader(specData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Content-Type", specContentType(specData))
 	req.Header.Set("Accept", "application/json")
 
 	client := &http.Client{}
@@ -284,48 +518,85 @@ func writeValidationError(w http.ResponseWriter, message string) {
 // patchOpenAPI32To31 patches OpenAPI 3.2.x versions to 3.1.0 for Swagger UI compatibility.
 // Swagger UI does not yet support rendering OpenAPI 3.2.x specifications.
 func patchOpenAPI32To31(data []byte) []byte {
-	content := string(data)
-
-	// Pattern to match openapi: 3.2.x in YAML or "openapi": "3.2.x" in JSON
-	yamlPattern := regexp.MustCompile(`(?m)^openapi:\s*['"]?(3\.2\.\d+)['"]?`)
-	jsonPattern := regexp.MustCompile(`"openapi"\s*:\s*"(3\.2\.\d+)"`)
-
-	// Check if patching is needed
-	if yamlPattern.MatchString(content) {
-		content = yamlPattern.ReplaceAllString(content, "openapi: 3.1.0")
+	if openAPI32YAML.Match(data) {
+		data = openAPI32YAML.ReplaceAll(data, []byte("openapi: 3.1.0"))
 	}
-	if jsonPattern.MatchString(content) {
-		content = jsonPattern.ReplaceAllString(content, `"openapi": "3.1.0"`)
+	if openAPI32JSON.Match(data) {
+		data = openAPI32JSON.ReplaceAll(data, []byte(`"openapi": "3.1.0"`))
 	}
-
-	return []byte(content)
+	return data
 }
 
-func (s *Server) handleUI(w http.ResponseWriter, r *http.Request) {
-	if r.URL.Path != "/" && r.URL.Path != "/index.html" {
-		http.NotFound(w, r)
-		return
-	}
+// Patterns matching openapi: 3.2.x in YAML and "openapi": "3.2.x" in JSON
+var (
+	openAPI32YAML = regexp.MustCompile(`(?m)^openapi:\s*['"]?(3\.2\.\d+)['"]?`)
+	openAPI32JSON = regexp.MustCompile(`"openapi"\s*:\s*"(3\.2\.\d+)"`)
+)
 
-	tmpl, err := template.ParseFS(templates, "templates/index.html")
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to load template: %v", err), http.StatusInternalServerError)
-		return
+// uiTemplate returns the template for the selected documentation renderer.
+func (s *Server) uiTemplate() string {
+	switch s.ui {
+	case uiassets.UIRedoc, uiassets.UIScalar, uiassets.UIRapiDoc:
+		return string(s.ui) + ".html"
+	default:
+		return "index.html"
 	}
+}
 
+// renderPage renders the UI page for the current settings.
+func (s *Server) renderPage() {
 	specURL := "/spec"
 	if s.isRemoteURL {
 		specURL = s.specURL
 	}
+	// the named specs replace the spec, starting with the first
+	var urls []map[string]string
+	for _, spec := range s.specs {
+		urls = append(urls, map[string]string{"name": spec.name, "url": "/openapi/" + spec.name + ".json"})
+	}
+	if len(urls) > 0 {
+		specURL = urls[0]["url"]
+	}
+
+	assets := uiassets.CDN()
+	if s.offline {
+		assets = uiassets.LocalOrCDN("/assets")
+	}
 
 	data := struct {
-		SpecURL string
+		SpecURL    string
+		URLs       []map[string]string
+		Assets     uiassets.URLs
+		Branding   *uiassets.Branding
+		LiveReload bool
 	}{
-		SpecURL: specURL,
+		SpecURL:    specURL,
+		URLs:       urls,
+		Assets:     assets,
+		Branding:   s.branding,
+		LiveReload: s.liveReload,
+	}
+
+	if s.pageErr = s.branding.Validate(); s.pageErr != nil {
+		return
+	}
+	var page bytes.Buffer
+	s.pageErr = pages.ExecuteTemplate(&page, s.uiTemplate(), data)
+	s.page = page.Bytes()
+}
+
+func (s *Server) handleUI(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" && r.URL.Path != "/index.html" {
+		http.NotFound(w, r)
+		return
+	}
+	if s.pageErr != nil {
+		http.Error(w, fmt.Sprintf("Failed to render template: %v", s.pageErr), http.StatusInternalServerError)
+		return
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	_ = tmpl.Execute(w, data)
+	_, _ = w.Write(s.page)
 }
 
 // EditorServer serves the Swagger Editor for editing OpenAPI specifications.
@@ -334,12 +605,12 @@ type EditorServer struct {
 	specURL     string
 	isRemoteURL bool
 	hasSpec     bool
-	port        int
+	listenConfig
 }
 
 // NewEditorServer creates a new Swagger Editor server.
 func NewEditorServer(port int) *EditorServer {
-	return &EditorServer{port: port}
+	return &EditorServer{listenConfig: newListenConfig(port)}
 }
 
 // SetSpecFromFile loads the OpenAPI specification from a file.
@@ -370,6 +641,12 @@ func (s *EditorServer) SetSpecFromData(data []byte) {
 
 // Serve starts the HTTP server and serves the Swagger Editor.
 func (s *EditorServer) Serve() error {
+	return s.ServeContext(context.Background())
+}
+
+// ServeContext serves the Swagger Editor until ctx is done, then shuts down
+// gracefully within the shutdown timeout.
+func (s *EditorServer) ServeContext(ctx context.Context) error {
 	mux := http.NewServeMux()
 
 	// Serve the spec (if provided)
@@ -380,11 +657,7 @@ func (s *EditorServer) Serve() error {
 	// Serve the Swagger Editor HTML
 	mux.HandleFunc("/", s.handleEditorUI)
 
-	addr := fmt.Sprintf(":%d", s.port)
-	fmt.Printf("Swagger Editor is available at http://localhost%s\n", addr)
-	fmt.Println("Press Ctrl+C to stop the server")
-
-	return http.ListenAndServe(addr, mux)
+	return s.listenAndServe(ctx, mux, "Swagger Editor")
 }
 
 func (s *EditorServer) handleSpec(w http.ResponseWriter, r *http.Request) {
@@ -408,13 +681,7 @@ func (s *EditorServer) handleSpec(w http.ResponseWriter, r *http.Request) {
 		specData = s.specData
 	}
 
-	// Determine content type
-	contentType := "application/json"
-	if len(specData) > 0 && (specData[0] == '-' || specData[0] == '#' || strings.HasPrefix(string(specData), "openapi:") || strings.HasPrefix(string(specData), "swagger:")) {
-		contentType = "application/yaml"
-	}
-
-	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Type", specContentType(specData))
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	_, _ = w.Write(specData)
 }
@@ -425,12 +692,6 @@ func (s *EditorServer) handleEditorUI(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tmpl, err := template.ParseFS(templates, "templates/editor.html")
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to load template: %v", err), http.StatusInternalServerError)
-		return
-	}
-
 	data := struct {
 		HasSpec bool
 	}{
@@ -438,5 +699,5 @@ func (s *EditorServer) handleEditorUI(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	_ = tmpl.Execute(w, data)
+	_ = pages.ExecuteTemplate(w, "editor.html", data)
 }
@@ -1,12 +1,18 @@
 package swaggerui
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/fathurrohman26/yaswag/pkg/uiassets"
 )
 
 func TestNewServer(t *testing.T) {
@@ -243,6 +249,48 @@ func TestServer_HandleUI_Root(t *testing.T) {
 	}
 }
 
+func TestServer_HandleUI_OfflineAssets(t *testing.T) {
+	server := NewServer(8080)
+	server.SetOfflineAssets(true)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	server.handleUI(w, req)
+
+	body := w.Body.String()
+	if !uiassets.Embedded() {
+		if !strings.Contains(body, uiassets.CDN().SwaggerUIBundle) {
+			t.Error("Offline UI should fall back to the CDN when the bundles are not embedded")
+		}
+		return
+	}
+	if !strings.Contains(body, `src="/assets/swagger-ui-bundle.js"`) {
+		t.Error("Offline UI should load the embedded Swagger UI bundle")
+	}
+	if strings.Contains(body, "https://cdn.") || strings.Contains(body, "fonts.googleapis.com") {
+		t.Error("Offline UI should not reference remote assets")
+	}
+}
+
+func TestServer_HandleUI_DocsUI(t *testing.T) {
+	for ui, want := range map[uiassets.UI]string{
+		uiassets.UIRedoc:   `<redoc spec-url="/spec">`,
+		uiassets.UIScalar:  `data-url="/spec"`,
+		uiassets.UIRapiDoc: `spec-url="/spec"`,
+	} {
+		server := NewServer(8080)
+		server.SetDocsUI(ui)
+
+		w := httptest.NewRecorder()
+		server.handleUI(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("%s UI should contain %q", ui, want)
+		}
+	}
+}
+
 func TestServer_HandleUI_NotFound(t *testing.T) {
 	server := NewServer(8080)
 
@@ -474,3 +522,296 @@ paths: {}`
 		}
 	})
 }
+
+func TestServer_HandleUI_FollowsSetters(t *testing.T) {
+	server := NewServer(8080)
+	server.SetSpecFromURL("https://example.com/openapi.json")
+
+	w := httptest.NewRecorder()
+	server.handleUI(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if body := w.Body.String(); !strings.Contains(body, "example.com") {
+		t.Error("UI page should use the spec URL set after construction")
+	}
+
+	server.SetSpecFromData([]byte(`{"openapi": "3.1.0"}`))
+	w = httptest.NewRecorder()
+	server.handleUI(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if body := w.Body.String(); strings.Contains(body, "example.com") {
+		t.Error("UI page should switch back to the local spec")
+	}
+}
+
+func BenchmarkServer_HandleUI(b *testing.B) {
+	server := NewServer(8080)
+	server.SetSpecFromData([]byte(`{"openapi": "3.1.0", "info": {"title": "Test", "version": "1.0.0"}}`))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		server.handleUI(httptest.NewRecorder(), req)
+	}
+}
+
+func BenchmarkServer_HandleSpec(b *testing.B) {
+	server := NewServer(8080)
+	server.SetSpecFromData([]byte("openapi: 3.2.0\ninfo:\n  title: Test\n  version: 1.0.0\npaths: {}\n"))
+	req := httptest.NewRequest(http.MethodGet, "/spec", nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		server.handleSpec(httptest.NewRecorder(), req)
+	}
+}
+
+func TestServer_HandleUI_Branding(t *testing.T) {
+	server := NewServer(8080)
+	server.SetBranding(&uiassets.Branding{
+		LogoURL:    "/static/logo.svg",
+		FooterText: "Acme Corp",
+		NavLinks:   []uiassets.NavLink{{Label: "Status", URL: "https://status.example.com"}},
+		Language:   "id",
+	})
+
+	w := httptest.NewRecorder()
+	server.handleUI(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	body := w.Body.String()
+	for _, want := range []string{`<html lang="id">`, `src="/static/logo.svg"`, `href="https://status.example.com"`, "Acme Corp"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("UI should contain %q", want)
+		}
+	}
+
+	server.SetBranding(&uiassets.Branding{PrimaryColor: "url(evil)"})
+	w = httptest.NewRecorder()
+	server.handleUI(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("invalid branding status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestServer_AddSpec(t *testing.T) {
+	server := NewServer(8080)
+	if err := server.AddSpec("v1", []byte(`{"openapi": "3.0.3", "info": {"title": "V1", "version": "1.0.0"}}`)); err != nil {
+		t.Fatalf("AddSpec(v1) error = %v", err)
+	}
+	if err := server.AddSpec("v2", []byte("openapi: 3.2.0\ninfo:\n  title: V2\n  version: 2.0.0\n")); err != nil {
+		t.Fatalf("AddSpec(v2) error = %v", err)
+	}
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{"/openapi/v1.json", http.StatusOK, `"title": "V1"`},
+		{"/openapi/v2.json", http.StatusOK, `"openapi": "3.1.0"`},
+		{"/openapi/v2.yaml", http.StatusNotFound, ""},
+		{"/openapi/v3.json", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		server.handleNamedSpec(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.wantCode {
+			t.Errorf("GET %s status = %d, want %d", tt.path, w.Code, tt.wantCode)
+		}
+		if !strings.Contains(w.Body.String(), tt.wantBody) {
+			t.Errorf("GET %s body = %s, want it to contain %s", tt.path, w.Body.String(), tt.wantBody)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	server.handleUI(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		`url: "\/openapi\/v1.json"`,
+		`<option value="/openapi/v1.json">v1</option>`,
+		`<option value="/openapi/v2.json">v2</option>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("UI should contain %s", want)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	server.handleValidate(w, httptest.NewRequest(http.MethodGet, "/validate?spec=v3", nil))
+	if w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), `no spec named \"v3\"`) {
+		t.Errorf("validating an unknown spec = %d %s, want an error", w.Code, w.Body.String())
+	}
+}
+
+func TestServer_AddSpec_Errors(t *testing.T) {
+	server := NewServer(8080)
+	if err := server.AddSpec("v1", []byte(`{"openapi": "3.0.3"}`)); err != nil {
+		t.Fatalf("AddSpec(v1) error = %v", err)
+	}
+
+	for name, data := range map[string]string{
+		"v1":       `{"openapi": "3.0.3"}`,
+		"v 2":      `{"openapi": "3.0.3"}`,
+		"":         `{"openapi": "3.0.3"}`,
+		"v3":       "openapi: [3.0.3",
+		"../admin": `{"openapi": "3.0.3"}`,
+	} {
+		if err := server.AddSpec(name, []byte(data)); err == nil {
+			t.Errorf("AddSpec(%q) error = nil, want an error", name)
+		}
+	}
+	if err := server.AddSpecFromFile("v4", "testdata/missing.yaml"); err == nil {
+		t.Error("AddSpecFromFile() error = nil for a missing file")
+	}
+}
+
+func TestServer_LiveReload(t *testing.T) {
+	server := NewServer(8080)
+	server.SetSpecFromData([]byte(`{"openapi": "3.0.3", "info": {"version": "1.0.0"}}`))
+	server.SetLiveReload(true)
+
+	w := httptest.NewRecorder()
+	server.handleUI(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.Contains(w.Body.String(), `new EventSource("/events")`) {
+		t.Error("UI should subscribe to reload events")
+	}
+
+	events := httptest.NewServer(http.HandlerFunc(server.handleEvents))
+	defer events.Close()
+	resp, err := http.Get(events.URL)
+	if err != nil {
+		t.Fatalf("GET /events error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+	stream := bufio.NewReader(resp.Body)
+	if line, _ := stream.ReadString('\n'); line != ": connected\n" {
+		t.Fatalf("first line = %q, want the connected comment", line)
+	}
+
+	server.SetSpecFromData([]byte(`{"openapi": "3.0.3", "info": {"version": "1.0.1"}}`))
+	for {
+		line, err := stream.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading events: %v", err)
+		}
+		if line == "event: reload\n" {
+			break
+		}
+	}
+
+	w = httptest.NewRecorder()
+	server.handleSpec(w, httptest.NewRequest(http.MethodGet, "/spec", nil))
+	if !strings.Contains(w.Body.String(), "1.0.1") {
+		t.Errorf("spec = %s, want the reloaded spec", w.Body.String())
+	}
+	if cc := w.Header().Get("Cache-Control"); cc != "no-cache" {
+		t.Errorf("Cache-Control = %q, want no-cache", cc)
+	}
+}
+
+func TestServer_ServeContext_GracefulShutdown(t *testing.T) {
+	server := NewServer(0)
+	server.SetSpecFromData([]byte(`{"openapi": "3.0.3"}`))
+	server.SetLiveReload(true)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.serve(ctx, ln, server.handler()) }()
+
+	// an open events stream does not hold up the shutdown
+	resp, err := http.Get("http://" + ln.Addr().String() + "/events")
+	if err != nil {
+		t.Fatalf("GET /events error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("ServeContext() error = %v, want nil after shutdown", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+	if _, err := http.Get("http://" + ln.Addr().String() + "/spec"); err == nil {
+		t.Error("server still accepts connections after shutdown")
+	}
+}
+
+func TestServer_ServeContext_FinishesInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		if r.Context().Err() != nil {
+			http.Error(w, "canceled", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("done"))
+	})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.serve(ctx, ln, handler) }()
+
+	type result struct {
+		body string
+		err  error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/slow")
+		if err != nil {
+			responses <- result{err: err}
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		responses <- result{string(body), err}
+	}()
+
+	<-started
+	cancel()
+	if r := <-responses; r.err != nil || r.body != "done" {
+		t.Errorf("in-flight request = %q, %v, want it to finish with its context intact", r.body, r.err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("ServeContext() error = %v, want nil after shutdown", err)
+	}
+}
+
+func TestServer_ServeContext_Errors(t *testing.T) {
+	server := NewServer(0)
+	server.SetHost("127.0.0.1")
+	server.SetTLS("cert.pem", "")
+	if err := server.ServeContext(context.Background()); err == nil || !strings.Contains(err.Error(), "TLS") {
+		t.Errorf("ServeContext() error = %v, want a TLS configuration error", err)
+	}
+
+	editor := NewEditorServer(0)
+	editor.SetHost("not a host")
+	if err := editor.ServeContext(context.Background()); err == nil {
+		t.Error("ServeContext() error = nil for an invalid host")
+	}
+}
+
+func TestDefaultTimeouts(t *testing.T) {
+	server := NewServer(8080)
+	if server.timeouts != DefaultTimeouts() {
+		t.Errorf("timeouts = %+v, want the defaults", server.timeouts)
+	}
+	if DefaultTimeouts().Write != 0 {
+		t.Error("a write timeout would close the live reload events stream")
+	}
+	server.SetTimeouts(Timeouts{Shutdown: time.Second})
+	if server.timeouts.ReadHeader != 0 || server.timeouts.Shutdown != time.Second {
+		t.Errorf("SetTimeouts() did not replace the timeouts: %+v", server.timeouts)
+	}
+}
@@ -0,0 +1,119 @@
+package uiassets
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Branding customizes documentation pages with an organization's look: a
+// navigation bar with a logo and links above the renderer, a footer below it,
+// the favicon, the primary color, and the page language. Values are escaped
+// by the page templates; URLs with unsafe schemes such as javascript: are
+// replaced by html/template.
+type Branding struct {
+	// LogoURL is the image at the start of the navigation bar
+	LogoURL string
+
+	// FaviconURL is the page icon
+	FaviconURL string
+
+	// PrimaryColor is a CSS color for the navigation bar and the renderer's
+	// accents: a hex color such as #32329f, rgb()/rgba(), or a color name
+	PrimaryColor string
+
+	// FooterText is shown below the documentation
+	FooterText string
+
+	// NavLinks are shown in the navigation bar
+	NavLinks []NavLink
+
+	// Language is the lang attribute of the pages, e.g. "id" for pages whose
+	// navigation and footer text are in Indonesian (default: en)
+	Language string
+}
+
+// NavLink is a link in the navigation bar.
+type NavLink struct {
+	Label string
+	URL   string
+}
+
+// cssColor matches the PrimaryColor values Validate accepts.
+var cssColor = regexp.MustCompile(`^(#[0-9a-fA-F]{3,8}|[a-zA-Z]+|rgba?\([0-9.,%\s]+\))$`)
+
+// Validate reports whether the primary color is a CSS color and every
+// navigation link has a label and URL.
+func (b *Branding) Validate() error {
+	if b == nil {
+		return nil
+	}
+	if b.PrimaryColor != "" && !cssColor.MatchString(b.PrimaryColor) {
+		return fmt.Errorf("invalid primary color %q: must be a hex color, rgb(), or a color name", b.PrimaryColor)
+	}
+	for _, link := range b.NavLinks {
+		if link.Label == "" || link.URL == "" {
+			return fmt.Errorf("invalid navigation link %q: needs a label and a URL", link.Label+"="+link.URL)
+		}
+	}
+	return nil
+}
+
+// ParseNavLink parses a navigation link given as label=url.
+func ParseNavLink(s string) (NavLink, error) {
+	label, url, ok := strings.Cut(s, "=")
+	link := NavLink{Label: strings.TrimSpace(label), URL: strings.TrimSpace(url)}
+	if !ok || link.Label == "" || link.URL == "" {
+		return NavLink{}, fmt.Errorf("invalid navigation link %q: must be label=url", s)
+	}
+	return link, nil
+}
+
+// Lang returns the lang attribute of the pages.
+func (b *Branding) Lang() string {
+	if b == nil || b.Language == "" {
+		return "en"
+	}
+	return b.Language
+}
+
+// HasNavbar reports whether the pages show a navigation bar.
+func (b *Branding) HasNavbar() bool {
+	return b != nil && (b.LogoURL != "" || len(b.NavLinks) > 0)
+}
+
+// BrandingTemplates defines the templates that render a *Branding, or
+// nothing for nil, in documentation pages: branding-head in the head, and
+// branding-navbar and branding-footer at the start and end of the body.
+const BrandingTemplates = `
+{{- define "branding-head"}}{{with .}}
+    {{- if .FaviconURL}}
+    <link rel="icon" href="{{.FaviconURL}}">
+    {{- end}}
+    <style>
+        {{- if .PrimaryColor}}
+        :root { --primary: {{.PrimaryColor}}; --scalar-color-accent: {{.PrimaryColor}}; }
+        .swagger-ui .btn.execute { background-color: {{.PrimaryColor}}; border-color: {{.PrimaryColor}}; }
+        {{- end}}
+        .yaswag-navbar { display: flex; align-items: center; gap: 20px; padding: 10px 20px; background: {{or .PrimaryColor "#1b1b1b"}}; font-family: sans-serif; }
+        .yaswag-navbar img { height: 32px; }
+        .yaswag-navbar a { color: #fff; text-decoration: none; }
+        .yaswag-footer { padding: 16px 20px; text-align: center; color: #64748b; font-family: sans-serif; font-size: 14px; }
+    </style>
+{{- end}}{{end}}
+
+{{- define "branding-navbar"}}{{if .HasNavbar}}
+    <nav class="yaswag-navbar">
+        {{- if .LogoURL}}
+        <img src="{{.LogoURL}}" alt="Logo">
+        {{- end}}
+        {{- range .NavLinks}}
+        <a href="{{.URL}}">{{.Label}}</a>
+        {{- end}}
+    </nav>
+{{- end}}{{end}}
+
+{{- define "branding-footer"}}{{with .}}{{if .FooterText}}
+    <footer class="yaswag-footer">{{.FooterText}}</footer>
+{{- end}}{{end}}{{end}}
+`
@@ -0,0 +1,129 @@
+// Package uiassets embeds the Swagger UI, ReDoc, Scalar, and RapiDoc bundles
+// so documentation pages can be served without outbound network access.
+//
+// The bundles are fetched into the dist directory by 'make ui-assets' and
+// compiled into the binary via go:embed. Pages use CDN URLs unless offline
+// assets are requested and the bundles were embedded.
+package uiassets
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+// UI identifies a documentation renderer.
+type UI string
+
+// Supported documentation renderers.
+const (
+	UISwagger UI = "swagger"
+	UIRedoc   UI = "redoc"
+	UIScalar  UI = "scalar"
+	UIRapiDoc UI = "rapidoc"
+)
+
+// ParseUI parses a renderer name, case-insensitively.
+func ParseUI(name string) (UI, error) {
+	switch ui := UI(strings.ToLower(strings.TrimSpace(name))); ui {
+	case UISwagger, UIRedoc, UIScalar, UIRapiDoc:
+		return ui, nil
+	default:
+		return "", fmt.Errorf("unknown docs UI %q: must be swagger, redoc, scalar, or rapidoc", name)
+	}
+}
+
+// Versions of the bundles fetched by 'make ui-assets'.
+const (
+	SwaggerUIVersion = "5.17.14"
+	RedocVersion     = "2.1.5"
+	ScalarVersion    = "1.25.11"
+	RapiDocVersion   = "9.3.4"
+)
+
+// Embedded asset file names.
+const (
+	SwaggerUICSSFile    = "swagger-ui.css"
+	SwaggerUIBundleFile = "swagger-ui-bundle.js"
+	RedocBundleFile     = "redoc.standalone.js"
+	ScalarBundleFile    = "scalar.standalone.js"
+	RapiDocBundleFile   = "rapidoc-min.js"
+)
+
+//go:embed dist
+var dist embed.FS
+
+// URLs locates the bundles referenced by documentation pages.
+type URLs struct {
+	SwaggerUICSS    string
+	SwaggerUIBundle string
+	RedocBundle     string
+	ScalarBundle    string
+	RapiDocBundle   string
+
+	// Offline reports that the URLs point at embedded assets; pages should
+	// not reference any other remote resource, such as web fonts.
+	Offline bool
+}
+
+// CDN returns the public CDN locations of the bundles. ReDoc is pinned to
+// RedocVersion, because its latest release can change rendering unexpectedly.
+func CDN() URLs {
+	return URLs{
+		SwaggerUICSS:    "https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui.css",
+		SwaggerUIBundle: "https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui-bundle.js",
+		RedocBundle:     "https://cdn.redoc.ly/redoc/v" + RedocVersion + "/bundles/redoc.standalone.js",
+		ScalarBundle:    "https://cdn.jsdelivr.net/npm/@scalar/api-reference",
+		RapiDocBundle:   "https://cdn.jsdelivr.net/npm/rapidoc/dist/rapidoc-min.js",
+	}
+}
+
+// Local returns the locations of the embedded bundles when Handler is
+// mounted at prefix.
+func Local(prefix string) URLs {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return URLs{
+		SwaggerUICSS:    prefix + "/" + SwaggerUICSSFile,
+		SwaggerUIBundle: prefix + "/" + SwaggerUIBundleFile,
+		RedocBundle:     prefix + "/" + RedocBundleFile,
+		ScalarBundle:    prefix + "/" + ScalarBundleFile,
+		RapiDocBundle:   prefix + "/" + RapiDocBundleFile,
+		Offline:         true,
+	}
+}
+
+// LocalOrCDN returns Local(prefix) when the bundles are embedded, and CDN()
+// otherwise, so pages never reference bundles the binary does not serve.
+func LocalOrCDN(prefix string) URLs {
+	if Embedded() {
+		return Local(prefix)
+	}
+	return CDN()
+}
+
+// Embedded reports whether all bundles are compiled into the binary.
+func Embedded() bool {
+	for _, name := range []string{SwaggerUICSSFile, SwaggerUIBundleFile, RedocBundleFile, ScalarBundleFile, RapiDocBundleFile} {
+		if _, err := fs.Stat(dist, "dist/"+name); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Handler returns an http.Handler serving the embedded bundles by file name.
+// Mount it with http.StripPrefix so request paths are relative to the bundle directory.
+func Handler() http.Handler {
+	sub, err := fs.Sub(dist, "dist")
+	if err != nil {
+		// dist is embedded at compile time, so this cannot fail
+		panic(err)
+	}
+	files := http.FileServerFS(sub)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+		files.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,94 @@
+package uiassets
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLocal(t *testing.T) {
+	urls := Local("/docs/assets/")
+	if urls.SwaggerUIBundle != "/docs/assets/swagger-ui-bundle.js" {
+		t.Errorf("SwaggerUIBundle = %q", urls.SwaggerUIBundle)
+	}
+	if urls.RedocBundle != "/docs/assets/redoc.standalone.js" {
+		t.Errorf("RedocBundle = %q", urls.RedocBundle)
+	}
+	if !urls.Offline {
+		t.Error("Local URLs should be offline")
+	}
+	if cdn := CDN(); cdn.Offline || !strings.HasPrefix(cdn.SwaggerUICSS, "https://") {
+		t.Errorf("CDN() = %+v, want remote URLs", cdn)
+	}
+	if redoc := CDN().RedocBundle; !strings.Contains(redoc, "/v"+RedocVersion+"/") {
+		t.Errorf("CDN().RedocBundle = %q, want pinned to %s", redoc, RedocVersion)
+	}
+}
+
+func TestLocalOrCDN(t *testing.T) {
+	want := CDN()
+	if Embedded() {
+		want = Local("/assets")
+	}
+	if got := LocalOrCDN("/assets"); got != want {
+		t.Errorf("LocalOrCDN() = %+v, want %+v (embedded: %v)", got, want, Embedded())
+	}
+}
+
+func TestParseUI(t *testing.T) {
+	for input, want := range map[string]UI{"swagger": UISwagger, "ReDoc": UIRedoc, " scalar ": UIScalar, "rapidoc": UIRapiDoc} {
+		got, err := ParseUI(input)
+		if err != nil || got != want {
+			t.Errorf("ParseUI(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseUI("elements"); err == nil {
+		t.Error("ParseUI(elements) should return an error")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/"+SwaggerUIBundleFile, nil))
+
+	want := http.StatusNotFound
+	if Embedded() {
+		want = http.StatusOK
+	}
+	if w.Code != want {
+		t.Errorf("Status = %d, want %d (embedded: %v)", w.Code, want, Embedded())
+	}
+}
+
+func TestBranding_Validate(t *testing.T) {
+	var nilBranding *Branding
+	for _, b := range []*Branding{nilBranding, {PrimaryColor: "#32329f"}, {PrimaryColor: "rgb(50, 50, 159)"}, {PrimaryColor: "teal"}} {
+		if err := b.Validate(); err != nil {
+			t.Errorf("Validate(%+v) error = %v", b, err)
+		}
+	}
+	for _, b := range []*Branding{
+		{PrimaryColor: "red;} body{display:none"},
+		{NavLinks: []NavLink{{Label: "Status"}}},
+	} {
+		if err := b.Validate(); err == nil {
+			t.Errorf("Validate(%+v) should return an error", b)
+		}
+	}
+	if nilBranding.Lang() != "en" || (&Branding{Language: "id"}).Lang() != "id" {
+		t.Error("Lang() should default to en")
+	}
+}
+
+func TestParseNavLink(t *testing.T) {
+	link, err := ParseNavLink(" Status = https://status.example.com ")
+	if err != nil || link != (NavLink{Label: "Status", URL: "https://status.example.com"}) {
+		t.Errorf("ParseNavLink() = %+v, %v", link, err)
+	}
+	for _, input := range []string{"Status", "=https://status.example.com", "Status="} {
+		if _, err := ParseNavLink(input); err == nil {
+			t.Errorf("ParseNavLink(%q) should return an error", input)
+		}
+	}
+}
@@ -0,0 +1,451 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// Direction tells readOnly and writeOnly properties apart: readOnly ones must
+// not be sent in requests and writeOnly ones must not be returned in responses.
+type Direction int
+
+const (
+	DirectionAny      Direction = iota // readOnly and writeOnly properties may both appear
+	DirectionRequest                   // the value is sent in a request
+	DirectionResponse                  // the value is returned in a response
+)
+
+// maxSchemaDepth stops runaway recursion through self-referencing schemas.
+const maxSchemaDepth = 64
+
+// SchemaError is a place where a value does not match its schema.
+type SchemaError struct {
+	Pointer string // JSON pointer into the value (RFC 6901)
+	Message string
+}
+
+func (e SchemaError) Error() string {
+	return e.Pointer + ": " + e.Message
+}
+
+// SchemaErrors is every place where a value does not match its schema.
+type SchemaErrors []SchemaError
+
+func (e SchemaErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%d schema errors, first: %s", len(e), e[0].Error())
+}
+
+// ValidateValue validates the JSON payload data against the components
+// schema name of doc, returning SchemaErrors if it does not match:
+//
+//	err := validator.ValidateValue(spec, "Pet", []byte(`{"name": 42}`))
+//	// /name: expected string, got number
+//
+// Schema composition, formats, and both the OpenAPI 3.0 (nullable) and 3.1
+// (type: null) forms of nullable are supported.
+func ValidateValue(doc *openapi.Document, name string, data []byte) error {
+	if doc.Components == nil || doc.Components.Schemas[name] == nil {
+		return fmt.Errorf("schema %q is not defined", name)
+	}
+	return ValidateSchema(doc, doc.Components.Schemas[name], data, DirectionAny)
+}
+
+// ValidateSchema validates the JSON payload data against schema, resolving
+// references to the component schemas of doc, and returns SchemaErrors if it
+// does not match.
+func ValidateSchema(doc *openapi.Document, schema *openapi.Schema, data []byte, dir Direction) error {
+	value, err := decodeJSON(data)
+	if err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	v := &schemaValidator{doc: doc, dir: dir}
+	v.validate(schema, value, "")
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+// schemaValidator checks decoded JSON values against schemas, collecting every error.
+type schemaValidator struct {
+	doc   *openapi.Document
+	dir   Direction
+	depth int
+	errs  SchemaErrors
+}
+
+func decodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("unexpected data after top-level value")
+	}
+	return value, nil
+}
+
+func (v *schemaValidator) fail(pointer, format string, args ...any) {
+	if pointer == "" {
+		pointer = "/"
+	}
+	v.errs = append(v.errs, SchemaError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// resolve follows a $ref to a component schema.
+func (v *schemaValidator) resolve(schema *openapi.Schema) *openapi.Schema {
+	for i := 0; schema != nil && schema.Ref != "" && i < maxSchemaDepth; i++ {
+		name, ok := strings.CutPrefix(schema.Ref, "#/components/schemas/")
+		if !ok || v.doc.Components == nil {
+			return nil
+		}
+		schema = v.doc.Components.Schemas[name]
+	}
+	return schema
+}
+
+func (v *schemaValidator) validate(schema *openapi.Schema, value any, pointer string) {
+	if value == nil && schema != nil && schema.Nullable {
+		return // a nullable $ref, which the referenced schema need not be
+	}
+	schema = v.resolve(schema)
+	if schema == nil || v.depth > maxSchemaDepth {
+		return
+	}
+	v.depth++
+	defer func() { v.depth-- }()
+
+	if !v.validateType(schema, value, pointer) {
+		return
+	}
+	v.validateKeywords(schema, value, pointer)
+	v.validateComposition(schema, value, pointer)
+}
+
+// validateKeywords checks the keywords of schema that apply to the type of value.
+func (v *schemaValidator) validateKeywords(schema *openapi.Schema, value any, pointer string) {
+	switch value := value.(type) {
+	case string:
+		v.validateString(schema, value, pointer)
+	case json.Number:
+		v.validateNumber(schema, value, pointer)
+	case []any:
+		v.validateArray(schema, value, pointer)
+	case map[string]any:
+		v.validateObject(schema, value, pointer)
+	}
+}
+
+// validateType checks the type and enum of value, reporting false if the type is wrong.
+func (v *schemaValidator) validateType(schema *openapi.Schema, value any, pointer string) bool {
+	if value == nil && (schema.Nullable || slices.Contains(schema.Type, openapi.TypeNull)) {
+		return false
+	}
+	if len(schema.Type) > 0 && !slices.ContainsFunc(schema.Type, func(t string) bool { return hasType(value, t) }) {
+		v.fail(pointer, "expected %s, got %s", strings.Join(schema.Type, " or "), jsonType(value))
+		return false
+	}
+	if len(schema.Enum) > 0 && !slices.ContainsFunc(schema.Enum, func(e any) bool { return equalJSON(e, value) }) {
+		v.fail(pointer, "value %s is not one of %s", formatJSON(value), formatJSON(schema.Enum))
+	}
+	return true
+}
+
+func (v *schemaValidator) validateString(schema *openapi.Schema, value, pointer string) {
+	length := int64(utf8.RuneCountInString(value))
+	if schema.MinLength != nil && length < *schema.MinLength {
+		v.fail(pointer, "string is shorter than minLength %d", *schema.MinLength)
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		v.fail(pointer, "string is longer than maxLength %d", *schema.MaxLength)
+	}
+	if schema.Pattern != "" {
+		re, err := compilePattern(schema.Pattern)
+		switch {
+		case err != nil:
+			v.fail(pointer, "pattern %s is invalid: %v", schema.Pattern, err)
+		case !re.MatchString(value):
+			v.fail(pointer, "string %q does not match pattern %s", value, schema.Pattern)
+		}
+	}
+	if check, ok := stringFormats[schema.Format]; ok && !check(value) {
+		v.fail(pointer, "string %q is not a valid %s", value, schema.Format)
+	}
+}
+
+// compiledPatterns caches the compiled pattern keywords of schemas, along
+// with the errors of invalid ones, so that each is compiled once.
+var compiledPatterns sync.Map // pattern -> compiledPattern
+
+type compiledPattern struct {
+	re  *regexp.Regexp
+	err error
+}
+
+// compilePattern compiles the pattern keyword of a schema, or returns it from
+// the cache.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if c, ok := compiledPatterns.Load(pattern); ok {
+		return c.(compiledPattern).re, c.(compiledPattern).err
+	}
+	re, err := regexp.Compile(pattern)
+	compiledPatterns.Store(pattern, compiledPattern{re, err})
+	return re, err
+}
+
+func (v *schemaValidator) validateNumber(schema *openapi.Schema, value json.Number, pointer string) {
+	n, err := value.Float64()
+	if err != nil {
+		return
+	}
+	v.validateBounds(schema, n, value, pointer)
+	v.validateMultipleOf(schema, n, value, pointer)
+	if bits, ok := integerFormats[schema.Format]; ok && hasType(value, openapi.TypeInteger) {
+		if limit := math.Ldexp(1, bits-1); n < -limit || n >= limit {
+			v.fail(pointer, "%s does not fit in %s", value, schema.Format)
+		}
+	}
+}
+
+// validateBounds checks n against the minimum and maximum of schema, which
+// may be exclusive.
+func (v *schemaValidator) validateBounds(schema *openapi.Schema, n float64, value json.Number, pointer string) {
+	if minimum, exclusive := schema.LowerBound(); minimum != nil && (n < *minimum || exclusive && n == *minimum) {
+		v.fail(pointer, "%s is less than the %s %v", value, boundName("minimum", exclusive), *minimum)
+	}
+	if maximum, exclusive := schema.UpperBound(); maximum != nil && (n > *maximum || exclusive && n == *maximum) {
+		v.fail(pointer, "%s is greater than the %s %v", value, boundName("maximum", exclusive), *maximum)
+	}
+}
+
+func (v *schemaValidator) validateMultipleOf(schema *openapi.Schema, n float64, value json.Number, pointer string) {
+	if m := schema.MultipleOf; m != nil && *m != 0 {
+		if q := n / *m; math.Abs(q-math.Round(q)) > 1e-9 {
+			v.fail(pointer, "%s is not a multiple of %v", value, *m)
+		}
+	}
+}
+
+func boundName(name string, exclusive bool) string {
+	if exclusive {
+		return "exclusive " + name
+	}
+	return name
+}
+
+func (v *schemaValidator) validateArray(schema *openapi.Schema, value []any, pointer string) {
+	count := int64(len(value))
+	if schema.MinItems != nil && count < *schema.MinItems {
+		v.fail(pointer, "array has %d items, fewer than minItems %d", count, *schema.MinItems)
+	}
+	if schema.MaxItems != nil && count > *schema.MaxItems {
+		v.fail(pointer, "array has %d items, more than maxItems %d", count, *schema.MaxItems)
+	}
+	for i, item := range value {
+		if schema.UniqueItems && slices.ContainsFunc(value[:i], func(prev any) bool { return equalJSON(prev, item) }) {
+			v.fail(pointer+"/"+strconv.Itoa(i), "duplicate item in array with uniqueItems")
+		}
+		if schema.Items != nil {
+			v.validate(schema.Items, item, pointer+"/"+strconv.Itoa(i))
+		}
+	}
+}
+
+func (v *schemaValidator) validateObject(schema *openapi.Schema, value map[string]any, pointer string) {
+	for _, name := range schema.Required {
+		if _, ok := value[name]; !ok && v.expects(schema.Properties[name]) {
+			v.fail(pointer, "missing required property %q", name)
+		}
+	}
+	count := int64(len(value))
+	if schema.MinProperties != nil && count < *schema.MinProperties {
+		v.fail(pointer, "object has %d properties, fewer than minProperties %d", count, *schema.MinProperties)
+	}
+	if schema.MaxProperties != nil && count > *schema.MaxProperties {
+		v.fail(pointer, "object has %d properties, more than maxProperties %d", count, *schema.MaxProperties)
+	}
+	for _, name := range slices.Sorted(maps.Keys(value)) {
+		v.validateProperty(schema, name, value[name], pointer+"/"+escapePointer(name))
+	}
+}
+
+func (v *schemaValidator) validateProperty(schema *openapi.Schema, name string, value any, pointer string) {
+	prop, declared := schema.Properties[name]
+	switch {
+	case declared && !v.expects(prop):
+		v.fail(pointer, "property is %s and must not appear in a %s", accessMode(v.dir), directionName(v.dir))
+	case declared:
+		v.validate(prop, value, pointer)
+	case schema.AdditionalProperties != nil:
+		v.validate(schema.AdditionalProperties, value, pointer)
+	}
+}
+
+// expects reports whether a property may appear in the current direction.
+func (v *schemaValidator) expects(prop *openapi.Schema) bool {
+	prop = v.resolve(prop)
+	if prop == nil {
+		return true
+	}
+	switch v.dir {
+	case DirectionRequest:
+		return !prop.ReadOnly
+	case DirectionResponse:
+		return !prop.WriteOnly
+	}
+	return true
+}
+
+func accessMode(dir Direction) string {
+	if dir == DirectionRequest {
+		return "readOnly"
+	}
+	return "writeOnly"
+}
+
+func directionName(dir Direction) string {
+	if dir == DirectionRequest {
+		return "request"
+	}
+	return "response"
+}
+
+func (v *schemaValidator) validateComposition(schema *openapi.Schema, value any, pointer string) {
+	for _, sub := range schema.AllOf {
+		v.validate(sub, value, pointer)
+	}
+	if len(schema.AnyOf) > 0 && v.countMatches(schema.AnyOf, value, pointer) == 0 {
+		v.fail(pointer, "value matches none of the anyOf schemas")
+	}
+	if len(schema.OneOf) > 0 {
+		if n := v.countMatches(schema.OneOf, value, pointer); n != 1 {
+			v.fail(pointer, "value matches %d of the oneOf schemas, want exactly 1", n)
+		}
+	}
+	if schema.Not != nil && v.countMatches([]*openapi.Schema{schema.Not}, value, pointer) == 1 {
+		v.fail(pointer, "value must not match the schema in not")
+	}
+}
+
+// countMatches returns how many of schemas value is valid against, without recording their errors.
+func (v *schemaValidator) countMatches(schemas []*openapi.Schema, value any, pointer string) int {
+	matches := 0
+	for _, sub := range schemas {
+		trial := &schemaValidator{doc: v.doc, dir: v.dir, depth: v.depth}
+		trial.validate(sub, value, pointer)
+		if len(trial.errs) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+func hasType(value any, schemaType string) bool {
+	switch schemaType {
+	case openapi.TypeInteger:
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		if _, err := n.Int64(); err == nil {
+			return true
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	case openapi.TypeNumber:
+		_, ok := value.(json.Number)
+		return ok
+	default:
+		return jsonType(value) == schemaType
+	}
+}
+
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return openapi.TypeNull
+	case bool:
+		return openapi.TypeBoolean
+	case json.Number:
+		return openapi.TypeNumber
+	case string:
+		return openapi.TypeString
+	case []any:
+		return openapi.TypeArray
+	default:
+		return openapi.TypeObject
+	}
+}
+
+// equalJSON compares values after a JSON round trip, so that enum values
+// declared as Go ints match decoded json.Numbers.
+func equalJSON(a, b any) bool {
+	return formatJSON(a) == formatJSON(b) || reflect.DeepEqual(a, b)
+}
+
+func formatJSON(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// integerFormats are the bit sizes of the integer formats.
+var integerFormats = map[string]int{"int32": 32, "int64": 64}
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnamePattern = regexp.MustCompile(`^(?i)[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+)
+
+// stringFormats check the string formats; other formats are not validated,
+// as JSON Schema allows.
+var stringFormats = map[string]func(string) bool{
+	"date-time": func(s string) bool { _, err := time.Parse(time.RFC3339, s); return err == nil },
+	"date":      func(s string) bool { _, err := time.Parse(time.DateOnly, s); return err == nil },
+	"email": func(s string) bool {
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	},
+	"uuid": uuidPattern.MatchString,
+	"uri": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && u.IsAbs()
+	},
+	"hostname": func(s string) bool { return len(s) <= 253 && hostnamePattern.MatchString(s) },
+	"ipv4": func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	},
+	"ipv6": func(s string) bool { return net.ParseIP(s) != nil && strings.Contains(s, ":") },
+	"byte": func(s string) bool { _, err := base64.StdEncoding.DecodeString(s); return err == nil },
+}
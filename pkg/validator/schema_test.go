@@ -0,0 +1,165 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+func schemaTestDocument() *openapi.Document {
+	str := func(format string) *openapi.Schema {
+		return &openapi.Schema{Type: openapi.NewSchemaType(openapi.TypeString), Format: format}
+	}
+	return &openapi.Document{
+		OpenAPI: "3.0.3",
+		Components: &openapi.Components{Schemas: map[string]*openapi.Schema{
+			"Pet": {
+				Type:     openapi.NewSchemaType(openapi.TypeObject),
+				Required: []string{"id", "name"},
+				Properties: map[string]*openapi.Schema{
+					"id":        {Type: openapi.NewSchemaType(openapi.TypeInteger), Format: "int32"},
+					"name":      openapi.StringSchema(),
+					"owner":     {Ref: "#/components/schemas/Owner", Nullable: true},
+					"tag":       {Type: openapi.SchemaType{openapi.TypeString, openapi.TypeNull}},
+					"bornOn":    str("date"),
+					"updatedAt": str("date-time"),
+					"chip":      str("uuid"),
+				},
+			},
+			"Owner": {
+				Type:       openapi.NewSchemaType(openapi.TypeObject),
+				Properties: map[string]*openapi.Schema{"email": str("email"), "site": str("uri"), "ip": str("ipv4")},
+			},
+			"Slug":   {Type: openapi.NewSchemaType(openapi.TypeString), Pattern: "^[a-z-]+$"},
+			"Broken": {Type: openapi.NewSchemaType(openapi.TypeString), Pattern: "[a-"},
+			"Animal": {OneOf: []*openapi.Schema{
+				{Ref: "#/components/schemas/Pet"},
+				{Type: openapi.NewSchemaType(openapi.TypeObject), Required: []string{"species"}},
+			}},
+		}},
+	}
+}
+
+func TestValidateValue(t *testing.T) {
+	doc := schemaTestDocument()
+
+	tests := []struct {
+		name   string
+		schema string
+		data   string
+		want   []string
+	}{
+		{
+			name:   "valid",
+			schema: "Pet",
+			data: `{"id": 1, "name": "Rex", "owner": null, "tag": null, "bornOn": "2020-02-29",
+				"updatedAt": "2024-01-02T15:04:05Z", "chip": "6ba7b810-9dad-11d1-80b4-00c04fd430c8"}`,
+		},
+		{
+			name:   "types",
+			schema: "Pet",
+			data:   `{"id": 2147483648, "name": null, "tag": 5}`,
+			want: []string{
+				"/id: 2147483648 does not fit in int32",
+				"/name: expected string, got null",
+				"/tag: expected string or null, got number",
+			},
+		},
+		{
+			name:   "formats",
+			schema: "Pet",
+			data:   `{"id": 1, "name": "Rex", "bornOn": "2020-02-30", "updatedAt": "yesterday", "chip": "chip-1"}`,
+			want: []string{
+				`/bornOn: string "2020-02-30" is not a valid date`,
+				`/chip: string "chip-1" is not a valid uuid`,
+				`/updatedAt: string "yesterday" is not a valid date-time`,
+			},
+		},
+		{
+			name:   "nested reference",
+			schema: "Owner",
+			data:   `{"email": "Rex <rex@example.com>", "site": "/home", "ip": "::1"}`,
+			want: []string{
+				`/email: string "Rex <rex@example.com>" is not a valid email`,
+				`/ip: string "::1" is not a valid ipv4`,
+				`/site: string "/home" is not a valid uri`,
+			},
+		},
+		{
+			name:   "pattern",
+			schema: "Slug",
+			data:   `"Pet Store"`,
+			want:   []string{`/: string "Pet Store" does not match pattern ^[a-z-]+$`},
+		},
+		{
+			name:   "invalid pattern",
+			schema: "Broken",
+			data:   `"a"`,
+			want:   []string{"/: pattern [a- is invalid: error parsing regexp: missing closing ]: `[a-`"},
+		},
+		{
+			name:   "composition",
+			schema: "Animal",
+			data:   `{"id": 1, "name": "Rex", "species": "dog"}`,
+			want:   []string{"/: value matches 2 of the oneOf schemas, want exactly 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateValue(doc, tt.schema, []byte(tt.data))
+			var got []string
+			var schemaErrs SchemaErrors
+			if errors.As(err, &schemaErrs) {
+				for _, e := range schemaErrs {
+					got = append(got, e.Error())
+				}
+			} else if err != nil {
+				t.Fatalf("ValidateValue() error = %v", err)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("errors = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateValue_Errors(t *testing.T) {
+	doc := schemaTestDocument()
+
+	if err := ValidateValue(doc, "Missing", []byte(`{}`)); err == nil || !strings.Contains(err.Error(), `schema "Missing" is not defined`) {
+		t.Errorf("unknown schema: error = %v", err)
+	}
+	if err := ValidateValue(doc, "Pet", []byte(`{"id": 1} {}`)); err == nil || !strings.HasPrefix(err.Error(), "invalid JSON") {
+		t.Errorf("trailing data: error = %v", err)
+	}
+}
+
+func TestValidateSchema_Direction(t *testing.T) {
+	doc := &openapi.Document{}
+	schema := &openapi.Schema{Properties: map[string]*openapi.Schema{
+		"id":       {ReadOnly: true},
+		"password": {WriteOnly: true},
+	}}
+	data := []byte(`{"id": 1, "password": "secret"}`)
+
+	for dir, want := range map[Direction]string{
+		DirectionAny:      "",
+		DirectionRequest:  "/id: property is readOnly and must not appear in a request",
+		DirectionResponse: "/password: property is writeOnly and must not appear in a response",
+	} {
+		err := ValidateSchema(doc, schema, data, dir)
+		if got := errorString(err); got != want {
+			t.Errorf("direction %d: error = %q, want %q", dir, got, want)
+		}
+	}
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
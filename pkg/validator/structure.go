@@ -0,0 +1,458 @@
+package validator
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// httpMethods are the fields of a path item holding operations.
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace", "query"}
+
+// parameterLocations are the valid values of a parameter's in field.
+var parameterLocations = []string{"query", "header", "path", "cookie"}
+
+var (
+	// statusCodePattern matches the keys of a responses object.
+	statusCodePattern = regexp.MustCompile(`^(default|[1-5](\d\d|XX))$`)
+
+	// componentNamePattern matches the names of components.
+	componentNamePattern = regexp.MustCompile(`^[a-zA-Z0-9.\-_]+$`)
+
+	// pathTemplatePattern matches the parameters of a path template.
+	pathTemplatePattern = regexp.MustCompile(`\{([^}/]+)\}`)
+
+	// identifierPattern matches keys written in dot notation in a JSON path.
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// structureChecker checks a document against the structural rules of the
+// OpenAPI specification that building the libopenapi model does not
+// enforce, reporting each problem with its JSON path and position.
+type structureChecker struct {
+	result  *ValidationResult
+	root    *yaml.Node
+	is30    bool
+	opPaths map[string]string // operationId -> JSON path of its first use
+}
+
+// validateStructure checks the structure of the OpenAPI 3.x document data.
+func (v *Validator) validateStructure(result *ValidationResult, data []byte) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil || len(node.Content) == 0 {
+		return
+	}
+	c := &structureChecker{
+		result:  result,
+		root:    node.Content[0],
+		is30:    strings.HasPrefix(result.Version, "3.0"),
+		opPaths: make(map[string]string),
+	}
+	c.checkDocument()
+}
+
+func (c *structureChecker) checkDocument() {
+	root := deref(c.root)
+	if root.Kind != yaml.MappingNode {
+		c.addError(root, "$", "OpenAPI document must be an object")
+		return
+	}
+	c.requireFields(root, "$", "openapi", "info")
+	c.checkInfo(field(root, "info"), "$.info")
+	c.checkServers(field(root, "servers"), "$.servers")
+	c.checkTags(field(root, "tags"), "$.tags")
+
+	paths := field(root, "paths")
+	switch {
+	case paths != nil:
+		c.checkPaths(paths, "$.paths")
+	case c.is30:
+		c.addWarning(root, "$", `Missing required field "paths"`)
+	case field(root, "components") == nil && field(root, "webhooks") == nil:
+		c.addError(root, "$", `Document must have at least one of "paths", "components", or "webhooks"`)
+	}
+
+	c.checkComponents(field(root, "components"), "$.components")
+	c.checkRefs(c.root, "$")
+}
+
+func (c *structureChecker) checkInfo(info *yaml.Node, path string) {
+	if info == nil {
+		return
+	}
+	c.requireFields(info, path, "title", "version")
+	if license := field(info, "license"); license != nil {
+		c.requireFields(license, path+".license", "name")
+	}
+}
+
+func (c *structureChecker) checkServers(servers *yaml.Node, path string) {
+	for i, server := range items(servers) {
+		c.requireFields(server, index(path, i), "url")
+	}
+}
+
+func (c *structureChecker) checkTags(tags *yaml.Node, path string) {
+	seen := make(map[string]bool)
+	for i, tag := range items(tags) {
+		tagPath := index(path, i)
+		c.requireFields(tag, tagPath, "name")
+		name := scalar(field(tag, "name"))
+		if name == "" {
+			continue
+		}
+		if seen[name] {
+			c.addError(tag, tagPath, fmt.Sprintf("Duplicate tag %q", name))
+		}
+		seen[name] = true
+	}
+}
+
+func (c *structureChecker) checkPaths(paths *yaml.Node, path string) {
+	if paths.Kind != yaml.MappingNode {
+		c.addError(paths, path, "Paths must be an object")
+		return
+	}
+	seen := make(map[string]string) // path template with parameters elided -> path
+	for key, item := range pairs(paths) {
+		name := key.Value
+		if strings.HasPrefix(name, "x-") {
+			continue
+		}
+		itemPath := member(path, name)
+		if !strings.HasPrefix(name, "/") {
+			c.addError(key, itemPath, fmt.Sprintf("Path %q must begin with /", name))
+		}
+		template := pathTemplatePattern.ReplaceAllString(name, "{}")
+		switch first, ok := seen[template]; {
+		case ok && first == name:
+			c.addError(key, itemPath, fmt.Sprintf("Duplicate path %q", name))
+		case ok:
+			c.addError(key, itemPath, fmt.Sprintf("Path %q is equivalent to %q", name, first))
+		default:
+			seen[template] = name
+		}
+		c.checkPathItem(name, item, itemPath)
+	}
+}
+
+func (c *structureChecker) checkPathItem(name string, item *yaml.Node, path string) {
+	if item.Kind != yaml.MappingNode || field(item, "$ref") != nil {
+		return
+	}
+	shared := c.checkParameters(field(item, "parameters"), path+".parameters")
+	c.checkServers(field(item, "servers"), path+".servers")
+	for _, method := range httpMethods {
+		if op := field(item, method); op != nil {
+			c.checkOperation(name, op, member(path, method), shared)
+		}
+	}
+}
+
+func (c *structureChecker) checkOperation(name string, op *yaml.Node, path string, shared map[paramKey]*yaml.Node) {
+	if op.Kind != yaml.MappingNode {
+		c.addError(op, path, "Operation must be an object")
+		return
+	}
+	if id := scalar(field(op, "operationId")); id != "" {
+		if first, ok := c.opPaths[id]; ok {
+			c.addError(field(op, "operationId"), path+".operationId",
+				fmt.Sprintf("Duplicate operationId %q (first used at %s)", id, first))
+		} else {
+			c.opPaths[id] = path
+		}
+	}
+
+	params := c.checkParameters(field(op, "parameters"), path+".parameters")
+	for key, param := range shared {
+		if _, ok := params[key]; !ok {
+			params[key] = param
+		}
+	}
+	c.checkPathTemplate(name, op, path, params)
+
+	if body := field(op, "requestBody"); body != nil && field(body, "$ref") == nil {
+		c.requireFields(body, path+".requestBody", "content")
+	}
+	c.checkServers(field(op, "servers"), path+".servers")
+
+	responses := field(op, "responses")
+	if responses == nil {
+		if c.is30 {
+			c.addError(op, path, `Missing required field "responses"`)
+		}
+		return
+	}
+	c.checkResponses(responses, path+".responses")
+}
+
+// paramKey identifies a parameter by its name and location.
+type paramKey struct{ name, in string }
+
+// checkParameters checks a list of parameters and returns them by name and
+// location, resolving references to component parameters.
+func (c *structureChecker) checkParameters(params *yaml.Node, path string) map[paramKey]*yaml.Node {
+	byKey := make(map[paramKey]*yaml.Node)
+	for i, param := range items(params) {
+		paramPath := index(path, i)
+		resolved := param
+		if ref := scalar(field(param, "$ref")); ref != "" {
+			if resolved = c.resolve(ref); resolved == nil {
+				continue // reported by checkRefs
+			}
+		} else {
+			c.checkParameter(param, paramPath)
+		}
+		key := paramKey{name: scalar(field(resolved, "name")), in: scalar(field(resolved, "in"))}
+		if key.name == "" || key.in == "" {
+			continue
+		}
+		if _, ok := byKey[key]; ok {
+			c.addError(param, paramPath, fmt.Sprintf("Duplicate %s parameter %q", key.in, key.name))
+		}
+		byKey[key] = resolved
+	}
+	return byKey
+}
+
+func (c *structureChecker) checkParameter(param *yaml.Node, path string) {
+	c.requireFields(param, path, "name", "in")
+	in := field(param, "in")
+	if in == nil {
+		return
+	}
+	if !slices.Contains(parameterLocations, in.Value) {
+		c.addError(in, path+".in", fmt.Sprintf("Invalid parameter location %q: must be query, header, path, or cookie", in.Value))
+	}
+	if in.Value == "path" && scalar(field(param, "required")) != "true" {
+		c.addError(param, path, fmt.Sprintf("Path parameter %q must be required", scalar(field(param, "name"))))
+	}
+}
+
+// checkPathTemplate checks that the path parameters of an operation match
+// the parameters in the template of its path.
+func (c *structureChecker) checkPathTemplate(name string, op *yaml.Node, path string, params map[paramKey]*yaml.Node) {
+	var inTemplate []string
+	for _, match := range pathTemplatePattern.FindAllStringSubmatch(name, -1) {
+		inTemplate = append(inTemplate, match[1])
+		if _, ok := params[paramKey{name: match[1], in: "path"}]; !ok {
+			c.addError(op, path, fmt.Sprintf("Path parameter %q in %q is not declared", match[1], name))
+		}
+	}
+	var declared []string
+	for key := range params {
+		if key.in == "path" && !slices.Contains(inTemplate, key.name) {
+			declared = append(declared, key.name)
+		}
+	}
+	slices.Sort(declared)
+	for _, param := range declared {
+		c.addError(op, path, fmt.Sprintf("Path parameter %q is not in the path %q", param, name))
+	}
+}
+
+func (c *structureChecker) checkResponses(responses *yaml.Node, path string) {
+	if responses.Kind != yaml.MappingNode {
+		c.addError(responses, path, "Responses must be an object")
+		return
+	}
+	count := 0
+	for key, response := range pairs(responses) {
+		if strings.HasPrefix(key.Value, "x-") {
+			continue
+		}
+		count++
+		responsePath := member(path, key.Value)
+		if !statusCodePattern.MatchString(key.Value) {
+			c.addError(key, responsePath, fmt.Sprintf("Invalid response status code %q", key.Value))
+		}
+		if field(response, "$ref") == nil {
+			c.requireFields(response, responsePath, "description")
+		}
+	}
+	if count == 0 {
+		c.addError(responses, path, "Responses must contain at least one response")
+	}
+}
+
+func (c *structureChecker) checkComponents(components *yaml.Node, path string) {
+	if components == nil || components.Kind != yaml.MappingNode {
+		return
+	}
+	for kind, group := range pairs(components) {
+		if strings.HasPrefix(kind.Value, "x-") || group.Kind != yaml.MappingNode {
+			continue
+		}
+		for key, component := range pairs(group) {
+			componentPath := member(member(path, kind.Value), key.Value)
+			if !componentNamePattern.MatchString(key.Value) {
+				c.addError(key, componentPath, fmt.Sprintf("Invalid component name %q: may contain only letters, digits, '.', '-', and '_'", key.Value))
+			}
+			if kind.Value == "securitySchemes" && field(component, "$ref") == nil {
+				c.requireFields(component, componentPath, "type")
+			}
+		}
+	}
+}
+
+// checkRefs reports the local references in node that do not resolve.
+// Aliases are not followed, since their anchors are checked where they are
+// defined.
+func (c *structureChecker) checkRefs(node *yaml.Node, path string) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "$ref" && value.Kind == yaml.ScalarNode {
+				if strings.HasPrefix(value.Value, "#") && c.resolve(value.Value) == nil {
+					c.addError(value, member(path, "$ref"), fmt.Sprintf("Unresolvable $ref %q", value.Value))
+				}
+				continue
+			}
+			c.checkRefs(value, member(path, key.Value))
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			c.checkRefs(item, index(path, i))
+		}
+	}
+}
+
+// resolve returns the node the local reference ref points to, or nil if it
+// does not resolve.
+func (c *structureChecker) resolve(ref string) *yaml.Node {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil
+	}
+	if unescaped, err := url.PathUnescape(pointer); err == nil {
+		pointer = unescaped
+	}
+	node := deref(c.root)
+	if pointer == "" {
+		return node
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		node = child(node, strings.NewReplacer("~1", "/", "~0", "~").Replace(token))
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// child returns the child of node a JSON pointer token names: the value of a
+// key of a mapping or the item at an index of a sequence.
+func child(node *yaml.Node, token string) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return field(node, token)
+	case yaml.SequenceNode:
+		i, err := strconv.Atoi(token)
+		if err != nil || i < 0 || i >= len(node.Content) {
+			return nil
+		}
+		return deref(node.Content[i])
+	}
+	return nil
+}
+
+// requireFields reports the fields missing from the object node.
+func (c *structureChecker) requireFields(node *yaml.Node, path string, names ...string) {
+	if node.Kind != yaml.MappingNode {
+		c.addError(node, path, "Must be an object")
+		return
+	}
+	for _, name := range names {
+		if field(node, name) == nil {
+			c.addError(node, path, fmt.Sprintf("Missing required field %q", name))
+		}
+	}
+}
+
+func (c *structureChecker) addError(node *yaml.Node, path, message string) {
+	c.result.Valid = false
+	c.result.Errors = append(c.result.Errors, ValidationError{
+		Line: node.Line, Column: node.Column, Message: message, Path: path,
+	})
+}
+
+func (c *structureChecker) addWarning(node *yaml.Node, path, message string) {
+	c.result.Warnings = append(c.result.Warnings, ValidationError{
+		Line: node.Line, Column: node.Column, Message: message, Path: path,
+	})
+}
+
+// deref follows aliases to the node they refer to.
+func deref(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// field returns the value of the field name of the object node, or nil.
+func field(node *yaml.Node, name string) *yaml.Node {
+	node = deref(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			return deref(node.Content[i+1])
+		}
+	}
+	return nil
+}
+
+// pairs iterates over the keys and values of the object node.
+func pairs(node *yaml.Node) func(yield func(key, value *yaml.Node) bool) {
+	return func(yield func(key, value *yaml.Node) bool) {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if !yield(node.Content[i], deref(node.Content[i+1])) {
+				return
+			}
+		}
+	}
+}
+
+// items returns the items of the array node, or nil if node is not an array.
+func items(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	result := make([]*yaml.Node, len(node.Content))
+	for i, item := range node.Content {
+		result[i] = deref(item)
+	}
+	return result
+}
+
+// scalar returns the value of the scalar node, or "" for other nodes.
+func scalar(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
+
+// member returns the JSON path of the field name of the value at path.
+func member(path, name string) string {
+	if identifierPattern.MatchString(name) {
+		return path + "." + name
+	}
+	return path + "['" + strings.ReplaceAll(name, "'", `\'`) + "']"
+}
+
+// index returns the JSON path of item i of the array at path.
+func index(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
@@ -0,0 +1,179 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidator_Structure(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want []ValidationError
+	}{
+		{
+			name: "valid",
+			spec: `openapi: "3.0.3"
+info:
+  title: Test API
+  version: "1.0.0"
+paths:
+  /pets/{id}:
+    parameters:
+      - $ref: '#/components/parameters/PetID'
+    get:
+      operationId: getPet
+      responses:
+        "200":
+          $ref: '#/components/responses/Pet'
+        4XX:
+          description: Client error
+components:
+  parameters:
+    PetID:
+      name: id
+      in: path
+      required: true
+      schema:
+        type: integer
+  responses:
+    Pet:
+      description: The pet`,
+		},
+		{
+			name: "required fields",
+			spec: `openapi: "3.0.3"
+info:
+  title: Test API
+  license: {}
+servers:
+  - description: Production
+paths:
+  /pets:
+    get:
+      operationId: listPets
+    post:
+      requestBody:
+        description: Pet to add
+      responses:
+        "201": {}`,
+			want: []ValidationError{
+				{Line: 3, Column: 3, Path: "$.info", Message: `Missing required field "version"`},
+				{Line: 4, Column: 12, Path: "$.info.license", Message: `Missing required field "name"`},
+				{Line: 6, Column: 5, Path: "$.servers[0]", Message: `Missing required field "url"`},
+				{Line: 10, Column: 7, Path: "$.paths['/pets'].get", Message: `Missing required field "responses"`},
+				{Line: 13, Column: 9, Path: "$.paths['/pets'].post.requestBody", Message: `Missing required field "content"`},
+				{Line: 15, Column: 16, Path: "$.paths['/pets'].post.responses['201']", Message: `Missing required field "description"`},
+			},
+		},
+		{
+			name: "operations",
+			spec: `openapi: "3.1.0"
+info:
+  title: Test API
+  version: "1.0.0"
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: id
+          in: path
+        - name: verbose
+          in: body
+      responses:
+        "600":
+          description: Unknown
+  /pets/{petId}:
+    delete:
+      operationId: getPet
+      parameters:
+        - name: id
+          in: path
+          required: true
+  pets: {}`,
+			want: []ValidationError{
+				{Line: 10, Column: 11, Path: "$.paths['/pets/{id}'].get.parameters[0]", Message: `Path parameter "id" must be required`},
+				{Line: 13, Column: 15, Path: "$.paths['/pets/{id}'].get.parameters[1].in", Message: `Invalid parameter location "body": must be query, header, path, or cookie`},
+				{Line: 15, Column: 9, Path: "$.paths['/pets/{id}'].get.responses['600']", Message: `Invalid response status code "600"`},
+				{Line: 17, Column: 3, Path: "$.paths['/pets/{petId}']", Message: `Path "/pets/{petId}" is equivalent to "/pets/{id}"`},
+				{Line: 19, Column: 20, Path: "$.paths['/pets/{petId}'].delete.operationId", Message: `Duplicate operationId "getPet" (first used at $.paths['/pets/{id}'].get)`},
+				{Line: 19, Column: 7, Path: "$.paths['/pets/{petId}'].delete", Message: `Path parameter "petId" in "/pets/{petId}" is not declared`},
+				{Line: 19, Column: 7, Path: "$.paths['/pets/{petId}'].delete", Message: `Path parameter "id" is not in the path "/pets/{petId}"`},
+				{Line: 24, Column: 3, Path: "$.paths.pets", Message: `Path "pets" must begin with /`},
+			},
+		},
+		{
+			name: "references",
+			spec: `{
+  "openapi": "3.0.3",
+  "info": {"title": "Test API", "version": "1.0.0"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "responses": {
+          "200": {"$ref": "#/components/responses/Missing"}
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet Store": {"type": "object"},
+      "Pet": {"$ref": "#/components/schemas/Pet%20Store"}
+    },
+    "securitySchemes": {
+      "apiKey": {"name": "X-API-Key", "in": "header"}
+    }
+  }
+}`,
+			want: []ValidationError{
+				{Line: 15, Column: 7, Path: "$.components.schemas['Pet Store']", Message: `Invalid component name "Pet Store": may contain only letters, digits, '.', '-', and '_'`},
+				{Line: 19, Column: 17, Path: "$.components.securitySchemes.apiKey", Message: `Missing required field "type"`},
+				{Line: 8, Column: 27, Path: "$.paths['/pets'].get.responses['200']['$ref']", Message: `Unresolvable $ref "#/components/responses/Missing"`},
+			},
+		},
+	}
+
+	v := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := v.Validate([]byte(tt.spec))
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			var got []ValidationError
+			for _, e := range result.Errors {
+				if e.Line > 0 {
+					got = append(got, e)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Errors = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Errors[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+			if result.Valid != (len(result.Errors) == 0) {
+				t.Errorf("Valid = %v with %d errors", result.Valid, len(result.Errors))
+			}
+		})
+	}
+}
+
+func TestValidator_Structure_MissingPaths(t *testing.T) {
+	v := New()
+
+	result, err := v.Validate([]byte(`openapi: "3.1.0"
+info:
+  title: Test API
+  version: "1.0.0"`))
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if result.Valid || len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "webhooks") {
+		t.Errorf("Errors = %v, want the missing paths error", result.Errors)
+	}
+}
@@ -1,4 +1,9 @@
-// Package validator provides OpenAPI specification validation using libopenapi.
+// Package validator provides OpenAPI specification validation.
+//
+// Documents are parsed with libopenapi and then checked against the
+// structural rules of the specification: required fields, unique
+// operationIds, path parameters matching their path templates, resolvable
+// local $refs, and valid response status codes.
 package validator
 
 import (
@@ -85,6 +90,9 @@ func (v *Validator) Validate(data []byte) (*ValidationResult, error) {
 
 	result.Version = doc.GetVersion()
 	v.validateVersion(result, doc)
+	if v.isOpenAPI3(result.Version) {
+		v.validateStructure(result, data)
+	}
 
 	if len(result.Errors) > 0 {
 		result.Valid = false
@@ -0,0 +1,47 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// FormatText formats the verification result as human-readable text
+func FormatText(result *Result) string {
+	var sb strings.Builder
+
+	sb.WriteString("Implementation Drift Report\n")
+	sb.WriteString("===========================\n\n")
+
+	sb.WriteString("Summary\n")
+	sb.WriteString("-------\n")
+	sb.WriteString(fmt.Sprintf("Server: %s\n", result.BaseURL))
+	sb.WriteString(fmt.Sprintf("Requests: %d\n", result.Requests))
+	sb.WriteString(fmt.Sprintf("Findings: %d missing, %d undocumented, %d status mismatch, %d skipped\n\n",
+		result.Count(KindMissing), result.Count(KindUndocumented), result.Count(KindStatusMismatch), result.Count(KindSkipped)))
+
+	if len(result.Findings) == 0 {
+		sb.WriteString("Findings\n")
+		sb.WriteString("--------\n")
+		sb.WriteString("No drift found.\n")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("Findings (%d)\n", len(result.Findings)))
+	sb.WriteString("-------------\n\n")
+
+	for _, f := range result.Findings {
+		sb.WriteString(fmt.Sprintf("[%s] %s %s\n", f.Kind, f.Method, f.Path))
+		if f.Status != 0 {
+			sb.WriteString(fmt.Sprintf("  Status: %d\n", f.Status))
+		}
+		sb.WriteString(fmt.Sprintf("  Message: %s\n\n", f.Message))
+	}
+
+	return sb.String()
+}
+
+// FormatJSON formats the verification result as JSON
+func FormatJSON(result *Result) ([]byte, error) {
+	return json.MarshalIndent(result, "", "  ")
+}
@@ -0,0 +1,460 @@
+// Package verify compares an OpenAPI specification with a running server to
+// detect implementation drift: documented operations the server does not
+// serve, endpoints it serves that the spec does not document, and responses
+// with undocumented status codes.
+//
+// The server is probed with OPTIONS requests and safe GETs built from the
+// spec's parameter examples, plus any fixtures supplied for operations that
+// need request bodies, credentials, or concrete path parameters.
+package verify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"gopkg.in/yaml.v3"
+)
+
+// Kind classifies a verification finding.
+type Kind string
+
+const (
+	KindMissing        Kind = "MISSING"         // documented operation the server does not serve
+	KindUndocumented   Kind = "UNDOCUMENTED"    // endpoint the server serves but the spec does not document
+	KindStatusMismatch Kind = "STATUS_MISMATCH" // response status not documented for the operation
+	KindSkipped        Kind = "SKIPPED"         // operation that could not be probed
+)
+
+// Finding represents a single difference between the spec and the server.
+type Finding struct {
+	Kind    Kind   `json:"kind"`
+	Method  string `json:"method"`
+	Path    string `json:"path"`             // path template from the spec, or the probed path if undocumented
+	Status  int    `json:"status,omitempty"` // response status, if a request was made
+	Message string `json:"message"`
+}
+
+// Result contains the outcome of a verification run.
+type Result struct {
+	BaseURL  string    `json:"base_url"`
+	Requests int       `json:"requests"`
+	Findings []Finding `json:"findings"`
+}
+
+// HasDrift reports whether any finding other than a skipped operation was recorded.
+func (r *Result) HasDrift() bool {
+	return slices.ContainsFunc(r.Findings, func(f Finding) bool { return f.Kind != KindSkipped })
+}
+
+// Count returns the number of findings of the given kind.
+func (r *Result) Count(kind Kind) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// Fixture is a request sent to the server in addition to the generated probes.
+type Fixture struct {
+	Method  string            `json:"method" yaml:"method"`
+	Path    string            `json:"path" yaml:"path"` // concrete path, e.g. /pets/1?status=sold
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body    any               `json:"body,omitempty" yaml:"body,omitempty"`     // sent as JSON
+	Status  int               `json:"status,omitempty" yaml:"status,omitempty"` // expected status (default: any documented status)
+}
+
+// LoadFixtures parses a JSON or YAML list of fixtures.
+func LoadFixtures(data []byte) ([]Fixture, error) {
+	var fixtures []Fixture
+	if err := yaml.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures: %w", err)
+	}
+	for i, f := range fixtures {
+		if f.Method == "" || f.Path == "" {
+			return nil, fmt.Errorf("fixture %d: method and path are required", i)
+		}
+	}
+	return fixtures, nil
+}
+
+// Config configures a Verifier.
+type Config struct {
+	// BaseURL is the server URL the spec paths are appended to
+	BaseURL string
+
+	// Client sends the probes (default: a client with a 10 second timeout)
+	Client *http.Client
+
+	// Headers are added to every request, e.g. credentials
+	Headers http.Header
+
+	// Fixtures are sent after the generated probes
+	Fixtures []Fixture
+}
+
+// Verifier probes a server and compares it with a spec.
+type Verifier struct {
+	cfg Config
+}
+
+// New creates a Verifier.
+func New(cfg Config) *Verifier {
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &Verifier{cfg: cfg}
+}
+
+// probeMethods are the methods an Allow header is compared against.
+var probeMethods = []string{
+	http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete, http.MethodPatch,
+}
+
+// route is a spec path compiled for matching concrete request paths.
+type route struct {
+	path  string
+	item  *openapi.PathItem
+	regex *regexp.Regexp
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Verify probes the server for every path in doc and sends the configured fixtures.
+func (v *Verifier) Verify(ctx context.Context, doc *openapi.Document) (*Result, error) {
+	run := &run{verifier: v, ctx: ctx, result: &Result{BaseURL: v.cfg.BaseURL, Findings: []Finding{}}}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path, item := range doc.Paths {
+		if item != nil {
+			paths = append(paths, path)
+			run.routes = append(run.routes, compileRoute(path, item))
+		}
+	}
+	sort.Strings(paths)
+	// match concrete paths such as /pets/mine before templates such as /pets/{id}
+	sort.SliceStable(run.routes, func(i, j int) bool {
+		return strings.Count(run.routes[i].path, "{") < strings.Count(run.routes[j].path, "{")
+	})
+
+	for _, path := range paths {
+		if err := run.probePath(path, doc.Paths[path]); err != nil {
+			return nil, err
+		}
+	}
+	for _, fixture := range v.cfg.Fixtures {
+		if err := run.sendFixture(fixture); err != nil {
+			return nil, err
+		}
+	}
+	return run.result, nil
+}
+
+// run holds the state of a single Verify call.
+type run struct {
+	verifier *Verifier
+	ctx      context.Context
+	routes   []route
+	result   *Result
+}
+
+func (r *run) add(f Finding) {
+	r.result.Findings = append(r.result.Findings, f)
+}
+
+// probePath compares the methods the server allows for a path with the spec,
+// then sends a GET if the path documents one.
+func (r *run) probePath(path string, item *openapi.PathItem) error {
+	concrete, missing := concretePath(path, item)
+	if missing != "" {
+		if !r.coveredByFixture(path) {
+			r.add(Finding{Kind: KindSkipped, Method: "*", Path: path,
+				Message: fmt.Sprintf("no example for path parameter %q; supply a fixture", missing)})
+		}
+		return nil
+	}
+
+	resp, err := r.send(http.MethodOptions, concrete, nil, nil)
+	if err != nil {
+		return err
+	}
+	if allow := resp.Header.Get("Allow"); allow != "" && isSuccess(resp.StatusCode) {
+		r.compareAllow(path, item, allow)
+	}
+
+	if item.Get == nil {
+		return nil
+	}
+	query, missingQuery := requiredQuery(item.Get)
+	if missingQuery != "" {
+		r.add(Finding{Kind: KindSkipped, Method: http.MethodGet, Path: path,
+			Message: fmt.Sprintf("no example for required query parameter %q; supply a fixture", missingQuery)})
+		return nil
+	}
+	resp, err = r.send(http.MethodGet, concrete+query, nil, nil)
+	if err != nil {
+		return err
+	}
+	r.checkStatus(http.MethodGet, path, item.Get, resp.StatusCode, 0)
+	return nil
+}
+
+// compareAllow reports methods allowed by the server but not documented, and documented methods the server omits.
+func (r *run) compareAllow(path string, item *openapi.PathItem, allow string) {
+	allowed := make(map[string]bool)
+	for _, method := range strings.Split(allow, ",") {
+		allowed[strings.ToUpper(strings.TrimSpace(method))] = true
+	}
+
+	for _, method := range probeMethods {
+		documented := operationFor(item, method) != nil
+		switch {
+		case allowed[method] && !documented:
+			r.add(Finding{Kind: KindUndocumented, Method: method, Path: path,
+				Message: "server allows the method but the spec does not document it"})
+		case !allowed[method] && documented:
+			r.add(Finding{Kind: KindMissing, Method: method, Path: path,
+				Message: "spec documents the method but the server's Allow header omits it"})
+		}
+	}
+}
+
+// sendFixture sends a fixture and checks the response against the matching operation.
+func (r *run) sendFixture(f Fixture) error {
+	method := strings.ToUpper(f.Method)
+	resp, err := r.send(method, f.Path, f.Headers, f.Body)
+	if err != nil {
+		return err
+	}
+
+	template, op := r.match(method, f.Path)
+	if op == nil {
+		if !isNotServed(resp.StatusCode) {
+			r.add(Finding{Kind: KindUndocumented, Method: method, Path: pathOnly(f.Path), Status: resp.StatusCode,
+				Message: "server handled a request the spec does not document"})
+		}
+		return nil
+	}
+	r.checkStatus(method, template, op, resp.StatusCode, f.Status)
+	return nil
+}
+
+// checkStatus reports a missing operation or an undocumented status code.
+// expected overrides the documented responses when non-zero.
+func (r *run) checkStatus(method, path string, op *openapi.Operation, status, expected int) {
+	switch {
+	case isNotServed(status) && !documentsStatus(op, status):
+		r.add(Finding{Kind: KindMissing, Method: method, Path: path, Status: status,
+			Message: "spec documents the operation but the server does not serve it"})
+	case expected != 0 && status != expected:
+		r.add(Finding{Kind: KindStatusMismatch, Method: method, Path: path, Status: status,
+			Message: fmt.Sprintf("fixture expected status %d", expected)})
+	case expected == 0 && !documentsStatus(op, status):
+		r.add(Finding{Kind: KindStatusMismatch, Method: method, Path: path, Status: status,
+			Message: fmt.Sprintf("status %d is not documented for the operation", status)})
+	}
+}
+
+func (r *run) send(method, path string, headers map[string]string, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode fixture body for %s %s: %w", method, path, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(r.ctx, method, r.verifier.cfg.BaseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request %s %s: %w", method, path, err)
+	}
+	for name, values := range r.verifier.cfg.Headers {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := r.verifier.cfg.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request %s %s failed: %w", method, path, err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	r.result.Requests++
+	return resp, nil
+}
+
+// match returns the path template and operation a concrete request path belongs to.
+func (r *run) match(method, rawPath string) (string, *openapi.Operation) {
+	path := pathOnly(rawPath)
+	for _, rt := range r.routes {
+		if rt.regex.MatchString(path) {
+			return rt.path, operationFor(rt.item, method)
+		}
+	}
+	return "", nil
+}
+
+func (r *run) coveredByFixture(template string) bool {
+	for _, f := range r.verifier.cfg.Fixtures {
+		if path, _ := r.match(f.Method, f.Path); path == template {
+			return true
+		}
+	}
+	return false
+}
+
+func compileRoute(path string, item *openapi.PathItem) route {
+	var pattern strings.Builder
+	last := 0
+	for _, loc := range pathParamPattern.FindAllStringIndex(path, -1) {
+		pattern.WriteString(regexp.QuoteMeta(path[last:loc[0]]))
+		pattern.WriteString(`[^/]+`)
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(path[last:]))
+	return route{path: path, item: item, regex: regexp.MustCompile("^" + pattern.String() + "$")}
+}
+
+// concretePath substitutes path parameters with their examples. It returns the
+// name of the first parameter without an example if the path cannot be built.
+func concretePath(path string, item *openapi.PathItem) (string, string) {
+	var missing string
+	concrete := pathParamPattern.ReplaceAllStringFunc(path, func(match string) string {
+		name := match[1 : len(match)-1]
+		value, ok := exampleValue(findParam(item, name, openapi.ParameterInPath))
+		if !ok && missing == "" {
+			missing = name
+		}
+		return url.PathEscape(value)
+	})
+	return concrete, missing
+}
+
+// requiredQuery builds a query string from the examples of required query
+// parameters, returning the name of the first one without an example.
+func requiredQuery(op *openapi.Operation) (string, string) {
+	values := url.Values{}
+	for _, param := range op.Parameters {
+		if param == nil || param.In != openapi.ParameterInQuery || !param.Required {
+			continue
+		}
+		value, ok := exampleValue(param)
+		if !ok {
+			return "", param.Name
+		}
+		values.Set(param.Name, value)
+	}
+	if len(values) == 0 {
+		return "", ""
+	}
+	return "?" + values.Encode(), ""
+}
+
+// findParam returns the parameter declared on any operation of item or on item itself.
+func findParam(item *openapi.PathItem, name string, in openapi.ParameterLocation) *openapi.Parameter {
+	lists := [][]*openapi.Parameter{item.Parameters}
+	for _, method := range slices.Concat(probeMethods, []string{http.MethodHead, http.MethodOptions}) {
+		if op := operationFor(item, method); op != nil {
+			lists = append(lists, op.Parameters)
+		}
+	}
+	for _, params := range lists {
+		for _, param := range params {
+			if param != nil && param.Name == name && param.In == in {
+				return param
+			}
+		}
+	}
+	return nil
+}
+
+// exampleValue returns a string form of the parameter's example, enum, or default.
+func exampleValue(param *openapi.Parameter) (string, bool) {
+	if param == nil {
+		return "", false
+	}
+	candidates := []any{param.Example}
+	if param.Schema != nil {
+		candidates = append(candidates, param.Schema.Example, param.Schema.Default)
+		if len(param.Schema.Enum) > 0 {
+			candidates = append(candidates, param.Schema.Enum[0])
+		}
+	}
+	for _, candidate := range candidates {
+		if candidate != nil {
+			return fmt.Sprint(candidate), true
+		}
+	}
+	return "", false
+}
+
+// documentsStatus reports whether op documents status, directly, through a
+// range such as 4XX, or through a default response.
+func documentsStatus(op *openapi.Operation, status int) bool {
+	code := fmt.Sprint(status)
+	for key := range op.Responses {
+		switch {
+		case key == code, key == "default":
+			return true
+		case len(key) == 3 && strings.EqualFold(key[1:], "XX") && key[0] == code[0]:
+			return true
+		}
+	}
+	return false
+}
+
+func isSuccess(status int) bool {
+	return status >= 200 && status < 300
+}
+
+// isNotServed reports statuses a server returns for routes it does not implement.
+func isNotServed(status int) bool {
+	return status == http.StatusNotFound || status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented
+}
+
+func pathOnly(rawPath string) string {
+	path, _, _ := strings.Cut(rawPath, "?")
+	return path
+}
+
+func operationFor(item *openapi.PathItem, method string) *openapi.Operation {
+	switch method {
+	case http.MethodGet:
+		return item.Get
+	case http.MethodPut:
+		return item.Put
+	case http.MethodPost:
+		return item.Post
+	case http.MethodDelete:
+		return item.Delete
+	case http.MethodPatch:
+		return item.Patch
+	case http.MethodHead:
+		return item.Head
+	case http.MethodOptions:
+		return item.Options
+	case http.MethodTrace:
+		return item.Trace
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package verify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+func createVerifyTestDoc() *openapi.Document {
+	ok := openapi.Responses{"200": {Description: "OK"}}
+	return &openapi.Document{
+		Paths: openapi.Paths{
+			"/pets": {
+				Get:  &openapi.Operation{Responses: ok},
+				Post: &openapi.Operation{Responses: openapi.Responses{"201": {Description: "Created"}}},
+			},
+			"/pets/{petId}": {
+				Get: &openapi.Operation{
+					Parameters: []*openapi.Parameter{{Name: "petId", In: openapi.ParameterInPath, Required: true, Example: 7}},
+					Responses:  openapi.Responses{"200": {Description: "OK"}, "4XX": {Description: "Error"}},
+				},
+			},
+			"/orders": {Get: &openapi.Operation{Responses: ok}},
+			"/orders/{orderId}": {
+				Get: &openapi.Operation{
+					Parameters: []*openapi.Parameter{{Name: "orderId", In: openapi.ParameterInPath, Required: true}},
+					Responses:  ok,
+				},
+			},
+			"/users/{userId}": {
+				Get: &openapi.Operation{
+					Parameters: []*openapi.Parameter{{Name: "userId", In: openapi.ParameterInPath, Required: true}},
+					Responses:  ok,
+				},
+			},
+		},
+	}
+}
+
+// newVerifyTestServer serves /pets (GET, POST, DELETE), /pets/7 returning 500,
+// /users/1, and an undocumented /admin; /orders is not implemented.
+func newVerifyTestServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("OPTIONS /pets", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", "GET, POST, DELETE, OPTIONS")
+	})
+	mux.HandleFunc("GET /pets", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("GET /pets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("GET /admin", func(w http.ResponseWriter, r *http.Request) {})
+	return httptest.NewServer(mux)
+}
+
+func TestVerify(t *testing.T) {
+	server := newVerifyTestServer()
+	defer server.Close()
+
+	v := New(Config{
+		BaseURL: server.URL + "/",
+		Fixtures: []Fixture{
+			{Method: "get", Path: "/users/1"},
+			{Method: "GET", Path: "/admin"},
+			{Method: "GET", Path: "/nothing"},
+		},
+	})
+	result, err := v.Verify(context.Background(), createVerifyTestDoc())
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	got := make(map[string]bool)
+	for _, f := range result.Findings {
+		got[string(f.Kind)+" "+f.Method+" "+f.Path] = true
+	}
+	want := []string{
+		"UNDOCUMENTED DELETE /pets",
+		"STATUS_MISMATCH GET /pets/{petId}",
+		"MISSING GET /orders",
+		"SKIPPED * /orders/{orderId}",
+		"UNDOCUMENTED GET /admin",
+	}
+	for _, key := range want {
+		if !got[key] {
+			t.Errorf("missing finding %q in %+v", key, result.Findings)
+		}
+	}
+	if len(result.Findings) != len(want) {
+		t.Errorf("findings = %+v, want %d", result.Findings, len(want))
+	}
+	if !result.HasDrift() {
+		t.Error("HasDrift() = false, want true")
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	fixtures, err := LoadFixtures([]byte(`
+- method: POST
+  path: /pets
+  headers: {Authorization: Bearer token}
+  body: {name: Rex}
+  status: 201
+`))
+	if err != nil {
+		t.Fatalf("LoadFixtures() error = %v", err)
+	}
+	if len(fixtures) != 1 || fixtures[0].Status != 201 || fixtures[0].Headers["Authorization"] != "Bearer token" {
+		t.Errorf("fixtures = %+v", fixtures)
+	}
+
+	if _, err := LoadFixtures([]byte(`[{method: GET}]`)); err == nil || !strings.Contains(err.Error(), "path") {
+		t.Errorf("LoadFixtures() error = %v, want missing path error", err)
+	}
+}
+
+func TestFormatText(t *testing.T) {
+	text := FormatText(&Result{BaseURL: "http://localhost", Findings: []Finding{
+		{Kind: KindMissing, Method: "GET", Path: "/orders", Status: 404, Message: "not served"},
+	}})
+	for _, want := range []string{"1 missing", "[MISSING] GET /orders", "Status: 404"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("FormatText() missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Package yachi adapts the yahttp plugin to the chi router.
+//
+// chi uses net/http handlers and middleware natively, so the yahttp
+// handlers plug in directly; this package adds route registration:
+//
+//	plugin := yahttp.New(spec, nil)
+//	r := chi.NewRouter()
+//	r.Use(yachi.Validation(plugin))
+//	yachi.Mount(r, plugin)
+package yachi
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/fathurrohman26/yaswag/pkg/yahttp"
+)
+
+// SpecHandler returns a handler that serves the OpenAPI spec.
+func SpecHandler(p *yahttp.Plugin) http.Handler {
+	return p.SpecHandler()
+}
+
+// DocsHandler returns a handler that serves the documentation UI selected by the DocsUI option.
+func DocsHandler(p *yahttp.Plugin) http.Handler {
+	return p.DocsHandler()
+}
+
+// Validation returns a chi middleware that validates requests against the spec.
+func Validation(p *yahttp.Plugin) func(http.Handler) http.Handler {
+	return p.ValidationMiddleware()
+}
+
+// Middleware returns a chi middleware running the plugin's configured chain
+// (logging, CORS, compression, and validation).
+func Middleware(p *yahttp.Plugin) func(http.Handler) http.Handler {
+	return p.Handler()
+}
+
+// Mount registers the spec, named spec, documentation, and embedded asset routes on r.
+func Mount(r chi.Router, p *yahttp.Plugin) {
+	opts := p.Options()
+	if opts.SpecPath != "" {
+		r.Method(http.MethodGet, opts.SpecPath, SpecHandler(p))
+		r.Method(http.MethodHead, opts.SpecPath, SpecHandler(p))
+	}
+	for _, path := range p.NamedSpecPaths() {
+		r.Method(http.MethodGet, path, p.NamedSpecHandler())
+	}
+	if opts.SwaggerUIPath != "" {
+		r.Method(http.MethodGet, opts.SwaggerUIPath, DocsHandler(p))
+	}
+	if opts.OfflineAssets {
+		r.Method(http.MethodGet, p.AssetsPath()+"/*", p.AssetsHandler())
+	}
+}
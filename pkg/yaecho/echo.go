@@ -0,0 +1,61 @@
+// Package yaecho adapts the yahttp plugin to the Echo web framework.
+//
+// The spec, documentation, and validation logic all live in yahttp; this
+// package only converts them to Echo handlers and middleware:
+//
+//	plugin := yahttp.New(spec, nil)
+//	e := echo.New()
+//	e.Use(yaecho.Validation(plugin))
+//	yaecho.Mount(e, plugin)
+package yaecho
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/fathurrohman26/yaswag/pkg/yahttp"
+)
+
+// Router is the subset of *echo.Echo and *echo.Group used by Mount.
+type Router interface {
+	GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+}
+
+// SpecHandler returns an Echo handler that serves the OpenAPI spec.
+func SpecHandler(p *yahttp.Plugin) echo.HandlerFunc {
+	return echo.WrapHandler(p.SpecHandler())
+}
+
+// DocsHandler returns an Echo handler that serves the documentation UI selected by the DocsUI option.
+func DocsHandler(p *yahttp.Plugin) echo.HandlerFunc {
+	return echo.WrapHandler(p.DocsHandler())
+}
+
+// Validation returns an Echo middleware that validates requests against the spec.
+func Validation(p *yahttp.Plugin) echo.MiddlewareFunc {
+	return echo.WrapMiddleware(p.ValidationMiddleware())
+}
+
+// Middleware returns an Echo middleware running the plugin's configured chain
+// (logging, CORS, compression, and validation).
+func Middleware(p *yahttp.Plugin) echo.MiddlewareFunc {
+	return echo.WrapMiddleware(p.Handler())
+}
+
+// Mount registers the spec, named spec, documentation, and embedded asset routes on r.
+func Mount(r Router, p *yahttp.Plugin) {
+	opts := p.Options()
+	if opts.SpecPath != "" {
+		r.GET(opts.SpecPath, SpecHandler(p))
+		r.HEAD(opts.SpecPath, SpecHandler(p))
+	}
+	for _, path := range p.NamedSpecPaths() {
+		r.GET(path, echo.WrapHandler(p.NamedSpecHandler()))
+	}
+	if opts.SwaggerUIPath != "" {
+		r.GET(opts.SwaggerUIPath, DocsHandler(p))
+	}
+	if opts.OfflineAssets {
+		r.GET(p.AssetsPath()+"/*", echo.WrapHandler(p.AssetsHandler()))
+	}
+}
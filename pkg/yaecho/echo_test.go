@@ -0,0 +1,63 @@
+package yaecho
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"github.com/fathurrohman26/yaswag/pkg/yahttp"
+)
+
+func createTestPlugin() *yahttp.Plugin {
+	return yahttp.New(&openapi.Document{
+		OpenAPI: "3.0.3",
+		Info:    openapi.Info{Title: "Test API", Version: "1.0.0"},
+		Paths: openapi.Paths{
+			"/users": &openapi.PathItem{
+				Get: &openapi.Operation{
+					OperationID: "listUsers",
+					Parameters: []*openapi.Parameter{
+						{Name: "page", In: openapi.ParameterInQuery, Required: true, Schema: openapi.IntegerSchema()},
+					},
+					Responses: openapi.Responses{"200": &openapi.Response{Description: "Success"}},
+				},
+			},
+		},
+	}, nil)
+}
+
+func TestEchoAdapter(t *testing.T) {
+	plugin := createTestPlugin()
+
+	e := echo.New()
+	Mount(e, plugin)
+	api := e.Group("", Validation(plugin))
+	api.GET("/users", func(c echo.Context) error { return c.String(http.StatusOK, "ok") })
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{path: "/openapi.json", wantCode: http.StatusOK, wantBody: "Test API"},
+		{path: "/docs", wantCode: http.StatusOK, wantBody: "swagger-ui"},
+		{path: "/users?page=1", wantCode: http.StatusOK, wantBody: "ok"},
+		{path: "/users", wantCode: http.StatusBadRequest, wantBody: "page"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if w.Code != tt.wantCode {
+			t.Errorf("GET %s status = %d, want %d", tt.path, w.Code, tt.wantCode)
+		}
+		if !strings.Contains(w.Body.String(), tt.wantBody) {
+			t.Errorf("GET %s body should contain %q", tt.path, tt.wantBody)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Package yafiber adapts the yahttp plugin to the Fiber web framework.
+//
+// The spec, documentation, and validation logic all live in yahttp; this
+// package converts them to fiber.Handler through Fiber's net/http adaptor:
+//
+//	plugin := yahttp.New(spec, nil)
+//	app := fiber.New()
+//	app.Use(yafiber.Validation(plugin))
+//	yafiber.Mount(app, plugin)
+//
+// Only validation is offered as middleware: Fiber runs on fasthttp, so the
+// net/http response wrappers used for logging and compression cannot observe
+// the output of Fiber handlers.
+package yafiber
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+
+	"github.com/fathurrohman26/yaswag/pkg/yahttp"
+)
+
+// SpecHandler returns a Fiber handler that serves the OpenAPI spec.
+func SpecHandler(p *yahttp.Plugin) fiber.Handler {
+	return adaptor.HTTPHandler(p.SpecHandler())
+}
+
+// DocsHandler returns a Fiber handler that serves the documentation UI selected by the DocsUI option.
+func DocsHandler(p *yahttp.Plugin) fiber.Handler {
+	return adaptor.HTTPHandler(p.DocsHandler())
+}
+
+// Validation returns a Fiber middleware that validates requests against the spec.
+func Validation(p *yahttp.Plugin) fiber.Handler {
+	return adaptor.HTTPMiddleware(p.ValidationMiddleware())
+}
+
+// Mount registers the spec, named spec, documentation, and embedded asset routes on r.
+func Mount(r fiber.Router, p *yahttp.Plugin) {
+	opts := p.Options()
+	if opts.SpecPath != "" {
+		r.Get(opts.SpecPath, SpecHandler(p))
+	}
+	for _, path := range p.NamedSpecPaths() {
+		r.Get(path, adaptor.HTTPHandler(p.NamedSpecHandler()))
+	}
+	if opts.SwaggerUIPath != "" {
+		r.Get(opts.SwaggerUIPath, DocsHandler(p))
+	}
+	if opts.OfflineAssets {
+		r.Get(p.AssetsPath()+"/*", adaptor.HTTPHandler(p.AssetsHandler()))
+	}
+}
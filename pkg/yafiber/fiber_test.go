@@ -0,0 +1,67 @@
+package yafiber
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"github.com/fathurrohman26/yaswag/pkg/yahttp"
+)
+
+func createTestPlugin() *yahttp.Plugin {
+	return yahttp.New(&openapi.Document{
+		OpenAPI: "3.0.3",
+		Info:    openapi.Info{Title: "Test API", Version: "1.0.0"},
+		Paths: openapi.Paths{
+			"/users": &openapi.PathItem{
+				Get: &openapi.Operation{
+					OperationID: "listUsers",
+					Parameters: []*openapi.Parameter{
+						{Name: "page", In: openapi.ParameterInQuery, Required: true, Schema: openapi.IntegerSchema()},
+					},
+					Responses: openapi.Responses{"200": &openapi.Response{Description: "Success"}},
+				},
+			},
+		},
+	}, nil)
+}
+
+func TestFiberAdapter(t *testing.T) {
+	plugin := createTestPlugin()
+
+	app := fiber.New()
+	Mount(app, plugin)
+	app.Get("/users", Validation(plugin), func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{path: "/openapi.json", wantCode: http.StatusOK, wantBody: "Test API"},
+		{path: "/docs", wantCode: http.StatusOK, wantBody: "swagger-ui"},
+		{path: "/users?page=1", wantCode: http.StatusOK, wantBody: "ok"},
+		{path: "/users", wantCode: http.StatusBadRequest, wantBody: "page"},
+	}
+
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if err != nil {
+			t.Fatalf("GET %s error = %v", tt.path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != tt.wantCode {
+			t.Errorf("GET %s status = %d, want %d", tt.path, resp.StatusCode, tt.wantCode)
+		}
+		if !strings.Contains(string(body), tt.wantBody) {
+			t.Errorf("GET %s body should contain %q", tt.path, tt.wantBody)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Package yagin adapts the yahttp plugin to the Gin web framework.
+//
+// The spec, documentation, and validation logic all live in yahttp; this
+// package only converts them to gin.HandlerFunc:
+//
+//	plugin := yahttp.New(spec, nil)
+//	r := gin.New()
+//	r.Use(yagin.Validation(plugin))
+//	yagin.Mount(r, plugin)
+package yagin
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fathurrohman26/yaswag/pkg/yahttp"
+)
+
+// SpecHandler returns a Gin handler that serves the OpenAPI spec.
+func SpecHandler(p *yahttp.Plugin) gin.HandlerFunc {
+	return gin.WrapH(p.SpecHandler())
+}
+
+// DocsHandler returns a Gin handler that serves the documentation UI selected by the DocsUI option.
+func DocsHandler(p *yahttp.Plugin) gin.HandlerFunc {
+	return gin.WrapH(p.DocsHandler())
+}
+
+// Validation returns a Gin middleware that validates requests against the spec.
+func Validation(p *yahttp.Plugin) gin.HandlerFunc {
+	return Wrap(p.ValidationMiddleware())
+}
+
+// Middleware returns a Gin middleware running the plugin's configured chain
+// (logging, CORS, compression, and validation).
+func Middleware(p *yahttp.Plugin) gin.HandlerFunc {
+	return Wrap(p.Handler())
+}
+
+// Mount registers the spec, named spec, documentation, and embedded asset routes on r.
+func Mount(r gin.IRoutes, p *yahttp.Plugin) {
+	opts := p.Options()
+	if opts.SpecPath != "" {
+		r.GET(opts.SpecPath, SpecHandler(p))
+		r.HEAD(opts.SpecPath, SpecHandler(p))
+	}
+	for _, path := range p.NamedSpecPaths() {
+		r.GET(path, gin.WrapH(p.NamedSpecHandler()))
+	}
+	if opts.SwaggerUIPath != "" {
+		r.GET(opts.SwaggerUIPath, DocsHandler(p))
+	}
+	if opts.OfflineAssets {
+		r.GET(p.AssetsPath()+"/*filepath", gin.WrapH(p.AssetsHandler()))
+	}
+}
+
+// Wrap adapts a yahttp middleware to a Gin middleware. The remaining Gin
+// handlers run only if the middleware calls its next handler; otherwise the
+// chain is aborted with the middleware's response.
+func Wrap(m yahttp.Middleware) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		called := false
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			c.Request = r
+			if w != http.ResponseWriter(c.Writer) {
+				c.Writer = &responseWriter{ResponseWriter: c.Writer, w: w}
+			}
+			c.Next()
+		})
+		m(next).ServeHTTP(c.Writer, c.Request)
+		if !called {
+			c.Abort()
+		}
+	}
+}
+
+// responseWriter routes Gin's writes through a writer installed by a yahttp
+// middleware, such as the logging or compression wrappers.
+type responseWriter struct {
+	gin.ResponseWriter
+	w http.ResponseWriter
+}
+
+func (rw *responseWriter) Header() http.Header {
+	return rw.w.Header()
+}
+
+func (rw *responseWriter) WriteHeader(code int) {
+	rw.w.WriteHeader(code)
+}
+
+func (rw *responseWriter) Write(data []byte) (int, error) {
+	return rw.w.Write(data)
+}
+
+func (rw *responseWriter) WriteString(s string) (int, error) {
+	return io.WriteString(rw.w, s)
+}
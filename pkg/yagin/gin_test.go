@@ -0,0 +1,81 @@
+package yagin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"github.com/fathurrohman26/yaswag/pkg/yahttp"
+)
+
+func createTestPlugin() *yahttp.Plugin {
+	return yahttp.New(&openapi.Document{
+		OpenAPI: "3.0.3",
+		Info:    openapi.Info{Title: "Test API", Version: "1.0.0"},
+		Paths: openapi.Paths{
+			"/users": &openapi.PathItem{
+				Get: &openapi.Operation{
+					OperationID: "listUsers",
+					Parameters: []*openapi.Parameter{
+						{Name: "page", In: openapi.ParameterInQuery, Required: true, Schema: openapi.IntegerSchema()},
+					},
+					Responses: openapi.Responses{"200": &openapi.Response{Description: "Success"}},
+				},
+			},
+		},
+	}, nil)
+}
+
+func TestGinAdapter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	plugin := createTestPlugin()
+
+	r := gin.New()
+	Mount(r, plugin)
+	api := r.Group("/", Validation(plugin))
+	api.GET("/users", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{path: "/openapi.json", wantCode: http.StatusOK, wantBody: "Test API"},
+		{path: "/docs", wantCode: http.StatusOK, wantBody: "swagger-ui"},
+		{path: "/users?page=1", wantCode: http.StatusOK, wantBody: "ok"},
+		{path: "/users", wantCode: http.StatusBadRequest, wantBody: "page"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if w.Code != tt.wantCode {
+			t.Errorf("GET %s status = %d, want %d", tt.path, w.Code, tt.wantCode)
+		}
+		if !strings.Contains(w.Body.String(), tt.wantBody) {
+			t.Errorf("GET %s body should contain %q", tt.path, tt.wantBody)
+		}
+	}
+}
+
+func TestWrap_ResponseWriter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var status int
+	logging := yahttp.Logging(func(format string, args ...any) { status = args[3].(int) })
+
+	r := gin.New()
+	r.Use(Wrap(logging))
+	r.GET("/teapot", func(c *gin.Context) { c.String(http.StatusTeapot, "short and stout") })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/teapot", nil))
+
+	if w.Code != http.StatusTeapot || status != http.StatusTeapot {
+		t.Errorf("status = %d, logged = %d, want %d", w.Code, status, http.StatusTeapot)
+	}
+}
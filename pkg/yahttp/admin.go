@@ -0,0 +1,137 @@
+package yahttp
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultAdminPath is the default path for the runtime configuration endpoint.
+const DefaultAdminPath = "/__yaswag/config"
+
+// RuntimeConfig is the set of plugin features that can be toggled at runtime.
+type RuntimeConfig struct {
+	EnableValidation bool `json:"enable_validation"`
+	EnableLogging    bool `json:"enable_logging"`
+	StrictValidation bool `json:"strict_validation"`
+}
+
+// runtimeConfigUpdate is a partial update; omitted fields are left unchanged.
+type runtimeConfigUpdate struct {
+	EnableValidation *bool `json:"enable_validation"`
+	EnableLogging    *bool `json:"enable_logging"`
+	StrictValidation *bool `json:"strict_validation"`
+}
+
+// runtimeState holds the toggles read by the middleware on every request.
+type runtimeState struct {
+	validation atomic.Bool
+	logging    atomic.Bool
+	strict     atomic.Bool
+}
+
+func (s *runtimeState) load() RuntimeConfig {
+	return RuntimeConfig{
+		EnableValidation: s.validation.Load(),
+		EnableLogging:    s.logging.Load(),
+		StrictValidation: s.strict.Load(),
+	}
+}
+
+func (s *runtimeState) apply(u runtimeConfigUpdate) {
+	if u.EnableValidation != nil {
+		s.validation.Store(*u.EnableValidation)
+	}
+	if u.EnableLogging != nil {
+		s.logging.Store(*u.EnableLogging)
+	}
+	if u.StrictValidation != nil {
+		s.strict.Store(*u.StrictValidation)
+	}
+}
+
+// RuntimeConfig returns the currently active runtime configuration.
+func (p *Plugin) RuntimeConfig() RuntimeConfig {
+	return p.runtime.load()
+}
+
+// AdminHandler returns a handler that reports the runtime configuration on GET
+// and applies partial updates on PUT, PATCH, or POST with a JSON body such as
+// {"enable_validation": false}.
+//
+// Every request must be accepted by Options.AdminAuth; without it the handler
+// rejects all requests.
+func (p *Plugin) AdminHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p.options.AdminAuth == nil || !p.options.AdminAuth(r) {
+			writeAdminError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+		case http.MethodPut, http.MethodPatch, http.MethodPost:
+			var update runtimeConfigUpdate
+			if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+				writeAdminError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+				return
+			}
+			p.runtime.apply(update)
+			p.logAdminChange(r)
+		default:
+			w.Header().Set("Allow", "GET, HEAD, PUT, PATCH, POST")
+			writeAdminError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(p.runtime.load())
+	})
+}
+
+func (p *Plugin) logAdminChange(r *http.Request) {
+	logger := p.options.Logger
+	if logger == nil {
+		logger = log.Printf
+	}
+	cfg := p.runtime.load()
+	logger("yaswag: runtime config updated from %s: validation=%t logging=%t strict=%t",
+		r.RemoteAddr, cfg.EnableValidation, cfg.EnableLogging, cfg.StrictValidation)
+}
+
+func writeAdminError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// AdminToken returns an AdminAuth function that accepts requests carrying
+// the given token as "Authorization: Bearer <token>".
+// An empty token rejects all requests.
+func AdminToken(token string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		if token == "" {
+			return false
+		}
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		return ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+	}
+}
+
+// toggle returns a middleware that applies m only while enabled is set.
+func toggle(enabled *atomic.Bool, m Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		wrapped := m(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if enabled.Load() {
+				wrapped.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
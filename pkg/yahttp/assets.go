@@ -0,0 +1,61 @@
+package yahttp
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/uiassets"
+)
+
+// AssetsHandler returns an http.Handler that serves the embedded documentation UI
+// bundles under the plugin's assets path.
+func (p *Plugin) AssetsHandler() http.Handler {
+	return http.StripPrefix(p.AssetsPath(), uiassets.Handler())
+}
+
+// AssetsPath returns the path the embedded UI bundles are served under,
+// defaulting to "assets" below the Swagger UI path.
+func (p *Plugin) AssetsPath() string {
+	if p.options.AssetsPath != "" {
+		return strings.TrimSuffix(p.options.AssetsPath, "/")
+	}
+	base := p.options.SwaggerUIPath
+	if base == "" {
+		base = "/docs"
+	}
+	return strings.TrimSuffix(base, "/") + "/assets"
+}
+
+// assetURLs returns where documentation pages load their bundles from. Offline
+// assets fall back to the CDN when the bundles were not embedded at build time.
+func (p *Plugin) assetURLs() uiassets.URLs {
+	if p.options.OfflineAssets {
+		return uiassets.LocalOrCDN(p.AssetsPath())
+	}
+	return uiassets.CDN()
+}
+
+// servesAssets reports whether documentation pages load embedded bundles,
+// which Mount then serves under AssetsPath.
+func (p *Plugin) servesAssets() bool {
+	return p.wantsAssets() && uiassets.Embedded()
+}
+
+// wantsAssets reports whether embedded bundles were requested.
+func (p *Plugin) wantsAssets() bool {
+	return p.options.OfflineAssets || p.options.RedocOptions.embeddedBundle()
+}
+
+// warnMissingAssets logs when embedded assets are requested but the bundles
+// were not embedded at build time, so pages load them from the CDN instead.
+func (p *Plugin) warnMissingAssets() {
+	if !p.wantsAssets() || uiassets.Embedded() {
+		return
+	}
+	logger := p.options.Logger
+	if logger == nil {
+		logger = log.Printf
+	}
+	logger("yahttp: embedded UI assets are enabled but not embedded; loading them from the CDN instead (run 'make ui-assets' and rebuild to serve them offline)")
+}
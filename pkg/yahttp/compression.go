@@ -0,0 +1,256 @@
+package yahttp
+
+import (
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// CompressionOptions configures response compression.
+type CompressionOptions struct {
+	// MinSize is the minimum response size in bytes to compress (default: 1024)
+	MinSize int
+
+	// ContentTypes is the allowlist of compressible media types; entries ending
+	// in "/" match a whole type such as "text/" (default: JSON, YAML, and text)
+	ContentTypes []string
+
+	// Level is the gzip compression level (default: gzip.DefaultCompression).
+	// Zero, which gzip takes as no compression, selects the default too.
+	Level int
+}
+
+// DefaultCompressionOptions returns sensible compression defaults.
+func DefaultCompressionOptions() *CompressionOptions {
+	return &CompressionOptions{
+		MinSize: 1024,
+		ContentTypes: []string{
+			"application/json",
+			"application/problem+json",
+			"application/yaml",
+			"text/",
+		},
+		Level: gzip.DefaultCompression,
+	}
+}
+
+// withDefaults returns a copy of o, or of the defaults if o is nil, with
+// each field left zero set to its default.
+func (o *CompressionOptions) withDefaults() *CompressionOptions {
+	defaults := DefaultCompressionOptions()
+	if o == nil {
+		return defaults
+	}
+	opts := *o
+	if opts.MinSize == 0 {
+		opts.MinSize = defaults.MinSize
+	}
+	if opts.ContentTypes == nil {
+		opts.ContentTypes = defaults.ContentTypes
+	}
+	if opts.Level == 0 {
+		opts.Level = defaults.Level
+	}
+	return &opts
+}
+
+// CompressionMiddleware returns a middleware that gzip-compresses responses.
+func (p *Plugin) CompressionMiddleware() Middleware {
+	return Compression(p.options.CompressionOptions)
+}
+
+// Compression returns a standalone middleware that gzip-compresses responses
+// for clients sending "Accept-Encoding: gzip". Options left zero take their
+// defaults. Responses smaller than MinSize, with a media type outside
+// ContentTypes, or already carrying a Content-Encoding are passed through
+// unchanged.
+func Compression(opts *CompressionOptions) Middleware {
+	opts = opts.withDefaults()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if r.Method == http.MethodHead || !acceptsGzip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, opts: opts, status: http.StatusOK}
+			defer cw.Close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			return strings.ReplaceAll(params, " ", "") != "q=0"
+		}
+	}
+	return false
+}
+
+// isCompressible reports whether the media type is in the allowlist.
+func (o *CompressionOptions) isCompressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range o.ContentTypes {
+		if mediaType == allowed || (strings.HasSuffix(allowed, "/") && strings.HasPrefix(mediaType, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// compressWriter buffers the response until MinSize bytes are written or the
+// handler returns, then decides whether to compress.
+type compressWriter struct {
+	http.ResponseWriter
+	opts     *CompressionOptions
+	status   int
+	buf      []byte
+	decided  bool
+	gz       *gzip.Writer
+	gzClosed bool
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.decided {
+		return
+	}
+	cw.status = code
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if !cw.decided {
+		cw.buf = append(cw.buf, b...)
+		if len(cw.buf) < cw.opts.MinSize {
+			return len(b), nil
+		}
+		if err := cw.decide(); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if cw.gz != nil {
+		return cw.gz.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// decide writes the status line and the buffered body, compressed if eligible.
+func (cw *compressWriter) decide() error {
+	cw.decided = true
+	h := cw.Header()
+	if h.Get("Content-Type") == "" && len(cw.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(cw.buf))
+	}
+
+	compress := len(cw.buf) >= cw.opts.MinSize &&
+		h.Get("Content-Encoding") == "" &&
+		cw.status != http.StatusNoContent && cw.status != http.StatusNotModified &&
+		cw.opts.isCompressible(h.Get("Content-Type"))
+
+	if compress {
+		gz, err := gzip.NewWriterLevel(cw.ResponseWriter, cw.opts.Level)
+		if err != nil {
+			return err
+		}
+		cw.gz = gz
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := cw.Write(buf)
+	return err
+}
+
+// Close flushes any buffered data and finishes the gzip stream.
+func (cw *compressWriter) Close() error {
+	if !cw.decided {
+		if err := cw.decide(); err != nil {
+			return err
+		}
+	}
+	if cw.gz != nil && !cw.gzClosed {
+		cw.gzClosed = true
+		return cw.gz.Close()
+	}
+	return nil
+}
+
+// Unwrap returns the underlying ResponseWriter for compatibility with
+// http.ResponseController and other interfaces.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// DocumentCompression adds a Content-Encoding response header to every
+// response in the spec whose content includes a compressible media type, so
+// clients know responses may be gzip-encoded. Existing headers are preserved.
+func DocumentCompression(spec *openapi.Document, opts *CompressionOptions) {
+	if spec == nil {
+		return
+	}
+	opts = opts.withDefaults()
+
+	for _, item := range spec.Paths {
+		if item == nil {
+			continue
+		}
+		for _, op := range []*openapi.Operation{
+			item.Get, item.Put, item.Post, item.Delete,
+			item.Options, item.Head, item.Patch, item.Trace,
+		} {
+			if op == nil {
+				continue
+			}
+			for _, resp := range op.Responses {
+				documentResponseCompression(resp, opts)
+			}
+		}
+	}
+	if spec.Components != nil {
+		for _, resp := range spec.Components.Responses {
+			documentResponseCompression(resp, opts)
+		}
+	}
+}
+
+func documentResponseCompression(resp *openapi.Response, opts *CompressionOptions) {
+	if resp == nil || resp.Ref != "" || !hasCompressibleContent(resp.Content, opts) {
+		return
+	}
+	if _, ok := resp.Headers["Content-Encoding"]; ok {
+		return
+	}
+	if resp.Headers == nil {
+		resp.Headers = make(map[string]*openapi.Header)
+	}
+	resp.Headers["Content-Encoding"] = &openapi.Header{
+		Description: "Set to gzip when the client accepts gzip and the body is at least the compression threshold",
+		Schema:      &openapi.Schema{Type: openapi.NewSchemaType(openapi.TypeString), Enum: []any{"gzip"}},
+	}
+}
+
+func hasCompressibleContent(content map[string]openapi.MediaType, opts *CompressionOptions) bool {
+	for mediaType := range content {
+		if opts.isCompressible(mediaType) {
+			return true
+		}
+	}
+	return false
+}
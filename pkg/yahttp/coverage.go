@@ -0,0 +1,249 @@
+package yahttp
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// Coverage records which operations of a spec, and which of their documented
+// responses, were exercised. Install its middleware in front of the API, or
+// in the handler under test, and call Report once the traffic or test suite
+// has run. It is safe for concurrent use.
+type Coverage struct {
+	routes *routeTrie
+
+	mu        sync.Mutex
+	hits      map[string]map[int]int // "METHOD /path/template" -> status -> count
+	unmatched int
+}
+
+// OperationCoverage describes the requests observed for one operation.
+type OperationCoverage struct {
+	Method      string      `json:"method"`
+	Path        string      `json:"path"`
+	OperationID string      `json:"operationId,omitempty"`
+	Requests    int         `json:"requests"`
+	Statuses    map[int]int `json:"statuses,omitempty"`
+
+	// UntestedResponses are documented response codes no request produced
+	UntestedResponses []string `json:"untestedResponses,omitempty"`
+}
+
+// Tested reports whether at least one request reached the operation.
+func (o OperationCoverage) Tested() bool {
+	return o.Requests > 0
+}
+
+func (o OperationCoverage) String() string {
+	if o.OperationID == "" {
+		return o.Method + " " + o.Path
+	}
+	return fmt.Sprintf("%s %s (%s)", o.Method, o.Path, o.OperationID)
+}
+
+// CoverageReport summarizes the operations exercised against the spec.
+type CoverageReport struct {
+	Operations []OperationCoverage `json:"operations"`
+
+	// Unmatched counts requests whose path or method is not in the spec
+	Unmatched int `json:"unmatched"`
+}
+
+// NewCoverage creates a recorder for the operations declared in spec.
+func NewCoverage(spec *openapi.Document) *Coverage {
+	return &Coverage{
+		routes: compileRoutes(spec),
+		hits:   make(map[string]map[int]int),
+	}
+}
+
+// CoverageMiddleware returns a middleware that records requests in the plugin's coverage recorder.
+func (p *Plugin) CoverageMiddleware() Middleware {
+	return p.Coverage().Middleware()
+}
+
+// Coverage returns the plugin's coverage recorder, creating it on first use.
+func (p *Plugin) Coverage() *Coverage {
+	p.coverageOnce.Do(func() {
+		p.coverage = NewCoverage(p.Spec())
+	})
+	return p.coverage
+}
+
+// Middleware returns a middleware that records the operation and response
+// status of every request.
+func (c *Coverage) Middleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(wrapped, r)
+			c.record(r, wrapped.statusCode)
+		})
+	}
+}
+
+func (c *Coverage) record(r *http.Request, status int) {
+	key := ""
+	if matcher, _ := c.routes.match(r.URL.Path); matcher != nil {
+		if op, method := requestOperation(matcher.pathItem, r.Method); op != nil {
+			key = method + " " + matcher.path
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if key == "" {
+		c.unmatched++
+		return
+	}
+	if c.hits[key] == nil {
+		c.hits[key] = make(map[int]int)
+	}
+	c.hits[key][status]++
+}
+
+// Reset discards everything recorded so far.
+func (c *Coverage) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hits = make(map[string]map[int]int)
+	c.unmatched = 0
+}
+
+// Report returns the coverage of every operation in the spec, sorted by path and method.
+func (c *Coverage) Report() *CoverageReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := &CoverageReport{Unmatched: c.unmatched}
+	for _, route := range c.routes.sorted {
+		for _, method := range routerMethods {
+			op := operationFor(route.pathItem, method)
+			if op == nil {
+				continue
+			}
+			report.Operations = append(report.Operations, operationCoverage(op, method, route.path, c.hits[method+" "+route.path]))
+		}
+	}
+	slices.SortStableFunc(report.Operations, func(a, b OperationCoverage) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return report
+}
+
+func operationCoverage(op *openapi.Operation, method, path string, statuses map[int]int) OperationCoverage {
+	oc := OperationCoverage{Method: method, Path: path, OperationID: op.OperationID}
+	if len(statuses) > 0 {
+		oc.Statuses = make(map[int]int, len(statuses))
+		for status, count := range statuses {
+			oc.Statuses[status] = count
+			oc.Requests += count
+		}
+	}
+
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	for _, code := range codes {
+		if !responseExercised(code, codes, statuses) {
+			oc.UntestedResponses = append(oc.UntestedResponses, code)
+		}
+	}
+	return oc
+}
+
+// responseExercised reports whether any observed status is described by the
+// response code, which may be exact ("404"), a range ("4XX"), or "default".
+func responseExercised(code string, documented []string, statuses map[int]int) bool {
+	for status := range statuses {
+		if code == "default" {
+			if !slices.ContainsFunc(documented, func(c string) bool { return c != "default" && statusMatches(c, status) }) {
+				return true
+			}
+			continue
+		}
+		if statusMatches(code, status) {
+			return true
+		}
+	}
+	return false
+}
+
+func statusMatches(code string, status int) bool {
+	s := strconv.Itoa(status)
+	if len(code) == 3 && strings.EqualFold(code[1:], "XX") {
+		return s[:1] == code[:1]
+	}
+	return s == code
+}
+
+// Covered returns the number of operations that received at least one request.
+func (r *CoverageReport) Covered() int {
+	covered := 0
+	for _, op := range r.Operations {
+		if op.Tested() {
+			covered++
+		}
+	}
+	return covered
+}
+
+// Percent returns the share of operations covered, from 0 to 100.
+// A spec without operations is fully covered.
+func (r *CoverageReport) Percent() int {
+	if len(r.Operations) == 0 {
+		return 100
+	}
+	return r.Covered() * 100 / len(r.Operations)
+}
+
+// Untested returns the operations that received no requests.
+func (r *CoverageReport) Untested() []OperationCoverage {
+	var untested []OperationCoverage
+	for _, op := range r.Operations {
+		if !op.Tested() {
+			untested = append(untested, op)
+		}
+	}
+	return untested
+}
+
+// WriteText writes a human-readable summary listing untested operations and
+// the documented responses of tested operations that were never produced.
+func (r *CoverageReport) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Operation coverage: %d/%d (%d%%)\n", r.Covered(), len(r.Operations), r.Percent())
+	if r.Unmatched > 0 {
+		fmt.Fprintf(&sb, "Requests outside the spec: %d\n", r.Unmatched)
+	}
+
+	if untested := r.Untested(); len(untested) > 0 {
+		sb.WriteString("\nUntested operations:\n")
+		for _, op := range untested {
+			fmt.Fprintf(&sb, "  %s\n", op)
+		}
+	}
+
+	var partial []string
+	for _, op := range r.Operations {
+		if op.Tested() && len(op.UntestedResponses) > 0 {
+			partial = append(partial, fmt.Sprintf("  %s: %s\n", op, strings.Join(op.UntestedResponses, ", ")))
+		}
+	}
+	if len(partial) > 0 {
+		sb.WriteString("\nUntested responses:\n")
+		sb.WriteString(strings.Join(partial, ""))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,120 @@
+package yahttp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"github.com/fathurrohman26/yaswag/pkg/validator"
+)
+
+// HealthStatus describes the service health along with the served spec metadata.
+type HealthStatus struct {
+	Status            string   `json:"status"`
+	Title             string   `json:"title,omitempty"`
+	Version           string   `json:"version,omitempty"`
+	OpenAPI           string   `json:"openapi,omitempty"`
+	Checksum          string   `json:"checksum,omitempty"` // SHA-256 of the JSON-serialized spec
+	SpecValid         bool     `json:"spec_valid"`
+	SpecErrors        []string `json:"spec_errors,omitempty"`
+	RequestValidation bool     `json:"request_validation"`
+}
+
+// Default paths for the health endpoints.
+const (
+	DefaultHealthPath = "/healthz"
+	DefaultReadyPath  = "/readyz"
+)
+
+// Health status values.
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusNotReady = "not_ready"
+)
+
+// specHealth caches the spec checksum and validation result.
+type specHealth struct {
+	once     sync.Once
+	checksum string
+	valid    bool
+	errors   []string
+}
+
+// HealthHandler returns a liveness handler that always responds with 200 OK
+// and reports the title, version, and checksum of the served spec.
+func (p *Plugin) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := p.healthStatus()
+		status.Status = HealthStatusOK
+		writeHealthStatus(w, http.StatusOK, status)
+	})
+}
+
+// ReadyHandler returns a readiness handler that responds with 200 OK when the
+// served spec is valid and 503 Service Unavailable otherwise.
+func (p *Plugin) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := p.healthStatus()
+		code := http.StatusOK
+		status.Status = HealthStatusOK
+		if !status.SpecValid {
+			code = http.StatusServiceUnavailable
+			status.Status = HealthStatusNotReady
+		}
+		writeHealthStatus(w, code, status)
+	})
+}
+
+func (p *Plugin) healthStatus() HealthStatus {
+	served := p.served.Load()
+	health := &served.health
+	health.once.Do(func() { health.compute(served.doc) })
+
+	status := HealthStatus{
+		Checksum:          health.checksum,
+		SpecValid:         health.valid,
+		SpecErrors:        health.errors,
+		RequestValidation: p.runtime.validation.Load(),
+	}
+	if spec := served.doc; spec != nil {
+		status.Title = spec.Info.Title
+		status.Version = spec.Info.Version
+		status.OpenAPI = spec.OpenAPI
+	}
+	return status
+}
+
+func (h *specHealth) compute(spec *openapi.Document) {
+	if spec == nil {
+		h.errors = []string{"no OpenAPI spec configured"}
+		return
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		h.errors = []string{"failed to serialize spec: " + err.Error()}
+		return
+	}
+	sum := sha256.Sum256(data)
+	h.checksum = "sha256:" + hex.EncodeToString(sum[:])
+
+	result, err := validator.New().Validate(data)
+	if err != nil {
+		h.errors = []string{err.Error()}
+		return
+	}
+	h.valid = result.Valid
+	for _, e := range result.Errors {
+		h.errors = append(h.errors, e.Error())
+	}
+}
+
+func writeHealthStatus(w http.ResponseWriter, code int, status HealthStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(status)
+}
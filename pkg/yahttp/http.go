@@ -37,6 +37,7 @@ package yahttp
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/fathurrohman26/yaswag/pkg/openapi"
 )
@@ -77,18 +78,146 @@ func (b *PluginBuilder) SpecPath(path string) *PluginBuilder {
 	return b
 }
 
-// SwaggerUIPath sets the path for serving Swagger UI.
+// AddSpec serves spec at SpecsPath/{name}.json and lists it in the spec selector of Swagger UI.
+func (b *PluginBuilder) AddSpec(name string, spec *openapi.Document) *PluginBuilder {
+	b.opts.Specs = append(b.opts.Specs, NamedSpec{Name: name, Spec: spec})
+	return b
+}
+
+// SpecsPath sets the path prefix for serving the named specs.
+func (b *PluginBuilder) SpecsPath(path string) *PluginBuilder {
+	b.opts.SpecsPath = path
+	return b
+}
+
+// SwaggerUIPath sets the path for serving the documentation UI.
 func (b *PluginBuilder) SwaggerUIPath(path string) *PluginBuilder {
 	b.opts.SwaggerUIPath = path
 	return b
 }
 
+// DocsUI selects the documentation renderer served at SwaggerUIPath.
+func (b *PluginBuilder) DocsUI(ui DocsUI) *PluginBuilder {
+	b.opts.DocsUI = ui
+	return b
+}
+
+// WithSwaggerUI serves Swagger UI at SwaggerUIPath, configured with opts.
+func (b *PluginBuilder) WithSwaggerUI(opts *SwaggerUIOptions) *PluginBuilder {
+	b.opts.DocsUI = DocsUISwagger
+	b.opts.SwaggerUIOptions = opts
+	return b
+}
+
+// WithRedoc serves ReDoc at SwaggerUIPath, configured with opts.
+func (b *PluginBuilder) WithRedoc(opts *RedocOptions) *PluginBuilder {
+	b.opts.DocsUI = DocsUIRedoc
+	b.opts.RedocOptions = opts
+	return b
+}
+
+// WithBranding adds a logo, favicon, primary color, footer text, and navigation links to the documentation pages.
+func (b *PluginBuilder) WithBranding(branding *Branding) *PluginBuilder {
+	b.opts.Branding = branding
+	return b
+}
+
+// OfflineAssets serves the documentation UIs from embedded bundles instead of a CDN.
+func (b *PluginBuilder) OfflineAssets() *PluginBuilder {
+	b.opts.OfflineAssets = true
+	return b
+}
+
+// LazyTags serves per-tag slices of the spec and has Swagger UI load one tag at a time.
+func (b *PluginBuilder) LazyTags() *PluginBuilder {
+	b.opts.LazyTags = true
+	return b
+}
+
+// SpecCacheControl sets the Cache-Control header of spec responses, e.g. "no-store" in development.
+func (b *PluginBuilder) SpecCacheControl(value string) *PluginBuilder {
+	b.opts.SpecCacheControl = value
+	return b
+}
+
+// SpecModTime sets when the spec was generated, sent as Last-Modified.
+func (b *PluginBuilder) SpecModTime(t time.Time) *PluginBuilder {
+	b.opts.SpecModTime = t
+	return b
+}
+
+// HealthChecks enables the liveness and readiness endpoints at /healthz and /readyz.
+func (b *PluginBuilder) HealthChecks() *PluginBuilder {
+	b.opts.HealthPath = DefaultHealthPath
+	b.opts.ReadyPath = DefaultReadyPath
+	return b
+}
+
+// HealthPath sets the path for serving the liveness endpoint.
+func (b *PluginBuilder) HealthPath(path string) *PluginBuilder {
+	b.opts.HealthPath = path
+	return b
+}
+
+// ReadyPath sets the path for serving the readiness endpoint.
+func (b *PluginBuilder) ReadyPath(path string) *PluginBuilder {
+	b.opts.ReadyPath = path
+	return b
+}
+
+// OperationsEndpoint enables the operation metadata endpoint at /__operations.
+func (b *PluginBuilder) OperationsEndpoint() *PluginBuilder {
+	b.opts.OperationsPath = DefaultOperationsPath
+	return b
+}
+
+// OperationsPath sets the path for serving the operation metadata endpoint.
+func (b *PluginBuilder) OperationsPath(path string) *PluginBuilder {
+	b.opts.OperationsPath = path
+	return b
+}
+
 // EnableValidation enables request validation.
 func (b *PluginBuilder) EnableValidation() *PluginBuilder {
 	b.opts.EnableValidation = true
 	return b
 }
 
+// StrictValidation enables request validation and rejects undeclared query parameters.
+func (b *PluginBuilder) StrictValidation() *PluginBuilder {
+	b.opts.EnableValidation = true
+	b.opts.StrictValidation = true
+	return b
+}
+
+// MaxBodyBytes sets the size limit of the request bodies validation reads;
+// negative means no limit.
+func (b *PluginBuilder) MaxBodyBytes(n int64) *PluginBuilder {
+	b.opts.MaxBodyBytes = n
+	return b
+}
+
+// StrictRouting rejects requests for paths and methods not declared in the spec.
+func (b *PluginBuilder) StrictRouting() *PluginBuilder {
+	b.opts.StrictRouting = true
+	return b
+}
+
+// WithStrictRouting rejects requests not declared in the spec with custom options.
+func (b *PluginBuilder) WithStrictRouting(opts *StrictRoutingOptions) *PluginBuilder {
+	b.opts.StrictRouting = true
+	b.opts.StrictRoutingOptions = opts
+	return b
+}
+
+// Admin enables the runtime configuration endpoint at /__yaswag/config,
+// protected by the given authorization function.
+func (b *PluginBuilder) Admin(auth func(*http.Request) bool) *PluginBuilder {
+	b.opts.AdminPath = DefaultAdminPath
+	b.opts.AdminAuth = auth
+	return b
+}
+
 // EnableCORS enables CORS with default options.
 func (b *PluginBuilder) EnableCORS() *PluginBuilder {
 	b.opts.EnableCORS = true
@@ -102,6 +231,19 @@ func (b *PluginBuilder) WithCORS(opts *CORSOptions) *PluginBuilder {
 	return b
 }
 
+// EnableCompression enables gzip response compression with default options.
+func (b *PluginBuilder) EnableCompression() *PluginBuilder {
+	b.opts.EnableCompression = true
+	return b
+}
+
+// WithCompression enables gzip response compression with custom options.
+func (b *PluginBuilder) WithCompression(opts *CompressionOptions) *PluginBuilder {
+	b.opts.EnableCompression = true
+	b.opts.CompressionOptions = opts
+	return b
+}
+
 // EnableLogging enables request logging.
 func (b *PluginBuilder) EnableLogging() *PluginBuilder {
 	b.opts.EnableLogging = true
@@ -121,6 +263,44 @@ func (b *PluginBuilder) ValidationErrorHandler(handler func(http.ResponseWriter,
 	return b
 }
 
+// EnableTracing enables OpenTelemetry tracing with the global tracer provider and propagator.
+func (b *PluginBuilder) EnableTracing() *PluginBuilder {
+	b.opts.EnableTracing = true
+	return b
+}
+
+// WithTracing enables OpenTelemetry tracing with custom options.
+func (b *PluginBuilder) WithTracing(opts *TracingOptions) *PluginBuilder {
+	b.opts.EnableTracing = true
+	b.opts.TracingOptions = opts
+	return b
+}
+
+// WithSecurity enforces each operation's security requirements with the given options.
+func (b *PluginBuilder) WithSecurity(opts *SecurityOptions) *PluginBuilder {
+	b.opts.EnableSecurity = true
+	b.opts.SecurityOptions = opts
+	return b
+}
+
+// EnableOperationHeaders sets X-Operation-Id and X-Spec-Version on every response.
+func (b *PluginBuilder) EnableOperationHeaders() *PluginBuilder {
+	b.opts.EnableOperationHeaders = true
+	return b
+}
+
+// EnableCoverage records the operations and response statuses exercised, reported by Plugin.Coverage.
+func (b *PluginBuilder) EnableCoverage() *PluginBuilder {
+	b.opts.EnableCoverage = true
+	return b
+}
+
+// OnSpecChange sets a function called with the new spec whenever it changes at runtime.
+func (b *PluginBuilder) OnSpecChange(fn func(spec *openapi.Document)) *PluginBuilder {
+	b.opts.OnSpecChange = fn
+	return b
+}
+
 // Build creates the plugin with the configured options.
 func (b *PluginBuilder) Build() *Plugin {
 	return New(b.spec, b.opts)
@@ -1,13 +1,25 @@
 package yahttp
 
 import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"github.com/fathurrohman26/yaswag/pkg/uiassets"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func createTestSpec() *openapi.Document {
@@ -445,6 +457,442 @@ func TestSwaggerUIHandler(t *testing.T) {
 	}
 }
 
+func TestSwaggerUIHandlerWithOptions(t *testing.T) {
+	plugin := WithSpec(createTestSpec()).WithSwaggerUI(&SwaggerUIOptions{
+		DocExpansion:         "none",
+		PersistAuthorization: true,
+		TryItOutEnabled:      true,
+		TagsSorter:           "alpha",
+		OAuth2: &SwaggerUIOAuth2{
+			ClientID: "docs-client",
+			Scopes:   []string{"read:pets"},
+			UsePKCE:  true,
+		},
+		Extra:     map[string]any{"defaultModelsExpandDepth": -1},
+		CustomCSS: ".swagger-ui .info { color: #32329f; }",
+		CustomJS:  `console.log("ready");`,
+	}).Build()
+
+	w := httptest.NewRecorder()
+	plugin.DocsHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", w.Code, http.StatusOK)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		`"docExpansion":"none"`,
+		`"persistAuthorization":true`,
+		`"tryItOutEnabled":true`,
+		`"tagsSorter":"alpha"`,
+		`"defaultModelsExpandDepth":-1`,
+		`ui.initOAuth({"clientId":"docs-client","scopes":["read:pets"],"usePkceWithAuthorizationCodeGrant":true})`,
+		`.swagger-ui .info { color: #32329f; }`,
+		`console.log("ready");`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Page should contain %s", want)
+		}
+	}
+}
+
+func TestSwaggerUIHandlerWithOptions_Invalid(t *testing.T) {
+	for _, opts := range []*SwaggerUIOptions{
+		{DocExpansion: "all"},
+		{OperationsSorter: "path"},
+	} {
+		w := httptest.NewRecorder()
+		New(createTestSpec(), nil).SwaggerUIHandlerWithOptions(opts).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("%+v: Status = %d, want %d", *opts, w.Code, http.StatusInternalServerError)
+		}
+	}
+}
+
+func TestLazyTags(t *testing.T) {
+	spec := createTestSpec()
+	spec.Paths["/users"].Get.Tags = []string{"users"}
+	spec.Paths["/users/{id}"].Get.Tags = []string{"user detail"}
+	handler := WithSpec(spec).LazyTags().Mount(http.NewServeMux())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json?tag=users", nil))
+	var slice openapi.Document
+	if err := json.Unmarshal(w.Body.Bytes(), &slice); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(slice.Paths) != 1 || slice.Paths["/users"] == nil {
+		t.Errorf("paths of users slice = %v, want /users only", slice.Paths)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json?tag=missing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown tag status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	body := w.Body.String()
+	for _, want := range []string{`"url":"/openapi.json?tag=user+detail"`, `"StandaloneLayout"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("docs page missing %s", want)
+		}
+	}
+}
+
+func TestLazyTags_Disabled(t *testing.T) {
+	handler := WithSpec(createTestSpec()).Mount(http.NewServeMux())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json?tag=missing", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want the full spec", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if body := w.Body.String(); strings.Contains(body, "urls:") || !strings.Contains(body, `url: "\/openapi.json"`) {
+		t.Errorf("docs page should load the full spec:\n%s", body)
+	}
+}
+
+func TestNamedSpecs(t *testing.T) {
+	v1, v2 := createTestSpec(), createTestSpec()
+	v1.Info.Version, v2.Info.Version = "1.0.0", "2.0.0"
+	mux := http.NewServeMux()
+	WithSpec(v2).AddSpec("v1", v1).AddSpec("v2", v2).Mount(mux)
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{"/openapi/v1.json", http.StatusOK, `"version": "1.0.0"`},
+		{"/openapi/v2.json", http.StatusOK, `"version": "2.0.0"`},
+		{"/openapi/v1.yaml", http.StatusOK, "version: 1.0.0"},
+		{"/openapi/v3.json", http.StatusNotFound, `No spec named "v3"`},
+		{"/openapi.json", http.StatusOK, `"version": "2.0.0"`},
+		{"/docs", http.StatusOK, `urls: [{"name":"v1","url":"/openapi/v1.json"},{"name":"v2","url":"/openapi/v2.json"}]`},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.wantCode {
+			t.Errorf("GET %s status = %d, want %d", tt.path, w.Code, tt.wantCode)
+		}
+		if !strings.Contains(w.Body.String(), tt.wantBody) {
+			t.Errorf("GET %s body should contain %s", tt.path, tt.wantBody)
+		}
+	}
+}
+
+func TestNamedSpecs_WithoutSpec(t *testing.T) {
+	admin := createTestSpec()
+	admin.Info.Title = "Admin API"
+	plugin := WithSpec(nil).AddSpec("admin", admin).SpecsPath("/specs").DocsUI(DocsUIRedoc).Build()
+
+	w := httptest.NewRecorder()
+	plugin.DocsHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	body := w.Body.String()
+	if !strings.Contains(body, "Admin API") || !strings.Contains(body, "/specs/admin.json") {
+		t.Errorf("ReDoc should show the first named spec, got:\n%s", body)
+	}
+	if got, want := plugin.NamedSpecPaths(), []string{"/specs/admin.json", "/specs/admin.yaml"}; !slices.Equal(got, want) {
+		t.Errorf("NamedSpecPaths() = %v, want %v", got, want)
+	}
+}
+
+func TestSpecHandler_Caching(t *testing.T) {
+	generated := time.Date(2024, 5, 1, 12, 30, 15, 500, time.UTC)
+	handler := New(createTestSpec(), &Options{SpecModTime: generated}).SpecHandler()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if got := w.Header().Get("Cache-Control"); got != DefaultSpecCacheControl {
+		t.Errorf("Cache-Control = %q, want %q", got, DefaultSpecCacheControl)
+	}
+	lastModified := w.Header().Get("Last-Modified")
+	if lastModified != "Wed, 01 May 2024 12:30:15 GMT" {
+		t.Errorf("Last-Modified = %q", lastModified)
+	}
+
+	for since, want := range map[string]int{
+		lastModified:                    http.StatusNotModified,
+		"Thu, 02 May 2024 00:00:00 GMT": http.StatusNotModified,
+		"Wed, 01 May 2024 12:30:14 GMT": http.StatusOK,
+		"not a date":                    http.StatusOK,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+		req.Header.Set("If-Modified-Since", since)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("If-Modified-Since %q: status = %d, want %d", since, w.Code, want)
+		}
+		if want == http.StatusNotModified && w.Body.Len() > 0 {
+			t.Errorf("If-Modified-Since %q: 304 with a body", since)
+		}
+	}
+}
+
+func TestSpecHandler_CacheControl(t *testing.T) {
+	handler := WithSpec(createTestSpec()).SpecCacheControl("no-store").Mount(http.NewServeMux())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if got := w.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", got)
+	}
+	if w.Header().Get("Last-Modified") == "" {
+		t.Error("Last-Modified not set")
+	}
+}
+
+func TestSpecHandler_ETag(t *testing.T) {
+	handler := New(createTestSpec(), nil).SpecHandler()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	etag := w.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `"`) || w.Body.Len() == 0 {
+		t.Fatalf("ETag = %q, body %d bytes", etag, w.Body.Len())
+	}
+
+	yamlW := httptest.NewRecorder()
+	handler.ServeHTTP(yamlW, httptest.NewRequest(http.MethodGet, "/openapi.yaml", nil))
+	if got := yamlW.Header().Get("ETag"); got == "" || got == etag {
+		t.Errorf("YAML ETag = %q, want one of its own", got)
+	}
+
+	for match, want := range map[string]int{
+		etag:           http.StatusNotModified,
+		"*":            http.StatusNotModified,
+		`"other"`:      http.StatusOK,
+		`W/` + etag:    http.StatusNotModified,
+		`"a", ` + etag: http.StatusNotModified,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+		req.Header.Set("If-None-Match", match)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("If-None-Match %q: status = %d, want %d", match, w.Code, want)
+		}
+	}
+}
+
+func TestSpecHandler_Gzip(t *testing.T) {
+	handler := New(createTestSpec(), nil).SpecHandler()
+
+	plain := httptest.NewRecorder()
+	handler.ServeHTTP(plain, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if plain.Header().Get("Content-Encoding") != "" {
+		t.Error("Content-Encoding set without Accept-Encoding")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Errorf("Vary = %q, want Accept-Encoding", got)
+	}
+	if w.Header().Get("ETag") == plain.Header().Get("ETag") {
+		t.Error("gzipped spec has the ETag of the plain one")
+	}
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(zr)
+	if string(body) != plain.Body.String() {
+		t.Error("gunzipped spec differs from the plain one")
+	}
+}
+
+func TestUpdateSpec(t *testing.T) {
+	plugin := New(createTestSpec(), nil)
+	handler := plugin.SpecHandler()
+
+	before := httptest.NewRecorder()
+	handler.ServeHTTP(before, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	_, health := serveHealth(t, plugin.HealthHandler())
+
+	updated := createTestSpec()
+	updated.Info.Version = "2.0.0"
+	plugin.UpdateSpec(updated)
+	if plugin.Spec() != updated {
+		t.Error("Spec() is not the updated spec")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	req.Header.Set("If-None-Match", before.Header().Get("ETag"))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"version": "2.0.0"`) {
+		t.Errorf("status = %d, want the updated spec", w.Code)
+	}
+	if w.Header().Get("ETag") == before.Header().Get("ETag") {
+		t.Error("ETag unchanged by UpdateSpec")
+	}
+
+	_, status := serveHealth(t, plugin.HealthHandler())
+	if status.Version != "2.0.0" || status.Checksum == health.Checksum {
+		t.Errorf("health = %q %q, want the updated spec", status.Version, status.Checksum)
+	}
+}
+
+func TestAddRemoveOperation(t *testing.T) {
+	spec := createTestSpec()
+	var changes []*openapi.Document
+	plugin := WithSpec(spec).
+		EnableValidation().
+		EnableOperationHeaders().
+		OnSpecChange(func(spec *openapi.Document) { changes = append(changes, spec) }).
+		Build()
+	handler := plugin.Handler()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serve := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	expectStatus(t, "before AddOperation", serve("/orders/abc"), http.StatusOK)
+
+	op := &openapi.Operation{
+		OperationID: "getOrder",
+		Parameters: []*openapi.Parameter{
+			{Name: "id", In: openapi.ParameterInPath, Required: true, Schema: openapi.IntegerSchema()},
+		},
+		Responses: openapi.Responses{"200": &openapi.Response{Description: "Success"}},
+	}
+	if err := plugin.AddOperation("get", "/orders/{id}", op); err != nil {
+		t.Fatalf("AddOperation() error = %v", err)
+	}
+	expectStatus(t, "after AddOperation", serve("/orders/abc"), http.StatusBadRequest)
+	if w := serve("/orders/1"); w.Header().Get(HeaderOperationID) != "getOrder" {
+		t.Errorf("%s = %q, want getOrder", HeaderOperationID, w.Header().Get(HeaderOperationID))
+	}
+	if spec.Paths["/orders/{id}"] != nil {
+		t.Error("AddOperation changed the original spec")
+	}
+	if len(changes) != 1 || changes[0] != plugin.Spec() {
+		t.Errorf("OnSpecChange called %d times, want once with the new spec", len(changes))
+	}
+
+	if err := plugin.RemoveOperation(http.MethodGet, "/orders/{id}"); err != nil {
+		t.Fatalf("RemoveOperation() error = %v", err)
+	}
+	expectStatus(t, "after RemoveOperation", serve("/orders/abc"), http.StatusOK)
+	if _, ok := plugin.Spec().Paths["/orders/{id}"]; ok {
+		t.Error("RemoveOperation kept the path without operations")
+	}
+	if len(changes) != 2 {
+		t.Errorf("OnSpecChange called %d times, want 2", len(changes))
+	}
+}
+
+// expectStatus reports an error if the status of w is not want.
+func expectStatus(t *testing.T, when string, w *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if w.Code != want {
+		t.Errorf("status %s = %d, want %d", when, w.Code, want)
+	}
+}
+
+func TestAddRemoveOperation_Errors(t *testing.T) {
+	changed := false
+	plugin := WithSpec(createTestSpec()).
+		OnSpecChange(func(*openapi.Document) { changed = true }).
+		Build()
+	op := &openapi.Operation{Responses: openapi.Responses{"200": &openapi.Response{Description: "Success"}}}
+
+	errs := map[string]error{
+		"duplicate":      plugin.AddOperation(http.MethodGet, "/users", op),
+		"unknown method": plugin.AddOperation("CONNECT", "/orders", op),
+		"nil operation":  plugin.AddOperation(http.MethodGet, "/orders", nil),
+		"missing":        plugin.RemoveOperation(http.MethodPost, "/users"),
+		"no spec":        New(nil, nil).AddOperation(http.MethodGet, "/orders", op),
+	}
+	for name, err := range errs {
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if changed {
+		t.Errorf("failed changes called OnSpecChange")
+	}
+}
+
+func BenchmarkSpecHandler(b *testing.B) {
+	handler := New(createTestSpec(), nil).SpecHandler()
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+func BenchmarkSwaggerUIHandler(b *testing.B) {
+	handler := New(createTestSpec(), nil).SwaggerUIHandler()
+	req := httptest.NewRequest(http.MethodGet, "/docs", nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+func TestOfflineAssets(t *testing.T) {
+	var logged []string
+	plugin := WithSpec(createTestSpec()).OfflineAssets().WithLogger(func(format string, args ...any) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}).Build()
+	mux := http.NewServeMux()
+	plugin.Mount(mux)
+
+	if !uiassets.Embedded() {
+		// Without the bundles, the pages load them from the CDN rather than
+		// from an assets path that would answer 404
+		if len(logged) == 0 {
+			t.Error("Expected a warning that the assets are not embedded")
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+		if body := w.Body.String(); !strings.Contains(body, uiassets.CDN().SwaggerUIBundle) || strings.Contains(body, "/docs/assets/") {
+			t.Error("Page should load the bundles from the CDN when they are not embedded")
+		}
+		return
+	}
+
+	pages := map[string]http.Handler{
+		`src="/docs/assets/swagger-ui-bundle.js"`: mux,
+		`src="/docs/assets/redoc.standalone.js"`:  plugin.RedocHandler(),
+	}
+	for want, handler := range pages {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+		body := w.Body.String()
+		if !strings.Contains(body, want) {
+			t.Errorf("Page should contain %q", want)
+		}
+		if strings.Contains(body, "https://") {
+			t.Error("Page should not reference remote assets")
+		}
+	}
+
+	// The assets path takes precedence over the Swagger UI catch-all
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/assets/README.md", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Cache-Control") == "" {
+		t.Errorf("assets status = %d, Cache-Control = %q", w.Code, w.Header().Get("Cache-Control"))
+	}
+}
+
 func TestRedocHandler(t *testing.T) {
 	spec := createTestSpec()
 	plugin := New(spec, nil)
@@ -464,6 +912,102 @@ func TestRedocHandler(t *testing.T) {
 	}
 }
 
+func TestRedocHandlerWithOptions(t *testing.T) {
+	plugin := WithSpec(createTestSpec()).WithRedoc(&RedocOptions{
+		HideDownloadButton: true,
+		ExpandResponses:    "200,201",
+		Theme:              json.RawMessage(`{"colors":{"primary":{"main":"#32329f"}}}`),
+		UntrustedSpec:      true,
+		BundleURL:          "https://example.com/redoc/v2.1.5/redoc.standalone.js",
+	}).Build()
+
+	w := httptest.NewRecorder()
+	plugin.DocsHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", w.Code, http.StatusOK)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		`"hideDownloadButton":true`,
+		`"expandResponses":"200,201"`,
+		`"untrustedSpec":true`,
+		`"theme":{"colors":{"primary":{"main":"#32329f"}}}`,
+		`src="https://example.com/redoc/v2.1.5/redoc.standalone.js"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Page should contain %s", want)
+		}
+	}
+}
+
+func TestRedocHandlerWithOptions_InvalidTheme(t *testing.T) {
+	handler := New(createTestSpec(), nil).RedocHandlerWithOptions(&RedocOptions{Theme: json.RawMessage(`{"colors"`)})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestRedocEmbeddedBundle(t *testing.T) {
+	plugin := WithSpec(createTestSpec()).WithRedoc(&RedocOptions{EmbeddedBundle: true}).WithLogger(func(string, ...any) {}).Build()
+	mux := http.NewServeMux()
+	plugin.Mount(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if !uiassets.Embedded() {
+		if body := w.Body.String(); !strings.Contains(body, `src="`+uiassets.CDN().RedocBundle+`"`) {
+			t.Error("Page should load the pinned CDN bundle when ReDoc is not embedded")
+		}
+		return
+	}
+	if body := w.Body.String(); !strings.Contains(body, `src="/docs/assets/redoc.standalone.js"`) {
+		t.Error("Page should load the embedded ReDoc bundle")
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/assets/README.md", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("assets status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestScalarAndRapiDocHandlers(t *testing.T) {
+	plugin := New(createTestSpec(), nil)
+
+	for want, handler := range map[string]http.Handler{
+		`id="api-reference" data-url="/openapi.json"`: plugin.ScalarHandler(),
+		`<rapi-doc spec-url="/openapi.json"`:          plugin.RapiDocHandler(),
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Status = %d, want %d", w.Code, http.StatusOK)
+		}
+		if body := w.Body.String(); !strings.Contains(body, want) || !strings.Contains(body, "Test API") {
+			t.Errorf("Response should contain %q and the API title", want)
+		}
+	}
+}
+
+func TestDocsUI(t *testing.T) {
+	plugin := WithSpec(createTestSpec()).DocsUI(DocsUIScalar).Build()
+	mux := http.NewServeMux()
+	plugin.Mount(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+	body := w.Body.String()
+	if !strings.Contains(body, "api-reference") || strings.Contains(body, "swagger-ui") {
+		t.Error("DocsUI should select Scalar at the docs path")
+	}
+}
+
 func TestCORSMiddleware(t *testing.T) {
 	opts := &CORSOptions{
 		AllowedOrigins:   []string{"http://example.com"},
@@ -626,6 +1170,47 @@ func TestValidationMiddleware(t *testing.T) {
 	})
 }
 
+func TestTypedParams(t *testing.T) {
+	spec := createTestSpec()
+	spec.Paths["/users"].Get.Parameters = append(spec.Paths["/users"].Get.Parameters,
+		&openapi.Parameter{Name: "active", In: openapi.ParameterInQuery, Schema: openapi.BooleanSchema()},
+		&openapi.Parameter{Name: "name", In: openapi.ParameterInQuery, Schema: openapi.StringSchema()},
+	)
+
+	var page int64
+	var active, hasLimit, wrongType bool
+	var name string
+	handler := RequestValidation(spec, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ = QueryInt64(r, "page")
+		active, _ = QueryBool(r, "active")
+		name, _ = QueryString(r, "name")
+		_, hasLimit = QueryInt64(r, "limit")
+		_, wrongType = QueryString(r, "page")
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users?page=3&active=1&name=ann", nil))
+
+	if page != 3 || !active || name != "ann" {
+		t.Errorf("page, active, name = %d, %v, %q, want 3, true, ann", page, active, name)
+	}
+	if hasLimit {
+		t.Error("QueryInt64() should report absent parameters")
+	}
+	if wrongType {
+		t.Error("QueryString() should not return an integer parameter")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	if _, ok := PathInt64(req, "id"); ok {
+		t.Error("PathInt64() should report false without validation")
+	}
+	RequestValidation(spec, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := PathInt64(r, "id"); !ok || id != 42 {
+			t.Errorf("PathInt64() = %d, %v, want 42, true", id, ok)
+		}
+	})).ServeHTTP(httptest.NewRecorder(), req)
+}
+
 func TestValidationError(t *testing.T) {
 	err := ValidationError{
 		Field:   "limit",
@@ -663,20 +1248,1018 @@ func TestValidateRequest(t *testing.T) {
 	})
 }
 
-func TestServeSpec(t *testing.T) {
-	spec := createTestSpec()
-	handler := ServeSpec(spec)
+func TestValidateRequest_ParameterRef(t *testing.T) {
+	spec := &openapi.Document{
+		OpenAPI: "3.1.0",
+		Info:    openapi.Info{Title: "Test API", Version: "1.0.0"},
+		Paths: openapi.Paths{
+			"/items": &openapi.PathItem{Get: &openapi.Operation{
+				Parameters: []*openapi.Parameter{openapi.RefToParameter("Limit")},
+			}},
+		},
+		Components: &openapi.Components{Parameters: map[string]*openapi.Parameter{
+			"Limit": {Name: "limit", In: openapi.ParameterInQuery, Required: true, Schema: &openapi.Schema{Type: openapi.NewSchemaType(openapi.TypeInteger)}},
+		}},
+	}
 
-	req := httptest.NewRequest(http.MethodGet, "/", nil)
-	w := httptest.NewRecorder()
-	handler.ServeHTTP(w, req)
+	if errs := ValidateRequest(spec, httptest.NewRequest(http.MethodGet, "/items?limit=10", nil)); len(errs) != 0 {
+		t.Errorf("valid request: errors = %v", errs)
+	}
+	if errs := ValidateRequest(spec, httptest.NewRequest(http.MethodGet, "/items?limit=ten", nil)); len(errs) != 1 || errs[0].Field != "limit" {
+		t.Errorf("invalid request: errors = %v, want one for limit", errs)
+	}
+}
 
-	if w.Code != http.StatusOK {
-		t.Errorf("Status = %d, want %d", w.Code, http.StatusOK)
+func TestValidateRequest_QueryStyles(t *testing.T) {
+	status := openapi.ArraySchema(&openapi.Schema{Type: openapi.NewSchemaType(openapi.TypeString), Enum: []any{"available", "pending", "sold"}})
+	filter := &openapi.Schema{
+		Type: openapi.NewSchemaType(openapi.TypeObject),
+		Properties: map[string]*openapi.Schema{
+			"minPrice": openapi.NumberSchema(),
+			"category": openapi.StringSchema(),
+		},
+		Required: []string{"category"},
+	}
+	param := func(style string, explode bool, schema *openapi.Schema) *openapi.Parameter {
+		return &openapi.Parameter{Name: "p", In: openapi.ParameterInQuery, Style: style, Explode: &explode, Schema: schema}
 	}
+	ids := openapi.ArraySchema(openapi.IntegerSchema())
+
+	tests := []struct {
+		name       string
+		param      *openapi.Parameter
+		query      string
+		wantFields []string
+	}{
+		{"form exploded", &openapi.Parameter{Name: "p", In: openapi.ParameterInQuery, Schema: status}, "p=available&p=sold", nil},
+		{"form exploded invalid item", &openapi.Parameter{Name: "p", In: openapi.ParameterInQuery, Schema: status}, "p=available&p=lost", []string{"p[1]"}},
+		{"form", param("form", false, status), "p=available,pending", nil},
+		{"form invalid item", param("form", false, status), "p=available,lost", []string{"p[1]"}},
+		{"pipeDelimited", param("pipeDelimited", false, ids), "p=1|2|3", nil},
+		{"pipeDelimited invalid item", param("pipeDelimited", false, ids), "p=1|two", []string{"p[1]"}},
+		{"spaceDelimited", param("spaceDelimited", false, ids), "p=1%202", nil},
+		{"deepObject", param("deepObject", true, filter), "p[category]=toys&p[minPrice]=9.5", nil},
+		{"deepObject invalid property", param("deepObject", true, filter), "p[category]=toys&p[minPrice]=cheap", []string{"p[minPrice]"}},
+		{"deepObject missing property", param("deepObject", true, filter), "p[minPrice]=1", []string{"p[category]"}},
+		{"form object exploded", param("form", true, filter), "category=toys&minPrice=1", nil},
+		{"form object", param("form", false, filter), "p=category,toys,minPrice,1", nil},
+		{"form object odd pairs", param("form", false, filter), "p=category,toys,minPrice", []string{"p"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &openapi.Document{Paths: openapi.Paths{
+				"/pets": &openapi.PathItem{Get: &openapi.Operation{Parameters: []*openapi.Parameter{tt.param}}},
+			}}
+			errs := ValidateRequest(spec, httptest.NewRequest(http.MethodGet, "/pets?"+tt.query, nil))
+			var fields []string
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			if !slices.Equal(fields, tt.wantFields) {
+				t.Errorf("errors = %v, want errors for %v", errs, tt.wantFields)
+			}
+		})
+	}
+}
 
-	body, _ := io.ReadAll(w.Body)
-	if !strings.Contains(string(body), "Test API") {
+func TestValidationMiddleware_QueryCollections(t *testing.T) {
+	spec := &openapi.Document{Paths: openapi.Paths{
+		"/pets": &openapi.PathItem{Get: &openapi.Operation{Parameters: []*openapi.Parameter{
+			{Name: "status", In: openapi.ParameterInQuery, Schema: openapi.ArraySchema(openapi.StringSchema())},
+			{Name: "ids", In: openapi.ParameterInQuery, Style: "pipeDelimited", Schema: openapi.ArraySchema(openapi.IntegerSchema())},
+			{Name: "filter", In: openapi.ParameterInQuery, Style: "deepObject", Schema: &openapi.Schema{
+				Type:       openapi.NewSchemaType(openapi.TypeObject),
+				Properties: map[string]*openapi.Schema{"minPrice": openapi.NumberSchema()},
+			}},
+		}}},
+	}}
+
+	var status []string
+	var ids []int64
+	var filter map[string]any
+	handler := WithSpec(spec).StrictValidation().Build().Handler()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, _ = QueryStrings(r, "status")
+		ids, _ = QueryInt64s(r, "ids")
+		filter, _ = QueryObject(r, "filter")
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pets?status=a&status=b&ids=1|2&filter[minPrice]=2.5", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if !slices.Equal(status, []string{"a", "b"}) || !slices.Equal(ids, []int64{1, 2}) || filter["minPrice"] != 2.5 {
+		t.Errorf("QueryStrings, QueryInt64s, QueryObject = %v, %v, %v", status, ids, filter)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pets?other[x]=1", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("undeclared key: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func bodyTestSpec() *openapi.Document {
+	return &openapi.Document{
+		OpenAPI: "3.0.3",
+		Info:    openapi.Info{Title: "Test API", Version: "1.0.0"},
+		Paths: openapi.Paths{
+			"/pets": &openapi.PathItem{Post: &openapi.Operation{
+				RequestBody: openapi.RefToRequestBody("Pet"),
+			}},
+		},
+		Components: &openapi.Components{
+			RequestBodies: map[string]*openapi.RequestBody{
+				"Pet": {Required: true, Content: map[string]openapi.MediaType{"application/json": {Schema: openapi.RefTo("Pet")}}},
+			},
+			Schemas: map[string]*openapi.Schema{
+				"Pet": {
+					Type:       openapi.NewSchemaType(openapi.TypeObject),
+					Required:   []string{"name"},
+					Properties: map[string]*openapi.Schema{"name": openapi.StringSchema(), "id": {Type: openapi.NewSchemaType(openapi.TypeInteger), ReadOnly: true}},
+				},
+			},
+		},
+	}
+}
+
+func postPet(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestValidateRequest_Body(t *testing.T) {
+	spec := bodyTestSpec()
+
+	req := postPet(`{"name": "Rex"}`)
+	if errs := ValidateRequest(spec, req); len(errs) != 0 {
+		t.Errorf("valid body: errors = %v", errs)
+	}
+	if body, _ := io.ReadAll(req.Body); string(body) != `{"name": "Rex"}` {
+		t.Errorf("body after validation = %q", body)
+	}
+
+	errs := ValidateRequest(spec, postPet(`{"id": 1, "name": 7}`))
+	if len(errs) != 2 || errs[0].Field != "/id" || errs[1].Field != "/name" || errs[1].In != "body" {
+		t.Errorf("invalid body: errors = %v, want /id and /name", errs)
+	}
+}
+
+func TestValidateRequest_BodyErrors(t *testing.T) {
+	spec := bodyTestSpec()
+
+	if errs := ValidateRequest(spec, postPet(`{"name":`)); len(errs) != 1 || !strings.Contains(errs[0].Message, "invalid JSON") {
+		t.Errorf("malformed body: errors = %v", errs)
+	}
+	if errs := ValidateRequest(spec, httptest.NewRequest(http.MethodPost, "/pets", nil)); len(errs) != 1 || errs[0].Message != "request body is required" {
+		t.Errorf("missing body: errors = %v", errs)
+	}
+	if errs := ValidateRequest(spec, postPet(`{"name": "Rex"}`)); errs.StatusCode() != http.StatusBadRequest {
+		t.Errorf("StatusCode() = %d, want %d", errs.StatusCode(), http.StatusBadRequest)
+	}
+}
+
+func TestValidationMiddleware_MaxBodyBytes(t *testing.T) {
+	handler := WithSpec(bodyTestSpec()).EnableValidation().MaxBodyBytes(16).Build().Handler()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, postPet(`{"name": "Rex"}`))
+	if w.Code != http.StatusOK {
+		t.Errorf("small body: status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, postPet(`{"name": "Rex the dog"}`))
+	if w.Code != http.StatusRequestEntityTooLarge || !strings.Contains(w.Body.String(), "larger than 16 bytes") {
+		t.Errorf("large body: status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestServeSpec(t *testing.T) {
+	spec := createTestSpec()
+	handler := ServeSpec(spec)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	body, _ := io.ReadAll(w.Body)
+	if !strings.Contains(string(body), "Test API") {
 		t.Error("Response should contain spec content")
 	}
 }
+
+func serveHealth(t *testing.T, handler http.Handler) (int, HealthStatus) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var status HealthStatus
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatalf("Failed to decode health status: %v", err)
+	}
+	return w.Code, status
+}
+
+func TestHealthHandler(t *testing.T) {
+	plugin := New(createTestSpec(), nil)
+
+	code, status := serveHealth(t, plugin.HealthHandler())
+	if code != http.StatusOK {
+		t.Errorf("Status = %d, want %d", code, http.StatusOK)
+	}
+	if status.Title != "Test API" || status.Version != "1.0.0" {
+		t.Errorf("Title/Version = %q/%q, want Test API/1.0.0", status.Title, status.Version)
+	}
+	if !strings.HasPrefix(status.Checksum, "sha256:") {
+		t.Errorf("Checksum = %q, want sha256 prefix", status.Checksum)
+	}
+	if !status.SpecValid {
+		t.Errorf("SpecValid = false, errors = %v", status.SpecErrors)
+	}
+}
+
+func TestReadyHandler(t *testing.T) {
+	code, status := serveHealth(t, New(createTestSpec(), nil).ReadyHandler())
+	if code != http.StatusOK || status.Status != HealthStatusOK {
+		t.Errorf("Ready = %d %q, want %d %q", code, status.Status, http.StatusOK, HealthStatusOK)
+	}
+
+	invalid := &openapi.Document{OpenAPI: "2.0", Info: openapi.Info{Title: "Legacy", Version: "1.0.0"}}
+	code, status = serveHealth(t, New(invalid, nil).ReadyHandler())
+	if code != http.StatusServiceUnavailable || status.Status != HealthStatusNotReady {
+		t.Errorf("Ready = %d %q, want %d %q", code, status.Status, http.StatusServiceUnavailable, HealthStatusNotReady)
+	}
+	if len(status.SpecErrors) == 0 {
+		t.Error("Expected spec errors for invalid spec")
+	}
+
+	code, _ = serveHealth(t, New(nil, nil).ReadyHandler())
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("Ready without spec = %d, want %d", code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestPluginBuilder_HealthChecks(t *testing.T) {
+	mux := http.NewServeMux()
+	handler := WithSpec(createTestSpec()).HealthChecks().Mount(mux)
+
+	for _, path := range []string{DefaultHealthPath, DefaultReadyPath} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("%s status = %d, want %d", path, w.Code, http.StatusOK)
+		}
+	}
+}
+
+func TestOperationsHandler(t *testing.T) {
+	spec := createSecurityTestSpec()
+	spec.Paths["/users"].Get.Tags = []string{"users"}
+	handler := WithSpec(spec).OperationsEndpoint().Mount(http.NewServeMux())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, DefaultOperationsPath, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var index OperationIndex
+	if err := json.NewDecoder(w.Body).Decode(&index); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	want := []OperationInfo{
+		{Method: "GET", Path: "/health", Tags: []string{}, Security: []openapi.SecurityRequirement{}},
+		{OperationID: "listUsers", Method: "GET", Path: "/users", Tags: []string{"users"},
+			Security: []openapi.SecurityRequirement{{"apiKey": {}}, {"oauth": {"users:read"}}}},
+		{OperationID: "getUser", Method: "GET", Path: "/users/{id}", Tags: []string{},
+			Security: []openapi.SecurityRequirement{{"bearer": {}}}},
+	}
+	if index.Title != "Test API" || index.Version != "1.0.0" {
+		t.Errorf("Title/Version = %q/%q, want Test API/1.0.0", index.Title, index.Version)
+	}
+	if !reflect.DeepEqual(index.Operations, want) {
+		t.Errorf("Operations = %+v, want %+v", index.Operations, want)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, DefaultOperationsPath, nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func serveAdmin(handler http.Handler, method, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, DefaultAdminPath, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestAdminHandler_Rejects(t *testing.T) {
+	handler := WithSpec(createTestSpec()).Admin(AdminToken("secret")).Mount(http.NewServeMux())
+
+	if w := serveAdmin(handler, http.MethodGet, "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Unauthenticated status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if w := serveAdmin(handler, http.MethodGet, "wrong", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Wrong token status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if w := serveAdmin(handler, http.MethodDelete, "secret", ""); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE status = %d, want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+	if w := serveAdmin(handler, http.MethodPatch, "secret", "{"); w.Code != http.StatusBadRequest {
+		t.Errorf("Invalid body status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestAdminHandler(t *testing.T) {
+	var logs []string
+	handler := WithSpec(createTestSpec()).
+		EnableValidation().
+		WithLogger(func(format string, args ...any) { logs = append(logs, format) }).
+		Admin(AdminToken("secret")).
+		Mount(http.NewServeMux())
+
+	w := serveAdmin(handler, http.MethodPatch, "secret", `{"enable_validation": false, "strict_validation": true}`)
+	var cfg RuntimeConfig
+	if err := json.NewDecoder(w.Body).Decode(&cfg); err != nil {
+		t.Fatalf("Failed to decode config: %v", err)
+	}
+	if cfg.EnableValidation || !cfg.EnableLogging || !cfg.StrictValidation {
+		t.Errorf("Config = %+v, want validation off, logging on, strict on", cfg)
+	}
+	if !slices.ContainsFunc(logs, func(l string) bool { return strings.Contains(l, "runtime config updated") }) {
+		t.Error("Expected config change to be logged")
+	}
+}
+
+func TestAdminHandler_NoAuth(t *testing.T) {
+	plugin := New(createTestSpec(), &Options{AdminPath: DefaultAdminPath})
+	if w := serveAdmin(plugin.AdminHandler(), http.MethodGet, "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAdminHandler_TogglesValidation(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	plugin := New(createTestSpec(), &Options{
+		AdminPath: DefaultAdminPath,
+		AdminAuth: AdminToken("secret"),
+		Logger:    func(string, ...any) {},
+	})
+	handler := plugin.WrapMux(mux)
+
+	get := func(target string) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w.Code
+	}
+
+	if code := get("/users"); code != http.StatusOK {
+		t.Errorf("Validation disabled status = %d, want %d", code, http.StatusOK)
+	}
+
+	serveAdmin(handler, http.MethodPut, "secret", `{"enable_validation": true}`)
+	if code := get("/users"); code != http.StatusBadRequest {
+		t.Errorf("Validation enabled status = %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := get("/users?page=1&debug=1"); code != http.StatusOK {
+		t.Errorf("Non-strict status = %d, want %d", code, http.StatusOK)
+	}
+
+	serveAdmin(handler, http.MethodPut, "secret", `{"strict_validation": true}`)
+	if code := get("/users?page=1&debug=1"); code != http.StatusBadRequest {
+		t.Errorf("Strict status = %d, want %d", code, http.StatusBadRequest)
+	}
+	if !plugin.RuntimeConfig().StrictValidation {
+		t.Error("RuntimeConfig() should report strict validation")
+	}
+}
+
+func serveCompressed(t *testing.T, opts *CompressionOptions, contentType, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	handler := Compression(opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, body)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "br, gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestCompression(t *testing.T) {
+	large := `{"data":"` + strings.Repeat("a", 2048) + `"}`
+
+	w := serveCompressed(t, nil, "application/json", large)
+	if w.Code != http.StatusCreated {
+		t.Errorf("Status = %d, want %d", w.Code, http.StatusCreated)
+	}
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	gz, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	body, _ := io.ReadAll(gz)
+	if string(body) != large {
+		t.Error("Decompressed body does not match original")
+	}
+
+	if w := serveCompressed(t, nil, "application/json", `{"small":true}`); w.Header().Get("Content-Encoding") != "" {
+		t.Error("Responses below MinSize should not be compressed")
+	}
+	if w := serveCompressed(t, nil, "image/png", large); w.Header().Get("Content-Encoding") != "" {
+		t.Error("Content types outside the allowlist should not be compressed")
+	}
+	opts := &CompressionOptions{MinSize: 1, ContentTypes: []string{"image/"}, Level: gzip.BestSpeed}
+	if w := serveCompressed(t, opts, "image/png", "png"); w.Header().Get("Content-Encoding") != "gzip" {
+		t.Error("Custom allowlist should be honored")
+	}
+}
+
+func TestCompression_PartialOptions(t *testing.T) {
+	large := strings.Repeat("a", 2048)
+	w := serveCompressed(t, &CompressionOptions{MinSize: 100}, "application/json", large)
+
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatal("Expected the default allowlist to apply when ContentTypes is unset")
+	}
+	if w.Body.Len() >= len(large) {
+		t.Errorf("Compressed body is %d bytes, want fewer than %d with the default level", w.Body.Len(), len(large))
+	}
+	if w := serveCompressed(t, &CompressionOptions{Level: gzip.BestSpeed}, "application/json", strings.Repeat("a", 512)); w.Header().Get("Content-Encoding") != "" {
+		t.Error("Expected the default MinSize to apply when MinSize is unset")
+	}
+}
+
+func TestCompression_NotAccepted(t *testing.T) {
+	handler := Compression(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, strings.Repeat("a", 4096))
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Header().Get("Content-Encoding") != "" || w.Body.Len() != 4096 {
+		t.Error("Response should not be compressed without Accept-Encoding")
+	}
+	if w.Header().Get("Vary") != "Accept-Encoding" {
+		t.Error("Expected Vary: Accept-Encoding")
+	}
+}
+
+func TestDocumentCompression(t *testing.T) {
+	spec := createTestSpec()
+	spec.Paths["/users"].Get.Responses["200"].Content = map[string]openapi.MediaType{
+		"application/json": {Schema: openapi.ObjectSchema()},
+	}
+	WithSpec(spec).EnableCompression().Build()
+
+	headers := spec.Paths["/users"].Get.Responses["200"].Headers
+	if headers["Content-Encoding"] == nil {
+		t.Error("Expected Content-Encoding header to be documented")
+	}
+}
+
+func TestRouter(t *testing.T) {
+	spec := createTestSpec()
+	spec.Paths["/users/me"] = &openapi.PathItem{Get: &openapi.Operation{OperationID: "getMe"}}
+
+	var logged []string
+	router := WithSpec(spec).WithLogger(func(format string, args ...any) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}).Build().Router()
+	router.HandleFunc("getUser", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "user %s %s", PathParam(r, "id"), r.PathValue("id"))
+	})
+	router.HandleFunc("getMe", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("me"))
+	})
+
+	tests := []struct {
+		method   string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{method: http.MethodGet, path: "/users/42", wantCode: http.StatusOK, wantBody: "user 42 42"},
+		{method: http.MethodGet, path: "/users/me", wantCode: http.StatusOK, wantBody: "me"},
+		{method: http.MethodHead, path: "/users/me", wantCode: http.StatusOK, wantBody: "me"},
+		{method: http.MethodGet, path: "/users", wantCode: http.StatusNotImplemented},
+		{method: http.MethodPost, path: "/users/42", wantCode: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, path: "/orders", wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+		if w.Code != tt.wantCode {
+			t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, w.Code, tt.wantCode)
+		}
+		if !strings.Contains(w.Body.String(), tt.wantBody) {
+			t.Errorf("%s %s body = %q, want %q", tt.method, tt.path, w.Body.String(), tt.wantBody)
+		}
+		if tt.wantCode == http.StatusMethodNotAllowed && w.Header().Get("Allow") != "GET, HEAD" {
+			t.Errorf("Allow = %q, want GET, HEAD", w.Header().Get("Allow"))
+		}
+	}
+
+	if got := router.Unimplemented(); len(got) != 1 || got[0] != "listUsers" {
+		t.Errorf("Unimplemented() = %v, want [listUsers]", got)
+	}
+	if len(logged) != 1 || !strings.Contains(logged[0], "listUsers (GET /users)") {
+		t.Errorf("logged = %v, want one warning for listUsers", logged)
+	}
+}
+
+func TestRouter_HandlePanics(t *testing.T) {
+	router := NewRouter(createTestSpec())
+	router.HandleFunc("listUsers", func(http.ResponseWriter, *http.Request) {})
+
+	for _, id := range []string{"unknownOperation", "listUsers"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Handle(%q) should panic", id)
+				}
+			}()
+			router.HandleFunc(id, func(http.ResponseWriter, *http.Request) {})
+		}()
+	}
+}
+
+func TestRouteTrie(t *testing.T) {
+	item := &openapi.PathItem{Get: &openapi.Operation{}}
+	spec := &openapi.Document{Paths: openapi.Paths{}}
+	for _, path := range []string{
+		"/",
+		"/users",
+		"/users/me",
+		"/users/{id}",
+		"/users/{userId}/posts",
+		"/users/me/settings",
+		"/files/{name}.{ext}",
+		"/files/{name}",
+		"/v1.0/status",
+	} {
+		spec.Paths[path] = item
+	}
+	routes := compileRoutes(spec)
+
+	tests := []struct {
+		path       string
+		wantRoute  string
+		wantParams map[string]string
+	}{
+		{"/", "/", map[string]string{}},
+		{"/users", "/users", map[string]string{}},
+		{"/users/me", "/users/me", map[string]string{}},
+		{"/users/42", "/users/{id}", map[string]string{"id": "42"}},
+		{"/users/me/posts", "/users/{userId}/posts", map[string]string{"userId": "me"}},
+		{"/users/me/settings", "/users/me/settings", map[string]string{}},
+		{"/files/report.pdf", "/files/{name}.{ext}", map[string]string{"name": "report", "ext": "pdf"}},
+		{"/files/report", "/files/{name}", map[string]string{"name": "report"}},
+		{"/users/", "", nil},
+		{"/users//posts", "", nil},
+		{"/users/42/settings", "", nil},
+		{"/v1x0/status", "", nil},
+	}
+	for _, tt := range tests {
+		route, params := routes.match(tt.path)
+		if tt.wantRoute == "" {
+			if route != nil {
+				t.Errorf("match(%q) = %s, want none", tt.path, route.path)
+			}
+			continue
+		}
+		if route == nil || route.path != tt.wantRoute {
+			t.Errorf("match(%q) = %v, want %s", tt.path, route, tt.wantRoute)
+			continue
+		}
+		if !reflect.DeepEqual(params, tt.wantParams) {
+			t.Errorf("match(%q) params = %v, want %v", tt.path, params, tt.wantParams)
+		}
+	}
+}
+
+func BenchmarkRouteTrie(b *testing.B) {
+	spec := &openapi.Document{Paths: openapi.Paths{}}
+	for i := range 500 {
+		spec.Paths[fmt.Sprintf("/resources%d/{id}/items/{itemId}", i)] = &openapi.PathItem{Get: &openapi.Operation{}}
+	}
+	routes := compileRoutes(spec)
+	b.ReportAllocs()
+	for b.Loop() {
+		if route, _ := routes.match("/resources499/42/items/7"); route == nil {
+			b.Fatal("no match")
+		}
+	}
+}
+
+func TestStrictRouting(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("handled"))
+	})
+	handler := WithSpec(createTestSpec()).StrictRouting().HealthChecks().Mount(mux)
+
+	tests := []struct {
+		method   string
+		path     string
+		wantCode int
+	}{
+		{http.MethodGet, "/users?page=1", http.StatusOK},
+		{http.MethodGet, "/users/42", http.StatusOK},
+		{http.MethodHead, "/users/42", http.StatusOK},
+		{http.MethodGet, "/orders", http.StatusNotFound},
+		{http.MethodGet, "/users/42/posts", http.StatusNotFound},
+		{http.MethodDelete, "/users/42", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/openapi.json", http.StatusOK},
+		{http.MethodGet, "/docs", http.StatusOK},
+		{http.MethodGet, "/healthz", http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.wantCode {
+			t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, w.Code, tt.wantCode)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users", nil))
+	if got := w.Header().Get("Allow"); got != "GET, HEAD" {
+		t.Errorf("Allow = %q, want GET, HEAD", got)
+	}
+	var body struct {
+		Error   string            `json:"error"`
+		Details []ValidationError `json:"details"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Error != "Method Not Allowed" || len(body.Details) != 1 {
+		t.Errorf("body = %s, want a validation error shape", w.Body.String())
+	}
+}
+
+func TestStrictRouting_ErrorSchema(t *testing.T) {
+	spec := createTestSpec()
+	spec.Components = &openapi.Components{Schemas: map[string]*openapi.Schema{
+		"Problem": {
+			Type: openapi.NewSchemaType(openapi.TypeObject),
+			Properties: map[string]*openapi.Schema{
+				"status":  openapi.IntegerSchema(),
+				"title":   openapi.StringSchema(),
+				"detail":  openapi.StringSchema(),
+				"traceId": openapi.StringSchema(),
+			},
+		},
+		"Trace": {
+			Type:       openapi.NewSchemaType(openapi.TypeObject),
+			Properties: map[string]*openapi.Schema{"traceId": openapi.StringSchema()},
+		},
+	}}
+	handler := StrictRouting(spec, &StrictRoutingOptions{
+		ErrorSchema: "Problem",
+		Exempt:      func(r *http.Request) bool { return r.URL.Path == "/metrics" },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders", nil))
+	var body map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"status": float64(http.StatusNotFound),
+		"title":  "Not Found",
+		"detail": "path /orders is not declared in the spec",
+	}
+	if w.Code != http.StatusNotFound || !reflect.DeepEqual(body, want) {
+		t.Errorf("response = %d %v, want %d %v", w.Code, body, http.StatusNotFound, want)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("exempt request status = %d, want %d", w.Code, http.StatusOK)
+	}
+	// Without fields to fill, the body has the shape of validation errors
+	w = httptest.NewRecorder()
+	StrictRouting(spec, &StrictRoutingOptions{ErrorSchema: "Trace"})(nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders", nil))
+	if !strings.Contains(w.Body.String(), `"error":"Not Found"`) || strings.Contains(w.Body.String(), "traceId") {
+		t.Errorf("body = %s, want a validation error", w.Body.String())
+	}
+}
+
+func createSecurityTestSpec() *openapi.Document {
+	spec := createTestSpec()
+	spec.Security = []openapi.SecurityRequirement{{"bearer": {}}}
+	spec.Components = &openapi.Components{
+		SecuritySchemes: map[string]*openapi.SecurityScheme{
+			"bearer": {Type: "http", Scheme: "bearer"},
+			"apiKey": {Type: "apiKey", Name: "X-API-Key", In: "header"},
+			"oauth": {Type: "oauth2", Flows: &openapi.OAuthFlows{
+				ClientCredentials: &openapi.OAuthFlow{TokenURL: "https://auth.example.com/token"},
+			}},
+		},
+	}
+	spec.Paths["/users"].Get.Security = []openapi.SecurityRequirement{{"apiKey": {}}, {"oauth": {"users:read"}}}
+	spec.Paths["/health"] = &openapi.PathItem{Get: &openapi.Operation{Security: []openapi.SecurityRequirement{}}}
+	return spec
+}
+
+func TestSecurity(t *testing.T) {
+	verifier := TokenVerifierFunc(func(ctx context.Context, scheme, token string) (*Claims, error) {
+		if token != "good" {
+			return nil, errors.New("unknown token")
+		}
+		return &Claims{Subject: "ann", Scopes: []string{"profile"}}, nil
+	})
+
+	var subject string
+	handler := WithSpec(createSecurityTestSpec()).WithSecurity(&SecurityOptions{TokenVerifier: verifier}).
+		Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if claims := SecurityClaims(r); claims != nil {
+				subject = claims.Subject
+			}
+		}))
+
+	tests := []struct {
+		name          string
+		path          string
+		header        string
+		value         string
+		wantCode      int
+		wantChallenge string
+	}{
+		{name: "global bearer", path: "/users/1", header: "Authorization", value: "Bearer good", wantCode: http.StatusOK},
+		{name: "missing token", path: "/users/1", wantCode: http.StatusUnauthorized, wantChallenge: "Bearer"},
+		{name: "rejected token", path: "/users/1", header: "Authorization", value: "Bearer bad", wantCode: http.StatusUnauthorized},
+		{name: "api key alternative", path: "/users?page=1", header: "X-API-Key", value: "k", wantCode: http.StatusOK},
+		{name: "missing scope", path: "/users?page=1", header: "Authorization", value: "Bearer good", wantCode: http.StatusForbidden},
+		{name: "public operation", path: "/health", wantCode: http.StatusOK},
+		{name: "path not in spec", path: "/other", wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.wantCode, w.Body.String())
+			}
+			if got := w.Header().Get("WWW-Authenticate"); tt.wantChallenge != "" && got != tt.wantChallenge {
+				t.Errorf("WWW-Authenticate = %q, want %q", got, tt.wantChallenge)
+			}
+		})
+	}
+
+	if subject != "ann" {
+		t.Errorf("SecurityClaims().Subject = %q, want ann", subject)
+	}
+}
+
+func TestSecurity_Basic(t *testing.T) {
+	spec := createSecurityTestSpec()
+	spec.Components.SecuritySchemes["basic"] = &openapi.SecurityScheme{Type: "http", Scheme: "basic"}
+	spec.Security = []openapi.SecurityRequirement{{"basic": {}}}
+	validator := func(r *http.Request, scheme, username, password string) bool {
+		return username == "ann" && password == "secret"
+	}
+
+	tests := []struct {
+		name      string
+		validator func(r *http.Request, scheme, username, password string) bool
+		password  string
+		wantCode  int
+	}{
+		{name: "no validator", password: "secret", wantCode: http.StatusUnauthorized},
+		{name: "valid credentials", validator: validator, password: "secret", wantCode: http.StatusOK},
+		{name: "invalid credentials", validator: validator, password: "guess", wantCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Security(spec, &SecurityOptions{BasicValidator: tt.validator})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+			req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			req.SetBasicAuth("ann", tt.password)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSecurity_ErrorBody(t *testing.T) {
+	handler := Security(createSecurityTestSpec(), nil)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var body struct {
+		Error   string            `json:"error"`
+		Details []ValidationError `json:"details"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if w.Code != http.StatusUnauthorized || body.Error != "Unauthorized" {
+		t.Errorf("status, error = %d, %q, want 401, Unauthorized", w.Code, body.Error)
+	}
+	if len(body.Details) != 1 || body.Details[0].Field != "bearer" || !strings.Contains(body.Details[0].Message, "cannot be verified") {
+		t.Errorf("details = %+v, want unverifiable bearer token", body.Details)
+	}
+}
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	propagator := propagation.TraceContext{}
+
+	handler := WithSpec(createTestSpec()).
+		EnableValidation().
+		WithTracing(&TracingOptions{TracerProvider: provider, Propagator: propagator}).
+		Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	parent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set("traceparent", parent)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/other", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("spans = %d, want 3", len(spans))
+	}
+
+	getUser := spanAttributes(spans[0])
+	if spans[0].Name() != "getUser" || getUser["http.route"] != "/users/{id}" || getUser["http.response.status_class"] != "2xx" {
+		t.Errorf("span = %s %v, want getUser on /users/{id} with 2xx", spans[0].Name(), getUser)
+	}
+	if got := spans[0].Parent().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("parent trace ID = %s, want the propagated trace", got)
+	}
+
+	listUsers := spanAttributes(spans[1])
+	if listUsers["openapi.validation.failed"] != "true" || listUsers["http.response.status_class"] != "4xx" {
+		t.Errorf("listUsers attributes = %v, want validation failure and 4xx", listUsers)
+	}
+
+	if spans[2].Name() != http.MethodGet {
+		t.Errorf("unmatched span name = %q, want GET", spans[2].Name())
+	}
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[string]string {
+	attrs := make(map[string]string)
+	for _, kv := range span.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	return attrs
+}
+
+func TestCoverage(t *testing.T) {
+	spec := createTestSpec()
+	spec.Paths["/users"].Get.Responses["404"] = &openapi.Response{Description: "Not found"}
+	plugin := WithSpec(spec).EnableCoverage().EnableValidation().Build()
+
+	handler := plugin.Handler()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, target := range []string{"/users?page=1", "/users?page=2", "/users", "/unknown"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	report := plugin.Coverage().Report()
+	if got := fmt.Sprintf("%d/%d%% unmatched=%d", report.Covered(), report.Percent(), report.Unmatched); got != "1/50% unmatched=1" {
+		t.Errorf("covered/percent = %s, want 1/50%% unmatched=1", got)
+	}
+
+	listUsers := report.Operations[0]
+	if got := fmt.Sprintf("%s %d %v", listUsers, listUsers.Requests, listUsers.Statuses); got != "GET /users (listUsers) 3 map[200:2 400:1]" {
+		t.Errorf("listUsers = %s, want 3 requests with two 200 and one 400", got)
+	}
+	if !slices.Equal(listUsers.UntestedResponses, []string{"404"}) {
+		t.Errorf("UntestedResponses = %v, want [404]", listUsers.UntestedResponses)
+	}
+
+	plugin.Coverage().Reset()
+	if report := plugin.Coverage().Report(); report.Covered() != 0 || report.Unmatched != 0 {
+		t.Error("Reset() should discard recorded requests")
+	}
+}
+
+func TestCoverageReport_WriteText(t *testing.T) {
+	report := &CoverageReport{
+		Operations: []OperationCoverage{
+			{Method: "GET", Path: "/users", OperationID: "listUsers", Requests: 2, Statuses: map[int]int{200: 2}, UntestedResponses: []string{"404"}},
+			{Method: "GET", Path: "/users/{id}", OperationID: "getUser"},
+		},
+		Unmatched: 1,
+	}
+
+	var buf strings.Builder
+	if err := report.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	for _, want := range []string{"Operation coverage: 1/2 (50%)", "Requests outside the spec: 1", "Untested operations:\n  GET /users/{id} (getUser)", "GET /users (listUsers): 404"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("WriteText() missing %q in:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestOperationHeaders(t *testing.T) {
+	handler := WithSpec(createTestSpec()).EnableOperationHeaders().EnableValidation().
+		Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		target      string
+		operationID string
+	}{
+		{"/users/1", "getUser"},
+		{"/users", "listUsers"}, // rejected by validation, still tagged
+		{"/unknown", ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		if got := rec.Header().Get(HeaderOperationID); got != tt.operationID {
+			t.Errorf("%s: %s = %q, want %q", tt.target, HeaderOperationID, got, tt.operationID)
+		}
+		if got := rec.Header().Get(HeaderSpecVersion); got != "1.0.0" {
+			t.Errorf("%s: %s = %q, want 1.0.0", tt.target, HeaderSpecVersion, got)
+		}
+	}
+}
+
+func TestBranding(t *testing.T) {
+	plugin := WithSpec(createTestSpec()).WithBranding(&Branding{
+		LogoURL:      "javascript:alert(1)",
+		PrimaryColor: "#32329f",
+		FooterText:   "<b>Acme</b> API",
+		NavLinks:     []NavLink{{Label: "Status", URL: "https://status.example.com"}},
+		Language:     "id",
+	}).Build()
+
+	for name, handler := range map[string]http.Handler{
+		"swagger": plugin.SwaggerUIHandler(),
+		"redoc":   plugin.RedocHandler(),
+		"scalar":  plugin.ScalarHandler(),
+		"rapidoc": plugin.RapiDocHandler(),
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+		body := w.Body.String()
+		for _, want := range []string{
+			`<html lang="id">`,
+			`<a href="https://status.example.com">Status</a>`,
+			"&lt;b&gt;Acme&lt;/b&gt; API",
+			"--primary: #32329f",
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("%s page should contain %q", name, want)
+			}
+		}
+		if strings.Contains(body, "javascript:") {
+			t.Errorf("%s page should not contain the unsafe logo URL", name)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	plugin.RedocHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/redoc", nil))
+	if !strings.Contains(w.Body.String(), `"main":"#32329f"`) {
+		t.Error("ReDoc theme should use the primary color")
+	}
+
+	invalid := WithSpec(createTestSpec()).WithBranding(&Branding{PrimaryColor: "red;}"}).Build()
+	w = httptest.NewRecorder()
+	invalid.SwaggerUIHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("invalid branding status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
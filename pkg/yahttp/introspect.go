@@ -0,0 +1,99 @@
+package yahttp
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// DefaultOperationsPath is the default path for the operation metadata endpoint.
+const DefaultOperationsPath = "/__operations"
+
+// OperationInfo describes one operation of the served spec.
+type OperationInfo struct {
+	OperationID string   `json:"operationId,omitempty"`
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	Tags        []string `json:"tags"`
+
+	// Security lists the requirements in effect: the operation's own, else the
+	// spec's. An empty list means the operation is public.
+	Security []openapi.SecurityRequirement `json:"security"`
+
+	Deprecated bool `json:"deprecated,omitempty"`
+}
+
+// OperationIndex is the response of the operation metadata endpoint.
+type OperationIndex struct {
+	Title      string          `json:"title,omitempty"`
+	Version    string          `json:"version,omitempty"`
+	Operations []OperationInfo `json:"operations"`
+}
+
+// Operations returns the metadata of every operation in the spec, sorted by path and method.
+func (p *Plugin) Operations() []OperationInfo {
+	operations := []OperationInfo{}
+	spec := p.Spec()
+	if spec == nil {
+		return operations
+	}
+	for _, path := range slices.Sorted(maps.Keys(spec.Paths)) {
+		item := spec.Paths[path]
+		if item == nil {
+			continue
+		}
+		for _, method := range routerMethods {
+			if op := operationFor(item, method); op != nil {
+				operations = append(operations, operationInfo(spec, op, method, path))
+			}
+		}
+	}
+	return operations
+}
+
+func operationInfo(spec *openapi.Document, op *openapi.Operation, method, path string) OperationInfo {
+	info := OperationInfo{
+		OperationID: op.OperationID,
+		Method:      method,
+		Path:        path,
+		Tags:        op.Tags,
+		Security:    op.Security,
+		Deprecated:  op.Deprecated,
+	}
+	if info.Tags == nil {
+		info.Tags = []string{}
+	}
+	if info.Security == nil {
+		info.Security = spec.Security
+	}
+	if info.Security == nil {
+		info.Security = []openapi.SecurityRequirement{}
+	}
+	return info
+}
+
+// OperationsHandler returns a handler that lists the operationId, method,
+// path template, tags, and effective security requirements of every
+// operation, so gateways and dashboards can introspect the running contract
+// without parsing the full spec.
+func (p *Plugin) OperationsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		index := OperationIndex{Operations: p.Operations()}
+		if spec := p.Spec(); spec != nil {
+			index.Title = spec.Info.Title
+			index.Version = spec.Info.Version
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(index)
+	})
+}
@@ -3,6 +3,11 @@ package yahttp
 
 import (
 	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/fathurrohman26/yaswag/pkg/openapi"
 )
@@ -32,8 +37,14 @@ func Chain(middlewares ...Middleware) Middleware {
 
 // Plugin provides OpenAPI-aware HTTP middleware.
 type Plugin struct {
-	spec    *openapi.Document
+	served  atomic.Pointer[servedSpec]
+	named   map[string]*servedSpec
 	options *Options
+	runtime runtimeState
+	specMu  sync.Mutex // serializes spec updates
+
+	coverage     *Coverage
+	coverageOnce sync.Once
 }
 
 // Options configures the HTTP plugin behavior.
@@ -41,18 +52,90 @@ type Options struct {
 	// SpecPath is the path to serve the OpenAPI spec (default: "/openapi.json")
 	SpecPath string
 
-	// SwaggerUIPath is the path to serve Swagger UI (default: "/docs")
+	// Specs are further specifications, such as the versions of an API, served
+	// at SpecsPath/{name}.json and listed in a spec selector in Swagger UI (default: nil)
+	Specs []NamedSpec
+
+	// SpecsPath is the path prefix to serve the named specs under (default: "/openapi")
+	SpecsPath string
+
+	// SwaggerUIPath is the path to serve the documentation UI (default: "/docs")
 	SwaggerUIPath string
 
+	// DocsUI selects the documentation renderer served at SwaggerUIPath:
+	// swagger, redoc, scalar, or rapidoc (default: swagger)
+	DocsUI DocsUI
+
+	// SwaggerUIOptions configures the Swagger UI page served at SwaggerUIPath,
+	// e.g. to preconfigure OAuth or apply custom CSS (default: nil)
+	SwaggerUIOptions *SwaggerUIOptions
+
+	// RedocOptions configures the ReDoc page served at SwaggerUIPath when DocsUI is redoc
+	RedocOptions *RedocOptions
+
+	// Branding adds a logo, favicon, primary color, footer text, and navigation
+	// links to the documentation pages (default: nil, unbranded)
+	Branding *Branding
+
+	// OfflineAssets serves the documentation UIs from bundles embedded in the
+	// binary instead of a CDN, for environments without outbound network access (default: false)
+	OfflineAssets bool
+
+	// LazyTags serves per-tag slices of the spec at SpecPath?tag=name and has
+	// Swagger UI load one tag at a time, for specs with thousands of operations (default: false)
+	LazyTags bool
+
+	// SpecCacheControl is the Cache-Control header of spec responses, e.g. "no-store"
+	// for development environments where the spec changes often (default: "public, max-age=3600")
+	SpecCacheControl string
+
+	// SpecModTime is when the spec was generated, sent as Last-Modified so that
+	// clients can revalidate it with If-Modified-Since (default: when the plugin is created)
+	SpecModTime time.Time
+
+	// AssetsPath is the path to serve the embedded bundles (default: SwaggerUIPath + "/assets")
+	AssetsPath string
+
+	// HealthPath is the path to serve the liveness endpoint (default: "", disabled)
+	HealthPath string
+
+	// ReadyPath is the path to serve the readiness endpoint (default: "", disabled)
+	ReadyPath string
+
+	// OperationsPath is the path to serve the operation metadata endpoint (default: "", disabled)
+	OperationsPath string
+
 	// EnableValidation enables request validation (default: false)
 	EnableValidation bool
 
+	// StrictValidation rejects query parameters not declared in the spec (default: false)
+	StrictValidation bool
+
+	// MaxBodyBytes limits the size of the request bodies validation reads;
+	// larger bodies get 413 Request Entity Too Large (default: 10 MiB,
+	// negative for no limit)
+	MaxBodyBytes int64
+
+	// StrictRouting rejects requests for paths and methods not declared in the
+	// spec with 404 and 405 (default: false)
+	StrictRouting bool
+
+	// StrictRoutingOptions configures the error responses and exemptions of strict routing
+	StrictRoutingOptions *StrictRoutingOptions
+
 	// EnableCORS enables CORS headers (default: false)
 	EnableCORS bool
 
 	// CORSOptions configures CORS behavior
 	CORSOptions *CORSOptions
 
+	// EnableCompression enables gzip response compression (default: false).
+	// When enabled, compressible responses in the spec document a Content-Encoding header.
+	EnableCompression bool
+
+	// CompressionOptions configures compression behavior
+	CompressionOptions *CompressionOptions
+
 	// EnableLogging enables request logging (default: false)
 	EnableLogging bool
 
@@ -61,6 +144,37 @@ type Options struct {
 
 	// ValidationErrorHandler handles validation errors
 	ValidationErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+	// EnableTracing starts an OpenTelemetry span per request, named after the operationId (default: false)
+	EnableTracing bool
+
+	// TracingOptions configures the tracer provider and propagator
+	TracingOptions *TracingOptions
+
+	// EnableSecurity enforces each operation's security requirements (default: false)
+	EnableSecurity bool
+
+	// SecurityOptions configures credential verification and error responses
+	SecurityOptions *SecurityOptions
+
+	// EnableOperationHeaders sets X-Operation-Id and X-Spec-Version on every response (default: false)
+	EnableOperationHeaders bool
+
+	// EnableCoverage records the operations and response statuses exercised,
+	// reported by Plugin.Coverage (default: false)
+	EnableCoverage bool
+
+	// AdminPath is the path to serve the runtime configuration endpoint (default: "", disabled).
+	// When set, validation and logging can be toggled at runtime.
+	AdminPath string
+
+	// AdminAuth authorizes requests to the admin endpoint; all requests are rejected if nil
+	AdminAuth func(r *http.Request) bool
+
+	// OnSpecChange is called with the new spec after UpdateSpec, AddOperation,
+	// or RemoveOperation changes it, one change at a time, so it must not
+	// change the spec itself (default: nil)
+	OnSpecChange func(spec *openapi.Document)
 }
 
 // DefaultOptions returns default plugin options.
@@ -79,15 +193,30 @@ func New(spec *openapi.Document, opts *Options) *Plugin {
 	if opts == nil {
 		opts = DefaultOptions()
 	}
-	return &Plugin{
-		spec:    spec,
-		options: opts,
+	modTime := opts.SpecModTime
+	if modTime.IsZero() {
+		modTime = time.Now()
+	}
+	p := &Plugin{options: opts, named: make(map[string]*servedSpec)}
+	p.served.Store(newServedSpec(spec, modTime))
+	for _, named := range opts.Specs {
+		if _, ok := p.named[named.Name]; !ok {
+			p.named[named.Name] = newServedSpec(named.Spec, modTime)
+		}
+	}
+	p.runtime.validation.Store(opts.EnableValidation)
+	p.runtime.logging.Store(opts.EnableLogging)
+	p.runtime.strict.Store(opts.StrictValidation)
+	if opts.EnableCompression {
+		DocumentCompression(spec, opts.CompressionOptions)
 	}
+	p.warnMissingAssets()
+	return p
 }
 
 // Spec returns the OpenAPI specification.
 func (p *Plugin) Spec() *openapi.Document {
-	return p.spec
+	return p.served.Load().doc
 }
 
 // Options returns the plugin options.
@@ -96,19 +225,34 @@ func (p *Plugin) Options() *Options {
 }
 
 // Handler returns a middleware chain based on the configured options.
+// When the admin endpoint is enabled, logging and validation are always
+// installed and gated by the runtime configuration.
 func (p *Plugin) Handler() Middleware {
-	var middlewares []Middleware
-
-	if p.options.EnableLogging {
-		middlewares = append(middlewares, p.LoggingMiddleware())
-	}
-
-	if p.options.EnableCORS {
-		middlewares = append(middlewares, p.CORSMiddleware())
+	toggleable := p.options.AdminPath != ""
+	stages := []struct {
+		enabled    bool
+		middleware func() Middleware
+	}{
+		{p.options.EnableTracing, p.TracingMiddleware},
+		{p.options.EnableCoverage, p.CoverageMiddleware},
+		{p.options.EnableOperationHeaders, p.OperationHeadersMiddleware},
+		{p.options.EnableLogging || toggleable, func() Middleware {
+			return toggle(&p.runtime.logging, p.LoggingMiddleware())
+		}},
+		{p.options.EnableCORS, p.CORSMiddleware},
+		{p.options.EnableCompression, p.CompressionMiddleware},
+		{p.options.StrictRouting, p.StrictRoutingMiddleware},
+		{p.options.EnableSecurity, p.SecurityMiddleware},
+		{p.options.EnableValidation || toggleable, func() Middleware {
+			return toggle(&p.runtime.validation, p.ValidationMiddleware())
+		}},
 	}
 
-	if p.options.EnableValidation {
-		middlewares = append(middlewares, p.ValidationMiddleware())
+	var middlewares []Middleware
+	for _, stage := range stages {
+		if stage.enabled {
+			middlewares = append(middlewares, stage.middleware())
+		}
 	}
 
 	if len(middlewares) == 0 {
@@ -118,15 +262,55 @@ func (p *Plugin) Handler() Middleware {
 	return Chain(middlewares...)
 }
 
-// Mount mounts the OpenAPI spec and Swagger UI handlers on the given mux.
+// Mount mounts the OpenAPI spec, named specs, documentation UI, embedded assets, health, operations, and admin handlers on the given mux.
 func (p *Plugin) Mount(mux *http.ServeMux) {
 	if p.options.SpecPath != "" {
 		mux.Handle(p.options.SpecPath, p.SpecHandler())
 	}
+	if len(p.options.Specs) > 0 {
+		mux.Handle(p.SpecsPath()+"/", p.NamedSpecHandler())
+	}
 	if p.options.SwaggerUIPath != "" {
-		mux.Handle(p.options.SwaggerUIPath, p.SwaggerUIHandler())
-		mux.Handle(p.options.SwaggerUIPath+"/", p.SwaggerUIHandler())
+		mux.Handle(p.options.SwaggerUIPath, p.DocsHandler())
+		mux.Handle(p.options.SwaggerUIPath+"/", p.DocsHandler())
+	}
+	if p.servesAssets() {
+		mux.Handle(p.AssetsPath()+"/", p.AssetsHandler())
+	}
+	if p.options.HealthPath != "" {
+		mux.Handle(p.options.HealthPath, p.HealthHandler())
+	}
+	if p.options.ReadyPath != "" {
+		mux.Handle(p.options.ReadyPath, p.ReadyHandler())
+	}
+	if p.options.OperationsPath != "" {
+		mux.Handle(p.options.OperationsPath, p.OperationsHandler())
+	}
+	if p.options.AdminPath != "" {
+		mux.Handle(p.options.AdminPath, p.AdminHandler())
+	}
+}
+
+// ownsPath reports whether Mount serves path, so that strict routing lets the
+// spec, documentation, and operational endpoints through.
+func (p *Plugin) ownsPath(path string) bool {
+	o := p.options
+	for _, exact := range []string{o.SpecPath, o.SwaggerUIPath, o.HealthPath, o.ReadyPath, o.OperationsPath, o.AdminPath} {
+		if exact != "" && path == exact {
+			return true
+		}
+	}
+	var prefixes []string
+	if o.SwaggerUIPath != "" {
+		prefixes = append(prefixes, o.SwaggerUIPath+"/")
+	}
+	if len(o.Specs) > 0 {
+		prefixes = append(prefixes, p.SpecsPath()+"/")
+	}
+	if p.servesAssets() {
+		prefixes = append(prefixes, p.AssetsPath()+"/")
 	}
+	return slices.ContainsFunc(prefixes, func(prefix string) bool { return strings.HasPrefix(path, prefix) })
 }
 
 // WrapMux wraps an existing ServeMux with the plugin middleware and mounts spec handlers.
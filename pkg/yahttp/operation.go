@@ -0,0 +1,45 @@
+package yahttp
+
+import (
+	"net/http"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// Response headers set by the operation headers middleware.
+const (
+	HeaderOperationID = "X-Operation-Id"
+	HeaderSpecVersion = "X-Spec-Version"
+)
+
+// OperationHeadersMiddleware returns a middleware that tags responses with the matched operation.
+func (p *Plugin) OperationHeadersMiddleware() Middleware {
+	return p.followSpec(OperationHeaders)
+}
+
+// OperationHeaders returns a standalone middleware that sets X-Operation-Id to
+// the operationId matching the request and X-Spec-Version to the spec's
+// info.version on every response, so clients can correlate what they received
+// with the documented contract. Requests matching no operation, or an
+// operation without an operationId, get only the version header.
+func OperationHeaders(spec *openapi.Document) Middleware {
+	routes := compileRoutes(spec)
+	version := ""
+	if spec != nil {
+		version = spec.Info.Version
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if version != "" {
+				w.Header().Set(HeaderSpecVersion, version)
+			}
+			if matcher, _ := routes.match(r.URL.Path); matcher != nil {
+				if op, _ := requestOperation(matcher.pathItem, r.Method); op != nil && op.OperationID != "" {
+					w.Header().Set(HeaderOperationID, op.OperationID)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package yahttp
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// params holds the parameters of a validated request, converted to the Go type
+// of their schema: int64 for integer, float64 for number, bool for boolean,
+// and string otherwise. Array query parameters hold slices of the type of
+// their items, and object query parameters map[string]any.
+type params struct {
+	values map[openapi.ParameterLocation]map[string]any
+}
+
+type paramsKey struct{}
+
+func newParams() *params {
+	return &params{values: make(map[openapi.ParameterLocation]map[string]any)}
+}
+
+func (p *params) set(in openapi.ParameterLocation, name string, value any) {
+	if p.values[in] == nil {
+		p.values[in] = make(map[string]any)
+	}
+	p.values[in][name] = value
+}
+
+func withParams(ctx context.Context, p *params) context.Context {
+	return context.WithValue(ctx, paramsKey{}, p)
+}
+
+// convertParam converts a raw parameter value that passed validation to the Go type of its schema.
+func convertParam(value string, schema *openapi.Schema) any {
+	if schema == nil || len(schema.Type) == 0 {
+		return value
+	}
+	switch schema.Type[0] {
+	case openapi.TypeInteger:
+		n, _ := strconv.ParseInt(value, 10, 64)
+		return n
+	case openapi.TypeNumber:
+		f, _ := strconv.ParseFloat(value, 64)
+		return f
+	case openapi.TypeBoolean:
+		return value == "true" || value == "1"
+	default:
+		return value
+	}
+}
+
+// convertItems converts the items of an array parameter that passed validation
+// to a slice of the Go type of the item schema.
+func convertItems(items []string, schema *openapi.Schema) any {
+	if schema == nil || len(schema.Type) == 0 {
+		return items
+	}
+	switch schema.Type[0] {
+	case openapi.TypeInteger:
+		return convertSlice[int64](items, schema)
+	case openapi.TypeNumber:
+		return convertSlice[float64](items, schema)
+	case openapi.TypeBoolean:
+		return convertSlice[bool](items, schema)
+	default:
+		return items
+	}
+}
+
+func convertSlice[T any](items []string, schema *openapi.Schema) []T {
+	converted := make([]T, len(items))
+	for i, item := range items {
+		converted[i], _ = convertParam(item, schema).(T)
+	}
+	return converted
+}
+
+// Param returns the converted value of a parameter checked by the validation
+// middleware. It reports false if the request was not validated, the parameter
+// was absent, or it is not declared on the operation.
+func Param(r *http.Request, in openapi.ParameterLocation, name string) (any, bool) {
+	p, ok := r.Context().Value(paramsKey{}).(*params)
+	if !ok {
+		return nil, false
+	}
+	value, ok := p.values[in][name]
+	return value, ok
+}
+
+// typedParam returns the parameter value if it was converted to type T.
+func typedParam[T any](r *http.Request, in openapi.ParameterLocation, name string) (T, bool) {
+	value, _ := Param(r, in, name)
+	typed, ok := value.(T)
+	return typed, ok
+}
+
+// PathString returns a validated path parameter declared with a string schema.
+func PathString(r *http.Request, name string) (string, bool) {
+	return typedParam[string](r, openapi.ParameterInPath, name)
+}
+
+// PathInt64 returns a validated path parameter declared with an integer schema.
+func PathInt64(r *http.Request, name string) (int64, bool) {
+	return typedParam[int64](r, openapi.ParameterInPath, name)
+}
+
+// PathFloat64 returns a validated path parameter declared with a number schema.
+func PathFloat64(r *http.Request, name string) (float64, bool) {
+	return typedParam[float64](r, openapi.ParameterInPath, name)
+}
+
+// PathBool returns a validated path parameter declared with a boolean schema.
+func PathBool(r *http.Request, name string) (bool, bool) {
+	return typedParam[bool](r, openapi.ParameterInPath, name)
+}
+
+// QueryString returns a validated query parameter declared with a string schema.
+func QueryString(r *http.Request, name string) (string, bool) {
+	return typedParam[string](r, openapi.ParameterInQuery, name)
+}
+
+// QueryInt64 returns a validated query parameter declared with an integer schema.
+func QueryInt64(r *http.Request, name string) (int64, bool) {
+	return typedParam[int64](r, openapi.ParameterInQuery, name)
+}
+
+// QueryFloat64 returns a validated query parameter declared with a number schema.
+func QueryFloat64(r *http.Request, name string) (float64, bool) {
+	return typedParam[float64](r, openapi.ParameterInQuery, name)
+}
+
+// QueryBool returns a validated query parameter declared with a boolean schema.
+func QueryBool(r *http.Request, name string) (bool, bool) {
+	return typedParam[bool](r, openapi.ParameterInQuery, name)
+}
+
+// QueryStrings returns a validated array query parameter declared with string items.
+func QueryStrings(r *http.Request, name string) ([]string, bool) {
+	return typedParam[[]string](r, openapi.ParameterInQuery, name)
+}
+
+// QueryInt64s returns a validated array query parameter declared with integer items.
+func QueryInt64s(r *http.Request, name string) ([]int64, bool) {
+	return typedParam[[]int64](r, openapi.ParameterInQuery, name)
+}
+
+// QueryFloat64s returns a validated array query parameter declared with number items.
+func QueryFloat64s(r *http.Request, name string) ([]float64, bool) {
+	return typedParam[[]float64](r, openapi.ParameterInQuery, name)
+}
+
+// QueryBools returns a validated array query parameter declared with boolean items.
+func QueryBools(r *http.Request, name string) ([]bool, bool) {
+	return typedParam[[]bool](r, openapi.ParameterInQuery, name)
+}
+
+// QueryObject returns a validated object query parameter, its properties
+// converted to the Go type of their schemas.
+func QueryObject(r *http.Request, name string) (map[string]any, bool) {
+	return typedParam[map[string]any](r, openapi.ParameterInQuery, name)
+}
@@ -0,0 +1,121 @@
+package yahttp
+
+import (
+	"regexp"
+	"strings"
+)
+
+// pathParamPattern matches the parameters of a path template, such as {id}.
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// routeTrie matches request paths against the path templates of a spec, one
+// segment at a time, so that matching takes time proportional to the length
+// of the path rather than the number of templates.
+//
+// At each segment, a static segment takes precedence over a segment mixing
+// text and parameters, such as {name}.json, which takes precedence over a
+// segment that is a single parameter. When the more specific branch does not
+// lead to a template, matching falls back to the next one, so /users/me
+// matches /users/me and /users/me/posts matches /users/{id}/posts.
+type routeTrie struct {
+	sorted []*pathMatcher // most specific first
+	root   trieNode
+}
+
+type trieNode struct {
+	static   map[string]*trieNode
+	patterns []*trieNode // children for segments mixing text and parameters
+	param    *trieNode   // child for a segment that is a single parameter
+	route    *pathMatcher
+
+	shape string         // of a pattern child, its segment with {} for each parameter
+	regex *regexp.Regexp // of a pattern child, capturing its parameters
+}
+
+// newRouteTrie indexes routes, which are sorted most specific first. Of
+// templates differing only in the names of their parameters, the first wins.
+func newRouteTrie(routes []*pathMatcher) *routeTrie {
+	t := &routeTrie{sorted: routes}
+	for _, route := range routes {
+		node := &t.root
+		for _, segment := range strings.Split(route.path, "/") {
+			node = node.child(segment)
+		}
+		if node.route == nil {
+			node.route = route
+		}
+	}
+	return t
+}
+
+// child returns the child of n for the template segment, adding it if needed.
+func (n *trieNode) child(segment string) *trieNode {
+	switch {
+	case !strings.Contains(segment, "{"):
+		if n.static == nil {
+			n.static = make(map[string]*trieNode)
+		}
+		if n.static[segment] == nil {
+			n.static[segment] = &trieNode{}
+		}
+		return n.static[segment]
+	case pathParamPattern.FindString(segment) == segment:
+		if n.param == nil {
+			n.param = &trieNode{}
+		}
+		return n.param
+	}
+
+	shape := pathParamPattern.ReplaceAllString(segment, "{}")
+	for _, child := range n.patterns {
+		if child.shape == shape {
+			return child
+		}
+	}
+	parts := strings.Split(shape, "{}")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	child := &trieNode{shape: shape, regex: regexp.MustCompile("^" + strings.Join(parts, "([^/]+)") + "$")}
+	n.patterns = append(n.patterns, child)
+	return child
+}
+
+// match returns the route matching path and the path parameters it captures.
+func (t *routeTrie) match(path string) (*pathMatcher, map[string]string) {
+	route, values := t.root.match(strings.Split(path, "/"), nil)
+	if route == nil {
+		return nil, nil
+	}
+	params := make(map[string]string, len(route.paramKeys))
+	for i, key := range route.paramKeys {
+		params[key] = values[i]
+	}
+	return route, params
+}
+
+// match returns the route of the subtree of n matching segments, along with
+// values and the parameter values captured on the way.
+func (n *trieNode) match(segments, values []string) (*pathMatcher, []string) {
+	if len(segments) == 0 {
+		return n.route, values
+	}
+	segment, rest := segments[0], segments[1:]
+
+	if child := n.static[segment]; child != nil {
+		if route, captured := child.match(rest, values); route != nil {
+			return route, captured
+		}
+	}
+	for _, child := range n.patterns {
+		if m := child.regex.FindStringSubmatch(segment); m != nil {
+			if route, captured := child.match(rest, append(values, m[1:]...)); route != nil {
+				return route, captured
+			}
+		}
+	}
+	if n.param != nil && segment != "" {
+		return n.param.match(rest, append(values, segment))
+	}
+	return nil, nil
+}
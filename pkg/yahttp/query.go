@@ -0,0 +1,183 @@
+package yahttp
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// queryDelimiters are the item delimiters of the non-exploded query styles.
+var queryDelimiters = map[string]string{
+	"form":           ",",
+	"spaceDelimited": " ",
+	"pipeDelimited":  "|",
+}
+
+// queryStyle returns the style of a query parameter and whether it is
+// exploded, defaulting to form, which defaults to exploded.
+func queryStyle(param *openapi.Parameter) (string, bool) {
+	style := param.Style
+	if style == "" {
+		style = "form"
+	}
+	explode := style == "form"
+	if param.Explode != nil {
+		explode = *param.Explode
+	}
+	return style, explode
+}
+
+// collectionType returns the type of schema if it is array or object, the
+// types of query parameters that span several values or keys.
+func collectionType(schema *openapi.Schema) string {
+	if schema == nil || len(schema.Type) == 0 {
+		return ""
+	}
+	if t := schema.Type[0]; t == openapi.TypeArray || t == openapi.TypeObject {
+		return t
+	}
+	return ""
+}
+
+// queryArray returns the items of an array query parameter: the values of
+// the repeated key when exploded (status=a&status=b), or else the value
+// split at the delimiter of the style (status=a,b or status=a|b).
+func queryArray(query url.Values, name, style string, explode bool) ([]string, bool) {
+	values, found := query[name]
+	switch {
+	case !found:
+		return nil, false
+	case explode:
+		return values, true
+	case values[0] == "":
+		return []string{}, true
+	}
+	delimiter, ok := queryDelimiters[style]
+	if !ok {
+		delimiter = ","
+	}
+	return strings.Split(values[0], delimiter), true
+}
+
+// queryObject returns the properties of an object query parameter: the keys
+// name[property] for deepObject (filter[status]=a), the properties of schema
+// as keys of their own when exploded (status=a&limit=1), or else the value
+// split at the delimiter of the style into name-value pairs (filter=status,a).
+func queryObject(query url.Values, name, style string, explode bool, schema *openapi.Schema) (map[string]string, bool, error) {
+	props := make(map[string]string)
+	switch {
+	case style == "deepObject":
+		for key, values := range query {
+			if prop, ok := strings.CutPrefix(key, name+"["); ok && strings.HasSuffix(prop, "]") {
+				props[strings.TrimSuffix(prop, "]")] = values[0]
+			}
+		}
+		return props, len(props) > 0, nil
+	case explode:
+		for prop := range schema.Properties {
+			if query.Has(prop) {
+				props[prop] = query.Get(prop)
+			}
+		}
+		return props, len(props) > 0, nil
+	}
+
+	items, found := queryArray(query, name, style, false)
+	if len(items)%2 != 0 {
+		return nil, true, errors.New("must be a list of name and value pairs")
+	}
+	for i := 0; i < len(items); i += 2 {
+		props[items[i]] = items[i+1]
+	}
+	return props, found, nil
+}
+
+// validateQueryCollection validates an array or object query parameter,
+// item by item or property by property, and returns its converted value.
+func (v *requestValidator) validateQueryCollection(query url.Values, param *openapi.Parameter, schema *openapi.Schema) (ValidationErrors, any, bool) {
+	var errs ValidationErrors
+	var value any
+	var found bool
+	if collectionType(schema) == openapi.TypeArray {
+		errs, value, found = v.validateQueryArray(query, param, schema)
+	} else {
+		errs, value, found = v.validateQueryObject(query, param, schema)
+	}
+	if param.Required && !found {
+		return ValidationErrors{{Field: param.Name, Message: "required parameter is missing", In: string(param.In)}}, nil, false
+	}
+	return errs, value, found
+}
+
+// validateQueryArray validates the item count and the items of an array
+// query parameter.
+func (v *requestValidator) validateQueryArray(query url.Values, param *openapi.Parameter, schema *openapi.Schema) (ValidationErrors, any, bool) {
+	style, explode := queryStyle(param)
+	in := string(param.In)
+	var errs ValidationErrors
+	items, found := queryArray(query, param.Name, style, explode)
+	if found && schema.MinItems != nil && int64(len(items)) < *schema.MinItems {
+		errs = append(errs, ValidationError{Field: param.Name, Message: fmt.Sprintf("must have at least %d items", *schema.MinItems), In: in})
+	}
+	if schema.MaxItems != nil && int64(len(items)) > *schema.MaxItems {
+		errs = append(errs, ValidationError{Field: param.Name, Message: fmt.Sprintf("must have at most %d items", *schema.MaxItems), In: in})
+	}
+	itemSchema := v.resolveSchema(schema.Items)
+	for i, item := range items {
+		if err := v.validateValue(item, itemSchema, fmt.Sprintf("%s[%d]", param.Name, i), in); err != nil {
+			errs = append(errs, *err)
+		}
+	}
+	return errs, convertItems(items, itemSchema), found
+}
+
+// validateQueryObject validates the properties of an object query parameter
+// and that its required properties are present.
+func (v *requestValidator) validateQueryObject(query url.Values, param *openapi.Parameter, schema *openapi.Schema) (ValidationErrors, any, bool) {
+	style, explode := queryStyle(param)
+	in := string(param.In)
+	props, found, err := queryObject(query, param.Name, style, explode, schema)
+	if err != nil {
+		return ValidationErrors{{Field: param.Name, Message: err.Error(), In: in}}, nil, true
+	}
+	var errs ValidationErrors
+	object := make(map[string]any, len(props))
+	for _, prop := range slices.Sorted(maps.Keys(props)) {
+		propSchema := v.resolveSchema(schema.Properties[prop])
+		if err := v.validateValue(props[prop], propSchema, param.Name+"["+prop+"]", in); err != nil {
+			errs = append(errs, *err)
+		}
+		object[prop] = convertParam(props[prop], propSchema)
+	}
+	for _, prop := range schema.Required {
+		if _, ok := props[prop]; !ok && found {
+			errs = append(errs, ValidationError{Field: param.Name + "[" + prop + "]", Message: "required property is missing", In: in})
+		}
+	}
+	return errs, object, found
+}
+
+// declaresQueryKey reports whether the query key belongs to param, such as
+// filter[status] to a deepObject parameter filter.
+func (v *requestValidator) declaresQueryKey(param *openapi.Parameter, key string) bool {
+	if key == param.Name {
+		return true
+	}
+	schema := v.resolveSchema(param.Schema)
+	if collectionType(schema) != openapi.TypeObject {
+		return false
+	}
+	switch style, explode := queryStyle(param); {
+	case style == "deepObject":
+		return strings.HasPrefix(key, param.Name+"[") && strings.HasSuffix(key, "]")
+	case explode:
+		_, ok := schema.Properties[key]
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+package yahttp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// routerMethods lists the HTTP methods a path item can declare operations for.
+var routerMethods = []string{
+	http.MethodDelete,
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodPatch,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodTrace,
+}
+
+// Router dispatches requests to handlers registered by operationId, matching
+// paths and methods against the spec.
+//
+// Requests for paths not in the spec get 404, methods not declared for a path
+// get 405 with an Allow header, and declared operations without a handler get
+// 501. Static path segments match before templated ones, so /users/me takes
+// precedence over /users/{id}. Path parameters are available through
+// PathParam and r.PathValue.
+type Router struct {
+	routes     *routeTrie
+	operations map[string]string
+	handlers   map[string]http.Handler
+	logger     func(format string, args ...any)
+	warnOnce   sync.Once
+
+	// NotFound handles requests whose path is not in the spec (default: http.NotFound)
+	NotFound http.Handler
+}
+
+type pathParamsKey struct{}
+
+// NewRouter creates a router for the operations declared in spec.
+func NewRouter(spec *openapi.Document) *Router {
+	rt := &Router{
+		operations: make(map[string]string),
+		handlers:   make(map[string]http.Handler),
+		logger:     log.Printf,
+	}
+	rt.routes = compileRoutes(spec)
+	for _, route := range rt.routes.sorted {
+		for _, method := range routerMethods {
+			if op := operationFor(route.pathItem, method); op != nil {
+				rt.operations[operationKey(op, method, route.path)] = method + " " + route.path
+			}
+		}
+	}
+	return rt
+}
+
+// compileRoutes compiles the paths of spec into a trie of matchers.
+func compileRoutes(spec *openapi.Document) *routeTrie {
+	var routes []*pathMatcher
+	if spec != nil {
+		for path, item := range spec.Paths {
+			if item != nil {
+				routes = append(routes, compilePath(path, item))
+			}
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		return moreSpecific(routes[i], routes[j])
+	})
+	return newRouteTrie(routes)
+}
+
+// Router creates a spec-driven router that logs through the plugin's logger.
+func (p *Plugin) Router() *Router {
+	rt := NewRouter(p.Spec())
+	if p.options.Logger != nil {
+		rt.logger = p.options.Logger
+	}
+	return rt
+}
+
+// Handle registers the handler for the operation with the given operationId.
+// Operations without an operationId are registered as "METHOD /path".
+// It panics if the operation is not in the spec or already has a handler.
+func (rt *Router) Handle(operationID string, handler http.Handler) {
+	if _, ok := rt.operations[operationID]; !ok {
+		panic(fmt.Sprintf("yahttp: operation %q not found in spec", operationID))
+	}
+	if _, ok := rt.handlers[operationID]; ok {
+		panic(fmt.Sprintf("yahttp: multiple registrations for operation %q", operationID))
+	}
+	rt.handlers[operationID] = handler
+}
+
+// HandleFunc registers the handler function for the operation with the given operationId.
+func (rt *Router) HandleFunc(operationID string, handler func(http.ResponseWriter, *http.Request)) {
+	rt.Handle(operationID, http.HandlerFunc(handler))
+}
+
+// Unimplemented returns the operations in the spec without a registered handler, sorted.
+func (rt *Router) Unimplemented() []string {
+	var missing []string
+	for id := range rt.operations {
+		if _, ok := rt.handlers[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// ServeHTTP dispatches the request to the handler of the matching operation.
+// Unimplemented operations are logged once, when the router serves its first request.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt.warnOnce.Do(rt.warnUnimplemented)
+
+	matcher, params := rt.routes.match(r.URL.Path)
+	if matcher == nil {
+		rt.notFound(w, r)
+		return
+	}
+
+	op, method := requestOperation(matcher.pathItem, r.Method)
+	if op == nil {
+		w.Header().Set("Allow", strings.Join(allowedMethods(matcher.pathItem), ", "))
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	handler, ok := rt.handlers[operationKey(op, method, matcher.path)]
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotImplemented), http.StatusNotImplemented)
+		return
+	}
+
+	r = r.WithContext(context.WithValue(r.Context(), pathParamsKey{}, params))
+	for name, value := range params {
+		r.SetPathValue(name, value)
+	}
+	handler.ServeHTTP(w, r)
+}
+
+func (rt *Router) notFound(w http.ResponseWriter, r *http.Request) {
+	if rt.NotFound != nil {
+		rt.NotFound.ServeHTTP(w, r)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+func (rt *Router) warnUnimplemented() {
+	for _, id := range rt.Unimplemented() {
+		rt.logger("yahttp: operation %s (%s) has no handler", id, rt.operations[id])
+	}
+}
+
+// PathParam returns the value of a path parameter captured by the Router.
+func PathParam(r *http.Request, name string) string {
+	return PathParams(r)[name]
+}
+
+// PathParams returns all path parameters captured by the Router.
+func PathParams(r *http.Request) map[string]string {
+	params, _ := r.Context().Value(pathParamsKey{}).(map[string]string)
+	return params
+}
+
+// operationKey identifies an operation by operationId, or by "METHOD /path" if it has none.
+func operationKey(op *openapi.Operation, method, path string) string {
+	if op.OperationID != "" {
+		return op.OperationID
+	}
+	return method + " " + path
+}
+
+// allowedMethods returns the methods requests for item may use, including
+// HEAD if item declares GET.
+func allowedMethods(item *openapi.PathItem) []string {
+	var methods []string
+	for _, method := range routerMethods {
+		if op, _ := requestOperation(item, method); op != nil {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+// moreSpecific orders concrete paths before templated ones, so /users/me
+// matches before /users/{id}.
+func moreSpecific(a, b *pathMatcher) bool {
+	if len(a.paramKeys) != len(b.paramKeys) {
+		return len(a.paramKeys) < len(b.paramKeys)
+	}
+	return a.path < b.path
+}
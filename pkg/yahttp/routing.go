@@ -0,0 +1,178 @@
+package yahttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// DefaultErrorSchema is the component schema strict routing shapes its error
+// responses after, when the spec defines it.
+const DefaultErrorSchema = "Error"
+
+// StrictRoutingOptions configures strict routing.
+type StrictRoutingOptions struct {
+	// ErrorSchema names the component schema the bodies of 404 and 405
+	// responses follow (default: "Error"), with its conventional fields such
+	// as code and message. Without such a schema or fields, they have the
+	// shape of validation errors.
+	ErrorSchema string
+
+	// Exempt reports whether to pass a request through although the spec
+	// does not describe it, such as for a metrics endpoint (default: nil)
+	Exempt func(r *http.Request) bool
+}
+
+// StrictRoutingMiddleware returns a middleware that rejects requests not described in the spec.
+// The spec, documentation, health, operations, and admin endpoints of the plugin pass through.
+func (p *Plugin) StrictRoutingMiddleware() Middleware {
+	opts := StrictRoutingOptions{}
+	if p.options.StrictRoutingOptions != nil {
+		opts = *p.options.StrictRoutingOptions
+	}
+	exempt := opts.Exempt
+	opts.Exempt = func(r *http.Request) bool {
+		return p.ownsPath(r.URL.Path) || exempt != nil && exempt(r)
+	}
+	return p.followSpec(func(spec *openapi.Document) Middleware {
+		return StrictRouting(spec, &opts)
+	})
+}
+
+// StrictRouting returns a standalone middleware that makes the spec the
+// enforced source of truth: requests for paths not in the spec get 404 Not
+// Found, and requests for methods not declared for a path get 405 Method Not
+// Allowed with an Allow header, instead of reaching the handler.
+func StrictRouting(spec *openapi.Document, opts *StrictRoutingOptions) Middleware {
+	if opts == nil {
+		opts = &StrictRoutingOptions{}
+	}
+	schemaName := opts.ErrorSchema
+	if schemaName == "" {
+		schemaName = DefaultErrorSchema
+	}
+	var template errorTemplate
+	if spec != nil && spec.Components != nil {
+		template = newErrorTemplate(spec, spec.Components.Schemas[schemaName])
+	}
+	routes := compileRoutes(spec)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if opts.Exempt != nil && opts.Exempt(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			matcher, _ := routes.match(r.URL.Path)
+			switch {
+			case matcher == nil:
+				writeRoutingError(w, r, template, http.StatusNotFound,
+					fmt.Sprintf("path %s is not declared in the spec", r.URL.Path))
+			case !declaresMethod(matcher.pathItem, r.Method):
+				w.Header().Set("Allow", strings.Join(allowedMethods(matcher.pathItem), ", "))
+				writeRoutingError(w, r, template, http.StatusMethodNotAllowed,
+					fmt.Sprintf("method %s is not declared for %s", r.Method, matcher.path))
+			default:
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// declaresMethod reports whether item has an operation serving requests
+// with method.
+func declaresMethod(item *openapi.PathItem, method string) bool {
+	op, _ := requestOperation(item, method)
+	return op != nil
+}
+
+// writeRoutingError writes a JSON error with the fields of template, or in the
+// shape of validation errors if template is nil.
+func writeRoutingError(w http.ResponseWriter, r *http.Request, template errorTemplate, status int, message string) {
+	var body any = struct {
+		Error   string            `json:"error"`
+		Details []ValidationError `json:"details,omitempty"`
+	}{
+		Error:   http.StatusText(status),
+		Details: []ValidationError{{Message: message}},
+	}
+	if template != nil {
+		body = template.fill(r, status, message)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// errorFields are the values of the conventional fields of an error object,
+// by lowercased field name, from the request and the error.
+var errorFields = map[string]func(r *http.Request, status int, message string) string{
+	"code":        func(_ *http.Request, status int, _ string) string { return strconv.Itoa(status) },
+	"status":      func(_ *http.Request, status int, _ string) string { return strconv.Itoa(status) },
+	"statuscode":  func(_ *http.Request, status int, _ string) string { return strconv.Itoa(status) },
+	"error":       func(_ *http.Request, status int, _ string) string { return http.StatusText(status) },
+	"title":       func(_ *http.Request, status int, _ string) string { return http.StatusText(status) },
+	"message":     func(_ *http.Request, _ int, message string) string { return message },
+	"detail":      func(_ *http.Request, _ int, message string) string { return message },
+	"details":     func(_ *http.Request, _ int, message string) string { return message },
+	"description": func(_ *http.Request, _ int, message string) string { return message },
+	"path":        func(r *http.Request, _ int, _ string) string { return r.URL.Path },
+	"instance":    func(r *http.Request, _ int, _ string) string { return r.URL.Path },
+	"method":      func(r *http.Request, _ int, _ string) string { return r.Method },
+}
+
+// statusFields are the error fields holding the status, which are numbers
+// unless their example is a string.
+var statusFields = map[string]bool{"code": true, "status": true, "statuscode": true}
+
+// errorTemplate holds the conventional fields of an error schema, by
+// property name, with whether each holds the status as a number.
+type errorTemplate map[string]bool
+
+// newErrorTemplate returns the template of the properties of schema that
+// errors can fill, such as code and message, or nil if it has none. Other
+// properties are left out of the error bodies rather than given invented values.
+func newErrorTemplate(spec *openapi.Document, schema *openapi.Schema) errorTemplate {
+	if schema == nil {
+		return nil
+	}
+	example, _ := spec.ExampleFor(schema).(map[string]any)
+	template := make(errorTemplate)
+	for name, value := range example {
+		field := strings.ToLower(name)
+		if _, ok := errorFields[field]; !ok {
+			continue
+		}
+		switch value.(type) {
+		case string:
+			template[name] = false
+		case nil:
+		default:
+			if statusFields[field] {
+				template[name] = true
+			}
+		}
+	}
+	if len(template) == 0 {
+		return nil
+	}
+	return template
+}
+
+// fill returns the error body of a request with status and message.
+func (t errorTemplate) fill(r *http.Request, status int, message string) map[string]any {
+	body := make(map[string]any, len(t))
+	for name, number := range t {
+		if number {
+			body[name] = status
+		} else {
+			body[name] = errorFields[strings.ToLower(name)](r, status, message)
+		}
+	}
+	return body
+}
@@ -0,0 +1,343 @@
+package yahttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// Claims describes the principal a token was issued to.
+type Claims struct {
+	Subject string
+	Scopes  []string
+	Extra   map[string]any
+}
+
+// TokenVerifier verifies bearer tokens for http bearer, oauth2, and
+// openIdConnect security schemes.
+type TokenVerifier interface {
+	// VerifyToken returns the claims of a valid token, or an error if the token is rejected.
+	VerifyToken(ctx context.Context, scheme, token string) (*Claims, error)
+}
+
+// TokenVerifierFunc adapts a function to the TokenVerifier interface.
+type TokenVerifierFunc func(ctx context.Context, scheme, token string) (*Claims, error)
+
+// VerifyToken calls f(ctx, scheme, token).
+func (f TokenVerifierFunc) VerifyToken(ctx context.Context, scheme, token string) (*Claims, error) {
+	return f(ctx, scheme, token)
+}
+
+// SecurityOptions configures security enforcement.
+type SecurityOptions struct {
+	// TokenVerifier verifies bearer tokens. Schemes that carry a bearer token
+	// (http bearer, oauth2, openIdConnect) reject every request if nil.
+	TokenVerifier TokenVerifier
+
+	// APIKeyValidator checks apiKey values; if nil, any non-empty key is accepted
+	APIKeyValidator func(r *http.Request, scheme, key string) bool
+
+	// BasicValidator checks http basic credentials. The http basic scheme
+	// rejects every request if nil.
+	BasicValidator func(r *http.Request, scheme, username, password string) bool
+
+	// ErrorHandler writes 401 and 403 responses (default: DefaultSecurityErrorHandler)
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err *SecurityError)
+}
+
+// SecurityError reports why a request failed the operation's security requirements.
+type SecurityError struct {
+	// Status is http.StatusUnauthorized for missing or invalid credentials and
+	// http.StatusForbidden for valid credentials lacking the required scopes.
+	Status int
+
+	// Challenges are WWW-Authenticate values for the schemes that were tried
+	Challenges []string
+
+	Details []ValidationError
+}
+
+func (e *SecurityError) Error() string {
+	if len(e.Details) == 1 {
+		return e.Details[0].Message
+	}
+	return fmt.Sprintf("%s: %d security requirements not met", http.StatusText(e.Status), len(e.Details))
+}
+
+// errInsufficientScope marks a failure caused by missing scopes rather than missing credentials.
+var errInsufficientScope = errors.New("insufficient scope")
+
+type claimsKey struct{}
+
+// SecurityClaims returns the claims of the token that satisfied the operation's
+// security requirements, or nil if no token was verified.
+func SecurityClaims(r *http.Request) *Claims {
+	claims, _ := r.Context().Value(claimsKey{}).(*Claims)
+	return claims
+}
+
+// SecurityMiddleware returns a middleware that enforces the security requirements of each operation.
+func (p *Plugin) SecurityMiddleware() Middleware {
+	return p.followSpec(func(spec *openapi.Document) Middleware {
+		return Security(spec, p.options.SecurityOptions)
+	})
+}
+
+// Security returns a standalone middleware that enforces the security
+// requirements declared for each operation, falling back to the document's
+// global requirements. Alternatives are tried in order and the request is
+// admitted as soon as one is satisfied. Requests for paths and methods not in
+// the spec pass through.
+func Security(spec *openapi.Document, opts *SecurityOptions) Middleware {
+	if opts == nil {
+		opts = &SecurityOptions{}
+	}
+	errorHandler := opts.ErrorHandler
+	if errorHandler == nil {
+		errorHandler = DefaultSecurityErrorHandler
+	}
+	enforcer := &securityEnforcer{spec: spec, opts: opts, routes: compileRoutes(spec)}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, err := enforcer.authorize(r)
+			if err != nil {
+				errorHandler(w, r, err)
+				return
+			}
+			if claims != nil {
+				r = r.WithContext(context.WithValue(r.Context(), claimsKey{}, claims))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// DefaultSecurityErrorHandler writes the error as JSON in the same shape as
+// validation errors, with a WWW-Authenticate header on 401 responses.
+func DefaultSecurityErrorHandler(w http.ResponseWriter, r *http.Request, err *SecurityError) {
+	if err.Status == http.StatusUnauthorized {
+		for _, challenge := range err.Challenges {
+			w.Header().Add("WWW-Authenticate", challenge)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(err.Status)
+
+	_ = json.NewEncoder(w).Encode(struct {
+		Error   string            `json:"error"`
+		Details []ValidationError `json:"details,omitempty"`
+	}{
+		Error:   http.StatusText(err.Status),
+		Details: err.Details,
+	})
+}
+
+type securityEnforcer struct {
+	spec   *openapi.Document
+	opts   *SecurityOptions
+	routes *routeTrie
+}
+
+// authorize checks the request against the requirements of its operation and
+// returns the verified claims, if any.
+func (e *securityEnforcer) authorize(r *http.Request) (*Claims, *SecurityError) {
+	requirements := e.requirements(r)
+	if len(requirements) == 0 {
+		return nil, nil
+	}
+
+	failure := &SecurityError{Status: http.StatusUnauthorized}
+	for _, requirement := range requirements {
+		claims, details, err := e.satisfy(r, requirement)
+		if err == nil {
+			return claims, nil
+		}
+		if errors.Is(err, errInsufficientScope) {
+			failure.Status = http.StatusForbidden
+		}
+		failure.Details = append(failure.Details, details...)
+	}
+	failure.Challenges = e.challenges(requirements)
+	return nil, failure
+}
+
+// requirements returns the security requirements of the request's operation.
+func (e *securityEnforcer) requirements(r *http.Request) []openapi.SecurityRequirement {
+	matcher, _ := e.routes.match(r.URL.Path)
+	if matcher == nil {
+		return nil
+	}
+	op, _ := requestOperation(matcher.pathItem, r.Method)
+	if op == nil {
+		return nil
+	}
+	if op.Security != nil {
+		return op.Security
+	}
+	return e.spec.Security
+}
+
+// satisfy checks every scheme of one requirement. An empty requirement makes security optional.
+func (e *securityEnforcer) satisfy(r *http.Request, requirement openapi.SecurityRequirement) (*Claims, []ValidationError, error) {
+	var claims *Claims
+	var details []ValidationError
+	var failed error
+
+	for _, name := range sortedSchemeNames(requirement) {
+		c, err := e.check(r, name, requirement[name])
+		if err != nil {
+			details = append(details, ValidationError{Field: name, Message: err.Error(), In: "security"})
+			// report missing scopes only if every failing scheme was otherwise satisfied
+			if failed == nil || !errors.Is(err, errInsufficientScope) {
+				failed = err
+			}
+			continue
+		}
+		if c != nil {
+			claims = c
+		}
+	}
+	return claims, details, failed
+}
+
+// check verifies the credentials of a single security scheme.
+func (e *securityEnforcer) check(r *http.Request, name string, scopes []string) (*Claims, error) {
+	scheme := e.scheme(name)
+	if scheme == nil {
+		return nil, fmt.Errorf("security scheme %q is not defined", name)
+	}
+
+	switch {
+	case scheme.Type == "apiKey":
+		return nil, e.checkAPIKey(r, name, scheme)
+	case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+		return nil, e.checkBasic(r, name)
+	case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer"),
+		scheme.Type == "oauth2", scheme.Type == "openIdConnect":
+		return e.checkToken(r, name, scopes)
+	case scheme.Type == "mutualTLS":
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			return nil, errors.New("client certificate is required")
+		}
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("security scheme type %q is not supported", scheme.Type)
+	}
+}
+
+func (e *securityEnforcer) checkAPIKey(r *http.Request, name string, scheme *openapi.SecurityScheme) error {
+	var key string
+	switch scheme.In {
+	case string(openapi.ParameterInHeader):
+		key = r.Header.Get(scheme.Name)
+	case string(openapi.ParameterInQuery):
+		key = r.URL.Query().Get(scheme.Name)
+	case string(openapi.ParameterInCookie):
+		if cookie, err := r.Cookie(scheme.Name); err == nil {
+			key = cookie.Value
+		}
+	}
+
+	if key == "" {
+		return fmt.Errorf("API key %s is missing from %s", scheme.Name, scheme.In)
+	}
+	if e.opts.APIKeyValidator != nil && !e.opts.APIKeyValidator(r, name, key) {
+		return errors.New("API key is invalid")
+	}
+	return nil
+}
+
+func (e *securityEnforcer) checkBasic(r *http.Request, name string) error {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return errors.New("basic credentials are missing")
+	}
+	if e.opts.BasicValidator == nil {
+		return errors.New("basic credentials cannot be verified")
+	}
+	if !e.opts.BasicValidator(r, name, username, password) {
+		return errors.New("basic credentials are invalid")
+	}
+	return nil
+}
+
+func (e *securityEnforcer) checkToken(r *http.Request, name string, scopes []string) (*Claims, error) {
+	token, ok := bearerToken(r)
+	if !ok {
+		return nil, errors.New("bearer token is missing")
+	}
+	if e.opts.TokenVerifier == nil {
+		return nil, errors.New("bearer tokens cannot be verified")
+	}
+
+	claims, err := e.opts.TokenVerifier.VerifyToken(r.Context(), name, token)
+	if err != nil {
+		return nil, fmt.Errorf("bearer token is invalid: %w", err)
+	}
+	if claims == nil {
+		claims = &Claims{}
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(claims.Scopes, scope) {
+			return nil, fmt.Errorf("%w: scope %q is required", errInsufficientScope, scope)
+		}
+	}
+	return claims, nil
+}
+
+func (e *securityEnforcer) scheme(name string) *openapi.SecurityScheme {
+	if e.spec.Components == nil {
+		return nil
+	}
+	return e.spec.Components.SecuritySchemes[name]
+}
+
+// challenges returns a WWW-Authenticate challenge for each HTTP authentication scheme in requirements.
+func (e *securityEnforcer) challenges(requirements []openapi.SecurityRequirement) []string {
+	var challenges []string
+	for _, requirement := range requirements {
+		for _, name := range sortedSchemeNames(requirement) {
+			scheme := e.scheme(name)
+			if scheme == nil {
+				continue
+			}
+			var challenge string
+			switch {
+			case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+				challenge = "Basic"
+			case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer"),
+				scheme.Type == "oauth2", scheme.Type == "openIdConnect":
+				challenge = "Bearer"
+			}
+			if challenge != "" && !slices.Contains(challenges, challenge) {
+				challenges = append(challenges, challenge)
+			}
+		}
+	}
+	return challenges
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func sortedSchemeNames(requirement openapi.SecurityRequirement) []string {
+	names := make([]string, 0, len(requirement))
+	for name := range requirement {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
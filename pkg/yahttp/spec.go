@@ -1,21 +1,35 @@
 package yahttp
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"path"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/fathurrohman26/yaswag/pkg/openapi"
 )
 
+// DefaultSpecCacheControl is the default Cache-Control header of spec responses.
+const DefaultSpecCacheControl = "public, max-age=3600"
+
 // SpecHandler returns an http.Handler that serves the OpenAPI specification.
 // It supports both JSON and YAML formats based on Accept header or file extension.
+// With LazyTags, a tag query parameter selects the slice of the spec for one tag.
 func (p *Plugin) SpecHandler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		format := p.detectFormat(r)
-		p.serveSpec(w, format)
+		p.serveSpec(w, r, format)
 	})
 }
 
@@ -23,21 +37,21 @@ func (p *Plugin) SpecHandler() http.Handler {
 func (p *Plugin) SpecHandlerFunc() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		format := p.detectFormat(r)
-		p.serveSpec(w, format)
+		p.serveSpec(w, r, format)
 	}
 }
 
 // JSONSpecHandler returns a handler that always serves the spec as JSON.
 func (p *Plugin) JSONSpecHandler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		p.serveSpec(w, "json")
+		p.serveSpec(w, r, "json")
 	})
 }
 
 // YAMLSpecHandler returns a handler that always serves the spec as YAML.
 func (p *Plugin) YAMLSpecHandler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		p.serveSpec(w, "yaml")
+		p.serveSpec(w, r, "yaml")
 	})
 }
 
@@ -66,29 +80,180 @@ func (p *Plugin) detectFormat(r *http.Request) string {
 	return "json"
 }
 
-func (p *Plugin) serveSpec(w http.ResponseWriter, format string) {
-	var data []byte
-	var err error
-	var contentType string
+func (p *Plugin) serveSpec(w http.ResponseWriter, r *http.Request, format string) {
+	p.serveDocument(w, r, p.served.Load(), format)
+}
+
+// servedSpec is a spec as served, with its serialized forms, tag slices, and
+// health made on first use and then kept. UpdateSpec replaces it as a whole.
+type servedSpec struct {
+	doc     *openapi.Document
+	modTime time.Time
+	json    specEncoding
+	yaml    specEncoding
+	tags    sync.Map // tag name to *servedSpec of its slice
+	health  specHealth
+}
+
+func newServedSpec(doc *openapi.Document, modTime time.Time) *servedSpec {
+	return &servedSpec{doc: doc, modTime: modTime}
+}
 
-	switch format {
-	case "yaml":
-		data, err = yaml.Marshal(p.spec)
-		contentType = "application/yaml; charset=utf-8"
-	default:
-		data, err = json.MarshalIndent(p.spec, "", "  ")
-		contentType = "application/json; charset=utf-8"
+// tagSlice returns the slice of the tag, nil if no operations have the tag.
+func (s *servedSpec) tagSlice(tag string) *servedSpec {
+	if slice, ok := s.tags.Load(tag); ok {
+		return slice.(*servedSpec)
 	}
+	doc := s.doc.TagSlice(tag)
+	if len(doc.Paths)+len(doc.Webhooks) == 0 {
+		return nil
+	}
+	slice, _ := s.tags.LoadOrStore(tag, newServedSpec(doc, s.modTime))
+	return slice.(*servedSpec)
+}
+
+// encoding returns the spec serialized in format, serializing it on first use.
+func (s *servedSpec) encoding(format string) *specEncoding {
+	if format == "yaml" {
+		s.yaml.once.Do(func() { s.yaml.encode(yaml.Marshal(s.doc)) })
+		return &s.yaml
+	}
+	s.json.once.Do(func() { s.json.encode(json.MarshalIndent(s.doc, "", "  ")) })
+	return &s.json
+}
 
+// specEncoding is a serialized form of a spec, along with its gzipped form
+// and their ETags.
+type specEncoding struct {
+	once    sync.Once
+	data    []byte
+	gzipped []byte // nil if gzip does not make data smaller
+	etag    string
+	err     error
+}
+
+func (e *specEncoding) encode(data []byte, err error) {
 	if err != nil {
+		e.err = err
+		return
+	}
+	e.data = data
+	sum := sha256.Sum256(data)
+	e.etag = hex.EncodeToString(sum[:16])
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write(data)
+	if zw.Close() == nil && buf.Len() < len(data) {
+		e.gzipped = buf.Bytes()
+	}
+}
+
+// serveDocument serves spec in format, or with LazyTags the slice of the tag
+// query parameter. The ETag and Last-Modified headers let clients revalidate
+// the spec, and gzip clients get it compressed.
+func (p *Plugin) serveDocument(w http.ResponseWriter, r *http.Request, spec *servedSpec, format string) {
+	if tag := r.URL.Query().Get("tag"); tag != "" && p.options.LazyTags && spec.doc != nil {
+		if spec = spec.tagSlice(tag); spec == nil {
+			http.Error(w, fmt.Sprintf("No operations tagged %q", tag), http.StatusNotFound)
+			return
+		}
+	}
+
+	encoding := spec.encoding(format)
+	if encoding.err != nil {
 		http.Error(w, "Failed to serialize OpenAPI spec", http.StatusInternalServerError)
 		return
 	}
 
-	w.Header().Set("Content-Type", contentType)
-	w.Header().Set("Cache-Control", "public, max-age=3600")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	_, _ = w.Write(data)
+	p.setSpecHeaders(w.Header(), format)
+	data := negotiateEncoding(w.Header(), r, encoding)
+
+	// handles If-None-Match, If-Modified-Since, HEAD, and ranges
+	http.ServeContent(w, r, "", spec.modTime, bytes.NewReader(data))
+}
+
+// setSpecHeaders sets the caching, CORS, and content type headers of a spec
+// served in format.
+func (p *Plugin) setSpecHeaders(header http.Header, format string) {
+	cacheControl := p.options.SpecCacheControl
+	if cacheControl == "" {
+		cacheControl = DefaultSpecCacheControl
+	}
+	header.Set("Cache-Control", cacheControl)
+	header.Set("Access-Control-Allow-Origin", "*")
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	if format == "yaml" {
+		header.Set("Content-Type", "application/yaml; charset=utf-8")
+	}
+}
+
+// negotiateEncoding returns the encoding of the spec to serve, gzipped if
+// the client accepts it, and sets the ETag and content coding headers.
+func negotiateEncoding(header http.Header, r *http.Request, encoding *specEncoding) []byte {
+	data, etag := encoding.data, encoding.etag
+	if encoding.gzipped != nil {
+		if !slices.Contains(header.Values("Vary"), "Accept-Encoding") {
+			header.Add("Vary", "Accept-Encoding")
+		}
+		if acceptsGzip(r) {
+			// the gzipped form is a representation of its own, with its own ETag
+			data, etag = encoding.gzipped, etag+"-gzip"
+			header.Set("Content-Encoding", "gzip")
+		}
+	}
+	header.Set("ETag", `"`+etag+`"`)
+	return data
+}
+
+// NamedSpec is an additional specification served at SpecsPath/{Name}.json
+// (and .yaml), such as one version of a multi-version API.
+type NamedSpec struct {
+	Name string
+	Spec *openapi.Document
+}
+
+// DefaultSpecsPath is the default path prefix of the named specs.
+const DefaultSpecsPath = "/openapi"
+
+// SpecsPath returns the path prefix the named specs are served under.
+func (p *Plugin) SpecsPath() string {
+	if p.options.SpecsPath != "" {
+		return p.options.SpecsPath
+	}
+	return DefaultSpecsPath
+}
+
+// NamedSpecURL returns the URL of the JSON form of the named spec.
+func (p *Plugin) NamedSpecURL(name string) string {
+	return p.SpecsPath() + "/" + url.PathEscape(name) + ".json"
+}
+
+// NamedSpecPaths returns the JSON and YAML paths of every named spec, for
+// routers that register exact paths.
+func (p *Plugin) NamedSpecPaths() []string {
+	var paths []string
+	for _, named := range p.options.Specs {
+		base := p.SpecsPath() + "/" + named.Name
+		paths = append(paths, base+".json", base+".yaml")
+	}
+	return paths
+}
+
+// NamedSpecHandler returns an http.Handler that serves the named specs at
+// SpecsPath/{name}.json and SpecsPath/{name}.yaml.
+func (p *Plugin) NamedSpecHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the last segment, so the handler also works in router groups
+		name := path.Base(r.URL.Path)
+		name = strings.TrimSuffix(name, path.Ext(name))
+		spec, ok := p.named[name]
+		if !ok {
+			http.Error(w, fmt.Sprintf("No spec named %q", name), http.StatusNotFound)
+			return
+		}
+		p.serveDocument(w, r, spec, p.detectFormat(r))
+	})
 }
 
 // ServeSpec is a standalone function to serve an OpenAPI spec.
@@ -1,44 +1,83 @@
 package yahttp
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"html/template"
+	"maps"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/uiassets"
+)
+
+// DocsUI selects the documentation renderer served at SwaggerUIPath.
+type DocsUI = uiassets.UI
+
+// Branding customizes the documentation pages with a logo, favicon, primary
+// color, footer text, and navigation links.
+type Branding = uiassets.Branding
+
+// NavLink is a link in the navigation bar of branded documentation pages.
+type NavLink = uiassets.NavLink
+
+// Supported documentation renderers.
+const (
+	DocsUISwagger = uiassets.UISwagger
+	DocsUIRedoc   = uiassets.UIRedoc
+	DocsUIScalar  = uiassets.UIScalar
+	DocsUIRapiDoc = uiassets.UIRapiDoc
 )
 
 const swaggerUITemplate = `<!DOCTYPE html>
-<html lang="en">
+<html lang="{{.Branding.Lang}}">
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
     <title>{{.Title}} - Swagger UI</title>
-    <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui.css">
+    <link rel="stylesheet" href="{{.Assets.SwaggerUICSS}}">
     <style>
         body { margin: 0; padding: 0; }
+        {{- if not .URLs}}
         .swagger-ui .topbar { display: none; }
+        {{- end}}
+        {{- with .CustomCSS}}
+        {{.}}
+        {{- end}}
     </style>
+    {{- template "branding-head" .Branding}}
 </head>
 <body>
+    {{- template "branding-navbar" .Branding}}
     <div id="swagger-ui"></div>
-    <script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+    {{- template "branding-footer" .Branding}}
+    <script src="{{.Assets.SwaggerUIBundle}}"></script>
     <script>
         window.onload = function() {
-            SwaggerUIBundle({
+            const ui = SwaggerUIBundle({
+                {{- if .URLs}}
+                urls: {{.URLs}},
+                {{- else}}
                 url: "{{.SpecURL}}",
+                {{- end}}
                 dom_id: '#swagger-ui',
-                deepLinking: true,
                 presets: [
                     SwaggerUIBundle.presets.apis,
                     SwaggerUIBundle.SwaggerUIStandalonePreset
                 ],
-                layout: "BaseLayout",
-                defaultModelsExpandDepth: 1,
-                defaultModelExpandDepth: 1,
-                docExpansion: "list",
-                filter: true,
-                showExtensions: true,
-                showCommonExtensions: true
+                layout: {{if .URLs}}"StandaloneLayout"{{else}}"BaseLayout"{{end}},
+                ...{{.Config}}
             });
+            {{- with .OAuth}}
+            ui.initOAuth({{.}});
+            {{- end}}
+            window.ui = ui;
+            {{- with .CustomJS}}
+            {{.}}
+            {{- end}}
         };
     </script>
 </body>
@@ -52,22 +91,152 @@ type SwaggerUIOptions struct {
 	// SpecURL is the URL to the OpenAPI spec (default: plugin's SpecPath)
 	SpecURL string
 
-	// CustomCSS is optional custom CSS to inject
+	// DocExpansion sets how much of the tags and operations is expanded on
+	// load: list, full, or none (default: list)
+	DocExpansion string
+
+	// PersistAuthorization keeps the authorization entered in Swagger UI
+	// across page reloads
+	PersistAuthorization bool
+
+	// TryItOutEnabled opens the "Try it out" form of every operation
+	TryItOutEnabled bool
+
+	// TagsSorter sorts the tags: alpha, or empty for the order of the spec
+	TagsSorter string
+
+	// OperationsSorter sorts the operations of a tag: alpha (by path),
+	// method, or empty for the order of the spec
+	OperationsSorter string
+
+	// OAuth2 preconfigures the OAuth 2.0 authorization dialog
+	OAuth2 *SwaggerUIOAuth2
+
+	// Extra holds further SwaggerUIBundle options by their JavaScript names,
+	// e.g. {"defaultModelsExpandDepth": -1}, overriding the others
+	Extra map[string]any
+
+	// CustomCSS is appended to the page styles, e.g. to apply a corporate theme
 	CustomCSS string
 
-	// CustomJS is optional custom JavaScript to inject
+	// CustomJS runs once Swagger UI is initialized, with the instance as
+	// window.ui
 	CustomJS string
 }
 
-// SwaggerUIHandler returns an http.Handler that serves Swagger UI.
+// SwaggerUIOAuth2 is the OAuth 2.0 configuration passed to Swagger UI's
+// initOAuth. Values are visible in the page source, so ClientSecret should
+// only be set for development clients.
+type SwaggerUIOAuth2 struct {
+	ClientID     string `json:"clientId,omitempty"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+	Realm        string `json:"realm,omitempty"`
+	AppName      string `json:"appName,omitempty"`
+
+	// Scopes are selected by default in the authorization dialog
+	Scopes []string `json:"scopes,omitempty"`
+
+	// AdditionalQueryStringParams are added to the authorization URL, e.g. an audience
+	AdditionalQueryStringParams map[string]string `json:"additionalQueryStringParams,omitempty"`
+
+	// UsePKCE uses Proof Key for Code Exchange with the authorization code flow
+	UsePKCE bool `json:"usePkceWithAuthorizationCodeGrant,omitempty"`
+}
+
+// config returns the options passed to SwaggerUIBundle, besides the spec
+// URLs, presets, and layout.
+func (o *SwaggerUIOptions) config() (map[string]any, error) {
+	config := map[string]any{
+		"deepLinking":              true,
+		"defaultModelsExpandDepth": 1,
+		"defaultModelExpandDepth":  1,
+		"docExpansion":             "list",
+		"filter":                   true,
+		"showExtensions":           true,
+		"showCommonExtensions":     true,
+	}
+	if o == nil {
+		return config, nil
+	}
+	for _, option := range []struct {
+		name, value string
+		allowed     []string
+	}{
+		{"docExpansion", o.DocExpansion, []string{"list", "full", "none"}},
+		{"tagsSorter", o.TagsSorter, []string{"alpha"}},
+		{"operationsSorter", o.OperationsSorter, []string{"alpha", "method"}},
+	} {
+		if option.value == "" {
+			continue
+		}
+		if !slices.Contains(option.allowed, option.value) {
+			return nil, fmt.Errorf("invalid %s %q: must be %s", option.name, option.value, strings.Join(option.allowed, ", "))
+		}
+		config[option.name] = option.value
+	}
+	if o.PersistAuthorization {
+		config["persistAuthorization"] = true
+	}
+	if o.TryItOutEnabled {
+		config["tryItOutEnabled"] = true
+	}
+	maps.Copy(config, o.Extra)
+	return config, nil
+}
+
+// SwaggerUIHandler returns an http.Handler that serves Swagger UI, configured
+// with the SwaggerUIOptions option.
 func (p *Plugin) SwaggerUIHandler() http.Handler {
-	return p.SwaggerUIHandlerWithOptions(nil)
+	return p.SwaggerUIHandlerWithOptions(p.options.SwaggerUIOptions)
 }
 
 // SwaggerUIHandlerWithOptions returns a Swagger UI handler with custom options.
+// With named specs, the page has a selector of the specs. Otherwise, with
+// LazyTags, the page lists the tags of the spec and loads the slice of the
+// selected tag only.
 func (p *Plugin) SwaggerUIHandlerWithOptions(opts *SwaggerUIOptions) http.Handler {
-	title, specURL := p.resolveDocOptions(opts.getTitle(), opts.getSpecURL())
-	return p.createDocHandler("swagger", swaggerUITemplate, title, specURL, "Swagger UI")
+	page := p.newDocPage(p.resolveDocOptions(opts.getTitle(), opts.getSpecURL()))
+	config, err := opts.config()
+	if err != nil {
+		return errorHandler(fmt.Sprintf("Failed to render Swagger UI: %v", err))
+	}
+	page.Config = config
+	if opts != nil {
+		if opts.OAuth2 != nil {
+			page.OAuth = opts.OAuth2
+		}
+		// set by the application, not by users, so trusted like the templates
+		page.CustomCSS = template.CSS(opts.CustomCSS)
+		page.CustomJS = template.JS(opts.CustomJS)
+	}
+	switch spec := p.Spec(); {
+	case len(p.options.Specs) > 0:
+		for _, named := range p.options.Specs {
+			page.URLs = append(page.URLs, map[string]string{"name": named.Name, "url": p.NamedSpecURL(named.Name)})
+		}
+	case p.options.LazyTags && spec != nil:
+		if urls := tagSpecURLs(page.SpecURL, spec.OperationTags()); len(urls) > 0 {
+			page.URLs = urls
+		}
+	}
+	return p.createDocHandler(swaggerUIPage, page, "Swagger UI")
+}
+
+// tagSpecURLs returns the Swagger UI urls option loading the slice of each tag from specURL.
+func tagSpecURLs(specURL string, tags []string) []map[string]string {
+	u, err := url.Parse(specURL)
+	if err != nil {
+		return nil
+	}
+	urls := make([]map[string]string, 0, len(tags))
+	for _, tag := range tags {
+		query := u.Query()
+		query.Set("tag", tag)
+		tagged := *u
+		tagged.RawQuery = query.Encode()
+		urls = append(urls, map[string]string{"name": tag, "url": tagged.String()})
+	}
+	return urls
 }
 
 // SwaggerUIHandlerFunc returns an http.HandlerFunc that serves Swagger UI.
@@ -87,27 +256,194 @@ func (p *Plugin) RedocHandler() http.Handler {
 type RedocOptions struct {
 	Title   string
 	SpecURL string
+
+	// HideDownloadButton hides the button to download the spec
+	HideDownloadButton bool
+
+	// ExpandResponses lists the response codes expanded by default,
+	// e.g. "200,201", or "all"
+	ExpandResponses string
+
+	// Theme is a ReDoc theme object in JSON, e.g. {"colors":{"primary":{"main":"#32329f"}}}
+	Theme json.RawMessage
+
+	// UntrustedSpec sanitizes Markdown and HTML in the spec, for specs from untrusted sources
+	UntrustedSpec bool
+
+	// EmbeddedBundle serves the ReDoc bundle embedded in the binary even when
+	// OfflineAssets is off. The bundle is embedded only in binaries built
+	// after 'make ui-assets'; otherwise the page loads the CDN bundle, which
+	// is pinned to uiassets.RedocVersion as well
+	EmbeddedBundle bool
+
+	// BundleURL loads ReDoc from this URL, e.g. a self-hosted or version-pinned bundle
+	BundleURL string
+}
+
+// config returns the options passed to Redoc.init.
+func (o *RedocOptions) config() (map[string]any, error) {
+	config := map[string]any{}
+	if o == nil {
+		return config, nil
+	}
+	if o.HideDownloadButton {
+		config["hideDownloadButton"] = true
+	}
+	if o.ExpandResponses != "" {
+		config["expandResponses"] = o.ExpandResponses
+	}
+	if o.UntrustedSpec {
+		config["untrustedSpec"] = true
+	}
+	if len(o.Theme) > 0 {
+		if !json.Valid(o.Theme) {
+			return nil, fmt.Errorf("theme is not valid JSON")
+		}
+		config["theme"] = o.Theme
+	}
+	return config, nil
+}
+
+func (o *RedocOptions) embeddedBundle() bool {
+	return o != nil && o.EmbeddedBundle
 }
 
 const redocTemplate = `<!DOCTYPE html>
-<html>
+<html lang="{{.Branding.Lang}}">
 <head>
     <title>{{.Title}} - API Documentation</title>
     <meta charset="utf-8"/>
     <meta name="viewport" content="width=device-width, initial-scale=1">
+    {{- if not .Assets.Offline}}
     <link href="https://fonts.googleapis.com/css?family=Montserrat:300,400,700|Roboto:300,400,700" rel="stylesheet">
+    {{- end}}
     <style>body { margin: 0; padding: 0; }</style>
+    {{- template "branding-head" .Branding}}
 </head>
 <body>
-    <redoc spec-url='{{.SpecURL}}'></redoc>
-    <script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+    {{- template "branding-navbar" .Branding}}
+    <div id="redoc-container"></div>
+    {{- template "branding-footer" .Branding}}
+    <script src="{{.Assets.RedocBundle}}"></script>
+    <script>
+        Redoc.init({{.SpecURL}}, {{.Config}}, document.getElementById("redoc-container"));
+    </script>
 </body>
 </html>`
 
 // RedocHandlerWithOptions returns a ReDoc handler with custom options.
 func (p *Plugin) RedocHandlerWithOptions(opts *RedocOptions) http.Handler {
-	title, specURL := p.resolveDocOptions(opts.getTitle(), opts.getSpecURL())
-	return p.createDocHandler("redoc", redocTemplate, title, specURL, "ReDoc")
+	page := p.newDocPage(p.resolveDocOptions(opts.getTitle(), opts.getSpecURL()))
+	config, err := opts.config()
+	if err != nil {
+		return errorHandler(fmt.Sprintf("Failed to render ReDoc: %v", err))
+	}
+	if _, ok := config["theme"]; !ok && page.Branding != nil && page.Branding.PrimaryColor != "" {
+		config["theme"] = map[string]any{"colors": map[string]any{"primary": map[string]any{"main": page.Branding.PrimaryColor}}}
+	}
+	page.Config = config
+	if opts.embeddedBundle() {
+		page.Assets.RedocBundle = uiassets.LocalOrCDN(p.AssetsPath()).RedocBundle
+	}
+	if opts != nil && opts.BundleURL != "" {
+		page.Assets.RedocBundle = opts.BundleURL
+	}
+	return p.createDocHandler(redocPage, page, "ReDoc")
+}
+
+// ScalarHandler returns an http.Handler that serves Scalar API reference documentation.
+func (p *Plugin) ScalarHandler() http.Handler {
+	return p.ScalarHandlerWithOptions(nil)
+}
+
+// ScalarOptions configures Scalar rendering.
+type ScalarOptions struct {
+	Title   string
+	SpecURL string
+}
+
+const scalarTemplate = `<!DOCTYPE html>
+<html lang="{{.Branding.Lang}}">
+<head>
+    <title>{{.Title}} - API Reference</title>
+    <meta charset="utf-8"/>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    {{- template "branding-head" .Branding}}
+</head>
+<body>
+    {{- template "branding-navbar" .Branding}}
+    <script id="api-reference" data-url="{{.SpecURL}}"></script>
+    <script src="{{.Assets.ScalarBundle}}"></script>
+    {{- template "branding-footer" .Branding}}
+</body>
+</html>`
+
+// ScalarHandlerWithOptions returns a Scalar handler with custom options.
+func (p *Plugin) ScalarHandlerWithOptions(opts *ScalarOptions) http.Handler {
+	page := p.newDocPage(p.resolveDocOptions(opts.getTitle(), opts.getSpecURL()))
+	return p.createDocHandler(scalarPage, page, "Scalar")
+}
+
+// RapiDocHandler returns an http.Handler that serves RapiDoc documentation.
+func (p *Plugin) RapiDocHandler() http.Handler {
+	return p.RapiDocHandlerWithOptions(nil)
+}
+
+// RapiDocOptions configures RapiDoc rendering.
+type RapiDocOptions struct {
+	Title   string
+	SpecURL string
+}
+
+const rapiDocTemplate = `<!DOCTYPE html>
+<html lang="{{.Branding.Lang}}">
+<head>
+    <title>{{.Title}} - API Documentation</title>
+    <meta charset="utf-8"/>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <script type="module" src="{{.Assets.RapiDocBundle}}"></script>
+    {{- template "branding-head" .Branding}}
+</head>
+<body>
+    {{- template "branding-navbar" .Branding}}
+    <rapi-doc spec-url="{{.SpecURL}}" render-style="read" show-header="false" allow-try="true"
+        {{- with .Branding}}{{with .PrimaryColor}} primary-color="{{.}}"{{end}}{{end}}></rapi-doc>
+    {{- template "branding-footer" .Branding}}
+</body>
+</html>`
+
+// RapiDocHandlerWithOptions returns a RapiDoc handler with custom options.
+func (p *Plugin) RapiDocHandlerWithOptions(opts *RapiDocOptions) http.Handler {
+	page := p.newDocPage(p.resolveDocOptions(opts.getTitle(), opts.getSpecURL()))
+	return p.createDocHandler(rapiDocPage, page, "RapiDoc")
+}
+
+// Documentation page templates, parsed once and shared by every handler.
+var (
+	swaggerUIPage = parseDocTemplate("swagger", swaggerUITemplate)
+	redocPage     = parseDocTemplate("redoc", redocTemplate)
+	scalarPage    = parseDocTemplate("scalar", scalarTemplate)
+	rapiDocPage   = parseDocTemplate("rapidoc", rapiDocTemplate)
+)
+
+// parseDocTemplate parses a documentation page template along with the branding templates it uses.
+func parseDocTemplate(name, text string) *template.Template {
+	return template.Must(template.Must(template.New(name).Parse(text)).Parse(uiassets.BrandingTemplates))
+}
+
+// DocsHandler returns the documentation handler selected by the DocsUI option,
+// defaulting to Swagger UI.
+func (p *Plugin) DocsHandler() http.Handler {
+	switch p.options.DocsUI {
+	case DocsUIRedoc:
+		return p.RedocHandlerWithOptions(p.options.RedocOptions)
+	case DocsUIScalar:
+		return p.ScalarHandler()
+	case DocsUIRapiDoc:
+		return p.RapiDocHandler()
+	default:
+		return p.SwaggerUIHandler()
+	}
 }
 
 // Helper methods for nil-safe option access
@@ -139,36 +475,100 @@ func (o *RedocOptions) getSpecURL() string {
 	return o.SpecURL
 }
 
+func (o *ScalarOptions) getTitle() string {
+	if o == nil {
+		return ""
+	}
+	return o.Title
+}
+
+func (o *ScalarOptions) getSpecURL() string {
+	if o == nil {
+		return ""
+	}
+	return o.SpecURL
+}
+
+func (o *RapiDocOptions) getTitle() string {
+	if o == nil {
+		return ""
+	}
+	return o.Title
+}
+
+func (o *RapiDocOptions) getSpecURL() string {
+	if o == nil {
+		return ""
+	}
+	return o.SpecURL
+}
+
 // resolveDocOptions resolves title and specURL with defaults from plugin.
+// Without a spec, the renderers show the first named spec.
 func (p *Plugin) resolveDocOptions(title, specURL string) (string, string) {
-	if title == "" && p.spec != nil {
-		title = p.spec.Info.Title
+	spec, defaultURL := p.Spec(), p.options.SpecPath
+	if spec == nil && len(p.options.Specs) > 0 {
+		spec, defaultURL = p.options.Specs[0].Spec, p.NamedSpecURL(p.options.Specs[0].Name)
+	}
+	if title == "" && spec != nil {
+		title = spec.Info.Title
 	}
 	if title == "" {
 		title = "API Documentation"
 	}
 	if specURL == "" {
-		specURL = p.options.SpecPath
+		specURL = defaultURL
 	}
 	return title, specURL
 }
 
-// createDocHandler creates an HTTP handler that renders a documentation template.
-func (p *Plugin) createDocHandler(name, tmplContent, title, specURL, docType string) http.Handler {
-	tmpl := template.Must(template.New(name).Parse(tmplContent))
+// docPage is the data of a documentation page template.
+type docPage struct {
+	Title   string
+	SpecURL string
+	Assets  uiassets.URLs
+
+	// Config holds the options of renderers configured from JavaScript
+	Config any
+
+	// URLs lists the specs Swagger UI selects from: the named specs, or the tag
+	// slices with LazyTags
+	URLs []map[string]string
+
+	// OAuth, CustomCSS, and CustomJS are the Swagger UI options of the same names
+	OAuth     any
+	CustomCSS template.CSS
+	CustomJS  template.JS
+
+	// Branding is the Branding option, or nil
+	Branding *Branding
+}
+
+func (p *Plugin) newDocPage(title, specURL string) docPage {
+	return docPage{Title: title, SpecURL: specURL, Assets: p.assetURLs(), Branding: p.options.Branding}
+}
 
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		data := struct {
-			Title   string
-			SpecURL string
-		}{
-			Title:   title,
-			SpecURL: specURL,
-		}
+// createDocHandler creates an HTTP handler that serves a documentation page.
+// The page only depends on the handler's options, so it is rendered once here
+// rather than on every request.
+func (p *Plugin) createDocHandler(tmpl *template.Template, data docPage, docType string) http.Handler {
+	if err := data.Branding.Validate(); err != nil {
+		return errorHandler(fmt.Sprintf("Failed to render %s: %v", docType, err))
+	}
+	var page bytes.Buffer
+	if err := tmpl.Execute(&page, data); err != nil {
+		return errorHandler(fmt.Sprintf("Failed to render %s: %v", docType, err))
+	}
 
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		if err := tmpl.Execute(w, data); err != nil {
-			http.Error(w, fmt.Sprintf("Failed to render %s: %v", docType, err), http.StatusInternalServerError)
-		}
+		_, _ = w.Write(page.Bytes())
+	})
+}
+
+// errorHandler responds to every request with an internal server error.
+func errorHandler(message string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, message, http.StatusInternalServerError)
 	})
 }
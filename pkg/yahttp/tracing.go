@@ -0,0 +1,115 @@
+package yahttp
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the instrumentation library in emitted spans.
+const tracerName = "github.com/fathurrohman26/yaswag/pkg/yahttp"
+
+// Span attributes set by the tracing middleware, in addition to the
+// OpenTelemetry HTTP semantic conventions.
+const (
+	AttrOperationID      = attribute.Key("openapi.operation_id")
+	AttrStatusClass      = attribute.Key("http.response.status_class")
+	AttrValidationFailed = attribute.Key("openapi.validation.failed")
+	AttrValidationErrors = attribute.Key("openapi.validation.error_count")
+)
+
+// TracingOptions configures OpenTelemetry tracing.
+type TracingOptions struct {
+	// TracerProvider creates the tracer (default: otel.GetTracerProvider())
+	TracerProvider trace.TracerProvider
+
+	// Propagator extracts the incoming trace context (default: otel.GetTextMapPropagator())
+	Propagator propagation.TextMapPropagator
+}
+
+// TracingMiddleware returns a middleware that traces requests with OpenTelemetry.
+func (p *Plugin) TracingMiddleware() Middleware {
+	return p.followSpec(func(spec *openapi.Document) Middleware {
+		return Tracing(spec, p.options.TracingOptions)
+	})
+}
+
+// Tracing returns a standalone middleware that starts a server span per
+// request, continuing the trace propagated by the caller. Spans are named
+// after the matched operationId, falling back to "METHOD /path/template",
+// and record the path template and response status class. The validation
+// middleware marks spans of requests that fail validation.
+func Tracing(spec *openapi.Document, opts *TracingOptions) Middleware {
+	if opts == nil {
+		opts = &TracingOptions{}
+	}
+	provider := opts.TracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	propagator := opts.Propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+	tracer := provider.Tracer(tracerName)
+	routes := compileRoutes(spec)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			name, attrs := spanNameAndAttributes(routes, r)
+
+			ctx, span := tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+			defer span.End()
+
+			wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(wrapped, r.WithContext(ctx))
+
+			status := wrapped.statusCode
+			span.SetAttributes(
+				attribute.Int("http.response.status_code", status),
+				AttrStatusClass.String(fmt.Sprintf("%dxx", status/100)),
+			)
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+		})
+	}
+}
+
+// spanNameAndAttributes names the span after the operation matching the request.
+// Unmatched requests are named after the method alone to keep span names low-cardinality.
+func spanNameAndAttributes(routes *routeTrie, r *http.Request) (string, []attribute.KeyValue) {
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", r.Method),
+		attribute.String("url.path", r.URL.Path),
+	}
+
+	matcher, _ := routes.match(r.URL.Path)
+	if matcher == nil {
+		return r.Method, attrs
+	}
+	attrs = append(attrs, attribute.String("http.route", matcher.path))
+
+	op, _ := requestOperation(matcher.pathItem, r.Method)
+	if op == nil || op.OperationID == "" {
+		return r.Method + " " + matcher.path, attrs
+	}
+	return op.OperationID, append(attrs, AttrOperationID.String(op.OperationID))
+}
+
+// recordValidationFailure marks the request's span, if any, as failing validation.
+func recordValidationFailure(r *http.Request, errs ValidationErrors) {
+	span := trace.SpanFromContext(r.Context())
+	if !span.IsRecording() {
+		return
+	}
+	span.SetAttributes(AttrValidationFailed.Bool(true), AttrValidationErrors.Int(len(errs)))
+	span.AddEvent("validation failed", trace.WithAttributes(attribute.String("error", errs.Error())))
+}
@@ -0,0 +1,136 @@
+package yahttp
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// UpdateSpec replaces the spec at runtime, such as when it is reloaded. The
+// spec, documentation, health, and operations handlers and the validation,
+// strict routing, security, tracing, and operation headers middleware switch
+// to the new spec atomically, so each request sees either the old or the new
+// one. The spec is served with Last-Modified set to now. Coverage and the
+// Router keep the spec they were created with.
+func (p *Plugin) UpdateSpec(spec *openapi.Document) {
+	p.specMu.Lock()
+	defer p.specMu.Unlock()
+	p.storeSpec(spec)
+}
+
+// AddOperation adds op to the spec as the operation for method on the path
+// template path, such as for routes registered by plugins after startup. It
+// fails if the path already has an operation for method.
+func (p *Plugin) AddOperation(method, path string, op *openapi.Operation) error {
+	if op == nil {
+		return fmt.Errorf("operation for %s %s is nil", method, path)
+	}
+	return p.updatePath(path, func(item *openapi.PathItem) error {
+		slot := operationSlot(item, method)
+		if slot == nil {
+			return fmt.Errorf("unsupported method %q", method)
+		}
+		if *slot != nil {
+			return fmt.Errorf("%s %s already has an operation", strings.ToUpper(method), path)
+		}
+		*slot = op
+		return nil
+	})
+}
+
+// RemoveOperation removes the operation for method on the path template path
+// from the spec, and the path once it has no operations left. It fails if
+// there is no such operation.
+func (p *Plugin) RemoveOperation(method, path string) error {
+	return p.updatePath(path, func(item *openapi.PathItem) error {
+		slot := operationSlot(item, method)
+		if slot == nil || *slot == nil {
+			return fmt.Errorf("%s %s has no operation", strings.ToUpper(method), path)
+		}
+		*slot = nil
+		return nil
+	})
+}
+
+// updatePath stores a copy of the spec in which update has changed a copy of
+// the path item of path, leaving the current spec, which requests in flight
+// may be reading, untouched.
+func (p *Plugin) updatePath(path string, update func(item *openapi.PathItem) error) error {
+	p.specMu.Lock()
+	defer p.specMu.Unlock()
+
+	current := p.Spec()
+	if current == nil {
+		return errors.New("no OpenAPI spec configured")
+	}
+	spec := *current
+	item := openapi.PathItem{}
+	existing := spec.Paths[path]
+	if existing != nil {
+		item = *existing
+	}
+	if err := update(&item); err != nil {
+		return err
+	}
+
+	spec.Paths = maps.Clone(spec.Paths)
+	switch {
+	case !slices.ContainsFunc(routerMethods, func(method string) bool { return operationFor(&item, method) != nil }):
+		delete(spec.Paths, path)
+		spec.PathOrder = slices.DeleteFunc(slices.Clone(spec.PathOrder), func(key string) bool { return key == path })
+	case existing == nil:
+		if spec.Paths == nil {
+			spec.Paths = make(openapi.Paths)
+		}
+		spec.Paths[path] = &item
+		spec.PathOrder = append(slices.Clip(spec.PathOrder), path)
+	default:
+		spec.Paths[path] = &item
+	}
+	p.storeSpec(&spec)
+	return nil
+}
+
+// storeSpec makes spec the current spec and reports the change. The caller
+// holds specMu, so that changes are reported in order.
+func (p *Plugin) storeSpec(spec *openapi.Document) {
+	if p.options.EnableCompression {
+		DocumentCompression(spec, p.options.CompressionOptions)
+	}
+	p.served.Store(newServedSpec(spec, time.Now()))
+	if p.options.OnSpecChange != nil {
+		p.options.OnSpecChange(spec)
+	}
+}
+
+// followSpec returns a middleware built by build from the current spec and
+// rebuilt, with its compiled path matchers, on the first request after the
+// spec changes.
+func (p *Plugin) followSpec(build func(spec *openapi.Document) Middleware) Middleware {
+	type built struct {
+		served  *servedSpec
+		handler http.Handler
+	}
+	return func(next http.Handler) http.Handler {
+		served := p.served.Load()
+		var current atomic.Pointer[built]
+		current.Store(&built{served, build(served.doc)(next)})
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b := current.Load()
+			if served := p.served.Load(); b.served != served {
+				// concurrent requests may build it twice, which is harmless
+				b = &built{served, build(served.doc)(next)}
+				current.Store(b)
+			}
+			b.handler.ServeHTTP(w, r)
+		})
+	}
+}
@@ -1,22 +1,33 @@
 package yahttp
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
-	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"github.com/fathurrohman26/yaswag/pkg/validator"
 )
 
+// DefaultMaxBodyBytes is the size limit of the request bodies validation
+// reads, unless Options.MaxBodyBytes sets another.
+const DefaultMaxBodyBytes = 10 << 20
+
 // ValidationError represents an API validation error.
 type ValidationError struct {
 	Field   string `json:"field,omitempty"`
 	Message string `json:"message"`
 	In      string `json:"in,omitempty"` // query, path, header, body
+
+	status int // of the response to the error, if not 400 Bad Request
 }
 
 func (e ValidationError) Error() string {
@@ -29,6 +40,18 @@ func (e ValidationError) Error() string {
 // ValidationErrors is a collection of validation errors.
 type ValidationErrors []ValidationError
 
+// StatusCode returns the status of the response to the errors: 413 Request
+// Entity Too Large if the request body exceeds the size limit, or else 400
+// Bad Request.
+func (e ValidationErrors) StatusCode() int {
+	for _, err := range e {
+		if err.status != 0 {
+			return err.status
+		}
+	}
+	return http.StatusBadRequest
+}
+
 func (e ValidationErrors) Error() string {
 	if len(e) == 0 {
 		return "no errors"
@@ -45,23 +68,40 @@ func (p *Plugin) ValidationMiddleware() Middleware {
 	if errorHandler == nil {
 		errorHandler = DefaultValidationErrorHandler
 	}
-	return RequestValidation(p.spec, errorHandler)
+	maxBodyBytes := p.options.MaxBodyBytes
+	if maxBodyBytes == 0 {
+		maxBodyBytes = DefaultMaxBodyBytes
+	}
+	return p.followSpec(func(spec *openapi.Document) Middleware {
+		rv := newRequestValidator(spec)
+		rv.strict = p.runtime.strict.Load
+		rv.maxBodyBytes = maxBodyBytes
+		return requestValidation(rv, errorHandler)
+	})
 }
 
-// RequestValidation returns a standalone request validation middleware.
+// RequestValidation returns a standalone request validation middleware. It
+// reads request bodies up to DefaultMaxBodyBytes.
 func RequestValidation(spec *openapi.Document, errorHandler func(http.ResponseWriter, *http.Request, error)) Middleware {
+	return requestValidation(newRequestValidator(spec), errorHandler)
+}
+
+func requestValidation(rv *requestValidator, errorHandler func(http.ResponseWriter, *http.Request, error)) Middleware {
 	if errorHandler == nil {
 		errorHandler = DefaultValidationErrorHandler
 	}
 
-	validator := newRequestValidator(spec)
-
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if errs := validator.Validate(r); len(errs) > 0 {
+			errs, params := rv.validate(r)
+			if len(errs) > 0 {
+				recordValidationFailure(r, errs)
 				errorHandler(w, r, errs)
 				return
 			}
+			if params != nil {
+				r = r.WithContext(withParams(r.Context(), params))
+			}
 			next.ServeHTTP(w, r)
 		})
 	}
@@ -69,9 +109,6 @@ func RequestValidation(spec *openapi.Document, errorHandler func(http.ResponseWr
 
 // DefaultValidationErrorHandler is the default handler for validation errors.
 func DefaultValidationErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusBadRequest)
-
 	response := struct {
 		Error   string            `json:"error"`
 		Details []ValidationError `json:"details,omitempty"`
@@ -79,55 +116,50 @@ func DefaultValidationErrorHandler(w http.ResponseWriter, r *http.Request, err e
 		Error: "Validation failed",
 	}
 
+	status := http.StatusBadRequest
 	var validationErrs ValidationErrors
 	if errors.As(err, &validationErrs) {
 		response.Details = validationErrs
+		status = validationErrs.StatusCode()
 	} else {
 		response.Details = []ValidationError{{Message: err.Error()}}
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(response)
 }
 
 // requestValidator validates HTTP requests against an OpenAPI spec.
 type requestValidator struct {
-	spec       *openapi.Document
-	pathRegexs map[string]*pathMatcher
+	spec         *openapi.Document
+	routes       *routeTrie
+	strict       func() bool // reports whether undeclared query parameters are rejected
+	maxBodyBytes int64       // read from request bodies, unlimited if not positive
 }
 
 type pathMatcher struct {
-	regex     *regexp.Regexp
+	path      string
 	pathItem  *openapi.PathItem
 	paramKeys []string
 }
 
 func newRequestValidator(spec *openapi.Document) *requestValidator {
-	v := &requestValidator{
-		spec:       spec,
-		pathRegexs: make(map[string]*pathMatcher),
-	}
-
-	if spec != nil && spec.Paths != nil {
-		for path, item := range spec.Paths {
-			v.pathRegexs[path] = v.compilePath(path, item)
-		}
+	return &requestValidator{
+		spec:         spec,
+		routes:       compileRoutes(spec),
+		maxBodyBytes: DefaultMaxBodyBytes,
 	}
-
-	return v
 }
 
-func (v *requestValidator) compilePath(path string, item *openapi.PathItem) *pathMatcher {
-	// Convert OpenAPI path params to regex
+// compilePath compiles an OpenAPI path template into a matcher capturing its path parameters.
+func compilePath(path string, item *openapi.PathItem) *pathMatcher {
 	var paramKeys []string
-	regexPath := regexp.MustCompile(`\{([^}]+)\}`).ReplaceAllStringFunc(path, func(match string) string {
-		paramName := match[1 : len(match)-1]
-		paramKeys = append(paramKeys, paramName)
-		return `([^/]+)`
-	})
-
-	regex := regexp.MustCompile("^" + regexPath + "$")
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		paramKeys = append(paramKeys, match[1])
+	}
 	return &pathMatcher{
-		regex:     regex,
+		path:      path,
 		pathItem:  item,
 		paramKeys: paramKeys,
 	}
@@ -135,74 +167,230 @@ func (v *requestValidator) compilePath(path string, item *openapi.PathItem) *pat
 
 // Validate validates an HTTP request against the OpenAPI spec.
 func (v *requestValidator) Validate(r *http.Request) ValidationErrors {
+	errs, _ := v.validate(r)
+	return errs
+}
+
+// validate validates the request and returns the type-converted values of the
+// parameters it found, or nil params if the request matches no operation.
+func (v *requestValidator) validate(r *http.Request) (ValidationErrors, *params) {
 	var errs ValidationErrors
 
 	if v.spec == nil || v.spec.Paths == nil {
-		return errs
+		return errs, nil
 	}
 
 	// Find matching path
-	matcher, pathParams := v.matchPath(r.URL.Path)
+	matcher, pathParams := v.routes.match(r.URL.Path)
 	if matcher == nil {
 		// Path not found in spec - skip validation
-		return errs
+		return errs, nil
 	}
 
 	// Get operation for method
-	operation := v.getOperation(matcher.pathItem, r.Method)
+	operation, _ := requestOperation(matcher.pathItem, r.Method)
 	if operation == nil {
 		// Method not defined - skip validation
-		return errs
+		return errs, nil
 	}
 
 	// Validate parameters
-	errs = append(errs, v.validateParameters(r, operation, pathParams)...)
+	paramErrs, values := v.validateParameters(r, operation, pathParams)
+	errs = append(errs, paramErrs...)
+	errs = append(errs, v.validateBody(r, operation)...)
+
+	if v.strict != nil && v.strict() {
+		errs = append(errs, v.validateUndeclaredQuery(r, matcher.pathItem, operation)...)
+	}
+
+	return errs, values
+}
+
+// validateBody validates a JSON request body against the schema of the
+// operation's request body, leaving r.Body readable by the handler.
+func (v *requestValidator) validateBody(r *http.Request, op *openapi.Operation) ValidationErrors {
+	rb := v.resolveRequestBody(op.RequestBody)
+	if rb == nil || r.Body == nil || r.Body == http.NoBody {
+		return requiredBody(rb)
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	mt, ok := rb.Content[mediaType]
+	if !ok || mt.Schema == nil || mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return nil
+	}
+
+	body, errs := v.readBody(r)
+	if errs != nil {
+		return errs
+	}
+	if len(body) == 0 {
+		return requiredBody(rb)
+	}
+	return bodyErrors(validator.ValidateSchema(v.spec, mt.Schema, body, validator.DirectionRequest))
+}
+
+// requiredBody reports a missing request body if rb requires one.
+func requiredBody(rb *openapi.RequestBody) ValidationErrors {
+	if rb != nil && rb.Required {
+		return ValidationErrors{{Message: "request body is required", In: "body"}}
+	}
+	return nil
+}
 
+// readBody reads the request body, up to the body size limit, and replaces it
+// with a reader of what was read.
+func (v *requestValidator) readBody(r *http.Request) ([]byte, ValidationErrors) {
+	reader := r.Body
+	if v.maxBodyBytes > 0 {
+		reader = http.MaxBytesReader(nil, r.Body, v.maxBodyBytes)
+	}
+	body, err := io.ReadAll(reader)
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		return nil, ValidationErrors{{
+			Message: fmt.Sprintf("request body is larger than %d bytes", tooLarge.Limit),
+			In:      "body",
+			status:  http.StatusRequestEntityTooLarge,
+		}}
+	case err != nil:
+		return nil, ValidationErrors{{Message: "failed to read request body: " + err.Error(), In: "body"}}
+	}
+	return body, nil
+}
+
+// bodyErrors converts the error of validating a request body into validation errors.
+func bodyErrors(err error) ValidationErrors {
+	var schemaErrs validator.SchemaErrors
+	if !errors.As(err, &schemaErrs) {
+		if err != nil {
+			return ValidationErrors{{Message: err.Error(), In: "body"}}
+		}
+		return nil
+	}
+	errs := make(ValidationErrors, len(schemaErrs))
+	for i, err := range schemaErrs {
+		errs[i] = ValidationError{Field: err.Pointer, Message: err.Message, In: "body"}
+	}
 	return errs
 }
 
-func (v *requestValidator) matchPath(path string) (*pathMatcher, map[string]string) {
-	for _, matcher := range v.pathRegexs {
-		if matches := matcher.regex.FindStringSubmatch(path); matches != nil {
-			params := make(map[string]string)
-			for i, key := range matcher.paramKeys {
-				if i+1 < len(matches) {
-					params[key] = matches[i+1]
-				}
+// resolveRequestBody follows a $ref to a component request body, returning nil if it does not resolve.
+func (v *requestValidator) resolveRequestBody(rb *openapi.RequestBody) *openapi.RequestBody {
+	if rb == nil || rb.Ref == "" {
+		return rb
+	}
+	name, ok := strings.CutPrefix(rb.Ref, "#/components/requestBodies/")
+	if !ok || v.spec.Components == nil {
+		return nil
+	}
+	return v.spec.Components.RequestBodies[name]
+}
+
+// resolveSchema follows a $ref to a component schema, returning nil if it does not resolve.
+func (v *requestValidator) resolveSchema(schema *openapi.Schema) *openapi.Schema {
+	if schema == nil || schema.Ref == "" {
+		return schema
+	}
+	name, ok := strings.CutPrefix(schema.Ref, "#/components/schemas/")
+	if !ok || v.spec.Components == nil {
+		return nil
+	}
+	return v.spec.Components.Schemas[name]
+}
+
+// validateUndeclaredQuery reports query parameters not declared on the operation or path item.
+func (v *requestValidator) validateUndeclaredQuery(r *http.Request, item *openapi.PathItem, op *openapi.Operation) ValidationErrors {
+	var declared []*openapi.Parameter
+	for _, params := range [][]*openapi.Parameter{item.Parameters, op.Parameters} {
+		for _, param := range params {
+			if param = v.resolveParameter(param); param != nil && param.In == openapi.ParameterInQuery {
+				declared = append(declared, param)
 			}
-			return matcher, params
 		}
 	}
-	return nil, nil
+
+	var names []string
+	for name := range r.URL.Query() {
+		if !slices.ContainsFunc(declared, func(param *openapi.Parameter) bool { return v.declaresQueryKey(param, name) }) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var errs ValidationErrors
+	for _, name := range names {
+		errs = append(errs, ValidationError{
+			Field:   name,
+			Message: "parameter is not declared in the spec",
+			In:      string(openapi.ParameterInQuery),
+		})
+	}
+	return errs
+}
+
+// requestOperation returns the operation of pathItem that serves a request
+// with an HTTP method, and the method it is declared for: as in net/http, a
+// HEAD request is served by the GET operation if there is no HEAD one.
+func requestOperation(pathItem *openapi.PathItem, method string) (*openapi.Operation, string) {
+	method = strings.ToUpper(method)
+	if op := operationFor(pathItem, method); op != nil || method != http.MethodHead {
+		return op, method
+	}
+	return operationFor(pathItem, http.MethodGet), http.MethodGet
+}
+
+// operationFor returns the operation of pathItem for an HTTP method, or nil if none is declared.
+func operationFor(pathItem *openapi.PathItem, method string) *openapi.Operation {
+	if slot := operationSlot(pathItem, method); slot != nil {
+		return *slot
+	}
+	return nil
 }
 
-func (v *requestValidator) getOperation(pathItem *openapi.PathItem, method string) *openapi.Operation {
+// operationSlot returns the field of pathItem holding the operation for an
+// HTTP method, or nil for an unknown method.
+func operationSlot(pathItem *openapi.PathItem, method string) **openapi.Operation {
 	switch strings.ToUpper(method) {
 	case "GET":
-		return pathItem.Get
+		return &pathItem.Get
 	case "POST":
-		return pathItem.Post
+		return &pathItem.Post
 	case "PUT":
-		return pathItem.Put
+		return &pathItem.Put
 	case "DELETE":
-		return pathItem.Delete
+		return &pathItem.Delete
 	case "PATCH":
-		return pathItem.Patch
+		return &pathItem.Patch
 	case "OPTIONS":
-		return pathItem.Options
+		return &pathItem.Options
 	case "HEAD":
-		return pathItem.Head
+		return &pathItem.Head
 	case "TRACE":
-		return pathItem.Trace
+		return &pathItem.Trace
 	}
 	return nil
 }
 
-func (v *requestValidator) validateParameters(r *http.Request, op *openapi.Operation, pathParams map[string]string) ValidationErrors {
+func (v *requestValidator) validateParameters(r *http.Request, op *openapi.Operation, pathParams map[string]string) (ValidationErrors, *params) {
 	var errs ValidationErrors
+	values := newParams()
 
 	for _, param := range op.Parameters {
-		if param == nil {
+		if param = v.resolveParameter(param); param == nil {
+			continue
+		}
+
+		if schema := v.resolveSchema(param.Schema); param.In == openapi.ParameterInQuery && collectionType(schema) != "" {
+			collectionErrs, value, found := v.validateQueryCollection(r.URL.Query(), param, schema)
+			if len(collectionErrs) > 0 {
+				errs = append(errs, collectionErrs...)
+			} else if found {
+				values.set(param.In, param.Name, value)
+			}
 			continue
 		}
 
@@ -210,10 +398,24 @@ func (v *requestValidator) validateParameters(r *http.Request, op *openapi.Opera
 
 		if err := v.validateParameter(param, value, found); err != nil {
 			errs = append(errs, *err)
+		} else if found {
+			values.set(param.In, param.Name, convertParam(value, param.Schema))
 		}
 	}
 
-	return errs
+	return errs, values
+}
+
+// resolveParameter follows a $ref to a component parameter, returning nil if it does not resolve.
+func (v *requestValidator) resolveParameter(param *openapi.Parameter) *openapi.Parameter {
+	if param == nil || param.Ref == "" {
+		return param
+	}
+	name, ok := strings.CutPrefix(param.Ref, "#/components/parameters/")
+	if !ok || v.spec.Components == nil {
+		return nil
+	}
+	return v.spec.Components.Parameters[name]
 }
 
 func (v *requestValidator) extractParamValue(r *http.Request, param *openapi.Parameter, pathParams map[string]string) (string, bool) {
@@ -301,6 +503,6 @@ func (v *requestValidator) validateEnum(value string, enum []any, field, in stri
 
 // ValidateRequest validates a single request against an OpenAPI spec.
 func ValidateRequest(spec *openapi.Document, r *http.Request) ValidationErrors {
-	validator := newRequestValidator(spec)
-	return validator.Validate(r)
+	rv := newRequestValidator(spec)
+	return rv.Validate(r)
 }